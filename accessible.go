@@ -0,0 +1,170 @@
+package devflow
+
+import (
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// accessibleEnvVar enables the screen-reader output mode for the current process
+const accessibleEnvVar = "DEVFLOW_ACCESSIBLE"
+
+// accessibleWords replaces the status symbols of the output in
+// accessible mode
+var accessibleWords = []struct{ symbol, word string }{
+	{"✅", "PASS:"},
+	{"❌", "FAIL:"},
+	{"⚠", "WARNING:"},
+	{"⏭", "SKIP:"},
+}
+
+// ansiEscapeRe matches the color and cursor escape sequences
+var ansiEscapeRe = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+
+// resultBoundaryRe matches the ", " between two results of a summary,
+// e.g. "✅ vet ok, ✅ tests ok"
+var resultBoundaryRe = regexp.MustCompile(`, *(✅|❌|⚠|⏭)`)
+
+// accessibleMode is AccessibleEnabled, resolved once per process
+var accessibleMode struct {
+	once    sync.Once
+	enabled bool
+}
+
+// AccessibleEnabled reports whether the screen-reader output mode is on:
+// output.accessible in the global config or DEVFLOW_ACCESSIBLE=1. It is
+// resolved on the first call; later changes apply to the next process.
+func AccessibleEnabled() bool {
+	accessibleMode.once.Do(func() {
+		if v := os.Getenv(accessibleEnvVar); v != "" {
+			accessibleMode.enabled, _ = strconv.ParseBool(v)
+			return
+		}
+		global, err := LoadGlobalConfig()
+		accessibleMode.enabled = err == nil && global.Bool("output.accessible", false)
+	})
+	return accessibleMode.enabled
+}
+
+// AccessibleText rewrites s for screen readers: status symbols become
+// PASS, FAIL, WARNING and SKIP, colors, other emoji, box drawing and
+// spinner frames are dropped, and the results of a summary are put one
+// per line
+func AccessibleText(s string) string {
+	lines := strings.Split(ansiEscapeRe.ReplaceAllString(s, ""), "\n")
+	out := make([]string, 0, len(lines))
+	for _, line := range lines {
+		// A spinner redraws the line after \r; keep the last frame
+		line = strings.TrimRight(line, "\r")
+		if i := strings.LastIndex(line, "\r"); i >= 0 {
+			line = line[i+1:]
+		}
+		if line == "" {
+			out = append(out, line)
+			continue
+		}
+		line = resultBoundaryRe.ReplaceAllString(line, "\n$1")
+		for _, part := range strings.Split(line, "\n") {
+			if part = accessibleLine(part); part != "" {
+				out = append(out, part)
+			}
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// accessibleLine rewrites one result of AccessibleText. Spacing is kept
+// (indentation, table columns) except around the dropped characters.
+func accessibleLine(line string) string {
+	var b strings.Builder
+	afterSpace := func() bool { return b.Len() == 0 || strings.HasSuffix(b.String(), " ") }
+	rest := line
+	for rest != "" {
+		if word, n := statusWord(rest); n > 0 {
+			if !afterSpace() {
+				b.WriteByte(' ')
+			}
+			b.WriteString(word + " ")
+			rest = strings.TrimLeft(rest[n:], " \uFE0F")
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(rest)
+		rest = rest[size:]
+		if isDecorativeRune(r) {
+			if afterSpace() {
+				rest = strings.TrimLeft(rest, " ")
+			}
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return strings.TrimRight(b.String(), " \t")
+}
+
+// statusWord returns the word of the status symbol s starts with and the
+// length of the symbol
+func statusWord(s string) (string, int) {
+	for _, w := range accessibleWords {
+		if strings.HasPrefix(s, w.symbol) {
+			return w.word, len(w.symbol)
+		}
+	}
+	return "", 0
+}
+
+// isDecorativeRune reports whether r is an emoji, pictograph, box drawing
+// or spinner character with no words for a screen reader
+func isDecorativeRune(r rune) bool {
+	switch {
+	case r >= 0x2500 && r <= 0x259F: // Box drawing, block elements
+		return true
+	case r >= 0x2800 && r <= 0x28FF: // Braille spinner frames
+		return true
+	case r >= 0x1F000 && r <= 0x1FAFF: // Emoji and pictographs
+		return true
+	case r >= 0x2300 && r <= 0x23FF, r >= 0x2600 && r <= 0x27BF, r >= 0x2B00 && r <= 0x2BFF:
+		return unicode.Is(unicode.So, r)
+	case r == 0xFE0F || r == 0x200D: // Emoji presentation, joiner
+		return true
+	}
+	return false
+}
+
+// accessibleWriter passes every write through AccessibleText
+type accessibleWriter struct {
+	w io.Writer
+}
+
+// NewAccessibleWriter returns a writer that rewrites what is written to w
+// with AccessibleText. Writes are rewritten as they come, so each one
+// should hold whole lines (as fmt.Fprintln writes).
+func NewAccessibleWriter(w io.Writer) io.Writer {
+	return &accessibleWriter{w: w}
+}
+
+func (a *accessibleWriter) Write(p []byte) (int, error) {
+	text := AccessibleText(string(p))
+	if text == "" && len(p) > 0 && strings.Trim(string(p), "\r\n") != "" {
+		// Only decoration: write nothing rather than an empty line
+		return len(p), nil
+	}
+	if _, err := io.WriteString(a.w, text); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// OutputWriter returns w, or w through NewAccessibleWriter when
+// AccessibleEnabled. Commands print their results to
+// OutputWriter(os.Stdout).
+func OutputWriter(w io.Writer) io.Writer {
+	if _, ok := w.(*accessibleWriter); ok || !AccessibleEnabled() {
+		return w
+	}
+	return NewAccessibleWriter(w)
+}
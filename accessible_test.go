@@ -0,0 +1,55 @@
+package devflow
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestAccessibleText(t *testing.T) {
+	for in, want := range map[string]string{
+		"✅ vet ok, ✅ tests stdlib ok, ❌ race detected, go1.22": "PASS: vet ok\nPASS: tests stdlib ok\nFAIL: race detected, go1.22",
+		"⚠️ 1 of 2 dependents not updated to v0.2.0":           "WARNING: 1 of 2 dependents not updated to v0.2.0",
+		"Dependents: ⏭️ api":                                   "Dependents: SKIP: api",
+		"\033[0;32mRelease v1.0.0\033[0m":                      "Release v1.0.0",
+		"📦 Processing dependent: api":                          "Processing dependent: api",
+		"⠋ waiting\r⠙ waiting\rdone":                           "done",
+		"  example.com/api (../api)":                           "  example.com/api (../api)",
+		"acme/svc      ★12    Service":                         "acme/svc      12    Service",
+		"──────\nNAME  RUNS\n":                                 "NAME  RUNS\n",
+	} {
+		if got := AccessibleText(in); got != want {
+			t.Errorf("AccessibleText(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestOutputWriter(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	var buf bytes.Buffer
+
+	t.Setenv(accessibleEnvVar, "")
+	accessibleMode.once = sync.Once{}
+	if w := OutputWriter(&buf); w != &buf {
+		t.Error("output should be left as is by default")
+	}
+
+	// Resolved once per process
+	t.Setenv(accessibleEnvVar, "1")
+	if w := OutputWriter(&buf); w != &buf {
+		t.Error("the mode should not change within the process")
+	}
+	accessibleMode.once = sync.Once{}
+	defer func() { accessibleMode.once = sync.Once{} }()
+	w := OutputWriter(&buf)
+	fmt.Fprintln(w, "✅ Pushed v1.2.3")
+	fmt.Fprintln(w, "━━━━━━")
+	fmt.Fprintln(w)
+	if buf.String() != "PASS: Pushed v1.2.3\n\n" {
+		t.Errorf("unexpected accessible output %q", buf.String())
+	}
+	if OutputWriter(w) != w {
+		t.Error("an accessible writer should not be wrapped twice")
+	}
+}
@@ -0,0 +1,145 @@
+package devflow
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// airGapEnvVar enables air-gapped mode for the current process and its children
+const airGapEnvVar = "DEVFLOW_AIRGAP"
+
+// External network operations checked by AirGapCheck
+const (
+	NetGitHub      = "gh"           // gh CLI calls (repo create/view, api)
+	NetOAuth       = "oauth"        // GitHub Device Flow against github.com
+	NetToolInstall = "tool install" // go install of helper tools (wasmbrowsertest)
+	NetProxy       = "go proxy"     // module proxy queries (version warm-up)
+	NetVulnDB      = "vuln db"      // govulncheck queries of vuln.go.dev
+)
+
+// AirGap describes the air-gapped/enterprise mode settings. When enabled,
+// external network operations are disabled unless redirected to an
+// internal endpoint.
+type AirGap struct {
+	Enabled    bool
+	GoProxy    string // Internal module proxy (GOPROXY), empty disables downloads
+	GoSumDB    string // Internal checksum database (GOSUMDB), default "off"
+	GitHubHost string // GitHub Enterprise host used by gh (GH_HOST)
+}
+
+// LoadAirGap reads the airgap.* settings from the config. DEVFLOW_AIRGAP=1
+// in the environment also enables the mode.
+func LoadAirGap(c *Config) AirGap {
+	enabled, _ := strconv.ParseBool(os.Getenv(airGapEnvVar))
+	return AirGap{
+		Enabled:    enabled || c.Bool("airgap.enabled", false),
+		GoProxy:    c.String("airgap.goproxy", ""),
+		GoSumDB:    c.String("airgap.gosumdb", "off"),
+		GitHubHost: c.String("airgap.github_host", ""),
+	}
+}
+
+// Validate checks that configured endpoints are internal and well formed
+func (a AirGap) Validate() error {
+	if !a.Enabled {
+		return nil
+	}
+
+	if a.GoProxy != "" && a.GoProxy != "off" {
+		for _, p := range strings.FieldsFunc(a.GoProxy, func(r rune) bool { return r == ',' || r == '|' }) {
+			if p == "direct" || p == "off" {
+				continue
+			}
+			u, err := url.Parse(p)
+			if err != nil || (u.Scheme != "https" && u.Scheme != "http" && u.Scheme != "file") {
+				return fmt.Errorf("airgap.goproxy: %q is not an http(s) or file URL", p)
+			}
+			if strings.Contains(u.Host, "proxy.golang.org") {
+				return fmt.Errorf("airgap.goproxy: %s is a public endpoint", u.Host)
+			}
+		}
+	}
+
+	if strings.Contains(a.GoSumDB, "sum.golang.org") {
+		return fmt.Errorf("airgap.gosumdb: sum.golang.org is a public endpoint")
+	}
+
+	if a.GitHubHost != "" {
+		if strings.Contains(a.GitHubHost, "/") {
+			return fmt.Errorf("airgap.github_host: expected a host name, got %q", a.GitHubHost)
+		}
+		if a.GitHubHost == "github.com" {
+			return fmt.Errorf("airgap.github_host: github.com is a public endpoint")
+		}
+	}
+	return nil
+}
+
+// Env returns the environment that redirects child processes to internal endpoints
+func (a AirGap) Env() []string {
+	if !a.Enabled {
+		return nil
+	}
+	proxy := a.GoProxy
+	if proxy == "" {
+		proxy = "off"
+	}
+	env := []string{airGapEnvVar + "=1", "GOPROXY=" + proxy, "GOSUMDB=" + a.GoSumDB}
+	if a.GitHubHost != "" {
+		env = append(env, "GH_HOST="+a.GitHubHost)
+	}
+	return env
+}
+
+// Apply exports Env into the current process so every command inherits it
+func (a AirGap) Apply() {
+	for _, kv := range a.Env() {
+		k, v, _ := strings.Cut(kv, "=")
+		os.Setenv(k, v)
+	}
+}
+
+// StartAirGap loads the config in dir, validates the air-gapped settings and
+// applies them. Commands call it at startup so misconfiguration fails early.
+func StartAirGap(dir string) (AirGap, error) {
+	c, err := LoadConfig(dir)
+	if err != nil {
+		return AirGap{}, err
+	}
+	a := LoadAirGap(c)
+	if err := a.Validate(); err != nil {
+		return a, err
+	}
+	a.Apply()
+	return a, nil
+}
+
+// AirGapEnabled reports whether air-gapped mode is active in this process
+func AirGapEnabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv(airGapEnvVar))
+	return enabled
+}
+
+// AirGapCheck returns an error if op reaches an external endpoint that is
+// disabled in air-gapped mode. Returns nil when the mode is off or the
+// operation is redirected to an internal endpoint.
+func AirGapCheck(op string) error {
+	if !AirGapEnabled() {
+		return nil
+	}
+
+	switch op {
+	case NetGitHub:
+		if host := os.Getenv("GH_HOST"); host != "" && host != "github.com" {
+			return nil
+		}
+	case NetToolInstall, NetProxy:
+		if p := os.Getenv("GOPROXY"); p != "" && p != "off" && !strings.Contains(p, "proxy.golang.org") {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s disabled in air-gapped mode", op)
+}
@@ -0,0 +1,89 @@
+package devflow
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAirGapValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		a       AirGap
+		wantErr string
+	}{
+		{"disabled ignores values", AirGap{GoProxy: "https://proxy.golang.org"}, ""},
+		{"internal endpoints", AirGap{Enabled: true, GoProxy: "https://athens.corp.local,direct", GoSumDB: "off", GitHubHost: "ghe.corp.local"}, ""},
+		{"no endpoints", AirGap{Enabled: true, GoSumDB: "off"}, ""},
+		{"public proxy", AirGap{Enabled: true, GoProxy: "https://proxy.golang.org"}, "public endpoint"},
+		{"bad proxy url", AirGap{Enabled: true, GoProxy: "athens.corp.local"}, "not an http(s) or file URL"},
+		{"public sumdb", AirGap{Enabled: true, GoSumDB: "sum.golang.org"}, "public endpoint"},
+		{"github.com host", AirGap{Enabled: true, GitHubHost: "github.com"}, "public endpoint"},
+		{"host with path", AirGap{Enabled: true, GitHubHost: "https://ghe.corp.local/"}, "expected a host name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.a.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoadAirGap(t *testing.T) {
+	t.Setenv(airGapEnvVar, "")
+
+	c, _ := ParseConfig("airgap:\n  enabled: true\n  goproxy: https://athens.corp.local\n  github_host: ghe.corp.local\n")
+	a := LoadAirGap(c)
+	if !a.Enabled || a.GoProxy != "https://athens.corp.local" || a.GoSumDB != "off" || a.GitHubHost != "ghe.corp.local" {
+		t.Fatalf("unexpected settings: %+v", a)
+	}
+
+	env := strings.Join(a.Env(), " ")
+	for _, want := range []string{"DEVFLOW_AIRGAP=1", "GOPROXY=https://athens.corp.local", "GOSUMDB=off", "GH_HOST=ghe.corp.local"} {
+		if !strings.Contains(env, want) {
+			t.Errorf("Env() missing %s: %s", want, env)
+		}
+	}
+
+	t.Setenv(airGapEnvVar, "1")
+	if !LoadAirGap(NewConfig()).Enabled {
+		t.Error("DEVFLOW_AIRGAP=1 should enable the mode")
+	}
+}
+
+func TestAirGapCheck(t *testing.T) {
+	t.Setenv(airGapEnvVar, "")
+	if err := AirGapCheck(NetGitHub); err != nil {
+		t.Fatalf("mode off should allow everything, got %v", err)
+	}
+
+	// Enabled without internal endpoints: everything external is blocked
+	t.Setenv(airGapEnvVar, "1")
+	t.Setenv("GH_HOST", "")
+	t.Setenv("GOPROXY", "off")
+	for _, op := range []string{NetGitHub, NetOAuth, NetToolInstall, NetProxy} {
+		if err := AirGapCheck(op); err == nil || !strings.Contains(err.Error(), "air-gapped") {
+			t.Errorf("%s: expected air-gapped error, got %v", op, err)
+		}
+	}
+
+	// Redirected to internal endpoints: allowed, except OAuth Device Flow
+	t.Setenv("GH_HOST", "ghe.corp.local")
+	t.Setenv("GOPROXY", "https://athens.corp.local")
+	for _, op := range []string{NetGitHub, NetToolInstall, NetProxy} {
+		if err := AirGapCheck(op); err != nil {
+			t.Errorf("%s: expected redirect to be allowed, got %v", op, err)
+		}
+	}
+	if err := AirGapCheck(NetOAuth); err == nil {
+		t.Error("Device Flow should stay disabled in air-gapped mode")
+	}
+}
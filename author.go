@@ -0,0 +1,127 @@
+package devflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Author is the identity gonew publishes in LICENSE, README.md and doc.go
+type Author struct {
+	Name  string
+	Email string
+	URL   string // Website, or the profile page without one
+}
+
+// String returns "Name <email> (url)", leaving out the empty parts
+func (a Author) String() string {
+	s := a.Name
+	if a.Email != "" {
+		s += " <" + a.Email + ">"
+	}
+	if a.URL != "" {
+		s += " (" + a.URL + ")"
+	}
+	return strings.TrimSpace(s)
+}
+
+// AuthorProfile reads the display name, public email and website of the
+// authenticated user from the GitHub API. Empty fields are left empty.
+func (gh *GitHub) AuthorProfile() (Author, error) {
+	out, err := RunCommandSilent("gh", "api", "user")
+	if err != nil {
+		return Author{}, fmt.Errorf("failed to get the user profile: %s", firstLine(out))
+	}
+	var user struct {
+		Name    string `json:"name"`
+		Email   string `json:"email"`
+		Blog    string `json:"blog"`
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.Unmarshal([]byte(out), &user); err != nil {
+		return Author{}, fmt.Errorf("failed to parse the user profile: %w", err)
+	}
+	author := Author{Name: user.Name, Email: user.Email, URL: user.Blog}
+	if author.URL == "" {
+		author.URL = user.HTMLURL
+	} else if !strings.Contains(author.URL, "://") {
+		author.URL = "https://" + author.URL
+	}
+	return author, nil
+}
+
+// ResolveAuthor returns the published identity, field by field: author.name,
+// author.email and author.url of cfg, else the profile of the provider
+// (when it is an AuthorProfiler), else git user.name and user.email
+func ResolveAuthor(cfg *Config, provider any, git GitClient) Author {
+	author := Author{
+		Name:  cfg.String("author.name", ""),
+		Email: cfg.String("author.email", ""),
+		URL:   cfg.String("author.url", ""),
+	}
+	if author.Name != "" && author.Email != "" && author.URL != "" {
+		return author
+	}
+	if p, ok := provider.(AuthorProfiler); ok {
+		if profile, err := p.AuthorProfile(); err == nil {
+			author = fillAuthor(author, profile)
+		}
+	}
+	if git != nil {
+		name, _ := git.GetConfigUserName()
+		email, _ := git.GetConfigUserEmail()
+		author = fillAuthor(author, Author{Name: name, Email: email})
+	}
+	return author
+}
+
+// fillAuthor sets the empty fields of a from b
+func fillAuthor(a, b Author) Author {
+	if a.Name == "" {
+		a.Name = b.Name
+	}
+	if a.Email == "" {
+		a.Email = b.Email
+	}
+	if a.URL == "" {
+		a.URL = b.URL
+	}
+	return a
+}
+
+// authorSection is the "## Author" section of README.md
+func authorSection(a Author) string {
+	return titledAuthorSection(a, "Author")
+}
+
+// titledAuthorSection is authorSection with a localized heading
+func titledAuthorSection(a Author, heading string) string {
+	if a.Name == "" {
+		return ""
+	}
+	line := a.Name
+	if a.URL != "" {
+		line = "[" + a.Name + "](" + a.URL + ")"
+	}
+	if a.Email != "" {
+		line += " - <" + a.Email + ">"
+	}
+	return "\n## " + heading + "\n\n" + line + "\n"
+}
+
+// GenerateDocFile writes doc.go with the package comment of a library:
+// the description and the author
+func GenerateDocFile(repoName, description string, author Author, targetDir string) error {
+	pkg := projectPackageName(repoName)
+	summary := SanitizeDescription(description)
+	if summary != "" && !strings.HasSuffix(summary, ".") && !strings.HasSuffix(summary, "…") {
+		summary += "."
+	}
+	content := fmt.Sprintf("// Package %s: %s\n", pkg, summary)
+	if author.Name != "" {
+		content += "//\n// Author: " + author.String() + "\n"
+	}
+	content += "package " + pkg + "\n"
+	return writeGenerated(filepath.Join(targetDir, "doc.go"), []byte(content))
+}
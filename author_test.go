@@ -0,0 +1,78 @@
+package devflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testProfileProvider is a testProvider with an author profile
+type testProfileProvider struct {
+	*testProvider
+	profile Author
+}
+
+func (p *testProfileProvider) AuthorProfile() (Author, error) { return p.profile, nil }
+
+func TestResolveAuthor(t *testing.T) {
+	git := &MockGitClient{}
+	if a := ResolveAuthor(NewConfig(), nil, git); a != (Author{Name: "Mock User", Email: "mock@example.com"}) {
+		t.Errorf("git fallback = %+v", a)
+	}
+
+	provider := &testProfileProvider{profile: Author{Name: "Jane Doe", URL: "https://jane.dev"}}
+	if a := ResolveAuthor(NewConfig(), provider, git); a != (Author{"Jane Doe", "mock@example.com", "https://jane.dev"}) {
+		t.Errorf("profile = %+v", a)
+	}
+
+	cfg, _ := ParseConfig("author:\n  name: J. Doe\n  email: oss@example.com\n")
+	a := ResolveAuthor(cfg, provider, git)
+	if a != (Author{"J. Doe", "oss@example.com", "https://jane.dev"}) {
+		t.Errorf("config override = %+v", a)
+	}
+	if a.String() != "J. Doe <oss@example.com> (https://jane.dev)" {
+		t.Errorf("String() = %q", a.String())
+	}
+}
+
+func TestGoNewAuthor(t *testing.T) {
+	tmp := testResumeEnv(t)
+	git, err := NewGit()
+	if err != nil {
+		t.Skip("git not installed")
+	}
+	goHandler, _ := NewGo(git)
+	provider := &testProfileProvider{testProvider: &testProvider{dir: tmp}, profile: Author{Name: "Jane Doe", Email: "jane@example.com", URL: "https://jane.dev"}}
+	gn := NewGoNew(git, NewResolvedFuture(provider), goHandler)
+
+	dir := filepath.Join(tmp, "author-lib")
+	if _, err := gn.Create(NewProjectOptions{Name: "author-lib", Description: "Authored library", Directory: dir}); err != nil {
+		t.Fatal(err)
+	}
+	read := func(name string) string {
+		data, _ := os.ReadFile(filepath.Join(dir, name))
+		return string(data)
+	}
+	if !strings.HasSuffix(read("README.md"), "\n## Author\n\n[Jane Doe](https://jane.dev) - <jane@example.com>\n") {
+		t.Errorf("unexpected README %q", read("README.md"))
+	}
+	if !strings.Contains(read("LICENSE"), "Jane Doe") {
+		t.Error("LICENSE should name the profile author")
+	}
+	want := "// Package authorlib: Authored library.\n//\n// Author: Jane Doe <jane@example.com> (https://jane.dev)\npackage authorlib\n"
+	if got := read("doc.go"); got != want {
+		t.Errorf("doc.go = %q", got)
+	}
+
+	// Local only: the git identity
+	dir = filepath.Join(tmp, "local-lib")
+	if _, err := gn.Create(NewProjectOptions{Name: "local-lib", Description: "Local", LocalOnly: true, Directory: dir}); err != nil {
+		t.Fatal(err)
+	}
+	name, _ := git.GetConfigUserName()
+	email, _ := git.GetConfigUserEmail()
+	if !strings.Contains(read("LICENSE"), name) || !strings.Contains(read("README.md"), name+" - <"+email+">") {
+		t.Errorf("expected the git identity, got %q", read("README.md"))
+	}
+}
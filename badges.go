@@ -1,7 +1,6 @@
 package devflow
 
 import (
-	"bufio"
 	"bytes"
 	"fmt"
 	"os"
@@ -12,6 +11,9 @@ import (
 
 const DevFlowRepository = "github.com/tinywasm/devflow"
 
+// DefaultBadgesFile is the SVG written by gotest and badges
+const DefaultBadgesFile = "docs/img/badges.svg"
+
 // Badges is responsible for creating and managing a collection of badges.
 // It handles parsing input arguments, generating the SVG image, and preparing
 // the necessary markdown to embed the badges in a file.
@@ -44,7 +46,7 @@ type Badges struct {
 func NewBadges(args ...string) *Badges {
 	// Create handler with defaults
 	h := &Badges{
-		outputFile:   "docs/img/badges.svg",
+		outputFile:   DefaultBadgesFile,
 		svgHeight:    20,
 		badgeHeight:  20,
 		fontSize:     11,
@@ -176,7 +178,7 @@ func (h *Badges) BuildBadges() ([]string, error) {
 	}
 
 	if shouldWrite {
-		if err := os.WriteFile(h.outputFile, svgBytes, 0o644); err != nil {
+		if err := writeGenerated(h.outputFile, svgBytes); err != nil {
 			return nil, fmt.Errorf("write svg file: %w", err)
 		}
 	}
@@ -259,31 +261,25 @@ func getBadgeColor(typ, value string) string {
 		if value == "Clean" {
 			return "#4c1"
 		}
+		if value == "Skipped" {
+			return "#9f9f9f"
+		}
 		return "#e05d44"
-	case "vet":
+	case "vet", "lint":
 		if value == "OK" {
 			return "#4c1"
 		}
 		return "#e05d44"
-	}
-	return "#007acc"
-}
-
-func getModuleName(dir string) (string, error) {
-	f, err := os.Open(filepath.Join(dir, "go.mod"))
-	if err != nil {
-		return "", err
-	}
-	defer f.Close()
-
-	scanner := bufio.NewScanner(f)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if strings.HasPrefix(line, "module ") {
-			return strings.TrimPrefix(line, "module "), nil
+	case "vulns":
+		if value == "none" {
+			return "#4c1"
 		}
+		if strings.HasSuffix(value, "imported") {
+			return "#dfb317"
+		}
+		return "#e05d44"
 	}
-	return "", fmt.Errorf("module name not found in go.mod")
+	return "#007acc"
 }
 
 func checkFileExists(path string) bool {
@@ -291,7 +287,9 @@ func checkFileExists(path string) bool {
 	return !os.IsNotExist(err)
 }
 
-func (h *Badges) updateBadges(readmeFile, licenseType, goVer, testStatus, coveragePercent, raceStatus, vetStatus string, quiet bool) error {
+// updateBadges writes the badges to readmeFile; an empty vulnStatus or
+// lintStatus leaves out the vulnerability or lint badge
+func (h *Badges) updateBadges(readmeFile, licenseType, goVer, testStatus, coveragePercent, raceStatus, vetStatus, vulnStatus, lintStatus string, quiet bool) error {
 	// Colors
 	licenseColor := getBadgeColor("license", licenseType)
 	goColor := getBadgeColor("go", goVer)
@@ -309,6 +307,12 @@ func (h *Badges) updateBadges(readmeFile, licenseType, goVer, testStatus, covera
 		fmt.Sprintf("Race:%s:%s", raceStatus, raceColor),
 		fmt.Sprintf("Vet:%s:%s", vetStatus, vetColor),
 	}
+	if vulnStatus != "" {
+		badgeArgs = append(badgeArgs, fmt.Sprintf("Vulns:%s:%s", vulnStatus, getBadgeColor("vulns", vulnStatus)))
+	}
+	if lintStatus != "" {
+		badgeArgs = append(badgeArgs, fmt.Sprintf("Lint:%s:%s", lintStatus, getBadgeColor("lint", lintStatus)))
+	}
 
 	bh := NewBadges(badgeArgs...)
 	bh.SetLog(h.log)
@@ -323,19 +327,20 @@ func (h *Badges) updateBadges(readmeFile, licenseType, goVer, testStatus, covera
 		content := sectionArgs[2]
 		readmeFile := sectionArgs[3]
 
-		m := NewMarkDown(".", ".", func(name string, data []byte) error {
-			return os.WriteFile(name, data, 0644)
-		})
-		m.InputPath(readmeFile, func(name string) ([]byte, error) {
-			return os.ReadFile(name)
-		})
+		// The localized variants (README.es.md, ...) get the same badges
+		for _, file := range append([]string{readmeFile}, LocalizedREADMEs(readmeFile)...) {
+			m := NewMarkDown(".", ".", writeGenerated)
+			m.InputPath(file, func(name string) ([]byte, error) {
+				return os.ReadFile(name)
+			})
 
-		if err := m.UpdateSection(sectionID, content, afterLine); err != nil {
-			return fmt.Errorf("error updating README with markdown handler: %w", err)
-		}
+			if err := m.UpdateSection(sectionID, content, afterLine); err != nil {
+				return fmt.Errorf("error updating %s with markdown handler: %w", file, err)
+			}
 
-		if !quiet {
-			h.log(fmt.Sprintf("Updated section %s in %s", sectionID, readmeFile))
+			if !quiet {
+				h.log(fmt.Sprintf("Updated section %s in %s", sectionID, file))
+			}
 		}
 	}
 
@@ -0,0 +1,182 @@
+package devflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// BaselineFile records known findings so gotest only fails on new ones
+const BaselineFile = ".devflow-baseline.json"
+
+// vetLineRe matches "./pkg/file.go:12:3: message" lines of go vet
+var vetLineRe = regexp.MustCompile(`^(?:vet: )?(.+?\.go):(\d+)(?::(\d+))?: (.*)$`)
+
+// BaselineEntry identifies a known finding. Line numbers are not part of
+// the identity so unrelated edits that shift code keep the entry valid.
+type BaselineEntry struct {
+	Tool    string `json:"tool"`
+	Rule    string `json:"rule,omitempty"`
+	File    string `json:"file"`
+	Message string `json:"message"`
+}
+
+// Baseline is the content of .devflow-baseline.json
+type Baseline struct {
+	Version  int             `json:"version"`
+	Findings []BaselineEntry `json:"findings"`
+}
+
+// LoadBaseline reads the baseline in dir. Returns nil without error when the
+// project has no baseline.
+func LoadBaseline(dir string) (*Baseline, error) {
+	data, err := os.ReadFile(filepath.Join(dir, BaselineFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("%s: %w", BaselineFile, err)
+	}
+	return &b, nil
+}
+
+// NewBaseline builds a baseline from the current findings
+func NewBaseline(findings []Finding) *Baseline {
+	b := &Baseline{Version: 1, Findings: []BaselineEntry{}}
+	for _, f := range findings {
+		b.Findings = append(b.Findings, baselineKey(f))
+	}
+	sort.Slice(b.Findings, func(i, j int) bool {
+		x, y := b.Findings[i], b.Findings[j]
+		if x.File != y.File {
+			return x.File < y.File
+		}
+		if x.Tool != y.Tool {
+			return x.Tool < y.Tool
+		}
+		return x.Message < y.Message
+	})
+	return b
+}
+
+// Save writes the baseline to dir
+func (b *Baseline) Save(dir string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, BaselineFile), append(data, '\n'), 0644)
+}
+
+// Filter splits findings into new ones and those recorded in the baseline.
+// Each entry matches at most one finding, so a second copy of a known issue
+// counts as new. A nil baseline reports everything as new.
+func (b *Baseline) Filter(findings []Finding) (fresh, known []Finding) {
+	if b == nil {
+		return findings, nil
+	}
+	for _, f := range b.mark(findings) {
+		if f.BaselineState == "unchanged" {
+			known = append(known, f)
+		} else {
+			fresh = append(fresh, f)
+		}
+	}
+	return fresh, known
+}
+
+// mark returns a copy of findings with BaselineState set
+func (b *Baseline) mark(findings []Finding) []Finding {
+	remaining := make(map[BaselineEntry]int)
+	for _, e := range b.Findings {
+		remaining[e]++
+	}
+	marked := make([]Finding, len(findings))
+	for i, f := range findings {
+		key := baselineKey(f)
+		f.BaselineState = "new"
+		if remaining[key] > 0 {
+			remaining[key]--
+			f.BaselineState = "unchanged"
+		}
+		marked[i] = f
+	}
+	return marked
+}
+
+// String formats the finding like a go vet line
+func (f Finding) String() string {
+	switch {
+	case f.File == "":
+		return f.Message
+	case f.Column > 0:
+		return fmt.Sprintf("%s:%d:%d: %s", f.File, f.Line, f.Column, f.Message)
+	}
+	return fmt.Sprintf("%s:%d: %s", f.File, f.Line, f.Message)
+}
+
+// baselineKey is the identity of a finding in the baseline. The text output
+// of go vet has no analyzer names, so vet entries match without a rule.
+func baselineKey(f Finding) BaselineEntry {
+	key := BaselineEntry{Tool: f.Tool, Rule: f.Rule, File: strings.TrimPrefix(f.File, "./"), Message: f.Message}
+	if key.Tool == "go vet" {
+		key.Rule = ""
+	}
+	return key
+}
+
+// ParseVetText converts go vet text output lines into findings. Lines that
+// are not file positions are kept as findings without a file.
+func ParseVetText(lines []string) []Finding {
+	findings := make([]Finding, 0, len(lines))
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if m := vetLineRe.FindStringSubmatch(line); m != nil {
+			f := Finding{Tool: "go vet", File: strings.TrimPrefix(filepath.ToSlash(m[1]), "./"), Message: m[4], Level: "warning"}
+			fmt.Sscan(m[2], &f.Line)
+			fmt.Sscan(m[3], &f.Column)
+			findings = append(findings, f)
+			continue
+		}
+		findings = append(findings, Finding{Tool: "go vet", Message: line, Level: "warning"})
+	}
+	return findings
+}
+
+// UpdateBaseline records the current go vet findings of the module in the
+// current directory (and those of the lint.tool linter when lint.enabled is
+// set, else of staticcheck when installed) in .devflow-baseline.json
+func (g *Go) UpdateBaseline() (string, error) {
+	vet := LoadVetSettings(g.Config())
+	if err := vet.Prepare(); err != nil {
+		return "", err
+	}
+	vetOutput, _ := RunCommand("go", vet.Args(nil)...)
+	findings := ParseVetText(vet.IssueLines(vetOutput))
+
+	if settings, err := LoadLintSettings(g.Config()); err == nil && settings.Enabled {
+		_, lint, err := g.Lint()
+		if err != nil {
+			return "", fmt.Errorf("lint failed: %w", err)
+		}
+		findings = append(findings, mergeLintFindings(findings, lint)...)
+	} else if _, err := RunCommandSilent("staticcheck", "-version"); err == nil {
+		cwd, _ := os.Getwd()
+		out, _ := RunCommandSilent("staticcheck", "-f", "json", "./...")
+		findings = append(findings, ParseStaticcheckJSON(out, cwd)...)
+	}
+
+	b := NewBaseline(findings)
+	if err := b.Save("."); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("✅ Baseline: %d findings recorded in %s", len(b.Findings), BaselineFile), nil
+}
@@ -0,0 +1,100 @@
+package devflow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseVetText(t *testing.T) {
+	findings := ParseVetText([]string{
+		"./main.go:12:3: fmt.Sprintf format %d has arg s of wrong type string",
+		"vet: pkg/util.go:4: unreachable code",
+		"some other output",
+	})
+	if len(findings) != 3 {
+		t.Fatalf("expected 3 findings, got %+v", findings)
+	}
+	if f := findings[0]; f.File != "main.go" || f.Line != 12 || f.Column != 3 || f.Message != "fmt.Sprintf format %d has arg s of wrong type string" {
+		t.Errorf("unexpected finding: %+v", f)
+	}
+	if f := findings[1]; f.File != "pkg/util.go" || f.Line != 4 || f.Column != 0 {
+		t.Errorf("unexpected finding: %+v", f)
+	}
+	if f := findings[2]; f.File != "" || f.Message != "some other output" {
+		t.Errorf("unexpected finding: %+v", f)
+	}
+	if s := findings[0].String(); s != "main.go:12:3: fmt.Sprintf format %d has arg s of wrong type string" {
+		t.Errorf("String() = %q", s)
+	}
+}
+
+func TestBaselineFilter(t *testing.T) {
+	known := Finding{Tool: "go vet", File: "a.go", Line: 3, Message: "unreachable code"}
+	b := NewBaseline([]Finding{known})
+
+	// Same issue on a different line is still known
+	moved := known
+	moved.Line = 10
+	// The JSON output carries the analyzer name, the text output does not
+	withRule := known
+	withRule.Rule = "unreachable"
+	fresh, old := b.Filter([]Finding{moved, {Tool: "go vet", File: "b.go", Line: 1, Message: "unreachable code"}})
+	if len(old) != 1 || len(fresh) != 1 || fresh[0].File != "b.go" {
+		t.Errorf("unexpected split: fresh=%+v known=%+v", fresh, old)
+	}
+	if _, old := b.Filter([]Finding{withRule}); len(old) != 1 {
+		t.Errorf("vet finding with rule should match the baseline")
+	}
+
+	// Each entry covers one occurrence
+	fresh, _ = b.Filter([]Finding{known, known})
+	if len(fresh) != 1 {
+		t.Errorf("duplicate of a known issue should be new, got %+v", fresh)
+	}
+
+	var none *Baseline
+	if fresh, _ := none.Filter([]Finding{known}); len(fresh) != 1 {
+		t.Errorf("nil baseline should report everything as new")
+	}
+}
+
+func TestBaselineSaveLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	b, err := LoadBaseline(dir)
+	if err != nil || b != nil {
+		t.Fatalf("missing baseline: got %v, %v", b, err)
+	}
+
+	findings := []Finding{
+		{Tool: "staticcheck", Rule: "SA4006", File: "z.go", Line: 9, Message: "value never used"},
+		{Tool: "go vet", File: "./a.go", Line: 3, Message: "unreachable code"},
+	}
+	if err := NewBaseline(findings).Save(dir); err != nil {
+		t.Fatal(err)
+	}
+	b, err = LoadBaseline(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Version != 1 || len(b.Findings) != 2 || b.Findings[0].File != "a.go" || b.Findings[1].Rule != "SA4006" {
+		t.Errorf("unexpected baseline: %+v", b)
+	}
+
+	os.WriteFile(filepath.Join(dir, BaselineFile), []byte("{"), 0644)
+	if _, err := LoadBaseline(dir); err == nil {
+		t.Error("expected error for invalid baseline")
+	}
+}
+
+func TestBaselineMark(t *testing.T) {
+	b := NewBaseline([]Finding{{Tool: "go vet", File: "a.go", Message: "old"}})
+	findings := b.mark([]Finding{
+		{Tool: "go vet", Rule: "printf", File: "a.go", Line: 1, Message: "old", Level: "warning"},
+		{Tool: "go vet", Rule: "printf", File: "a.go", Line: 2, Message: "new", Level: "warning"},
+	})
+	if findings[0].BaselineState != "unchanged" || findings[1].BaselineState != "new" {
+		t.Errorf("unexpected states: %+v", findings)
+	}
+}
@@ -0,0 +1,248 @@
+package devflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// benchLineRe matches "BenchmarkX-8   1000   1234 ns/op   56 B/op   2 allocs/op"
+var benchLineRe = regexp.MustCompile(`^(Benchmark\S+?)(?:-\d+)?\s+\d+\s+(.+)$`)
+
+// BenchOptions configures a benchmark run
+type BenchOptions struct {
+	Compare string  // Git ref to compare against (e.g. "main"); empty runs the working tree only
+	Count   int     // Runs per benchmark (default 6, the minimum for a useful comparison)
+	Bench   string  // -bench pattern (default ".")
+	Alpha   float64 // Significance level (default 0.05)
+}
+
+// BenchSamples maps "pkg.BenchmarkName" to unit ("ns/op", "B/op", ...) to
+// the values of each run
+type BenchSamples map[string]map[string][]float64
+
+// BenchDelta is the comparison of one benchmark metric
+type BenchDelta struct {
+	Name        string
+	Unit        string
+	Old, New    float64 // Medians
+	Delta       float64 // Percent change of the median, New vs Old
+	P           float64 // Mann-Whitney U test p-value
+	Significant bool
+}
+
+// ParseBenchOutput collects the samples in go test -bench output
+func ParseBenchOutput(output string) BenchSamples {
+	samples := make(BenchSamples)
+	pkg := ""
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if p, ok := strings.CutPrefix(line, "pkg: "); ok {
+			pkg = strings.TrimSpace(p)
+			continue
+		}
+		m := benchLineRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		name := m[1]
+		if pkg != "" {
+			name = pkg + "." + name
+		}
+		fields := strings.Fields(m[2])
+		for i := 0; i+1 < len(fields); i += 2 {
+			v, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				continue
+			}
+			if samples[name] == nil {
+				samples[name] = make(map[string][]float64)
+			}
+			samples[name][fields[i+1]] = append(samples[name][fields[i+1]], v)
+		}
+	}
+	return samples
+}
+
+// CompareBench compares the benchmarks present in both sample sets
+func CompareBench(base, head BenchSamples, alpha float64) []BenchDelta {
+	if alpha <= 0 {
+		alpha = 0.05
+	}
+	var deltas []BenchDelta
+	for name, units := range head {
+		for unit, nv := range units {
+			ov := base[name][unit]
+			if len(ov) == 0 {
+				continue
+			}
+			d := BenchDelta{Name: name, Unit: unit, Old: median(ov), New: median(nv)}
+			if d.Old != 0 {
+				d.Delta = (d.New - d.Old) / d.Old * 100
+			}
+			d.P = mannWhitneyU(ov, nv)
+			d.Significant = d.P < alpha && d.Old != d.New
+			deltas = append(deltas, d)
+		}
+	}
+	sort.Slice(deltas, func(i, j int) bool {
+		if deltas[i].Name != deltas[j].Name {
+			return deltas[i].Name < deltas[j].Name
+		}
+		return unitOrder(deltas[i].Unit) < unitOrder(deltas[j].Unit)
+	})
+	return deltas
+}
+
+func unitOrder(unit string) string {
+	switch unit {
+	case "ns/op":
+		return "0"
+	case "B/op":
+		return "1"
+	case "allocs/op":
+		return "2"
+	}
+	return "3" + unit
+}
+
+// FormatBenchDeltas renders the comparison as a table; "~" marks changes
+// that are not statistically significant
+func FormatBenchDeltas(deltas []BenchDelta, ref string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-50s %-10s %14s %14s %9s %7s\n", "benchmark", "unit", ref, "working tree", "delta", "p")
+	for _, d := range deltas {
+		delta := "~"
+		if d.Significant {
+			delta = fmt.Sprintf("%+.2f%%", d.Delta)
+		}
+		fmt.Fprintf(&b, "%-50s %-10s %14s %14s %9s %7.3f\n", d.Name, d.Unit, formatBenchValue(d.Old), formatBenchValue(d.New), delta, d.P)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func formatBenchValue(v float64) string {
+	if v == float64(int64(v)) {
+		return strconv.FormatInt(int64(v), 10)
+	}
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+// runBenchmarks runs the benchmarks of the module in dir
+func runBenchmarks(dir string, opts BenchOptions) (string, error) {
+	return RunCommandInDir(dir, "go", "test", "-run=^$", "-bench="+opts.Bench, "-benchmem",
+		fmt.Sprintf("-count=%d", opts.Count), "./...")
+}
+
+// Bench runs the benchmarks of the module in the current directory. With
+// opts.Compare it also runs them on that ref (checked out in a temporary git
+// worktree) and reports the statistically significant differences.
+func (g *Go) Bench(opts BenchOptions) (_ string, err error) {
+	defer trackMetric("bench")(&err)
+	if opts.Count <= 0 {
+		opts.Count = 6
+	}
+	if opts.Bench == "" {
+		opts.Bench = "."
+	}
+
+	g.logger.Info("Running benchmarks on the working tree...")
+	newOut, err := runBenchmarks(".", opts)
+	if err != nil {
+		return newOut, fmt.Errorf("benchmarks failed: %w", err)
+	}
+	current := ParseBenchOutput(newOut)
+	if len(current) == 0 {
+		return "No benchmarks found", nil
+	}
+	if opts.Compare == "" {
+		return formatBenchSamples(current), nil
+	}
+
+	baseOut, err := g.benchAtRef(opts)
+	if err != nil {
+		return "", err
+	}
+	base := ParseBenchOutput(baseOut)
+
+	deltas := CompareBench(base, current, opts.Alpha)
+	if len(deltas) == 0 {
+		return fmt.Sprintf("No benchmarks in common with %s", opts.Compare), nil
+	}
+
+	slower, faster := 0, 0
+	for _, d := range deltas {
+		if !d.Significant {
+			continue
+		}
+		if (d.Delta > 0) == (d.Unit != "MB/s") { // throughput is the only higher-is-better unit
+			slower++
+		} else {
+			faster++
+		}
+	}
+	summary := fmt.Sprintf("✅ bench: no significant changes vs %s", opts.Compare)
+	if slower+faster > 0 {
+		summary = fmt.Sprintf("⚠️ bench: %d significant changes vs %s (%d worse, %d better)", slower+faster, opts.Compare, slower, faster)
+	}
+	return FormatBenchDeltas(deltas, opts.Compare) + "\n\n" + summary, nil
+}
+
+// benchAtRef runs the benchmarks on opts.Compare in a temporary worktree
+func (g *Go) benchAtRef(opts BenchOptions) (string, error) {
+	prefix, err := RunCommandSilent("git", "rev-parse", "--show-prefix")
+	if err != nil {
+		return "", fmt.Errorf("bench -compare needs a git repository: %w", err)
+	}
+	tmp, err := os.MkdirTemp("", "gotest-bench-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmp)
+
+	worktree := filepath.Join(tmp, "tree")
+	if _, err := RunCommand("git", "worktree", "add", "--detach", worktree, opts.Compare); err != nil {
+		return "", fmt.Errorf("checkout %s: %w", opts.Compare, err)
+	}
+	defer RunCommandSilent("git", "worktree", "remove", "--force", worktree)
+	repo, _ := os.Getwd()
+	defer OnInterrupt(func() {
+		RunCommandInDir(repo, "git", "worktree", "remove", "--force", worktree)
+		os.RemoveAll(tmp)
+	})()
+
+	g.logger.Info("Running benchmarks on", opts.Compare+"...")
+	out, err := runBenchmarks(filepath.Join(worktree, filepath.FromSlash(prefix)), opts)
+	if err != nil {
+		return out, fmt.Errorf("benchmarks failed on %s: %w", opts.Compare, err)
+	}
+	return out, nil
+}
+
+// formatBenchSamples lists the median of each benchmark metric
+func formatBenchSamples(samples BenchSamples) string {
+	names := make([]string, 0, len(samples))
+	for name := range samples {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		units := make([]string, 0, len(samples[name]))
+		for unit := range samples[name] {
+			units = append(units, unit)
+		}
+		sort.Slice(units, func(i, j int) bool { return unitOrder(units[i]) < unitOrder(units[j]) })
+		parts := make([]string, 0, len(units))
+		for _, unit := range units {
+			parts = append(parts, formatBenchValue(median(samples[name][unit]))+" "+unit)
+		}
+		fmt.Fprintf(&b, "%s: %s\n", name, strings.Join(parts, ", "))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
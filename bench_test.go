@@ -0,0 +1,95 @@
+package devflow
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParseBenchOutput(t *testing.T) {
+	output := `goos: linux
+goarch: amd64
+pkg: example.com/app
+cpu: Some CPU
+BenchmarkParse-8          1000      1200 ns/op      64 B/op       2 allocs/op
+BenchmarkParse-8          1000      1300 ns/op      64 B/op       2 allocs/op
+BenchmarkSub/case-1-8      500      2.5 ns/op
+PASS
+ok  	example.com/app	1.2s
+pkg: example.com/app/io
+BenchmarkCopy       200      10 ns/op     100.50 MB/s
+`
+	s := ParseBenchOutput(output)
+	if got := s["example.com/app.BenchmarkParse"]["ns/op"]; len(got) != 2 || got[1] != 1300 {
+		t.Errorf("ns/op samples = %v", got)
+	}
+	if got := s["example.com/app.BenchmarkParse"]["allocs/op"]; len(got) != 2 {
+		t.Errorf("allocs/op samples = %v", got)
+	}
+	if got := s["example.com/app.BenchmarkSub/case-1"]["ns/op"]; len(got) != 1 || got[0] != 2.5 {
+		t.Errorf("sub-benchmark samples = %v (all: %v)", got, s)
+	}
+	if got := s["example.com/app/io.BenchmarkCopy"]["MB/s"]; len(got) != 1 || got[0] != 100.5 {
+		t.Errorf("MB/s samples = %v", got)
+	}
+}
+
+func TestMannWhitneyU(t *testing.T) {
+	x := []float64{1, 2, 3, 4, 5, 6}
+	y := []float64{7, 8, 9, 10, 11, 12}
+	// Complete separation of 6+6 samples: 2 of C(12,6)=924 arrangements
+	if p := mannWhitneyU(x, y); math.Abs(p-2.0/924) > 1e-9 {
+		t.Errorf("separated p = %v, want %v", p, 2.0/924)
+	}
+	if p := mannWhitneyU(x, x); p != 1 {
+		t.Errorf("identical samples p = %v, want 1", p)
+	}
+	if p := mannWhitneyU([]float64{5, 5, 5}, []float64{5, 5, 5}); p != 1 {
+		t.Errorf("constant samples p = %v, want 1", p)
+	}
+	// Ties use the normal approximation
+	if p := mannWhitneyU([]float64{1, 1, 2, 2, 3, 3}, []float64{8, 8, 9, 9, 9, 10}); p > 0.01 {
+		t.Errorf("separated samples with ties p = %v, want < 0.01", p)
+	}
+	if p := mannWhitneyU([]float64{1, 3, 5, 7}, []float64{2, 4, 6, 8}); p < 0.5 {
+		t.Errorf("interleaved samples p = %v, want large", p)
+	}
+}
+
+func TestCompareBench(t *testing.T) {
+	base := BenchSamples{"p.BenchmarkA": {
+		"ns/op": {100, 101, 99, 100, 102, 98},
+		"B/op":  {64, 64, 64, 64, 64, 64},
+	}}
+	head := BenchSamples{
+		"p.BenchmarkA": {
+			"ns/op": {150, 151, 149, 152, 150, 148},
+			"B/op":  {64, 64, 64, 64, 64, 64},
+		},
+		"p.BenchmarkNew": {"ns/op": {1}},
+	}
+	deltas := CompareBench(base, head, 0)
+	if len(deltas) != 2 || deltas[0].Unit != "ns/op" {
+		t.Fatalf("unexpected deltas: %+v", deltas)
+	}
+	if d := deltas[0]; !d.Significant || math.Abs(d.Delta-50) > 0.01 {
+		t.Errorf("ns/op delta = %+v", d)
+	}
+	if deltas[1].Significant {
+		t.Errorf("unchanged B/op should not be significant: %+v", deltas[1])
+	}
+
+	table := FormatBenchDeltas(deltas, "main")
+	if !strings.Contains(table, "+50.00%") || !strings.Contains(table, "~") {
+		t.Errorf("unexpected table:\n%s", table)
+	}
+}
+
+func TestMedian(t *testing.T) {
+	if m := median([]float64{3, 1, 2}); m != 2 {
+		t.Errorf("odd median = %v", m)
+	}
+	if m := median([]float64{4, 1, 3, 2}); m != 2.5 {
+		t.Errorf("even median = %v", m)
+	}
+}
@@ -0,0 +1,479 @@
+package devflow
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// BootstrapManifestFile is the manifest devflow bootstrap export writes by
+// default
+const BootstrapManifestFile = "devflow-bootstrap.yaml"
+
+// BootstrapManifest describes the setup of a development machine: what
+// devflow bootstrap export records on one machine and devflow bootstrap
+// restores on another. It is written in the format of .devflow.yaml.
+type BootstrapManifest struct {
+	GitName    string            // git config --global user.name
+	GitEmail   string            // git config --global user.email
+	GitHubAuth bool              // Log gh in (keyring token or Device Flow)
+	Tools      []string          // Packages for go install, e.g. golang.org/x/tools/cmd/goimports@latest
+	Dotfiles   BootstrapDotfiles // Dotfiles repository (see Dotfiles)
+	Repos      []BootstrapRepo   // Repositories cloned and registered as project aliases
+}
+
+// BootstrapDotfiles is the dotfiles repository of a manifest; an empty
+// Remote skips it
+type BootstrapDotfiles struct {
+	Remote   string
+	Dir      string // Default: dotfiles.dir, else ~/.dotfiles
+	Strategy string // Default: dotfiles.strategy, else symlink
+}
+
+// BootstrapRepo is a repository of the inventory: cloned into Dir and
+// registered as projects.<Alias> in the global config
+type BootstrapRepo struct {
+	Alias string
+	URL   string
+	Dir   string // "~/" means the home directory
+}
+
+// LoadBootstrapManifest reads a manifest written by Marshal (or by hand)
+func LoadBootstrapManifest(path string) (*BootstrapManifest, error) {
+	cfg, err := LoadConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m := &BootstrapManifest{
+		GitName:    cfg.String("git.name", ""),
+		GitEmail:   cfg.String("git.email", ""),
+		GitHubAuth: cfg.Bool("github.auth", false),
+		Tools:      cfg.List("tools"),
+		Dotfiles: BootstrapDotfiles{
+			Remote:   cfg.String("dotfiles.remote", ""),
+			Dir:      cfg.String("dotfiles.dir", ""),
+			Strategy: cfg.String("dotfiles.strategy", ""),
+		},
+	}
+	for _, alias := range cfg.Keys("repos") {
+		repo := BootstrapRepo{Alias: alias, URL: cfg.String("repos."+alias+".url", ""), Dir: cfg.String("repos."+alias+".dir", "")}
+		if repo.URL == "" || repo.Dir == "" {
+			return nil, fmt.Errorf("%s: repos.%s needs url and dir", path, alias)
+		}
+		m.Repos = append(m.Repos, repo)
+	}
+	return m, nil
+}
+
+// Marshal returns the manifest in the format of .devflow.yaml
+func (m *BootstrapManifest) Marshal() []byte {
+	var b strings.Builder
+	b.WriteString("# Machine setup for 'devflow bootstrap <file>', written by 'devflow bootstrap export'\n")
+	if m.GitName != "" || m.GitEmail != "" {
+		b.WriteString("git:\n")
+		writeManifestValue(&b, "  ", "name", m.GitName)
+		writeManifestValue(&b, "  ", "email", m.GitEmail)
+	}
+	if m.GitHubAuth {
+		b.WriteString("github:\n  auth: true\n")
+	}
+	if len(m.Tools) > 0 {
+		b.WriteString("tools:\n")
+		for _, tool := range m.Tools {
+			b.WriteString("  - " + tool + "\n")
+		}
+	}
+	if m.Dotfiles.Remote != "" {
+		b.WriteString("dotfiles:\n")
+		writeManifestValue(&b, "  ", "remote", m.Dotfiles.Remote)
+		writeManifestValue(&b, "  ", "dir", m.Dotfiles.Dir)
+		writeManifestValue(&b, "  ", "strategy", m.Dotfiles.Strategy)
+	}
+	if len(m.Repos) > 0 {
+		b.WriteString("repos:\n")
+		for _, repo := range m.Repos {
+			b.WriteString("  " + repo.Alias + ":\n")
+			writeManifestValue(&b, "    ", "url", repo.URL)
+			writeManifestValue(&b, "    ", "dir", repo.Dir)
+		}
+	}
+	return []byte(b.String())
+}
+
+// writeManifestValue writes "key: value", quoted when the value has a
+// comment or leading or trailing spaces; nothing for an empty value
+func writeManifestValue(b *strings.Builder, indent, key, value string) {
+	if value == "" {
+		return
+	}
+	if strings.Contains(value, " #") || strings.TrimSpace(value) != value {
+		value = `"` + value + `"`
+	}
+	b.WriteString(indent + key + ": " + value + "\n")
+}
+
+// ExportBootstrapManifest records the setup of this machine: the global git
+// identity, whether gh is logged in, the Go tools installed in GOBIN (with
+// their versions), the dotfiles repository and the project aliases with an
+// origin
+func ExportBootstrapManifest() (*BootstrapManifest, error) {
+	m := &BootstrapManifest{}
+	m.GitName, _ = RunCommandSilent("git", "config", "--global", "user.name")
+	m.GitEmail, _ = RunCommandSilent("git", "config", "--global", "user.email")
+	if _, err := RunCommandSilent("gh", "auth", "status"); err == nil {
+		m.GitHubAuth = true
+	}
+	m.Tools = installedGoTools()
+
+	if d, err := NewDotfiles(); err == nil {
+		if remote, err := RunCommandInDir(d.Dir(), "git", "remote", "get-url", "origin"); err == nil {
+			m.Dotfiles = BootstrapDotfiles{Remote: remote, Dir: homeTilde(d.Dir()), Strategy: d.strategy}
+		}
+	}
+
+	global, err := LoadGlobalConfig()
+	if err != nil {
+		return nil, err
+	}
+	aliases := ProjectAliases(global)
+	names := make([]string, 0, len(aliases))
+	for alias := range aliases {
+		names = append(names, alias)
+	}
+	sort.Strings(names)
+	for _, alias := range names {
+		url, err := RunCommandInDir(aliases[alias], "git", "remote", "get-url", "origin")
+		if err != nil {
+			continue
+		}
+		m.Repos = append(m.Repos, BootstrapRepo{Alias: alias, URL: url, Dir: homeTilde(aliases[alias])})
+	}
+	return m, nil
+}
+
+// installedGoTools returns the packages of the binaries in GOBIN (or
+// GOPATH/bin) as path@version, from the build info go version -m prints
+func installedGoTools() []string {
+	bin, _ := RunCommandSilent("go", "env", "GOBIN")
+	if bin == "" {
+		gopath, err := RunCommandSilent("go", "env", "GOPATH")
+		if err != nil || gopath == "" {
+			return nil
+		}
+		bin = filepath.Join(strings.Split(gopath, string(os.PathListSeparator))[0], "bin")
+	}
+	entries, err := os.ReadDir(bin)
+	if err != nil {
+		return nil
+	}
+	var tools []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		out, err := RunCommandSilent("go", "version", "-m", filepath.Join(bin, e.Name()))
+		if err != nil {
+			continue
+		}
+		if tool := goToolFromBuildInfo(out); tool != "" {
+			tools = append(tools, tool)
+		}
+	}
+	sort.Strings(tools)
+	return tools
+}
+
+// goToolFromBuildInfo returns path@version of go version -m output, with
+// @latest for a development build
+func goToolFromBuildInfo(out string) string {
+	path, version := "", ""
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) >= 2 && fields[0] == "path":
+			path = fields[1]
+		case len(fields) >= 3 && fields[0] == "mod":
+			version = fields[2]
+		}
+	}
+	if path == "" {
+		return ""
+	}
+	if version == "" || version == "(devel)" {
+		version = "latest"
+	}
+	return path + "@" + version
+}
+
+// homeTilde writes a path in the home directory as ~/..., so a manifest
+// works for another user name
+func homeTilde(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(home, path); err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+		return "~/" + filepath.ToSlash(rel)
+	}
+	return path
+}
+
+// Bootstrap sets up a new machine from a BootstrapManifest
+type Bootstrap struct {
+	log    func(...any)
+	auth   GitHubAuthenticator
+	dryRun bool
+}
+
+// NewBootstrap creates a Bootstrap that logs gh in with GitHubAuth
+func NewBootstrap() *Bootstrap {
+	return &Bootstrap{log: func(...any) {}}
+}
+
+// SetLog sets the logger function
+func (b *Bootstrap) SetLog(fn func(...any)) {
+	if fn != nil {
+		b.log = fn
+	}
+}
+
+// SetAuth sets the authenticator gh is logged in with (default: GitHubAuth)
+func (b *Bootstrap) SetAuth(auth GitHubAuthenticator) {
+	b.auth = auth
+}
+
+// SetDryRun makes Run print the commands and file updates it would
+// perform instead of running them
+func (b *Bootstrap) SetDryRun(enabled bool) {
+	b.dryRun = enabled
+}
+
+// Run applies the manifest in order: git identity, gh authentication, Go
+// tools, dotfiles and repositories. What is already in place is kept (an
+// existing git identity, a cloned directory), and a failing step does not
+// stop the others, so it can be re-run after fixing one. It returns a
+// summary line per step and the errors together.
+func (b *Bootstrap) Run(m *BootstrapManifest) (_ []string, err error) {
+	if b.dryRun {
+		defer startDryRun()()
+	}
+	defer trackMetric("bootstrap")(&err)
+
+	var summary []string
+	var errs []error
+	step := func(name, result string, err error) {
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			summary = append(summary, "❌ "+name+": "+firstLine(err.Error()))
+		} else if result != "" {
+			summary = append(summary, "✅ "+name+": "+result)
+		}
+	}
+
+	if m.GitName != "" || m.GitEmail != "" {
+		result, err := b.gitIdentity(m.GitName, m.GitEmail)
+		step("Git identity", result, err)
+	}
+	if m.GitHubAuth {
+		result, err := b.githubAuth()
+		step("GitHub", result, err)
+	}
+	if len(m.Tools) > 0 {
+		result, err := b.installTools(m.Tools)
+		step("Tools", result, err)
+	}
+	if m.Dotfiles.Remote != "" {
+		result, err := b.dotfiles(m.Dotfiles)
+		step("Dotfiles", result, err)
+	}
+	if len(m.Repos) > 0 {
+		result, err := b.cloneRepos(m.Repos)
+		step("Repos", result, err)
+	}
+	return summary, errors.Join(errs...)
+}
+
+// gitIdentity sets the global user.name and user.email that are not set
+func (b *Bootstrap) gitIdentity(name, email string) (string, error) {
+	var set []string
+	for _, kv := range [][2]string{{"user.name", name}, {"user.email", email}} {
+		if kv[1] == "" {
+			continue
+		}
+		if current, _ := RunCommandSilent("git", "config", "--global", kv[0]); current != "" {
+			if current != kv[1] {
+				b.log("Keeping", kv[0], current, "instead of", kv[1])
+			}
+			continue
+		}
+		if _, err := RunCommand("git", "config", "--global", kv[0], kv[1]); err != nil {
+			return "", err
+		}
+		set = append(set, kv[0])
+	}
+	if len(set) == 0 {
+		return "already set", nil
+	}
+	return "set " + strings.Join(set, ", "), nil
+}
+
+// githubAuth logs gh in unless it already is
+func (b *Bootstrap) githubAuth() (string, error) {
+	if _, err := RunCommandSilent("gh", "auth", "status"); err == nil {
+		return "already authenticated", nil
+	}
+	if b.dryRun {
+		dryRunf("", "gh auth login (Device Flow)")
+		return "authenticated", nil
+	}
+	auth := b.auth
+	if auth == nil {
+		a := NewGitHubAuth()
+		a.SetLog(b.log)
+		auth = a
+	}
+	if err := auth.EnsureGitHubAuth(); err != nil {
+		return "", err
+	}
+	return "authenticated", nil
+}
+
+// installTools runs go install for each tool, going on after a failure
+func (b *Bootstrap) installTools(tools []string) (string, error) {
+	if err := AirGapCheck(NetToolInstall); err != nil {
+		return "", err
+	}
+	var errs []error
+	installed := 0
+	for _, tool := range tools {
+		if !strings.Contains(tool, "@") {
+			tool += "@latest"
+		}
+		b.log("Installing", tool)
+		if out, err := RunCommand("go", "install", tool); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s", tool, firstLine(out)))
+			continue
+		}
+		installed++
+	}
+	return fmt.Sprintf("%d of %d installed", installed, len(tools)), errors.Join(errs...)
+}
+
+// dotfiles clones the dotfiles repository and places its files
+func (b *Bootstrap) dotfiles(m BootstrapDotfiles) (string, error) {
+	d, err := NewDotfiles()
+	if err != nil {
+		return "", err
+	}
+	d.SetLog(b.log)
+	if m.Dir != "" {
+		d.SetDir(m.Dir)
+	}
+	if m.Strategy != "" {
+		if err := d.SetStrategy(m.Strategy); err != nil {
+			return "", err
+		}
+	}
+	if b.dryRun {
+		dryRunf("", "devflow dotfiles init %s  (into %s)", m.Remote, d.Dir())
+		return "restored", nil
+	}
+	summary, err := d.Init(m.Remote)
+	return strings.TrimPrefix(summary, "✅ Dotfiles: "), err
+}
+
+// cloneRepos clones the repositories whose directory does not exist and
+// registers the aliases the global config does not have
+func (b *Bootstrap) cloneRepos(repos []BootstrapRepo) (string, error) {
+	global, err := LoadGlobalConfig()
+	if err != nil {
+		return "", err
+	}
+	home, _ := os.UserHomeDir()
+	var errs []error
+	var register []BootstrapRepo
+	cloned, present := 0, 0
+	for _, repo := range repos {
+		dir := repo.Dir
+		if rest, ok := strings.CutPrefix(dir, "~/"); ok && home != "" {
+			dir = filepath.Join(home, rest)
+		}
+		if _, err := os.Stat(dir); err == nil {
+			present++
+		} else {
+			b.log("Cloning", repo.URL, "into", dir)
+			if !b.dryRun {
+				if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+					errs = append(errs, err)
+					continue
+				}
+			}
+			if out, err := RunCommand("git", "clone", "--quiet", "--", repo.URL, dir); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %s", repo.Alias, firstLine(out)))
+				continue
+			}
+			cloned++
+		}
+		if !global.Has("projects." + repo.Alias) {
+			register = append(register, repo)
+		}
+	}
+	if err := registerProjects(register); err != nil {
+		errs = append(errs, err)
+	}
+	result := fmt.Sprintf("%d cloned, %d present", cloned, present)
+	if len(register) > 0 {
+		result += fmt.Sprintf(", %d aliases added", len(register))
+	}
+	return result, errors.Join(errs...)
+}
+
+// registerProjects adds projects.<alias> entries to the global config,
+// under its projects section when it has one
+func registerProjects(repos []BootstrapRepo) error {
+	if len(repos) == 0 {
+		return nil
+	}
+	path, err := globalConfigPath()
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if os.IsNotExist(err) {
+		if _, err := os.Stat(strings.TrimSuffix(path, ".yaml") + ".toml"); err == nil {
+			return fmt.Errorf("add the projects to %s by hand, aliases are only written to YAML", strings.TrimSuffix(path, ".yaml")+".toml")
+		}
+	}
+
+	var entries strings.Builder
+	for _, repo := range repos {
+		writeManifestValue(&entries, "  ", repo.Alias, repo.Dir)
+	}
+	lines := strings.SplitAfter(string(data), "\n")
+	content := ""
+	for i, line := range lines {
+		if strings.TrimRight(line, " \r\n") == "projects:" {
+			content = strings.Join(lines[:i+1], "") + entries.String() + strings.Join(lines[i+1:], "")
+			break
+		}
+	}
+	if content == "" {
+		content = string(data)
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		content += "projects:\n" + entries.String()
+	}
+	if !DryRunActive() {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+	}
+	return writeUnlessDryRun(path, []byte(content))
+}
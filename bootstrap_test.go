@@ -0,0 +1,98 @@
+package devflow
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBootstrapManifestRoundTrip(t *testing.T) {
+	m := &BootstrapManifest{
+		GitName:    "Jane Doe",
+		GitEmail:   "jane@example.com",
+		GitHubAuth: true,
+		Tools:      []string{"golang.org/x/tools/cmd/goimports@v0.20.0"},
+		Dotfiles:   BootstrapDotfiles{Remote: "git@github.com:jane/dotfiles.git", Dir: "~/.dotfiles"},
+		Repos:      []BootstrapRepo{{Alias: "api", URL: "git@github.com:jane/api.git", Dir: "~/Dev/api"}},
+	}
+	path := filepath.Join(t.TempDir(), BootstrapManifestFile)
+	os.WriteFile(path, m.Marshal(), 0644)
+	loaded, err := LoadBootstrapManifest(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, m) {
+		t.Errorf("round trip changed the manifest:\n%+v\n%+v", loaded, m)
+	}
+
+	os.WriteFile(path, []byte("repos:\n  api:\n    url: git@github.com:jane/api.git\n"), 0644)
+	if _, err := LoadBootstrapManifest(path); err == nil {
+		t.Error("expected a repository without dir to fail")
+	}
+}
+
+func TestGoToolFromBuildInfo(t *testing.T) {
+	out := "/go/bin/goimports: go1.22.1\n\tpath\tgolang.org/x/tools/cmd/goimports\n\tmod\tgolang.org/x/tools\tv0.20.0\th1:abc=\n"
+	if got := goToolFromBuildInfo(out); got != "golang.org/x/tools/cmd/goimports@v0.20.0" {
+		t.Errorf("unexpected tool %q", got)
+	}
+	if got := goToolFromBuildInfo("x: go1.22\n\tpath\texample.com/cmd/x\n\tmod\texample.com\t(devel)\t\n"); got != "example.com/cmd/x@latest" {
+		t.Errorf("expected @latest for a development build, got %q", got)
+	}
+}
+
+func TestBootstrapRun(t *testing.T) {
+	tmp := testResumeEnv(t)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmp, ".config"))
+	exec.Command("git", "config", "--global", "--unset", "user.email").Run()
+
+	// A repository of the inventory
+	remote := filepath.Join(tmp, "api.git")
+	exec.Command("git", "init", "--bare", "-q", remote).Run()
+	seed, cleanup := testCreateGitRepo()
+	defer cleanup()
+	os.WriteFile(filepath.Join(seed, "go.mod"), []byte("module api\n"), 0644)
+	RunCommandInDir(seed, "git", "add", ".")
+	RunCommandInDir(seed, "git", "commit", "-qm", "init")
+	RunCommandInDir(seed, "git", "push", "-q", remote, "HEAD")
+
+	global := filepath.Join(tmp, ".config", GlobalConfigFile)
+	os.MkdirAll(filepath.Dir(global), 0755)
+	os.WriteFile(global, []byte("projects:\n  web: ~/Dev/web\nmetrics:\n  enabled: false\n"), 0644)
+
+	m := &BootstrapManifest{
+		GitName:  "Jane Doe",
+		GitEmail: "jane@example.com",
+		Repos:    []BootstrapRepo{{Alias: "api", URL: remote, Dir: "~/Dev/api"}},
+	}
+	summary, err := NewBootstrap().Run(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"✅ Git identity: set user.email", "✅ Repos: 1 cloned, 0 present, 1 aliases added"}
+	if !reflect.DeepEqual(summary, want) {
+		t.Errorf("unexpected summary %q", summary)
+	}
+	if name, _ := RunCommandSilent("git", "config", "--global", "user.name"); name != "TestUser" {
+		t.Errorf("expected the existing user.name kept, got %q", name)
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "Dev", "api", "go.mod")); err != nil {
+		t.Error("expected the repository cloned")
+	}
+	cfg, _ := LoadGlobalConfig()
+	if got := ProjectAliases(cfg)["api"]; got != filepath.Join(tmp, "Dev", "api") {
+		t.Errorf("expected the api alias registered, got %q", got)
+	}
+	if cfg.Bool("metrics.enabled", true) {
+		t.Error("expected the rest of the global config kept")
+	}
+
+	// Re-running finds everything in place
+	summary, err = NewBootstrap().Run(m)
+	if err != nil || strings.Join(summary, "; ") != "✅ Git identity: already set; ✅ Repos: 0 cloned, 1 present" {
+		t.Errorf("unexpected second run %q, %v", summary, err)
+	}
+}
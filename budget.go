@@ -0,0 +1,206 @@
+package devflow
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// budgetGrace is how long go test may take past the budget to report the
+// packages that hit -timeout before the remaining processes are stopped
+var budgetGrace = 10 * time.Second
+
+var (
+	// pkgResultRe matches "ok  \tpkg\t1.23s", "FAIL\tpkg\t0.5s" and "?   \tpkg\t[no test files]"
+	pkgResultRe = regexp.MustCompile(`^(ok|FAIL|\?)\s+(\S+)\s+(\S+)`)
+	// pkgNoTestsRe matches "\tpkg\t\tcoverage: 0.0% of statements" (packages without tests under -cover)
+	pkgNoTestsRe = regexp.MustCompile(`^\s+(\S+)\s+coverage:`)
+	// runningTestRe matches a test listed after "panic: test timed out", e.g. "\tTestSlow (2m0s)"
+	runningTestRe = regexp.MustCompile(`^\s+(Test\S*|Example\S*|Fuzz\S*) \(`)
+)
+
+// PackageTime is the wall time go test reported for one package
+type PackageTime struct {
+	Package  string
+	Elapsed  time.Duration
+	TimedOut []string // Tests still running when the package hit -timeout
+}
+
+// LoadTestBudget reads test.budget (a duration such as "10m") from c
+func LoadTestBudget(c *Config) (time.Duration, error) {
+	if c == nil || !c.Has("test.budget") {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(c.String("test.budget", ""))
+	if err != nil {
+		return 0, fmt.Errorf("test.budget: %w", err)
+	}
+	return d, nil
+}
+
+// ParsePackageTimes collects the per-package results of go test output, in
+// the order reported, and the import paths of every package that finished
+func ParsePackageTimes(output string) ([]PackageTime, map[string]bool) {
+	var times []PackageTime
+	finished := make(map[string]bool)
+	var running []string
+	inTimeout := false
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.HasPrefix(line, "panic: test timed out") {
+			inTimeout, running = true, nil
+			continue
+		}
+		if inTimeout {
+			if m := runningTestRe.FindStringSubmatch(line); m != nil {
+				running = append(running, m[1])
+				continue
+			}
+		}
+		if m := pkgNoTestsRe.FindStringSubmatch(line); m != nil {
+			finished[m[1]] = true
+			continue
+		}
+		m := pkgResultRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		finished[m[2]] = true
+		if m[1] == "?" {
+			continue
+		}
+		d, err := time.ParseDuration(m[3])
+		if err != nil {
+			continue
+		}
+		pt := PackageTime{Package: m[2], Elapsed: d}
+		if inTimeout {
+			pt.TimedOut = running
+			inTimeout, running = false, nil
+		}
+		times = append(times, pt)
+	}
+	return times, finished
+}
+
+// testBudget bounds the wall time of all go test invocations of one run
+type testBudget struct {
+	limit    time.Duration
+	deadline time.Time
+	stopped  bool // A go test process was stopped at the deadline
+}
+
+func newTestBudget(limit time.Duration) *testBudget {
+	if limit <= 0 {
+		return nil
+	}
+	return &testBudget{limit: limit, deadline: time.Now().Add(limit)}
+}
+
+// args adds -timeout with the remaining budget to go test args, so test
+// binaries still running at the deadline panic with the list of running tests
+func (b *testBudget) args(args []string) []string {
+	if b == nil {
+		return args
+	}
+	remaining := time.Until(b.deadline).Round(time.Second)
+	if remaining < time.Second {
+		remaining = time.Second
+	}
+	return append([]string{args[0], "-timeout=" + remaining.String()}, args[1:]...)
+}
+
+// expired reports whether the budget is used up
+func (b *testBudget) expired() bool {
+	return b != nil && !time.Now().Before(b.deadline)
+}
+
+// exceeded reports whether the run went over the budget, judging by the
+// clock, a stopped process or a package that hit -timeout in output
+func (b *testBudget) exceeded(output string) bool {
+	if b == nil {
+		return false
+	}
+	if b.stopped || b.expired() {
+		return true
+	}
+	times, _ := ParsePackageTimes(output)
+	for _, t := range times {
+		if len(t.TimedOut) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// run runs cmd, interrupting its process group once the budget and the
+// grace period are over, and killing it if it still does not exit
+func (b *testBudget) run(cmd *exec.Cmd) error {
+	if b == nil {
+		return runTracked(cmd)
+	}
+	setProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	defer trackProcess(cmd, true)()
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(time.Until(b.deadline) + budgetGrace):
+	}
+	b.stopped = true
+	stopProcessGroup(cmd, false)
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(budgetGrace):
+		stopProcessGroup(cmd, true)
+		return <-done
+	}
+}
+
+// report summarizes which packages consumed the budget. pkgs are all the
+// packages that were meant to run; those never reported are unfinished.
+func (b *testBudget) report(output string, pkgs []string) string {
+	times, finished := ParsePackageTimes(output)
+	sort.SliceStable(times, func(i, j int) bool { return times[i].Elapsed > times[j].Elapsed })
+	if len(times) > 5 {
+		times = times[:5]
+	}
+
+	var slowest []string
+	for _, t := range times {
+		s := fmt.Sprintf("%s %s", t.Package, t.Elapsed.Round(100*time.Millisecond))
+		if len(t.TimedOut) > 0 {
+			s += " [" + strings.Join(t.TimedOut, " ") + "]"
+		}
+		slowest = append(slowest, s)
+	}
+	var unfinished []string
+	for _, p := range pkgs {
+		if !finished[p] {
+			unfinished = append(unfinished, p)
+		}
+	}
+
+	msg := fmt.Sprintf("time budget %s exceeded", b.limit)
+	var details []string
+	if len(slowest) > 0 {
+		details = append(details, "slowest: "+strings.Join(slowest, "; "))
+	}
+	if len(unfinished) > 0 {
+		details = append(details, "unfinished: "+strings.Join(unfinished, " "))
+	}
+	if len(details) > 0 {
+		msg += " (" + strings.Join(details, "; ") + ")"
+	}
+	return msg
+}
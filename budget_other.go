@@ -0,0 +1,13 @@
+//go:build !unix
+
+package devflow
+
+import "os/exec"
+
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// stopProcessGroup kills cmd; without process groups the test binaries it
+// started exit when their -timeout fires
+func stopProcessGroup(cmd *exec.Cmd, force bool) {
+	_ = cmd.Process.Kill()
+}
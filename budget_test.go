@@ -0,0 +1,100 @@
+package devflow
+
+import (
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+const budgetOutputFixture = `ok  	example.com/m/fast	0.120s	coverage: 80.0% of statements
+panic: test timed out after 30s
+	running tests:
+		TestSlow (30s)
+		TestSlower (30s)
+
+goroutine 1 [running]:
+FAIL	example.com/m/slow	30.014s
+?   	example.com/m/cmd	[no test files]
+	example.com/m/util		coverage: 0.0% of statements
+FAIL
+`
+
+func TestParsePackageTimes(t *testing.T) {
+	times, finished := ParsePackageTimes(budgetOutputFixture)
+	if len(times) != 2 {
+		t.Fatalf("expected 2 package times, got %+v", times)
+	}
+	if times[0].Package != "example.com/m/fast" || times[0].Elapsed != 120*time.Millisecond || times[0].TimedOut != nil {
+		t.Errorf("unexpected fast package: %+v", times[0])
+	}
+	if times[1].Package != "example.com/m/slow" || strings.Join(times[1].TimedOut, ",") != "TestSlow,TestSlower" {
+		t.Errorf("unexpected slow package: %+v", times[1])
+	}
+	for _, pkg := range []string{"example.com/m/fast", "example.com/m/slow", "example.com/m/cmd", "example.com/m/util"} {
+		if !finished[pkg] {
+			t.Errorf("%s not reported as finished", pkg)
+		}
+	}
+}
+
+func TestTestBudgetReport(t *testing.T) {
+	b := newTestBudget(30 * time.Second)
+	if !b.exceeded(budgetOutputFixture) {
+		t.Error("expected the timed out package to exceed the budget")
+	}
+	got := b.report(budgetOutputFixture, []string{"example.com/m/fast", "example.com/m/slow", "example.com/m/pending"})
+	want := "time budget 30s exceeded (slowest: example.com/m/slow 30s [TestSlow TestSlower]; example.com/m/fast 100ms; unfinished: example.com/m/pending)"
+	if got != want {
+		t.Errorf("report:\n got %s\nwant %s", got, want)
+	}
+
+	if newTestBudget(0) != nil {
+		t.Error("zero limit should disable the budget")
+	}
+	var none *testBudget
+	if none.exceeded(budgetOutputFixture) || none.expired() {
+		t.Error("nil budget should never be exceeded")
+	}
+	if args := none.args([]string{"test", "./..."}); len(args) != 2 {
+		t.Errorf("nil budget changed args: %v", args)
+	}
+	if args := b.args([]string{"test", "./..."}); args[1] != "-timeout=30s" || args[2] != "./..." {
+		t.Errorf("unexpected args: %v", args)
+	}
+}
+
+func TestTestBudgetStopsProcess(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sleep")
+	}
+	defer func(d time.Duration) { budgetGrace = d }(budgetGrace)
+	budgetGrace = 100 * time.Millisecond
+
+	b := newTestBudget(100 * time.Millisecond)
+	start := time.Now()
+	if err := b.run(exec.Command("sleep", "30")); err == nil {
+		t.Error("expected the stopped process to fail")
+	}
+	if !b.stopped || time.Since(start) > 5*time.Second {
+		t.Errorf("process not stopped at the deadline (stopped=%v after %s)", b.stopped, time.Since(start))
+	}
+}
+
+func TestLoadTestBudget(t *testing.T) {
+	c, err := ParseConfig("test:\n  budget: 10m\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d, err := LoadTestBudget(c); err != nil || d != 10*time.Minute {
+		t.Errorf("LoadTestBudget = %s, %v", d, err)
+	}
+	if d, err := LoadTestBudget(NewConfig()); err != nil || d != 0 {
+		t.Errorf("LoadTestBudget(empty) = %s, %v", d, err)
+	}
+	c, _ = ParseConfig("test:\n  budget: soon\n")
+	if _, err := LoadTestBudget(c); err == nil {
+		t.Error("expected error for invalid duration")
+	}
+}
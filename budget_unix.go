@@ -0,0 +1,23 @@
+//go:build unix
+
+package devflow
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup starts cmd in its own process group so the test binaries
+// started by go test can be signalled with it
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// stopProcessGroup interrupts (or with force, kills) the process group of cmd
+func stopProcessGroup(cmd *exec.Cmd, force bool) {
+	sig := syscall.SIGINT
+	if force {
+		sig = syscall.SIGKILL
+	}
+	_ = syscall.Kill(-cmd.Process.Pid, sig)
+}
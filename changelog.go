@@ -0,0 +1,328 @@
+package devflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ChangelogFile is the changelog release notes are read from
+const ChangelogFile = "CHANGELOG.md"
+
+// Release note sources (tag.notes config)
+const (
+	TagNotesChangelog = "changelog" // CHANGELOG.md section only (default)
+	TagNotesCommits   = "commits"   // CHANGELOG.md section, else commit subjects since the previous tag
+	TagNotesOff       = "off"       // Lightweight tags
+)
+
+// changelogLinkRe matches link reference definitions such as
+// "[1.2.0]: https://github.com/o/r/compare/v1.1.0...v1.2.0"
+var changelogLinkRe = regexp.MustCompile(`^\[[^\]]+\]:\s`)
+
+// ChangelogSection returns the body of the "## [1.2.3]" (or "## v1.2.3 -
+// 2024-01-02") section of a Keep a Changelog style document, or "" if
+// the version has no section
+func ChangelogSection(content, version string) string {
+	version = strings.TrimPrefix(version, "v")
+	var section []string
+	in := false
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.HasPrefix(line, "## ") {
+			if in {
+				break
+			}
+			in = changelogHeadingVersion(line) == version
+			continue
+		}
+		if in {
+			section = append(section, line)
+		}
+	}
+	// Link definitions after the last section are not part of it
+	for len(section) > 0 {
+		last := strings.TrimSpace(section[len(section)-1])
+		if last != "" && !changelogLinkRe.MatchString(last) {
+			break
+		}
+		section = section[:len(section)-1]
+	}
+	return strings.TrimSpace(strings.Join(section, "\n"))
+}
+
+// changelogHeadingVersion extracts "1.2.3" from "## [v1.2.3] - 2024-01-02"
+func changelogHeadingVersion(heading string) string {
+	fields := strings.Fields(strings.TrimPrefix(heading, "## "))
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.TrimPrefix(strings.Trim(fields[0], "[]"), "v")
+}
+
+// ReleaseNotes returns the notes of tag: its CHANGELOG.md section, or with
+// tag.notes: commits the subjects committed since the previous tag. The
+// same text is the annotated tag message and the release body, so the
+// three never disagree. Empty means no notes (a lightweight tag).
+func (g *Git) ReleaseNotes(tag string) (string, error) {
+	mode := TagNotesChangelog
+	if cfg, err := LoadConfig(g.rootDir); err == nil {
+		mode = cfg.String("tag.notes", TagNotesChangelog)
+	}
+	switch mode {
+	case TagNotesOff:
+		return "", nil
+	case TagNotesChangelog, TagNotesCommits:
+	default:
+		return "", fmt.Errorf("tag.notes: unknown mode %q (want changelog, commits or off)", mode)
+	}
+
+	if data, err := os.ReadFile(filepath.Join(g.rootDir, ChangelogFile)); err == nil {
+		if notes := ChangelogSection(string(data), tag); notes != "" {
+			return notes, nil
+		}
+	}
+	if mode != TagNotesCommits {
+		return "", nil
+	}
+	return g.commitNotes()
+}
+
+// commitNotes lists the commit subjects since the latest tag
+func (g *Git) commitNotes() (string, error) {
+	args := []string{"log", "--no-merges", "--format=- %s"}
+	if prev, _ := g.GetLatestTag(); prev != "" {
+		args = append(args, prev+"..HEAD")
+	}
+	out, err := RunCommandSilent("git", args...)
+	if err != nil {
+		return "", err
+	}
+	return out, nil
+}
+
+// tagMessage is the annotated tag message for tag and its notes
+func tagMessage(tag, notes string) string {
+	return tag + "\n\n" + notes
+}
+
+// changelogSections maps conventional commit types to Keep a Changelog
+// sections. Types mapped to "" are routine and get no entry.
+var changelogSections = map[string]string{
+	"feat":      "Added",
+	"fix":       "Fixed",
+	"perf":      "Changed",
+	"refactor":  "Changed",
+	"deprecate": "Deprecated",
+	"remove":    "Removed",
+	"revert":    "Removed",
+	"security":  "Security",
+	"docs":      "",
+	"chore":     "",
+	"test":      "",
+	"ci":        "",
+	"style":     "",
+	"build":     "",
+	"deps":      "",
+}
+
+// changelogUnreleasedLinkRe matches "[Unreleased]: <base>/compare/v1.2.0...HEAD"
+var changelogUnreleasedLinkRe = regexp.MustCompile(`^\[Unreleased\]:\s*(\S+)/compare/(\S+)\.\.\.HEAD\s*$`)
+
+// ChangelogEntry returns the Keep a Changelog section and entry for a commit
+// message: "feat(api): add x" is "Added", "**api**: add x". Routine types
+// (docs, chore, test, ci...) return an empty section.
+func ChangelogEntry(message string) (section, entry string) {
+	subject := strings.TrimSpace(strings.SplitN(message, "\n", 2)[0])
+	m := conventionalRe.FindStringSubmatch(subject)
+	if m == nil {
+		return "Changed", subject
+	}
+	section, known := changelogSections[strings.ToLower(m[1])]
+	if !known {
+		section = "Changed"
+	}
+	if section == "" {
+		return "", ""
+	}
+	entry = m[4]
+	if scope := strings.Trim(m[2], "()"); scope != "" {
+		entry = "**" + scope + "**: " + entry
+	}
+	if m[3] == "!" {
+		entry = "**BREAKING:** " + entry
+	}
+	return section, entry
+}
+
+// unreleasedBounds returns the line range of the "## [Unreleased]" section
+// (heading index, end index), or -1 if there is none. Trailing link
+// definitions of the document are not part of the section.
+func unreleasedBounds(lines []string) (int, int) {
+	start := -1
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "## ") {
+			continue
+		}
+		if start >= 0 {
+			return start, i
+		}
+		if strings.EqualFold(changelogHeadingVersion(line), "unreleased") {
+			start = i
+		}
+	}
+	if start < 0 {
+		return -1, -1
+	}
+	end := len(lines)
+	for end > start+1 {
+		last := strings.TrimSpace(lines[end-1])
+		if last != "" && !changelogLinkRe.MatchString(last) {
+			break
+		}
+		end--
+	}
+	return start, end
+}
+
+// AddUnreleasedEntry adds "- entry" under "### section" of the Unreleased
+// section, creating both (and the document) when missing. An entry that
+// is already listed is not added twice.
+func AddUnreleasedEntry(content, section, entry string) string {
+	if strings.TrimSpace(content) == "" {
+		content = "# Changelog\n\nAll notable changes to this project are documented in this file.\n"
+	}
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	start, end := unreleasedBounds(lines)
+	if start < 0 {
+		at := len(lines)
+		for i, line := range lines {
+			if strings.HasPrefix(line, "## ") {
+				at = i
+				break
+			}
+		}
+		head := append([]string{}, lines[:at]...)
+		if at > 0 && strings.TrimSpace(head[at-1]) != "" {
+			head = append(head, "")
+		}
+		head = append(head, "## [Unreleased]", "")
+		lines = append(head, lines[at:]...)
+		start, end = unreleasedBounds(lines)
+	}
+
+	item := "- " + entry
+	catStart, catEnd := -1, end
+	for i := start + 1; i < end; i++ {
+		if strings.TrimSpace(lines[i]) == item {
+			return content
+		}
+		if strings.HasPrefix(lines[i], "### ") {
+			if catStart >= 0 && catEnd == end {
+				catEnd = i
+			}
+			if strings.TrimSpace(strings.TrimPrefix(lines[i], "### ")) == section {
+				catStart, catEnd = i, end
+			}
+		}
+	}
+
+	var insert []string
+	at := 0
+	if catStart >= 0 {
+		at = lastContentLine(lines, catStart, catEnd) + 1
+		insert = []string{item}
+	} else {
+		at = lastContentLine(lines, start, end) + 1
+		insert = []string{"", "### " + section, item}
+	}
+	out := append(append(append([]string{}, lines[:at]...), insert...), lines[at:]...)
+	// Keep a blank line before the next heading or link definitions
+	if next := at + len(insert); next < len(out) && strings.TrimSpace(out[next]) != "" {
+		out = append(out[:next], append([]string{""}, out[next:]...)...)
+	}
+	return strings.TrimRight(strings.Join(out, "\n"), "\n") + "\n"
+}
+
+// lastContentLine returns the last non-blank line in lines[from:to], from included
+func lastContentLine(lines []string, from, to int) int {
+	for i := to - 1; i > from; i-- {
+		if strings.TrimSpace(lines[i]) != "" {
+			return i
+		}
+	}
+	return from
+}
+
+// ReleaseUnreleased renames the Unreleased section to "## [1.2.0] - date"
+// and starts a new empty Unreleased section above it. The
+// "[Unreleased]: .../compare/v1.1.0...HEAD" link, if any, moves on to the
+// new tag and the version gets its own compare link. Reports false when
+// there is nothing unreleased.
+func ReleaseUnreleased(content, tag, date string) (string, bool) {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	start, end := unreleasedBounds(lines)
+	if start < 0 || lastContentLine(lines, start, end) == start {
+		return content, false
+	}
+	version := strings.TrimPrefix(tag, "v")
+	heading := fmt.Sprintf("## [%s] - %s", version, date)
+
+	out := append([]string{}, lines[:start]...)
+	out = append(out, "## [Unreleased]", "", heading)
+	for _, line := range lines[start+1:] {
+		if m := changelogUnreleasedLinkRe.FindStringSubmatch(line); m != nil {
+			out = append(out,
+				fmt.Sprintf("[Unreleased]: %s/compare/%s...HEAD", m[1], tag),
+				fmt.Sprintf("[%s]: %s/compare/%s...%s", version, m[1], m[2], tag))
+			continue
+		}
+		out = append(out, line)
+	}
+	return strings.TrimRight(strings.Join(out, "\n"), "\n") + "\n", true
+}
+
+// updateChangelog records message in the Unreleased section of
+// CHANGELOG.md and, when tag is set, rolls the section into tag. Runs
+// before the commit so the changelog is part of it. Opt-in via
+// changelog.unreleased.
+func (g *Git) updateChangelog(message, tag string) (string, error) {
+	cfg, err := LoadConfig(g.rootDir)
+	if err != nil || !cfg.Bool("changelog.unreleased", false) {
+		return "", nil
+	}
+	path := filepath.Join(g.rootDir, ChangelogFile)
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	content := string(data)
+
+	// Only commits that change something get an entry
+	if changed, err := g.hasChanges(); err != nil {
+		return "", err
+	} else if section, entry := ChangelogEntry(message); changed && section != "" {
+		content = AddUnreleasedEntry(content, section, entry)
+	}
+
+	summary := ""
+	if tag != "" {
+		var released bool
+		if content, released = ReleaseUnreleased(content, tag, time.Now().Format("2006-01-02")); released {
+			summary = "✅ " + ChangelogFile + ": " + tag
+		}
+	}
+	if content == string(data) {
+		return "", nil
+	}
+	if err := writeUnlessDryRun(path, []byte(content)); err != nil {
+		return "", err
+	}
+	if _, err := RunCommand("git", "add", path); err != nil {
+		return "", err
+	}
+	return summary, nil
+}
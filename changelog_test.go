@@ -0,0 +1,159 @@
+package devflow
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+const testChangelog = `# Changelog
+
+## [Unreleased]
+
+- Work in progress
+
+## [1.2.0] - 2024-03-01
+
+### Added
+- Retry on proxy errors
+
+## v1.1.0
+
+- First stable API
+
+[1.2.0]: https://github.com/o/r/compare/v1.1.0...v1.2.0
+[1.1.0]: https://github.com/o/r/releases/tag/v1.1.0
+`
+
+func TestChangelogSection(t *testing.T) {
+	if got := ChangelogSection(testChangelog, "v1.2.0"); got != "### Added\n- Retry on proxy errors" {
+		t.Errorf("v1.2.0 section = %q", got)
+	}
+	if got := ChangelogSection(testChangelog, "1.1.0"); got != "- First stable API" {
+		t.Errorf("v1.1.0 section = %q", got)
+	}
+	if got := ChangelogSection(testChangelog, "v2.0.0"); got != "" {
+		t.Errorf("missing version section = %q", got)
+	}
+}
+
+func TestCreateTagFromChangelog(t *testing.T) {
+	defer testPushedRepo(t)()
+	os.WriteFile(ChangelogFile, []byte(testChangelog), 0644)
+	exec.Command("git", "add", ".").Run()
+	exec.Command("git", "commit", "-m", "docs: changelog").Run()
+	git, _ := NewGit()
+
+	if _, err := git.CreateTag("v1.2.0"); err != nil {
+		t.Fatal(err)
+	}
+	msg, _ := RunCommandSilent("git", "tag", "-l", "--format=%(contents)", "v1.2.0")
+	if !strings.HasPrefix(msg, "v1.2.0\n\n### Added\n- Retry on proxy errors") {
+		t.Errorf("tag message = %q", msg)
+	}
+
+	// No section: lightweight by default, commit subjects with tag.notes: commits
+	if _, err := git.CreateTag("v1.2.1"); err != nil {
+		t.Fatal(err)
+	}
+	if kind, _ := RunCommandSilent("git", "cat-file", "-t", "v1.2.1"); kind != "commit" {
+		t.Errorf("v1.2.1 is a %s, want a lightweight tag", kind)
+	}
+	os.WriteFile(".devflow.yaml", []byte("tag:\n  notes: commits\n"), 0644)
+	os.WriteFile("fix.go", []byte("package fix\n"), 0644)
+	exec.Command("git", "add", ".").Run()
+	exec.Command("git", "commit", "-m", "fix: handle empty input").Run()
+	notes, err := git.ReleaseNotes("v1.2.2")
+	if err != nil || notes != "- fix: handle empty input" {
+		t.Errorf("ReleaseNotes = %q, %v", notes, err)
+	}
+}
+
+func TestChangelogEntry(t *testing.T) {
+	for msg, want := range map[string][2]string{
+		"feat(api): add retries":  {"Added", "**api**: add retries"},
+		"fix!: drop nil inputs":   {"Fixed", "**BREAKING:** drop nil inputs"},
+		"perf: faster parse\n\nx": {"Changed", "faster parse"},
+		"Update the parser":       {"Changed", "Update the parser"},
+		"docs: typo":              {"", ""},
+	} {
+		if section, entry := ChangelogEntry(msg); section != want[0] || entry != want[1] {
+			t.Errorf("ChangelogEntry(%q) = %q, %q", msg, section, entry)
+		}
+	}
+}
+
+func TestUnreleasedSection(t *testing.T) {
+	got := AddUnreleasedEntry(testChangelog, "Fixed", "handle empty input")
+	got = AddUnreleasedEntry(got, "Fixed", "handle empty input")
+	got = AddUnreleasedEntry(got, "Added", "retries")
+	want := "## [Unreleased]\n\n- Work in progress\n\n### Fixed\n- handle empty input\n\n### Added\n- retries\n\n## [1.2.0]"
+	if !strings.Contains(got, want) {
+		t.Errorf("unexpected changelog:\n%s", got)
+	}
+
+	// A new document gets the header and the section
+	fresh := AddUnreleasedEntry("", "Added", "first")
+	if !strings.HasPrefix(fresh, "# Changelog\n") || !strings.HasSuffix(fresh, "## [Unreleased]\n\n### Added\n- first\n") {
+		t.Errorf("unexpected new changelog:\n%s", fresh)
+	}
+	// Sections are added above existing versions
+	noUnreleased := "# Changelog\n\n## [1.0.0] - 2024-01-01\n\n- initial\n"
+	if got := AddUnreleasedEntry(noUnreleased, "Fixed", "x"); !strings.Contains(got, "## [Unreleased]\n\n### Fixed\n- x\n\n## [1.0.0]") {
+		t.Errorf("unexpected changelog:\n%s", got)
+	}
+
+	linked := "# Changelog\n\n## [Unreleased]\n\n### Added\n- x\n\n[Unreleased]: https://github.com/o/r/compare/v1.2.0...HEAD\n"
+	rolled, ok := ReleaseUnreleased(linked, "v1.3.0", "2026-10-14")
+	wantRolled := "## [Unreleased]\n\n## [1.3.0] - 2026-10-14\n\n### Added\n- x\n\n" +
+		"[Unreleased]: https://github.com/o/r/compare/v1.3.0...HEAD\n[1.3.0]: https://github.com/o/r/compare/v1.2.0...v1.3.0\n"
+	if !ok || !strings.HasSuffix(rolled, wantRolled) {
+		t.Errorf("unexpected release:\n%s", rolled)
+	}
+	if ChangelogSection(rolled, "v1.3.0") != "### Added\n- x" {
+		t.Errorf("release section not found:\n%s", rolled)
+	}
+	if _, ok := ReleaseUnreleased(rolled, "v1.3.1", "2026-10-14"); ok {
+		t.Error("empty Unreleased section should not be released")
+	}
+}
+
+func TestPushMaintainsChangelog(t *testing.T) {
+	defer testPushedRepo(t)()
+	os.WriteFile(".devflow.yaml", []byte("changelog:\n  unreleased: true\n"), 0644)
+	git, _ := NewGit()
+
+	git.SetPushOptions(PushOptions{NoTag: true})
+	if _, err := git.Push("feat: add config", ""); err != nil {
+		t.Fatal(err)
+	}
+	if latest, _ := git.GetLatestTag(); latest != "v0.0.1" {
+		t.Errorf("push without tag created %s", latest)
+	}
+	data, _ := os.ReadFile(ChangelogFile)
+	if ChangelogSection(string(data), "Unreleased") != "### Added\n- add config" {
+		t.Errorf("unexpected changelog:\n%s", data)
+	}
+
+	os.WriteFile("fix.go", []byte("package fix\n"), 0644)
+	git.SetPushOptions(PushOptions{})
+	summary, err := git.Push("fix: handle empty input", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(summary, "CHANGELOG.md: v0.1.0") {
+		t.Errorf("summary = %q", summary)
+	}
+	data, _ = os.ReadFile(ChangelogFile)
+	if got := ChangelogSection(string(data), "v0.1.0"); got != "### Added\n- add config\n\n### Fixed\n- handle empty input" {
+		t.Errorf("v0.1.0 section = %q\n%s", got, data)
+	}
+	// The rolled section is in the release commit and the tag message
+	if status, _ := RunCommandSilent("git", "status", "--porcelain"); status != "" {
+		t.Errorf("uncommitted changes after push: %s", status)
+	}
+	if msg, _ := RunCommandSilent("git", "tag", "-l", "--format=%(contents)", "v0.1.0"); !strings.Contains(msg, "- handle empty input") {
+		t.Errorf("tag message = %q", msg)
+	}
+}
@@ -0,0 +1,102 @@
+package devflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CIWorkflowPath is the GitHub Actions workflow written by GenerateCIWorkflow
+var CIWorkflowPath = filepath.Join(".github", "workflows", "ci.yml")
+
+// CIWorkflowOptions configures GenerateCIWorkflow
+type CIWorkflowOptions struct {
+	GoVersions []string // Go version matrix (default: the go directive of go.mod and stable)
+	Wasm       bool     // Add a job running the tests under GOOS=js GOARCH=wasm with wasmbrowsertest
+}
+
+// GenerateCIWorkflow writes .github/workflows/ci.yml to targetDir. It runs
+// the gotest pipeline on pushes and pull requests: go vet, the race tests
+// with coverage and the coverage total, for every Go version of the matrix.
+func GenerateCIWorkflow(targetDir string, opts CIWorkflowOptions) error {
+	versions := opts.GoVersions
+	if len(versions) == 0 {
+		versions = defaultCIGoVersions(targetDir)
+	}
+	quoted := make([]string, len(versions))
+	for i, v := range versions {
+		quoted[i] = "'" + v + "'"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `name: CI
+
+on:
+  push:
+    branches: ['**']
+    tags-ignore: ['**']
+  pull_request:
+
+jobs:
+  test:
+    name: Go ${{ matrix.go }}
+    runs-on: ubuntu-latest
+    strategy:
+      fail-fast: false
+      matrix:
+        go: [%s]
+    steps:
+      - uses: actions/checkout@v4
+      - uses: actions/setup-go@v5
+        with:
+          go-version: ${{ matrix.go }}
+      - name: Vet
+        run: go vet ./...
+      - name: Test
+        run: go test -race -count=1 -coverprofile=coverage.out ./...
+      - name: Coverage
+        run: go tool cover -func=coverage.out | tail -n 1
+`, strings.Join(quoted, ", "))
+
+	if opts.Wasm {
+		b.WriteString(`
+  wasm:
+    name: WASM
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      - uses: actions/setup-go@v5
+        with:
+          go-version-file: go.mod
+      - name: Install wasmbrowsertest
+        run: go install github.com/tinywasm/wasmbrowsertest@latest
+      - name: Test
+        run: go test -exec wasmbrowsertest -cover ./...
+        env:
+          GOOS: js
+          GOARCH: wasm
+`)
+	}
+
+	path := filepath.Join(targetDir, CIWorkflowPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return writeGenerated(path, []byte(b.String()))
+}
+
+// defaultCIGoVersions returns the go directive of targetDir/go.mod (the
+// oldest version the module supports) and stable
+func defaultCIGoVersions(targetDir string) []string {
+	data, err := os.ReadFile(filepath.Join(targetDir, "go.mod"))
+	if err != nil {
+		return []string{"stable"}
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if v, ok := strings.CutPrefix(strings.TrimSpace(line), "go "); ok && strings.TrimSpace(v) != "" {
+			return []string{strings.TrimSpace(v), "stable"}
+		}
+	}
+	return []string{"stable"}
+}
@@ -0,0 +1,85 @@
+package devflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateCIWorkflow(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/lib\n\ngo 1.22.4\n"), 0644)
+
+	if err := GenerateCIWorkflow(dir, CIWorkflowOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, ".github", "workflows", "ci.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	for _, want := range []string{"go: ['1.22.4', 'stable']", "run: go vet ./...", "go test -race -count=1 -coverprofile=coverage.out ./...", "go tool cover -func=coverage.out"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("workflow missing %q:\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "wasm") {
+		t.Error("the wasm job should be opt-in")
+	}
+
+	if err := GenerateCIWorkflow(dir, CIWorkflowOptions{GoVersions: []string{"1.23", "1.24"}, Wasm: true}); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(filepath.Join(dir, CIWorkflowPath))
+	content = string(data)
+	if !strings.Contains(content, "go: ['1.23', '1.24']") || !strings.Contains(content, "go test -exec wasmbrowsertest -cover ./...") || !strings.Contains(content, "GOOS: js") {
+		t.Errorf("expected the custom matrix and wasm job:\n%s", content)
+	}
+
+	if got := defaultCIGoVersions(t.TempDir()); len(got) != 1 || got[0] != "stable" {
+		t.Errorf("without go.mod expected [stable], got %v", got)
+	}
+}
+
+func TestGoNewCIWorkflow(t *testing.T) {
+	tmp := testResumeEnv(t)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmp, ".config"))
+	git, err := NewGit()
+	if err != nil {
+		t.Skip("git not installed")
+	}
+	goHandler, _ := NewGo(git)
+	gn := NewGoNew(git, nil, goHandler)
+
+	dir := filepath.Join(tmp, "ci-lib")
+	if _, err := gn.Create(NewProjectOptions{Name: "ci-lib", Description: "CI", LocalOnly: true, Directory: dir, CI: true}); err != nil {
+		t.Fatal(err)
+	}
+	if files, _ := RunCommandInDir(dir, "git", "ls-files"); !strings.Contains(files, filepath.ToSlash(CIWorkflowPath)) {
+		t.Errorf("expected the workflow in the initial commit, got %q", files)
+	}
+	m, _ := ReadModule(dir)
+	if data, _ := os.ReadFile(filepath.Join(dir, CIWorkflowPath)); !strings.Contains(string(data), "go: ['"+m.GoVersion+"', 'stable']") {
+		t.Errorf("expected the matrix to start at the go directive %s:\n%s", m.GoVersion, data)
+	}
+
+	// Off by default, on with gonew.ci
+	dir = filepath.Join(tmp, "plain-lib")
+	if _, err := gn.Create(NewProjectOptions{Name: "plain-lib", Description: "Plain", LocalOnly: true, Directory: dir}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, CIWorkflowPath)); !os.IsNotExist(err) {
+		t.Error("the workflow should be opt-in")
+	}
+	global := filepath.Join(tmp, ".config", GlobalConfigFile)
+	os.MkdirAll(filepath.Dir(global), 0755)
+	os.WriteFile(global, []byte("gonew:\n  ci: true\n"), 0644)
+	dir = filepath.Join(tmp, "config-lib")
+	if _, err := gn.Create(NewProjectOptions{Name: "config-lib", Description: "Config", LocalOnly: true, Directory: dir}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, CIWorkflowPath)); err != nil {
+		t.Errorf("expected the workflow from gonew.ci: %v", err)
+	}
+}
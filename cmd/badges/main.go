@@ -16,6 +16,8 @@ func main() {
 	coveragePercent := flag.String("coverage", "85", "Coverage percentage")
 	raceStatus := flag.String("race-status", "Clean", "Race status")
 	vetStatus := flag.String("vet-status", "OK", "Vet status")
+	vulnStatus := flag.String("vulns", "", "Vulnerability status: none, \"N imported\" or \"N called\" (empty: no badge)")
+	lintStatus := flag.String("lint", "", "Lint status: OK or \"N issues\" (empty: no badge)")
 	licenseType := flag.String("license", "MIT", "License type")
 	readmeFile := flag.String("readme", "README.md", "Readme file")
 
@@ -60,6 +62,12 @@ func main() {
 		fmt.Sprintf("Race:%s:%s", *raceStatus, raceColor),
 		fmt.Sprintf("Vet:%s:%s", *vetStatus, vetColor),
 	}
+	if *vulnStatus != "" {
+		badgeArgs = append(badgeArgs, fmt.Sprintf("Vulns:%s:%s", *vulnStatus, getBadgeColor("vulns", *vulnStatus)))
+	}
+	if *lintStatus != "" {
+		badgeArgs = append(badgeArgs, fmt.Sprintf("Lint:%s:%s", *lintStatus, getBadgeColor("lint", *lintStatus)))
+	}
 
 	// Create badge handler and build badges
 	handler := devflow.NewBadges(badgeArgs...)
@@ -103,11 +111,19 @@ func getBadgeColor(badgeType, value string) string {
 			return "#4c1"
 		}
 		return "#e05d44"
-	case "vet":
+	case "vet", "lint":
 		if value == "OK" {
 			return "#4c1"
 		}
 		return "#e05d44"
+	case "vulns":
+		if value == "none" {
+			return "#4c1"
+		}
+		if strings.HasSuffix(value, "imported") {
+			return "#dfb317"
+		}
+		return "#e05d44"
 	default:
 		return "#007acc"
 	}
@@ -8,6 +8,9 @@ import (
 	"github.com/tinywasm/devflow"
 )
 
+// stdout and stderr follow the accessible output mode (output.accessible)
+var stdout, stderr = devflow.OutputWriter(os.Stdout), devflow.OutputWriter(os.Stderr)
+
 func main() {
 	fs := flag.NewFlagSet("devbackup", flag.ExitOnError)
 	setCmd := fs.String("s", "", "Set backup command")
@@ -16,14 +19,15 @@ func main() {
 	fs.Parse(os.Args[1:])
 
 	backup := devflow.NewDevBackup()
+	backup.SetLogger(devflow.NewLoggerFromConfig(os.Stderr))
 
 	// Handle -s flag (set command)
 	if *setCmd != "" {
 		if err := backup.SetCommand(*setCmd); err != nil {
-			fmt.Fprintf(os.Stderr, "Error setting backup command: %v\n", err)
+			fmt.Fprintf(stderr, "Error setting backup command: %v\n", err)
 			os.Exit(1)
 		}
-		fmt.Println("✅ Backup command saved to ~/.bashrc")
+		fmt.Fprintln(stdout, "✅ Backup command saved to ~/.bashrc")
 		return
 	}
 
@@ -31,20 +35,20 @@ func main() {
 	if *getCmd {
 		command, err := backup.GetCommand()
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "No backup command configured\n")
+			fmt.Fprintf(stderr, "No backup command configured\n")
 			os.Exit(1)
 		}
-		fmt.Println(command)
+		fmt.Fprintln(stdout, command)
 		return
 	}
 
 	// Default: execute backup
 	msg, err := backup.Run()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error executing backup: %v\n", err)
+		fmt.Fprintf(stderr, "Error executing backup: %v\n", err)
 		os.Exit(1)
 	}
 	if msg != "" {
-		fmt.Println(msg)
+		fmt.Fprintln(stdout, msg)
 	}
 }
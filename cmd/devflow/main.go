@@ -0,0 +1,761 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tinywasm/devflow"
+)
+
+// stdout and stderr follow the accessible output mode (output.accessible)
+var stdout, stderr = devflow.OutputWriter(os.Stdout), devflow.OutputWriter(os.Stderr)
+
+func usage() {
+	fmt.Fprintf(stderr, `devflow - Manage devflow settings, local metrics, project archives, SSH keys, dotfiles, machine bootstrap and repository maintenance, and show a project dashboard
+
+Usage:
+    devflow config lint [dir]      Validate the config files of a project
+    devflow config init [flags] [dir]
+                                   Write a commented default .devflow.yaml
+    devflow config schema          Print the JSON Schema of the config
+    devflow metrics [-since=30d] [-clear]
+                                   Show where workflow time goes (opt-in)
+    devflow export [-o=file] [-no-metadata] [dir]
+                                   Archive the repository, releases, issues and PRs
+    devflow import [-remote=url] <archive> [dir]
+                                   Restore an archive, optionally pushing it to a new remote
+    devflow keys setup [flags]     Create, upload and configure GitHub SSH keys
+    devflow git maintain [flags] [dir|alias...]
+                                   Compact repositories and schedule their maintenance
+    devflow dotfiles init [flags] [remote]
+                                   Create or clone the dotfiles repository
+    devflow dotfiles track [flags] <path>...
+                                   Move files of the home directory into it
+    devflow dotfiles sync [flags]  Commit, pull, place and push the tracked files
+    devflow bootstrap [-dry-run] <manifest>
+                                   Set up this machine from a bootstrap manifest
+    devflow bootstrap export [-o=file]
+                                   Write the manifest of this machine
+    devflow dashboard [flags] [dir|alias]
+                                   Show the project status and run Test, Push or Backup
+    devflow release [flags] [dir|alias]
+                                   Release if unreleased commits wait and none was made this period
+
+Init flags:
+    -global    Write the global config instead (%s)
+    -force     Overwrite an existing file
+    -dry-run   Print the file write without doing it
+
+Metrics flags:
+    -since     Only runs in this period, e.g. 7d or 12h (default: all)
+    -clear     Delete the recorded metrics
+
+Export and import flags:
+    -o             Archive to write (default: <name>-export-<date>.tar.gz)
+    -no-metadata   Only the git bundle and config, skip the GitHub API
+    -remote        Set origin to this URL and push all branches and tags
+
+Keys flags:
+    -key        Private key to use or create (default ~/.ssh/id_ed25519)
+    -signing    Also set up an SSH commit signing key (<key>_signing)
+    -title      Title of the uploaded keys (default devflow@<hostname>)
+    -verify     Repository to check with git ls-remote (default: origin)
+    -dry-run    Print the commands and file updates without running them
+
+Maintain flags:
+    -all          Every project alias of the global config (projects.*)
+    -schedule     Also register the repositories with git maintenance start
+    -aggressive   git gc --aggressive (slower, smaller)
+    -dry-run      Print the commands without running them
+
+Dotfiles flags:
+    -dir        Dotfiles repository (default: dotfiles.dir or ~/.dotfiles)
+    -strategy   symlink or copy (default: dotfiles.strategy or symlink)
+
+Bootstrap flags:
+    -o          Manifest to write (default: %s, - for stdout)
+    -dry-run    Print the commands and file updates without running them
+
+Dashboard flags:
+    -search-path   Directory searched for dependent modules (default: ..)
+    -once          Print the status once, without the interactive view
+
+Release flags:
+    -schedule          daily, weekly or monthly (default: release.schedule, weekly)
+    -skip-deps-update  Do not update the modules that depend on this one
+    -search-path       Directory searched for dependent modules (default: ..)
+    -no-release        Do not create a GitHub release for the new tag
+    -dry-run           Print the commands and file updates without running them
+
+Examples:
+    devflow config lint
+    devflow config init
+    devflow config init -global
+    devflow config schema > devflow.schema.json
+    devflow metrics -since=7d
+    devflow export ~/Dev/my-lib
+    devflow import -remote=git@gitlab.com:me/my-lib.git my-lib-export-20261014.tar.gz
+    devflow keys setup -signing
+    devflow git maintain
+    devflow git maintain -all -schedule
+    devflow dotfiles init git@github.com:me/dotfiles.git
+    devflow dotfiles track ~/.bashrc ~/.config/nvim
+    devflow dotfiles sync
+    devflow bootstrap export -o ~/devflow-bootstrap.yaml
+    devflow bootstrap devflow-bootstrap.yaml
+    devflow dashboard
+    devflow dashboard -once api
+    devflow release -schedule=weekly
+`, devflow.GlobalConfigFile, devflow.BootstrapManifestFile)
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "metrics":
+			runMetrics(os.Args[2:])
+			return
+		case "export":
+			defer devflow.HandleInterrupts()()
+			runExport(os.Args[2:])
+			return
+		case "import":
+			defer devflow.HandleInterrupts()()
+			runImport(os.Args[2:])
+			return
+		case "keys":
+			if len(os.Args) < 3 || os.Args[2] != "setup" {
+				usage()
+				os.Exit(2)
+			}
+			runKeysSetup(os.Args[3:])
+			return
+		case "git":
+			if len(os.Args) < 3 || os.Args[2] != "maintain" {
+				usage()
+				os.Exit(2)
+			}
+			defer devflow.HandleInterrupts()()
+			runMaintain(os.Args[3:])
+			return
+		case "dotfiles":
+			if len(os.Args) < 3 {
+				usage()
+				os.Exit(2)
+			}
+			defer devflow.HandleInterrupts()()
+			runDotfiles(os.Args[2], os.Args[3:])
+			return
+		case "bootstrap":
+			defer devflow.HandleInterrupts()()
+			if len(os.Args) > 2 && os.Args[2] == "export" {
+				runBootstrapExport(os.Args[3:])
+			} else {
+				runBootstrap(os.Args[2:])
+			}
+			return
+		case "dashboard":
+			runDashboard(os.Args[2:])
+			return
+		case "release":
+			defer devflow.HandleInterrupts()()
+			runRelease(os.Args[2:])
+			return
+		}
+	}
+	if len(os.Args) < 3 || os.Args[1] != "config" {
+		usage()
+		os.Exit(2)
+	}
+
+	switch os.Args[2] {
+	case "lint":
+		runLint(os.Args[3:])
+	case "init":
+		runInit(os.Args[3:])
+	case "schema":
+		data, err := devflow.ConfigJSONSchema()
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Stdout.Write(data)
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
+
+func runLint(args []string) {
+	fs := flag.NewFlagSet("config lint", flag.ExitOnError)
+	fs.Usage = usage
+	fs.Parse(args)
+	dir := "."
+	if fs.NArg() > 0 {
+		dir = fs.Arg(0)
+	}
+
+	files, issues, err := devflow.LintConfigDir(dir)
+	if err != nil {
+		fmt.Fprintln(stdout, "❌", err)
+		os.Exit(1)
+	}
+	for _, issue := range issues {
+		fmt.Fprintln(stdout, issue)
+	}
+	switch {
+	case len(issues) > 0:
+		fmt.Fprintf(stdout, "❌ %d issue(s) in %d config file(s)\n", len(issues), len(files))
+		os.Exit(1)
+	case len(files) == 0:
+		fmt.Fprintln(stdout, "✅ no config files (defaults apply)")
+	default:
+		fmt.Fprintf(stdout, "✅ %d config file(s) ok\n", len(files))
+	}
+}
+
+func runInit(args []string) {
+	fs := flag.NewFlagSet("config init", flag.ExitOnError)
+	global := fs.Bool("global", false, "Write the global config")
+	force := fs.Bool("force", false, "Overwrite an existing file")
+	dryRun := fs.Bool("dry-run", false, "Print the file write without doing it")
+	fs.Usage = usage
+	fs.Parse(args)
+	dir := "."
+	if fs.NArg() > 0 {
+		dir = fs.Arg(0)
+	}
+
+	path, err := devflow.InitConfig(dir, devflow.ConfigInitOptions{Global: *global, Force: *force, DryRun: *dryRun})
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if !*dryRun {
+		fmt.Fprintln(stdout, "✅ wrote", path)
+	}
+}
+
+func runMetrics(args []string) {
+	fs := flag.NewFlagSet("metrics", flag.ExitOnError)
+	since := fs.String("since", "", "Only runs in this period, e.g. 7d or 12h")
+	clear := fs.Bool("clear", false, "Delete the recorded metrics")
+	fs.Usage = usage
+	fs.Parse(args)
+
+	if *clear {
+		if err := devflow.ClearMetrics(); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintln(stdout, "✅ metrics cleared")
+		return
+	}
+
+	var from time.Time
+	if *since != "" {
+		d, err := parsePeriod(*since)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: invalid -since %q\n", *since)
+			os.Exit(2)
+		}
+		from = time.Now().Add(-d)
+	}
+	events, err := devflow.LoadMetrics(from)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintln(stdout, devflow.FormatMetrics(events))
+	if !devflow.MetricsEnabled() {
+		fmt.Fprintln(stdout, "Metrics are off; set metrics.enabled: true in the global config or DEVFLOW_METRICS=1 to record runs.")
+	}
+}
+
+func runExport(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	output := fs.String("o", "", "Archive to write")
+	noMetadata := fs.Bool("no-metadata", false, "Skip issues, pull requests and releases")
+	fs.Usage = usage
+	fs.Parse(args)
+	dir := "."
+	if fs.NArg() > 0 {
+		dir = fs.Arg(0)
+	}
+	if *output == "" {
+		abs, _ := filepath.Abs(dir)
+		*output = devflow.ExportArchiveName(filepath.Base(abs), time.Now())
+	}
+
+	if _, err := devflow.StartAirGap(dir); err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	e := devflow.NewExporter()
+	e.SetLog(func(args ...any) { fmt.Fprintln(stdout, args...) })
+	e.SetMetadata(!*noMetadata)
+	m, err := e.Export(dir, *output)
+	if err != nil {
+		fmt.Fprintf(stderr, "❌ export failed: %v\n", err)
+		os.Exit(1)
+	}
+	for _, w := range m.Warnings {
+		fmt.Fprintln(stdout, "⚠️", w)
+	}
+	fmt.Fprintf(stdout, "✅ exported %s to %s\n", m.Summary(), *output)
+}
+
+func runImport(args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	remote := fs.String("remote", "", "Set origin to this URL and push all branches and tags")
+	fs.Usage = usage
+	fs.Parse(args)
+	if fs.NArg() < 1 {
+		usage()
+		os.Exit(2)
+	}
+
+	e := devflow.NewExporter()
+	e.SetLog(func(args ...any) { fmt.Fprintln(stdout, args...) })
+	m, err := e.Import(fs.Arg(0), fs.Arg(1), *remote)
+	if err != nil {
+		fmt.Fprintf(stderr, "❌ import failed: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(stdout, "✅ imported %s (metadata in .git/%s)\n", m.Summary(), devflow.ImportArchiveDir)
+}
+
+func runKeysSetup(args []string) {
+	fs := flag.NewFlagSet("keys setup", flag.ExitOnError)
+	key := fs.String("key", "", "Private key to use or create")
+	signing := fs.Bool("signing", false, "Also set up an SSH commit signing key")
+	title := fs.String("title", "", "Title of the uploaded keys")
+	verify := fs.String("verify", "", "Repository to check with git ls-remote")
+	dryRun := fs.Bool("dry-run", false, "Print commands without running them")
+	fs.Usage = usage
+	fs.Parse(args)
+
+	if _, err := devflow.StartAirGap("."); err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	k := devflow.NewKeySetup()
+	k.SetLog(func(args ...any) { fmt.Fprintln(stdout, args...) })
+	k.SetDryRun(*dryRun)
+	result, err := k.Setup(devflow.KeySetupOptions{Path: *key, Signing: *signing, Title: *title, VerifyURL: *verify})
+	if err != nil {
+		fmt.Fprintf(stderr, "❌ keys setup failed: %v\n", err)
+		os.Exit(1)
+	}
+	if !*dryRun {
+		fmt.Fprintln(stdout, result.Summary())
+	}
+}
+
+func runDotfiles(command string, args []string) {
+	fs := flag.NewFlagSet("dotfiles "+command, flag.ExitOnError)
+	dir := fs.String("dir", "", "Dotfiles repository")
+	strategy := fs.String("strategy", "", "symlink or copy")
+	fs.Usage = usage
+	fs.Parse(args)
+
+	d, err := devflow.NewDotfiles()
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	d.SetLog(func(args ...any) { fmt.Fprintln(stdout, args...) })
+	if *dir != "" {
+		d.SetDir(*dir)
+	}
+	if *strategy != "" {
+		if err := d.SetStrategy(*strategy); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			os.Exit(2)
+		}
+	}
+
+	var result string
+	switch command {
+	case "init":
+		if fs.NArg() > 1 {
+			usage()
+			os.Exit(2)
+		}
+		result, err = d.Init(fs.Arg(0))
+	case "track":
+		if fs.NArg() == 0 {
+			usage()
+			os.Exit(2)
+		}
+		result, err = d.Track(fs.Args()...)
+	case "sync":
+		result, err = d.Sync()
+	default:
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "❌ dotfiles %s failed: %v\n", command, err)
+		os.Exit(1)
+	}
+	fmt.Fprintln(stdout, result)
+}
+
+func runBootstrap(args []string) {
+	fs := flag.NewFlagSet("bootstrap", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "Print commands without running them")
+	fs.Usage = usage
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		usage()
+		os.Exit(2)
+	}
+
+	if _, err := devflow.StartAirGap("."); err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	m, err := devflow.LoadBootstrapManifest(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	b := devflow.NewBootstrap()
+	b.SetLog(func(args ...any) { fmt.Fprintln(stdout, args...) })
+	b.SetDryRun(*dryRun)
+	summary, err := b.Run(m)
+	for _, line := range summary {
+		fmt.Fprintln(stdout, line)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "❌ bootstrap incomplete, fix and re-run: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runBootstrapExport(args []string) {
+	fs := flag.NewFlagSet("bootstrap export", flag.ExitOnError)
+	out := fs.String("o", devflow.BootstrapManifestFile, "Manifest to write")
+	fs.Usage = usage
+	fs.Parse(args)
+
+	m, err := devflow.ExportBootstrapManifest()
+	if err != nil {
+		fmt.Fprintf(stderr, "❌ bootstrap export failed: %v\n", err)
+		os.Exit(1)
+	}
+	if *out == "-" {
+		os.Stdout.Write(m.Marshal())
+		return
+	}
+	if err := os.WriteFile(*out, m.Marshal(), 0644); err != nil {
+		fmt.Fprintf(stderr, "❌ bootstrap export failed: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(stdout, "✅ wrote %s: %d tools, %d repositories\n", *out, len(m.Tools), len(m.Repos))
+}
+
+func runMaintain(args []string) {
+	fs := flag.NewFlagSet("git maintain", flag.ExitOnError)
+	all := fs.Bool("all", false, "Every project alias of the global config")
+	schedule := fs.Bool("schedule", false, "Also register the repositories with git maintenance start")
+	aggressive := fs.Bool("aggressive", false, "git gc --aggressive")
+	dryRun := fs.Bool("dry-run", false, "Print commands without running them")
+	fs.Usage = usage
+	fs.Parse(args)
+
+	targets := fs.Args()
+	if *all {
+		global, err := devflow.LoadGlobalConfig()
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		aliases := devflow.ProjectAliases(global)
+		if len(aliases) == 0 {
+			fmt.Fprintf(stderr, "Error: -all: no projects in %s\n", devflow.GlobalConfigFile)
+			os.Exit(1)
+		}
+		for alias := range aliases {
+			targets = append(targets, alias)
+		}
+		sort.Strings(targets)
+	}
+	if len(targets) == 0 {
+		targets = []string{"."}
+	}
+
+	git, err := devflow.NewGit()
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	git.SetDryRun(*dryRun)
+	failed := 0
+	var reclaimed int64
+	for _, target := range targets {
+		dir, err := devflow.ResolveProject(target)
+		if err == nil {
+			var result devflow.MaintainResult
+			result, err = git.Maintain(dir, devflow.MaintainOptions{Aggressive: *aggressive, Schedule: *schedule})
+			if err == nil && !*dryRun {
+				fmt.Fprintln(stdout, result)
+				reclaimed += result.Reclaimed()
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "❌ %s: %v\n", target, err)
+			failed++
+		}
+	}
+	if len(targets) > 1 && !*dryRun {
+		fmt.Fprintf(stdout, "%d of %d repositories maintained, %s reclaimed\n", len(targets)-failed, len(targets), devflow.FormatBytes(reclaimed))
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func runDashboard(args []string) {
+	fs := flag.NewFlagSet("dashboard", flag.ExitOnError)
+	searchPath := fs.String("search-path", "..", "Directory searched for dependent modules")
+	once := fs.Bool("once", false, "Print the status once")
+	fs.Usage = usage
+	fs.Parse(args)
+
+	if fs.NArg() > 0 {
+		dir, err := devflow.ResolveProject(fs.Arg(0))
+		if err == nil {
+			err = os.Chdir(dir)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	git, err := devflow.NewGit()
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	goHandler, err := devflow.NewGo(git)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	dash := devflow.NewDashboard(git, goHandler)
+	dash.SetSearchPath(*searchPath)
+
+	if *once || !isTerminal(os.Stdin) || !isTerminal(os.Stdout) {
+		status, err := dash.Status()
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintln(stdout, strings.Join(status.Render(), "\n"))
+		return
+	}
+	defer devflow.HandleInterrupts()()
+	if err := dashboardLoop(dash); err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// runRelease handles "devflow release": a scheduled release when one is due
+func runRelease(args []string) {
+	fs := flag.NewFlagSet("release", flag.ExitOnError)
+	schedule := fs.String("schedule", "", "Release schedule: daily, weekly or monthly")
+	skipDeps := fs.Bool("skip-deps-update", false, "Do not update dependent modules")
+	searchPath := fs.String("search-path", "..", "Directory searched for dependent modules")
+	noRelease := fs.Bool("no-release", false, "Do not create a GitHub release")
+	dryRun := fs.Bool("dry-run", false, "Print commands without running them")
+	fs.Usage = usage
+	fs.Parse(args)
+
+	var err error
+	if fs.NArg() > 0 {
+		var dir string
+		if dir, err = devflow.ResolveProject(fs.Arg(0)); err == nil {
+			err = os.Chdir(dir)
+		}
+	}
+	if err == nil {
+		err = devflow.ChdirRoot()
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	git, err := devflow.NewGit()
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	goHandler, err := devflow.NewGo(git)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	goHandler.SetDryRun(*dryRun)
+	goHandler.SetRelease(!*noRelease)
+
+	summary, err := goHandler.ScheduledRelease(*schedule, *skipDeps, *searchPath)
+	if err != nil {
+		fmt.Fprintln(stdout, "Release failed:", err)
+		devflow.WriteWarnings(stdout, goHandler.Warnings())
+		os.Exit(1)
+	}
+	fmt.Fprintln(stdout, summary)
+	devflow.WriteWarnings(stdout, goHandler.Warnings())
+}
+
+// dashboardLoop draws the dashboard and runs the chosen actions until q.
+// Keys are read unbuffered (stty -icanon); an action runs with the
+// terminal restored so its output and prompts work as in the other tools.
+func dashboardLoop(dash *devflow.Dashboard) error {
+	restore, err := rawTerminal()
+	if err != nil {
+		return err
+	}
+	defer func() { restore() }()
+	remove := devflow.OnInterrupt(func() { restore() })
+	defer remove()
+
+	selected, result := 0, ""
+	status, statusErr := dash.Status()
+	buf := make([]byte, 8)
+	for {
+		fmt.Fprint(os.Stdout, "\x1b[H\x1b[2J")
+		if statusErr != nil {
+			fmt.Fprintln(stdout, "❌", statusErr)
+		} else {
+			fmt.Fprintln(stdout, strings.Join(status.Render(), "\n"))
+		}
+		fmt.Fprintln(stdout)
+		for i, a := range devflow.DashboardActions {
+			label := fmt.Sprintf("[%c] %s", a.Key, a.Label)
+			if i == selected {
+				label = "\x1b[7m" + label + "\x1b[0m"
+			}
+			fmt.Fprint(stdout, label, "  ")
+		}
+		fmt.Fprintln(stdout)
+		if result != "" {
+			fmt.Fprintln(stdout, "\n"+result)
+		}
+
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			return err
+		}
+		key := rune(buf[0])
+		switch {
+		case n == 3 && buf[0] == 0x1b && (buf[2] == 'C' || buf[2] == 'B'):
+			selected = (selected + 1) % len(devflow.DashboardActions)
+			continue
+		case n == 3 && buf[0] == 0x1b && (buf[2] == 'D' || buf[2] == 'A'):
+			selected = (selected + len(devflow.DashboardActions) - 1) % len(devflow.DashboardActions)
+			continue
+		case key == '\r' || key == '\n':
+			key = devflow.DashboardActions[selected].Key
+		}
+
+		switch key {
+		case 'q', 0x1b:
+			fmt.Fprint(os.Stdout, "\x1b[H\x1b[2J")
+			return nil
+		case 'r':
+			status, statusErr = dash.Status()
+			result = ""
+			continue
+		case 't', 'p', 'b':
+		default:
+			continue
+		}
+
+		restore()
+		fmt.Fprint(os.Stdout, "\x1b[H\x1b[2J")
+		result = runDashboardAction(dash, key)
+		// On failure the terminal is already restored; the deferred
+		// restore then sets the same mode again
+		next, err := rawTerminal()
+		if err != nil {
+			return err
+		}
+		restore = next
+		status, statusErr = dash.Status()
+	}
+}
+
+// runDashboardAction runs an action with the terminal in its normal mode
+// and returns the line shown under the dashboard
+func runDashboardAction(dash *devflow.Dashboard, key rune) string {
+	in := bufio.NewReader(os.Stdin)
+	message := ""
+	if key == 'p' {
+		fmt.Fprint(stdout, "Commit message: ")
+		line, _ := in.ReadString('\n')
+		if message = strings.TrimSpace(line); message == "" {
+			return "Push cancelled: no commit message"
+		}
+	}
+	summary, err := dash.Run(key, message)
+	if err != nil {
+		summary = "❌ " + err.Error()
+	}
+	fmt.Fprintln(stdout, "\n"+summary+"\n\nPress Enter to return to the dashboard")
+	in.ReadString('\n')
+	return summary
+}
+
+// rawTerminal switches the terminal to unbuffered input without echo and
+// returns the function that restores its previous mode
+func rawTerminal() (func(), error) {
+	stty := func(args ...string) (string, error) {
+		cmd := exec.Command("stty", args...)
+		cmd.Stdin = os.Stdin
+		out, err := cmd.Output()
+		return strings.TrimSpace(string(out)), err
+	}
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, fmt.Errorf("the dashboard needs a terminal with stty (use -once): %w", err)
+	}
+	if _, err := stty("-icanon", "-echo", "min", "1"); err != nil {
+		return nil, err
+	}
+	fmt.Fprint(os.Stdout, "\x1b[?25l")
+	return func() {
+		stty(saved)
+		fmt.Fprint(os.Stdout, "\x1b[?25h")
+	}, nil
+}
+
+// isTerminal reports whether f is a terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// parsePeriod parses a duration that may also be given in days ("30d")
+func parsePeriod(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid period %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
@@ -4,15 +4,36 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/tinywasm/devflow"
 )
 
+// stdout and stderr follow the accessible output mode (output.accessible)
+var stdout, stderr = devflow.OutputWriter(os.Stdout), devflow.OutputWriter(os.Stderr)
+
 func main() {
+	defer devflow.HandleInterrupts()()
+
 	// Subcommands
 	addRemoteCmd := flag.NewFlagSet("add-remote", flag.ExitOnError)
 	addRemoteOwner := addRemoteCmd.String("owner", "", "GitHub owner/organization (default: auto-detected)")
-	addRemoteVisibility := addRemoteCmd.String("visibility", "public", "Visibility (public/private)")
+	addRemoteVisibility := addRemoteCmd.String("visibility", "", "Visibility (public/private, default: gonew.visibility config, else public)")
+	addRemoteProvider := addRemoteCmd.String("provider", "", "Repository provider: github, gitlab or gitea (default: provider.name config)")
+	addRemoteHost := addRemoteCmd.String("host", "", "Provider host for self-hosted GitLab/Gitea (default: provider.host config)")
+	addRemoteDryRun := addRemoteCmd.Bool("dry-run", false, "Print the commands without running them")
+
+	resumeCmd := flag.NewFlagSet("resume", flag.ExitOnError)
+	resumeProvider := resumeCmd.String("provider", "", "Repository provider: github, gitlab or gitea (default: provider.name config)")
+	resumeHost := resumeCmd.String("host", "", "Provider host for self-hosted GitLab/Gitea (default: provider.host config)")
+	resumeDiscard := resumeCmd.Bool("discard", false, "Forget the saved progress instead of resuming")
+	resumeDryRun := resumeCmd.Bool("dry-run", false, "Print the remaining steps without running them")
+	resumeRollback := resumeCmd.String("rollback", "", "On failure undo: off, local (directory and progress) or remote (also the created repository)")
+
+	workspaceCmd := flag.NewFlagSet("workspace", flag.ExitOnError)
+	workspaceDryRun := workspaceCmd.Bool("dry-run", false, "Print the commands and writes without running them")
 
 	// Main command flags
 	// We handle main flags manually or via a FlagSet for the root command if no subcommand provided
@@ -22,37 +43,173 @@ func main() {
 		switch os.Args[1] {
 		case "add-remote":
 			addRemoteCmd.Parse(os.Args[2:])
-			handleAddRemote(addRemoteCmd.Args(), *addRemoteVisibility, *addRemoteOwner)
+			handleAddRemote(addRemoteCmd.Args(), *addRemoteVisibility, *addRemoteOwner, *addRemoteProvider, *addRemoteHost, *addRemoteDryRun)
+			return
+		case "resume":
+			resumeCmd.Parse(os.Args[2:])
+			handleResume(resumeCmd.Args(), *resumeProvider, *resumeHost, *resumeDiscard, *resumeDryRun, *resumeRollback)
+			return
+		case "templates":
+			handleTemplates(os.Args[2:])
+			return
+		case "regenerate":
+			handleRegenerate(os.Args[2:])
+			return
+		case "workspace":
+			workspaceCmd.Parse(os.Args[2:])
+			handleWorkspace(workspaceCmd.Args(), *workspaceDryRun)
 			return
 		}
 	}
 
 	// Main command handling (gonew <repo-name> <description>)
 	fs := flag.NewFlagSet("gonew", flag.ExitOnError)
-	ownerFlag := fs.String("owner", "", "GitHub owner/organization (default: auto-detected from gh or git)")
-	visibilityFlag := fs.String("visibility", "public", "Visibility (public/private)")
+	ownerFlag := fs.String("owner", "", "Owner/organization (default: auto-detected from the provider or git)")
+	providerFlag := fs.String("provider", "", "Repository provider: github, gitlab or gitea (default: provider.name config)")
+	hostFlag := fs.String("host", "", "Provider host for self-hosted GitLab/Gitea (default: provider.host config)")
+	visibilityFlag := fs.String("visibility", "", "Visibility (public/private, default: gonew.visibility config, else public)")
+	protocolFlag := fs.String("protocol", "", "Protocol of the origin URL: https, ssh or auto (default: gonew.remote_protocol config, else auto)")
 	localOnlyFlag := fs.Bool("local-only", false, "Skip remote creation entirely")
-	licenseFlag := fs.String("license", "MIT", "License type (default: MIT)")
+	licenseFlag := fs.String("license", "", "SPDX license: MIT, Apache-2.0, BSD-3-Clause, GPL-3.0, MPL-2.0 or Unlicense (default: gonew.license config, else MIT)")
+	branchFlag := fs.String("branch", "", "Initial branch (default: gonew.default_branch, init.defaultBranch or main)")
+	modulePrefixFlag := fs.String("module-prefix", "", "Module path prefix, e.g. go.example.com/libs (default: gonew.module_prefix config, else <host>/<owner>)")
+	typeFlag := fs.String("type", devflow.ProjectLibrary, "Project type: library, cli, wasm or web")
+	templateFlag := fs.String("template", "", "Template directory (with optional template.yml) or installed owner/repo template")
+	trustFlag := fs.Bool("trust", false, "Run the hooks of an installed template without asking")
+	seedFlag := fs.String("seed", "", "Existing code directory copied into the project")
+	ciFlag := fs.Bool("ci", false, "Write a GitHub Actions workflow running vet, race tests and coverage (default: gonew.ci config)")
+	communityFlag := fs.Bool("community", false, "Write issue and PR templates, CODE_OF_CONDUCT.md and CONTRIBUTING.md (default: gonew.community config)")
+	var dependencyBot string
+	fs.BoolFunc("dependabot", "Write .github/dependabot.yml, or renovate.json with -dependabot=renovate (default: gonew.dependency_bot config)", func(s string) error {
+		switch s {
+		case "true":
+			dependencyBot = devflow.DependencyBotDependabot
+		case "false":
+			dependencyBot = devflow.DependencyBotOff
+		default:
+			dependencyBot = s
+		}
+		return devflow.ValidateDependencyBot(dependencyBot)
+	})
+	auditFlag := fs.Bool("audit", false, "Print the audit log of every create step")
+	dryRunFlag := fs.Bool("dry-run", false, "Print every command and file write without running them")
+	rollbackFlag := fs.String("rollback", "", "On failure undo: off, local (directory and progress) or remote (also the created repository) (default: gonew.rollback config, else off)")
+	var snippets []string
+	fs.Func("snippets", "Gist ids or URLs whose files are copied into the project (comma-separated, repeatable)", func(s string) error {
+		snippets = append(snippets, splitList(s)...)
+		return nil
+	})
+	var readmeLangs []string
+	fs.Func("readme-langs", "Also write README.<lang>.md in these languages (comma-separated, default: gonew.readme_languages config)", func(s string) error {
+		readmeLangs = append(readmeLangs, strings.Split(s, ",")...)
+		return nil
+	})
+	var repo devflow.RepoSettings
+	fs.Func("topics", "Repository topics (comma-separated, GitHub)", func(s string) error {
+		repo.Topics = append(repo.Topics, splitList(s)...)
+		return nil
+	})
+	fs.StringVar(&repo.Homepage, "homepage", "", "Repository homepage URL (GitHub)")
+	fs.Func("merge", "Allowed pull request merges: squash, merge, rebase (comma-separated, default: gonew.repo.merge config)", func(s string) error {
+		repo.MergeStrategies = append(repo.MergeStrategies, splitList(s)...)
+		return nil
+	})
+	fs.BoolVar(&repo.ProtectBranch, "protect", false, "Protect the default branch (default: gonew.repo.protect_branch config)")
+	fs.BoolFunc("issues", "Enable the issue tracker, -issues=false disables it (default: gonew.repo.issues config)", func(s string) error {
+		v, err := strconv.ParseBool(s)
+		repo.Issues = &v
+		return err
+	})
+	fs.BoolFunc("wiki", "Enable the wiki, -wiki=false disables it (default: gonew.repo.wiki config)", func(s string) error {
+		v, err := strconv.ParseBool(s)
+		repo.Wiki = &v
+		return err
+	})
+	templateVars := map[string]string{}
+	signOffFlag := fs.Bool("signoff", false, "Sign off the initial commit with git user.name and user.email (default: commit.signoff config)")
+	var coAuthors []string
+	fs.Func("co-author", "Co-authored-by identity of the initial commit, \"Name <email>\" (repeatable)", func(s string) error {
+		if _, err := devflow.CoAuthorTrailer(s); err != nil {
+			return err
+		}
+		coAuthors = append(coAuthors, s)
+		return nil
+	})
+	fs.Func("var", "Template variable name=value (repeatable)", func(s string) error {
+		k, v, ok := strings.Cut(s, "=")
+		if !ok || k == "" {
+			return fmt.Errorf("expected name=value, got %q", s)
+		}
+		templateVars[k] = v
+		return nil
+	})
 
 	fs.Usage = func() {
-		fmt.Fprintf(os.Stderr, `gonew - Create new Go projects
+		fmt.Fprintf(stderr, `gonew - Create new Go projects
 
 Usage:
     gonew <repo-name> <description> [flags]
     gonew add-remote <project-path> [flags]
+    gonew resume [-discard] [-dry-run] [-rollback=local|remote] [repo-name|dir]
+    gonew regenerate [project-path] [-ci] [-dependabot] [-community] [-conflict=P] [-dry-run]
+    gonew templates search|install|update|list
+    gonew workspace <name> <module-dir>... [-dry-run]
 
 Flags:
-    -owner       GitHub owner/organization (default: auto-detected)
-    -visibility  public|private (default: public)
+    -owner       Owner/organization (default: auto-detected)
+    -provider    github|gitlab|gitea (default: provider.name in global config)
+    -host        Provider host, e.g. gitlab.example.com
+    -visibility  public|private (default: gonew.visibility config, else public)
+    -protocol    https|ssh|auto: origin URL, auto asks gh (default: gonew.remote_protocol, else auto)
     -local-only  Skip remote creation
-    -license     License type (default: MIT)
+    -license     MIT|Apache-2.0|BSD-3-Clause|GPL-3.0|MPL-2.0|Unlicense (default: gonew.license config, else MIT)
+    -branch      Initial branch (default: gonew.default_branch, init.defaultBranch or main)
+    -module-prefix  Module path prefix, e.g. go.example.com/libs (default: <host>/<owner>)
+    -type        library|cli|wasm|web (default: library)
+    -template    Template directory (with optional template.yml) or installed owner/repo
+    -var         Template variable name=value (repeatable)
+    -trust       Run the hooks of an installed template without asking
+    -seed        Existing code directory copied into the project
+    -snippets    Gists (id, id@revision or URL) copied into the project, comma-separated
+    -readme-langs  Localized READMEs to write, e.g. es,pt (supported: de, es, fr, it, pt)
+    -ci          Write .github/workflows/ci.yml (vet, race tests, coverage; WASM job for -type=wasm)
+    -dependabot  Write .github/dependabot.yml; -dependabot=renovate writes renovate.json
+    -community   Write issue/PR templates, CODE_OF_CONDUCT.md and CONTRIBUTING.md
+    -signoff     Sign off the initial commit (DCO; default: commit.signoff config)
+    -co-author   Co-authored-by "Name <email>" of the initial commit (repeatable)
+    -audit       Print the audit log of every create step
+    -dry-run     Print every command and file write without doing it
+    -rollback    On failure undo: off|local|remote (default: gonew.rollback config, else off)
+    -topics      Repository topics, comma-separated (GitHub)
+    -homepage    Repository homepage URL (GitHub)
+    -merge       Allowed merges, e.g. squash for squash-only (GitHub)
+    -protect     Protect the default branch (GitHub)
+    -issues, -wiki  Enable (or =false disable) the issue tracker and wiki (GitHub)
 
 Examples:
     gonew my-project "A sample Go project"
     gonew my-lib "Go library" -owner=cdvelop
     gonew my-tool "CLI tool" -owner=veltylabs -visibility=private
+    gonew my-app "Browser app" -type=wasm
+    gonew my-lib "Go library" -license=Apache-2.0
     gonew ~/Dev/my-tool "CLI tool" -local-only
+    gonew svc "Service" -template=~/templates/svc -var team=core
+    gonew svc "Service" -template=acme/svc-template
+    gonew my-tool "CLI tool" -seed=./prototype
+    gonew my-lib "Go library" -snippets=5f3a1c9e,9b2d7e41
+    gonew my-lib "Go library" -readme-langs=es,pt
+    gonew my-app "Browser app" -type=wasm -ci
+    gonew my-lib "Go library" -ci -dependabot
+    gonew my-lib "Go library" -community
+    gonew my-lib "Go library" -signoff -co-author "Jane Doe <jane@example.com>"
+    gonew svc "Service" -provider=gitlab -host=gitlab.example.com -owner=platform
+    gonew my-lib "Go library" -rollback=remote
+    gonew my-lib "Go library" -topics=go,cli -merge=squash -protect -wiki=false
     gonew add-remote ./my-project -owner=tinywasm -visibility=public
+    gonew resume my-project
+    gonew regenerate ./my-project -ci -community -conflict=merge
+    gonew workspace shop ./api ./web ./billing
+    gonew my-project "A sample Go project" -dry-run
 `)
 	}
 
@@ -89,7 +246,23 @@ Examples:
 			// Our flags: -visibility (takes arg), -local-only (bool), -license (takes arg)
 			if arg == "--owner" || arg == "-owner" ||
 				arg == "--visibility" || arg == "-visibility" ||
-				arg == "--license" || arg == "-license" {
+				arg == "--protocol" || arg == "-protocol" ||
+				arg == "--license" || arg == "-license" ||
+				arg == "--module-prefix" || arg == "-module-prefix" ||
+				arg == "--branch" || arg == "-branch" ||
+				arg == "--type" || arg == "-type" ||
+				arg == "--template" || arg == "-template" ||
+				arg == "--var" || arg == "-var" ||
+				arg == "--co-author" || arg == "-co-author" ||
+				arg == "--seed" || arg == "-seed" ||
+				arg == "--snippets" || arg == "-snippets" ||
+				arg == "--readme-langs" || arg == "-readme-langs" ||
+				arg == "--rollback" || arg == "-rollback" ||
+				arg == "--topics" || arg == "-topics" ||
+				arg == "--homepage" || arg == "-homepage" ||
+				arg == "--merge" || arg == "-merge" ||
+				arg == "--provider" || arg == "-provider" ||
+				arg == "--host" || arg == "-host" {
 				if i+1 < len(args) {
 					reorderedArgs = append(reorderedArgs, args[i+1])
 					skipNext = true
@@ -110,54 +283,113 @@ Examples:
 	repoName := purePositional[0]
 	description := purePositional[1]
 
+	if _, err := devflow.StartAirGap("."); err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	provider, err := providerSettings(*providerFlag, *hostFlag)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if provider.Name == devflow.ProviderGitHub {
+		if err := devflow.AirGapCheck(devflow.NetGitHub); err != nil && !*localOnlyFlag {
+			fmt.Fprintln(stdout, "⚠️", err, "- creating local only")
+			*localOnlyFlag = true
+		}
+	}
+
 	// Init handlers
 	git, err := devflow.NewGit()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fmt.Fprintf(stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
 	// Logger for all operations
-	log := func(args ...any) { fmt.Println(args...) }
+	log := func(args ...any) { fmt.Fprintln(stdout, args...) }
 
 	// Use Future for GitHub initialization
 	var githubFuture *devflow.Future
 	if !*localOnlyFlag {
 		githubFuture = devflow.NewFuture(func() (any, error) {
-			return devflow.NewGitHub(log)
+			return devflow.NewRepoProvider(provider, log)
 		})
 	}
 
 	goHandler, err := devflow.NewGo(git)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fmt.Fprintf(stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
 	orchestrator := devflow.NewGoNew(git, githubFuture, goHandler)
+	orchestrator.SetLogger(devflow.NewLoggerFromConfig(os.Stderr))
+	orchestrator.SetPrompt(os.Stdin, stdout)
+	orchestrator.SetDryRun(*dryRunFlag)
+	if err := orchestrator.SetRollback(*rollbackFlag); err != nil {
+		fmt.Fprintf(stderr, "Error: -rollback: %v\n", err)
+		os.Exit(1)
+	}
+	if len(snippets) > 0 && (*localOnlyFlag || provider.Name != devflow.ProviderGitHub) {
+		// Gists live on GitHub whatever the provider of the project
+		gists, err := devflow.NewGitHub(log)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: -snippets: %v\n", err)
+			os.Exit(1)
+		}
+		orchestrator.SetGistFetcher(gists)
+	}
 
 	// Create project
 	opts := devflow.NewProjectOptions{
-		Name:        repoName,
-		Description: description,
-		Owner:       *ownerFlag,
-		Visibility:  *visibilityFlag,
-		LocalOnly:   *localOnlyFlag,
-		License:     *licenseFlag,
+		Name:          repoName,
+		Description:   description,
+		Owner:         *ownerFlag,
+		Visibility:    *visibilityFlag,
+		LocalOnly:     *localOnlyFlag,
+		License:       *licenseFlag,
+		Type:          *typeFlag,
+		Host:          provider.ModuleHost(),
+		ModulePrefix:  *modulePrefixFlag,
+		DefaultBranch: *branchFlag,
+
+		RemoteProtocol: *protocolFlag,
+
+		Template:      devflow.ResolveTemplatePath(expandHome(*templateFlag)),
+		TemplateVars:  templateVars,
+		TrustTemplate: *trustFlag,
+		Seed:          expandHome(*seedFlag),
+		Snippets:      snippets,
+
+		ReadmeLanguages: readmeLangs,
+		CI:              *ciFlag,
+		DependencyBot:   dependencyBot,
+		Community:       *communityFlag,
+		Repo:            repo,
+
+		SignOff:   *signOffFlag,
+		CoAuthors: coAuthors,
 	}
 
 	summary, err := orchestrator.Create(opts)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "❌ Failed: %v\n", err)
+		orchestrator.WriteAuditLog(stderr)
+		fmt.Fprintf(stderr, "❌ Failed: %v\n", err)
+		devflow.WriteWarnings(stderr, orchestrator.Warnings())
 		os.Exit(1)
 	}
 
-	fmt.Println(summary)
+	if *auditFlag {
+		orchestrator.WriteAuditLog(stdout)
+	}
+	fmt.Fprintln(stdout, summary)
+	devflow.WriteWarnings(stdout, orchestrator.Warnings())
 }
 
-func handleAddRemote(args []string, visibility, owner string) {
+func handleAddRemote(args []string, visibility, owner, providerName, host string, dryRun bool) {
 	if len(args) < 1 {
-		fmt.Fprintf(os.Stderr, "Usage: gonew add-remote <project-path> [flags]\n")
+		fmt.Fprintf(stderr, "Usage: gonew add-remote <project-path> [flags]\n")
 		os.Exit(1)
 	}
 
@@ -165,29 +397,339 @@ func handleAddRemote(args []string, visibility, owner string) {
 
 	git, err := devflow.NewGit()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fmt.Fprintf(stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
-	log := func(args ...any) { fmt.Println(args...) }
+	log := func(args ...any) { fmt.Fprintln(stdout, args...) }
 
+	provider, err := providerSettings(providerName, host)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 	githubFuture := devflow.NewFuture(func() (any, error) {
-		return devflow.NewGitHub(log)
+		return devflow.NewRepoProvider(provider, log)
 	})
 
 	goHandler, err := devflow.NewGo(git)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fmt.Fprintf(stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
 	orchestrator := devflow.NewGoNew(git, githubFuture, goHandler)
+	orchestrator.SetLogger(devflow.NewLoggerFromConfig(os.Stderr))
+	orchestrator.SetDryRun(dryRun)
 
 	summary, err := orchestrator.AddRemote(projectPath, visibility, owner)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "❌ Failed: %v\n", err)
+		fmt.Fprintf(stderr, "❌ Failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Fprintln(stdout, summary)
+}
+
+func handleResume(args []string, providerName, host string, discard, dryRun bool, rollback string) {
+	if len(args) < 1 {
+		states, err := devflow.ListCreateStates()
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(states) == 0 {
+			fmt.Fprintln(stdout, "No unfinished creates")
+			return
+		}
+		fmt.Fprintln(stdout, "Unfinished creates (gonew resume <repo-name|dir>):")
+		for _, s := range states {
+			fmt.Fprintln(stdout, "  "+s.String())
+		}
+		return
+	}
+	name := args[0]
+
+	state, err := devflow.FindCreateState(name)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if state == nil {
+		fmt.Fprintf(stderr, "❌ No unfinished create of %s (see: gonew resume)\n", name)
+		os.Exit(1)
+	}
+
+	if discard {
+		if err := devflow.DiscardCreateState(state.Dir); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintln(stdout, "Discarded the saved progress of", state.Options.Name, "("+state.Dir+")")
+		return
+	}
+
+	if _, err := devflow.StartAirGap("."); err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	git, err := devflow.NewGit()
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	log := func(args ...any) { fmt.Fprintln(stdout, args...) }
+
+	var githubFuture *devflow.Future
+	if !state.Options.LocalOnly {
+		provider, err := providerSettings(providerName, host)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		githubFuture = devflow.NewFuture(func() (any, error) {
+			return devflow.NewRepoProvider(provider, log)
+		})
+	}
+
+	goHandler, err := devflow.NewGo(git)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	orchestrator := devflow.NewGoNew(git, githubFuture, goHandler)
+	orchestrator.SetLogger(devflow.NewLoggerFromConfig(os.Stderr))
+	orchestrator.SetPrompt(os.Stdin, stdout)
+	orchestrator.SetDryRun(dryRun)
+	if err := orchestrator.SetRollback(rollback); err != nil {
+		fmt.Fprintf(stderr, "Error: -rollback: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Fprintln(stdout, "Resuming", state)
+	summary, err := orchestrator.Resume(state.Dir)
+	if err != nil {
+		orchestrator.WriteAuditLog(stderr)
+		fmt.Fprintf(stderr, "❌ Failed: %v\n", err)
+		devflow.WriteWarnings(stderr, orchestrator.Warnings())
+		os.Exit(1)
+	}
+	fmt.Fprintln(stdout, summary)
+	devflow.WriteWarnings(stdout, orchestrator.Warnings())
+}
+
+func handleWorkspace(args []string, dryRun bool) {
+	if len(args) < 2 {
+		fmt.Fprintf(stderr, "Usage: gonew workspace <name> <module-dir>... [-dry-run]\n")
 		os.Exit(1)
 	}
 
-	fmt.Println(summary)
+	git, err := devflow.NewGit()
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	goHandler, err := devflow.NewGo(git)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	orchestrator := devflow.NewGoNew(git, nil, goHandler)
+	orchestrator.SetLogger(devflow.NewLoggerFromConfig(os.Stderr))
+	orchestrator.SetDryRun(dryRun)
+
+	summary, err := orchestrator.CreateWorkspace(expandHome(args[0]), args[1:])
+	if err != nil {
+		fmt.Fprintf(stderr, "❌ Failed: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintln(stdout, summary)
+}
+
+func handleRegenerate(args []string) {
+	fs := flag.NewFlagSet("regenerate", flag.ExitOnError)
+	var opts devflow.NewProjectOptions
+	fs.BoolVar(&opts.CI, "ci", false, "Write .github/workflows/ci.yml")
+	fs.BoolFunc("dependabot", "Write .github/dependabot.yml, or renovate.json with -dependabot=renovate", func(s string) error {
+		switch s {
+		case "true":
+			opts.DependencyBot = devflow.DependencyBotDependabot
+		case "false":
+			opts.DependencyBot = devflow.DependencyBotOff
+		default:
+			opts.DependencyBot = s
+		}
+		return nil
+	})
+	fs.BoolVar(&opts.Community, "community", false, "Write issue/PR templates, CODE_OF_CONDUCT.md and CONTRIBUTING.md")
+	wasm := fs.Bool("wasm", false, "Add the WASM job to the CI workflow")
+	conflict := fs.String("conflict", devflow.ConflictNew, "Existing files that differ: overwrite, skip, merge or new (<file>.new)")
+	dryRun := fs.Bool("dry-run", false, "Print the files without writing them")
+
+	// The project path may come before the flags
+	dir := "."
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		dir, args = args[0], args[1:]
+	}
+	fs.Parse(args)
+	if fs.NArg() > 0 {
+		dir = fs.Arg(0)
+	}
+	if *wasm {
+		opts.Type = devflow.ProjectWasm
+	}
+
+	git, err := devflow.NewGit()
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	goHandler, err := devflow.NewGo(git)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	orchestrator := devflow.NewGoNew(git, nil, goHandler)
+	orchestrator.SetLogger(devflow.NewLoggerFromConfig(os.Stderr))
+	orchestrator.SetDryRun(*dryRun)
+
+	conflicts, err := orchestrator.Regenerate(expandHome(dir), opts, *conflict)
+	if err != nil {
+		fmt.Fprintf(stderr, "❌ Failed: %v\n", err)
+		os.Exit(1)
+	}
+	for _, c := range conflicts {
+		fmt.Fprintln(stdout, c)
+		if c.Diff != "" {
+			fmt.Fprintln(stdout, c.Diff)
+		}
+	}
+	if !*dryRun {
+		fmt.Fprintf(stdout, "✅ Regenerated project files (%d existing files differed)\n", len(conflicts))
+	}
+}
+
+func handleTemplates(args []string) {
+	usage := func() {
+		fmt.Fprintf(stderr, `Usage:
+    gonew templates search [query]              Templates on GitHub (topic %s)
+    gonew templates install <owner/repo> [-tag vX.Y.Z]  Install, pinned to the latest release or -tag
+    gonew templates update [owner/repo...]      Move installed templates to their latest release
+    gonew templates list                        Installed templates and their tags
+
+Installed templates live in ~/.config/%s and are used with -template=<owner/repo>.
+`, devflow.TemplateTopic, devflow.InstalledTemplatesDir)
+		os.Exit(1)
+	}
+	if len(args) < 1 {
+		usage()
+	}
+
+	switch args[0] {
+	case "search":
+		gh, err := devflow.NewGitHub(func(args ...any) { fmt.Fprintln(stdout, args...) })
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		listings, err := gh.SearchTemplates(strings.Join(args[1:], " "))
+		if err != nil {
+			fmt.Fprintf(stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		if len(listings) == 0 {
+			fmt.Fprintln(stdout, "No templates found")
+			return
+		}
+		for _, l := range listings {
+			fmt.Fprintf(stdout, "%-40s ★%-5d %s\n", l.Repo, l.Stars, l.Description)
+		}
+		fmt.Fprintln(stdout, "\nInstall one with: gonew templates install <owner/repo>")
+
+	case "install":
+		fs := flag.NewFlagSet("templates install", flag.ExitOnError)
+		tag := fs.String("tag", "", "Tag to pin (default: the latest vX.Y.Z release)")
+		fs.Parse(args[1:])
+		if fs.NArg() != 1 {
+			usage()
+		}
+		t, err := devflow.InstallTemplate(fs.Arg(0), *tag)
+		if err != nil {
+			fmt.Fprintf(stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(stdout, "✅ Installed %s %s in %s\n", t.Name, t.Tag, t.Dir)
+		fmt.Fprintf(stdout, "   gonew <repo-name> <description> -template=%s\n", t.Name)
+
+	case "update":
+		lines, err := devflow.UpdateTemplates(args[1:]...)
+		for _, line := range lines {
+			fmt.Fprintln(stdout, line)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		if len(lines) == 0 {
+			fmt.Fprintln(stdout, "No templates installed")
+		}
+
+	case "list":
+		installed, err := devflow.ListInstalledTemplates()
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(installed) == 0 {
+			fmt.Fprintln(stdout, "No templates installed")
+			return
+		}
+		for _, t := range installed {
+			fmt.Fprintf(stdout, "%-40s %s\n", t.Name, t.Tag)
+		}
+
+	default:
+		usage()
+	}
+}
+
+// providerSettings combines the provider.* global config with the flags
+func providerSettings(name, host string) (devflow.ProviderSettings, error) {
+	global, err := devflow.LoadGlobalConfig()
+	if err != nil {
+		return devflow.ProviderSettings{}, err
+	}
+	s := devflow.LoadProviderSettings(global)
+	if name = strings.ToLower(name); name != "" && name != s.Name {
+		s.Name, s.Host = name, "" // provider.host belongs to the configured provider
+	}
+	if host != "" {
+		s.Host = host
+	}
+	return s, nil
+}
+
+// expandHome resolves a leading ~/ in flag values the shell did not expand
+func expandHome(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[2:])
+		}
+	}
+	return path
+}
+
+// splitList splits a comma-separated flag value, dropping empty items
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
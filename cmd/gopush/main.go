@@ -1,68 +1,304 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 
 	"github.com/tinywasm/devflow"
 )
 
+// stdout and stderr follow the accessible output mode (output.accessible)
+var stdout, stderr = devflow.OutputWriter(os.Stdout), devflow.OutputWriter(os.Stderr)
+
 func main() {
+	defer devflow.HandleInterrupts()()
+
 	usage := func() {
-		fmt.Fprintf(os.Stderr, `gopush - Complete Go project workflow: test + git push + update dependents
+		fmt.Fprintf(stderr, `gopush - Complete Go project workflow: test + git push + update dependents
 
 Usage:
-    gopush 'commit message' [tag]
+    gopush [flags] 'commit message' [tag]
+    gopush -pr [-branch B] [-base B] [-auto-merge M] [-wait D] [-draft] 'commit message'
+    gopush [-dry-run] release [-schedule=daily|weekly|monthly]
+    gopush [-search-path D] graph [-format=text|dot|mermaid]
 
 Arguments:
-    message    Commit message (required)
+    message    Commit message (required, optional with -i)
     tag        Tag name (optional, auto-generated if not provided)
 
+Flags:
+    -m message       Commit message (instead of the argument)
+    -tag tag         Tag name (instead of the argument)
+    -i               Review changed files, message and bump level before committing
+    -verify-deps M   Verify tag signatures of direct dependencies (warn|fail)
+    -bump L          Bump level of the generated tag: patch, minor or major
+                     (default: from feat:, fix: and BREAKING CHANGE: commits)
+    -issue N         Reference issue N in the commit trailers (Refs: #N)
+    -stash           Commit and tag only the staged changes, stashing the rest
+    -signoff         Append Signed-off-by with git user.name and user.email (DCO)
+    -co-author C     Append Co-authored-by C, "Name <email>" or an earlier author (repeatable)
+    -no-release      Do not create a GitHub release for the new tag
+    -skip-tests      Do not run gotest before pushing
+    -skip-deps-update
+                     Do not update the modules that depend on this one
+    -search-path D   Directory searched for dependent modules (default: ..)
+    -dry-run         Print the commands and file updates without running them
+    -plan            Print the resolved plan (files, message, tag, dependents) and exit
+    -workspace       Push every module of go.work (or below the current dir),
+                     dependencies first, each with its own generated tag
+    -p alias         Run in the project with this alias (projects in the global config)
+    -pr              Commit on a branch, push it without a tag and open a pull request
+    -branch B        Branch of the pull request (default: current, or named after the message)
+    -base B          Branch the pull request targets (default: the default branch)
+    -auto-merge M    Enable auto-merge: squash, merge or rebase (default: pr.auto_merge config)
+    -wait D          Wait up to D (e.g. 30m) for the checks, then merge
+                     with -auto-merge (default squash)
+    -draft           Open the pull request as a draft
+
+Release:
+    release          Release only if there are unreleased commits and no
+                     release yet this period (for cron / scheduled CI)
+    -schedule S      daily, weekly or monthly (default: release.schedule, weekly)
+
+Graph:
+    graph            Print the modules of -search-path that depend on this one,
+                     directly (updated by gopush) or through each other
+    -format F        text, dot (Graphviz) or mermaid (default: text)
+
 Examples:
     gopush 'feat: new feature'
     gopush 'fix: bug' 'v1.2.3'
+    gopush -m 'docs: readme' -tag v1.2.4 -skip-tests
+    gopush -search-path ~/Dev 'feat: new api'
+    gopush -i 'feat: new feature'
+    gopush -dry-run 'feat: new feature'
+    gopush -plan 'feat: new feature'
+    gopush -workspace 'feat: shared api'
+    gopush -pr -auto-merge=squash 'fix: handle empty input'
+    gopush -pr -wait=30m 'fix: handle empty input'
+    gopush release -schedule=weekly
+    gopush -search-path ~/Dev graph -format=dot | dot -Tsvg > deps.svg
 
 `)
 	}
 
-	args := os.Args[1:]
+	fs := flag.NewFlagSet("gopush", flag.ExitOnError)
+	fs.Usage = usage
+	messageFlag := fs.String("m", "", "Commit message")
+	tagFlag := fs.String("tag", "", "Tag name")
+	interactive := fs.Bool("i", false, "Interactive review before committing")
+	verifyDeps := fs.String("verify-deps", "", "Verify dependency tag signatures (warn|fail)")
+	bump := fs.String("bump", "", "Bump level: patch, minor or major")
+	issue := fs.String("issue", "", "Issue referenced in the commit trailers")
+	stash := fs.Bool("stash", false, "Stash unstaged and untracked changes during the push")
+	signOff := fs.Bool("signoff", false, "Append Signed-off-by with the git user")
+	var coAuthors []string
+	fs.Func("co-author", "Co-authored-by identity (repeatable)", func(s string) error {
+		coAuthors = append(coAuthors, s)
+		return nil
+	})
+	noRelease := fs.Bool("no-release", false, "Do not create a GitHub release")
+	skipTests := fs.Bool("skip-tests", false, "Do not run tests before pushing")
+	skipDeps := fs.Bool("skip-deps-update", false, "Do not update dependent modules")
+	searchPath := fs.String("search-path", "..", "Directory searched for dependent modules")
+	dryRun := fs.Bool("dry-run", false, "Print commands without running them")
+	plan := fs.Bool("plan", false, "Print the push plan and exit")
+	workspace := fs.Bool("workspace", false, "Push every module of the workspace")
+	project := fs.String("p", "", "Run in the project with this alias")
+	pr := fs.Bool("pr", false, "Push to a branch and open a pull request")
+	prBranch := fs.String("branch", "", "Branch of the pull request")
+	prBase := fs.String("base", "", "Branch the pull request targets")
+	autoMerge := fs.String("auto-merge", "", "Enable auto-merge: squash, merge or rebase")
+	draft := fs.Bool("draft", false, "Open the pull request as a draft")
+	wait := fs.Duration("wait", 0, "Wait this long for the checks, then merge the pull request")
+	fs.Parse(os.Args[1:])
+
+	if *project != "" {
+		if err := devflow.ChdirProject(*project); err != nil {
+			fmt.Fprintln(stdout, "Error:", err)
+			os.Exit(1)
+		}
+	} else if !*workspace {
+		if err := devflow.ChdirRoot(); err != nil {
+			fmt.Fprintln(stdout, "Error:", err)
+			os.Exit(1)
+		}
+	}
+
+	args := fs.Args()
+
+	// A subcommand is only ever the first positional argument, so -m release
+	// commits with the message "release"
+	var subcommand string
+	if len(args) > 0 && (args[0] == "release" || args[0] == "graph") {
+		subcommand, args = args[0], args[1:]
+	}
 
 	// Check if help requested or no arguments
-	if len(args) == 0 {
+	if subcommand == "" && len(args) == 0 && *messageFlag == "" && !*interactive {
 		usage()
 		os.Exit(0)
 	}
 
-	firstArg := args[0]
-	if firstArg == "help" || firstArg == "?" || firstArg == "-h" || firstArg == "--help" {
-		usage()
-		os.Exit(0)
+	var message, tag string
+	if subcommand == "" {
+		message, tag = *messageFlag, *tagFlag
+		if len(args) > 0 && message == "" {
+			firstArg := args[0]
+			if firstArg == "help" || firstArg == "?" {
+				usage()
+				os.Exit(0)
+			}
+			message = firstArg
+		}
+		if len(args) > 1 && tag == "" {
+			tag = args[1]
+		} else if len(args) > 0 && *messageFlag != "" && tag == "" {
+			tag = args[0]
+		}
 	}
 
-	// Message is mandatory
-	message := firstArg
-	tag := ""
-	if len(args) > 1 {
-		tag = args[1]
+	if _, err := devflow.StartAirGap("."); err != nil {
+		fmt.Fprintln(stdout, "Error:", err)
+		os.Exit(1)
 	}
 
 	git, err := devflow.NewGit()
 	if err != nil {
-		fmt.Println("Error:", err)
+		fmt.Fprintln(stdout, "Error:", err)
 		os.Exit(1)
 	}
+
+	logger := devflow.NewLoggerFromConfig(os.Stderr)
+	git.SetLogger(logger)
+	git.SetPushOptions(devflow.PushOptions{Bump: *bump, Issue: *issue, StashUncommitted: *stash, SignOff: *signOff, CoAuthors: coAuthors})
+	if *interactive && subcommand == "" {
+		review, err := git.ReviewChanges(os.Stdin, stdout, message)
+		if err != nil {
+			fmt.Fprintln(stdout, "Push aborted:", err)
+			os.Exit(1)
+		}
+		message = review.Message
+		if tag == "" {
+			tag = review.Tag
+		}
+		git.SetPushOptions(devflow.PushOptions{Exclude: review.Exclude, Bump: *bump, Issue: *issue, StashUncommitted: *stash, SignOff: *signOff, CoAuthors: coAuthors})
+	}
+
 	goHandler, err := devflow.NewGo(git)
 	if err != nil {
-		fmt.Println("Error:", err)
+		fmt.Fprintln(stdout, "Error:", err)
+		os.Exit(1)
+	}
+
+	goHandler.SetLogger(logger)
+	goHandler.SetDryRun(*dryRun)
+	goHandler.SetRelease(!*noRelease)
+
+	switch *verifyDeps {
+	case devflow.DepVerifyOff, devflow.DepVerifyWarn, devflow.DepVerifyFail:
+		goHandler.SetDependencyVerification(*verifyDeps)
+	default:
+		fmt.Fprintln(stdout, "Error: -verify-deps must be warn or fail")
+		os.Exit(1)
+	}
+
+	if subcommand == "release" {
+		runRelease(goHandler, args, *skipDeps, *searchPath)
+		return
+	}
+	if subcommand == "graph" {
+		runGraph(goHandler, args, *searchPath)
+		return
+	}
+
+	if *plan {
+		p, err := goHandler.PushPlan(message, tag, *skipTests, false, *skipDeps, *searchPath)
+		if err != nil {
+			fmt.Fprintln(stdout, "Plan failed:", err)
+			os.Exit(1)
+		}
+		fmt.Fprintln(stdout, p)
+		return
+	}
+
+	if *pr {
+		if tag != "" {
+			fmt.Fprintln(stdout, "Error: -pr pushes no tag; tag after the merge")
+			os.Exit(1)
+		}
+		opts := devflow.PullRequestOptions{Branch: *prBranch, Base: *prBase, Draft: *draft, AutoMerge: *autoMerge, WaitChecks: *wait, SkipTests: *skipTests}
+		summary, err := goHandler.PushPR(message, opts)
+		if err != nil {
+			fmt.Fprintln(stdout, "Push failed:", err)
+			devflow.WriteWarnings(stdout, goHandler.Warnings())
+			os.Exit(1)
+		}
+		fmt.Fprintln(stdout, summary)
+		devflow.WriteWarnings(stdout, goHandler.Warnings())
+		return
+	}
+
+	if *workspace {
+		if tag != "" {
+			fmt.Fprintln(stdout, "Error: -workspace generates the tag of each module")
+			os.Exit(1)
+		}
+		opts := devflow.WorkspacePushOptions{SkipTests: *skipTests, SkipDependents: *skipDeps, SearchPath: *searchPath}
+		summary, err := goHandler.PushWorkspace(".", message, opts)
+		if summary != "" {
+			fmt.Fprintln(stdout, summary)
+		}
+		if err != nil {
+			fmt.Fprintln(stdout, "Push failed:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	summary, err := goHandler.Push(message, tag, *skipTests, false, *skipDeps, false, *searchPath)
+	if err != nil {
+		fmt.Fprintln(stdout, "Push failed:", err)
+		devflow.WriteWarnings(stdout, goHandler.Warnings())
 		os.Exit(1)
 	}
 
-	// Always run with defaults
-	summary, err := goHandler.Push(message, tag, false, false, false, false, "..")
+	fmt.Fprintln(stdout, summary)
+	devflow.WriteWarnings(stdout, goHandler.Warnings())
+}
+
+// runGraph handles "gopush graph": the dependents that a push updates
+func runGraph(goHandler *devflow.Go, args []string, searchPath string) {
+	fs := flag.NewFlagSet("gopush graph", flag.ExitOnError)
+	format := fs.String("format", devflow.GraphText, "Output format: text, dot or mermaid")
+	fs.Parse(args)
+
+	graph, err := goHandler.DependencyGraph(searchPath)
+	if err != nil {
+		fmt.Fprintln(stdout, "Graph failed:", err)
+		os.Exit(1)
+	}
+	out, err := graph.Format(*format)
 	if err != nil {
-		fmt.Println("Push failed:", err)
+		fmt.Fprintln(stdout, "Error:", err)
 		os.Exit(1)
 	}
+	fmt.Fprint(stdout, out)
+}
+
+// runRelease handles "gopush release": a scheduled release when one is due
+func runRelease(goHandler *devflow.Go, args []string, skipDeps bool, searchPath string) {
+	fs := flag.NewFlagSet("gopush release", flag.ExitOnError)
+	schedule := fs.String("schedule", "", "Release schedule: daily, weekly or monthly")
+	fs.Parse(args)
 
-	fmt.Println(summary)
+	summary, err := goHandler.ScheduledRelease(*schedule, skipDeps, searchPath)
+	if err != nil {
+		fmt.Fprintln(stdout, "Release failed:", err)
+		devflow.WriteWarnings(stdout, goHandler.Warnings())
+		os.Exit(1)
+	}
+	fmt.Fprintln(stdout, summary)
+	devflow.WriteWarnings(stdout, goHandler.Warnings())
 }
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/tinywasm/devflow"
+)
+
+// stdout and stderr follow the accessible output mode (output.accessible)
+var stdout, stderr = devflow.OutputWriter(os.Stdout), devflow.OutputWriter(os.Stderr)
+
+func main() {
+	defer devflow.HandleInterrupts()()
+
+	usage := func() {
+		fmt.Fprintf(stderr, `gorelease - Cross-compile release binaries into checksummed archives
+
+Usage:
+    gorelease [flags]
+
+Flags:
+    -targets T   Comma separated GOOS/GOARCH list (default: build.targets,
+                 else linux, darwin (amd64, arm64) and windows/amd64)
+    -upload      Attach the archives and checksums to the GitHub release of the tag
+    -dry-run     Print the commands and file updates without running them
+    -p alias     Run in the project with this alias (projects in the global config)
+
+Examples:
+    gorelease
+    gorelease -targets=linux/amd64,linux/arm64
+    gorelease -upload
+
+`)
+	}
+
+	fs := flag.NewFlagSet("gorelease", flag.ExitOnError)
+	fs.Usage = usage
+	targetList := fs.String("targets", "", "Comma separated GOOS/GOARCH targets")
+	upload := fs.Bool("upload", false, "Upload the archives to the GitHub release")
+	dryRun := fs.Bool("dry-run", false, "Print commands without running them")
+	project := fs.String("p", "", "Run in the project with this alias")
+	fs.Parse(os.Args[1:])
+
+	if args := fs.Args(); len(args) > 0 {
+		if args[0] == "help" || args[0] == "?" {
+			usage()
+			os.Exit(0)
+		}
+		fmt.Fprintln(stdout, "Error: unexpected argument", args[0])
+		os.Exit(1)
+	}
+
+	if *project != "" {
+		if err := devflow.ChdirProject(*project); err != nil {
+			fmt.Fprintln(stdout, "Error:", err)
+			os.Exit(1)
+		}
+	}
+
+	targets, err := devflow.ParseTargets(*targetList)
+	if err != nil {
+		fmt.Fprintln(stdout, "Error:", err)
+		os.Exit(1)
+	}
+
+	if _, err := devflow.StartAirGap("."); err != nil {
+		fmt.Fprintln(stdout, "Error:", err)
+		os.Exit(1)
+	}
+
+	git, err := devflow.NewGit()
+	if err != nil {
+		fmt.Fprintln(stdout, "Error:", err)
+		os.Exit(1)
+	}
+	git.SetShouldWrite(func() bool { return true }) // build.dir goes to .gitignore
+
+	goHandler, err := devflow.NewGo(git)
+	if err != nil {
+		fmt.Fprintln(stdout, "Error:", err)
+		os.Exit(1)
+	}
+	goHandler.SetDryRun(*dryRun)
+
+	result, err := goHandler.BuildRelease(targets)
+	if err != nil {
+		fmt.Fprintln(stdout, "Build failed:", err)
+		os.Exit(1)
+	}
+	fmt.Fprintln(stdout, result.Summary())
+
+	if *upload {
+		summary, err := goHandler.UploadRelease(result)
+		if err != nil {
+			fmt.Fprintln(stdout, "Upload failed:", err)
+			os.Exit(1)
+		}
+		if summary != "" {
+			fmt.Fprintln(stdout, summary)
+		}
+	}
+}
@@ -5,17 +5,56 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"github.com/tinywasm/devflow"
 )
 
+// stdout and stderr follow the accessible output mode (output.accessible)
+var stdout, stderr = devflow.OutputWriter(os.Stdout), devflow.OutputWriter(os.Stderr)
+
 func main() {
+	defer devflow.HandleInterrupts()()
+
 	fs := flag.NewFlagSet("gotest", flag.ContinueOnError)
 	fs.SetOutput(io.Discard) // Silence default flag errors
+	ci := fs.Bool("ci", false, "Write a Markdown report to $GITHUB_STEP_SUMMARY")
+	sarif := fs.String("sarif", "", "Write vet, staticcheck and secret-scan findings to a SARIF file")
+	race := fs.String("race", "", "Race detector: auto (default), on or off")
+	budget := fs.Duration("budget", 0, "Time budget for all test runs (e.g. 10m)")
+	leaks := fs.Bool("leaks", false, "Fail when tests leave goroutines running")
+	profile := fs.Bool("profile", false, "Capture CPU and memory profiles per package")
+	top := fs.Int("top", 0, "With -profile, print the top N functions of each CPU profile")
+	format := fs.String("format", "text", "Report format: text, json or junit")
+	output := fs.String("o", "", "With -format, write the report to this file instead of stdout")
+	vuln := fs.Bool("vuln", false, "Run govulncheck in parallel with the tests")
+	lint := fs.Bool("lint", false, "Run staticcheck or golangci-lint alongside vet")
+	minCoverage := fs.Float64("min-coverage", 0, "Fail when a package's coverage is below this percentage")
+	project := fs.String("p", "", "Run in the project with this alias (projects in the global config)")
+	workspace := fs.Bool("workspace", false, "Test every module of go.work (or below the current dir)")
+	parallel := fs.Int("parallel", 1, "With -workspace, modules tested at once")
+	updateBaseline := fs.Bool("update-baseline", false, "Record current vet/staticcheck findings in .devflow-baseline.json")
 
 	usage := func() {
-		fmt.Println("Usage: gotest")
-		fmt.Println("Runs: vet, tests, race detection, coverage, and wasm tests.")
+		fmt.Fprintln(stdout, "Usage: gotest [-ci] [-sarif=results.sarif] [-race=auto|on|off] [-budget=10m] [-leaks] [-min-coverage=70] [-vuln] [-lint] [-format=text|json|junit [-o=file]] [-profile [-top=N]] [-update-baseline] [-workspace [-parallel=N]] [-p alias]")
+		fmt.Fprintln(stdout, "       gotest bench [-compare=main] [-count=6] [-bench=.]")
+		fmt.Fprintln(stdout, "       gotest fuzz [-fuzztime=10s] [-run=Parse]")
+		fmt.Fprintln(stdout, "Runs: vet, tests, race detection, coverage, and wasm tests.")
+		fmt.Fprintln(stdout, "  -ci     Write a Markdown report to $GITHUB_STEP_SUMMARY (GitHub Actions)")
+		fmt.Fprintln(stdout, "  -sarif  Write vet, staticcheck and secret-scan findings to a SARIF file")
+		fmt.Fprintln(stdout, "  -race   Race detector: auto (when CGO and the platform support it), on or off")
+		fmt.Fprintln(stdout, "  -budget Stop tests still running after this time and report the slowest packages")
+		fmt.Fprintln(stdout, "  -leaks  Report goroutines still running after each package's tests")
+		fmt.Fprintln(stdout, "  -min-coverage  Minimum coverage of every package (coverage.packages config overrides it per package)")
+		fmt.Fprintln(stdout, "  -vuln   Run govulncheck alongside the tests and add a Vulns badge (also vuln.enabled config)")
+		fmt.Fprintln(stdout, "  -lint   Run staticcheck or golangci-lint with vet and add a Lint badge (also lint.enabled config)")
+		fmt.Fprintln(stdout, "  -format Also print a json summary or JUnit XML report of every test (stdout, or the -o file)")
+		fmt.Fprintln(stdout, "  -profile  Capture CPU/memory profiles per package and print pprof commands")
+		fmt.Fprintln(stdout, "  -top    With -profile, print the top N functions of each CPU profile")
+		fmt.Fprintln(stdout, "  -p      Run in the project with this alias (projects: in ~/.config/devflow/config.yaml)")
+		fmt.Fprintln(stdout, "  -workspace  Run gotest in every module of go.work (or below the current dir) and print a table")
+		fmt.Fprintln(stdout, "  -parallel   With -workspace, number of modules tested at once")
+		fmt.Fprintln(stdout, "  -update-baseline  Record current findings in .devflow-baseline.json; only new ones fail")
 	}
 
 	err := fs.Parse(os.Args[1:])
@@ -25,7 +64,7 @@ func main() {
 			os.Exit(0)
 		}
 		// Minimal error for flags like -v
-		fmt.Println("gotest: no arguments needed.")
+		fmt.Fprintln(stdout, "gotest: unknown flag. Usage: gotest [-ci] [-sarif=results.sarif] [-race=auto|on|off] [-budget=10m] [-leaks] [-min-coverage=70] [-vuln] [-lint] [-format=text|json|junit [-o=file]] [-profile [-top=N]] [-update-baseline] [-workspace [-parallel=N]] [-p alias]")
 		os.Exit(1)
 	}
 
@@ -36,26 +75,221 @@ func main() {
 			usage()
 			os.Exit(0)
 		}
-		fmt.Printf("gotest: unexpected %q. No arguments needed.\n", arg)
+		if arg == "bench" {
+			runBench(fs.Args()[1:])
+			return
+		}
+		if arg == "fuzz" {
+			runFuzz(fs.Args()[1:])
+			return
+		}
+		fmt.Fprintf(stdout, "gotest: unexpected %q. No arguments needed.\n", arg)
+		os.Exit(1)
+	}
+
+	if *project != "" {
+		if err := devflow.ChdirProject(*project); err != nil {
+			fmt.Fprintln(stdout, "gotest:", err)
+			os.Exit(1)
+		}
+	} else if !*workspace {
+		if err := devflow.ChdirRoot(); err != nil {
+			fmt.Fprintln(stdout, "gotest:", err)
+			os.Exit(1)
+		}
+	}
+
+	reportFormat, err := devflow.ParseFormat(*format)
+	if err != nil {
+		fmt.Fprintln(stdout, "gotest:", err)
+		os.Exit(1)
+	}
+
+	raceMode := devflow.RaceMode("")
+	if *race != "" {
+		if raceMode, err = devflow.ParseRaceMode(*race); err != nil {
+			fmt.Fprintln(stdout, "gotest:", err)
+			os.Exit(1)
+		}
+	}
+
+	if *workspace {
+		runWorkspace(fs, *parallel)
+		return
+	}
+
+	if _, err := devflow.StartAirGap("."); err != nil {
+		fmt.Fprintln(stdout, "Error:", err)
+		os.Exit(1)
+	}
+
+	git, err := devflow.NewGit()
+	if err != nil {
+		fmt.Fprintln(stdout, "Error:", err)
+		os.Exit(1)
+	}
+	goHandler, err := devflow.NewGo(git)
+	if err != nil {
+		fmt.Fprintln(stdout, "Error:", err)
+		os.Exit(1)
+	}
+
+	if *profile {
+		summary, err := goHandler.Profile(*top)
+		fmt.Fprintln(stdout, summary)
+		if err != nil {
+			fmt.Fprintln(stdout, "Error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *updateBaseline {
+		summary, err := goHandler.UpdateBaseline()
+		if err != nil {
+			fmt.Fprintln(stdout, "Error:", err)
+			os.Exit(1)
+		}
+		fmt.Fprintln(stdout, summary)
+		return
+	}
+
+	opts := devflow.TestOptions{CI: *ci, SARIF: *sarif, Race: raceMode, Budget: *budget, Leaks: *leaks, Format: reportFormat, CoverageThreshold: *minCoverage, Vuln: *vuln, Lint: *lint}
+	// With a structured report on stdout, everything else goes to stderr
+	var console io.Writer = stdout
+	if reportFormat != devflow.FormatText {
+		if *output != "" {
+			f, err := os.Create(*output)
+			if err != nil {
+				fmt.Fprintln(stdout, "Error:", err)
+				os.Exit(1)
+			}
+			defer f.Close()
+			opts.Output = f
+		} else {
+			console = stderr
+			goHandler.SetLogger(devflow.NewLoggerFromConfig(os.Stderr))
+		}
+	}
+
+	summary, err := goHandler.TestWithOptions(opts)
+	if err != nil {
+		fmt.Fprintln(console, "Tests failed:", err)
+		devflow.WriteWarnings(console, goHandler.Warnings())
+		if f, ok := opts.Output.(*os.File); ok {
+			f.Close()
+		}
+		os.Exit(1)
+	}
+
+	fmt.Fprintln(console, summary)
+	devflow.WriteWarnings(console, goHandler.Warnings())
+}
+
+// runBench handles "gotest bench": benchmarks, optionally compared to a git ref
+func runBench(args []string) {
+	fs := flag.NewFlagSet("gotest bench", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	compare := fs.String("compare", "", "Git ref to compare against (e.g. main)")
+	count := fs.Int("count", 6, "Runs per benchmark")
+	bench := fs.String("bench", ".", "Benchmark pattern")
+	if err := fs.Parse(args); err != nil || fs.NArg() > 0 {
+		fmt.Fprintln(stdout, "Usage: gotest bench [-compare=main] [-count=6] [-bench=.]")
+		fmt.Fprintln(stdout, "  -compare  Also run on this git ref (temporary worktree) and report significant deltas")
+		fmt.Fprintln(stdout, "  -count    Runs per benchmark (default 6)")
+		fmt.Fprintln(stdout, "  -bench    Benchmark pattern (default .)")
+		if err == flag.ErrHelp {
+			os.Exit(0)
+		}
 		os.Exit(1)
 	}
 
 	git, err := devflow.NewGit()
 	if err != nil {
-		fmt.Println("Error:", err)
+		fmt.Fprintln(stdout, "Error:", err)
 		os.Exit(1)
 	}
 	goHandler, err := devflow.NewGo(git)
 	if err != nil {
-		fmt.Println("Error:", err)
+		fmt.Fprintln(stdout, "Error:", err)
+		os.Exit(1)
+	}
+	goHandler.SetLogger(devflow.NewLoggerFromConfig(os.Stderr))
+
+	summary, err := goHandler.Bench(devflow.BenchOptions{Compare: *compare, Count: *count, Bench: *bench})
+	fmt.Fprintln(stdout, summary)
+	if err != nil {
+		fmt.Fprintln(stdout, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+// runFuzz handles "gotest fuzz": every fuzz target for a time each
+func runFuzz(args []string) {
+	fs := flag.NewFlagSet("gotest fuzz", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fuzztime := fs.Duration("fuzztime", 0, "Fuzzing time per target")
+	match := fs.String("run", "", "Regexp of the fuzz targets to run")
+	corpus := fs.String("corpus", "", "Dir where generated corpora are kept")
+	if err := fs.Parse(args); err != nil || fs.NArg() > 0 {
+		fmt.Fprintln(stdout, "Usage: gotest fuzz [-fuzztime=10s] [-run=Parse] [-corpus=dir]")
+		fmt.Fprintln(stdout, "  -fuzztime  Fuzzing time per target (default fuzz.time config, then 10s)")
+		fmt.Fprintln(stdout, "  -run       Regexp of the fuzz targets to run (default all)")
+		fmt.Fprintln(stdout, "  -corpus    Dir where generated corpora are kept between runs (default user cache dir)")
+		if err == flag.ErrHelp {
+			os.Exit(0)
+		}
 		os.Exit(1)
 	}
 
-	summary, err := goHandler.Test()
+	goHandler, err := devflow.NewGo(nil)
 	if err != nil {
-		fmt.Println("Tests failed:", err)
+		fmt.Fprintln(stdout, "Error:", err)
 		os.Exit(1)
 	}
+	goHandler.SetLogger(devflow.NewLoggerFromConfig(os.Stderr))
 
-	fmt.Println(summary)
+	summary, err := goHandler.Fuzz(devflow.FuzzOptions{Time: *fuzztime, Match: *match, CorpusDir: *corpus})
+	fmt.Fprintln(stdout, summary)
+	if err != nil {
+		os.Exit(1)
+	}
+}
+
+// workspaceFlags are the flags passed on to each module by -workspace
+var workspaceFlags = map[string]bool{"race": true, "budget": true, "leaks": true, "min-coverage": true, "vuln": true, "lint": true}
+
+// runWorkspace handles -workspace: gotest in every module, then a table
+func runWorkspace(fs *flag.FlagSet, parallel int) {
+	var args []string
+	fs.Visit(func(f *flag.Flag) {
+		if workspaceFlags[f.Name] {
+			args = append(args, "-"+f.Name+"="+f.Value.String())
+		}
+	})
+	command, err := os.Executable()
+	if err != nil {
+		command = "gotest"
+	}
+
+	goHandler, err := devflow.NewGo(nil)
+	if err != nil {
+		fmt.Fprintln(stdout, "Error:", err)
+		os.Exit(1)
+	}
+	goHandler.SetLogger(devflow.NewLoggerFromConfig(os.Stderr))
+
+	modules, err := goHandler.TestWorkspace(".", devflow.WorkspaceOptions{Parallel: parallel, Command: command, Args: args})
+	for _, m := range modules {
+		if !m.Passed {
+			fmt.Fprintf(stdout, "--- %s\n%s\n", m.Dir, strings.TrimSpace(m.Output))
+		}
+	}
+	if len(modules) > 0 {
+		fmt.Fprintln(stdout, devflow.FormatWorkspaceTable(modules))
+	}
+	if err != nil {
+		fmt.Fprintln(stdout, "Tests failed:", err)
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/tinywasm/devflow"
+)
+
+// stdout and stderr follow the accessible output mode (output.accessible)
+var stdout, stderr = devflow.OutputWriter(os.Stdout), devflow.OutputWriter(os.Stderr)
+
+func main() {
+	fs := flag.NewFlagSet("licenseheader", flag.ExitOnError)
+	check := fs.Bool("check", false, "Only verify headers, exit 1 if any file fails")
+	spdx := fs.String("license", "", "SPDX identifier (default: license.spdx or detected from LICENSE)")
+	holder := fs.String("holder", "", "Copyright holder (default: license.holder or git user.name)")
+
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, `licenseheader - Insert and verify SPDX license headers in .go files
+
+Usage:
+    licenseheader [flags] [dir]
+
+Flags:
+    -check     Only verify headers, exit 1 if any file fails
+    -license   SPDX identifier (default: license.spdx or detected from LICENSE)
+    -holder    Copyright holder (default: license.holder or git user.name)
+
+Examples:
+    licenseheader
+    licenseheader -license=Apache-2.0 -holder="ACME Inc."
+    licenseheader -check ./mylib
+`)
+	}
+	fs.Parse(os.Args[1:])
+
+	dir := "."
+	if fs.NArg() > 0 {
+		dir = fs.Arg(0)
+	}
+
+	cfg, err := devflow.LoadConfig(dir)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if *spdx != "" {
+		cfg.Set("license.spdx", *spdx)
+	}
+	if *holder != "" {
+		cfg.Set("license.holder", *holder)
+	}
+
+	lh, err := devflow.NewLicenseHeader(dir, cfg)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *check {
+		report, err := lh.Check()
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		for _, f := range report.Missing {
+			fmt.Fprintln(stdout, "missing:", f)
+		}
+		for _, f := range report.Mismatched {
+			fmt.Fprintln(stdout, "mismatched:", f)
+		}
+		if !report.OK() {
+			fmt.Fprintln(stdout, "❌", report.Summary())
+			os.Exit(1)
+		}
+		fmt.Fprintln(stdout, "✅", report.Summary())
+		return
+	}
+
+	lh.SetLog(func(args ...any) { fmt.Fprintln(stdout, args...) })
+	n, err := lh.Insert()
+	if err != nil {
+		fmt.Fprintf(stderr, "❌ Failed: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(stdout, "✅ License headers: %d files updated (%s)\n", n, lh.SPDX)
+}
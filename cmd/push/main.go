@@ -8,37 +8,106 @@ import (
 	"github.com/tinywasm/devflow"
 )
 
+// stdout and stderr follow the accessible output mode (output.accessible)
+var stdout, stderr = devflow.OutputWriter(os.Stdout), devflow.OutputWriter(os.Stderr)
+
 func main() {
+	defer devflow.HandleInterrupts()()
+
 	// Parse flags
 	flag.Usage = func() {
-		fmt.Fprintf(os.Stderr, `push - Automated Git workflow
+		fmt.Fprintf(stderr, `push - Automated Git workflow
 
 Usage:
     push 'commit message' [tag]
     push [options]
+    push -i                  Build a conventional commit message interactively
 
 Arguments:
-    message    Commit message (required if no changes)
+    message    Commit message (prompted for in a terminal when omitted)
     tag        Tag name (optional, auto-generated if not provided)
 
 Options:
+    -i             Prompt for type, scope, subject and body, preview the tag
+    -amend         Amend HEAD if it is an unpushed auto-update (deps:/docs:)
+    -squash N      Squash the last N unpushed commits into one
+    -split         One commit per top-level directory (or -group)
+    -group G       Commit group name=pattern[,pattern] for -split (repeatable)
+    -no-tag        Commit and push without tagging (no release)
+    -bump L        Bump level of the generated tag: patch, minor or major
+                   (default: from feat:, fix: and BREAKING CHANGE: commits)
+    -branch B      Commit on and push branch B, created from HEAD when missing
+    -issue N       Reference issue N in the commit trailers (Refs: #N)
+    -signoff       Append Signed-off-by with git user.name and user.email (DCO)
+    -co-author C   Append Co-authored-by C: "Name <email>", or a name or email
+                   of an earlier commit author (repeatable)
+    -stash         Commit and tag only the staged changes, stashing the rest
+    -only P        Stage only the changes matching patterns P (pkg/,*.go), not git add .
+    -exclude P     Leave the changes matching patterns P unstaged (*.tmp,scratch/)
+    -dry-run       Print the git commands without running them
+    -p alias       Run in the project with this alias (projects in the global config)
     -h, --help     Show this help message
 
 Examples:
     push 'feat: new feature'
     push 'fix: bug correction' 'v1.2.3'
+    push -amend 'docs: fix typo'
+    push -squash 3 'docs: rewrite guide'
+    push -split -group 'docs=docs/,*.md' 'feat: new api'
+    push -dry-run 'feat: new feature'
+    push -no-tag 'fix: work in progress'
+    push -bump major 'feat: v1 api'
+    push -issue 42 'fix: empty input'
+    push -stash 'fix: staged part only'
+    push -signoff -co-author jane 'feat: pair-programmed parser'
+    push -only pkg/ 'feat: new parser'
 
 Workflow:
     1. git add .
     2. git commit -m "message"
-    3. git tag <tag> (auto-generated or provided)
+    3. git tag <tag> (provided, or the latest bumped by the commits since it)
     4. git push && git push origin <tag>
 
 `)
 	}
 
+	interactiveFlag := flag.Bool("i", false, "Build the commit message interactively")
 	helpFlag := flag.Bool("h", false, "Show help")
 	flag.BoolVar(helpFlag, "help", false, "Show help")
+	amendFlag := flag.Bool("amend", false, "Amend HEAD if it is an unpushed auto-update")
+	squashFlag := flag.Int("squash", 0, "Squash the last N unpushed commits into one")
+	splitFlag := flag.Bool("split", false, "Split changes into one commit per group")
+	noTagFlag := flag.Bool("no-tag", false, "Commit and push without tagging")
+	bumpFlag := flag.String("bump", "", "Bump level: patch, minor or major")
+	branchFlag := flag.String("branch", "", "Commit on and push this branch, created when missing")
+	issueFlag := flag.String("issue", "", "Issue referenced in the commit trailers")
+	stashFlag := flag.Bool("stash", false, "Stash unstaged and untracked changes during the push")
+	signOffFlag := flag.Bool("signoff", false, "Append Signed-off-by with the git user")
+	var coAuthors []string
+	flag.Func("co-author", "Co-authored-by identity (repeatable)", func(s string) error {
+		coAuthors = append(coAuthors, s)
+		return nil
+	})
+	var paths devflow.CommitOptions
+	flag.Func("only", "Stage only the changes matching these patterns (repeatable)", func(s string) error {
+		paths.Include = append(paths.Include, devflow.ParsePatterns(s)...)
+		return nil
+	})
+	flag.Func("exclude", "Leave the changes matching these patterns unstaged (repeatable)", func(s string) error {
+		paths.Exclude = append(paths.Exclude, devflow.ParsePatterns(s)...)
+		return nil
+	})
+	projectFlag := flag.String("p", "", "Run in the project with this alias")
+	dryRunFlag := flag.Bool("dry-run", false, "Print git commands without running them")
+	var groups []devflow.CommitGroup
+	flag.Func("group", "Commit group name=pattern[,pattern] (repeatable)", func(s string) error {
+		g, err := devflow.ParseCommitGroup(s)
+		if err != nil {
+			return err
+		}
+		groups = append(groups, g)
+		return nil
+	})
 	flag.Parse()
 
 	if *helpFlag {
@@ -46,6 +115,13 @@ Workflow:
 		os.Exit(0)
 	}
 
+	if *projectFlag != "" {
+		if err := devflow.ChdirProject(*projectFlag); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	args := flag.Args()
 
 	var message, tag string
@@ -58,29 +134,72 @@ Workflow:
 		tag = args[1]
 	}
 
-	if message == "" {
-		fmt.Fprintln(os.Stderr, "Error: commit message is required")
+	prompt := message == "" && (*interactiveFlag || stdinIsTerminal())
+	if message == "" && !prompt {
+		fmt.Fprintln(stderr, "Error: commit message is required")
 		flag.Usage()
 		os.Exit(1)
 	}
 
+	if _, err := devflow.StartAirGap("."); err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Execute workflow
 	git, err := devflow.NewGit()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fmt.Fprintf(stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
+	git.SetPushOptions(devflow.PushOptions{
+		Amend:  *amendFlag,
+		Squash: *squashFlag,
+		Split:  *splitFlag,
+		Groups: groups,
+		NoTag:  *noTagFlag,
+		Bump:   *bumpFlag,
+		Branch: *branchFlag,
+		Issue:  *issueFlag,
+
+		SignOff:   *signOffFlag,
+		CoAuthors: coAuthors,
+
+		StashUncommitted: *stashFlag,
+		Paths:            paths,
+	})
+	git.SetDryRun(*dryRunFlag)
+	git.SetLogger(devflow.NewLoggerFromConfig(os.Stderr))
+
+	if prompt {
+		result, err := git.PromptCommitMessage(os.Stdin, stdout)
+		if err != nil {
+			fmt.Fprintf(stderr, "Push aborted: %v\n", err)
+			os.Exit(1)
+		}
+		message = result.Message
+		if tag == "" {
+			tag = result.Tag
+		}
+	}
+
 	summary, err := git.Push(message, tag)
 
 	if summary != "" {
-		fmt.Println(summary)
+		fmt.Fprintln(stdout, summary)
 	}
 
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fmt.Fprintf(stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
 	os.Exit(0)
 }
+
+// stdinIsTerminal reports whether the user can answer prompts
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -1,6 +1,9 @@
 package devflow
 
-import "fmt"
+import (
+	"fmt"
+	"os"
+)
 
 const (
 	ColorRed    = "\033[0;31m"
@@ -10,22 +13,25 @@ const (
 	ColorNone   = "\033[0m"
 )
 
+// The Print functions write through OutputWriter, which drops the colors
+// in the accessible output mode
+
 // PrintSuccess prints a success message in green.
 func PrintSuccess(msg string) {
-	fmt.Printf("%s%s%s\n", ColorGreen, msg, ColorNone)
+	fmt.Fprintf(OutputWriter(os.Stdout), "%s%s%s\n", ColorGreen, msg, ColorNone)
 }
 
 // PrintWarning prints a warning message in yellow.
 func PrintWarning(msg string) {
-	fmt.Printf("%s%s%s\n", ColorYellow, msg, ColorNone)
+	fmt.Fprintf(OutputWriter(os.Stdout), "%s%s%s\n", ColorYellow, msg, ColorNone)
 }
 
 // PrintError prints an error message in red.
 func PrintError(msg string) {
-	fmt.Printf("%sError: %s%s\n", ColorRed, msg, ColorNone)
+	fmt.Fprintf(OutputWriter(os.Stdout), "%sError: %s%s\n", ColorRed, msg, ColorNone)
 }
 
 // PrintInfo prints an informational message in cyan.
 func PrintInfo(msg string) {
-	fmt.Printf("%s%s%s\n", ColorCyan, msg, ColorNone)
+	fmt.Fprintf(OutputWriter(os.Stdout), "%s%s%s\n", ColorCyan, msg, ColorNone)
 }
@@ -0,0 +1,177 @@
+package devflow
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// CommitType is a conventional commit type offered by the message builder
+type CommitType struct {
+	Name        string
+	Description string
+}
+
+// CommitTypes are the types PromptCommitMessage offers, in menu order
+var CommitTypes = []CommitType{
+	{"feat", "A new feature (minor bump)"},
+	{"fix", "A bug fix"},
+	{"docs", "Documentation only"},
+	{"refactor", "Code change that neither fixes a bug nor adds a feature"},
+	{"perf", "Performance improvement"},
+	{"test", "Adding or fixing tests"},
+	{"build", "Build system or dependencies"},
+	{"ci", "CI configuration"},
+	{"chore", "Other changes that don't touch the code"},
+	{"style", "Formatting, no code change"},
+	{"revert", "Reverts a previous commit"},
+}
+
+// maxCommitHeader is the longest "type(scope): subject" line accepted
+const maxCommitHeader = 100
+
+// ValidateConventionalCommit checks that the first line of message is
+// "type(scope)!: subject" with a type from CommitTypes
+func ValidateConventionalCommit(message string) error {
+	if err := ValidateCommitMessage(message); err != nil {
+		return err
+	}
+	header, body, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	m := conventionalRe.FindStringSubmatch(header)
+	if m == nil {
+		return fmt.Errorf("commit message %q is not a conventional commit (type(scope): subject)", header)
+	}
+	if !knownCommitType(m[1]) {
+		return fmt.Errorf("unknown commit type %q (use one of %s)", m[1], commitTypeNames())
+	}
+	if strings.TrimSpace(m[4]) == "" {
+		return fmt.Errorf("commit subject cannot be empty")
+	}
+	if len(header) > maxCommitHeader {
+		return fmt.Errorf("commit header is %d characters long (max %d)", len(header), maxCommitHeader)
+	}
+	if body != "" && !strings.HasPrefix(body, "\n") {
+		return fmt.Errorf("separate the commit subject from the body with an empty line")
+	}
+	return nil
+}
+
+func knownCommitType(name string) bool {
+	for _, t := range CommitTypes {
+		if t.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func commitTypeNames() string {
+	names := make([]string, len(CommitTypes))
+	for i, t := range CommitTypes {
+		names[i] = t.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// PromptCommitMessage builds a conventional commit message from prompts
+// for the type, scope, breaking change, subject and body, then previews
+// the tag it produces and asks for confirmation. Nothing is committed;
+// the caller pushes result.Message and result.Tag.
+func (g *Git) PromptCommitMessage(in io.Reader, out io.Writer) (ReviewResult, error) {
+	ask := promptReader(in, out)
+	message, err := buildCommitMessage(ask, out)
+	if err != nil {
+		return ReviewResult{}, err
+	}
+
+	result := ReviewResult{Message: message}
+	latest, _ := g.GetLatestTag()
+	if !g.pushOpts.NoTag {
+		if result.Tag, err = BumpVersion(latest, g.BumpLevel(latest, message)); err != nil {
+			return ReviewResult{}, err
+		}
+	}
+
+	fmt.Fprintln(out, "\n"+message)
+	switch {
+	case result.Tag == "":
+	case latest == "":
+		fmt.Fprintf(out, "\nNext tag: %s (first tag)\n", result.Tag)
+	default:
+		fmt.Fprintf(out, "\nNext tag: %s (from %s)\n", result.Tag, latest)
+	}
+	if answer := strings.ToLower(ask("Proceed? [Y/n]: ")); answer != "" && answer != "y" && answer != "yes" {
+		return ReviewResult{}, fmt.Errorf("push cancelled")
+	}
+	return result, nil
+}
+
+// promptReader returns a function that prints a prompt and reads a line
+func promptReader(in io.Reader, out io.Writer) func(string) string {
+	reader := bufio.NewReader(in)
+	return func(prompt string) string {
+		fmt.Fprint(out, prompt)
+		line, _ := reader.ReadString('\n')
+		return strings.TrimSpace(line)
+	}
+}
+
+// buildCommitMessage asks for each part of a conventional commit message
+// and returns the validated message
+func buildCommitMessage(ask func(string) string, out io.Writer) (string, error) {
+	fmt.Fprintln(out, "Commit type:")
+	for i, t := range CommitTypes {
+		fmt.Fprintf(out, "  %2d) %-9s %s\n", i+1, t.Name, t.Description)
+	}
+	kind := ask("Type (number or name): ")
+	if n, err := strconv.Atoi(kind); err == nil {
+		if n < 1 || n > len(CommitTypes) {
+			return "", fmt.Errorf("invalid commit type number: %d", n)
+		}
+		kind = CommitTypes[n-1].Name
+	}
+	if !knownCommitType(kind) {
+		return "", fmt.Errorf("unknown commit type %q (use one of %s)", kind, commitTypeNames())
+	}
+
+	header := kind
+	if scope := ask("Scope (optional): "); scope != "" {
+		header += "(" + scope + ")"
+	}
+	breaking := strings.ToLower(ask("Breaking change? [y/N]: "))
+	isBreaking := breaking == "y" || breaking == "yes"
+	if isBreaking {
+		header += "!"
+	}
+	subject := ask("Subject: ")
+	if subject == "" {
+		return "", fmt.Errorf("commit subject cannot be empty")
+	}
+	header += ": " + subject
+
+	fmt.Fprintln(out, "Body (optional, end with an empty line):")
+	var body []string
+	for {
+		line := ask("  ")
+		if line == "" {
+			break
+		}
+		body = append(body, line)
+	}
+	if isBreaking {
+		if note := ask("Describe the breaking change (optional): "); note != "" {
+			body = append(body, "", "BREAKING CHANGE: "+note)
+		}
+	}
+
+	message := header
+	if len(body) > 0 {
+		message += "\n\n" + strings.TrimLeft(strings.Join(body, "\n"), "\n")
+	}
+	if err := ValidateConventionalCommit(message); err != nil {
+		return "", err
+	}
+	return message, nil
+}
@@ -0,0 +1,64 @@
+package devflow
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestValidateConventionalCommit(t *testing.T) {
+	valid := []string{"feat: add x", "fix(api)!: drop v1", "docs: readme\n\nlonger text"}
+	for _, m := range valid {
+		if err := ValidateConventionalCommit(m); err != nil {
+			t.Errorf("%q should be valid: %v", m, err)
+		}
+	}
+	invalid := map[string]string{
+		"add x":                            "not a conventional commit",
+		"feature: add x":                   "unknown commit type",
+		"fix: " + strings.Repeat("x", 100): "max 100",
+		"fix: x\nbody":                     "empty line",
+		"":                                 "empty",
+	}
+	for m, want := range invalid {
+		if err := ValidateConventionalCommit(m); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: expected error containing %q, got %v", m, want, err)
+		}
+	}
+}
+
+func TestPromptCommitMessage(t *testing.T) {
+	defer testPushedRepo(t)()
+	git, _ := NewGit()
+
+	// feat, scope api, breaking, subject, one body line, footer, confirm
+	in := strings.NewReader("1\napi\ny\nnew client\nReplaces the old one.\n\nremoved Dial\n\n")
+	var out bytes.Buffer
+	res, err := git.PromptCommitMessage(in, &out)
+	if err != nil {
+		t.Fatalf("PromptCommitMessage failed: %v\n%s", err, out.String())
+	}
+	want := "feat(api)!: new client\n\nReplaces the old one.\n\nBREAKING CHANGE: removed Dial"
+	if res.Message != want {
+		t.Errorf("unexpected message:\n%s", res.Message)
+	}
+	// Below v1 a breaking change bumps minor
+	if res.Tag != "v0.1.0" || !strings.Contains(out.String(), "Next tag: v0.1.0 (from v0.0.1)") {
+		t.Errorf("unexpected tag preview %s:\n%s", res.Tag, out.String())
+	}
+
+	res, err = git.PromptCommitMessage(strings.NewReader("fix\n\n\ntypo\n\ny\n"), &bytes.Buffer{})
+	if err != nil || res.Message != "fix: typo" || res.Tag != "v0.0.2" {
+		t.Errorf("unexpected result %+v, %v", res, err)
+	}
+
+	if _, err := git.PromptCommitMessage(strings.NewReader("fix\n\n\ntypo\n\nn\n"), &bytes.Buffer{}); err == nil || err.Error() != "push cancelled" {
+		t.Errorf("expected cancel, got %v", err)
+	}
+	if _, err := git.PromptCommitMessage(strings.NewReader("99\n"), &bytes.Buffer{}); err == nil {
+		t.Error("expected invalid type number error")
+	}
+	if _, err := git.PromptCommitMessage(strings.NewReader("feat\n\n\n\n"), &bytes.Buffer{}); err == nil || !strings.Contains(err.Error(), "subject") {
+		t.Errorf("expected empty subject error, got %v", err)
+	}
+}
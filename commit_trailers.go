@@ -0,0 +1,179 @@
+package devflow
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// defaultCommitTrailers are the trailers of commit.trailers when it is not
+// set: the issue reference, when there is one
+var defaultCommitTrailers = []string{"Refs: {{issue}}"}
+
+// branchIssueRe matches the issue number of a branch name: feat/42-login,
+// 42-login, issue-42, fix/gh_42
+var branchIssueRe = regexp.MustCompile(`(?:^|[/_-])(\d+)(?:[/_-]|$)`)
+
+// trailerRe matches a "Key: value" trailer line
+var trailerRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*: \S`)
+
+// identityRe matches a "Name <email>" identity
+var identityRe = regexp.MustCompile(`^[^<>]*[^<>\s][^<>]*\s<[^<>\s]+@[^<>\s]+>$`)
+
+// userIdentity is the git identity of the sign-off (Git, a GitClient)
+type userIdentity interface {
+	GetConfigUserName() (string, error)
+	GetConfigUserEmail() (string, error)
+}
+
+// IssueReference returns the form of an issue the trailers reference: a
+// number gets "#42", other references (PROJ-7, owner/repo#42) are kept
+func IssueReference(issue string) string {
+	issue = strings.TrimSpace(issue)
+	if issue != "" && strings.Trim(issue, "0123456789") == "" {
+		return "#" + issue
+	}
+	return issue
+}
+
+// BranchIssue returns the issue reference of a branch named after an issue
+// (feat/42-login gets "#42"), empty when the name has no number
+func BranchIssue(branch string) string {
+	if m := branchIssueRe.FindStringSubmatch(branch); m != nil {
+		return "#" + m[1]
+	}
+	return ""
+}
+
+// AddTrailers appends trailers to the trailer block of message, starting
+// one after a blank line when its last paragraph is not made of trailers.
+// Trailers already in the message are not repeated.
+func AddTrailers(message string, trailers []string) string {
+	message = strings.TrimRight(message, "\n")
+	lines := strings.Split(message, "\n")
+	have := make(map[string]bool, len(lines))
+	for _, line := range lines {
+		have[strings.TrimSpace(line)] = true
+	}
+	var add []string
+	for _, t := range trailers {
+		if t = strings.TrimSpace(t); t != "" && !have[t] {
+			add = append(add, t)
+			have[t] = true
+		}
+	}
+	if len(add) == 0 {
+		return message
+	}
+
+	// The last paragraph, when it is not the subject, may be the trailers
+	start := len(lines)
+	for start > 0 && lines[start-1] != "" {
+		start--
+	}
+	block := start > 0
+	for _, line := range lines[start:] {
+		block = block && trailerRe.MatchString(line)
+	}
+	if block {
+		return message + "\n" + strings.Join(add, "\n")
+	}
+	return message + "\n\n" + strings.Join(add, "\n")
+}
+
+// CoAuthorTrailer returns the Co-authored-by trailer of an identity
+// written as "Name <email>"
+func CoAuthorTrailer(identity string) (string, error) {
+	identity = strings.TrimSpace(identity)
+	if !identityRe.MatchString(identity) {
+		return "", fmt.Errorf("co-author %q is not written as Name <email>", identity)
+	}
+	return "Co-authored-by: " + identity, nil
+}
+
+// SignOffTrailer returns the Signed-off-by trailer of the git user.name and
+// user.email of client, which must both be set
+func SignOffTrailer(client userIdentity) (string, error) {
+	name, _ := client.GetConfigUserName()
+	email, _ := client.GetConfigUserEmail()
+	if name == "" || email == "" {
+		return "", fmt.Errorf("the sign-off needs git user.name and user.email")
+	}
+	return fmt.Sprintf("Signed-off-by: %s <%s>", name, email), nil
+}
+
+// identityTrailers returns the Co-authored-by trailers of coAuthors, then
+// the sign-off of client when signOff is set, as commits end with them.
+// resolve, when set, completes a co-author given by name or email only.
+func identityTrailers(client userIdentity, coAuthors []string, signOff bool, resolve func(string) string) ([]string, error) {
+	var trailers []string
+	for _, identity := range coAuthors {
+		if resolve != nil && !identityRe.MatchString(strings.TrimSpace(identity)) {
+			identity = resolve(identity)
+		}
+		t, err := CoAuthorTrailer(identity)
+		if err != nil {
+			return nil, err
+		}
+		trailers = append(trailers, t)
+	}
+	if signOff {
+		t, err := SignOffTrailer(client)
+		if err != nil {
+			return nil, err
+		}
+		trailers = append(trailers, t)
+	}
+	return trailers, nil
+}
+
+// knownAuthor returns "Name <email>" of the latest commit whose author
+// name or email contains who, else who as given
+func knownAuthor(who string) string {
+	out, err := RunCommandSilent("git", "log", "-1", "-i", "--fixed-strings", "--author="+strings.TrimSpace(who), "--format=%an <%ae>")
+	if err != nil || out == "" {
+		return who
+	}
+	return out
+}
+
+// commitTrailers returns the trailers Push appends to its commits:
+// commit.trailers with {{issue}} replaced by the issue of the push (those
+// that use it are left out without one), then the Co-authored-by of
+// commit.co_authors and PushOptions.CoAuthors, then the sign-off of the
+// git user with commit.signoff or PushOptions.SignOff
+func (g *Git) commitTrailers() ([]string, error) {
+	cfg, err := LoadConfig(g.rootDir)
+	if err != nil {
+		// A required sign-off must not be dropped silently
+		return nil, err
+	}
+	templates := defaultCommitTrailers
+	if cfg.Has("commit.trailers") {
+		templates = cfg.List("commit.trailers")
+	}
+	issue := IssueReference(g.pushOpts.Issue)
+	if issue == "" && cfg.Bool("commit.issue_from_branch", false) {
+		branch, _ := g.CurrentBranch()
+		issue = BranchIssue(branch)
+	}
+
+	var trailers []string
+	for _, t := range templates {
+		if strings.Contains(t, "{{issue}}") {
+			if issue == "" {
+				continue
+			}
+			t = strings.ReplaceAll(t, "{{issue}}", issue)
+		}
+		trailers = append(trailers, t)
+	}
+
+	coAuthors := append(cfg.List("commit.co_authors"), g.pushOpts.CoAuthors...)
+	signOff := g.pushOpts.SignOff || cfg.Bool("commit.signoff", false)
+	identities, err := identityTrailers(g, coAuthors, signOff, knownAuthor)
+	if err != nil {
+		return nil, err
+	}
+	return append(trailers, identities...), nil
+}
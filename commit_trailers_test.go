@@ -0,0 +1,120 @@
+package devflow
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+)
+
+func TestAddTrailers(t *testing.T) {
+	trailers := []string{"Refs: #42", "Signed-off-by: Jane <jane@example.com>"}
+	for _, tc := range []struct{ message, want string }{
+		{"fix: empty input", "fix: empty input\n\nRefs: #42\nSigned-off-by: Jane <jane@example.com>"},
+		{"fix: empty input\n\nSome details.\n", "fix: empty input\n\nSome details.\n\nRefs: #42\nSigned-off-by: Jane <jane@example.com>"},
+		{"fix: empty input\n\nCo-authored-by: Bob <bob@example.com>", "fix: empty input\n\nCo-authored-by: Bob <bob@example.com>\nRefs: #42\nSigned-off-by: Jane <jane@example.com>"},
+		{"fix: empty input\n\nRefs: #42", "fix: empty input\n\nRefs: #42\nSigned-off-by: Jane <jane@example.com>"},
+		{"fix: a: b", "fix: a: b\n\nRefs: #42\nSigned-off-by: Jane <jane@example.com>"},
+	} {
+		if got := AddTrailers(tc.message, trailers); got != tc.want {
+			t.Errorf("AddTrailers(%q) = %q, want %q", tc.message, got, tc.want)
+		}
+	}
+	if got := AddTrailers("fix: x", nil); got != "fix: x" {
+		t.Errorf("expected no change without trailers, got %q", got)
+	}
+}
+
+func TestIssueReferences(t *testing.T) {
+	for in, want := range map[string]string{"42": "#42", "#42": "#42", "PROJ-7": "PROJ-7", "acme/api#3": "acme/api#3", "": ""} {
+		if got := IssueReference(in); got != want {
+			t.Errorf("IssueReference(%q) = %q, want %q", in, got, want)
+		}
+	}
+	for branch, want := range map[string]string{"feat/42-login": "#42", "42-login": "#42", "issue-42": "#42", "fix/gh_7": "#7", "main": "", "feat/v2-api": ""} {
+		if got := BranchIssue(branch); got != want {
+			t.Errorf("BranchIssue(%q) = %q, want %q", branch, got, want)
+		}
+	}
+}
+
+func TestGitPushTrailers(t *testing.T) {
+	testResumeEnv(t)
+	defer testPushedRepo(t)()
+	os.WriteFile(".devflow.yaml", []byte("commit:\n  issue_from_branch: true\n  signoff: true\n"), 0644)
+
+	git, _ := NewGit()
+	git.SetPushOptions(PushOptions{Branch: "fix/42-empty-input", NoTag: true})
+	os.WriteFile("a.go", []byte("package a\n"), 0644)
+	if _, err := git.Push("fix: empty input", ""); err != nil {
+		t.Fatal(err)
+	}
+	body, _ := RunCommandSilent("git", "log", "-1", "--format=%B")
+	if want := "fix: empty input\n\nRefs: #42\nSigned-off-by: Test <test@test.com>"; body != want {
+		t.Errorf("unexpected message %q, want %q", body, want)
+	}
+
+	// -issue wins over the branch; trailers: [] keeps only the sign-off
+	os.WriteFile(".devflow.yaml", []byte("commit:\n  trailers: []\n  signoff: true\n"), 0644)
+	git.SetPushOptions(PushOptions{NoTag: true, Issue: "7"})
+	os.WriteFile("b.go", []byte("package a\n"), 0644)
+	if _, err := git.Push("fix: other", ""); err != nil {
+		t.Fatal(err)
+	}
+	body, _ = RunCommandSilent("git", "log", "-1", "--format=%B")
+	if want := "fix: other\n\nSigned-off-by: Test <test@test.com>"; body != want {
+		t.Errorf("unexpected message %q, want %q", body, want)
+	}
+
+	exec.Command("git", "config", "--unset", "user.email").Run()
+	exec.Command("git", "config", "--global", "--unset", "user.email").Run()
+	os.WriteFile("c.go", []byte("package a\n"), 0644)
+	if _, err := git.Push("fix: third", ""); err == nil {
+		t.Error("expected the sign-off without user.email to fail")
+	}
+}
+
+func TestCoAuthorTrailer(t *testing.T) {
+	if got, err := CoAuthorTrailer(" Jane Doe <jane@example.com> "); err != nil || got != "Co-authored-by: Jane Doe <jane@example.com>" {
+		t.Errorf("unexpected trailer %q, %v", got, err)
+	}
+	for _, bad := range []string{"", "jane", "jane@example.com", "<jane@example.com>", "Jane <jane>", "Jane <a@b> extra"} {
+		if _, err := CoAuthorTrailer(bad); err == nil {
+			t.Errorf("expected %q to fail", bad)
+		}
+	}
+}
+
+func TestGitPushIdentityTrailers(t *testing.T) {
+	testResumeEnv(t)
+	defer testPushedRepo(t)()
+	os.WriteFile(".devflow.yaml", []byte("commit:\n  trailers: []\n  co_authors: [Jane Doe <jane@example.com>]\n"), 0644)
+
+	// A co-author given by name is completed from an earlier commit
+	git, _ := NewGit()
+	git.SetPushOptions(PushOptions{NoTag: true, SignOff: true, CoAuthors: []string{"test"}})
+	os.WriteFile("a.go", []byte("package a\n"), 0644)
+	if _, err := git.Push("feat: pair", ""); err != nil {
+		t.Fatal(err)
+	}
+	body, _ := RunCommandSilent("git", "log", "-1", "--format=%B")
+	if want := "feat: pair\n\nCo-authored-by: Jane Doe <jane@example.com>\nCo-authored-by: Test <test@test.com>\nSigned-off-by: Test <test@test.com>"; body != want {
+		t.Errorf("unexpected message %q, want %q", body, want)
+	}
+
+	git.SetPushOptions(PushOptions{NoTag: true, CoAuthors: []string{"nobody"}})
+	os.WriteFile("b.go", []byte("package a\n"), 0644)
+	if _, err := git.Push("feat: alone", ""); err == nil {
+		t.Error("expected an unknown co-author to fail")
+	}
+}
+
+func TestCommitTrailersMalformedConfig(t *testing.T) {
+	testResumeEnv(t)
+	defer testPushedRepo(t)()
+	os.WriteFile(".devflow.yaml", []byte("commit:\n  signoff true\n"), 0644)
+
+	git, _ := NewGit()
+	if _, err := git.commitTrailers(); err == nil {
+		t.Error("expected a malformed config to fail instead of dropping the sign-off")
+	}
+}
@@ -0,0 +1,70 @@
+package devflow
+
+import (
+	"embed"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// communityTexts holds the built-in community files, by base name
+//
+//go:embed community/*.md
+var communityTexts embed.FS
+
+// CommunityFiles are the files GenerateCommunityFiles writes, relative to
+// the project root
+var CommunityFiles = []string{
+	".github/ISSUE_TEMPLATE/bug_report.md",
+	".github/ISSUE_TEMPLATE/feature_request.md",
+	".github/PULL_REQUEST_TEMPLATE.md",
+	"CODE_OF_CONDUCT.md",
+	"CONTRIBUTING.md",
+}
+
+// GenerateCommunityFiles writes the issue and pull request templates, the
+// Code of Conduct and the contributing guide to targetDir, with {{name}}
+// placeholders replaced by values (the template built-ins). {{contact}},
+// where the Code of Conduct reports go, defaults to the author email or
+// URL. Files of customDir, when set, are rendered like a template over
+// the built-in ones: same path to replace one, any other path to add it.
+func GenerateCommunityFiles(targetDir, customDir string, values map[string]string) error {
+	merged := map[string]string{"contact": communityContact(values)}
+	for k, v := range values {
+		merged[k] = v
+	}
+
+	for _, name := range CommunityFiles {
+		text, err := communityTexts.ReadFile("community/" + path.Base(name))
+		if err != nil {
+			return err
+		}
+		dest := filepath.Join(targetDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return err
+		}
+		if err := writeGenerated(dest, []byte(ExpandTemplate(string(text), merged))); err != nil {
+			return err
+		}
+	}
+
+	if customDir == "" {
+		return nil
+	}
+	custom, err := LoadTemplate(customDir)
+	if err != nil {
+		return err
+	}
+	return custom.Render(targetDir, merged)
+}
+
+// communityContact returns the author email, else URL, else a generic
+// pointer to the maintainers
+func communityContact(values map[string]string) string {
+	for _, key := range []string{"author_email", "author_url"} {
+		if v := values[key]; v != "" {
+			return v
+		}
+	}
+	return "the contact listed in the project README"
+}
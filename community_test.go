@@ -0,0 +1,56 @@
+package devflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateCommunityFiles(t *testing.T) {
+	dir := t.TempDir()
+	values := map[string]string{"name": "widget", "module": "github.com/acme/widget", "license": "MIT", "author_email": "oss@acme.dev"}
+	if err := GenerateCommunityFiles(dir, "", values); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range CommunityFiles {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatalf("expected %s: %v", name, err)
+		}
+		if strings.Contains(string(data), "{{name}}") || strings.Contains(string(data), "{{contact}}") {
+			t.Errorf("%s has unexpanded placeholders:\n%s", name, data)
+		}
+	}
+	coc, _ := os.ReadFile(filepath.Join(dir, "CODE_OF_CONDUCT.md"))
+	if !strings.Contains(string(coc), "maintainers at oss@acme.dev") {
+		t.Errorf("expected the author email as contact:\n%s", coc)
+	}
+	bug, _ := os.ReadFile(filepath.Join(dir, ".github", "ISSUE_TEMPLATE", "bug_report.md"))
+	if !strings.Contains(string(bug), "go list -m github.com/acme/widget") {
+		t.Errorf("unexpected bug report template:\n%s", bug)
+	}
+
+	// The custom directory replaces and adds files
+	custom := t.TempDir()
+	os.WriteFile(filepath.Join(custom, "CONTRIBUTING.md"), []byte("# Contributing to {{name}} at {{contact}}\n"), 0644)
+	os.MkdirAll(filepath.Join(custom, ".github", "ISSUE_TEMPLATE"), 0755)
+	os.WriteFile(filepath.Join(custom, ".github", "ISSUE_TEMPLATE", "config.yml"), []byte("blank_issues_enabled: false\n"), 0644)
+	dir = t.TempDir()
+	if err := GenerateCommunityFiles(dir, custom, values); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "CONTRIBUTING.md")); string(data) != "# Contributing to widget at oss@acme.dev\n" {
+		t.Errorf("expected the custom CONTRIBUTING.md, got %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".github", "ISSUE_TEMPLATE", "config.yml")); err != nil {
+		t.Errorf("expected the added config.yml: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "CODE_OF_CONDUCT.md")); err != nil {
+		t.Errorf("expected the built-in CODE_OF_CONDUCT.md: %v", err)
+	}
+
+	if err := GenerateCommunityFiles(t.TempDir(), filepath.Join(custom, "missing"), values); err == nil {
+		t.Error("expected missing directory error")
+	}
+}
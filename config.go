@@ -0,0 +1,478 @@
+package devflow
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ConfigFileNames are the project config files, in lookup order
+var ConfigFileNames = []string{".devflow.yaml", ".devflow.yml", ".devflow.toml"}
+
+// Config holds settings loaded from a .devflow.yaml (or .devflow.toml)
+// file. Only a YAML subset is supported: nested maps by indentation, scalars,
+// inline lists ([a, b]) and block lists (- item). Keys are flattened with
+// dots, e.g.:
+//
+//	go:
+//	  proxy_fallback: true
+//
+// is read with c.Bool("go.proxy_fallback", false).
+type Config struct {
+	values  map[string]configValue
+	path    string   // project file the config was loaded from (empty if none)
+	sources []string // every file merged into the config, in order
+}
+
+// configValue is a single setting with its origin for error reporting
+type configValue struct {
+	scalar string
+	list   []string
+	isList bool
+	line   int
+	file   string
+}
+
+// GlobalConfigFile is the user-level config, relative to os.UserConfigDir.
+// devflow/config.toml is read instead when it is the only one.
+const GlobalConfigFile = "devflow/config.yaml"
+
+// NewConfig returns an empty config (all getters return their defaults)
+func NewConfig() *Config {
+	return &Config{values: make(map[string]configValue)}
+}
+
+// LoadConfig loads the config of the project in dir, merged in order:
+// the global config, the .devflow.yaml of each parent directory from the
+// outermost down (e.g. ~/work/.devflow.yaml for every project under
+// ~/work) and the project's own file. Later files override earlier ones
+// key by key. Missing files are not an error; with none the config is
+// empty. Parents are looked up to the home directory, or outside it to
+// the repository root, so a stray file higher up (say /tmp/.devflow.yaml)
+// cannot add hooks to the project.
+func LoadConfig(dir string) (*Config, error) {
+	c, err := LoadGlobalConfig()
+	if err != nil {
+		return nil, err
+	}
+	c.path = ""
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	top := configCeiling(abs)
+	var dirs []string
+	for d := abs; ; d = filepath.Dir(d) {
+		dirs = append(dirs, d)
+		if d == top || filepath.Dir(d) == d {
+			break
+		}
+	}
+	for i := len(dirs) - 1; i >= 0; i-- {
+		path := findConfigFile(dirs[i])
+		if path == "" {
+			continue
+		}
+		file, err := LoadConfigFile(path)
+		if err != nil {
+			return nil, err
+		}
+		c.merge(file)
+		if i == 0 {
+			c.path = path
+		}
+	}
+	return c, nil
+}
+
+// configCeiling returns the outermost directory LoadConfig reads for dir:
+// the home directory when dir is in it, else the root of the git
+// repository of dir, else dir itself
+func configCeiling(dir string) string {
+	if home, err := os.UserHomeDir(); err == nil {
+		if rel, err := filepath.Rel(home, dir); err == nil && !strings.HasPrefix(rel, "..") {
+			return home
+		}
+	}
+	for d := dir; ; d = filepath.Dir(d) {
+		if _, err := os.Stat(filepath.Join(d, ".git")); err == nil {
+			return d
+		}
+		if filepath.Dir(d) == d {
+			return dir
+		}
+	}
+}
+
+// findConfigFile returns the config file of dir, or "" if it has none
+func findConfigFile(dir string) string {
+	for _, name := range ConfigFileNames {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// merge sets the values of other over those of c
+func (c *Config) merge(other *Config) {
+	for key, v := range other.values {
+		c.values[key] = v
+	}
+	c.sources = append(c.sources, other.sources...)
+}
+
+// LoadGlobalConfig loads the user-level config. A missing file is not an
+// error and yields an empty config.
+func LoadGlobalConfig() (*Config, error) {
+	path, err := globalConfigPath()
+	if err != nil {
+		return NewConfig(), nil
+	}
+	if _, err := os.Stat(path); err != nil {
+		path = strings.TrimSuffix(path, ".yaml") + ".toml"
+		if _, err := os.Stat(path); err != nil {
+			return NewConfig(), nil
+		}
+	}
+	return LoadConfigFile(path)
+}
+
+// globalConfigPath returns the location of the global config file
+func globalConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, GlobalConfigFile), nil
+}
+
+// LoadConfigFile parses a single config file, as TOML when its name ends
+// in .toml
+func LoadConfigFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	parse := ParseConfig
+	if strings.HasSuffix(path, ".toml") {
+		parse = ParseTOMLConfig
+	}
+	c, err := parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for key, v := range c.values {
+		v.file = path
+		c.values[key] = v
+	}
+	c.path = path
+	c.sources = []string{path}
+	return c, nil
+}
+
+// ParseConfig parses config content
+func ParseConfig(content string) (*Config, error) {
+	c := NewConfig()
+
+	type level struct {
+		indent int
+		key    string
+	}
+	var stack []level
+	lastKey := ""
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		raw := stripConfigComment(scanner.Text())
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+		indent := len(raw) - len(strings.TrimLeft(raw, " "))
+		line := strings.TrimSpace(raw)
+
+		// Block list item belongs to the last key that had no scalar value
+		if strings.HasPrefix(line, "- ") || line == "-" {
+			v, ok := c.values[lastKey]
+			if lastKey == "" || !ok || (v.scalar != "" && !v.isList) {
+				return nil, fmt.Errorf("line %d: list item without key", lineNum)
+			}
+			v.isList = true
+			v.list = append(v.list, unquoteConfig(strings.TrimSpace(strings.TrimPrefix(line, "-"))))
+			c.values[lastKey] = v
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("line %d: expected 'key: value'", lineNum)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		fullKey := key
+		if len(stack) > 0 {
+			fullKey = stack[len(stack)-1].key + "." + key
+		}
+
+		v := configValue{line: lineNum}
+		switch {
+		case value == "":
+			// Section or block list, decided by the following lines
+			stack = append(stack, level{indent: indent, key: fullKey})
+		case strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]"):
+			v.isList = true
+			for _, item := range strings.Split(value[1:len(value)-1], ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					v.list = append(v.list, unquoteConfig(item))
+				}
+			}
+		default:
+			v.scalar = unquoteConfig(value)
+		}
+		c.values[fullKey] = v
+		lastKey = fullKey
+	}
+
+	// Drop section placeholders that only hold nested keys
+	for key, v := range c.values {
+		if v.scalar == "" && !v.isList && c.hasChildren(key) {
+			delete(c.values, key)
+		}
+	}
+	return c, scanner.Err()
+}
+
+// ParseTOMLConfig parses config content in a TOML subset: [table] and
+// [table.sub] headers, key = value with dotted keys, quoted strings,
+// bools, numbers and arrays of scalars (also over several lines). Keys
+// are flattened with dots like those of ParseConfig.
+func ParseTOMLConfig(content string) (*Config, error) {
+	c := NewConfig()
+	table := ""
+	lines := strings.Split(content, "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNum := i + 1
+		line := strings.TrimSpace(stripConfigComment(lines[i]))
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			if strings.HasPrefix(line, "[[") || !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: expected [table]", lineNum)
+			}
+			table = tomlKey(line[1 : len(line)-1])
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("line %d: expected 'key = value'", lineNum)
+		}
+		fullKey := tomlKey(key)
+		if table != "" {
+			fullKey = table + "." + fullKey
+		}
+		value = strings.TrimSpace(value)
+		// An array may continue on the following lines
+		for strings.HasPrefix(value, "[") && !strings.HasSuffix(value, "]") && i+1 < len(lines) {
+			i++
+			value += " " + strings.TrimSpace(stripConfigComment(lines[i]))
+		}
+
+		v := configValue{line: lineNum}
+		switch {
+		case value == "":
+			return nil, fmt.Errorf("line %d: missing value of %s", lineNum, fullKey)
+		case strings.HasPrefix(value, "{"):
+			return nil, fmt.Errorf("line %d: inline tables are not supported, use [%s]", lineNum, fullKey)
+		case strings.HasPrefix(value, "["):
+			if !strings.HasSuffix(value, "]") {
+				return nil, fmt.Errorf("line %d: unterminated array", lineNum)
+			}
+			v.isList = true
+			for _, item := range strings.Split(value[1:len(value)-1], ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					v.list = append(v.list, unquoteConfig(item))
+				}
+			}
+		default:
+			v.scalar = unquoteConfig(value)
+		}
+		c.values[fullKey] = v
+	}
+	return c, nil
+}
+
+// tomlKey joins the parts of a dotted, possibly quoted TOML key
+func tomlKey(key string) string {
+	parts := strings.Split(strings.TrimSpace(key), ".")
+	for i, p := range parts {
+		parts[i] = unquoteConfig(strings.TrimSpace(p))
+	}
+	return strings.Join(parts, ".")
+}
+
+// Path returns the project file the config was loaded from (empty if none)
+func (c *Config) Path() string {
+	return c.path
+}
+
+// Sources returns every file merged into the config, lowest precedence first
+func (c *Config) Sources() []string {
+	return c.sources
+}
+
+// Source returns the file that set key, empty for unset keys and values
+// that did not come from a file (Set, ParseConfig)
+func (c *Config) Source(key string) string {
+	return c.values[key].file
+}
+
+// Has reports whether key is set
+func (c *Config) Has(key string) bool {
+	_, ok := c.values[key]
+	return ok
+}
+
+// Set overrides a scalar value (e.g., from a command line flag)
+func (c *Config) Set(key, value string) {
+	c.values[key] = configValue{scalar: value}
+}
+
+// String returns the scalar value of key or def
+func (c *Config) String(key, def string) string {
+	if v, ok := c.values[key]; ok && !v.isList {
+		return v.scalar
+	}
+	return def
+}
+
+// Bool returns the boolean value of key or def
+func (c *Config) Bool(key string, def bool) bool {
+	v, ok := c.values[key]
+	if !ok {
+		return def
+	}
+	b, err := strconv.ParseBool(v.scalar)
+	if err != nil {
+		switch strings.ToLower(v.scalar) {
+		case "yes", "on":
+			return true
+		case "no", "off":
+			return false
+		}
+		return def
+	}
+	return b
+}
+
+// Int returns the integer value of key or def
+func (c *Config) Int(key string, def int) int {
+	v, ok := c.values[key]
+	if !ok {
+		return def
+	}
+	n, err := strconv.Atoi(v.scalar)
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+// List returns the list value of key. A scalar is returned as a one-item list.
+func (c *Config) List(key string) []string {
+	v, ok := c.values[key]
+	if !ok {
+		return nil
+	}
+	if v.isList {
+		return v.list
+	}
+	if v.scalar == "" {
+		return nil
+	}
+	return []string{v.scalar}
+}
+
+// Keys returns the names directly under prefix in file order, e.g. for
+// "variables" the keys of each variable declared in that section
+func (c *Config) Keys(prefix string) []string {
+	type entry struct {
+		name string
+		line int
+	}
+	seen := make(map[string]int)
+	for key, v := range c.values {
+		rest, ok := strings.CutPrefix(key, prefix+".")
+		if !ok {
+			continue
+		}
+		name, _, _ := strings.Cut(rest, ".")
+		if line, ok := seen[name]; !ok || v.line < line {
+			seen[name] = v.line
+		}
+	}
+
+	entries := make([]entry, 0, len(seen))
+	for name, line := range seen {
+		entries = append(entries, entry{name, line})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].line != entries[j].line {
+			return entries[i].line < entries[j].line
+		}
+		return entries[i].name < entries[j].name
+	})
+
+	names := make([]string, len(entries))
+	for i, e := range entries {
+		names[i] = e.name
+	}
+	return names
+}
+
+// hasChildren reports whether any key is nested under prefix
+func (c *Config) hasChildren(prefix string) bool {
+	for key := range c.values {
+		if strings.HasPrefix(key, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// stripConfigComment removes a trailing # comment outside quotes
+func stripConfigComment(line string) string {
+	inQuote := rune(0)
+	for i, r := range line {
+		switch {
+		case inQuote != 0:
+			if r == inQuote {
+				inQuote = 0
+			}
+		case r == '"' || r == '\'':
+			inQuote = r
+		case r == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return strings.TrimRight(line[:i], " \t")
+		}
+	}
+	return line
+}
+
+func unquoteConfig(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,450 @@
+package devflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config value types of the schema
+const (
+	ConfigString   = "string"
+	ConfigBool     = "bool"
+	ConfigInt      = "int"
+	ConfigNumber   = "number" // e.g. 70 or 70%
+	ConfigDuration = "duration"
+	ConfigList     = "list"
+)
+
+// ConfigKey describes one setting of .devflow.yaml. Keys ending in ".*"
+// match any name under the prefix (e.g. projects.*).
+type ConfigKey struct {
+	Key         string
+	Type        string
+	Default     string
+	Values      []string // Allowed values, empty for any
+	Global      bool     // Only read from the global config
+	Description string
+}
+
+// ConfigSchema lists every setting devflow reads, in docs/CONFIG.md order
+var ConfigSchema = []ConfigKey{
+	{Key: "go.proxy_fallback", Type: ConfigBool, Default: "false", Description: "Retry module downloads in direct mode when the Go proxy is down"},
+	{Key: "dependents.parallel", Type: ConfigNumber, Default: "4", Description: "Dependent modules gopush updates at once"},
+	{Key: "license.spdx", Type: ConfigString, Description: "SPDX identifier for license headers (default: detected from LICENSE)"},
+	{Key: "license.holder", Type: ConfigString, Description: "Copyright holder in license headers (default: git user.name)"},
+	{Key: "license.header_template", Type: ConfigString, Description: "File with the license header template"},
+	{Key: "license.check", Type: ConfigBool, Default: "false", Description: "gotest fails when a .go file lacks the expected header"},
+	{Key: "license.exclude", Type: ConfigList, Description: "Paths or globs skipped by license headers"},
+	{Key: "license.update_year", Type: ConfigBool, Default: "true", Description: "push extends the copyright year on the first release of a new year"},
+	{Key: "notices.enabled", Type: ConfigBool, Default: "false", Description: "gopush generates THIRD_PARTY_NOTICES.md"},
+	{Key: "vet.enable", Type: ConfigList, Description: "Run only these go vet analyzers"},
+	{Key: "vet.analyzers", Type: ConfigList, Description: "Extra go/analysis analyzers built into a -vettool"},
+	{Key: "vet.disable", Type: ConfigList, Description: "go vet analyzers turned off in gotest"},
+	{Key: "vet.ignore", Type: ConfigList, Default: "[possible misuse of unsafe.Pointer]", Description: "Vet messages (substrings) that do not fail gotest"},
+	{Key: "test.race", Type: ConfigString, Default: "auto", Values: []string{"auto", "on", "off"}, Description: "Race detector mode for gotest"},
+	{Key: "test.budget", Type: ConfigDuration, Description: "Wall-time budget for gotest test runs, e.g. 10m"},
+	{Key: "coverage.threshold", Type: ConfigNumber, Description: "Minimum coverage percentage of every package"},
+	{Key: "coverage.packages", Type: ConfigList, Description: "Per-package minimums as dir=percent"},
+	{Key: "test.skip.coverage", Type: ConfigList, Description: "Package dirs left out of the coverage average"},
+	{Key: "test.skip.race", Type: ConfigList, Description: "Package dirs tested without -race"},
+	{Key: "test.skip.wasm", Type: ConfigList, Description: "Package dirs excluded from WASM tests"},
+	{Key: "test.skip.leaks", Type: ConfigList, Description: "Package dirs excluded from the goroutine leak check"},
+	{Key: "fuzz.time", Type: ConfigDuration, Default: "10s", Description: "Fuzzing time per target of gotest fuzz"},
+	{Key: "vuln.enabled", Type: ConfigBool, Default: "false", Description: "gotest runs govulncheck alongside the tests"},
+	{Key: "vuln.block", Type: ConfigString, Default: VulnBlockOff, Values: []string{VulnBlockOff, VulnBlockCalled, VulnBlockAny}, Description: "Vulnerabilities that stop gopush"},
+	{Key: "vuln.db", Type: ConfigString, Description: "Vulnerability database URL (govulncheck -db)"},
+	{Key: "lint.enabled", Type: ConfigBool, Default: "false", Description: "gotest runs a linter alongside vet and fails on its findings"},
+	{Key: "lint.tool", Type: ConfigString, Default: LintAuto, Values: []string{LintAuto, LintStaticcheck, LintGolangci}, Description: "Linter of the lint phase"},
+	{Key: "lint.install", Type: ConfigBool, Default: "true", Description: "Install the linter with go install when it is missing"},
+	{Key: "leaks.enabled", Type: ConfigBool, Default: "false", Description: "gotest fails when tests leave goroutines running"},
+	{Key: "leaks.ignore", Type: ConfigList, Description: "Functions (stack substrings) of goroutines that are not leaks"},
+	{Key: "cache.git_state", Type: ConfigString, Default: GitStateAuto, Values: []string{GitStateAuto, GitStateFull, GitStateIncremental}, Description: "How the test cache hashes uncommitted changes: the full diff or the changed files"},
+	{Key: "cache.remote.url", Type: ConfigString, Description: "Shared test cache: https://... or s3://bucket/prefix"},
+	{Key: "cache.remote.region", Type: ConfigString, Description: "S3 region of the remote test cache (default: AWS_REGION or us-east-1)"},
+	{Key: "cache.remote.endpoint", Type: ConfigString, Description: "Endpoint of an S3-compatible store"},
+	{Key: "tag.notes", Type: ConfigString, Default: "changelog", Values: []string{"changelog", "commits", "off"}, Description: "Annotated tag message source"},
+	{Key: "changelog.unreleased", Type: ConfigBool, Default: "false", Description: "Record each push in the CHANGELOG.md Unreleased section"},
+	{Key: "commit.trailers", Type: ConfigList, Default: "[Refs: {{issue}}]", Description: "Trailers appended to push commits; {{issue}} is the issue of the push"},
+	{Key: "commit.issue_from_branch", Type: ConfigBool, Default: "false", Description: "Take the issue of a push from the branch name (feat/42-login)"},
+	{Key: "commit.signoff", Type: ConfigBool, Default: "false", Description: "Append Signed-off-by with the git user.name and user.email (DCO)"},
+	{Key: "commit.co_authors", Type: ConfigList, Description: "Co-authored-by identities appended to push and gonew commits"},
+	{Key: "release.schedule", Type: ConfigString, Default: ScheduleWeekly, Values: []string{ScheduleDaily, ScheduleWeekly, ScheduleMonthly}, Description: "Period of scheduled releases"},
+	{Key: "release.create", Type: ConfigBool, Default: "true", Description: "gopush creates a GitHub release for the new tag"},
+	{Key: "release.assets", Type: ConfigList, Description: "Files (globs) attached to the GitHub release"},
+	{Key: "release.mirror.gitlab", Type: ConfigString, Description: "GitLab project (group/name) that also gets the tag and release"},
+	{Key: "release.mirror.host", Type: ConfigString, Default: "gitlab.com", Description: "Host of the GitLab mirror"},
+	{Key: "release.mirror.remote", Type: ConfigString, Description: "Git remote or URL the mirror is pushed to"},
+	{Key: "release.bump", Type: ConfigString, Default: "patch", Values: []string{"patch", "minor", "major"}, Description: "Version bump of scheduled releases"},
+	{Key: "pr.auto_merge", Type: ConfigString, Default: "off", Values: []string{"off", MergeSquash, MergeCommit, MergeRebase}, Description: "Merge method gopush -pr enables auto-merge with"},
+	{Key: "build.targets", Type: ConfigList, Description: "GOOS/GOARCH targets of gorelease"},
+	{Key: "build.dir", Type: ConfigString, Default: "dist", Description: "Output directory of gorelease"},
+	{Key: "build.version_var", Type: ConfigString, Default: "main.version", Description: "Variable set to the version tag with -ldflags -X"},
+	{Key: "build.ldflags", Type: ConfigString, Description: "Extra -ldflags of release builds"},
+	{Key: "hooks.before_test.*", Type: ConfigString, Description: "gopush step run before the tests: shell command or @function"},
+	{Key: "hooks.before_commit.*", Type: ConfigString, Description: "push step run before the changes are committed"},
+	{Key: "hooks.before_push.*", Type: ConfigString, Description: "push step run after the commit and tag, before pushing"},
+	{Key: "hooks.after_tag.*", Type: ConfigString, Description: "gopush step run after the new tag is pushed"},
+	{Key: "network.probe", Type: ConfigBool, Default: "true", Description: "Probe the provider host before network steps and go offline at once when it is unreachable"},
+	{Key: "network.probe_timeout", Type: ConfigDuration, Default: "2s", Description: "Timeout of each step (DNS, HTTPS) of the network probe"},
+	{Key: "gonew.visibility", Type: ConfigString, Default: "public", Values: []string{"public", "private"}, Description: "Visibility of the remotes gonew creates"},
+	{Key: "gonew.license", Type: ConfigString, Default: "MIT", Description: "SPDX license of new projects"},
+	{Key: "gonew.module_prefix", Type: ConfigString, Description: "Module path prefix of new projects (default: <host>/<owner>)"},
+	{Key: "gonew.remote_protocol", Type: ConfigString, Default: ProtocolAuto, Values: []string{ProtocolAuto, ProtocolHTTPS, ProtocolSSH}, Description: "Protocol of the origin URL of new projects"},
+	{Key: "gonew.readme_languages", Type: ConfigList, Description: "Languages of the README.<lang>.md files of new projects, e.g. [es, pt]"},
+	{Key: "gonew.ci", Type: ConfigBool, Default: "false", Description: "Write a GitHub Actions CI workflow to new projects"},
+	{Key: "gonew.dependency_bot", Type: ConfigString, Default: DependencyBotOff, Values: []string{DependencyBotOff, DependencyBotDependabot, DependencyBotRenovate}, Description: "Dependency update bot configured in new projects"},
+	{Key: "gonew.dependency_schedule", Type: ConfigString, Default: "weekly", Values: DependencySchedules, Description: "Update interval of the dependency bot"},
+	{Key: "gonew.community", Type: ConfigBool, Default: "false", Description: "Write issue and PR templates, CODE_OF_CONDUCT.md and CONTRIBUTING.md to new projects"},
+	{Key: "gonew.community_dir", Type: ConfigString, Description: "Directory of files replacing or adding to the built-in community files"},
+	{Key: "gonew.default_branch", Type: ConfigString, Description: "Initial branch of new projects (default: git init.defaultBranch, else main)"},
+	{Key: "gonew.rollback", Type: ConfigString, Default: RollbackOff, Values: []string{RollbackOff, RollbackLocal, RollbackRemote}, Description: "What a failed create undoes instead of keeping it for gonew resume"},
+	{Key: "gonew.repo.merge", Type: ConfigList, Description: "Pull request merges allowed on new GitHub repositories, e.g. [squash] for squash-only"},
+	{Key: "gonew.repo.protect_branch", Type: ConfigBool, Default: "false", Description: "Protect the default branch of new GitHub repositories"},
+	{Key: "gonew.repo.issues", Type: ConfigBool, Description: "Enable (true) or disable (false) the issue tracker of new GitHub repositories"},
+	{Key: "gonew.repo.wiki", Type: ConfigBool, Description: "Enable (true) or disable (false) the wiki of new GitHub repositories"},
+	{Key: "author.name", Type: ConfigString, Description: "Author name published by gonew (default: provider profile, else git user.name)"},
+	{Key: "author.email", Type: ConfigString, Description: "Author email published by gonew (default: provider profile, else git user.email)"},
+	{Key: "author.url", Type: ConfigString, Description: "Author website published by gonew (default: provider profile)"},
+	{Key: "backup.command", Type: ConfigString, Description: "Backup command when DEV_BACKUP is not set"},
+	{Key: "backup.mode", Type: ConfigString, Default: BackupModeCommand, Values: []string{BackupModeCommand, BackupModeGit}, Description: "Run the backup command, or commit and push backup.git_dirs"},
+	{Key: "backup.git_dirs", Type: ConfigList, Description: "Git repositories committed and pushed by the git backup mode"},
+	{Key: "dotfiles.dir", Type: ConfigString, Default: "~/.dotfiles", Global: true, Description: "Repository of devflow dotfiles"},
+	{Key: "dotfiles.strategy", Type: ConfigString, Default: DotfilesSymlink, Values: []string{DotfilesSymlink, DotfilesCopy}, Global: true, Description: "How tracked dotfiles reach the home directory"},
+	{Key: "provider.name", Type: ConfigString, Default: "github", Values: []string{"github", "gitlab", "gitea"}, Global: true, Description: "Provider where gonew creates remotes"},
+	{Key: "provider.host", Type: ConfigString, Global: true, Description: "Self-hosted GitLab/Gitea host"},
+	{Key: "projects.*", Type: ConfigString, Global: true, Description: "Directory of a project alias for -p"},
+	{Key: "template.vars.*", Type: ConfigString, Global: true, Description: "Value of a gonew template variable"},
+	{Key: "metrics.enabled", Type: ConfigBool, Default: "false", Global: true, Description: "Record command runs in the local metrics log"},
+	{Key: "tracing.endpoint", Type: ConfigString, Global: true, Description: "OTLP/HTTP collector the workflow traces are exported to, e.g. http://localhost:4318"},
+	{Key: "tracing.headers.*", Type: ConfigString, Global: true, Description: "Header sent with the exported traces, e.g. an API key"},
+	{Key: "log.level", Type: ConfigString, Default: "info", Values: []string{"debug", "info", "warn", "error"}, Global: true, Description: "Minimum level of the messages the commands log"},
+	{Key: "log.format", Type: ConfigString, Default: LogFormatText, Values: []string{LogFormatText, LogFormatJSON}, Global: true, Description: "Format of the logged messages"},
+	{Key: "output.accessible", Type: ConfigBool, Default: "false", Global: true, Description: "Screen-reader friendly output: words instead of emoji, no colors, one result per line"},
+	{Key: "airgap.enabled", Type: ConfigBool, Default: "false", Description: "Air-gapped/enterprise mode"},
+	{Key: "airgap.goproxy", Type: ConfigString, Description: "Internal module proxy exported as GOPROXY"},
+	{Key: "airgap.gosumdb", Type: ConfigString, Default: "off", Description: "Internal checksum database exported as GOSUMDB"},
+	{Key: "airgap.github_host", Type: ConfigString, Description: "GitHub Enterprise host exported as GH_HOST"},
+}
+
+// ConfigIssue is a problem found by LintConfig
+type ConfigIssue struct {
+	File    string
+	Line    int
+	Key     string
+	Message string
+}
+
+// String returns e.g. ".devflow.yaml:3: test.race: invalid value "yes" (want auto, on, off)"
+func (i ConfigIssue) String() string {
+	if i.File == "" {
+		return fmt.Sprintf("line %d: %s: %s", i.Line, i.Key, i.Message)
+	}
+	return fmt.Sprintf("%s:%d: %s: %s", i.File, i.Line, i.Key, i.Message)
+}
+
+// LookupConfigKey returns the schema entry of key
+func LookupConfigKey(key string) (ConfigKey, bool) {
+	for _, k := range ConfigSchema {
+		if k.Key == key {
+			return k, true
+		}
+		if prefix, ok := strings.CutSuffix(k.Key, "*"); ok && strings.HasPrefix(key, prefix) && !strings.Contains(key[len(prefix):], ".") {
+			return k, true
+		}
+	}
+	return ConfigKey{}, false
+}
+
+// LintConfig checks a single config file against ConfigSchema: unknown
+// keys, values of the wrong type and global-only keys in a project file.
+// Issues are in line order.
+func LintConfig(c *Config, global bool) []ConfigIssue {
+	keys := make([]string, 0, len(c.values))
+	for key := range c.values {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		li, lj := c.values[keys[i]].line, c.values[keys[j]].line
+		if li != lj {
+			return li < lj
+		}
+		return keys[i] < keys[j]
+	})
+
+	var issues []ConfigIssue
+	for _, key := range keys {
+		v := c.values[key]
+		issue := ConfigIssue{File: c.path, Line: v.line, Key: key}
+		k, ok := LookupConfigKey(key)
+		switch {
+		case !ok && !v.isList && v.scalar == "" && isConfigSection(key):
+			// Empty section, e.g. "cache:" with every setting commented out
+		case !ok:
+			issue.Message = "unknown key"
+			if s := suggestConfigKey(key); s != "" {
+				issue.Message += fmt.Sprintf(" (did you mean %s?)", s)
+			}
+		case k.Global && !global:
+			issue.Message = "only read from the global config (" + GlobalConfigFile + ")"
+		default:
+			issue.Message = checkConfigValue(k, v)
+		}
+		if issue.Message != "" {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// LintConfigDir lints every file LoadConfig merges for dir: the global
+// config, parent directory files and the project file. Returns the files
+// checked; a file that does not parse is an error.
+func LintConfigDir(dir string) ([]string, []ConfigIssue, error) {
+	merged, err := LoadConfig(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	globalPath, _ := globalConfigPath()
+	var issues []ConfigIssue
+	for _, path := range merged.Sources() {
+		c, err := LoadConfigFile(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		issues = append(issues, LintConfig(c, path == globalPath)...)
+	}
+	return merged.Sources(), issues, nil
+}
+
+// isConfigSection reports whether schema keys are nested under key
+func isConfigSection(key string) bool {
+	for _, k := range ConfigSchema {
+		if strings.HasPrefix(k.Key, key+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// checkConfigValue returns why v is not a valid value of k, or ""
+func checkConfigValue(k ConfigKey, v configValue) string {
+	if k.Type == ConfigList {
+		return ""
+	}
+	if v.isList {
+		return fmt.Sprintf("expected %s, got a list", k.Type)
+	}
+	s := v.scalar
+	var bad bool
+	switch k.Type {
+	case ConfigBool:
+		_, err := strconv.ParseBool(s)
+		bad = err != nil && !slices.Contains([]string{"yes", "no", "on", "off"}, strings.ToLower(s))
+	case ConfigInt:
+		_, err := strconv.Atoi(s)
+		bad = err != nil
+	case ConfigNumber:
+		_, err := parsePercent(s)
+		bad = err != nil
+	case ConfigDuration:
+		_, err := time.ParseDuration(s)
+		bad = err != nil
+	}
+	if bad {
+		return fmt.Sprintf("invalid %s %q", k.Type, s)
+	}
+	if len(k.Values) > 0 && !slices.Contains(k.Values, s) {
+		return fmt.Sprintf("invalid value %q (want %s)", s, strings.Join(k.Values, ", "))
+	}
+	return ""
+}
+
+// suggestConfigKey returns the schema key with the same last segment or
+// section as key, for typos like "tests.race" or "test.rac"
+func suggestConfigKey(key string) string {
+	section, _, _ := strings.Cut(key, ".")
+	name := key[strings.LastIndex(key, ".")+1:]
+	for _, k := range ConfigSchema {
+		if strings.HasSuffix(k.Key, "."+name) && !strings.HasSuffix(k.Key, "*") {
+			return k.Key
+		}
+	}
+	for _, k := range ConfigSchema {
+		kSection, _, _ := strings.Cut(k.Key, ".")
+		if kSection == section && !strings.HasSuffix(k.Key, "*") && strings.HasPrefix(k.Key[len(kSection)+1:], name[:min(len(name), 3)]) {
+			return k.Key
+		}
+	}
+	return ""
+}
+
+// ConfigJSONSchema returns ConfigSchema as a JSON Schema document for
+// editors with YAML schema support
+func ConfigJSONSchema() ([]byte, error) {
+	root := map[string]any{
+		"$schema":     "https://json-schema.org/draft/2020-12/schema",
+		"title":       "devflow config",
+		"type":        "object",
+		"description": "Settings of .devflow.yaml and " + GlobalConfigFile,
+	}
+	root["properties"] = jsonSchemaProperties("")
+	data, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// jsonSchemaProperties returns the nested properties of the keys under prefix
+func jsonSchemaProperties(prefix string) map[string]any {
+	props := make(map[string]any)
+	for _, k := range ConfigSchema {
+		rest, ok := strings.CutPrefix(k.Key, prefix)
+		if !ok {
+			continue
+		}
+		name, sub, nested := strings.Cut(rest, ".")
+		if _, done := props[name]; done {
+			continue
+		}
+		if nested && sub == "*" {
+			props[name] = map[string]any{"type": "object", "additionalProperties": jsonSchemaType(k)}
+		} else if nested {
+			props[name] = map[string]any{"type": "object", "additionalProperties": false, "properties": jsonSchemaProperties(prefix + name + ".")}
+		} else {
+			props[name] = jsonSchemaType(k)
+		}
+	}
+	return props
+}
+
+func jsonSchemaType(k ConfigKey) map[string]any {
+	s := map[string]any{"description": k.Description}
+	switch k.Type {
+	case ConfigBool:
+		s["type"] = "boolean"
+	case ConfigInt:
+		s["type"] = "integer"
+	case ConfigNumber:
+		s["type"] = []string{"number", "string"}
+	case ConfigList:
+		s["type"] = []string{"array", "string"}
+		s["items"] = map[string]any{"type": "string"}
+	default:
+		s["type"] = "string"
+	}
+	if len(k.Values) > 0 {
+		s["enum"] = k.Values
+	}
+	return s
+}
+
+// DefaultConfigFile returns a config file with every setting commented out
+// at its default, grouped by section. global selects the global-only keys
+// instead of the project ones.
+func DefaultConfigFile(global bool) string {
+	var b strings.Builder
+	if global {
+		fmt.Fprintf(&b, "# devflow global config (%s). Projects override these settings.\n", GlobalConfigFile)
+	} else {
+		b.WriteString("# devflow project config. Every setting is optional; uncomment to change one.\n")
+	}
+	b.WriteString("# See https://github.com/tinywasm/devflow/blob/main/docs/CONFIG.md\n")
+
+	// Keys of a section are written together, sections in schema order
+	var sections []string
+	bySection := make(map[string][]ConfigKey)
+	for _, k := range ConfigSchema {
+		if k.Global && !global {
+			continue
+		}
+		section, _, _ := strings.Cut(k.Key, ".")
+		if _, ok := bySection[section]; !ok {
+			sections = append(sections, section)
+		}
+		bySection[section] = append(bySection[section], k)
+	}
+
+	var ordered []ConfigKey
+	for _, section := range sections {
+		ordered = append(ordered, bySection[section]...)
+	}
+
+	var path []string
+	for _, k := range ordered {
+		parts := strings.Split(k.Key, ".")
+		common := 0
+		for common < len(path) && common < len(parts)-1 && path[common] == parts[common] {
+			common++
+		}
+		if common == 0 {
+			b.WriteString("\n")
+		}
+		for i := common; i < len(parts)-1; i++ {
+			fmt.Fprintf(&b, "# %s%s:\n", strings.Repeat("  ", i), parts[i])
+		}
+		path = parts[:len(parts)-1]
+
+		indent := strings.Repeat("  ", len(parts)-1)
+		name := parts[len(parts)-1]
+		if name == "*" {
+			name = "name"
+		}
+		comment := k.Description
+		if len(k.Values) > 0 {
+			comment += " (" + strings.Join(k.Values, ", ") + ")"
+		}
+		value := k.Default
+		if value == "" && k.Type == ConfigList {
+			value = "[]"
+		}
+		if value != "" {
+			value = " " + value
+		}
+		fmt.Fprintf(&b, "# %s%s:%s  # %s\n", indent, name, value, comment)
+	}
+	return b.String()
+}
+
+// ConfigInitOptions configures InitConfig
+type ConfigInitOptions struct {
+	Global bool // Write the global config instead of dir/.devflow.yaml
+	Force  bool // Replace an existing file
+	DryRun bool // Print the write without doing it
+}
+
+// InitConfig writes the commented default config to dir/.devflow.yaml (or
+// the project's existing .devflow.yml) or to the global config file.
+// Returns the path written.
+func InitConfig(dir string, opts ConfigInitOptions) (string, error) {
+	if opts.DryRun {
+		defer startDryRun()()
+	}
+	path := filepath.Join(dir, ConfigFileNames[0])
+	if opts.Global {
+		var err error
+		if path, err = globalConfigPath(); err != nil {
+			return "", err
+		}
+	} else if existing := findConfigFile(dir); strings.HasSuffix(existing, ".toml") {
+		return existing, fmt.Errorf("%s already exists (the default config is written as YAML)", existing)
+	} else if existing != "" {
+		path = existing
+	}
+	if _, err := os.Stat(path); err == nil && !opts.Force {
+		return path, fmt.Errorf("%s already exists (use -force to overwrite)", path)
+	}
+	if opts.Global && !DryRunActive() {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return "", err
+		}
+	}
+	return path, writeUnlessDryRun(path, []byte(DefaultConfigFile(opts.Global)))
+}
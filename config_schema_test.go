@@ -0,0 +1,95 @@
+package devflow
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestLintConfig(t *testing.T) {
+	c, _ := ParseConfig("test:\n  race: yes\n  budget: 5m\n  skip:\n    race: [a]\ncoverage:\n  threshold: [70]\nrelease:\n  bumb: minor\nprojects:\n  api: ~/api\nleaks:\n  enabled: maybe\n")
+	var got []string
+	for _, issue := range LintConfig(c, false) {
+		got = append(got, issue.String())
+	}
+	want := []string{
+		`line 2: test.race: invalid value "yes" (want auto, on, off)`,
+		`line 7: coverage.threshold: expected number, got a list`,
+		`line 9: release.bumb: unknown key (did you mean release.bump?)`,
+		`line 11: projects.api: only read from the global config (devflow/config.yaml)`,
+		`line 13: leaks.enabled: invalid bool "maybe"`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if issues := LintConfig(c, true); len(issues) != 4 {
+		t.Errorf("global config should accept projects.*: %v", issues)
+	}
+}
+
+func TestDefaultConfigFile(t *testing.T) {
+	for _, global := range []bool{false, true} {
+		content := DefaultConfigFile(global)
+		// Uncomment sections and the settings that have a default
+		content = regexp.MustCompile(`(?m)^#.*:  #.*$`).ReplaceAllString(content, "")
+		uncommented := regexp.MustCompile(`(?m)^# ( *[a-z_]+:)`).ReplaceAllString(content, "$1")
+		c, err := ParseConfig(uncommented)
+		if err != nil {
+			t.Fatalf("uncommented default file does not parse: %v\n%s", err, uncommented)
+		}
+		if issues := LintConfig(c, global); len(issues) != 0 {
+			t.Errorf("default file has issues: %v", issues)
+		}
+		if c.String("release.schedule", "") != ScheduleWeekly || c.Has("provider.name") != global {
+			t.Errorf("unexpected defaults (global %v):\n%s", global, content)
+		}
+	}
+}
+
+func TestInitConfig(t *testing.T) {
+	dir := t.TempDir()
+	path, err := InitConfig(dir, ConfigInitOptions{})
+	if err != nil || path != filepath.Join(dir, ".devflow.yaml") {
+		t.Fatalf("InitConfig = %q, %v", path, err)
+	}
+	if _, err := InitConfig(dir, ConfigInitOptions{}); err == nil {
+		t.Error("expected error for existing file")
+	}
+	os.WriteFile(path, []byte("bad"), 0644)
+	if _, err := InitConfig(dir, ConfigInitOptions{Force: true}); err != nil {
+		t.Fatal(err)
+	}
+	if files, issues, err := LintConfigDir(dir); err != nil || len(issues) != 0 || files[len(files)-1] != path {
+		t.Errorf("LintConfigDir = %v, %v, %v", files, issues, err)
+	}
+}
+
+func TestConfigSchemaDocs(t *testing.T) {
+	docs, err := os.ReadFile("docs/CONFIG.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	documented := make(map[string]bool)
+	for _, m := range regexp.MustCompile("(?m)^\\| `([a-z_.<>]+)` \\|").FindAllStringSubmatch(string(docs), -1) {
+		documented[strings.ReplaceAll(strings.ReplaceAll(m[1], "<alias>", "*"), "<name>", "*")] = true
+	}
+	for _, k := range ConfigSchema {
+		if !documented[k.Key] {
+			t.Errorf("%s is missing from docs/CONFIG.md", k.Key)
+		}
+		delete(documented, k.Key)
+	}
+	for key := range documented {
+		t.Errorf("%s is documented but not in ConfigSchema", key)
+	}
+
+	data, err := ConfigJSONSchema()
+	var schema map[string]any
+	if err != nil || json.Unmarshal(data, &schema) != nil {
+		t.Fatalf("invalid JSON schema: %v", err)
+	}
+}
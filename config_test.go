@@ -0,0 +1,204 @@
+package devflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	content := `# devflow settings
+go:
+  proxy_fallback: true   # allow direct mode
+  retries: 5
+vet:
+  ignore: ["possible misuse of unsafe.Pointer", other]
+coverage:
+  skip:
+    - internal/gen
+    - "cmd/tool"
+name: 'quoted # not comment'
+`
+	c, err := ParseConfig(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !c.Bool("go.proxy_fallback", false) {
+		t.Error("expected go.proxy_fallback true")
+	}
+	if c.Int("go.retries", 0) != 5 {
+		t.Errorf("expected go.retries 5, got %d", c.Int("go.retries", 0))
+	}
+	if got := c.List("vet.ignore"); len(got) != 2 || got[0] != "possible misuse of unsafe.Pointer" {
+		t.Errorf("unexpected inline list: %v", got)
+	}
+	if got := c.List("coverage.skip"); len(got) != 2 || got[1] != "cmd/tool" {
+		t.Errorf("unexpected block list: %v", got)
+	}
+	if got := c.String("name", ""); got != "quoted # not comment" {
+		t.Errorf("unexpected quoted value: %q", got)
+	}
+	if c.Has("go") {
+		t.Error("section placeholders should not be values")
+	}
+	if c.String("missing", "def") != "def" {
+		t.Error("expected default for missing key")
+	}
+}
+
+func TestParseConfigErrors(t *testing.T) {
+	if _, err := ParseConfig("- orphan\n"); err == nil {
+		t.Error("expected error for list item without key")
+	}
+	if _, err := ParseConfig("just text\n"); err == nil {
+		t.Error("expected error for line without colon")
+	}
+}
+
+func TestParseTOMLConfig(t *testing.T) {
+	content := `# devflow settings
+[go]
+proxy_fallback = true   # allow direct mode
+retries = 5
+
+[test.skip]
+race = ["internal/gen", 'cmd/tool']
+wasm = [
+  "web",  # browser only
+]
+gonew.license = "Apache-2.0"
+`
+	c, err := ParseTOMLConfig(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.Bool("go.proxy_fallback", false) || c.Int("go.retries", 0) != 5 {
+		t.Error("expected values of [go]")
+	}
+	if got := c.List("test.skip.race"); len(got) != 2 || got[1] != "cmd/tool" {
+		t.Errorf("unexpected array: %v", got)
+	}
+	if got := c.List("test.skip.wasm"); len(got) != 1 || got[0] != "web" {
+		t.Errorf("unexpected multi-line array: %v", got)
+	}
+	if c.String("test.skip.gonew.license", "") != "Apache-2.0" {
+		t.Error("dotted keys should be nested in the table")
+	}
+
+	for _, bad := range []string{"[[plugins]]\n", "key\n", "opts = {a = 1}\n", "list = [1, 2\n"} {
+		if _, err := ParseTOMLConfig(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	c, err := LoadConfig(dir)
+	if err != nil || c.Path() != "" {
+		t.Fatalf("missing file should give empty config, got %v %q", err, c.Path())
+	}
+
+	os.WriteFile(filepath.Join(dir, ".devflow.yml"), []byte("go:\n  proxy_fallback: yes\n"), 0644)
+	c, err = LoadConfig(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.Bool("go.proxy_fallback", false) {
+		t.Error("expected value from .devflow.yml")
+	}
+}
+
+func TestLoadConfigStopsAtHomeOrRepository(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(root, "xdg"))
+	home := filepath.Join(root, "home")
+	t.Setenv("HOME", home)
+	stray := "hooks:\n  before_push: [\"touch pwned\"]\n"
+	os.WriteFile(filepath.Join(root, ".devflow.yaml"), []byte(stray), 0644)
+
+	// Under the home directory: up to it, not above
+	project := filepath.Join(home, "work", "api")
+	os.MkdirAll(project, 0755)
+	os.WriteFile(filepath.Join(home, ".devflow.yaml"), []byte("release:\n  bump: minor\n"), 0644)
+	c, err := LoadConfig(project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Has("hooks.before_push") || c.String("release.bump", "") != "minor" {
+		t.Errorf("expected the config of the home directory only, got %v", c.Sources())
+	}
+
+	// Outside it: up to the repository root
+	repo := filepath.Join(root, "srv", "repo")
+	sub := filepath.Join(repo, "cmd")
+	os.MkdirAll(filepath.Join(repo, ".git"), 0755)
+	os.MkdirAll(sub, 0755)
+	os.WriteFile(filepath.Join(filepath.Dir(repo), ".devflow.yaml"), []byte(stray), 0644)
+	os.WriteFile(filepath.Join(repo, ".devflow.yaml"), []byte("release:\n  bump: major\n"), 0644)
+	if c, err = LoadConfig(sub); err != nil {
+		t.Fatal(err)
+	}
+	if c.Has("hooks.before_push") || c.String("release.bump", "") != "major" {
+		t.Errorf("expected the config of the repository only, got %v", c.Sources())
+	}
+}
+
+func TestLoadConfigInheritance(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(root, "xdg"))
+	t.Setenv("HOME", root)
+	os.MkdirAll(filepath.Join(root, "xdg", "devflow"), 0755)
+	os.WriteFile(filepath.Join(root, "xdg", "devflow", "config.yaml"), []byte("go:\n  proxy_fallback: true\nrelease:\n  bump: major\n"), 0644)
+
+	workspace := filepath.Join(root, "work")
+	project := filepath.Join(workspace, "api")
+	os.MkdirAll(project, 0755)
+	os.WriteFile(filepath.Join(workspace, ".devflow.yaml"), []byte("release:\n  bump: minor\n  schedule: monthly\n"), 0644)
+
+	c, err := LoadConfig(project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Path() != "" || c.String("release.bump", "") != "minor" || !c.Bool("go.proxy_fallback", false) {
+		t.Errorf("workspace config not merged: path %q, bump %q", c.Path(), c.String("release.bump", ""))
+	}
+
+	own := filepath.Join(project, ".devflow.yml")
+	os.WriteFile(own, []byte("release:\n  schedule: daily\n"), 0644)
+	c, err = LoadConfig(project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Path() != own || c.String("release.schedule", "") != "daily" || c.String("release.bump", "") != "minor" {
+		t.Errorf("project config should override workspace: %q %q", c.String("release.schedule", ""), c.String("release.bump", ""))
+	}
+	if c.Source("release.bump") != filepath.Join(workspace, ".devflow.yaml") || c.Source("release.schedule") != own {
+		t.Errorf("unexpected sources: %q %q", c.Source("release.bump"), c.Source("release.schedule"))
+	}
+	if len(c.Sources()) != 3 {
+		t.Errorf("Sources() = %v", c.Sources())
+	}
+
+	// A TOML global config is read when there is no YAML one
+	os.Remove(filepath.Join(root, "xdg", "devflow", "config.yaml"))
+	os.WriteFile(filepath.Join(root, "xdg", "devflow", "config.toml"), []byte("[gonew]\nlicense = \"BSD-3-Clause\"\n"), 0644)
+	c, err = LoadConfig(project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.String("gonew.license", "") != "BSD-3-Clause" || c.Bool("go.proxy_fallback", false) {
+		t.Errorf("global config.toml not loaded: %v", c.Sources())
+	}
+}
+
+func TestConfigKeys(t *testing.T) {
+	c, _ := ParseConfig("variables:\n  zeta:\n    required: true\n  alpha:\n  mid:\n    default: x\nother: 1\n")
+	got := c.Keys("variables")
+	if strings.Join(got, ",") != "zeta,alpha,mid" {
+		t.Errorf("expected keys in file order, got %v", got)
+	}
+}
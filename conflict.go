@@ -0,0 +1,198 @@
+package devflow
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Conflict policies of WithConflictPolicy: what a generator does with a
+// file that already exists and differs from what it generates
+const (
+	ConflictOverwrite = "overwrite" // Replace the file (the default outside WithConflictPolicy)
+	ConflictSkip      = "skip"      // Keep the file as it is
+	ConflictMerge     = "merge"     // Add the generated lines or sections the file lacks
+	ConflictNew       = "new"       // Keep the file and write the generated one to <file>.new
+)
+
+// ConflictPolicies lists the conflict policies
+var ConflictPolicies = []string{ConflictOverwrite, ConflictSkip, ConflictMerge, ConflictNew}
+
+// FileConflict is an existing file a generator would have changed
+type FileConflict struct {
+	Path   string
+	Action string // overwrite, skip, merge or new: what was done
+	Diff   string // git diff of the file and the .new file (new only)
+}
+
+// String returns e.g. "README.md: kept, generated version in README.md.new"
+func (c FileConflict) String() string {
+	switch c.Action {
+	case ConflictSkip:
+		return c.Path + ": kept"
+	case ConflictMerge:
+		return c.Path + ": merged"
+	case ConflictNew:
+		return c.Path + ": kept, generated version in " + c.Path + ".new"
+	}
+	return c.Path + ": overwritten"
+}
+
+// generation is the scope of WithConflictPolicy
+var generation struct {
+	sync.Mutex
+	policy    string
+	written   map[string]bool // Files generated in the scope, rewritten freely
+	conflicts []FileConflict
+}
+
+// ValidateConflictPolicy checks policy, empty meaning overwrite
+func ValidateConflictPolicy(policy string) error {
+	if policy == "" {
+		return nil
+	}
+	for _, p := range ConflictPolicies {
+		if p == policy {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown conflict policy %q (want %s)", policy, strings.Join(ConflictPolicies, ", "))
+}
+
+// WithConflictPolicy runs fn with the file generators (README, LICENSE,
+// .gitignore, doc.go, project skeleton, CI workflow, dependency bot,
+// community files and badges) applying policy to the files that existed before: a
+// run over an existing project, e.g. to add what it lacks, then keeps the
+// user's edits. Files a generator wrote earlier in fn are rewritten as
+// usual. It returns the existing files that differed and what was done
+// with each, in order.
+func WithConflictPolicy(policy string, fn func() error) ([]FileConflict, error) {
+	if err := ValidateConflictPolicy(policy); err != nil {
+		return nil, err
+	}
+	if policy == "" {
+		policy = ConflictOverwrite
+	}
+	generation.Lock()
+	if generation.policy != "" {
+		generation.Unlock()
+		return nil, fmt.Errorf("WithConflictPolicy calls cannot be nested")
+	}
+	generation.policy, generation.written, generation.conflicts = policy, map[string]bool{}, nil
+	generation.Unlock()
+
+	err := fn()
+
+	generation.Lock()
+	conflicts := generation.conflicts
+	generation.policy, generation.written, generation.conflicts = "", nil, nil
+	generation.Unlock()
+	return conflicts, err
+}
+
+// writeGenerated writes a generated file, applying the conflict policy of
+// the current WithConflictPolicy scope when path already exists
+func writeGenerated(path string, data []byte) error {
+	generation.Lock()
+	defer generation.Unlock()
+	if generation.policy == "" || generation.written[path] {
+		return os.WriteFile(path, data, 0644)
+	}
+	existing, err := os.ReadFile(path)
+	if err != nil || bytes.Equal(existing, data) {
+		generation.written[path] = true
+		return os.WriteFile(path, data, 0644)
+	}
+
+	conflict := FileConflict{Path: path, Action: generation.policy}
+	switch generation.policy {
+	case ConflictOverwrite:
+		err = os.WriteFile(path, data, 0644)
+	case ConflictSkip:
+	case ConflictMerge:
+		if merged, ok := mergeGenerated(path, existing, data); ok {
+			err = os.WriteFile(path, merged, 0644)
+			break
+		}
+		conflict.Action = ConflictNew
+		conflict.Diff, err = writeNewFile(path, data)
+	case ConflictNew:
+		conflict.Diff, err = writeNewFile(path, data)
+	}
+	if err != nil {
+		return err
+	}
+	generation.conflicts = append(generation.conflicts, conflict)
+	return nil
+}
+
+// writeNewFile writes data to path.new and returns the diff preview
+func writeNewFile(path string, data []byte) (string, error) {
+	if err := os.WriteFile(path+".new", data, 0644); err != nil {
+		return "", err
+	}
+	// git diff --no-index exits with 1 when the files differ
+	out, _ := RunCommand("git", "diff", "--no-index", "--no-color", "--", path, path+".new")
+	return out, nil
+}
+
+// mergeGenerated adds to existing what data has and it lacks: the lines of
+// a .gitignore-style file, the "## " sections of a Markdown file. Other
+// files cannot be merged.
+func mergeGenerated(path string, existing, data []byte) ([]byte, bool) {
+	name := filepath.Base(path)
+	switch {
+	case strings.HasPrefix(name, ".") && filepath.Ext(name) == name:
+		return mergeLines(existing, data), true
+	case strings.HasSuffix(name, ".md"):
+		return mergeSections(existing, data), true
+	}
+	return nil, false
+}
+
+// mergeLines appends the lines of data missing from existing
+func mergeLines(existing, data []byte) []byte {
+	have := map[string]bool{}
+	for _, line := range strings.Split(string(existing), "\n") {
+		have[strings.TrimSpace(line)] = true
+	}
+	var missing []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if t := strings.TrimSpace(line); t != "" && !strings.HasPrefix(t, "#") && !have[t] {
+			missing = append(missing, line)
+			have[t] = true
+		}
+	}
+	return appendBlock(existing, strings.Join(missing, "\n"))
+}
+
+// mergeSections appends the "## " sections of data whose heading is
+// missing from existing
+func mergeSections(existing, data []byte) []byte {
+	have := map[string]bool{}
+	for _, line := range strings.Split(string(existing), "\n") {
+		if strings.HasPrefix(line, "## ") {
+			have[strings.TrimSpace(line)] = true
+		}
+	}
+	var missing []string
+	parts := strings.Split("\n"+string(data), "\n## ")
+	for _, section := range parts[1:] {
+		heading, _, _ := strings.Cut(section, "\n")
+		if !have["## "+strings.TrimSpace(heading)] {
+			missing = append(missing, "## "+strings.TrimRight(section, "\n"))
+		}
+	}
+	return appendBlock(existing, strings.Join(missing, "\n\n"))
+}
+
+// appendBlock adds block after existing, separated by a blank line
+func appendBlock(existing []byte, block string) []byte {
+	if block == "" {
+		return existing
+	}
+	return []byte(strings.TrimRight(string(existing), "\n") + "\n\n" + block + "\n")
+}
@@ -0,0 +1,141 @@
+package devflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWithConflictPolicy(t *testing.T) {
+	gitignore := "# Mine\nnode_modules/\n*.exe\n"
+	readme := "# widget\n\nMy own words.\n\n## Usage\n\nRun it.\n"
+	setup := func(t *testing.T) string {
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, ".gitignore"), []byte(gitignore), 0644)
+		os.WriteFile(filepath.Join(dir, "README.md"), []byte(readme), 0644)
+		return dir
+	}
+	generate := func(dir string) func() error {
+		return func() error {
+			if err := GenerateGitignore(dir); err != nil {
+				return err
+			}
+			if err := GenerateREADME("widget", "A widget", dir); err != nil {
+				return err
+			}
+			return GenerateCIWorkflow(dir, CIWorkflowOptions{})
+		}
+	}
+	read := func(dir, name string) string {
+		data, _ := os.ReadFile(filepath.Join(dir, name))
+		return string(data)
+	}
+
+	t.Run("skip", func(t *testing.T) {
+		dir := setup(t)
+		conflicts, err := WithConflictPolicy(ConflictSkip, generate(dir))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(conflicts) != 2 || conflicts[0].Action != ConflictSkip {
+			t.Fatalf("expected 2 skipped files, got %v", conflicts)
+		}
+		if read(dir, ".gitignore") != gitignore || read(dir, "README.md") != readme {
+			t.Error("expected the existing files to be kept")
+		}
+		if read(dir, CIWorkflowPath) == "" {
+			t.Error("expected the missing CI workflow to be written")
+		}
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		dir := setup(t)
+		if _, err := WithConflictPolicy(ConflictMerge, generate(dir)); err != nil {
+			t.Fatal(err)
+		}
+		got := read(dir, ".gitignore")
+		if !strings.HasPrefix(got, gitignore) || !strings.Contains(got, "\ngo.work\n") || strings.Count(got, "*.exe\n") != 1 {
+			t.Errorf("expected the missing patterns appended once:\n%s", got)
+		}
+		got = read(dir, "README.md")
+		if !strings.HasPrefix(got, readme) || !strings.Contains(got, "My own words.") || strings.Count(got, "## Usage") != 1 {
+			t.Errorf("expected the missing sections appended:\n%s", got)
+		}
+	})
+
+	t.Run("new", func(t *testing.T) {
+		dir := setup(t)
+		conflicts, err := WithConflictPolicy(ConflictNew, generate(dir))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if read(dir, "README.md") != readme {
+			t.Error("expected README.md to be kept")
+		}
+		if !strings.Contains(read(dir, "README.md.new"), "A widget") {
+			t.Error("expected the generated README in README.md.new")
+		}
+		if len(conflicts) != 2 || !strings.Contains(conflicts[1].Diff, "+A widget") {
+			t.Errorf("expected a diff preview, got %v", conflicts)
+		}
+	})
+
+	t.Run("identical and rewritten files", func(t *testing.T) {
+		dir := t.TempDir()
+		GenerateGitignore(dir)
+		conflicts, err := WithConflictPolicy(ConflictSkip, func() error {
+			if err := GenerateGitignore(dir); err != nil {
+				return err
+			}
+			if err := GenerateREADME("widget", "A widget", dir); err != nil {
+				return err
+			}
+			return GenerateLocalizedREADMEs("widget", "A widget", Author{Name: "Jane"}, []string{"es"}, dir)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(conflicts) != 0 {
+			t.Errorf("expected no conflicts, got %v", conflicts)
+		}
+		if !strings.Contains(read(dir, "README.md"), "README.es.md") {
+			t.Error("expected the README written in the scope to get the language links")
+		}
+	})
+
+	if _, err := WithConflictPolicy("clobber", func() error { return nil }); err == nil {
+		t.Error("expected an unknown policy to fail")
+	}
+}
+
+func TestGoNewRegenerate(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module github.com/acme/widget\n\ngo 1.22\n"), 0644)
+	os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("# Mine\nnode_modules/\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "CONTRIBUTING.md"), []byte("# Contributing\n\nAsk first.\n"), 0644)
+
+	git, _ := NewGit()
+	goHandler, _ := NewGo(git)
+	gn := NewGoNew(git, nil, goHandler)
+	conflicts, err := gn.Regenerate(dir, NewProjectOptions{CI: true, Community: true}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflicts) != 2 || conflicts[0].Action != ConflictNew {
+		t.Fatalf("expected the .gitignore and CONTRIBUTING.md conflicts, got %v", conflicts)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "CONTRIBUTING.md")); string(data) != "# Contributing\n\nAsk first.\n" {
+		t.Errorf("existing file overwritten:\n%s", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "CONTRIBUTING.md.new")); !strings.Contains(string(data), "widget") {
+		t.Errorf("generated version not written next to it:\n%s", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, CIWorkflowPath)); err != nil {
+		t.Errorf("CI workflow not generated: %v", err)
+	}
+
+	if _, err := gn.Regenerate(t.TempDir(), NewProjectOptions{}, ""); err == nil {
+		t.Error("expected an error outside a Go project")
+	}
+}
@@ -2,6 +2,7 @@ package devflow
 
 import (
 	"fmt"
+	"os"
 	"strings"
 )
 
@@ -15,7 +16,8 @@ type ConsoleFilter struct {
 
 func NewConsoleFilter(output func(string)) *ConsoleFilter {
 	if output == nil {
-		output = func(s string) { fmt.Println(s) }
+		w := OutputWriter(os.Stdout)
+		output = func(s string) { fmt.Fprintln(w, s) }
 	}
 	return &ConsoleFilter{
 		output: output,
@@ -0,0 +1,150 @@
+package devflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// copyrightYearRe matches "Copyright 2024", "Copyright (c) 2020-2025" and "Copyright © 2021"
+var copyrightYearRe = regexp.MustCompile(`(?i)(copyright\s+(?:\(c\)\s+|©\s+)?)(\d{4})(?:\s*[-–]\s*(\d{4}))?`)
+
+// licenseFileNames are the license files whose copyright year is updated
+var licenseFileNames = []string{"LICENSE", "LICENSE.md", "LICENSE.txt"}
+
+// UpdateCopyrightYear extends year ranges ending before year, e.g.
+// "Copyright (c) 2023 Name" becomes "Copyright (c) 2023-2026 Name".
+// With holders, only the notices whose line names one of them change, so
+// the notices of others (the FSF one of the GPL text) are kept.
+// Returns the updated text and whether it changed.
+func UpdateCopyrightYear(text string, year int, holders ...string) (string, bool) {
+	var b strings.Builder
+	last := 0
+	for _, m := range copyrightYearRe.FindAllStringSubmatchIndex(text, -1) {
+		start, _ := strconv.Atoi(text[m[4]:m[5]])
+		end := start
+		if m[6] >= 0 {
+			end, _ = strconv.Atoi(text[m[6]:m[7]])
+		}
+		if end >= year || start > year || !namesHolder(text[m[1]:], holders) {
+			continue
+		}
+		fmt.Fprintf(&b, "%s%s%d-%d", text[last:m[0]], text[m[2]:m[3]], start, year)
+		last = m[1]
+	}
+	if last == 0 {
+		return text, false
+	}
+	return b.String() + text[last:], true
+}
+
+// namesHolder reports whether the rest of the line of a notice names one
+// of holders; any notice does without holders
+func namesHolder(rest string, holders []string) bool {
+	if len(holders) == 0 {
+		return true
+	}
+	line, _, _ := strings.Cut(rest, "\n")
+	for _, h := range holders {
+		if h = strings.TrimSpace(h); h != "" && strings.Contains(strings.ToLower(line), strings.ToLower(h)) {
+			return true
+		}
+	}
+	return false
+}
+
+// updateCopyrightYears runs before the release commit. On the first release
+// of a calendar year (the latest tag points to a commit from an earlier
+// year) it extends the copyright year of the project's notices (naming
+// license.holder, else the git user.name) in LICENSE and in the leading
+// comment block of .go files, and returns the updated files relative to
+// the root. Disabled with license.update_year: false.
+func (g *Git) updateCopyrightYears() (string, []string, error) {
+	cfg, err := LoadConfig(g.rootDir)
+	if err != nil || !cfg.Bool("license.update_year", true) {
+		return "", nil, nil
+	}
+	holder := cfg.String("license.holder", "")
+	if holder == "" {
+		holder, _ = g.GetConfigUserName()
+	}
+	if strings.TrimSpace(holder) == "" {
+		return "", nil, nil
+	}
+
+	year := time.Now().Year()
+	latest, err := g.GetLatestTag()
+	if err != nil || latest == "" {
+		return "", nil, nil
+	}
+	tagYear, err := RunCommandSilent("git", "log", "-1", "--format=%cd", "--date=format:%Y", latest)
+	if err != nil {
+		return "", nil, nil
+	}
+	if y, err := strconv.Atoi(tagYear); err != nil || y >= year {
+		return "", nil, nil
+	}
+
+	var updated []string
+	for _, name := range licenseFileNames {
+		path := filepath.Join(g.rootDir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if text, changed := UpdateCopyrightYear(string(data), year, holder); changed {
+			if err := writeUnlessDryRun(path, []byte(text)); err != nil {
+				return "", nil, err
+			}
+			updated = append(updated, name)
+		}
+	}
+
+	files, err := goSourceFiles(g.rootDir, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	for _, rel := range files {
+		path := filepath.Join(g.rootDir, rel)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", nil, err
+		}
+		header, body := splitHeaderComment(string(data))
+		if text, changed := UpdateCopyrightYear(header, year, holder); changed {
+			if err := writeUnlessDryRun(path, []byte(text+body)); err != nil {
+				return "", nil, err
+			}
+			updated = append(updated, rel)
+		}
+	}
+
+	if len(updated) == 0 {
+		return "", nil, nil
+	}
+	g.logger.Info("Copyright year updated:", strings.Join(updated, ", "))
+	return fmt.Sprintf("✅ Copyright %d: %d files", year, len(updated)), updated, nil
+}
+
+// splitHeaderComment splits a Go file into its leading comment block and the rest
+func splitHeaderComment(src string) (header, body string) {
+	offset := 0
+	for offset < len(src) {
+		end := strings.IndexByte(src[offset:], '\n')
+		if end < 0 {
+			end = len(src) - offset
+		} else {
+			end++
+		}
+		line := strings.TrimSpace(src[offset : offset+end])
+		if line != "" && !strings.HasPrefix(line, "//") {
+			break
+		}
+		offset += end
+	}
+	return src[:offset], src[offset:]
+}
@@ -0,0 +1,172 @@
+package devflow
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUpdateCopyrightYear(t *testing.T) {
+	tests := []struct {
+		in, want string
+		changed  bool
+	}{
+		{"Copyright (c) 2023 Jane", "Copyright (c) 2023-2026 Jane", true},
+		{"// Copyright 2020-2025 ACME", "// Copyright 2020-2026 ACME", true},
+		{"Copyright © 2021 Org", "Copyright © 2021-2026 Org", true},
+		{"Copyright 2026 Jane", "Copyright 2026 Jane", false},
+		{"Copyright 2019-2026 Jane", "Copyright 2019-2026 Jane", false},
+		{"no notice 2020", "no notice 2020", false},
+	}
+	for _, tt := range tests {
+		got, changed := UpdateCopyrightYear(tt.in, 2026)
+		if got != tt.want || changed != tt.changed {
+			t.Errorf("UpdateCopyrightYear(%q) = %q, %v; want %q, %v", tt.in, got, changed, tt.want, tt.changed)
+		}
+	}
+
+	// With a holder, the notices of others are kept
+	in := "Copyright (C) 2007 Free Software Foundation, Inc.\nCopyright (c) 2023 jane doe\n"
+	want := "Copyright (C) 2007 Free Software Foundation, Inc.\nCopyright (c) 2023-2026 jane doe\n"
+	if got, changed := UpdateCopyrightYear(in, 2026, "Jane Doe"); got != want || !changed {
+		t.Errorf("unexpected holder update %q, %v", got, changed)
+	}
+	if _, changed := UpdateCopyrightYear("Copyright 2023 ACME", 2026, "Jane Doe"); changed {
+		t.Error("expected another holder's notice kept")
+	}
+}
+
+func TestSplitHeaderComment(t *testing.T) {
+	header, body := splitHeaderComment("// Copyright 2020 A\n\npackage x\n// Copyright 2020 B\n")
+	if header != "// Copyright 2020 A\n\n" || body != "package x\n// Copyright 2020 B\n" {
+		t.Errorf("unexpected split: %q | %q", header, body)
+	}
+}
+
+func TestGitPushUpdatesCopyrightYear(t *testing.T) {
+	remoteDir := t.TempDir()
+	exec.Command("git", "init", "--bare", remoteDir).Run()
+	dir, cleanup := testCreateGitRepo()
+	defer cleanup()
+	defer testChdir(t, dir)()
+
+	// Last release was tagged last year
+	lastYear := time.Now().Year() - 1
+	exec.Command("git", "remote", "add", "origin", "file://"+remoteDir).Run()
+	os.WriteFile("LICENSE", []byte(fmt.Sprintf("MIT License\n\nCopyright (c) %d Test\n", lastYear)), 0644)
+	os.WriteFile("lib.go", []byte(fmt.Sprintf("// Copyright %d Test\n\npackage lib\n\n// Copyright %d in body is kept\n", lastYear, lastYear)), 0644)
+	exec.Command("git", "add", ".").Run()
+	commit := exec.Command("git", "commit", "-m", "initial")
+	commit.Env = append(os.Environ(), fmt.Sprintf("GIT_COMMITTER_DATE=%d-06-01T12:00:00", lastYear))
+	if out, err := commit.CombinedOutput(); err != nil {
+		t.Fatalf("commit failed: %v %s", err, out)
+	}
+	exec.Command("git", "tag", "v0.0.1").Run()
+	exec.Command("git", "push", "-u", "origin", "HEAD", "--tags").Run()
+
+	os.WriteFile("feature.txt", []byte("new"), 0644)
+	git, _ := NewGit()
+	summary, err := git.Push("feat: first release of the year", "")
+	if err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	if !strings.Contains(summary, fmt.Sprintf("Copyright %d: 2 files", lastYear+1)) {
+		t.Errorf("expected copyright update in summary, got %q", summary)
+	}
+
+	// Part of the release commit
+	files, _ := RunCommandSilent("git", "show", "--name-only", "--format=", "HEAD")
+	if !strings.Contains(files, "LICENSE") || !strings.Contains(files, "lib.go") {
+		t.Errorf("expected LICENSE and lib.go in release commit, got %q", files)
+	}
+	src, _ := os.ReadFile("lib.go")
+	want := fmt.Sprintf("// Copyright %d-%d Test\n\npackage lib\n\n// Copyright %d in body is kept\n", lastYear, lastYear+1, lastYear)
+	if string(src) != want {
+		t.Errorf("unexpected lib.go:\n%s", src)
+	}
+
+	// Next release of the same year leaves files alone
+	os.WriteFile("feature.txt", []byte("newer"), 0644)
+	summary, err = git.Push("fix: second release", "")
+	if err != nil || strings.Contains(summary, "Copyright") {
+		t.Errorf("expected no copyright update, got %q %v", summary, err)
+	}
+}
+
+func TestGitPushKeepsGPLNotice(t *testing.T) {
+	gpl, err := os.ReadFile(filepath.Join("licenses", "GPL-3.0.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	remoteDir := t.TempDir()
+	exec.Command("git", "init", "--bare", remoteDir).Run()
+	dir, cleanup := testCreateGitRepo()
+	defer cleanup()
+	defer testChdir(t, dir)()
+
+	lastYear := time.Now().Year() - 1
+	exec.Command("git", "remote", "add", "origin", "file://"+remoteDir).Run()
+	os.WriteFile("LICENSE", gpl, 0644)
+	exec.Command("git", "add", ".").Run()
+	commit := exec.Command("git", "commit", "-m", "initial")
+	commit.Env = append(os.Environ(), fmt.Sprintf("GIT_COMMITTER_DATE=%d-06-01T12:00:00", lastYear))
+	if out, err := commit.CombinedOutput(); err != nil {
+		t.Fatalf("commit failed: %v %s", err, out)
+	}
+	exec.Command("git", "tag", "v0.0.1").Run()
+	exec.Command("git", "push", "-u", "origin", "HEAD", "--tags").Run()
+
+	os.WriteFile("feature.txt", []byte("new"), 0644)
+	git, _ := NewGit()
+	summary, err := git.Push("feat: first release of the year", "")
+	if err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	if strings.Contains(summary, "Copyright") {
+		t.Errorf("expected no copyright update, got %q", summary)
+	}
+	if data, _ := os.ReadFile("LICENSE"); string(data) != string(gpl) {
+		t.Error("expected the GPL text unchanged")
+	}
+}
+
+func TestGitPushStagesCopyrightWithSelectedPaths(t *testing.T) {
+	remoteDir := t.TempDir()
+	exec.Command("git", "init", "--bare", remoteDir).Run()
+	dir, cleanup := testCreateGitRepo()
+	defer cleanup()
+	defer testChdir(t, dir)()
+
+	lastYear := time.Now().Year() - 1
+	exec.Command("git", "remote", "add", "origin", "file://"+remoteDir).Run()
+	os.WriteFile("LICENSE", []byte(fmt.Sprintf("MIT License\n\nCopyright (c) %d Test\n", lastYear)), 0644)
+	exec.Command("git", "add", ".").Run()
+	commit := exec.Command("git", "commit", "-m", "initial")
+	commit.Env = append(os.Environ(), fmt.Sprintf("GIT_COMMITTER_DATE=%d-06-01T12:00:00", lastYear))
+	if out, err := commit.CombinedOutput(); err != nil {
+		t.Fatalf("commit failed: %v %s", err, out)
+	}
+	exec.Command("git", "tag", "v0.0.1").Run()
+	exec.Command("git", "push", "-u", "origin", "HEAD", "--tags").Run()
+
+	os.Mkdir("pkg", 0755)
+	os.WriteFile(filepath.Join("pkg", "a.txt"), []byte("new"), 0644)
+	os.WriteFile("notes.txt", []byte("left out"), 0644)
+	git, _ := NewGit()
+	git.SetPushOptions(PushOptions{Paths: CommitOptions{Include: []string{"pkg/"}}})
+	if _, err := git.Push("feat: first release of the year", ""); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+
+	files, _ := RunCommandSilent("git", "show", "--name-only", "--format=", "HEAD")
+	if !strings.Contains(files, "LICENSE") || strings.Contains(files, "notes.txt") {
+		t.Errorf("expected LICENSE but not notes.txt in the release commit, got %q", files)
+	}
+	if status, _ := RunCommandSilent("git", "status", "--porcelain", "LICENSE"); status != "" {
+		t.Errorf("expected LICENSE committed, got %q", status)
+	}
+}
@@ -70,12 +70,12 @@ func TestGoUpdateDependentsNoSearchPath(t *testing.T) {
 	}
 
 	// It should not fail, just find nothing
-	results, err := goHandler.updateDependents("github.com/test/repo", "v0.0.1", "")
+	report, err := goHandler.updateDependents("github.com/test/repo", "v0.0.1", "")
 	if err != nil {
 		t.Fatal(err)
 	}
-	if len(results) != 0 {
-		t.Errorf("Expected 0 results, got %d", len(results))
+	if len(report.Results) != 0 {
+		t.Errorf("Expected 0 results, got %d", len(report.Results))
 	}
 }
 
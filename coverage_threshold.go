@@ -0,0 +1,205 @@
+package devflow
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CoverageThresholds are the minimum coverage percentages gotest enforces
+type CoverageThresholds struct {
+	Global   float64            // Every package (0: no minimum)
+	Packages []CoverageOverride // Per-package minimums, first match wins
+}
+
+// CoverageOverride is the minimum coverage of the package dirs matching
+// Pattern ("internal/legacy", "cmd/...", globs), relative to the module root
+type CoverageOverride struct {
+	Pattern string
+	Min     float64
+}
+
+// CoverageCheck is the coverage of one package against its threshold and
+// the last successful run
+type CoverageCheck struct {
+	Package  string
+	Coverage float64
+	Min      float64
+	Previous float64 // -1 when the package was not in the last run
+}
+
+// CoverageResult lists the packages below their threshold and those whose
+// coverage dropped since the last successful run
+type CoverageResult struct {
+	Below     []CoverageCheck
+	Regressed []CoverageCheck
+}
+
+// LoadCoverageThresholds reads coverage.threshold and coverage.packages
+// (a list of "dir=percent" entries)
+func LoadCoverageThresholds(c *Config) (CoverageThresholds, error) {
+	var t CoverageThresholds
+	if c == nil {
+		return t, nil
+	}
+	if c.Has("coverage.threshold") {
+		v, err := parsePercent(c.String("coverage.threshold", ""))
+		if err != nil {
+			return t, fmt.Errorf("coverage.threshold: %w", err)
+		}
+		t.Global = v
+	}
+	for _, entry := range c.List("coverage.packages") {
+		pattern, value, ok := strings.Cut(entry, "=")
+		if !ok || strings.TrimSpace(pattern) == "" {
+			return t, fmt.Errorf("coverage.packages: %q is not dir=percent", entry)
+		}
+		v, err := parsePercent(value)
+		if err != nil {
+			return t, fmt.Errorf("coverage.packages: %q: %w", entry, err)
+		}
+		t.Packages = append(t.Packages, CoverageOverride{Pattern: strings.TrimSpace(pattern), Min: v})
+	}
+	return t, nil
+}
+
+// parsePercent parses "70" or "70%" in the 0-100 range
+func parsePercent(s string) (float64, error) {
+	v, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s), "%"), 64)
+	if err != nil || v < 0 || v > 100 {
+		return 0, fmt.Errorf("invalid percentage %q", s)
+	}
+	return v, nil
+}
+
+// Enabled reports whether any threshold is set
+func (t CoverageThresholds) Enabled() bool {
+	return t.Global > 0 || len(t.Packages) > 0
+}
+
+// For returns the minimum coverage of the package in dir
+func (t CoverageThresholds) For(dir string) float64 {
+	for _, o := range t.Packages {
+		if matchPackageDir([]string{o.Pattern}, dir) {
+			return o.Min
+		}
+	}
+	return t.Global
+}
+
+// CheckCoverage compares the coverage of each package against its
+// threshold and the previous run. dirs maps import paths to package dirs;
+// results tagged " (wasm)" use the dir of their import path.
+func CheckCoverage(current map[string]float64, dirs map[string]string, t CoverageThresholds, previous map[string]float64) CoverageResult {
+	var res CoverageResult
+	paths := make([]string, 0, len(current))
+	for path := range current {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		check := CoverageCheck{Package: path, Coverage: current[path], Previous: -1}
+		if prev, ok := previous[path]; ok {
+			check.Previous = prev
+		}
+		dir, ok := dirs[strings.TrimSuffix(path, " (wasm)")]
+		if !ok {
+			dir = "."
+		}
+		check.Min = t.For(dir)
+		if check.Coverage < check.Min {
+			res.Below = append(res.Below, check)
+		} else if check.Previous >= 0 && check.Coverage < check.Previous-0.05 {
+			res.Regressed = append(res.Regressed, check)
+		}
+	}
+	return res
+}
+
+// Summary returns the gotest message for the result. On failure it names
+// the packages below their threshold and the other ones that regressed.
+func (r CoverageResult) Summary() string {
+	if len(r.Below) == 0 {
+		return "coverage thresholds ok"
+	}
+	below := make([]string, len(r.Below))
+	for i, c := range r.Below {
+		below[i] = c.String()
+	}
+	s := "coverage below threshold: " + strings.Join(below, "; ")
+	if len(r.Regressed) > 0 {
+		regressed := make([]string, len(r.Regressed))
+		for i, c := range r.Regressed {
+			regressed[i] = c.String()
+		}
+		s += "; regressed: " + strings.Join(regressed, "; ")
+	}
+	return s
+}
+
+// String returns e.g. "example.com/app/parse 62.0% < 70% (was 75.0%)"
+func (c CoverageCheck) String() string {
+	s := fmt.Sprintf("%s %.1f%%", c.Package, c.Coverage)
+	if c.Coverage < c.Min {
+		s += fmt.Sprintf(" < %s%%", strconv.FormatFloat(c.Min, 'f', -1, 64))
+	}
+	if c.Previous >= 0 {
+		s += fmt.Sprintf(" (was %.1f%%)", c.Previous)
+	}
+	return s
+}
+
+// packageCoverage returns the coverage of the packages that report one,
+// leaving out skipped import paths (test.skip.coverage)
+func packageCoverage(results []PackageResult, skipped []string) map[string]float64 {
+	isSkipped := make(map[string]bool, len(skipped))
+	for _, p := range skipped {
+		isSkipped[p] = true
+	}
+	cov := make(map[string]float64)
+	for _, p := range results {
+		if p.Coverage >= 0 && !isSkipped[strings.TrimSuffix(p.Path, " (wasm)")] {
+			cov[p.Path] = p.Coverage
+		}
+	}
+	return cov
+}
+
+// SaveCoverage stores the per-package coverage of a successful run
+func (tc *TestCache) SaveCoverage(cov map[string]float64) error {
+	cachePath, err := tc.getCachePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(tc.cacheDir, 0755); err != nil {
+		return err
+	}
+	var b strings.Builder
+	for path, v := range cov {
+		fmt.Fprintf(&b, "%s\t%g\n", path, v)
+	}
+	return os.WriteFile(cachePath+".coverage", []byte(b.String()), 0644)
+}
+
+// LastCoverage returns the per-package coverage of the last successful run
+func (tc *TestCache) LastCoverage() map[string]float64 {
+	cov := make(map[string]float64)
+	cachePath, err := tc.getCachePath()
+	if err != nil {
+		return cov
+	}
+	data, err := os.ReadFile(cachePath + ".coverage")
+	if err != nil {
+		return cov
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		path, value, ok := strings.Cut(line, "\t")
+		if v, err := strconv.ParseFloat(value, 64); ok && err == nil {
+			cov[path] = v
+		}
+	}
+	return cov
+}
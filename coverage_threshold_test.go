@@ -0,0 +1,78 @@
+package devflow
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadCoverageThresholds(t *testing.T) {
+	cfg, _ := ParseConfig("coverage:\n  threshold: 70%\n  packages: [internal/legacy=40, cmd/...=0]\n")
+	th, err := LoadCoverageThresholds(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !th.Enabled() || th.For(".") != 70 || th.For("internal/legacy") != 40 || th.For("cmd/gotest") != 0 || th.For("internal/parse") != 70 {
+		t.Errorf("unexpected thresholds: %+v", th)
+	}
+
+	for _, bad := range []string{"coverage:\n  threshold: high\n", "coverage:\n  threshold: 120\n", "coverage:\n  packages: [legacy]\n"} {
+		cfg, _ := ParseConfig(bad)
+		if _, err := LoadCoverageThresholds(cfg); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+	if th, _ := LoadCoverageThresholds(NewConfig()); th.Enabled() {
+		t.Error("empty config should not enable thresholds")
+	}
+}
+
+func TestCheckCoverage(t *testing.T) {
+	results := []PackageResult{
+		{Path: "example.com/app", Status: "ok", Coverage: 82},
+		{Path: "example.com/app/parse", Status: "ok", Coverage: 62},
+		{Path: "example.com/app/legacy", Status: "ok", Coverage: 41},
+		{Path: "example.com/app/gen", Status: "ok", Coverage: 5},
+		{Path: "example.com/app/web (wasm)", Status: "ok", Coverage: 50},
+		{Path: "example.com/app/tools", Status: "no tests", Coverage: -1},
+	}
+	current := packageCoverage(results, []string{"example.com/app/gen"})
+	if len(current) != 4 {
+		t.Fatalf("expected 4 packages with coverage, got %v", current)
+	}
+	dirs := map[string]string{"example.com/app": ".", "example.com/app/parse": "parse", "example.com/app/legacy": "legacy", "example.com/app/web": "web"}
+	th := CoverageThresholds{Global: 70, Packages: []CoverageOverride{{"legacy", 40}, {"web", 50}}}
+	previous := map[string]float64{"example.com/app": 85, "example.com/app/parse": 75}
+
+	res := CheckCoverage(current, dirs, th, previous)
+	if len(res.Below) != 1 || res.Below[0].Package != "example.com/app/parse" {
+		t.Fatalf("unexpected below: %+v", res.Below)
+	}
+	if got := res.Below[0].String(); got != "example.com/app/parse 62.0% < 70% (was 75.0%)" {
+		t.Errorf("String() = %q", got)
+	}
+	if len(res.Regressed) != 1 || res.Regressed[0].String() != "example.com/app 82.0% (was 85.0%)" {
+		t.Errorf("unexpected regressions: %+v", res.Regressed)
+	}
+	if !strings.HasSuffix(res.Summary(), "62.0% < 70% (was 75.0%); regressed: example.com/app 82.0% (was 85.0%)") {
+		t.Errorf("Summary() = %q", res.Summary())
+	}
+}
+
+func TestTestCache_Coverage(t *testing.T) {
+	dir, cleanup := testCreateGoModule("example.com/covcache")
+	defer cleanup()
+	defer testChdir(t, dir)()
+
+	cache := NewTestCache()
+	cache.cacheDir = t.TempDir()
+	if len(cache.LastCoverage()) != 0 {
+		t.Error("expected no previous coverage")
+	}
+	if err := cache.SaveCoverage(map[string]float64{"example.com/covcache": 71.5, "example.com/covcache/web (wasm)": 40}); err != nil {
+		t.Fatal(err)
+	}
+	got := cache.LastCoverage()
+	if len(got) != 2 || got["example.com/covcache"] != 71.5 || got["example.com/covcache/web (wasm)"] != 40 {
+		t.Errorf("LastCoverage() = %v", got)
+	}
+}
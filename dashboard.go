@@ -0,0 +1,196 @@
+package devflow
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Test cache states of a DashboardStatus
+const (
+	TestCachePassing = "passing" // The last passing run covers the current code
+	TestCacheStale   = "stale"   // Tests passed, but the code changed since
+	TestCacheNone    = "none"    // No passing run recorded
+)
+
+// PendingDependent is a module under the search path that requires an
+// older version of the project than its latest tag
+type PendingDependent struct {
+	Dir     string
+	Version string // Version it requires now
+}
+
+// DashboardStatus is the state of a project shown by devflow dashboard
+type DashboardStatus struct {
+	Module      *ModuleInfo
+	Branch      string
+	Unpushed    int      // Commits not on any remote
+	Changes     []string // Paths with uncommitted changes, as git status shows them
+	LatestTag   string
+	LastCommit  *Commit
+	TestCache   string // TestCachePassing, TestCacheStale or TestCacheNone
+	TestMessage string // Summary of the last passing run
+	Dependents  []PendingDependent
+}
+
+// DashboardAction is an action the dashboard offers for the project
+type DashboardAction struct {
+	Key   rune
+	Label string
+}
+
+// DashboardActions are the actions of devflow dashboard, in display order
+var DashboardActions = []DashboardAction{
+	{'t', "Test"},
+	{'p', "Push"},
+	{'b', "Backup"},
+	{'r', "Refresh"},
+	{'q', "Quit"},
+}
+
+// Dashboard collects the status of the project of a Go handler and runs
+// its Test, Push and Backup workflows
+type Dashboard struct {
+	git        *Git
+	goH        *Go
+	searchPath string
+}
+
+// NewDashboard creates a dashboard for the project of goH
+func NewDashboard(git *Git, goH *Go) *Dashboard {
+	return &Dashboard{git: git, goH: goH, searchPath: ".."}
+}
+
+// SetSearchPath sets where the dependents are looked for (default: ..)
+func (d *Dashboard) SetSearchPath(path string) {
+	if path != "" {
+		d.searchPath = path
+	}
+}
+
+// Status reads the state of the project. Only a directory outside any Go
+// module fails; other parts that cannot be read are left empty.
+func (d *Dashboard) Status() (DashboardStatus, error) {
+	// Each refresh reads go.mod again
+	d.goH.refreshModule()
+	module, err := d.goH.Module()
+	if err != nil {
+		return DashboardStatus{}, err
+	}
+	s := DashboardStatus{Module: module}
+	s.Branch, _ = d.git.CurrentBranch()
+	s.Unpushed, _ = d.git.unpushedCount()
+	if out, err := RunCommandSilent("git", "status", "--porcelain"); err == nil {
+		for _, line := range nonEmptyLines(out) {
+			// "XY path", with X or Y blank (the output is trimmed)
+			_, path, _ := strings.Cut(strings.TrimLeft(line, " "), " ")
+			s.Changes = append(s.Changes, strings.TrimLeft(path, " "))
+		}
+	}
+	s.LatestTag, _ = d.git.GetLatestTag()
+	if commits, _ := d.git.Log(LogOptions{Max: 1}); len(commits) > 0 {
+		s.LastCommit = &commits[0]
+	}
+
+	cache := NewTestCache()
+	cache.SetModule(module)
+	if err := cache.SetGitStateMode(d.goH.Config().String("cache.git_state", GitStateAuto)); err != nil {
+		d.goH.logger.Warn("cache.git_state:", err)
+	}
+	s.TestMessage = cache.GetCachedMessage()
+	switch {
+	case cache.IsCacheValid():
+		s.TestCache = TestCachePassing
+	case s.TestMessage != "":
+		s.TestCache = TestCacheStale
+	default:
+		s.TestCache = TestCacheNone
+	}
+
+	if s.LatestTag != "" {
+		dirs, _ := d.goH.findDependentModules(module.Path, d.searchPath)
+		for _, dir := range dirs {
+			version, err := d.goH.GetCurrentVersion(dir, module.Path)
+			if err == nil && CompareVersions(version, s.LatestTag) < 0 {
+				s.Dependents = append(s.Dependents, PendingDependent{Dir: dir, Version: version})
+			}
+		}
+	}
+	return s, nil
+}
+
+// Run runs the workflow of an action: the tests, or a push of message
+// with the next tag, or the configured backup
+func (d *Dashboard) Run(key rune, message string) (string, error) {
+	switch key {
+	case 't':
+		return d.goH.Test()
+	case 'p':
+		return d.goH.Push(message, "", false, false, false, false, d.searchPath)
+	case 'b':
+		summary, err := d.goH.backup.Run()
+		if err == nil && summary == "" {
+			summary = "no backup configured (backup.command or backup.mode)"
+		}
+		return summary, err
+	}
+	return "", fmt.Errorf("unknown action %q", key)
+}
+
+// Render returns the text of the dashboard, one line per entry
+func (s DashboardStatus) Render() []string {
+	lines := []string{fmt.Sprintf("%s  (%s)", s.Module.Path, s.Module.Dir)}
+
+	branch := s.Branch
+	if branch == "" {
+		branch = "(detached)"
+	}
+	if s.Unpushed > 0 {
+		branch += fmt.Sprintf(", %d unpushed", s.Unpushed)
+	}
+	lines = append(lines, "Branch     "+branch)
+	if len(s.Changes) == 0 {
+		lines = append(lines, "Changes    clean")
+	} else {
+		lines = append(lines, fmt.Sprintf("Changes    %d uncommitted", len(s.Changes)))
+		for i, c := range s.Changes {
+			if i == 5 {
+				lines = append(lines, fmt.Sprintf("           ... %d more", len(s.Changes)-i))
+				break
+			}
+			lines = append(lines, "           "+c)
+		}
+	}
+	tag := s.LatestTag
+	if tag == "" {
+		tag = "none"
+	}
+	lines = append(lines, "Tag        "+tag)
+	if s.LastCommit != nil {
+		lines = append(lines, fmt.Sprintf("Commit     %.7s %s (%s)", s.LastCommit.Hash, s.LastCommit.Subject, s.LastCommit.Author))
+	}
+
+	tests := s.TestCache
+	switch s.TestCache {
+	case TestCachePassing:
+		tests = "✅ passing (cached)"
+	case TestCacheStale:
+		tests = "⚠️ stale: changed since the last passing run"
+	case TestCacheNone:
+		tests = "no passing run"
+	}
+	lines = append(lines, "Tests      "+tests)
+	if msg := strings.TrimSpace(s.TestMessage); msg != "" && s.TestCache == TestCachePassing {
+		lines = append(lines, "           "+firstLine(msg))
+	}
+
+	if len(s.Dependents) == 0 {
+		lines = append(lines, "Dependents up to date")
+	} else {
+		lines = append(lines, fmt.Sprintf("Dependents %d to update to %s", len(s.Dependents), s.LatestTag))
+		for _, dep := range s.Dependents {
+			lines = append(lines, fmt.Sprintf("           %s (%s)", filepath.Base(dep.Dir), dep.Version))
+		}
+	}
+	return lines
+}
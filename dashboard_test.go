@@ -0,0 +1,53 @@
+package devflow
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDashboardStatus(t *testing.T) {
+	testResumeEnv(t)
+	parent := t.TempDir()
+	lib := filepath.Join(parent, "lib")
+	os.MkdirAll(lib, 0755)
+	defer testChdir(t, lib)()
+	exec.Command("git", "init", "-q").Run()
+	os.WriteFile("go.mod", []byte("module example.com/lib\n\ngo 1.21\n"), 0644)
+	exec.Command("git", "add", ".").Run()
+	exec.Command("git", "commit", "-qm", "feat: lib").Run()
+	exec.Command("git", "tag", "v0.2.0").Run()
+	os.WriteFile("lib.go", []byte("package lib\n"), 0644)
+
+	// One dependent behind the latest tag, one up to date
+	for name, version := range map[string]string{"app": "v0.1.0", "web": "v0.2.0"} {
+		dir := filepath.Join(parent, name)
+		os.MkdirAll(dir, 0755)
+		os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/"+name+"\n\ngo 1.21\n\nrequire example.com/lib "+version+"\n\nreplace example.com/lib => ../lib\n"), 0644)
+	}
+
+	git, _ := NewGit()
+	goH, _ := NewGo(git)
+	s, err := NewDashboard(git, goH).Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Module.Path != "example.com/lib" || s.LatestTag != "v0.2.0" || s.LastCommit == nil || s.LastCommit.Subject != "feat: lib" {
+		t.Errorf("unexpected status %+v", s)
+	}
+	if len(s.Changes) != 1 || s.Changes[0] != "lib.go" || s.TestCache != TestCacheNone {
+		t.Errorf("unexpected changes or test cache %q, %s", s.Changes, s.TestCache)
+	}
+	if len(s.Dependents) != 1 || filepath.Base(s.Dependents[0].Dir) != "app" || s.Dependents[0].Version != "v0.1.0" {
+		t.Errorf("expected app pending, got %+v", s.Dependents)
+	}
+
+	text := strings.Join(s.Render(), "\n")
+	for _, want := range []string{"Changes    1 uncommitted\n           lib.go", "Tag        v0.2.0", "Dependents 1 to update to v0.2.0\n           app (v0.1.0)"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in\n%s", want, text)
+		}
+	}
+}
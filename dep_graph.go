@@ -0,0 +1,168 @@
+package devflow
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Output formats of DependencyGraph.Format
+const (
+	GraphText    = "text"
+	GraphDOT     = "dot"
+	GraphMermaid = "mermaid"
+)
+
+// DependencyGraph is the reverse-dependency graph of a module: the modules
+// of a search path that require it, directly or through each other. Push
+// updates the direct dependents.
+type DependencyGraph struct {
+	Root    string            // Module path of the graphed module
+	Modules map[string]string // Module path -> directory, Root included
+	Edges   []DependencyEdge  // Sorted by From, then To
+}
+
+// DependencyEdge means From requires To
+type DependencyEdge struct {
+	From string
+	To   string
+}
+
+// DependencyGraph finds the modules under searchPath (default: "..") that
+// depend on the module in the root dir, and the modules depending on those
+func (g *Go) DependencyGraph(searchPath string) (*DependencyGraph, error) {
+	if searchPath == "" {
+		searchPath = ".."
+	}
+	module, err := g.Module()
+	if err != nil {
+		return nil, err
+	}
+	root := module.Path
+
+	graph := &DependencyGraph{Root: root, Modules: map[string]string{root: module.Dir}}
+	queue := []string{root}
+	for len(queue) > 0 {
+		path := queue[0]
+		queue = queue[1:]
+		dirs, err := g.findDependentModules(path, searchPath)
+		if err != nil {
+			return nil, err
+		}
+		for _, dir := range dirs {
+			abs, err := filepath.Abs(dir)
+			if err != nil {
+				return nil, err
+			}
+			m, err := ReadModule(abs)
+			if err != nil || m.Path == path {
+				continue
+			}
+			dependent := m.Path
+			graph.Edges = append(graph.Edges, DependencyEdge{From: dependent, To: path})
+			if _, seen := graph.Modules[dependent]; !seen {
+				graph.Modules[dependent] = abs
+				queue = append(queue, dependent)
+			}
+		}
+	}
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		if graph.Edges[i].From != graph.Edges[j].From {
+			return graph.Edges[i].From < graph.Edges[j].From
+		}
+		return graph.Edges[i].To < graph.Edges[j].To
+	})
+	return graph, nil
+}
+
+// Dependents returns the modules requiring path, sorted
+func (d *DependencyGraph) Dependents(path string) []string {
+	var dependents []string
+	for _, e := range d.Edges {
+		if e.To == path {
+			dependents = append(dependents, e.From)
+		}
+	}
+	sort.Strings(dependents)
+	return dependents
+}
+
+// Format renders the graph as GraphText, GraphDOT or GraphMermaid
+func (d *DependencyGraph) Format(format string) (string, error) {
+	switch format {
+	case "", GraphText:
+		return d.String(), nil
+	case GraphDOT:
+		return d.DOT(), nil
+	case GraphMermaid:
+		return d.Mermaid(), nil
+	}
+	return "", fmt.Errorf("unknown graph format %q (use %s, %s or %s)", format, GraphText, GraphDOT, GraphMermaid)
+}
+
+// String returns the dependents as an indented tree under Root. A module
+// reached again is listed without its dependents.
+func (d *DependencyGraph) String() string {
+	var b strings.Builder
+	b.WriteString(d.Root + "\n")
+	printed := map[string]bool{d.Root: true}
+	var walk func(path string, depth int)
+	walk = func(path string, depth int) {
+		for _, dep := range d.Dependents(path) {
+			line := strings.Repeat("  ", depth) + dep + " (" + d.Modules[dep] + ")"
+			if depth == 1 {
+				line += " <- updated by gopush"
+			}
+			if printed[dep] {
+				b.WriteString(line + " ...\n")
+				continue
+			}
+			b.WriteString(line + "\n")
+			printed[dep] = true
+			walk(dep, depth+1)
+		}
+	}
+	walk(d.Root, 1)
+	if len(d.Edges) == 0 {
+		b.WriteString("  (no dependents)\n")
+	}
+	return b.String()
+}
+
+// DOT returns the graph in Graphviz DOT, edges pointing at the required
+// module and Root in bold
+func (d *DependencyGraph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph dependents {\n\trankdir=BT;\n")
+	fmt.Fprintf(&b, "\t%q [style=bold];\n", d.Root)
+	for _, e := range d.Edges {
+		fmt.Fprintf(&b, "\t%q -> %q;\n", e.From, e.To)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid returns the graph as a Mermaid flowchart, for Markdown files
+func (d *DependencyGraph) Mermaid() string {
+	paths := make([]string, 0, len(d.Modules))
+	for path := range d.Modules {
+		if path != d.Root {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	ids := map[string]string{d.Root: "n0"}
+	var b strings.Builder
+	b.WriteString("graph BT\n")
+	fmt.Fprintf(&b, "\tn0[\"%s\"]\n", d.Root)
+	for i, path := range paths {
+		ids[path] = fmt.Sprintf("n%d", i+1)
+		fmt.Fprintf(&b, "\t%s[\"%s\"]\n", ids[path], path)
+	}
+	for _, e := range d.Edges {
+		fmt.Fprintf(&b, "\t%s --> %s\n", ids[e.From], ids[e.To])
+	}
+	b.WriteString("\tstyle n0 stroke-width:3px\n")
+	return b.String()
+}
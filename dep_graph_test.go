@@ -0,0 +1,63 @@
+package devflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDependencyGraph(t *testing.T) {
+	root := t.TempDir()
+	write := func(dir, gomod string) {
+		os.MkdirAll(filepath.Join(root, dir), 0755)
+		os.WriteFile(filepath.Join(root, dir, "go.mod"), []byte(gomod), 0644)
+	}
+	write("core", "module example.com/core\n")
+	write("api", "module example.com/api\n\nrequire example.com/core v0.1.0\n")
+	write("tool", "module example.com/tool\n\nrequire (\n\texample.com/api v0.2.0\n\texample.com/core v0.1.0\n)\n")
+	write("web", "module example.com/web\n\nrequire example.com/api v0.2.0\n")
+	write("other", "module example.com/other\n")
+
+	g := &Go{rootDir: filepath.Join(root, "core")}
+	graph, err := g.DependencyGraph(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(graph.Modules) != 4 || graph.Modules["example.com/web"] != filepath.Join(root, "web") {
+		t.Errorf("unexpected modules %v", graph.Modules)
+	}
+	if got := strings.Join(graph.Dependents("example.com/core"), ","); got != "example.com/api,example.com/tool" {
+		t.Errorf("direct dependents = %s", got)
+	}
+
+	text := graph.String()
+	for _, want := range []string{
+		"example.com/core\n",
+		"  example.com/api (" + filepath.Join(root, "api") + ") <- updated by gopush\n",
+		"    example.com/tool (" + filepath.Join(root, "tool") + ")\n",
+		"    example.com/web (",
+		"  example.com/tool (" + filepath.Join(root, "tool") + ") <- updated by gopush ...\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text graph should contain %q, got:\n%s", want, text)
+		}
+	}
+
+	dot, _ := graph.Format(GraphDOT)
+	if !strings.Contains(dot, "\"example.com/tool\" -> \"example.com/api\";") || !strings.Contains(dot, "\"example.com/core\" [style=bold];") {
+		t.Errorf("unexpected DOT:\n%s", dot)
+	}
+	mermaid, _ := graph.Format(GraphMermaid)
+	if !strings.Contains(mermaid, "n1[\"example.com/api\"]") || !strings.Contains(mermaid, "n1 --> n0") || !strings.Contains(mermaid, "n3 --> n1") {
+		t.Errorf("unexpected Mermaid:\n%s", mermaid)
+	}
+	if _, err := graph.Format("svg"); err == nil {
+		t.Error("expected unknown format error")
+	}
+
+	g.SetRootDir(filepath.Join(root, "other"))
+	if graph, _ := g.DependencyGraph(root); !strings.Contains(graph.String(), "(no dependents)") {
+		t.Errorf("expected no dependents, got:\n%s", graph)
+	}
+}
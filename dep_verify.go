@@ -0,0 +1,268 @@
+package devflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Dependency verification modes
+const (
+	DepVerifyOff  = ""     // Phase disabled
+	DepVerifyWarn = "warn" // Report unverified dependencies in the summary
+	DepVerifyFail = "fail" // Abort when any direct dependency is not verified
+)
+
+// Dependency verification statuses
+const (
+	DepVerified     = "verified"
+	DepUnsigned     = "unsigned"
+	DepUnverifiable = "unverifiable"
+	DepBadSignature = "bad signature"
+)
+
+// ModuleVersion is a required module and its version
+type ModuleVersion struct {
+	Path    string
+	Version string
+}
+
+// DepVerifyResult is the verification outcome for a single dependency
+type DepVerifyResult struct {
+	Module ModuleVersion
+	Status string // One of the Dep* status constants
+	Reason string // Detail for non-verified statuses
+}
+
+// DependencyVerifier checks the signatures of the release tags of direct
+// dependencies by fetching the tag object and running git verify-tag.
+type DependencyVerifier struct {
+	// resolve maps a module to its git repository and tag prefix (for submodules)
+	resolve func(modulePath string) (repoURL, tagPrefix string, err error)
+	log     func(...any)
+}
+
+var (
+	pseudoVersionRe = regexp.MustCompile(`\d{14}-[0-9a-f]{12}`)
+	majorSuffixRe   = regexp.MustCompile(`^v\d+$`)
+)
+
+// NewDependencyVerifier creates a verifier resolving modules hosted on
+// github.com, gitlab.com and bitbucket.org.
+func NewDependencyVerifier() *DependencyVerifier {
+	return &DependencyVerifier{
+		resolve: resolveModuleRepo,
+		log:     func(...any) {},
+	}
+}
+
+// SetLog sets the logger function
+func (v *DependencyVerifier) SetLog(fn func(...any)) {
+	if fn != nil {
+		v.log = fn
+	}
+}
+
+// DirectDependencies returns the non-indirect requirements of the go.mod in dir
+func DirectDependencies(dir string) ([]ModuleVersion, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+
+	var deps []ModuleVersion
+	inRequire := false
+	for _, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "require ("):
+			inRequire = true
+			continue
+		case inRequire && trimmed == ")":
+			inRequire = false
+			continue
+		case strings.HasPrefix(trimmed, "require "):
+			trimmed = strings.TrimPrefix(trimmed, "require ")
+		case !inRequire:
+			continue
+		}
+
+		if strings.Contains(trimmed, "// indirect") {
+			continue
+		}
+		fields := strings.Fields(trimmed)
+		if len(fields) >= 2 {
+			deps = append(deps, ModuleVersion{Path: fields[0], Version: fields[1]})
+		}
+	}
+	return deps, nil
+}
+
+// Verify checks the tag signature of every module
+func (v *DependencyVerifier) Verify(mods []ModuleVersion) []DepVerifyResult {
+	results := make([]DepVerifyResult, 0, len(mods))
+	for _, m := range mods {
+		results = append(results, v.verifyModule(m))
+	}
+	return results
+}
+
+func (v *DependencyVerifier) verifyModule(m ModuleVersion) DepVerifyResult {
+	res := DepVerifyResult{Module: m, Status: DepUnverifiable}
+
+	if pseudoVersionRe.MatchString(m.Version) {
+		res.Reason = "pseudo-version has no tag"
+		return res
+	}
+
+	repoURL, prefix, err := v.resolve(m.Path)
+	if err != nil {
+		res.Reason = err.Error()
+		return res
+	}
+	tag := prefix + strings.TrimSuffix(m.Version, "+incompatible")
+	v.log("Verifying tag", tag, "of", repoURL)
+
+	tmp, err := os.MkdirTemp("", "devflow-depverify-")
+	if err != nil {
+		res.Reason = err.Error()
+		return res
+	}
+	defer os.RemoveAll(tmp)
+
+	if _, err := RunCommandSilent("git", "init", "--bare", "-q", tmp); err != nil {
+		res.Reason = "git init failed"
+		return res
+	}
+	ref := "refs/tags/" + tag
+	if _, err := RunCommandSilent("git", "-C", tmp, "fetch", "-q", "--depth=1", repoURL, ref+":"+ref); err != nil {
+		res.Reason = "tag " + tag + " not fetchable"
+		return res
+	}
+
+	if kind, _ := RunCommandSilent("git", "-C", tmp, "cat-file", "-t", ref); kind != "tag" {
+		res.Status = DepUnsigned
+		res.Reason = "lightweight tag"
+		return res
+	}
+
+	body, _ := RunCommandSilent("git", "-C", tmp, "cat-file", "tag", ref)
+	if !strings.Contains(body, "-----BEGIN") {
+		res.Status = DepUnsigned
+		res.Reason = "annotated tag without signature"
+		return res
+	}
+
+	if out, err := RunCommandSilent("git", "-C", tmp, "verify-tag", ref); err != nil {
+		res.Status = DepBadSignature
+		if missingSignerKey(out) {
+			// Signed, but not by a key this machine knows
+			res.Status = DepUnverifiable
+		}
+		res.Reason = firstLine(out)
+		return res
+	}
+
+	res.Status = DepVerified
+	res.Reason = ""
+	return res
+}
+
+// missingSignerKey reports whether git verify-tag failed for want of the
+// signer's key (gpg keyring or ssh allowed signers) rather than on a bad
+// signature
+func missingSignerKey(out string) bool {
+	out = strings.ToLower(out)
+	for _, s := range []string{"no public key", "public key not found", "allowedsignersfile needs to be configured", "no principal matched", "cannot run gpg"} {
+		if strings.Contains(out, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// moduleRepoExists reports whether a git repository answers at url
+var moduleRepoExists = probeRemote
+
+// resolveModuleRepo derives the git repository and tag prefix of a module
+// path on well-known hosts (e.g., github.com/o/r/sub/v2 -> github.com/o/r, "sub/").
+// GitLab repositories may sit in subgroups (gitlab.com/group/sub/repo), so
+// there the longest path that is a repository wins.
+func resolveModuleRepo(modulePath string) (string, string, error) {
+	parts := strings.Split(modulePath, "/")
+	switch parts[0] {
+	case "github.com", "gitlab.com", "bitbucket.org":
+	default:
+		return "", "", fmt.Errorf("unknown host %s", parts[0])
+	}
+	if len(parts) < 3 {
+		return "", "", fmt.Errorf("invalid module path %s", modulePath)
+	}
+	if n := len(parts); n > 3 && majorSuffixRe.MatchString(parts[n-1]) {
+		parts = parts[:n-1]
+	}
+
+	repoLen := 3
+	if parts[0] == "gitlab.com" {
+		for n := len(parts); n > 3; n-- {
+			if moduleRepoExists("https://" + strings.Join(parts[:n], "/") + ".git") {
+				repoLen = n
+				break
+			}
+		}
+	}
+	prefix := ""
+	if sub := parts[repoLen:]; len(sub) > 0 {
+		prefix = strings.Join(sub, "/") + "/"
+	}
+	return "https://" + strings.Join(parts[:repoLen], "/"), prefix, nil
+}
+
+// DependencySummary formats verification results for the summary line.
+// Returns an error when mode is DepVerifyFail and any dependency is not verified.
+func DependencySummary(results []DepVerifyResult, mode string) (string, error) {
+	var problems []string
+	for _, r := range results {
+		if r.Status != DepVerified {
+			problems = append(problems, fmt.Sprintf("%s@%s: %s (%s)", r.Module.Path, r.Module.Version, r.Status, r.Reason))
+		}
+	}
+
+	if len(problems) == 0 {
+		return fmt.Sprintf("✅ deps verified: %d", len(results)), nil
+	}
+
+	msg := fmt.Sprintf("deps unverified: %d/%d [%s]", len(problems), len(results), strings.Join(problems, "; "))
+	if mode == DepVerifyFail {
+		return "❌ " + msg, fmt.Errorf("%s", msg)
+	}
+	return "⚠️ " + msg, nil
+}
+
+// SetDependencyVerification enables the supply-chain phase of Push (warn or fail)
+func (g *Go) SetDependencyVerification(mode string) {
+	g.depVerify = mode
+}
+
+// VerifyDependencies checks the tag signatures of the direct dependencies
+// of the module in rootDir and returns a summary entry.
+func (g *Go) VerifyDependencies(mode string) (string, error) {
+	deps, err := DirectDependencies(g.rootDir)
+	if err != nil {
+		return "", err
+	}
+	if len(deps) == 0 {
+		return "", nil
+	}
+
+	v := NewDependencyVerifier()
+	v.SetLog(g.logger.Info)
+	return DependencySummary(v.Verify(deps), mode)
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
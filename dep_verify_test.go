@@ -0,0 +1,125 @@
+package devflow
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDirectDependencies(t *testing.T) {
+	dir := t.TempDir()
+	gomod := `module github.com/test/app
+
+go 1.22
+
+require github.com/a/single v1.0.0
+
+require (
+	github.com/b/direct v1.2.3
+	github.com/c/indirect v0.1.0 // indirect
+)
+`
+	os.WriteFile(filepath.Join(dir, "go.mod"), []byte(gomod), 0644)
+
+	deps, err := DirectDependencies(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deps) != 2 {
+		t.Fatalf("expected 2 direct deps, got %+v", deps)
+	}
+	if deps[0].Path != "github.com/a/single" || deps[1].Version != "v1.2.3" {
+		t.Errorf("unexpected deps: %+v", deps)
+	}
+}
+
+func TestResolveModuleRepo(t *testing.T) {
+	tests := []struct{ path, url, prefix string }{
+		{"github.com/o/r", "https://github.com/o/r", ""},
+		{"github.com/o/r/v2", "https://github.com/o/r", ""},
+		{"github.com/o/r/sub/v3", "https://github.com/o/r", "sub/"},
+	}
+	for _, tt := range tests {
+		url, prefix, err := resolveModuleRepo(tt.path)
+		if err != nil || url != tt.url || prefix != tt.prefix {
+			t.Errorf("resolveModuleRepo(%q) = %q, %q, %v", tt.path, url, prefix, err)
+		}
+	}
+	if _, _, err := resolveModuleRepo("golang.org/x/sys"); err == nil {
+		t.Error("expected unknown host error")
+	}
+}
+
+func TestResolveModuleRepoGitLabSubgroup(t *testing.T) {
+	orig := moduleRepoExists
+	defer func() { moduleRepoExists = orig }()
+	moduleRepoExists = func(url string) bool { return url == "https://gitlab.com/g/sub/r.git" }
+
+	tests := []struct{ path, url, prefix string }{
+		{"gitlab.com/g/sub/r", "https://gitlab.com/g/sub/r", ""},
+		{"gitlab.com/g/sub/r/v2", "https://gitlab.com/g/sub/r", ""},
+		{"gitlab.com/g/sub/r/pkg/v3", "https://gitlab.com/g/sub/r", "pkg/"},
+		{"gitlab.com/o/r/pkg", "https://gitlab.com/o/r", "pkg/"},
+	}
+	for _, tt := range tests {
+		url, prefix, err := resolveModuleRepo(tt.path)
+		if err != nil || url != tt.url || prefix != tt.prefix {
+			t.Errorf("resolveModuleRepo(%q) = %q, %q, %v", tt.path, url, prefix, err)
+		}
+	}
+}
+
+func TestMissingSignerKey(t *testing.T) {
+	tests := []struct {
+		out  string
+		want bool
+	}{
+		{"gpg: Can't check signature: No public key", true},
+		{"error: gpg.ssh.allowedSignersFile needs to be configured and exist for ssh signature verification", true},
+		{"No principal matched.", true},
+		{"gpg: BAD signature from \"Dev <dev@example.com>\"", false},
+	}
+	for _, tt := range tests {
+		if got := missingSignerKey(tt.out); got != tt.want {
+			t.Errorf("missingSignerKey(%q) = %v, want %v", tt.out, got, tt.want)
+		}
+	}
+}
+
+func TestDependencyVerifierUnsignedTags(t *testing.T) {
+	repo, cleanup := testCreateGitRepo()
+	defer cleanup()
+	exec.Command("git", "-C", repo, "commit", "--allow-empty", "-m", "init").Run()
+	exec.Command("git", "-C", repo, "tag", "v1.0.0").Run()
+	exec.Command("git", "-C", repo, "tag", "-a", "v1.1.0", "-m", "release").Run()
+
+	v := NewDependencyVerifier()
+	v.resolve = func(string) (string, string, error) { return "file://" + repo, "", nil }
+
+	results := v.Verify([]ModuleVersion{
+		{Path: "github.com/x/light", Version: "v1.0.0"},
+		{Path: "github.com/x/annotated", Version: "v1.1.0"},
+		{Path: "github.com/x/pseudo", Version: "v0.0.0-20240101120000-abcdefabcdef"},
+		{Path: "github.com/x/missing", Version: "v9.9.9"},
+	})
+
+	want := []string{DepUnsigned, DepUnsigned, DepUnverifiable, DepUnverifiable}
+	for i, r := range results {
+		if r.Status != want[i] {
+			t.Errorf("%s: status %q, want %q (%s)", r.Module.Path, r.Status, want[i], r.Reason)
+		}
+	}
+
+	summary, err := DependencySummary(results, DepVerifyWarn)
+	if err != nil || !strings.Contains(summary, "deps unverified: 4/4") {
+		t.Errorf("warn mode: summary %q, err %v", summary, err)
+	}
+	if _, err := DependencySummary(results, DepVerifyFail); err == nil {
+		t.Error("fail mode should return an error")
+	}
+	if summary, _ := DependencySummary(nil, DepVerifyFail); !strings.Contains(summary, "deps verified: 0") {
+		t.Errorf("empty results summary: %q", summary)
+	}
+}
@@ -0,0 +1,100 @@
+package devflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Dependency update bots of GenerateDependencyBotConfig
+const (
+	DependencyBotOff        = "off"
+	DependencyBotDependabot = "dependabot" // .github/dependabot.yml
+	DependencyBotRenovate   = "renovate"   // renovate.json
+)
+
+// DependencySchedules are the update intervals of the dependency bots
+var DependencySchedules = []string{"daily", "weekly", "monthly"}
+
+// ValidateDependencyBot checks bot, empty meaning off
+func ValidateDependencyBot(bot string) error {
+	switch bot {
+	case "", DependencyBotOff, DependencyBotDependabot, DependencyBotRenovate:
+		return nil
+	}
+	return fmt.Errorf("unknown dependency bot %q (want %s, %s or %s)", bot, DependencyBotDependabot, DependencyBotRenovate, DependencyBotOff)
+}
+
+// ValidateDependencySchedule checks schedule, empty meaning weekly
+func ValidateDependencySchedule(schedule string) error {
+	if schedule == "" {
+		return nil
+	}
+	for _, s := range DependencySchedules {
+		if s == schedule {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown dependency update schedule %q (want daily, weekly or monthly)", schedule)
+}
+
+// GenerateDependencyBotConfig writes the config of bot to targetDir so it
+// keeps the Go modules and GitHub Actions up to date on schedule (daily,
+// weekly or monthly, default weekly). It returns the path written,
+// relative to targetDir, or "" when bot is off.
+func GenerateDependencyBotConfig(targetDir, bot, schedule string) (string, error) {
+	if err := ValidateDependencyBot(bot); err != nil {
+		return "", err
+	}
+	if err := ValidateDependencySchedule(schedule); err != nil {
+		return "", err
+	}
+	if schedule == "" {
+		schedule = "weekly"
+	}
+
+	var content string
+	switch bot {
+	case DependencyBotDependabot:
+		content = fmt.Sprintf(`version: 2
+updates:
+  - package-ecosystem: gomod
+    directory: /
+    schedule:
+      interval: %[1]s
+  - package-ecosystem: github-actions
+    directory: /
+    schedule:
+      interval: %[1]s
+`, schedule)
+	case DependencyBotRenovate:
+		content = fmt.Sprintf(`{
+  "$schema": "https://docs.renovatebot.com/renovate-schema.json",
+  "extends": ["config:recommended", "schedule:%s"],
+  "enabledManagers": ["gomod", "github-actions"],
+  "postUpdateOptions": ["gomodTidy"]
+}
+`, schedule)
+	default:
+		return "", nil
+	}
+
+	name := dependencyBotFile(bot)
+	path := filepath.Join(targetDir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	return name, writeGenerated(path, []byte(content))
+}
+
+// dependencyBotFile returns the config file of bot, relative to the
+// project root
+func dependencyBotFile(bot string) string {
+	switch bot {
+	case DependencyBotDependabot:
+		return filepath.Join(".github", "dependabot.yml")
+	case DependencyBotRenovate:
+		return "renovate.json"
+	}
+	return ""
+}
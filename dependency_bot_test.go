@@ -0,0 +1,79 @@
+package devflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateDependencyBotConfig(t *testing.T) {
+	dir := t.TempDir()
+	name, err := GenerateDependencyBotConfig(dir, DependencyBotDependabot, "")
+	if err != nil || name != filepath.Join(".github", "dependabot.yml") {
+		t.Fatalf("unexpected result %q, %v", name, err)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, name))
+	if !strings.Contains(string(data), "package-ecosystem: gomod") || !strings.Contains(string(data), "package-ecosystem: github-actions") || strings.Count(string(data), "interval: weekly") != 2 {
+		t.Errorf("unexpected dependabot.yml:\n%s", data)
+	}
+
+	name, err = GenerateDependencyBotConfig(dir, DependencyBotRenovate, "monthly")
+	if err != nil || name != "renovate.json" {
+		t.Fatalf("unexpected result %q, %v", name, err)
+	}
+	data, _ = os.ReadFile(filepath.Join(dir, name))
+	if !strings.Contains(string(data), `"schedule:monthly"`) || !strings.Contains(string(data), `"enabledManagers": ["gomod", "github-actions"]`) {
+		t.Errorf("unexpected renovate.json:\n%s", data)
+	}
+
+	if name, err := GenerateDependencyBotConfig(t.TempDir(), DependencyBotOff, ""); err != nil || name != "" {
+		t.Errorf("off should write nothing, got %q, %v", name, err)
+	}
+	if _, err := GenerateDependencyBotConfig(dir, "greenkeeper", ""); err == nil {
+		t.Error("expected unknown bot error")
+	}
+	if _, err := GenerateDependencyBotConfig(dir, DependencyBotDependabot, "hourly"); err == nil {
+		t.Error("expected unknown schedule error")
+	}
+}
+
+func TestGoNewDependencyBot(t *testing.T) {
+	tmp := testResumeEnv(t)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmp, ".config"))
+	git, err := NewGit()
+	if err != nil {
+		t.Skip("git not installed")
+	}
+	goHandler, _ := NewGo(git)
+	gn := NewGoNew(git, nil, goHandler)
+
+	dir := filepath.Join(tmp, "bot-lib")
+	summary, err := gn.Create(NewProjectOptions{Name: "bot-lib", Description: "Bot", LocalOnly: true, Directory: dir, DependencyBot: DependencyBotDependabot})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if files, _ := RunCommandInDir(dir, "git", "ls-files"); !strings.Contains(files, ".github/dependabot.yml") {
+		t.Errorf("expected dependabot.yml in the initial commit, got %q (%s)", files, summary)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "renovate.json")); !os.IsNotExist(err) {
+		t.Error("only the selected bot should be configured")
+	}
+
+	// The bot and schedule of the config
+	global := filepath.Join(tmp, ".config", GlobalConfigFile)
+	os.MkdirAll(filepath.Dir(global), 0755)
+	os.WriteFile(global, []byte("gonew:\n  dependency_bot: renovate\n  dependency_schedule: monthly\n"), 0644)
+	dir = filepath.Join(tmp, "config-lib")
+	if _, err := gn.Create(NewProjectOptions{Name: "config-lib", Description: "Config", LocalOnly: true, Directory: dir}); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "renovate.json")); !strings.Contains(string(data), `"schedule:monthly"`) {
+		t.Errorf("expected renovate.json from the config, got %q", data)
+	}
+
+	dir = filepath.Join(tmp, "bad-lib")
+	if _, err := gn.Create(NewProjectOptions{Name: "bad-lib", Description: "Bad", LocalOnly: true, Directory: dir, DependencyBot: "greenkeeper"}); err == nil {
+		t.Error("expected unknown bot error")
+	}
+}
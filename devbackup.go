@@ -12,21 +12,28 @@ const (
 // DevBackup handles backup operations
 type DevBackup struct {
 	bashrc *Bashrc
-	log    func(...any)
+	logger Logger
 }
 
 // NewDevBackup creates a new DevBackup instance
 func NewDevBackup() *DevBackup {
 	return &DevBackup{
 		bashrc: NewBashrc(),
-		log:    func(...any) {},
+		logger: NopLogger(),
 	}
 }
 
 // SetLog sets the logger function
 func (d *DevBackup) SetLog(fn func(...any)) {
 	if fn != nil {
-		d.log = fn
+		d.SetLogger(FuncLogger(fn))
+	}
+}
+
+// SetLogger sets the leveled logger
+func (d *DevBackup) SetLogger(l Logger) {
+	if l != nil {
+		d.logger = l
 	}
 }
 
@@ -48,7 +55,8 @@ func (d *DevBackup) SetCommand(command string) error {
 }
 
 // GetCommand retrieves the backup command
-// First checks environment variable, then falls back to .bashrc
+// First checks environment variable, then .bashrc, then backup.command
+// of the config
 func (d *DevBackup) GetCommand() (string, error) {
 	// Try environment variable first (current session)
 	if envCmd := os.Getenv(backupEnvVar); envCmd != "" {
@@ -56,12 +64,25 @@ func (d *DevBackup) GetCommand() (string, error) {
 	}
 
 	// Fallback to .bashrc
-	return d.bashrc.Get(backupEnvVar)
+	command, err := d.bashrc.Get(backupEnvVar)
+	if err == nil && command != "" {
+		return command, nil
+	}
+	if cfg, cerr := LoadConfig("."); cerr == nil && cfg.Has("backup.command") {
+		return cfg.String("backup.command", ""), nil
+	}
+	return command, err
 }
 
-// Run executes the backup command asynchronously
+// Run executes the backup command asynchronously, or with backup.mode git
+// of the global config commits and pushes the repositories of
+// backup.git_dirs before returning (see RunGit)
 // Returns a message for the summary or empty string if not configured
 func (d *DevBackup) Run() (string, error) {
+	if cfg, err := LoadGlobalConfig(); err == nil && cfg.String("backup.mode", BackupModeCommand) == BackupModeGit {
+		return d.RunGit(cfg.List("backup.git_dirs"))
+	}
+
 	command, err := d.GetCommand()
 	if err != nil {
 		// Not configured, silent skip
@@ -73,6 +94,7 @@ func (d *DevBackup) Run() (string, error) {
 	}
 
 	// Execute asynchronously at OS level
+	d.logger.Debug("Starting backup:", command)
 	if err := RunShellCommandAsync(command); err != nil {
 		return "", fmt.Errorf("failed to start backup: %w", err)
 	}
@@ -0,0 +1,111 @@
+package devflow
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Backup modes of backup.mode
+const (
+	BackupModeCommand = "command" // Run the backup command (DEV_BACKUP or backup.command)
+	BackupModeGit     = "git"     // Commit and push the git repositories of backup.git_dirs
+)
+
+// backupTimeFormat is the timestamp of the git backup commits
+const backupTimeFormat = "2006-01-02 15:04:05"
+
+// RunGit backs up dirs that are git repositories, such as notes or
+// dotfiles: each gets its changes staged and committed as
+// "backup: <time> (<host>)", and its branch pushed when it has an origin.
+// Directories without changes are left alone. A failing directory does not
+// stop the others; the errors are returned together.
+func (d *DevBackup) RunGit(dirs []string) (string, error) {
+	if len(dirs) == 0 {
+		return "", nil
+	}
+	git, err := NewGit()
+	if err != nil {
+		return "", err
+	}
+	git.SetLogger(d.logger)
+	message := "backup: " + time.Now().Format(backupTimeFormat)
+	if host, err := os.Hostname(); err == nil && host != "" {
+		message += " (" + host + ")"
+	}
+
+	var results []string
+	var errs []error
+	for _, dir := range dirs {
+		result, err := d.backupRepo(git, dir, message)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", dir, err))
+			continue
+		}
+		results = append(results, filepath.Base(dir)+" "+result)
+	}
+	summary := ""
+	if len(results) > 0 {
+		summary = "✅ Backup: " + strings.Join(results, ", ")
+	}
+	return summary, errors.Join(errs...)
+}
+
+// backupRepo commits and pushes the changes of the repository at dir and
+// returns what was done: committed, pushed, committed and pushed, or
+// unchanged
+func (d *DevBackup) backupRepo(git *Git, dir, message string) (string, error) {
+	if rest, ok := strings.CutPrefix(dir, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			dir = filepath.Join(home, rest)
+		}
+	}
+	if _, err := os.Stat(dir); err != nil {
+		return "", err
+	}
+	if out, err := RunCommandInDir(dir, "git", "rev-parse", "--git-dir"); err != nil {
+		return "", fmt.Errorf("not a git repository: %s", firstLine(out))
+	}
+
+	// The Git handler works in the current directory
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	defer os.Chdir(wd)
+	if err := os.Chdir(dir); err != nil {
+		return "", err
+	}
+
+	d.logger.Debug("Backing up", dir)
+	if err := git.Add(); err != nil {
+		return "", fmt.Errorf("git add failed: %w", err)
+	}
+	committed, err := git.Commit(message)
+	if err != nil {
+		return "", fmt.Errorf("git commit failed: %w", err)
+	}
+	if _, err := RunCommandSilent("git", "remote", "get-url", "origin"); err != nil {
+		if committed {
+			return "committed", nil
+		}
+		return "unchanged", nil
+	}
+	// Commits of an earlier backup whose push failed go out too
+	if unpushed, err := git.unpushedCount(); err != nil || unpushed == 0 {
+		return "unchanged", err
+	}
+	if err := git.pushBranch(); err != nil {
+		if committed {
+			return "", fmt.Errorf("committed, but %w", err)
+		}
+		return "", err
+	}
+	if !committed {
+		return "pushed", nil
+	}
+	return "committed and pushed", nil
+}
@@ -0,0 +1,67 @@
+package devflow
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDevBackupRunGit(t *testing.T) {
+	tmp := testResumeEnv(t)
+	remote := filepath.Join(tmp, "notes.git")
+	exec.Command("git", "init", "--bare", remote).Run()
+	notes := filepath.Join(tmp, "notes")
+	dotfiles := filepath.Join(tmp, "dotfiles")
+	for _, dir := range []string{notes, dotfiles} {
+		os.MkdirAll(dir, 0755)
+		exec.Command("git", "-C", dir, "init", "-q").Run()
+		os.WriteFile(filepath.Join(dir, "a.md"), []byte("a\n"), 0644)
+	}
+	exec.Command("git", "-C", notes, "remote", "add", "origin", remote).Run()
+
+	d := NewDevBackup()
+	summary, err := d.RunGit([]string{notes, dotfiles, filepath.Join(tmp, "missing")})
+	if err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("expected the missing directory reported, got %v", err)
+	}
+	if summary != "✅ Backup: notes committed and pushed, dotfiles committed" {
+		t.Errorf("unexpected summary %q", summary)
+	}
+	subject, _ := RunCommandInDir(remote, "git", "log", "-1", "--format=%s")
+	if !strings.HasPrefix(subject, "backup: ") {
+		t.Errorf("expected the backup commit pushed, got %q", subject)
+	}
+
+	if summary, err := d.RunGit([]string{notes}); err != nil || summary != "✅ Backup: notes unchanged" {
+		t.Errorf("expected an unchanged repository, got %q, %v", summary, err)
+	}
+}
+
+func TestDevBackupRunReadsGlobalConfig(t *testing.T) {
+	tmp := testResumeEnv(t)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmp, ".config"))
+	t.Setenv(backupEnvVar, "")
+	notes := filepath.Join(tmp, "notes")
+	empty := filepath.Join(tmp, "empty")
+	for _, dir := range []string{notes, empty} {
+		os.MkdirAll(dir, 0755)
+		exec.Command("git", "-C", dir, "init", "-q").Run()
+	}
+	os.WriteFile(filepath.Join(notes, "a.md"), []byte("a\n"), 0644)
+	// A repository without commits has nothing to push yet
+	exec.Command("git", "-C", empty, "remote", "add", "origin", filepath.Join(tmp, "empty.git")).Run()
+	os.MkdirAll(filepath.Join(tmp, ".config", "devflow"), 0755)
+	os.WriteFile(filepath.Join(tmp, ".config", GlobalConfigFile), []byte("backup:\n  mode: git\n  git_dirs: ["+notes+", "+empty+"]\n"), 0644)
+
+	// The project pushed from has no backup settings of its own
+	project := filepath.Join(tmp, "project")
+	os.MkdirAll(project, 0755)
+	defer testChdir(t, project)()
+
+	summary, err := NewDevBackup().Run()
+	if err != nil || summary != "✅ Backup: notes committed, empty unchanged" {
+		t.Errorf("expected the global git backup, got %q, %v", summary, err)
+	}
+}
@@ -0,0 +1,479 @@
+package devflow
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Dotfiles strategies of dotfiles.strategy
+const (
+	DotfilesSymlink = "symlink" // The home file is a symlink to the file in the repository
+	DotfilesCopy    = "copy"    // The home file and the repository file are copied both ways on sync
+)
+
+// DotfilesManifest lists the tracked files of a dotfiles repository, one
+// path relative to the home directory per line
+const DotfilesManifest = ".devflow-dotfiles"
+
+// dotfilesBackupSuffix is added to a home file that a sync replaces with
+// the tracked one
+const dotfilesBackupSuffix = ".pre-dotfiles"
+
+// Dotfiles manages a dotfiles repository: files of the home directory
+// kept in a git repository, at the same relative path, and pushed to its
+// origin with the Git handler
+type Dotfiles struct {
+	dir      string
+	home     string
+	strategy string
+	logger   Logger
+}
+
+// NewDotfiles creates a handler for the repository of dotfiles.dir in the
+// global config (default ~/.dotfiles) with the dotfiles.strategy
+func NewDotfiles() (*Dotfiles, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	d := &Dotfiles{dir: filepath.Join(home, ".dotfiles"), home: home, strategy: DotfilesSymlink, logger: NopLogger()}
+	if cfg, err := LoadGlobalConfig(); err == nil {
+		if dir := cfg.String("dotfiles.dir", ""); dir != "" {
+			d.SetDir(dir)
+		}
+		if err := d.SetStrategy(cfg.String("dotfiles.strategy", DotfilesSymlink)); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// SetLog sets the logger function
+func (d *Dotfiles) SetLog(fn func(...any)) {
+	if fn != nil {
+		d.SetLogger(FuncLogger(fn))
+	}
+}
+
+// SetLogger sets the leveled logger
+func (d *Dotfiles) SetLogger(l Logger) {
+	if l != nil {
+		d.logger = l
+	}
+}
+
+// SetDir sets the repository directory, "~/" meaning the home directory
+func (d *Dotfiles) SetDir(dir string) {
+	if rest, ok := strings.CutPrefix(dir, "~/"); ok {
+		dir = filepath.Join(d.home, rest)
+	}
+	d.dir = dir
+}
+
+// Dir returns the repository directory
+func (d *Dotfiles) Dir() string {
+	return d.dir
+}
+
+// SetStrategy sets how tracked files reach the home directory: symlink
+// (default) or copy, for systems or tools that do not follow symlinks
+func (d *Dotfiles) SetStrategy(strategy string) error {
+	switch strategy {
+	case DotfilesSymlink, DotfilesCopy:
+		d.strategy = strategy
+		return nil
+	}
+	return fmt.Errorf("unknown dotfiles strategy %q (want %s or %s)", strategy, DotfilesSymlink, DotfilesCopy)
+}
+
+// Init creates the dotfiles repository. With remote, an existing one is
+// cloned and its files placed in the home directory (see Sync), or origin
+// of a new one is set to it. An existing repository is left as it is.
+func (d *Dotfiles) Init(remote string) (string, error) {
+	if _, err := os.Stat(filepath.Join(d.dir, ".git")); err == nil {
+		return "✅ Dotfiles: " + d.dir + " already initialized", nil
+	}
+	if remote != "" {
+		if out, err := RunCommand("git", "clone", "--quiet", "--", remote, d.dir); err != nil {
+			return "", fmt.Errorf("git clone %s failed: %s", remote, firstLine(out))
+		}
+		if _, err := os.Stat(filepath.Join(d.dir, DotfilesManifest)); err == nil {
+			summary, err := d.Sync()
+			if err != nil {
+				return "", err
+			}
+			return "✅ Dotfiles: cloned " + remote + ", " + strings.TrimPrefix(summary, "✅ Dotfiles: "), nil
+		}
+		// An empty remote gives an empty repository with origin set
+	}
+
+	git, err := NewGit()
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(filepath.Join(d.dir, ".git")); err != nil {
+		if err := os.MkdirAll(d.dir, 0755); err != nil {
+			return "", err
+		}
+		if err := git.InitRepo(d.dir); err != nil {
+			return "", err
+		}
+		if remote != "" {
+			if out, err := RunCommandInDir(d.dir, "git", "remote", "add", "origin", remote); err != nil {
+				return "", fmt.Errorf("failed to add origin: %s", firstLine(out))
+			}
+		}
+	}
+	if err := d.writeManifest(nil); err != nil {
+		return "", err
+	}
+	if _, err := d.commit(git, "chore: init dotfiles"); err != nil {
+		return "", err
+	}
+	return "✅ Dotfiles: initialized " + d.dir, nil
+}
+
+// Track adds files or directories of the home directory to the repository
+// and commits them. With the symlink strategy the file moves into the
+// repository and a symlink replaces it; with copy it is copied.
+func (d *Dotfiles) Track(paths ...string) (string, error) {
+	tracked, err := d.Tracked()
+	if err != nil {
+		return "", err
+	}
+	known := make(map[string]bool, len(tracked))
+	for _, rel := range tracked {
+		known[rel] = true
+	}
+
+	var added []string
+	for _, path := range paths {
+		rel, err := d.homeRelative(path)
+		if err != nil {
+			return "", err
+		}
+		if known[rel] {
+			d.logger.Info(rel, "is already tracked")
+			continue
+		}
+		home, repo := filepath.Join(d.home, rel), filepath.Join(d.dir, rel)
+		if _, err := os.Lstat(home); err != nil {
+			return "", err
+		}
+		if err := os.MkdirAll(filepath.Dir(repo), 0755); err != nil {
+			return "", err
+		}
+		if err := copyPath(home, repo); err != nil {
+			return "", fmt.Errorf("failed to copy %s: %w", rel, err)
+		}
+		if d.strategy == DotfilesSymlink {
+			if err := os.RemoveAll(home); err != nil {
+				return "", err
+			}
+			if err := os.Symlink(repo, home); err != nil {
+				return "", fmt.Errorf("failed to link %s: %w", rel, err)
+			}
+		}
+		known[rel] = true
+		tracked = append(tracked, rel)
+		added = append(added, rel)
+	}
+	if len(added) == 0 {
+		return "✅ Dotfiles: nothing new to track", nil
+	}
+	if err := d.writeManifest(tracked); err != nil {
+		return "", err
+	}
+	git, err := NewGit()
+	if err != nil {
+		return "", err
+	}
+	if _, err := d.commit(git, "chore(dotfiles): track "+strings.Join(added, ", ")); err != nil {
+		return "", err
+	}
+	return "✅ Dotfiles: tracking " + strings.Join(added, ", "), nil
+}
+
+// Sync brings the home directory and the remote in line with the
+// repository: with the copy strategy the home files are first copied into
+// it; the changes are committed, the remote changes pulled, the tracked
+// files placed in the home directory (a different file there is kept as
+// <file>.pre-dotfiles) and the result pushed.
+func (d *Dotfiles) Sync() (string, error) {
+	tracked, err := d.Tracked()
+	if err != nil {
+		return "", err
+	}
+	git, err := NewGit()
+	if err != nil {
+		return "", err
+	}
+	var parts []string
+
+	if d.strategy == DotfilesCopy {
+		for _, rel := range tracked {
+			home := filepath.Join(d.home, rel)
+			if _, err := os.Lstat(home); err == nil {
+				if err := copyPath(home, filepath.Join(d.dir, rel)); err != nil {
+					return "", fmt.Errorf("failed to copy %s: %w", rel, err)
+				}
+			}
+		}
+	}
+	host, _ := os.Hostname()
+	committed, err := d.commit(git, fmt.Sprintf("chore(dotfiles): sync %s %s", host, time.Now().Format(backupTimeFormat)))
+	if err != nil {
+		return "", err
+	}
+	if committed {
+		parts = append(parts, "committed")
+	}
+
+	hasOrigin := false
+	if _, err := RunCommandInDir(d.dir, "git", "remote", "get-url", "origin"); err == nil {
+		hasOrigin = true
+		if _, err := RunCommandInDir(d.dir, "git", "rev-parse", "--abbrev-ref", "@{u}"); err == nil {
+			if out, err := RunCommandInDir(d.dir, "git", "pull", "--rebase", "--quiet"); err != nil {
+				return "", fmt.Errorf("git pull failed: %s", firstLine(out))
+			}
+			parts = append(parts, "pulled")
+		}
+	}
+
+	// The pull may have tracked more files
+	if tracked, err = d.Tracked(); err != nil {
+		return "", err
+	}
+	placed := 0
+	for _, rel := range tracked {
+		changed, err := d.place(rel)
+		if err != nil {
+			return "", fmt.Errorf("failed to place %s: %w", rel, err)
+		}
+		if changed {
+			placed++
+		}
+	}
+	if placed > 0 {
+		parts = append(parts, fmt.Sprintf("%d placed", placed))
+	}
+
+	if hasOrigin {
+		pushed := false
+		err := d.inRepo(func() error {
+			// Commits of an earlier sync whose push failed go out too
+			if unpushed, err := git.unpushedCount(); err != nil || unpushed == 0 {
+				return err
+			}
+			pushed = true
+			return git.pushBranch()
+		})
+		if err != nil {
+			return "", err
+		}
+		if pushed {
+			parts = append(parts, "pushed")
+		}
+	}
+	if len(parts) == 0 {
+		parts = append(parts, "up to date")
+	}
+	return fmt.Sprintf("✅ Dotfiles: %d tracked, %s", len(tracked), strings.Join(parts, ", ")), nil
+}
+
+// Tracked returns the files of the manifest, relative to the home
+// directory. The manifest may come from a cloned remote, so an entry that
+// Track would refuse (one leaving the home directory) fails.
+func (d *Dotfiles) Tracked() ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(d.dir, DotfilesManifest))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%s is not a dotfiles repository (run 'devflow dotfiles init')", d.dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var tracked []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entry := filepath.FromSlash(line)
+		if filepath.IsAbs(entry) {
+			return nil, fmt.Errorf("%s: %s is not relative to the home directory", DotfilesManifest, line)
+		}
+		rel, err := d.homeRelative(filepath.Join(d.home, entry))
+		if err != nil || rel != filepath.Clean(entry) {
+			return nil, fmt.Errorf("%s: %s leaves the home directory", DotfilesManifest, line)
+		}
+		tracked = append(tracked, rel)
+	}
+	return tracked, nil
+}
+
+// place puts the repository file rel in the home directory, reporting
+// whether anything changed
+func (d *Dotfiles) place(rel string) (bool, error) {
+	home, repo := filepath.Join(d.home, rel), filepath.Join(d.dir, rel)
+	if _, err := os.Lstat(repo); err != nil {
+		return false, err
+	}
+	info, err := os.Lstat(home)
+	if d.strategy == DotfilesSymlink {
+		if err == nil && info.Mode()&os.ModeSymlink != 0 {
+			if target, _ := os.Readlink(home); target == repo {
+				return false, nil
+			}
+		}
+	} else if err == nil && samePath(home, repo) {
+		return false, nil
+	}
+
+	if err == nil {
+		backup := dotfilesBackupPath(home)
+		d.logger.Info("Keeping the previous", rel, "as", filepath.Base(backup))
+		if err := os.Rename(home, backup); err != nil {
+			return false, err
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(home), 0755); err != nil {
+		return false, err
+	}
+	if d.strategy == DotfilesSymlink {
+		return true, os.Symlink(repo, home)
+	}
+	return true, copyPath(repo, home)
+}
+
+// dotfilesBackupPath returns where place keeps a replaced home file:
+// <path>.pre-dotfiles, numbered (.pre-dotfiles.1...) when an earlier
+// backup is there
+func dotfilesBackupPath(home string) string {
+	backup := home + dotfilesBackupSuffix
+	for n := 1; ; n++ {
+		if _, err := os.Lstat(backup); os.IsNotExist(err) {
+			return backup
+		}
+		backup = fmt.Sprintf("%s%s.%d", home, dotfilesBackupSuffix, n)
+	}
+}
+
+// homeRelative returns path (absolute, "~/" or relative to the current
+// directory) relative to the home directory, which it must be in
+func (d *Dotfiles) homeRelative(path string) (string, error) {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		path = filepath.Join(d.home, rest)
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(d.home, abs)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("%s is not in the home directory %s", path, d.home)
+	}
+	if repoRel, err := filepath.Rel(d.dir, abs); err == nil && !strings.HasPrefix(repoRel, "..") {
+		return "", fmt.Errorf("%s is in the dotfiles repository", path)
+	}
+	if repoRel, err := filepath.Rel(abs, d.dir); err == nil && !strings.HasPrefix(repoRel, "..") {
+		return "", fmt.Errorf("%s contains the dotfiles repository %s", path, d.dir)
+	}
+	return rel, nil
+}
+
+// writeManifest writes the sorted tracked files, creating the manifest
+func (d *Dotfiles) writeManifest(tracked []string) error {
+	path := filepath.Join(d.dir, DotfilesManifest)
+	if _, err := os.Stat(path); err == nil && tracked == nil {
+		return nil
+	}
+	lines := make([]string, 0, len(tracked))
+	for _, rel := range tracked {
+		lines = append(lines, filepath.ToSlash(rel))
+	}
+	sort.Strings(lines)
+	content := "# Files tracked by devflow dotfiles, relative to the home directory\n" + strings.Join(lines, "\n")
+	return os.WriteFile(path, []byte(strings.TrimRight(content, "\n")+"\n"), 0644)
+}
+
+// commit stages and commits the changes of the repository
+func (d *Dotfiles) commit(git *Git, message string) (committed bool, err error) {
+	err = d.inRepo(func() error {
+		if err := git.Add(); err != nil {
+			return fmt.Errorf("git add failed: %w", err)
+		}
+		committed, err = git.Commit(message)
+		return err
+	})
+	return committed, err
+}
+
+// inRepo runs fn in the repository directory, where the Git handler works
+func (d *Dotfiles) inRepo(fn func() error) error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	defer os.Chdir(wd)
+	if err := os.Chdir(d.dir); err != nil {
+		return err
+	}
+	return fn()
+}
+
+// samePath reports whether two files, or directory trees, have the same
+// content
+func samePath(a, b string) bool {
+	same := true
+	err := filepath.WalkDir(a, func(path string, e fs.DirEntry, err error) error {
+		if err != nil || !same {
+			return err
+		}
+		rel, _ := filepath.Rel(a, path)
+		if e.IsDir() {
+			return nil
+		}
+		x, errA := os.ReadFile(path)
+		y, errB := os.ReadFile(filepath.Join(b, rel))
+		same = errA == nil && errB == nil && bytes.Equal(x, y)
+		return nil
+	})
+	return err == nil && same
+}
+
+// copyPath copies the file or directory tree src to dst, keeping the file
+// modes; symlinks are copied as links
+func copyPath(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, e fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(src, path)
+		target := filepath.Join(dst, rel)
+		info, err := e.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case e.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0700)
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			os.Remove(target)
+			return os.Symlink(link, target)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, info.Mode().Perm())
+	})
+}
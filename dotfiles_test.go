@@ -0,0 +1,140 @@
+package devflow
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDotfilesSymlink(t *testing.T) {
+	tmp := testResumeEnv(t)
+	remote := filepath.Join(tmp, "dotfiles.git")
+	exec.Command("git", "init", "--bare", "-q", remote).Run()
+	os.WriteFile(filepath.Join(tmp, ".bashrc"), []byte("alias g=git\n"), 0644)
+	os.MkdirAll(filepath.Join(tmp, ".config", "nvim"), 0755)
+	os.WriteFile(filepath.Join(tmp, ".config", "nvim", "init.lua"), []byte("-- nvim\n"), 0644)
+
+	d, err := NewDotfiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Init(remote); err != nil {
+		t.Fatal(err)
+	}
+	summary, err := d.Track("~/.bashrc", filepath.Join(tmp, ".config", "nvim"), "~/.bashrc")
+	if err != nil || summary != "✅ Dotfiles: tracking .bashrc, .config/nvim" {
+		t.Fatalf("unexpected track result %q, %v", summary, err)
+	}
+	if target, _ := os.Readlink(filepath.Join(tmp, ".bashrc")); target != filepath.Join(d.Dir(), ".bashrc") {
+		t.Errorf("expected .bashrc linked into the repository, got %q", target)
+	}
+	if _, err := d.Track(filepath.Join(os.TempDir(), "outside")); err == nil {
+		t.Error("expected a path outside the home directory to fail")
+	}
+	if summary, err := d.Sync(); err != nil || summary != "✅ Dotfiles: 2 tracked, pushed" {
+		t.Fatalf("unexpected sync result %q, %v", summary, err)
+	}
+	if summary, _ := d.Sync(); summary != "✅ Dotfiles: 2 tracked, pulled" {
+		t.Errorf("expected nothing to push, got %q", summary)
+	}
+
+	// A new machine clones the repository and gets the links, keeping its own file
+	other := filepath.Join(tmp, "other")
+	os.MkdirAll(other, 0755)
+	os.WriteFile(filepath.Join(other, ".bashrc"), []byte("# default\n"), 0644)
+	os.WriteFile(filepath.Join(other, ".bashrc"+dotfilesBackupSuffix), []byte("# older\n"), 0644)
+	t.Setenv("HOME", other)
+	d2, _ := NewDotfiles()
+	summary, err = d2.Init(remote)
+	if err != nil || !strings.Contains(summary, "2 placed") {
+		t.Fatalf("unexpected init result %q, %v", summary, err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(other, ".config", "nvim", "init.lua")); string(data) != "-- nvim\n" {
+		t.Errorf("expected init.lua placed, got %q", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(other, ".bashrc"+dotfilesBackupSuffix+".1")); string(data) != "# default\n" {
+		t.Errorf("expected the previous .bashrc kept, got %q", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(other, ".bashrc"+dotfilesBackupSuffix)); string(data) != "# older\n" {
+		t.Errorf("expected the earlier backup kept, got %q", data)
+	}
+}
+
+func TestDotfilesTrackRejectsRepositoryParent(t *testing.T) {
+	tmp := testResumeEnv(t)
+	os.MkdirAll(filepath.Join(tmp, ".config", "nvim"), 0755)
+	os.WriteFile(filepath.Join(tmp, ".config", "nvim", "init.lua"), []byte("-- nvim\n"), 0644)
+
+	d, err := NewDotfiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.SetDir(filepath.Join(tmp, ".config", "dotfiles"))
+	if _, err := d.Init(""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Track("~/.config"); err == nil || !strings.Contains(err.Error(), "contains the dotfiles repository") {
+		t.Fatalf("expected a directory containing the repository to fail, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmp, ".config", "dotfiles", DotfilesManifest)); err != nil {
+		t.Error("expected the repository kept")
+	}
+	if _, err := os.Stat(filepath.Join(tmp, ".config", "nvim", "init.lua")); err != nil {
+		t.Error("expected ~/.config kept")
+	}
+}
+
+func TestDotfilesManifestLeavingHome(t *testing.T) {
+	tmp := testResumeEnv(t)
+	d, err := NewDotfiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Init(""); err != nil {
+		t.Fatal(err)
+	}
+
+	// A manifest of a cloned remote naming files outside the home directory
+	for _, entry := range []string{"../escaped", ".config/../../escaped", "/etc/escaped"} {
+		os.WriteFile(filepath.Join(d.Dir(), DotfilesManifest), []byte(entry+"\n"), 0644)
+		if _, err := d.Sync(); err == nil || !strings.Contains(err.Error(), DotfilesManifest) {
+			t.Errorf("%s: expected the manifest entry to fail, got %v", entry, err)
+		}
+	}
+	if _, err := os.Lstat(filepath.Join(filepath.Dir(tmp), "escaped")); !os.IsNotExist(err) {
+		t.Error("nothing should be placed outside the home directory")
+	}
+}
+
+func TestDotfilesCopy(t *testing.T) {
+	tmp := testResumeEnv(t)
+	os.WriteFile(filepath.Join(tmp, ".gitconfig.local"), []byte("a\n"), 0644)
+	d, _ := NewDotfiles()
+	if err := d.SetStrategy("hardlink"); err == nil {
+		t.Error("expected an unknown strategy to fail")
+	}
+	d.SetStrategy(DotfilesCopy)
+	d.SetDir("~/dots")
+	if _, err := d.Init(""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Track("~/.gitconfig.local"); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := os.Lstat(filepath.Join(tmp, ".gitconfig.local")); info.Mode()&os.ModeSymlink != 0 {
+		t.Error("expected the copy strategy to keep the home file")
+	}
+
+	os.WriteFile(filepath.Join(tmp, ".gitconfig.local"), []byte("b\n"), 0644)
+	if summary, err := d.Sync(); err != nil || summary != "✅ Dotfiles: 1 tracked, committed" {
+		t.Fatalf("unexpected sync result %q, %v", summary, err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(tmp, "dots", ".gitconfig.local")); string(data) != "b\n" {
+		t.Errorf("expected the change copied into the repository, got %q", data)
+	}
+	if summary, _ := d.Sync(); summary != "✅ Dotfiles: 1 tracked, up to date" {
+		t.Errorf("expected nothing to sync, got %q", summary)
+	}
+}
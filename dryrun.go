@@ -0,0 +1,198 @@
+package devflow
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// DryRunOutput receives the commands and writes skipped in dry-run mode
+var DryRunOutput io.Writer = os.Stdout
+
+// dryRun is the package-wide dry-run state. Orchestrators with DryRun set
+// enable it for the duration of the call, so every helper they use is
+// covered; nested calls (Go.Push -> Git.Push) just add a level.
+var dryRun struct {
+	sync.Mutex
+	depth   int
+	lastTag string // Last tag a skipped "git tag" would have created
+}
+
+// startDryRun enables dry-run mode until the returned function is called
+func startDryRun() func() {
+	dryRun.Lock()
+	dryRun.depth++
+	if dryRun.depth == 1 {
+		dryRun.lastTag = ""
+	}
+	dryRun.Unlock()
+	return func() {
+		dryRun.Lock()
+		dryRun.depth--
+		dryRun.Unlock()
+	}
+}
+
+// DryRunActive reports whether commands that change state are skipped
+func DryRunActive() bool {
+	dryRun.Lock()
+	defer dryRun.Unlock()
+	return dryRun.depth > 0
+}
+
+// dryRunTag returns the tag a skipped "git tag" would have created
+func dryRunTag() string {
+	dryRun.Lock()
+	defer dryRun.Unlock()
+	return dryRun.lastTag
+}
+
+// resetDryRunTag forgets the last skipped tag, so a caller running several
+// Pushes can tell whether the next one creates a tag
+func resetDryRunTag() {
+	dryRun.Lock()
+	dryRun.lastTag = ""
+	dryRun.Unlock()
+}
+
+// dryRunf prints a step that dry-run mode skips, with its working directory
+func dryRunf(dir, format string, args ...any) {
+	if dir == "" || dir == "." {
+		dir, _ = os.Getwd()
+	} else if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	fmt.Fprintf(DryRunOutput, "[dry-run] %s  (in %s)\n", fmt.Sprintf(format, args...), dir)
+}
+
+// skipInDryRun reports whether the command must not run and prints it.
+// Read-only commands still run so the plan reflects the real state.
+func skipInDryRun(dir, name string, args []string) bool {
+	if !DryRunActive() || readOnlyCommand(name, args) {
+		return false
+	}
+	if name == "git" && len(args) >= 2 && args[0] == "tag" {
+		if tag := tagOperand(args[1:]); tag != "" {
+			dryRun.Lock()
+			dryRun.lastTag = tag
+			dryRun.Unlock()
+		}
+	}
+	dryRunf(dir, "%s", formatCommand(name, args))
+	return true
+}
+
+// writeUnlessDryRun writes a file, or only prints it in dry-run mode
+func writeUnlessDryRun(path string, data []byte) error {
+	if DryRunActive() {
+		dryRunf(filepath.Dir(path), "write %s", filepath.Base(path))
+		return nil
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// formatCommand renders a command line, quoting arguments with spaces
+func formatCommand(name string, args []string) string {
+	parts := []string{name}
+	for _, a := range args {
+		if a == "" || strings.ContainsAny(a, " \t\n\"'") {
+			a = fmt.Sprintf("%q", a)
+		}
+		parts = append(parts, a)
+	}
+	return strings.Join(parts, " ")
+}
+
+// readOnlyCommand reports whether a command only inspects state. Unknown
+// commands are assumed to change something.
+func readOnlyCommand(name string, args []string) bool {
+	sub, rest := "", []string(nil)
+	for i, a := range args {
+		if !strings.HasPrefix(a, "-") {
+			sub, rest = a, args[i+1:]
+			break
+		}
+	}
+	if sub == "" {
+		// "git --version", "gh --version"
+		return len(args) > 0 && (args[0] == "--version" || args[0] == "version")
+	}
+
+	switch filepath.Base(name) {
+	case "git":
+		switch sub {
+		case "status", "rev-parse", "log", "diff", "diff-index", "show", "describe", "ls-remote",
+			"ls-files", "rev-list", "for-each-ref", "cat-file", "merge-base", "check-ignore",
+			"verify-tag", "verify-commit", "shortlog", "blame", "name-rev", "var", "version", "check-ref-format":
+			return true
+		case "symbolic-ref":
+			return countOperands(rest) <= 1
+		case "config":
+			return countOperands(rest) <= 1 || hasAny(rest, "--get", "--get-all", "--list", "-l")
+		case "tag":
+			return countOperands(rest) == 0 || hasAny(rest, "-l", "--list", "--contains", "--points-at")
+		case "branch":
+			return countOperands(rest) == 0 && !hasAny(rest, "-M", "-m", "-D", "-d", "-u", "--set-upstream-to")
+		case "remote":
+			return len(rest) == 0 || hasAny(rest, "-v") || (len(rest) > 0 && (rest[0] == "get-url" || rest[0] == "show"))
+		case "worktree", "stash":
+			return len(rest) > 0 && rest[0] == "list"
+		}
+	case "gh", "glab":
+		switch sub {
+		case "auth":
+			return len(rest) > 0 && rest[0] == "status"
+		case "repo", "release", "pr":
+			return len(rest) > 0 && (rest[0] == "view" || rest[0] == "list")
+		case "api":
+			return !hasAny(rest, "-X", "--method", "-f", "-F", "--field", "--raw-field", "--input")
+		}
+	case "go":
+		switch sub {
+		case "list", "env", "version", "vet", "doc", "help":
+			return true
+		case "mod":
+			return len(rest) > 0 && (rest[0] == "verify" || rest[0] == "graph" || rest[0] == "why" || rest[0] == "download")
+		}
+	}
+	return false
+}
+
+// countOperands counts the arguments that are not flags
+func countOperands(args []string) int {
+	n := 0
+	for _, a := range args {
+		if !strings.HasPrefix(a, "-") {
+			n++
+		}
+	}
+	return n
+}
+
+// tagOperand returns the tag name of "git tag" arguments, skipping the
+// values of -m and -F
+func tagOperand(args []string) string {
+	for i := 0; i < len(args); i++ {
+		switch a := args[i]; {
+		case a == "-m" || a == "-F" || a == "--message" || a == "--file":
+			i++
+		case !strings.HasPrefix(a, "-"):
+			return a
+		}
+	}
+	return ""
+}
+
+func hasAny(args []string, flags ...string) bool {
+	for _, a := range args {
+		for _, f := range flags {
+			if a == f || strings.HasPrefix(a, f+"=") {
+				return true
+			}
+		}
+	}
+	return false
+}
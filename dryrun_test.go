@@ -0,0 +1,118 @@
+package devflow
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadOnlyCommand(t *testing.T) {
+	tests := []struct {
+		cmd  string
+		want bool
+	}{
+		{"git status --porcelain", true},
+		{"git rev-parse HEAD", true},
+		{"git symbolic-ref --short HEAD", true},
+		{"git config user.name", true},
+		{"git config --global user.name Bob", false},
+		{"git tag", true},
+		{"git tag v1.0.0", false},
+		{"git branch -M main", false},
+		{"git remote", true},
+		{"git remote add origin x", false},
+		{"git add .", false},
+		{"git commit -m msg", false},
+		{"git push origin v1.0.0", false},
+		{"git --version", true},
+		{"gh api user --jq .login", true},
+		{"gh api -X PATCH repos/o/r", false},
+		{"gh repo view o/r", true},
+		{"gh repo create o/r --public", false},
+		{"go list -m", true},
+		{"go mod verify", true},
+		{"go mod tidy", false},
+		{"go get example.com/m@v1.0.0", false},
+		{"sh -c ./setup.sh", false},
+	}
+	for _, tt := range tests {
+		f := strings.Fields(tt.cmd)
+		if got := readOnlyCommand(f[0], f[1:]); got != tt.want {
+			t.Errorf("readOnlyCommand(%q) = %v, want %v", tt.cmd, got, tt.want)
+		}
+	}
+}
+
+func testDryRunOutput(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	old := DryRunOutput
+	DryRunOutput = &buf
+	t.Cleanup(func() { DryRunOutput = old })
+	return &buf
+}
+
+func TestGitPushDryRun(t *testing.T) {
+	defer testPushedRepo(t)()
+	out := testDryRunOutput(t)
+	os.WriteFile("new.go", []byte("package x\n"), 0644)
+
+	git, err := NewGit()
+	if err != nil {
+		t.Skip("git not installed")
+	}
+	git.SetDryRun(true)
+	if _, err := git.Push("feat: dry", ""); err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+
+	for _, want := range []string{"git add .", `git commit -m "feat: dry"`, "git tag v0.1.0", "git push origin v0.1.0"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("dry-run output missing %q:\n%s", want, out)
+		}
+	}
+	if n := testCommitCount(t); n != 1 {
+		t.Errorf("dry run created commits: %d", n)
+	}
+	if exists, _ := git.TagExists("v0.1.0"); exists {
+		t.Error("dry run created tag v0.1.0")
+	}
+	if DryRunActive() {
+		t.Error("dry-run mode still active after Push")
+	}
+}
+
+func TestGoNewCreateDryRun(t *testing.T) {
+	tmpDir := t.TempDir()
+	defer testChdir(t, tmpDir)()
+	t.Setenv("HOME", tmpDir)
+	os.WriteFile(filepath.Join(tmpDir, ".gitconfig"), []byte("[user]\n\tname = Test User\n\temail = test@example.com\n"), 0644)
+	out := testDryRunOutput(t)
+
+	git, err := NewGit()
+	if err != nil {
+		t.Skip("git not installed")
+	}
+	goHandler, _ := NewGo(git)
+	gn := NewGoNew(git, nil, goHandler)
+	gn.SetDryRun(true)
+
+	target := filepath.Join(tmpDir, "dry-project")
+	summary, err := gn.Create(NewProjectOptions{Name: "dry-project", Description: "Dry run", LocalOnly: true, Directory: target})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if !strings.HasPrefix(summary, "[dry-run]") {
+		t.Errorf("summary = %q", summary)
+	}
+	if _, err := os.Stat(target); !os.IsNotExist(err) {
+		t.Error("dry run created the project directory")
+	}
+	for _, want := range []string{"git init " + target, "go mod init github.com/testuser/dry-project", "git tag v0.0.1"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("dry-run output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package devflow
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
 	"runtime"
 	"strings"
@@ -14,14 +15,40 @@ var ExecCommand = exec.Command
 // RunCommand executes a shell command
 // It returns the output (trimmed) and an error if the command fails
 func RunCommand(name string, args ...string) (string, error) {
-	// Execute
-	cmd := ExecCommand(name, args...)
-	outputBytes, err := cmd.CombinedOutput()
+	if skipInDryRun("", name, args) {
+		return "", nil
+	}
+	return runCommand(&Command{Name: name, Args: args})
+}
+
+// execCommand runs cmd, the innermost runner of the middleware chain
+func execCommand(c *Command) (string, error) {
+	cmd := ExecCommand(c.Name, c.Args...)
+	cmd.Dir = c.Dir
+	cmd.Stdin = c.Stdin
+	if len(c.Env) > 0 {
+		cmd.Env = append(os.Environ(), c.Env...)
+	}
+	if c.Stdout != nil || c.start != nil {
+		cmd.Stdout, cmd.Stderr = c.Stdout, c.Stderr
+		if c.Stderr == nil {
+			cmd.Stderr = c.Stdout
+		}
+		start := c.start
+		if start == nil {
+			start = runTracked
+		}
+		return "", start(cmd)
+	}
+	outputBytes, err := combinedOutput(cmd)
 	output := strings.TrimSpace(string(outputBytes))
 
 	if err != nil {
-		cmdStr := name + " " + strings.Join(args, " ")
-		return output, fmt.Errorf("command failed: %s\nError: %w\nOutput: %s", cmdStr, err, output)
+		cmdStr := c.Name + " " + strings.Join(c.Args, " ")
+		if c.Dir == "" {
+			return output, fmt.Errorf("command failed: %s\nError: %w\nOutput: %s", cmdStr, err, output)
+		}
+		return output, fmt.Errorf("command failed in %s: %s\nError: %w\nOutput: %s", c.Dir, cmdStr, err, output)
 	}
 
 	return output, nil
@@ -46,34 +73,45 @@ func RunShellCommand(command string) (string, error) {
 	}
 }
 
+// RunShellCommandInDir executes a shell command in dir with extra
+// environment variables (KEY=value), using cmd.exe on Windows and sh elsewhere
+func RunShellCommandInDir(dir, command string, env ...string) (string, error) {
+	if runtime.GOOS == "windows" {
+		return RunCommandWithEnvInDir(dir, env, "cmd.exe", "/C", command)
+	}
+	return RunCommandWithEnvInDir(dir, env, "sh", "-c", command)
+}
+
 // RunShellCommandAsync starts a shell command asynchronously (non-blocking)
 // Returns immediately after starting, does not wait for completion
 func RunShellCommandAsync(command string) error {
-	var cmd *exec.Cmd
-
-	switch runtime.GOOS {
-	case "windows":
-		cmd = exec.Command("cmd.exe", "/C", command)
-	default: // linux, darwin, etc.
-		cmd = exec.Command("sh", "-c", command)
+	if DryRunActive() {
+		dryRunf("", "%s &", command)
+		return nil
 	}
-
-	return cmd.Start()
+	cmd := &Command{Name: "sh", Args: []string{"-c", command}, start: (*exec.Cmd).Start}
+	if runtime.GOOS == "windows" {
+		cmd.Name, cmd.Args = "cmd.exe", []string{"/C", command}
+	}
+	_, err := runCommand(cmd)
+	return err
 }
 
 // RunCommandInDir executes a command in a specific directory
 func RunCommandInDir(dir, name string, args ...string) (string, error) {
-	cmd := ExecCommand(name, args...)
-	cmd.Dir = dir
-	outputBytes, err := cmd.CombinedOutput()
-	output := strings.TrimSpace(string(outputBytes))
-
-	if err != nil {
-		cmdStr := name + " " + strings.Join(args, " ")
-		return output, fmt.Errorf("command failed in %s: %s\nError: %w\nOutput: %s", dir, cmdStr, err, output)
+	if skipInDryRun(dir, name, args) {
+		return "", nil
 	}
+	return runCommand(&Command{Name: name, Args: args, Dir: dir})
+}
 
-	return output, nil
+// RunCommandWithEnvInDir executes a command in a specific directory with extra
+// environment variables (KEY=value) appended to the current environment
+func RunCommandWithEnvInDir(dir string, env []string, name string, args ...string) (string, error) {
+	if skipInDryRun(dir, name, args) {
+		return "", nil
+	}
+	return runCommand(&Command{Name: name, Args: args, Dir: dir, Env: env})
 }
 
 // RunCommandWithRetryInDir executes a command in a specific directory with retries
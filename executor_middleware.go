@@ -0,0 +1,113 @@
+package devflow
+
+import (
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// Command is a command run through the executor (RunCommand,
+// RunCommandInDir, RunCommandWithEnvInDir and the shell variants, and the
+// streamed go test, lint and govulncheck runs), as seen by the middleware. Middleware may change it before calling next, e.g.
+// to run Name inside a sandbox.
+type Command struct {
+	Name string
+	Args []string
+	Dir  string   // Working directory, empty for the current one
+	Env  []string // KEY=value variables added to the environment
+
+	Stdin io.Reader // Input of the process, e.g. a token; nil for none
+
+	// Stdout and Stderr (default Stdout) receive the output as it is
+	// written, e.g. of go test; the runner then returns no output and the
+	// bare process error
+	Stdout io.Writer
+	Stderr io.Writer
+
+	start func(cmd *exec.Cmd) error // Runs the process of a streamed command (default runTracked)
+}
+
+// String returns the command line, quoted like the dry-run output
+func (c *Command) String() string {
+	return formatCommand(c.Name, c.Args)
+}
+
+// CommandResult is the outcome of a Command passed to the after hooks
+type CommandResult struct {
+	Output   string // Trimmed combined output
+	Err      error
+	Duration time.Duration
+}
+
+// CommandRunner runs cmd and returns its trimmed output, also on error
+type CommandRunner func(cmd *Command) (string, error)
+
+// Middleware wraps the runner of the executor. It can inspect or rewrite
+// cmd, refuse it by returning an error without calling next, and observe
+// the output and error next returns.
+type Middleware func(next CommandRunner) CommandRunner
+
+// middlewares is the chain of UseMiddleware, outermost first
+var middlewares struct {
+	sync.Mutex
+	chain []*Middleware
+}
+
+// UseMiddleware adds mw to the executor chain of the process. The first
+// one added runs outermost, so it sees the command before the others do.
+// Commands skipped in dry-run mode do not reach the chain. The returned
+// function removes mw again: defer UseMiddleware(mw)()
+func UseMiddleware(mw Middleware) (remove func()) {
+	m := &mw
+	middlewares.Lock()
+	middlewares.chain = append(middlewares.chain, m)
+	middlewares.Unlock()
+	return func() {
+		middlewares.Lock()
+		defer middlewares.Unlock()
+		for i, other := range middlewares.chain {
+			if other == m {
+				middlewares.chain = append(middlewares.chain[:i], middlewares.chain[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// CommandHooks returns a Middleware calling before ahead of every command
+// and after once it finished. A before error stops the command and is
+// returned as its error (after still sees it). Either hook may be nil.
+func CommandHooks(before func(cmd *Command) error, after func(cmd *Command, result CommandResult)) Middleware {
+	return func(next CommandRunner) CommandRunner {
+		return func(cmd *Command) (string, error) {
+			start := time.Now()
+			var out string
+			var err error
+			if before != nil {
+				err = before(cmd)
+			}
+			if err == nil {
+				out, err = next(cmd)
+			}
+			if after != nil {
+				after(cmd, CommandResult{Output: out, Err: err, Duration: time.Since(start)})
+			}
+			return out, err
+		}
+	}
+}
+
+// runCommand runs cmd through the middleware chain, then execCommand
+func runCommand(cmd *Command) (string, error) {
+	middlewares.Lock()
+	chain := make([]*Middleware, len(middlewares.chain))
+	copy(chain, middlewares.chain)
+	middlewares.Unlock()
+
+	run := CommandRunner(execCommand)
+	for i := len(chain) - 1; i >= 0; i-- {
+		run = (*chain[i])(run)
+	}
+	return run(cmd)
+}
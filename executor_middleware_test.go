@@ -0,0 +1,83 @@
+package devflow
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestExecutorMiddleware(t *testing.T) {
+	var calls []string
+	trace := func(label string) Middleware {
+		return func(next CommandRunner) CommandRunner {
+			return func(cmd *Command) (string, error) {
+				calls = append(calls, label+" "+cmd.String())
+				return next(cmd)
+			}
+		}
+	}
+	// A sandbox prefix: "echo hi" runs as "sh -c 'echo sandboxed $0' hi"
+	sandbox := func(next CommandRunner) CommandRunner {
+		return func(cmd *Command) (string, error) {
+			cmd.Args = append([]string{"-c", "echo sandboxed $0", cmd.Name}, cmd.Args...)
+			cmd.Name = "sh"
+			return next(cmd)
+		}
+	}
+	var results []CommandResult
+	removeTrace := UseMiddleware(trace("outer"))
+	removeSandbox := UseMiddleware(sandbox)
+	removeHooks := UseMiddleware(CommandHooks(func(cmd *Command) error {
+		if cmd.Args[len(cmd.Args)-1] == "forbidden" {
+			return errors.New("blocked by policy")
+		}
+		return nil
+	}, func(cmd *Command, r CommandResult) {
+		results = append(results, r)
+	}))
+
+	out, err := RunCommandInDir(t.TempDir(), "echo", "hi")
+	if err != nil || out != "sandboxed echo" {
+		t.Fatalf("expected the sandboxed command, got %q, %v", out, err)
+	}
+	if len(calls) != 1 || calls[0] != "outer echo hi" {
+		t.Errorf("the first middleware should see the original command, got %v", calls)
+	}
+	if len(results) != 1 || results[0].Output != "sandboxed echo" || results[0].Duration <= 0 {
+		t.Errorf("unexpected after hook results %+v", results)
+	}
+
+	if _, err := RunCommand("echo", "forbidden"); err == nil || !strings.Contains(err.Error(), "blocked by policy") {
+		t.Errorf("expected policy error, got %v", err)
+	}
+	if len(results) != 2 || results[1].Err == nil {
+		t.Errorf("the after hook should see the refusal, got %+v", results)
+	}
+
+	removeHooks()
+	removeSandbox()
+	removeTrace()
+	if out, err := RunCommand("echo", "plain"); err != nil || out != "plain" || len(calls) != 2 {
+		t.Errorf("removed middleware should not run, got %q, %v, %v", out, err, calls)
+	}
+}
+
+func TestExecutorMiddlewareSeesDirectCommands(t *testing.T) {
+	var seen []string
+	defer UseMiddleware(CommandHooks(func(cmd *Command) error {
+		seen = append(seen, cmd.String())
+		return errors.New("blocked by policy")
+	}, nil))()
+
+	m := NewGoModHandler()
+	m.SetRootDir(t.TempDir())
+	if err := m.RunTidy(); err == nil || !strings.Contains(err.Error(), "blocked by policy") {
+		t.Errorf("go mod tidy should be refused by the middleware, got %v", err)
+	}
+	if err := RunShellCommandAsync("true"); err == nil {
+		t.Error("async command should be refused by the middleware")
+	}
+	if len(seen) != 2 || seen[0] != "go mod tidy" {
+		t.Errorf("middleware should see both commands, got %v", seen)
+	}
+}
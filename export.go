@@ -0,0 +1,486 @@
+package devflow
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ExportFormat is the version of the archive layout written by Export
+const ExportFormat = 1
+
+// Files of an export archive
+const (
+	exportManifestFile = "export.json"
+	exportBundleFile   = "repo.bundle"
+	exportConfigDir    = "config"
+	exportReleasesDir  = "releases"
+)
+
+// exportMetadata are the GitHub API listings saved as <name>.json
+var exportMetadata = []struct{ name, path string }{
+	{"repo", "repos/%s"},
+	{"issues", "repos/%s/issues?state=all&per_page=100"},
+	{"pulls", "repos/%s/pulls?state=all&per_page=100"},
+	{"comments", "repos/%s/issues/comments?per_page=100"},
+	{"review-comments", "repos/%s/pulls/comments?per_page=100"},
+	{"labels", "repos/%s/labels?per_page=100"},
+	{"milestones", "repos/%s/milestones?state=all&per_page=100"},
+	{"releases", "repos/%s/releases?per_page=100"},
+}
+
+// ImportArchiveDir is where Import keeps the metadata of an archive,
+// inside the git directory so it is neither committed nor pushed
+const ImportArchiveDir = "devflow-export"
+
+// ExportManifest describes the contents of an export archive
+type ExportManifest struct {
+	Format   int       `json:"format"`
+	Name     string    `json:"name"`
+	Module   string    `json:"module,omitempty"`
+	Remote   string    `json:"remote,omitempty"`
+	Repo     string    `json:"repo,omitempty"` // owner/name on GitHub
+	Head     string    `json:"head"`           // Branch checked out on import
+	Branches []string  `json:"branches"`
+	Tags     []string  `json:"tags"`
+	Created  time.Time `json:"created"`
+	Files    []string  `json:"files"`
+	Warnings []string  `json:"warnings,omitempty"` // Parts that could not be exported
+}
+
+// Exporter writes and restores portable archives of a project: a git
+// bundle of every branch and tag, the project config files and, for
+// GitHub repositories, the issues, pull requests, releases and their
+// assets fetched with gh api.
+type Exporter struct {
+	metadata bool
+	log      func(...any)
+}
+
+// NewExporter creates an exporter that includes the GitHub metadata
+func NewExporter() *Exporter {
+	return &Exporter{metadata: true, log: func(...any) {}}
+}
+
+// SetLog sets the logger function
+func (e *Exporter) SetLog(fn func(...any)) {
+	if fn != nil {
+		e.log = fn
+	}
+}
+
+// SetMetadata sets whether issues, pull requests and releases are exported
+func (e *Exporter) SetMetadata(enabled bool) {
+	e.metadata = enabled
+}
+
+// ExportArchiveName returns the default archive file name, e.g.
+// "my-lib-export-20261014.tar.gz"
+func ExportArchiveName(name string, now time.Time) string {
+	return fmt.Sprintf("%s-export-%s.tar.gz", name, now.Format("20060102"))
+}
+
+// Export writes the archive of the git repository in dir to archive.
+// Metadata that cannot be fetched (gh missing, air-gap, other providers)
+// is recorded in the manifest warnings instead of failing the export.
+func (e *Exporter) Export(dir, archive string) (m ExportManifest, err error) {
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return m, err
+	}
+	top, err := RunCommandInDir(dir, "git", "rev-parse", "--show-toplevel")
+	if err != nil {
+		return m, fmt.Errorf("%s is not a git repository", dir)
+	}
+	dir = top
+
+	m = ExportManifest{Format: ExportFormat, Name: filepath.Base(dir), Created: time.Now().UTC()}
+	if module, err := ReadModule(dir); err == nil {
+		m.Module = module.Path
+	}
+	m.Head, _ = RunCommandInDir(dir, "git", "symbolic-ref", "--short", "HEAD")
+	if out, _ := RunCommandInDir(dir, "git", "for-each-ref", "--format=%(refname:short)", "refs/heads"); out != "" {
+		m.Branches = strings.Split(out, "\n")
+	}
+	if out, _ := RunCommandInDir(dir, "git", "tag", "--list"); out != "" {
+		m.Tags = strings.Split(out, "\n")
+	}
+	if len(m.Branches) == 0 {
+		return m, fmt.Errorf("%s has no commits to export", dir)
+	}
+	if m.Remote, _ = RunCommandInDir(dir, "git", "remote", "get-url", "origin"); m.Remote != "" {
+		if host, owner, name, ok := ParseRemoteURL(m.Remote); ok && host == "github.com" {
+			m.Repo = owner + "/" + name
+		}
+	}
+
+	stage, err := os.MkdirTemp("", "devflow-export-")
+	if err != nil {
+		return m, err
+	}
+	defer os.RemoveAll(stage)
+	defer OnInterrupt(func() { os.RemoveAll(stage) })()
+
+	e.log("Bundling", len(m.Branches), "branches and", len(m.Tags), "tags")
+	if _, err := RunCommandInDir(dir, "git", "bundle", "create", filepath.Join(stage, exportBundleFile), "--branches", "--tags"); err != nil {
+		return m, fmt.Errorf("git bundle failed: %w", err)
+	}
+	m.Files = append(m.Files, exportBundleFile)
+
+	for _, name := range ConfigFileNames {
+		if data, err := os.ReadFile(filepath.Join(dir, name)); err == nil {
+			if err := writeExportFile(stage, filepath.Join(exportConfigDir, name), data); err != nil {
+				return m, err
+			}
+			m.Files = append(m.Files, exportConfigDir+"/"+name)
+		}
+	}
+
+	if e.metadata {
+		e.exportMetadata(stage, &m)
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return m, err
+	}
+	if err := writeExportFile(stage, exportManifestFile, append(data, '\n')); err != nil {
+		return m, err
+	}
+	return m, writeTarGz(archive, stage)
+}
+
+// exportMetadata saves the GitHub listings and release assets into stage
+func (e *Exporter) exportMetadata(stage string, m *ExportManifest) {
+	switch {
+	case m.Repo == "" && m.Remote == "":
+		m.Warnings = append(m.Warnings, "metadata: no origin remote")
+		return
+	case m.Repo == "":
+		m.Warnings = append(m.Warnings, "metadata: only GitHub remotes are supported ("+m.Remote+")")
+		return
+	}
+	if err := AirGapCheck(NetGitHub); err != nil {
+		m.Warnings = append(m.Warnings, "metadata: "+err.Error())
+		return
+	}
+	if _, err := RunCommandSilent("gh", "--version"); err != nil {
+		m.Warnings = append(m.Warnings, "metadata: gh CLI not installed")
+		return
+	}
+
+	var releases []struct {
+		Tag    string `json:"tag_name"`
+		Assets []struct {
+			Name string `json:"name"`
+		} `json:"assets"`
+	}
+	for _, item := range exportMetadata {
+		e.log("Exporting", item.name)
+		data, err := ghAPIPages(fmt.Sprintf(item.path, m.Repo))
+		if err != nil {
+			m.Warnings = append(m.Warnings, fmt.Sprintf("%s: %v", item.name, firstLine(err.Error())))
+			continue
+		}
+		if item.name == "releases" {
+			json.Unmarshal(data, &releases)
+		}
+		if err := writeExportFile(stage, item.name+".json", data); err != nil {
+			m.Warnings = append(m.Warnings, fmt.Sprintf("%s: %v", item.name, err))
+			continue
+		}
+		m.Files = append(m.Files, item.name+".json")
+	}
+
+	for _, r := range releases {
+		if len(r.Assets) == 0 {
+			continue
+		}
+		e.log("Downloading assets of", r.Tag)
+		dest := filepath.Join(stage, exportReleasesDir, r.Tag)
+		if _, err := RunCommandSilent("gh", "release", "download", r.Tag, "--repo", m.Repo, "--dir", dest); err != nil {
+			m.Warnings = append(m.Warnings, fmt.Sprintf("release %s assets: %v", r.Tag, firstLine(err.Error())))
+			continue
+		}
+		for _, a := range r.Assets {
+			m.Files = append(m.Files, exportReleasesDir+"/"+r.Tag+"/"+a.Name)
+		}
+	}
+}
+
+// ghAPIPages fetches every page of a gh api listing as one JSON value
+func ghAPIPages(path string) ([]byte, error) {
+	out, err := RunCommandSilent("gh", "api", "--paginate", path)
+	if err != nil {
+		return nil, err
+	}
+	return mergeJSONPages(out)
+}
+
+// mergeJSONPages joins the arrays gh api --paginate prints one after the
+// other into a single array; a single object is returned as it is
+func mergeJSONPages(out string) ([]byte, error) {
+	dec := json.NewDecoder(strings.NewReader(out))
+	var merged []json.RawMessage
+	for {
+		var page json.RawMessage
+		if err := dec.Decode(&page); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("gh api output: %w", err)
+		}
+		var items []json.RawMessage
+		if err := json.Unmarshal(page, &items); err != nil {
+			return page, nil // Not a listing
+		}
+		merged = append(merged, items...)
+	}
+	if merged == nil {
+		merged = []json.RawMessage{}
+	}
+	return json.MarshalIndent(merged, "", "  ")
+}
+
+// remoteURLRe matches https, ssh and scp-like remotes:
+// https://github.com/o/r.git, ssh://git@host/o/r, git@github.com:o/r.git
+var remoteURLRe = regexp.MustCompile(`^(?:[a-z+]+://)?(?:[^@/]+@)?([^/:]+)(?::\d+)?[:/](.+)/([^/]+?)(?:\.git)?/?$`)
+
+// ParseRemoteURL returns the host, owner (GitLab subgroups included) and
+// repository name of a git remote URL
+func ParseRemoteURL(url string) (host, owner, name string, ok bool) {
+	m := remoteURLRe.FindStringSubmatch(strings.TrimSpace(url))
+	if m == nil || strings.HasPrefix(url, "/") || strings.HasPrefix(url, "file:") {
+		return "", "", "", false
+	}
+	return m[1], m[2], m[3], true
+}
+
+// Import restores an export archive into dir (default: the exported name
+// in the current directory): every branch and tag from the bundle, the
+// exported config files missing from the checkout, and the metadata under
+// .git/devflow-export. With remote, origin is set to it and all branches
+// and tags are pushed there, e.g. a new repository on another provider.
+func (e *Exporter) Import(archive, dir, remote string) (m ExportManifest, err error) {
+	stage, err := os.MkdirTemp("", "devflow-import-")
+	if err != nil {
+		return m, err
+	}
+	defer os.RemoveAll(stage)
+	if err := extractTarGz(archive, stage); err != nil {
+		return m, err
+	}
+	data, err := os.ReadFile(filepath.Join(stage, exportManifestFile))
+	if err != nil {
+		return m, fmt.Errorf("%s is not a devflow export: %w", archive, err)
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return m, fmt.Errorf("%s: %w", exportManifestFile, err)
+	}
+	if m.Format > ExportFormat {
+		return m, fmt.Errorf("archive format %d is newer than supported (%d); update devflow", m.Format, ExportFormat)
+	}
+
+	if dir == "" {
+		dir = m.Name
+	}
+	if dir, err = filepath.Abs(dir); err != nil {
+		return m, err
+	}
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+		return m, fmt.Errorf("directory %s already exists and is not empty", dir)
+	}
+
+	bundle := filepath.Join(stage, exportBundleFile)
+	if _, err := RunCommand("git", "bundle", "verify", bundle); err != nil {
+		return m, fmt.Errorf("bundle verification failed: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return m, err
+	}
+	defer OnInterrupt(func() { os.RemoveAll(dir) })()
+
+	e.log("Restoring", len(m.Branches), "branches and", len(m.Tags), "tags into", dir)
+	steps := [][]string{
+		{"init", "--quiet"},
+		{"fetch", "--quiet", "--update-head-ok", bundle, "refs/heads/*:refs/heads/*", "refs/tags/*:refs/tags/*"},
+	}
+	if m.Head != "" {
+		steps = append(steps, []string{"symbolic-ref", "HEAD", "refs/heads/" + m.Head})
+	}
+	steps = append(steps, []string{"reset", "--hard", "--quiet"})
+	for _, args := range steps {
+		if _, err := RunCommandInDir(dir, "git", args...); err != nil {
+			return m, err
+		}
+	}
+
+	// Config files that were not committed
+	for _, name := range ConfigFileNames {
+		data, err := os.ReadFile(filepath.Join(stage, exportConfigDir, name))
+		if err != nil {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dir, name)); os.IsNotExist(err) {
+			if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+				return m, err
+			}
+		}
+	}
+
+	// Remaining files: the manifest and the metadata
+	os.Remove(bundle)
+	os.RemoveAll(filepath.Join(stage, exportConfigDir))
+	if err := moveTree(stage, filepath.Join(dir, ".git", ImportArchiveDir)); err != nil {
+		return m, err
+	}
+
+	if remote != "" {
+		if _, err := RunCommandInDir(dir, "git", "remote", "add", "origin", remote); err != nil {
+			return m, err
+		}
+		e.log("Pushing to", remote)
+		if _, err := RunCommandInDir(dir, "git", "push", "origin", "--all"); err != nil {
+			return m, err
+		}
+		if _, err := RunCommandInDir(dir, "git", "push", "origin", "--tags"); err != nil {
+			return m, err
+		}
+	}
+	return m, nil
+}
+
+// Summary describes the manifest contents in one line
+func (m ExportManifest) Summary() string {
+	metadata := 0
+	for _, f := range m.Files {
+		if f != exportBundleFile && !strings.HasPrefix(f, exportConfigDir+"/") {
+			metadata++
+		}
+	}
+	s := fmt.Sprintf("%s: %d branches, %d tags, %d metadata files", m.Name, len(m.Branches), len(m.Tags), metadata)
+	if len(m.Warnings) > 0 {
+		s += fmt.Sprintf(", %d warnings", len(m.Warnings))
+	}
+	return s
+}
+
+// writeExportFile writes data to rel under stage, creating directories
+func writeExportFile(stage, rel string, data []byte) error {
+	path := filepath.Join(stage, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// writeTarGz archives the files under dir into the gzipped tar archive
+func writeTarGz(archive, dir string) error {
+	f, err := os.Create(archive)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(f)
+	tw := tar.NewWriter(zw)
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.Copy(tw, src)
+		return err
+	})
+	for _, c := range []io.Closer{tw, zw, f} {
+		if cerr := c.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		os.Remove(archive)
+	}
+	return err
+}
+
+// moveTree moves the files under src to dst, copying them when src is on
+// another filesystem (the temp dir often is)
+func moveTree(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, _ := filepath.Rel(src, path)
+		target := filepath.Join(dst, rel)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		if os.Rename(path, target) == nil {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, info.Mode().Perm())
+	})
+}
+
+// extractTarGz unpacks the regular files of a gzipped tar archive into dir
+func extractTarGz(archive, dir string) error {
+	f, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", archive, err)
+	}
+	tr := tar.NewReader(zr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", archive, err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		path := filepath.Join(dir, filepath.FromSlash(hdr.Name))
+		if !strings.HasPrefix(path, filepath.Clean(dir)+string(os.PathSeparator)) {
+			return fmt.Errorf("%s: invalid path %s", archive, hdr.Name)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		out, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(out, tr)
+		out.Close()
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package devflow
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportImportRoundTrip(t *testing.T) {
+	defer testPushedRepo(t)()
+	exec.Command("git", "branch", "feature").Run()
+	os.WriteFile(".devflow.yaml", []byte("lint:\n  enabled: true\n"), 0644)
+	src, _ := os.Getwd()
+
+	archive := filepath.Join(t.TempDir(), "repo.tar.gz")
+	m, err := NewExporter().Export(src, archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Branches) != 2 || len(m.Tags) != 1 || m.Head == "" {
+		t.Errorf("unexpected manifest: %+v", m)
+	}
+	if len(m.Warnings) != 1 || !strings.Contains(m.Warnings[0], "only GitHub") {
+		t.Errorf("expected the file:// remote to skip metadata, got %v", m.Warnings)
+	}
+
+	newRemote := t.TempDir()
+	exec.Command("git", "init", "--bare", newRemote).Run()
+	dest := filepath.Join(t.TempDir(), "restored")
+	imported, err := NewExporter().Import(archive, dest, "file://"+newRemote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.Name != m.Name {
+		t.Errorf("expected name %s, got %s", m.Name, imported.Name)
+	}
+
+	if out, _ := RunCommandInDir(dest, "git", "branch", "--list", "--format=%(refname:short)"); !strings.Contains(out, "feature") {
+		t.Errorf("branch feature not restored: %q", out)
+	}
+	if out, _ := RunCommandInDir(dest, "git", "symbolic-ref", "--short", "HEAD"); out != m.Head {
+		t.Errorf("expected %s checked out, got %s", m.Head, out)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "README.md")); err != nil {
+		t.Error("working tree not checked out")
+	}
+	if data, _ := os.ReadFile(filepath.Join(dest, ".devflow.yaml")); !strings.Contains(string(data), "lint:") {
+		t.Error("untracked config not restored")
+	}
+	if _, err := os.Stat(filepath.Join(dest, ".git", ImportArchiveDir, exportManifestFile)); err != nil {
+		t.Error("manifest not kept in the git directory")
+	}
+	if out, _ := RunCommandInDir(newRemote, "git", "tag"); out != "v0.0.1" {
+		t.Errorf("tags not pushed to the new remote: %q", out)
+	}
+
+	if _, err := NewExporter().Import(archive, dest, ""); err == nil {
+		t.Error("expected error importing into a non-empty directory")
+	}
+}
+
+func TestMergeJSONPages(t *testing.T) {
+	data, err := mergeJSONPages(`[{"number":1},{"number":2}][{"number":3}]`)
+	if err != nil || strings.Count(string(data), "number") != 3 {
+		t.Errorf("unexpected merge: %s, %v", data, err)
+	}
+	if data, _ := mergeJSONPages(`{"name":"r"}`); !strings.Contains(string(data), `"name"`) {
+		t.Errorf("object should be kept, got %s", data)
+	}
+	if data, _ := mergeJSONPages(""); string(data) != "[]" {
+		t.Errorf("expected empty list, got %s", data)
+	}
+}
+
+func TestParseRemoteURL(t *testing.T) {
+	tests := []struct{ url, host, owner, name string }{
+		{"https://github.com/tinywasm/devflow.git", "github.com", "tinywasm", "devflow"},
+		{"git@github.com:cdvelop/gitgo.git", "github.com", "cdvelop", "gitgo"},
+		{"ssh://git@gitlab.example.com:2222/group/sub/repo", "gitlab.example.com", "group/sub", "repo"},
+	}
+	for _, tt := range tests {
+		host, owner, name, ok := ParseRemoteURL(tt.url)
+		if !ok || host != tt.host || owner != tt.owner || name != tt.name {
+			t.Errorf("ParseRemoteURL(%q) = %s %s %s %v", tt.url, host, owner, name, ok)
+		}
+	}
+	if _, _, _, ok := ParseRemoteURL("file:///tmp/repo.git"); ok {
+		t.Error("file remotes should not parse")
+	}
+}
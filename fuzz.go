@@ -0,0 +1,267 @@
+package devflow
+
+import (
+	"crypto/md5"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// fuzzFuncRe matches "func FuzzParse(f *testing.F)"
+var fuzzFuncRe = regexp.MustCompile(`(?m)^func (Fuzz\w*)\(\s*\w+\s+\*testing\.F\s*\)`)
+
+// FuzzOptions configures a fuzzing run
+type FuzzOptions struct {
+	Time      time.Duration // Fuzzing time per target (default fuzz.time config, then 10s)
+	Match     string        // Regexp of the target names to run (default all)
+	CorpusDir string        // Where generated corpora are kept between runs (default user cache dir)
+}
+
+// FuzzTarget is a FuzzXxx function of a package
+type FuzzTarget struct {
+	Package string // Import path
+	Dir     string // Package dir, relative to the module root
+	Name    string
+}
+
+// FuzzResult is the outcome of fuzzing one target
+type FuzzResult struct {
+	FuzzTarget
+	Crashers []string // New failing inputs under testdata/fuzz, relative to the module root
+	Failed   bool     // Crashed, or did not build
+	Duration time.Duration
+	Output   string
+}
+
+// FindFuzzTargets returns the fuzz targets of the module in root, sorted by
+// package and name (vendor, testdata, hidden dirs and nested modules are skipped)
+func FindFuzzTargets(root string) ([]FuzzTarget, error) {
+	module, err := ReadModule(root)
+	if err != nil {
+		return nil, err
+	}
+	modulePath := module.Path
+	var targets []FuzzTarget
+	err = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path == root {
+				return nil
+			}
+			name := d.Name()
+			if name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(path, "go.mod")); err == nil {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(root, filepath.Dir(path))
+		rel = filepath.ToSlash(rel)
+		pkg := modulePath
+		if rel != "." {
+			pkg += "/" + rel
+		}
+		for _, m := range fuzzFuncRe.FindAllStringSubmatch(string(data), -1) {
+			targets = append(targets, FuzzTarget{Package: pkg, Dir: rel, Name: m[1]})
+		}
+		return nil
+	})
+	sort.Slice(targets, func(i, j int) bool {
+		if targets[i].Package != targets[j].Package {
+			return targets[i].Package < targets[j].Package
+		}
+		return targets[i].Name < targets[j].Name
+	})
+	return targets, err
+}
+
+// Fuzz runs every fuzz target of the module in the current directory for
+// opts.Time each. The corpus go test generates is restored before and
+// saved after each target, so it survives "go clean -cache" and can be
+// cached by CI. New crashers (files go test writes to testdata/fuzz)
+// fail the run and invalidate the gotest cache.
+func (g *Go) Fuzz(opts FuzzOptions) (_ string, err error) {
+	defer trackMetric("fuzz")(&err)
+	if opts.Time <= 0 {
+		opts.Time = 10 * time.Second
+		if cfg, err := LoadConfig("."); err == nil && cfg.Has("fuzz.time") {
+			d, err := time.ParseDuration(cfg.String("fuzz.time", ""))
+			if err != nil {
+				return "", fmt.Errorf("fuzz.time: %w", err)
+			}
+			opts.Time = d
+		}
+	}
+	targets, err := FindFuzzTargets(".")
+	if err != nil {
+		return "", err
+	}
+	if opts.Match != "" {
+		re, err := regexp.Compile(opts.Match)
+		if err != nil {
+			return "", fmt.Errorf("invalid fuzz pattern: %w", err)
+		}
+		var matched []FuzzTarget
+		for _, t := range targets {
+			if re.MatchString(t.Name) {
+				matched = append(matched, t)
+			}
+		}
+		targets = matched
+	}
+	if len(targets) == 0 {
+		return "No fuzz targets found", nil
+	}
+
+	if opts.CorpusDir == "" {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return "", err
+		}
+		opts.CorpusDir = filepath.Join(cacheDir, "devflow", "fuzz")
+	}
+	goCache, err := RunCommandSilent("go", "env", "GOCACHE")
+	if err != nil {
+		return "", err
+	}
+
+	var results []FuzzResult
+	for _, t := range targets {
+		results = append(results, g.fuzzTarget(t, opts, filepath.Join(goCache, "fuzz")))
+	}
+
+	summary, failed := fuzzSummary(results, opts.Time)
+	if failed {
+		NewTestCache().InvalidateCache()
+		return summary, fmt.Errorf("fuzzing failed")
+	}
+	return summary, nil
+}
+
+// fuzzTarget runs one target with its saved corpus
+func (g *Go) fuzzTarget(t FuzzTarget, opts FuzzOptions, goFuzzDir string) FuzzResult {
+	res := FuzzResult{FuzzTarget: t}
+	g.logger.Info("Fuzzing", t.Package, t.Name, "for", opts.Time)
+
+	saved := filepath.Join(opts.CorpusDir, filepath.FromSlash(t.Package), t.Name)
+	generated := filepath.Join(goFuzzDir, filepath.FromSlash(t.Package), t.Name)
+	copyCorpus(saved, generated)
+
+	crasherDir := filepath.Join(t.Dir, "testdata", "fuzz", t.Name)
+	before := corpusFiles(crasherDir)
+
+	start := time.Now()
+	out, err := RunCommandInDir(t.Dir, "go", "test", "-run=^$", "-fuzz=^"+t.Name+"$", "-fuzztime="+opts.Time.String(), ".")
+	res.Duration = time.Since(start)
+	res.Output = out
+	res.Failed = err != nil
+	copyCorpus(generated, saved)
+
+	for name := range corpusFiles(crasherDir) {
+		if !before[name] {
+			res.Crashers = append(res.Crashers, filepath.ToSlash(filepath.Join(crasherDir, name)))
+		}
+	}
+	sort.Strings(res.Crashers)
+	return res
+}
+
+// corpusFiles returns the names of the files in a corpus dir
+func corpusFiles(dir string) map[string]bool {
+	files := make(map[string]bool)
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		if !e.IsDir() {
+			files[e.Name()] = true
+		}
+	}
+	return files
+}
+
+// copyCorpus copies the corpus files of src missing from dst. Corpus
+// entries are named by their content hash, so existing ones are equal.
+func copyCorpus(src, dst string) {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		target := filepath.Join(dst, e.Name())
+		if _, err := os.Stat(target); err == nil {
+			continue
+		}
+		if data, err := os.ReadFile(filepath.Join(src, e.Name())); err == nil {
+			os.WriteFile(target, data, 0644)
+		}
+	}
+}
+
+// fuzzSummary returns the summary line, with the crashers and failures of
+// each target before it
+func fuzzSummary(results []FuzzResult, perTarget time.Duration) (string, bool) {
+	var b strings.Builder
+	crashers, failed := 0, 0
+	for _, r := range results {
+		if !r.Failed {
+			continue
+		}
+		failed++
+		crashers += len(r.Crashers)
+		if len(r.Crashers) == 0 {
+			fmt.Fprintf(&b, "❌ %s %s failed:\n%s\n", r.Package, r.Name, r.Output)
+			continue
+		}
+		pkgArg := "./" + r.Dir
+		if r.Dir == "." {
+			pkgArg = "."
+		}
+		for _, c := range r.Crashers {
+			fmt.Fprintf(&b, "❌ %s %s crasher: %s (go test -run=%s/%s %s)\n", r.Package, r.Name, c, r.Name, filepath.Base(c), pkgArg)
+		}
+	}
+	switch {
+	case crashers > 0:
+		fmt.Fprintf(&b, "❌ fuzz: %d new crasher(s) in %d of %d targets", crashers, failed, len(results))
+	case failed > 0:
+		fmt.Fprintf(&b, "❌ fuzz: %d of %d targets failed", failed, len(results))
+	default:
+		fmt.Fprintf(&b, "✅ fuzz: %d targets, %s each, no crashers", len(results), perTarget)
+	}
+	return b.String(), failed > 0
+}
+
+// fuzzCrasherState hashes the crasher files not committed yet, so a new
+// testdata/fuzz input changes the git state the test cache is keyed by
+func fuzzCrasherState() string {
+	out, err := RunCommandSilent("git", "ls-files", "--others", "--exclude-standard", "--", "*testdata/fuzz/*")
+	if err != nil || out == "" {
+		return ""
+	}
+	h := md5.New()
+	for _, path := range strings.Split(out, "\n") {
+		data, _ := os.ReadFile(path)
+		fmt.Fprintf(h, "%s\x00%s\x00", path, data)
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))[:8]
+}
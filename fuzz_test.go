@@ -0,0 +1,82 @@
+package devflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testFuzzFile = `package parse
+
+import "testing"
+
+func FuzzParse(f *testing.F) {
+	f.Fuzz(func(t *testing.T, s string) {
+		if len(s) > 0 {
+			t.Fatal("crash")
+		}
+	})
+}
+
+func FuzzOK(f *testing.F) {
+	f.Fuzz(func(t *testing.T, b []byte) {})
+}
+`
+
+func TestFindFuzzTargets(t *testing.T) {
+	dir, cleanup := testCreateGoModule("example.com/fuzzy")
+	defer cleanup()
+	os.MkdirAll(filepath.Join(dir, "parse"), 0755)
+	os.WriteFile(filepath.Join(dir, "parse", "parse_test.go"), []byte(testFuzzFile), 0644)
+	os.MkdirAll(filepath.Join(dir, "nested"), 0755)
+	os.WriteFile(filepath.Join(dir, "nested", "go.mod"), []byte("module example.com/nested\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "nested", "x_test.go"), []byte(testFuzzFile), 0644)
+
+	targets, err := FindFuzzTargets(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(targets) != 2 || targets[0] != (FuzzTarget{Package: "example.com/fuzzy/parse", Dir: "parse", Name: "FuzzOK"}) || targets[1].Name != "FuzzParse" {
+		t.Errorf("unexpected targets: %+v", targets)
+	}
+}
+
+func TestFuzz(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the fuzzer")
+	}
+	dir, cleanup := testCreateGoModule("example.com/fuzzy")
+	defer cleanup()
+	defer testChdir(t, dir)()
+	os.MkdirAll("parse", 0755)
+	os.WriteFile(filepath.Join("parse", "parse_test.go"), []byte(testFuzzFile), 0644)
+	RunCommandSilent("git", "init")
+	RunCommandSilent("git", "add", ".")
+	RunCommandSilent("git", "-c", "user.name=t", "-c", "user.email=t@t", "commit", "-m", "init")
+	cache := NewTestCache()
+	stateBefore, _ := cache.getGitState()
+
+	g, _ := NewGo(nil)
+	corpus := t.TempDir()
+	summary, err := g.Fuzz(FuzzOptions{Time: 5 * time.Second, Match: "Parse", CorpusDir: corpus})
+	if err == nil {
+		t.Fatalf("expected a crasher: %s", summary)
+	}
+	if !strings.Contains(summary, "❌ example.com/fuzzy/parse FuzzParse crasher: parse/testdata/fuzz/FuzzParse/") ||
+		!strings.HasSuffix(summary, "❌ fuzz: 1 new crasher(s) in 1 of 1 targets") {
+		t.Errorf("unexpected summary:\n%s", summary)
+	}
+	if stateAfter, _ := cache.getGitState(); stateAfter == stateBefore {
+		t.Error("a new crasher should change the test cache state")
+	}
+
+	summary, err = g.Fuzz(FuzzOptions{Time: time.Second, Match: "OK", CorpusDir: corpus})
+	if err != nil || summary != "✅ fuzz: 1 targets, 1s each, no crashers" {
+		t.Errorf("Fuzz() = %q, %v", summary, err)
+	}
+	if entries, _ := os.ReadDir(filepath.Join(corpus, "example.com", "fuzzy", "parse", "FuzzOK")); len(entries) == 0 {
+		t.Error("expected the generated corpus to be saved")
+	}
+}
@@ -0,0 +1,101 @@
+package devflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Gist is a snippet collection fetched at one revision
+type Gist struct {
+	ID       string
+	Revision string            // Version SHA of the fetched files
+	Files    map[string]string // File name -> content
+}
+
+// Ref returns "id@revision", which fetches the same files again
+func (g *Gist) Ref() string {
+	return g.ID + "@" + g.Revision
+}
+
+// FileNames returns the file names of the gist, sorted
+func (g *Gist) FileNames() []string {
+	names := make([]string, 0, len(g.Files))
+	for name := range g.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseGistRef splits a gist reference: an id, "id@revision" or a
+// gist.github.com URL
+func ParseGistRef(ref string) (id, revision string, err error) {
+	ref = strings.TrimSuffix(strings.TrimSpace(ref), "/")
+	ref, revision, _ = strings.Cut(ref, "@")
+	if i := strings.LastIndex(ref, "/"); i >= 0 {
+		ref = ref[i+1:]
+	}
+	if ref == "" || strings.ContainsAny(ref, " .") {
+		return "", "", fmt.Errorf("invalid gist %q, expected an id, id@revision or gist URL", ref)
+	}
+	return ref, revision, nil
+}
+
+// FetchGist reads the files of a gist with the GitHub API, at revision
+// or the latest one when empty
+func (gh *GitHub) FetchGist(id, revision string) (*Gist, error) {
+	endpoint := "gists/" + id
+	if revision != "" {
+		endpoint += "/" + revision
+	}
+	out, err := RunCommandSilent("gh", "api", endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch gist %s: %s", id, firstLine(out))
+	}
+	var resp struct {
+		Files map[string]struct {
+			Content   string `json:"content"`
+			Truncated bool   `json:"truncated"`
+		} `json:"files"`
+		History []struct {
+			Version string `json:"version"`
+		} `json:"history"`
+	}
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse gist %s: %w", id, err)
+	}
+	gist := &Gist{ID: id, Revision: revision, Files: make(map[string]string, len(resp.Files))}
+	if gist.Revision == "" && len(resp.History) > 0 {
+		gist.Revision = resp.History[0].Version
+	}
+	for name, f := range resp.Files {
+		if f.Truncated {
+			return nil, fmt.Errorf("gist %s: %s is too large for the API", id, name)
+		}
+		gist.Files[name] = f.Content
+	}
+	return gist, nil
+}
+
+// WriteSnippets writes the files of gist into targetDir, overwriting
+// existing ones. Non-main Go files get the package of the project.
+func WriteSnippets(gist *Gist, repoName, targetDir string) error {
+	pkg := projectPackageName(repoName)
+	for _, name := range gist.FileNames() {
+		if name != filepath.Base(name) || name == ".." || name == "." {
+			return fmt.Errorf("gist %s: invalid file name %q", gist.ID, name)
+		}
+		content := gist.Files[name]
+		if strings.HasSuffix(name, ".go") {
+			content = renamePackage(content, pkg)
+		}
+		if err := os.WriteFile(filepath.Join(targetDir, name), []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
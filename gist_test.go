@@ -0,0 +1,82 @@
+package devflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testGists serves gists from memory
+type testGists map[string]*Gist
+
+func (g testGists) FetchGist(id, revision string) (*Gist, error) {
+	gist, ok := g[id]
+	if !ok || (revision != "" && revision != gist.Revision) {
+		return nil, fmt.Errorf("failed to fetch gist %s: HTTP 404", id)
+	}
+	return gist, nil
+}
+
+func TestParseGistRef(t *testing.T) {
+	for ref, want := range map[string]string{
+		"aa11":                             "aa11 ",
+		"aa11@f00d":                        "aa11 f00d",
+		"https://gist.github.com/me/aa11":  "aa11 ",
+		"https://gist.github.com/me/aa11/": "aa11 ",
+	} {
+		id, rev, err := ParseGistRef(ref)
+		if err != nil || id+" "+rev != want {
+			t.Errorf("ParseGistRef(%q) = %q, %q, %v", ref, id, rev, err)
+		}
+	}
+	if _, _, err := ParseGistRef("gist.github.com"); err == nil {
+		t.Error("expected invalid gist error")
+	}
+}
+
+func TestGoNewSnippets(t *testing.T) {
+	tmpDir := testResumeEnv(t)
+	defer testChdir(t, tmpDir)()
+	git, _ := NewGit()
+	goHandler, _ := NewGo(git)
+	gn := NewGoNew(git, nil, goHandler)
+	opts := NewProjectOptions{Name: "my-lib", Description: "Snippets", Owner: "org", LocalOnly: true, Snippets: []string{"aa11"}}
+
+	if _, err := gn.Create(opts); err == nil || !strings.Contains(err.Error(), "snippets need GitHub gists") {
+		t.Fatalf("expected missing gist source error, got %v", err)
+	}
+
+	gn.SetGistFetcher(testGists{
+		"aa11": {ID: "aa11", Revision: "f00d", Files: map[string]string{"utils.go": "package utils\n\nfunc Must() {}\n", "Makefile": "test:\n\tgo test ./...\n"}},
+	})
+	if _, err := gn.Create(opts); err != nil {
+		t.Fatal(err)
+	}
+	target := filepath.Join(tmpDir, "my-lib")
+	if src, _ := os.ReadFile(filepath.Join(target, "utils.go")); !strings.HasPrefix(string(src), "package mylib\n") {
+		t.Errorf("snippet should get the project package, got %q", src)
+	}
+	files, _ := RunCommandInDir(target, "git", "ls-files")
+	if !strings.Contains(files, "Makefile") || !strings.Contains(files, "utils.go") {
+		t.Errorf("snippets should be in the initial commit, got %q", files)
+	}
+	logged := false
+	for _, e := range gn.AuditLog() {
+		if e.Step == "snippet gist aa11@f00d" && e.Output == "Makefile, utils.go" {
+			logged = true
+		}
+	}
+	if !logged {
+		t.Errorf("expected the snippet revision in the audit log, got %+v", gn.AuditLog())
+	}
+
+	opts.Name, opts.Snippets = "other", []string{"aa11@beef"}
+	if _, err := gn.Create(opts); err == nil || !strings.Contains(err.Error(), "HTTP 404") {
+		t.Errorf("expected fetch error, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "other")); !os.IsNotExist(err) {
+		t.Error("nothing should be created when a snippet cannot be fetched")
+	}
+}
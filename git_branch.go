@@ -0,0 +1,146 @@
+package devflow
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CurrentBranch returns the checked out branch; it fails on a detached HEAD
+func (g *Git) CurrentBranch() (string, error) {
+	output, err := RunCommandSilent("git", "symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to get current branch: %w", err)
+	}
+	return output, nil
+}
+
+// DefaultBranch returns the default branch of the repository: the HEAD of
+// origin (the local origin/HEAD ref, else asked from the remote), else
+// the first local branch of init.defaultBranch, main, master and trunk,
+// else the current branch. A repository without any branch gets "main".
+func (g *Git) DefaultBranch() (string, error) {
+	if ref, err := RunCommandSilent("git", "symbolic-ref", "--short", "refs/remotes/origin/HEAD"); err == nil && ref != "" {
+		return strings.TrimPrefix(ref, "origin/"), nil
+	}
+	if url, err := RunCommandSilent("git", "remote", "get-url", "origin"); err == nil && url != "" {
+		out, err := RunCommandWithEnvInDir("", remoteProbeEnv(), "git", "ls-remote", "--symref", "origin", "HEAD")
+		if branch := parseSymrefHead(out); err == nil && branch != "" {
+			return branch, nil
+		}
+	}
+	candidates := []string{"main", "master", "trunk"}
+	if name, _ := RunCommandSilent("git", "config", "init.defaultBranch"); name != "" {
+		candidates = append([]string{name}, candidates...)
+	}
+	for _, name := range candidates {
+		if g.BranchExists(name) {
+			return name, nil
+		}
+	}
+	if current, err := g.CurrentBranch(); err == nil {
+		return current, nil
+	}
+	if _, err := RunCommandSilent("git", "rev-parse", "--git-dir"); err != nil {
+		return "", fmt.Errorf("not a git repository")
+	}
+	return "main", nil
+}
+
+// parseSymrefHead returns the branch of the "ref: refs/heads/x\tHEAD" line
+// of git ls-remote --symref
+func parseSymrefHead(out string) string {
+	for _, line := range strings.Split(out, "\n") {
+		ref, target, ok := strings.Cut(strings.TrimSpace(line), "\t")
+		if ok && target == "HEAD" && strings.HasPrefix(ref, "ref: refs/heads/") {
+			return strings.TrimPrefix(ref, "ref: refs/heads/")
+		}
+	}
+	return ""
+}
+
+// ListBranches returns the local branches in name order
+func (g *Git) ListBranches() ([]string, error) {
+	out, err := RunCommandSilent("git", "for-each-ref", "--format=%(refname:short)", "refs/heads")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches: %s", firstLine(out))
+	}
+	var branches []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			branches = append(branches, line)
+		}
+	}
+	return branches, nil
+}
+
+// BranchExists reports whether the local branch name exists
+func (g *Git) BranchExists(name string) bool {
+	_, err := RunCommandSilent("git", "rev-parse", "--verify", "--quiet", "refs/heads/"+name)
+	return err == nil
+}
+
+// CreateBranch creates the branch name at start (default: HEAD) without
+// switching to it
+func (g *Git) CreateBranch(name, start string) error {
+	if err := validateBranchName(name); err != nil {
+		return err
+	}
+	if g.BranchExists(name) {
+		return fmt.Errorf("branch %s already exists", name)
+	}
+	args := []string{"branch", name}
+	if start != "" {
+		args = append(args, start)
+	}
+	if out, err := RunCommand("git", args...); err != nil {
+		return fmt.Errorf("failed to create branch %s: %s", name, firstLine(out))
+	}
+	return nil
+}
+
+// SwitchBranch checks out the branch name, creating it from HEAD first
+// when create is set and it does not exist. Uncommitted changes are
+// carried over as git switch does.
+func (g *Git) SwitchBranch(name string, create bool) error {
+	if err := validateBranchName(name); err != nil {
+		return err
+	}
+	args := []string{"switch", name}
+	if !g.BranchExists(name) {
+		if !create {
+			return fmt.Errorf("branch %s does not exist", name)
+		}
+		args = []string{"switch", "-c", name}
+	}
+	if out, err := RunCommand("git", args...); err != nil {
+		return fmt.Errorf("failed to switch to branch %s: %s", name, firstLine(out))
+	}
+	return nil
+}
+
+// DeleteBranch deletes the local branch name. Without force only a branch
+// merged into HEAD is deleted; the current branch never is.
+func (g *Git) DeleteBranch(name string, force bool) error {
+	if current, err := g.CurrentBranch(); err == nil && current == name {
+		return fmt.Errorf("cannot delete the current branch %s", name)
+	}
+	flag := "-d"
+	if force {
+		flag = "-D"
+	}
+	if out, err := RunCommand("git", "branch", flag, name); err != nil {
+		return fmt.Errorf("failed to delete branch %s: %s", name, firstLine(out))
+	}
+	return nil
+}
+
+// validateBranchName rejects names git does not accept for a branch
+func validateBranchName(name string) error {
+	if name == "" || strings.HasPrefix(name, "-") {
+		return fmt.Errorf("invalid branch name %q", name)
+	}
+	if _, err := RunCommandSilent("git", "check-ref-format", "--branch", name); err != nil {
+		return fmt.Errorf("invalid branch name %q", name)
+	}
+	return nil
+}
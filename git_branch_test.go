@@ -0,0 +1,112 @@
+package devflow
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestGitBranches(t *testing.T) {
+	defer testPushedRepo(t)()
+	g, _ := NewGit()
+	start, err := g.CurrentBranch()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g.CreateBranch("feat/parser", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.CreateBranch("feat/parser", ""); err == nil {
+		t.Error("expected error creating an existing branch")
+	}
+	if err := g.CreateBranch("bad..name", ""); err == nil {
+		t.Error("expected error for an invalid branch name")
+	}
+	if branches, _ := g.ListBranches(); strings.Join(branches, ",") != "feat/parser,"+start && strings.Join(branches, ",") != start+",feat/parser" {
+		t.Errorf("unexpected branches %v", branches)
+	}
+
+	if err := g.SwitchBranch("missing", false); err == nil {
+		t.Error("expected error switching to a missing branch")
+	}
+	if err := g.SwitchBranch("fix/crash", true); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := g.CurrentBranch(); b != "fix/crash" {
+		t.Errorf("CurrentBranch() = %q", b)
+	}
+	if err := g.DeleteBranch("fix/crash", false); err == nil {
+		t.Error("expected error deleting the current branch")
+	}
+
+	g.SwitchBranch(start, false)
+	if err := g.DeleteBranch("fix/crash", false); err != nil {
+		t.Fatal(err)
+	}
+	if g.BranchExists("fix/crash") {
+		t.Error("branch not deleted")
+	}
+}
+
+func TestGitPushBranch(t *testing.T) {
+	defer testPushedRepo(t)()
+	g, _ := NewGit()
+	start, _ := g.CurrentBranch()
+	g.SetPushOptions(PushOptions{Branch: "feat/new", NoTag: true})
+
+	os.WriteFile("feature.go", []byte("package main\n"), 0644)
+	summary, err := g.Push("feat: new feature", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(summary, "Branch: feat/new (new)") {
+		t.Errorf("unexpected summary %q", summary)
+	}
+	if b, _ := g.CurrentBranch(); b != "feat/new" {
+		t.Errorf("expected to be on feat/new, got %q", b)
+	}
+	if out, _ := exec.Command("git", "ls-remote", "--heads", "origin", "feat/new").Output(); len(out) == 0 {
+		t.Error("feature branch not pushed")
+	}
+	if out, _ := exec.Command("git", "log", "-1", "--format=%s", start).Output(); strings.TrimSpace(string(out)) != "initial" {
+		t.Errorf("%s should not get the commit, has %q", start, out)
+	}
+}
+
+func TestGitDefaultBranch(t *testing.T) {
+	tmp := testResumeEnv(t)
+	remote := tmp + "/remote.git"
+	if err := exec.Command("git", "init", "--bare", "--initial-branch=trunk", remote).Run(); err != nil {
+		t.Skip("git does not support --initial-branch")
+	}
+	defer testChdir(t, tmp)()
+	g, _ := NewGit()
+
+	// Local only: the existing branch of the usual names
+	for _, args := range [][]string{{"init", "--initial-branch=master", "local"}, {"-C", "local", "commit", "--allow-empty", "-m", "init"}} {
+		if out, err := RunCommand("git", args...); err != nil {
+			t.Fatal(out, err)
+		}
+	}
+	os.Chdir("local")
+	if b, err := g.DefaultBranch(); err != nil || b != "master" {
+		t.Errorf("DefaultBranch() = %q, %v, want master", b, err)
+	}
+
+	// With origin: its HEAD, asked from the remote before it is fetched
+	RunCommand("git", "push", remote, "master:trunk")
+	RunCommand("git", "remote", "add", "origin", remote)
+	if b, err := g.DefaultBranch(); err != nil || b != "trunk" {
+		t.Errorf("DefaultBranch() = %q, %v, want trunk", b, err)
+	}
+
+	// A clone has origin/HEAD
+	os.Chdir(tmp)
+	RunCommand("git", "clone", remote, "clone")
+	os.Chdir("clone")
+	if b, err := g.DefaultBranch(); err != nil || b != "trunk" {
+		t.Errorf("DefaultBranch() of a clone = %q, %v, want trunk", b, err)
+	}
+}
@@ -0,0 +1,122 @@
+package devflow
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// CommitGroup names a set of paths that are committed together when
+// PushOptions.Split is enabled. Patterns are path prefixes ("docs/") or
+// globs matched against the full path or the file name ("*.md").
+type CommitGroup struct {
+	Name     string
+	Patterns []string
+}
+
+// rootGroup collects files at the repository root
+const rootGroup = "root"
+
+var conventionalRe = regexp.MustCompile(`^(\w+)(\([^)]*\))?(!)?:\s*(.+)$`)
+
+// ParseCommitGroup parses "name=pattern,pattern" into a CommitGroup
+func ParseCommitGroup(s string) (CommitGroup, error) {
+	name, patterns, ok := strings.Cut(s, "=")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" || strings.TrimSpace(patterns) == "" {
+		return CommitGroup{}, fmt.Errorf("invalid group %q, expected name=pattern[,pattern]", s)
+	}
+
+	group := CommitGroup{Name: name}
+	for _, p := range strings.Split(patterns, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			group.Patterns = append(group.Patterns, p)
+		}
+	}
+	return group, nil
+}
+
+// match reports whether file belongs to the group
+func (cg CommitGroup) match(file string) bool {
+	for _, p := range cg.Patterns {
+		if strings.HasSuffix(p, "/") && strings.HasPrefix(file, p) {
+			return true
+		}
+		if ok, _ := path.Match(p, file); ok {
+			return true
+		}
+		if ok, _ := path.Match(p, path.Base(file)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// groupFiles assigns each file to the first matching configured group,
+// falling back to its top-level directory (or "root").
+func groupFiles(files []string, groups []CommitGroup) map[string][]string {
+	result := make(map[string][]string)
+	for _, f := range files {
+		name := ""
+		for _, g := range groups {
+			if g.match(f) {
+				name = g.Name
+				break
+			}
+		}
+		if name == "" {
+			name = rootGroup
+			if dir, _, found := strings.Cut(f, "/"); found {
+				name = dir
+			}
+		}
+		result[name] = append(result[name], f)
+	}
+	return result
+}
+
+// groupMessage derives the commit message for a group. Conventional messages
+// get the group as scope ("feat: x" -> "feat(cmd): x"); others are prefixed.
+func groupMessage(message, group string) string {
+	if m := conventionalRe.FindStringSubmatch(message); m != nil && m[2] == "" {
+		return fmt.Sprintf("%s(%s)%s: %s", m[1], group, m[3], m[4])
+	}
+	return fmt.Sprintf("%s: %s", group, message)
+}
+
+// commitGroups splits staged changes into one commit per group, each with
+// the trailers. Returns a summary entry listing the created commits.
+func (g *Git) commitGroups(message string, trailers []string) (string, error) {
+	// Without rename detection a moved file lists its old path too, so
+	// the deletion is committed with one of the groups
+	out, err := RunCommandSilent("git", "diff", "--cached", "--name-only", "--no-renames")
+	if err != nil {
+		return "", fmt.Errorf("failed to list staged files: %w", err)
+	}
+	if out == "" {
+		return "", nil
+	}
+
+	grouped := groupFiles(strings.Split(out, "\n"), g.pushOpts.Groups)
+	if len(grouped) == 1 {
+		_, err := g.Commit(AddTrailers(message, trailers))
+		return "", err
+	}
+
+	names := make([]string, 0, len(grouped))
+	for name := range grouped {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		args := append([]string{"commit", "-m", AddTrailers(groupMessage(message, name), trailers), "--"}, grouped[name]...)
+		if _, err := RunCommand("git", args...); err != nil {
+			return "", fmt.Errorf("commit for group %s failed: %w", name, err)
+		}
+	}
+
+	return fmt.Sprintf("✅ Commits: %d (%s)", len(names), strings.Join(names, ", ")), nil
+}
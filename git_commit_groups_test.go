@@ -0,0 +1,103 @@
+package devflow
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestGroupFiles(t *testing.T) {
+	groups := []CommitGroup{{Name: "docs", Patterns: []string{"docs/", "*.md"}}}
+	files := []string{"README.md", "docs/GUIDE.md", "cmd/app/main.go", "cmd/tool/main.go", "main.go"}
+
+	got := groupFiles(files, groups)
+
+	if len(got["docs"]) != 2 {
+		t.Errorf("expected 2 docs files, got %v", got["docs"])
+	}
+	if len(got["cmd"]) != 2 {
+		t.Errorf("expected 2 cmd files, got %v", got["cmd"])
+	}
+	if len(got[rootGroup]) != 1 || got[rootGroup][0] != "main.go" {
+		t.Errorf("expected main.go in root group, got %v", got[rootGroup])
+	}
+}
+
+func TestGroupMessage(t *testing.T) {
+	tests := []struct{ msg, group, want string }{
+		{"feat: add api", "cmd", "feat(cmd): add api"},
+		{"fix!: breaking", "pkg", "fix(pkg)!: breaking"},
+		{"feat(core): scoped", "docs", "docs: feat(core): scoped"},
+		{"update things", "root", "root: update things"},
+	}
+	for _, tt := range tests {
+		if got := groupMessage(tt.msg, tt.group); got != tt.want {
+			t.Errorf("groupMessage(%q, %q) = %q; want %q", tt.msg, tt.group, got, tt.want)
+		}
+	}
+}
+
+func TestParseCommitGroup(t *testing.T) {
+	g, err := ParseCommitGroup("docs=docs/, *.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Name != "docs" || len(g.Patterns) != 2 || g.Patterns[1] != "*.md" {
+		t.Errorf("unexpected group: %+v", g)
+	}
+	if _, err := ParseCommitGroup("nopatterns"); err == nil {
+		t.Error("expected error for missing patterns")
+	}
+}
+
+func TestGitPushSplit(t *testing.T) {
+	defer testPushedRepo(t)()
+
+	os.MkdirAll("cmd/app", 0755)
+	os.WriteFile("cmd/app/main.go", []byte("package main"), 0644)
+	os.MkdirAll("docs", 0755)
+	os.WriteFile("docs/GUIDE.md", []byte("guide"), 0644)
+	os.WriteFile("README.md", []byte("# changed"), 0644)
+
+	git, _ := NewGit()
+	git.SetPushOptions(PushOptions{Split: true})
+
+	summary, err := git.Push("feat: add app", "")
+	if err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	if !strings.Contains(summary, "Commits: 3 (cmd, docs, root)") {
+		t.Errorf("unexpected summary: %s", summary)
+	}
+
+	log, _ := RunCommandSilent("git", "log", "-3", "--format=%s")
+	for _, want := range []string{"feat(cmd): add app", "feat(docs): add app", "feat(root): add app"} {
+		if !strings.Contains(log, want) {
+			t.Errorf("missing commit %q in log:\n%s", want, log)
+		}
+	}
+}
+
+func TestGitPushSplitRename(t *testing.T) {
+	defer testPushedRepo(t)()
+
+	os.MkdirAll("docs", 0755)
+	if _, err := RunCommand("git", "mv", "README.md", "docs/README.md"); err != nil {
+		t.Fatal(err)
+	}
+	os.MkdirAll("cmd/app", 0755)
+	os.WriteFile("cmd/app/main.go", []byte("package main"), 0644)
+
+	git, _ := NewGit()
+	git.SetPushOptions(PushOptions{Split: true})
+	if _, err := git.Push("docs: move readme", ""); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+
+	if status, _ := RunCommandSilent("git", "status", "--porcelain"); status != "" {
+		t.Errorf("expected the rename fully committed, got status %q", status)
+	}
+	if files, _ := RunCommandSilent("git", "ls-tree", "-r", "--name-only", "HEAD"); strings.Contains(files, "\nREADME.md") || strings.HasPrefix(files, "README.md") {
+		t.Errorf("expected README.md removed from HEAD, got %q", files)
+	}
+}
@@ -1,6 +1,7 @@
 package devflow
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
@@ -11,7 +12,11 @@ import (
 type Git struct {
 	rootDir     string
 	shouldWrite func() bool
-	log         func(...any)
+	logger      Logger
+	pushOpts    PushOptions
+	dryRun      bool
+	pushHook    func(stage string) error // Set by Go.Push for its hooks
+	stashHeld   bool                     // StashForPush stashed, Push leaves it alone
 }
 
 // NewGit creates a new Git handler and verifies git is available
@@ -24,7 +29,7 @@ func NewGit() (*Git, error) {
 	return &Git{
 		rootDir:     ".",
 		shouldWrite: func() bool { return false },
-		log:         func(...any) {}, // default no-op
+		logger:      NopLogger(),
 	}, nil
 }
 
@@ -39,91 +44,186 @@ func (g *Git) SetShouldWrite(f func() bool) {
 	g.shouldWrite = f
 }
 
-// SetLog sets the logger function
+// SetDryRun makes Push print the git commands and file updates it would
+// perform instead of running them
+func (g *Git) SetDryRun(enabled bool) {
+	g.dryRun = enabled
+}
+
+// SetLog sets the logger function; messages of every level but debug
+// are passed to fn. Use SetLogger for levels or JSON output.
 func (g *Git) SetLog(fn func(...any)) {
 	if fn != nil {
-		g.log = fn
+		g.SetLogger(FuncLogger(fn))
 	}
 }
 
-// CheckRemoteAccess verifies connectivity to the remote repository
-func (g *Git) CheckRemoteAccess() error {
-	// git ls-remote origin checks access without needing upstream configured
-	_, err := RunCommandSilent("git", "ls-remote", "origin")
-	if err != nil {
-		// Try to provide a helpful error message
-		if strings.Contains(err.Error(), "Authentication failed") || strings.Contains(err.Error(), "Could not read from remote repository") {
-			return fmt.Errorf("❌ Authentication failed. Please check your git credentials or use 'git push' manually to authenticate")
-		}
-		if strings.Contains(err.Error(), "Could not resolve host") {
-			return fmt.Errorf("❌ Network error. Please check your internet connection")
-		}
-		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "does not exist") {
-			return fmt.Errorf("❌ Remote 'origin' not found. Please add a remote using 'git remote add origin <url>'")
-		}
-		return fmt.Errorf("❌ checking remote access failed: %w", err)
+// SetLogger sets the leveled logger
+func (g *Git) SetLogger(l Logger) {
+	if l != nil {
+		g.logger = l
 	}
-	return nil
 }
 
 // Push executes the complete push workflow (add, commit, tag, push)
 // Returns a summary of operations and error if any.
-func (g *Git) Push(message, tag string) (string, error) {
+func (g *Git) Push(message, tag string) (_ string, err error) {
 	// Validate message
 	if err := ValidateCommitMessage(message); err != nil {
 		return "", err
 	}
 	message = FormatCommitMessage(message)
+	if g.dryRun {
+		defer startDryRun()()
+	}
+	defer trackMetric("push")(&err)
+	defer traceSpan("push")(&err)
 
 	summary := []string{}
 
 	// 0. Verify remote access before doing anything destructive
+	tracePhase("check remote")
 	if err := g.CheckRemoteAccess(); err != nil {
 		return "", err
 	}
 
-	// 1. Git add
-	if err := g.Add(); err != nil {
-		return "", fmt.Errorf("git add failed: %w", err)
+	// 0b. Commit on the requested (feature) branch, carrying the changes over
+	if branch := g.pushOpts.Branch; branch != "" {
+		if current, _ := g.CurrentBranch(); current != branch {
+			created := !g.BranchExists(branch)
+			if err := g.SwitchBranch(branch, true); err != nil {
+				return "", err
+			}
+			if created {
+				summary = append(summary, "✅ Branch: "+branch+" (new)")
+			} else {
+				summary = append(summary, "✅ Branch: "+branch)
+			}
+		}
 	}
 
-	// 2. Commit (only if there are changes)
-	_, err := g.Commit(message)
+	// 0c. Leave out what is not staged, restoring it however the push ends
+	stashSummary, restore, err := g.StashForPush()
 	if err != nil {
-		return "", fmt.Errorf("git commit failed: %w", err)
+		return "", err
+	}
+	if restore != nil {
+		summary = append(summary, stashSummary)
+		defer func() {
+			err = errors.Join(err, restore())
+		}()
+	}
+
+	// 0d. User-defined steps that may still change the commit
+	tracePhase("commit")
+	if err := g.runPushHook(HookBeforeCommit); err != nil {
+		return "", err
+	}
+
+	// 1. Copyright year on the first release of the year (part of this commit)
+	yearSummary, yearFiles, err := g.updateCopyrightYears()
+	if err != nil {
+		return "", fmt.Errorf("copyright year update failed: %w", err)
 	}
 
-	// 3. Determine tag (provided or generated)
+	// Git add, of everything or of the selected paths
+	stageSummary, err := g.stageForPush()
+	if err != nil {
+		return "", fmt.Errorf("git add failed: %w", err)
+	}
+	if stageSummary != "" {
+		summary = append(summary, stageSummary)
+	}
+	if err := g.unstageExcluded(); err != nil {
+		return "", fmt.Errorf("git add failed: %w", err)
+	}
+	// The files Push itself updated belong to the release, whatever paths
+	// were selected
+	if err := g.stagePushFiles(yearFiles); err != nil {
+		return "", fmt.Errorf("git add failed: %w", err)
+	}
+
+	// 2. Determine tag (provided or generated) before committing, so a
+	// bad tag fails early and the changelog can name the release
 	finalTag := tag
-	if finalTag == "" {
-		generatedTag, err := g.GenerateNextTag()
-		if err != nil {
-			return "", fmt.Errorf("failed to generate tag: %w", err)
+	if g.pushOpts.NoTag && tag != "" {
+		return "", fmt.Errorf("tag %s given for a push without tag", tag)
+	}
+	if !g.pushOpts.NoTag {
+		if finalTag == "" {
+			generatedTag, err := g.nextTag(message)
+			if err != nil {
+				return "", fmt.Errorf("failed to generate tag: %w", err)
+			}
+			finalTag = generatedTag
+		}
+
+		// Validate tag is greater than latest
+		latestTag, err := g.GetLatestTag()
+		if err == nil && latestTag != "" {
+			if CompareVersions(finalTag, latestTag) <= 0 {
+				return "", fmt.Errorf("tag %s is not greater than latest tag %s", finalTag, latestTag)
+			}
+		}
+		if finalTag, err = g.nextFreeTag(finalTag); err != nil {
+			return "", err
 		}
-		finalTag = generatedTag
 	}
 
-	// Validate tag is greater than latest
-	latestTag, err := g.GetLatestTag()
-	if err == nil && latestTag != "" {
-		if CompareVersions(finalTag, latestTag) <= 0 {
-			return "", fmt.Errorf("tag %s is not greater than latest tag %s", finalTag, latestTag)
+	// 2b. CHANGELOG.md Unreleased section (opt-in), part of this commit
+	changelogSummary, err := g.updateChangelog(message, finalTag)
+	if err != nil {
+		return "", fmt.Errorf("changelog update failed: %w", err)
+	}
+
+	// 3. Commit (only if there are changes), amending or squashing if requested
+	commitSummary, err := g.commitForPush(message)
+	if err != nil {
+		return "", fmt.Errorf("git commit failed: %w", err)
+	}
+	if commitSummary != "" {
+		summary = append(summary, commitSummary)
+	}
+	if yearSummary != "" {
+		summary = append(summary, yearSummary)
+	}
+	if changelogSummary != "" {
+		summary = append(summary, changelogSummary)
+	}
+
+	// From here on the work is local only until the push succeeds
+	tagged := false
+	defer OnInterrupt(func() {
+		fmt.Fprintln(os.Stderr, pushResumeHint(finalTag, tagged))
+	})()
+
+	if g.pushOpts.NoTag {
+		tracePhase("push")
+		if err := g.runPushHook(HookBeforePush); err != nil {
+			return "", err
+		}
+		if err := g.pushBranch(); err != nil {
+			return "", fmt.Errorf("push failed: %w", err)
 		}
+		summary = append(summary, "✅ Pushed ok (no tag)")
+		return strings.Join(summary, ", "), nil
 	}
 
 	// 4. Create tag - if exists, keep incrementing until we find available one
+	tracePhase("tag")
 	maxAttempts := 100 // Prevent infinite loop
 	attempt := 0
 	for attempt < maxAttempts {
 		created, err := g.CreateTag(finalTag)
 		if err == nil && created {
 			// Success
+			tagged = true
 			summary = append(summary, fmt.Sprintf("✅ Tag: %s", finalTag))
 			break
 		}
 
 		// Tag exists, increment from current finalTag
-		g.log("Tag", finalTag, "already exists, trying next")
+		g.logger.Info("Tag", finalTag, "already exists, trying next")
 		nextTag, err := g.IncrementTag(finalTag)
 		if err != nil {
 			return "", fmt.Errorf("failed to increment tag: %w", err)
@@ -137,6 +237,10 @@ func (g *Git) Push(message, tag string) (string, error) {
 	}
 
 	// 5. Push commits and tag
+	tracePhase("push")
+	if err := g.runPushHook(HookBeforePush); err != nil {
+		return "", err
+	}
 	if err := g.PushWithTags(finalTag); err != nil {
 		return "", fmt.Errorf("push failed: %w", err)
 	}
@@ -145,6 +249,31 @@ func (g *Git) Push(message, tag string) (string, error) {
 	return strings.Join(summary, ", "), nil
 }
 
+// SetPushHook sets the function Push calls at the before_commit and
+// before_push stages; nil runs no hooks
+func (g *Git) SetPushHook(fn func(stage string) error) {
+	g.pushHook = fn
+}
+
+// runPushHook runs the push hook of stage, if one is set
+func (g *Git) runPushHook(stage string) error {
+	if g.pushHook == nil {
+		return nil
+	}
+	return g.pushHook(stage)
+}
+
+// pushResumeHint tells how to finish a push interrupted after the commit
+func pushResumeHint(tag string, tagged bool) string {
+	switch {
+	case tag == "":
+		return "⚠️ Push interrupted: the commit is local only. Resume with: git push"
+	case tagged:
+		return fmt.Sprintf("⚠️ Push interrupted: the commit and tag %s are local only. Resume with: git push && git push origin %s", tag, tag)
+	}
+	return fmt.Sprintf("⚠️ Push interrupted: the commit is local only and %s is not tagged. Resume with: git tag %s && git push && git push origin %s", tag, tag, tag)
+}
+
 // Add adds all changes to staging
 func (g *Git) Add() error {
 	_, err := RunCommand("git", "add", ".")
@@ -153,9 +282,10 @@ func (g *Git) Add() error {
 
 // hasChanges checks if there are staged changes
 func (g *Git) hasChanges() (bool, error) {
-	// Check if HEAD exists
+	// Check if HEAD exists. In dry-run mode nothing was staged, so any
+	// change git add would have picked up counts.
 	_, err := RunCommandSilent("git", "rev-parse", "HEAD")
-	if err != nil {
+	if err != nil || DryRunActive() {
 		// No HEAD (fresh repo). Check if there are any files staged for initial commit.
 		out, err := RunCommandSilent("git", "status", "--porcelain")
 		if err != nil {
@@ -217,12 +347,29 @@ func (g *Git) CreateTag(tag string) (bool, error) {
 		return false, fmt.Errorf("tag %s already exists", tag)
 	}
 
+	// Annotated with the release notes when there are any; whitespace
+	// cleanup keeps Markdown headings that git would strip as comments
+	notes, err := g.ReleaseNotes(tag)
+	if err != nil {
+		return false, err
+	}
+	if notes != "" {
+		_, err = RunCommand("git", "tag", "-a", "--cleanup=whitespace", tag, "-m", tagMessage(tag, notes))
+		return true, err
+	}
 	_, err = RunCommand("git", "tag", tag)
 	return true, err
 }
 
-// GenerateNextTag calculates the next semantic version
+// GenerateNextTag calculates the next semantic version from the commits
+// since the latest tag (see BumpLevel)
 func (g *Git) GenerateNextTag() (string, error) {
+	return g.nextTag("")
+}
+
+// nextTag returns the tag that follows the latest one for the commits
+// since it plus message, the commit about to be made (may be empty)
+func (g *Git) nextTag(message string) (string, error) {
 	latestTag, err := g.GetLatestTag()
 	if err != nil {
 		return "", err
@@ -232,21 +379,64 @@ func (g *Git) GenerateNextTag() (string, error) {
 		return "v0.0.1", nil
 	}
 
-	parts := strings.Split(latestTag, ".")
-	if len(parts) < 3 {
-		return "", fmt.Errorf("invalid tag format: %s", latestTag)
+	tag, err := BumpVersion(latestTag, g.BumpLevel(latestTag, message))
+	if err != nil {
+		return "", err
+	}
+	if err := g.checkMajorModulePath(tag); err != nil {
+		return "", err
 	}
+	return tag, nil
+}
 
-	lastNumStr := parts[len(parts)-1]
-	lastNum, err := strconv.Atoi(lastNumStr)
-	if err != nil {
-		return "", fmt.Errorf("invalid tag number: %s", lastNumStr)
+// BumpLevel returns the bump level of the next release: PushOptions.Bump
+// when set, else the highest one the conventional commit messages since
+// latestTag and message ask for. Below v1 a breaking change bumps the
+// minor version, since leaving v0 is a deliberate decision.
+func (g *Git) BumpLevel(latestTag, message string) string {
+	if g.pushOpts.Bump != "" {
+		return g.pushOpts.Bump
+	}
+	messages := g.commitMessagesSince(latestTag)
+	if message != "" {
+		messages = append(messages, message)
+	}
+	level := ConventionalBump(messages)
+	if level == BumpMajor && strings.HasPrefix(latestTag, "v0.") {
+		level = BumpMinor
 	}
+	return level
+}
 
-	parts[len(parts)-1] = strconv.Itoa(lastNum + 1)
-	newTag := strings.Join(parts, ".")
+// commitMessagesSince returns the full messages of the commits after tag
+func (g *Git) commitMessagesSince(tag string) []string {
+	opts := LogOptions{NoMerges: true}
+	if tag != "" {
+		opts.Range = tag + "..HEAD"
+	}
+	commits, _ := g.Log(opts)
+	var messages []string
+	for _, c := range commits {
+		messages = append(messages, c.Message())
+	}
+	return messages
+}
 
-	return newTag, nil
+// checkMajorModulePath fails when tag starts a major version from v2 on
+// that the module path in go.mod does not end with (/v2, /v3...)
+func (g *Git) checkMajorModulePath(tag string) error {
+	major, _, _ := strings.Cut(strings.TrimPrefix(tag, "v"), ".")
+	if n, err := strconv.Atoi(major); err != nil || n < 2 {
+		return nil
+	}
+	module, err := FindModule(g.rootDir)
+	if err != nil {
+		return nil // Not a Go module
+	}
+	if suffix := "/v" + major; !strings.HasSuffix(module.Path, suffix) {
+		return fmt.Errorf("%s is a breaking release but the module path %s lacks %s: update go.mod and imports first, or set the bump level (-bump minor)", tag, module.Path, suffix)
+	}
+	return nil
 }
 
 // IncrementTag increments a specific tag (e.g., v0.0.12 -> v0.0.13)
@@ -272,6 +462,21 @@ func (g *Git) IncrementTag(tag string) (string, error) {
 	return newTag, nil
 }
 
+// nextFreeTag returns tag, or the first increment of it that does not exist yet
+func (g *Git) nextFreeTag(tag string) (string, error) {
+	for i := 0; i < 100; i++ {
+		if exists, _ := g.TagExists(tag); !exists {
+			return tag, nil
+		}
+		next, err := g.IncrementTag(tag)
+		if err != nil {
+			return "", fmt.Errorf("failed to increment tag: %w", err)
+		}
+		tag = next
+	}
+	return "", fmt.Errorf("could not find available tag after 100 attempts")
+}
+
 // TagExists checks if a tag exists
 func (g *Git) TagExists(tag string) (bool, error) {
 	_, err := RunCommandSilent("git", "rev-parse", tag)
@@ -281,15 +486,6 @@ func (g *Git) TagExists(tag string) (bool, error) {
 	return true, nil
 }
 
-// getCurrentBranch gets the current branch
-func (g *Git) getCurrentBranch() (string, error) {
-	output, err := RunCommandSilent("git", "symbolic-ref", "--short", "HEAD")
-	if err != nil {
-		return "", fmt.Errorf("failed to get current branch: %w", err)
-	}
-	return output, nil
-}
-
 // hasUpstream checks if the branch has upstream
 func (g *Git) hasUpstream() (bool, error) {
 	_, err := RunCommandSilent("git", "rev-parse", "--symbolic-full-name", "--abbrev-ref", "@{u}")
@@ -319,8 +515,19 @@ func (g *Git) pushTag(tag string) error {
 
 // PushWithTags pushes commits and tag
 func (g *Git) PushWithTags(tag string) error {
-	branch, err := g.getCurrentBranch()
+	if err := g.pushBranch(); err != nil {
+		return err
+	}
+	return g.pushTag(tag)
+}
+
+// pushBranch pushes the current branch, setting its upstream if needed
+func (g *Git) pushBranch() error {
+	branch, err := g.CurrentBranch()
 	if err != nil {
+		if name, derr := g.DefaultBranch(); derr == nil {
+			return fmt.Errorf("HEAD is detached, switch to a branch first (git switch %s)", name)
+		}
 		return err
 	}
 
@@ -340,11 +547,6 @@ func (g *Git) PushWithTags(tag string) error {
 			return fmt.Errorf("git push failed: %w", err)
 		}
 	}
-
-	if err := g.pushTag(tag); err != nil {
-		return err
-	}
-
 	return nil
 }
 
@@ -377,31 +579,32 @@ func (g *Git) SetUserConfig(name, email string) error {
 	return nil
 }
 
-// InitRepo initializes a new git repository
-func (g *Git) InitRepo(dir string) error {
-	if _, err := RunCommand("git", "init", dir); err != nil {
-		return err
+// InitBranch returns the branch new repositories start on:
+// init.defaultBranch of the git config, else main
+func (g *Git) InitBranch() string {
+	if name, _ := RunCommandSilent("git", "config", "--get", "init.defaultBranch"); name != "" && validateBranchName(name) == nil {
+		return name
 	}
+	return "main"
+}
 
-	// Set main branch
-	cwd, err := os.Getwd()
-	if err != nil {
-		return err
-	}
-	defer os.Chdir(cwd)
+// InitRepo initializes a new git repository on InitBranch
+func (g *Git) InitRepo(dir string) error {
+	return g.InitRepoBranch(dir, g.InitBranch())
+}
 
-	if err := os.Chdir(dir); err != nil {
+// InitRepoBranch initializes a new git repository whose unborn branch is
+// branch, whatever git's own default is
+func (g *Git) InitRepoBranch(dir, branch string) error {
+	if err := validateBranchName(branch); err != nil {
 		return err
 	}
-
-	if _, err := RunCommand("git", "branch", "-M", "main"); err != nil {
-		// On fresh init with no commits, this might fail, but git init usually sets up a default branch.
-		// Newer git versions use init.defaultBranch.
-		// If it fails, it might mean there are no commits yet so HEAD doesn't point anywhere meaningful.
-		// We can ignore or handle.
-		// Actually "git branch -M main" works even with no commits in recent git.
-		// Let's assume it works or is not critical if we are on a version that defaults to master.
+	if _, err := RunCommand("git", "init", dir); err != nil {
 		return err
 	}
+	// symbolic-ref works on every git version, unlike init --initial-branch
+	if out, err := RunCommandInDir(dir, "git", "symbolic-ref", "HEAD", "refs/heads/"+branch); err != nil {
+		return fmt.Errorf("failed to set branch %s: %s", branch, firstLine(out))
+	}
 	return nil
 }
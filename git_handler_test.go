@@ -354,3 +354,48 @@ exit 1
 		t.Error("Expected Push to fail at commit step")
 	}
 }
+
+func TestGitGenerateNextTagConventional(t *testing.T) {
+	defer testPushedRepo(t)()
+	git, _ := NewGit()
+	commit := func(message string) {
+		exec.Command("git", "commit", "--allow-empty", "-m", message).Run()
+	}
+
+	commit("fix: handle empty input")
+	if tag, _ := git.GenerateNextTag(); tag != "v0.0.2" {
+		t.Errorf("fix: expected v0.0.2, got %s", tag)
+	}
+	commit("feat: add retries")
+	if tag, _ := git.GenerateNextTag(); tag != "v0.1.0" {
+		t.Errorf("feat: expected v0.1.0, got %s", tag)
+	}
+	// Below v1 breaking changes bump minor
+	commit("refactor: rename\n\nBREAKING CHANGE: Client is now Conn")
+	if tag, _ := git.GenerateNextTag(); tag != "v0.1.0" {
+		t.Errorf("breaking below v1: expected v0.1.0, got %s", tag)
+	}
+
+	// The message being pushed counts too
+	exec.Command("git", "tag", "v1.2.0").Run()
+	if tag, _ := git.nextTag("feat: stream api"); tag != "v1.3.0" {
+		t.Errorf("pending feat: expected v1.3.0, got %s", tag)
+	}
+
+	// From v2 on, the module path needs the major suffix
+	os.WriteFile("go.mod", []byte("module example.com/lib\n\ngo 1.22\n"), 0644)
+	_, err := git.nextTag("feat!: new api")
+	if err == nil || !strings.Contains(err.Error(), "/v2") {
+		t.Errorf("expected module path error, got %v", err)
+	}
+	os.WriteFile("go.mod", []byte("module example.com/lib/v2\n\ngo 1.22\n"), 0644)
+	if tag, err := git.nextTag("feat!: new api"); err != nil || tag != "v2.0.0" {
+		t.Errorf("expected v2.0.0, got %s, %v", tag, err)
+	}
+
+	// -bump overrides the commits
+	git.SetPushOptions(PushOptions{Bump: BumpPatch})
+	if tag, _ := git.nextTag("feat!: new api"); tag != "v1.2.1" {
+		t.Errorf("override: expected v1.2.1, got %s", tag)
+	}
+}
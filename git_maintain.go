@@ -0,0 +1,127 @@
+package devflow
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// MaintainOptions configures Git.Maintain
+type MaintainOptions struct {
+	Aggressive bool // git gc --aggressive: a slower, tighter repack
+	Schedule   bool // Also register the repository with git maintenance start
+}
+
+// MaintainResult is the outcome of Git.Maintain for one repository
+type MaintainResult struct {
+	Dir       string
+	Before    int64 // Size of the git directory before, in bytes
+	After     int64
+	Scheduled bool // Registered for the scheduled background maintenance
+}
+
+// Reclaimed returns the bytes freed, 0 when the repository grew
+func (r MaintainResult) Reclaimed() int64 {
+	if r.After >= r.Before {
+		return 0
+	}
+	return r.Before - r.After
+}
+
+// String returns "✅ api: 12.4 MB -> 8.1 MB (4.3 MB reclaimed), scheduled"
+func (r MaintainResult) String() string {
+	s := fmt.Sprintf("✅ %s: %s -> %s (%s reclaimed)", filepath.Base(r.Dir), FormatBytes(r.Before), FormatBytes(r.After), FormatBytes(r.Reclaimed()))
+	if r.Scheduled {
+		s += ", scheduled"
+	}
+	return s
+}
+
+// Maintain compacts the repository in dir: git gc, a full repack and the
+// commit-graph used by log and merge-base. With opts.Schedule it also runs
+// git maintenance start, which adds the repository to maintenance.repo of
+// the global git config and installs the system scheduler (systemd, cron,
+// launchd or schtasks) that keeps it maintained hourly, daily and weekly.
+func (g *Git) Maintain(dir string, opts MaintainOptions) (MaintainResult, error) {
+	if g.dryRun {
+		defer startDryRun()()
+	}
+	if dir == "" {
+		dir = g.rootDir
+	}
+	result := MaintainResult{Dir: dir}
+	if abs, err := filepath.Abs(dir); err == nil {
+		result.Dir = abs
+	}
+
+	gitDir, err := RunCommandInDir(dir, "git", "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return result, fmt.Errorf("%s is not a git repository: %s", dir, firstLine(gitDir))
+	}
+	if result.Before, err = dirSize(gitDir); err != nil {
+		return result, err
+	}
+
+	gc := []string{"gc", "--quiet"}
+	if opts.Aggressive {
+		gc = append(gc, "--aggressive")
+	}
+	steps := [][]string{
+		gc,
+		{"repack", "-a", "-d", "--quiet"},
+		{"commit-graph", "write", "--reachable", "--changed-paths"},
+	}
+	for _, args := range steps {
+		g.logger.Info("Running git", strings.Join(args, " "), "in", filepath.Base(result.Dir))
+		if out, err := RunCommandInDir(dir, "git", args...); err != nil {
+			return result, fmt.Errorf("git %s failed: %s", args[0], firstLine(out))
+		}
+	}
+
+	if opts.Schedule {
+		if out, err := RunCommandInDir(dir, "git", "maintenance", "start"); err != nil {
+			return result, fmt.Errorf("git maintenance start failed: %s", firstLine(out))
+		}
+		result.Scheduled = true
+	}
+
+	if result.After, err = dirSize(gitDir); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// dirSize returns the bytes of the files under dir
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Files removed by a concurrent gc are not counted
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size, err
+}
+
+// FormatBytes returns n in B, KB, MB or GB (powers of 1024), e.g. "4.3 MB"
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	value, suffix := float64(n)/unit, "KB"
+	for _, next := range []string{"MB", "GB"} {
+		if value < unit {
+			break
+		}
+		value, suffix = value/unit, next
+	}
+	return fmt.Sprintf("%.1f %s", value, suffix)
+}
@@ -0,0 +1,54 @@
+package devflow
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGitMaintain(t *testing.T) {
+	tmp := testResumeEnv(t)
+	git, err := NewGit()
+	if err != nil {
+		t.Skip("git not installed")
+	}
+	repo := filepath.Join(tmp, "repo")
+	os.MkdirAll(repo, 0755)
+	for _, args := range [][]string{{"init", "-q"}, {"commit", "-q", "--allow-empty", "-m", "one"}, {"commit", "-q", "--allow-empty", "-m", "two"}} {
+		if out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	var calls []string
+	defer UseMiddleware(CommandHooks(func(cmd *Command) error {
+		calls = append(calls, cmd.String())
+		return nil
+	}, nil))()
+	result, err := git.Maintain(repo, MaintainOptions{Aggressive: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "git rev-parse --absolute-git-dir|git gc --quiet --aggressive|git repack -a -d --quiet|git commit-graph write --reachable --changed-paths"
+	if strings.Join(calls, "|") != want {
+		t.Errorf("unexpected commands:\n%s", strings.Join(calls, "\n"))
+	}
+	if result.Before == 0 || result.After == 0 || result.Scheduled || !strings.HasPrefix(result.String(), "✅ repo: ") {
+		t.Errorf("unexpected result %+v: %s", result, result)
+	}
+	if _, err := os.Stat(filepath.Join(repo, ".git", "objects", "info", "commit-graph")); err != nil {
+		t.Errorf("expected the commit-graph: %v", err)
+	}
+
+	if _, err := git.Maintain(t.TempDir(), MaintainOptions{}); err == nil || !strings.Contains(err.Error(), "not a git repository") {
+		t.Errorf("expected not a repository error, got %v", err)
+	}
+
+	for n, want := range map[int64]string{512: "512 B", 4096: "4.0 KB", 4508876: "4.3 MB", 3 << 30: "3.0 GB"} {
+		if got := FormatBytes(n); got != want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
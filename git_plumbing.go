@@ -0,0 +1,191 @@
+package devflow
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Field and record separators of the git formats below; they cannot
+// appear in names or messages
+const (
+	gitFieldSep  = "\x1f"
+	gitRecordSep = "\x1e"
+)
+
+// Commit is a commit of Git.Log
+type Commit struct {
+	Hash    string
+	Parents []string // Hashes of the parents, two or more for a merge
+	Author  string
+	Email   string
+	Date    time.Time // Author date
+	Subject string
+	Body    string // Message after the subject line, trimmed
+}
+
+// Message returns the full commit message: the subject, then the body
+// after a blank line
+func (c Commit) Message() string {
+	if c.Body == "" {
+		return c.Subject
+	}
+	return c.Subject + "\n\n" + c.Body
+}
+
+// LogOptions selects the commits of Git.Log. The zero value lists every
+// commit of HEAD, newest first.
+type LogOptions struct {
+	Range    string   // Revision or range, e.g. v1.2.0..HEAD (default: HEAD)
+	Max      int      // At most this many commits (0: all)
+	Paths    []string // Only commits that change these paths
+	NoMerges bool     // Leave out merge commits
+	Reverse  bool     // Oldest first
+}
+
+// Log returns the commits selected by opts. A repository without commits
+// has none.
+func (g *Git) Log(opts LogOptions) ([]Commit, error) {
+	if opts.Range == "" {
+		if _, err := RunCommandSilent("git", "rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+			return nil, nil
+		}
+	}
+	args := []string{"log", "--format=%H%x1f%P%x1f%an%x1f%ae%x1f%aI%x1f%B%x1e"}
+	if opts.Max > 0 {
+		args = append(args, "-n", strconv.Itoa(opts.Max))
+	}
+	if opts.NoMerges {
+		args = append(args, "--no-merges")
+	}
+	if opts.Reverse {
+		args = append(args, "--reverse")
+	}
+	if opts.Range != "" {
+		args = append(args, opts.Range)
+	}
+	if len(opts.Paths) > 0 {
+		args = append(append(args, "--"), opts.Paths...)
+	}
+	out, err := RunCommandSilent("git", args...)
+	if err != nil {
+		return nil, fmt.Errorf("git log failed: %s", firstLine(out))
+	}
+
+	var commits []Commit
+	for _, record := range strings.Split(out, gitRecordSep) {
+		fields := strings.SplitN(strings.TrimLeft(record, "\n"), gitFieldSep, 6)
+		if len(fields) < 6 {
+			continue
+		}
+		date, _ := time.Parse(time.RFC3339, fields[4])
+		subject, body, _ := strings.Cut(strings.TrimSpace(fields[5]), "\n")
+		commits = append(commits, Commit{
+			Hash:    fields[0],
+			Parents: strings.Fields(fields[1]),
+			Author:  fields[2],
+			Email:   fields[3],
+			Date:    date,
+			Subject: strings.TrimSpace(subject),
+			Body:    strings.TrimSpace(body),
+		})
+	}
+	return commits, nil
+}
+
+// FileStat is the line count of one file of a DiffStat
+type FileStat struct {
+	Path    string
+	Added   int
+	Deleted int
+	Binary  bool // Binary files have no line counts
+}
+
+// DiffStat sums the changes of a diff by file, as git diff --numstat
+type DiffStat struct {
+	Files   []FileStat
+	Added   int
+	Deleted int
+}
+
+// String returns the git --shortstat form, e.g.
+// "3 files changed, 10 insertions(+), 2 deletions(-)"
+func (s DiffStat) String() string {
+	plural := func(n int, one, many string) string {
+		if n == 1 {
+			return "1 " + one
+		}
+		return fmt.Sprintf("%d %s", n, many)
+	}
+	return fmt.Sprintf("%s, %s(+), %s(-)", plural(len(s.Files), "file changed", "files changed"),
+		plural(s.Added, "insertion", "insertions"), plural(s.Deleted, "deletion", "deletions"))
+}
+
+// DiffStat returns the changes of the working tree and the index against
+// HEAD, or with revisions those of git diff <revs>: against one revision,
+// or between two. Renames count as a deletion and an addition.
+func (g *Git) DiffStat(revs ...string) (DiffStat, error) {
+	if len(revs) == 0 {
+		revs = []string{"HEAD"}
+	}
+	args := append([]string{"diff", "--numstat", "--no-renames", "-z"}, revs...)
+	out, err := RunCommandSilent("git", append(args, "--")...)
+	if err != nil {
+		return DiffStat{}, fmt.Errorf("git diff failed: %s", firstLine(out))
+	}
+
+	var s DiffStat
+	for _, entry := range strings.Split(out, "\x00") {
+		fields := strings.SplitN(strings.TrimLeft(entry, "\n"), "\t", 3)
+		if len(fields) < 3 {
+			continue
+		}
+		f := FileStat{Path: fields[2]}
+		if fields[0] == "-" {
+			f.Binary = true
+		} else {
+			f.Added, _ = strconv.Atoi(fields[0])
+			f.Deleted, _ = strconv.Atoi(fields[1])
+		}
+		s.Files = append(s.Files, f)
+		s.Added += f.Added
+		s.Deleted += f.Deleted
+	}
+	return s, nil
+}
+
+// Tag is a tag of Git.Tags
+type Tag struct {
+	Name      string
+	Commit    string    // Hash of the tagged commit
+	Date      time.Time // Tagger date of an annotated tag, else the commit date
+	Annotated bool
+	Subject   string // First line of the tag message, or of the commit of a lightweight tag
+}
+
+// Tags returns the tags of the repository, newest first
+func (g *Git) Tags() ([]Tag, error) {
+	out, err := RunCommandSilent("git", "for-each-ref", "--sort=-creatordate",
+		"--format=%(refname:short)%1f%(objecttype)%1f%(objectname)%1f%(*objectname)%1f%(creatordate:iso-strict)%1f%(contents:subject)%1e",
+		"refs/tags")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %s", firstLine(out))
+	}
+
+	var tags []Tag
+	for _, record := range strings.Split(out, gitRecordSep) {
+		fields := strings.SplitN(strings.TrimLeft(record, "\n"), gitFieldSep, 6)
+		if len(fields) < 6 {
+			continue
+		}
+		date, _ := time.Parse(time.RFC3339, fields[4])
+		tag := Tag{Name: fields[0], Commit: fields[2], Date: date, Subject: fields[5]}
+		if fields[1] == "tag" {
+			tag.Annotated = true
+			tag.Commit = fields[3]
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
@@ -0,0 +1,96 @@
+package devflow
+
+import (
+	"os"
+	"os/exec"
+	"reflect"
+	"testing"
+)
+
+func TestGitLog(t *testing.T) {
+	dir, cleanup := testCreateGitRepo()
+	defer cleanup()
+	defer testChdir(t, dir)()
+
+	git, _ := NewGit()
+	if commits, err := git.Log(LogOptions{}); err != nil || commits != nil {
+		t.Fatalf("expected no commits in an empty repository, got %v, %v", commits, err)
+	}
+
+	os.WriteFile("a.go", []byte("package a\n"), 0644)
+	exec.Command("git", "add", ".").Run()
+	exec.Command("git", "commit", "-qm", "feat: a").Run()
+	os.WriteFile("b.go", []byte("package a\n"), 0644)
+	exec.Command("git", "add", ".").Run()
+	exec.Command("git", "commit", "-qm", "fix: b\n\nDetails: with a colon.\n\nRefs: #1").Run()
+
+	commits, err := git.Log(LogOptions{})
+	if err != nil || len(commits) != 2 {
+		t.Fatalf("expected 2 commits, got %v, %v", commits, err)
+	}
+	c := commits[0]
+	if c.Subject != "fix: b" || c.Body != "Details: with a colon.\n\nRefs: #1" || c.Author != "Test" || c.Email != "test@test.com" {
+		t.Errorf("unexpected commit %+v", c)
+	}
+	if len(c.Hash) != 40 || !reflect.DeepEqual(c.Parents, []string{commits[1].Hash}) || c.Date.IsZero() {
+		t.Errorf("unexpected hash, parents or date %+v", c)
+	}
+	if c.Message() != "fix: b\n\nDetails: with a colon.\n\nRefs: #1" || commits[1].Message() != "feat: a" {
+		t.Errorf("unexpected messages %q, %q", c.Message(), commits[1].Message())
+	}
+
+	if commits, _ := git.Log(LogOptions{Paths: []string{"a.go"}}); len(commits) != 1 || commits[0].Subject != "feat: a" {
+		t.Errorf("expected the a.go commit, got %v", commits)
+	}
+	if commits, _ := git.Log(LogOptions{Reverse: true, Max: 1}); len(commits) != 1 {
+		t.Errorf("expected one commit, got %v", commits)
+	}
+	if _, err := git.Log(LogOptions{Range: "v9.9.9..HEAD"}); err == nil {
+		t.Error("expected an unknown revision to fail")
+	}
+}
+
+func TestGitDiffStatAndTags(t *testing.T) {
+	dir, cleanup := testCreateGitRepo()
+	defer cleanup()
+	defer testChdir(t, dir)()
+
+	os.WriteFile("a.go", []byte("package a\n"), 0644)
+	exec.Command("git", "add", ".").Run()
+	exec.Command("git", "commit", "-qm", "feat: a").Run()
+	exec.Command("git", "tag", "v0.1.0").Run()
+	exec.Command("git", "tag", "-a", "v0.2.0", "-m", "Release v0.2.0\n\nNotes").Run()
+
+	git, _ := NewGit()
+	os.WriteFile("a.go", []byte("package b\n\nfunc A() {}\n"), 0644)
+	os.WriteFile("logo.bin", []byte{0, 1, 2}, 0644)
+	exec.Command("git", "add", "logo.bin").Run()
+	stat, err := git.DiffStat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := DiffStat{Files: []FileStat{{Path: "a.go", Added: 3, Deleted: 1}, {Path: "logo.bin", Binary: true}}, Added: 3, Deleted: 1}
+	if !reflect.DeepEqual(stat, want) {
+		t.Errorf("unexpected diff stat %+v", stat)
+	}
+	if stat.String() != "2 files changed, 3 insertions(+), 1 deletion(-)" {
+		t.Errorf("unexpected summary %q", stat.String())
+	}
+	if stat, _ := git.DiffStat("v0.1.0", "v0.2.0"); len(stat.Files) != 0 {
+		t.Errorf("expected no changes between the tags, got %+v", stat)
+	}
+
+	tags, err := git.Tags()
+	if err != nil || len(tags) != 2 {
+		t.Fatalf("expected 2 tags, got %v, %v", tags, err)
+	}
+	head, _ := RunCommandSilent("git", "rev-parse", "HEAD")
+	byName := map[string]Tag{tags[0].Name: tags[0], tags[1].Name: tags[1]}
+	annotated, light := byName["v0.2.0"], byName["v0.1.0"]
+	if !annotated.Annotated || annotated.Commit != head || annotated.Subject != "Release v0.2.0" || annotated.Date.IsZero() {
+		t.Errorf("unexpected annotated tag %+v", annotated)
+	}
+	if light.Annotated || light.Commit != head || light.Subject != "feat: a" {
+		t.Errorf("unexpected lightweight tag %+v", light)
+	}
+}
@@ -0,0 +1,185 @@
+package devflow
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PushOptions tunes how Git.Push records the commit.
+// The zero value keeps the default add, commit, tag, push behavior.
+type PushOptions struct {
+	Amend   bool          // Amend HEAD instead of committing when HEAD is an unpushed auto-update
+	Squash  int           // Squash the last N unpushed commits and the new changes into one commit
+	Exclude []string      // Paths left out of the commit (unstaged after git add)
+	Split   bool          // Split staged changes into one commit per group
+	Groups  []CommitGroup // Groups used by Split (default: top-level directory)
+	NoTag   bool          // Commit and push the branch without creating a tag
+	Bump    string        // Bump level of the generated tag: patch, minor or major (default: from the commit messages)
+	Branch  string        // Commit on and push this branch, created from HEAD when missing (default: the current branch)
+	Issue   string        // Issue the commit trailers reference, e.g. 42 or PROJ-7 (default: from the branch with commit.issue_from_branch)
+
+	SignOff   bool     // Append Signed-off-by with the git user.name and user.email (also commit.signoff)
+	CoAuthors []string // Co-authored-by identities, "Name <email>" or a name or email of an earlier commit author (added to commit.co_authors)
+
+	// StashUncommitted commits and tags only the staged changes: untracked
+	// files and unstaged changes are stashed first and restored after the push
+	StashUncommitted bool
+
+	// Paths stages only the selected changes instead of git add .
+	Paths CommitOptions
+}
+
+// autoUpdatePrefixes are commit subjects considered routine updates
+// (dependency bumps made by gopush, doc-only iterations).
+var autoUpdatePrefixes = []string{"deps:", "docs:"}
+
+// SetPushOptions sets the options used by Push
+func (g *Git) SetPushOptions(opts PushOptions) {
+	g.pushOpts = opts
+}
+
+// unstageExcluded removes the excluded paths from the index after git add
+func (g *Git) unstageExcluded() error {
+	return unstagePaths(g.pushOpts.Exclude)
+}
+
+// commitForPush commits staged changes honoring the configured PushOptions.
+// Returns a summary entry (empty when nothing special happened).
+func (g *Git) commitForPush(message string) (string, error) {
+	trailers, err := g.commitTrailers()
+	if err != nil {
+		return "", err
+	}
+
+	if g.pushOpts.Split {
+		if g.pushOpts.Squash > 0 || g.pushOpts.Amend {
+			return "", fmt.Errorf("split cannot be combined with amend or squash")
+		}
+		return g.commitGroups(message, trailers)
+	}
+
+	if g.pushOpts.Squash > 0 {
+		return g.squashCommits(g.pushOpts.Squash, message, trailers)
+	}
+
+	message = AddTrailers(message, trailers)
+
+	if g.pushOpts.Amend {
+		ok, err := g.canAmend()
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			if _, err := RunCommand("git", "commit", "--amend", "-m", message); err != nil {
+				return "", err
+			}
+			return "✅ Amended HEAD", nil
+		}
+		g.logger.Debug("HEAD is pushed, tagged or not an auto-update, creating a new commit")
+	}
+
+	_, err = g.Commit(message)
+	return "", err
+}
+
+// canAmend reports whether HEAD can be safely amended: it must be unpushed,
+// untagged and an auto-update commit.
+func (g *Git) canAmend() (bool, error) {
+	if _, err := RunCommandSilent("git", "rev-parse", "HEAD"); err != nil {
+		return false, nil
+	}
+
+	unpushed, err := g.unpushedCount()
+	if err != nil {
+		return false, err
+	}
+	if unpushed == 0 {
+		return false, nil
+	}
+
+	tags, _ := RunCommandSilent("git", "tag", "--points-at", "HEAD")
+	if tags != "" {
+		return false, nil
+	}
+
+	subject, err := RunCommandSilent("git", "log", "-1", "--format=%s")
+	if err != nil {
+		return false, err
+	}
+	return isAutoUpdateCommit(subject), nil
+}
+
+// squashCommits folds the last n unpushed commits plus staged changes into a
+// single commit whose body lists the squashed subjects, then trailers.
+func (g *Git) squashCommits(n int, message string, trailers []string) (string, error) {
+	unpushed, err := g.unpushedCount()
+	if err != nil {
+		return "", err
+	}
+	if n > unpushed {
+		return "", fmt.Errorf("cannot squash %d commits: only %d unpushed", n, unpushed)
+	}
+
+	total, err := RunCommandSilent("git", "rev-list", "--count", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	if count, _ := strconv.Atoi(total); n >= count {
+		return "", fmt.Errorf("cannot squash %d commits: the root commit cannot be squashed", n)
+	}
+
+	subjects, err := RunCommandSilent("git", "log", "-n", strconv.Itoa(n), "--format=%s")
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := RunCommand("git", "reset", "--soft", fmt.Sprintf("HEAD~%d", n)); err != nil {
+		return "", err
+	}
+
+	if _, err := RunCommand("git", "commit", "-m", AddTrailers(squashMessage(message, subjects), trailers)); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("✅ Squashed %d commits", n), nil
+}
+
+// unpushedCount returns the number of commits in HEAD not present on any
+// remote: all local commits on a branch never pushed, none before the
+// first commit
+func (g *Git) unpushedCount() (int, error) {
+	if _, err := RunCommandSilent("git", "rev-parse", "--verify", "-q", "HEAD"); err != nil {
+		return 0, nil
+	}
+	out, err := RunCommandSilent("git", "rev-list", "--count", "HEAD", "--not", "--remotes")
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unpushed commits: %w", err)
+	}
+	return strconv.Atoi(strings.TrimSpace(out))
+}
+
+// isAutoUpdateCommit reports whether a commit subject is a routine update
+func isAutoUpdateCommit(subject string) bool {
+	subject = strings.TrimSpace(subject)
+	for _, prefix := range autoUpdatePrefixes {
+		if strings.HasPrefix(subject, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// squashMessage builds the combined message: the new message followed by
+// the squashed subjects (newest first) as a bullet list.
+func squashMessage(message, subjects string) string {
+	var body []string
+	for _, s := range strings.Split(subjects, "\n") {
+		if s = strings.TrimSpace(s); s != "" && s != message {
+			body = append(body, "- "+s)
+		}
+	}
+	if len(body) == 0 {
+		return message
+	}
+	return message + "\n\n" + strings.Join(body, "\n")
+}
@@ -0,0 +1,129 @@
+package devflow
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// testPushedRepo creates a repo with a bare remote and one pushed commit
+func testPushedRepo(t *testing.T) func() {
+	t.Helper()
+	remoteDir := t.TempDir()
+	exec.Command("git", "init", "--bare", remoteDir).Run()
+
+	dir, cleanup := testCreateGitRepo()
+	restore := testChdir(t, dir)
+
+	exec.Command("git", "remote", "add", "origin", "file://"+remoteDir).Run()
+	os.WriteFile("README.md", []byte("# test"), 0644)
+	exec.Command("git", "add", ".").Run()
+	exec.Command("git", "commit", "-m", "initial").Run()
+	exec.Command("git", "tag", "v0.0.1").Run()
+	exec.Command("git", "push", "-u", "origin", "HEAD", "--tags").Run()
+
+	return func() {
+		restore()
+		cleanup()
+	}
+}
+
+func testCommitCount(t *testing.T) int {
+	t.Helper()
+	out, err := RunCommandSilent("git", "rev-list", "--count", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, _ := strconv.Atoi(out)
+	return n
+}
+
+func TestGitPushAmendAutoUpdate(t *testing.T) {
+	defer testPushedRepo(t)()
+
+	os.WriteFile("doc.md", []byte("first"), 0644)
+	exec.Command("git", "add", ".").Run()
+	exec.Command("git", "commit", "-m", "docs: first draft").Run()
+
+	git, _ := NewGit()
+	git.SetPushOptions(PushOptions{Amend: true})
+
+	os.WriteFile("doc.md", []byte("second"), 0644)
+	summary, err := git.Push("docs: final wording", "")
+	if err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	if !strings.Contains(summary, "Amended HEAD") {
+		t.Errorf("expected amend in summary, got: %s", summary)
+	}
+	if n := testCommitCount(t); n != 2 {
+		t.Errorf("expected 2 commits after amend, got %d", n)
+	}
+	subject, _ := RunCommandSilent("git", "log", "-1", "--format=%s")
+	if subject != "docs: final wording" {
+		t.Errorf("unexpected HEAD subject: %s", subject)
+	}
+}
+
+func TestGitPushAmendSkipsRegularCommit(t *testing.T) {
+	defer testPushedRepo(t)()
+
+	os.WriteFile("main.go", []byte("package main"), 0644)
+	exec.Command("git", "add", ".").Run()
+	exec.Command("git", "commit", "-m", "feat: new code").Run()
+
+	git, _ := NewGit()
+	git.SetPushOptions(PushOptions{Amend: true})
+
+	os.WriteFile("doc.md", []byte("doc"), 0644)
+	if _, err := git.Push("docs: explain", ""); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	if n := testCommitCount(t); n != 3 {
+		t.Errorf("expected a new commit (3 total), got %d", n)
+	}
+}
+
+func TestGitPushSquash(t *testing.T) {
+	defer testPushedRepo(t)()
+
+	for _, msg := range []string{"wip one", "wip two"} {
+		os.WriteFile("notes.md", []byte(msg), 0644)
+		exec.Command("git", "add", ".").Run()
+		exec.Command("git", "commit", "-m", msg).Run()
+	}
+
+	git, _ := NewGit()
+	git.SetPushOptions(PushOptions{Squash: 2})
+
+	os.WriteFile("notes.md", []byte("done"), 0644)
+	summary, err := git.Push("docs: notes", "")
+	if err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	if !strings.Contains(summary, "Squashed 2 commits") {
+		t.Errorf("expected squash in summary, got: %s", summary)
+	}
+	if n := testCommitCount(t); n != 2 {
+		t.Errorf("expected 2 commits after squash, got %d", n)
+	}
+	body, _ := RunCommandSilent("git", "log", "-1", "--format=%B")
+	if !strings.Contains(body, "- wip one") || !strings.Contains(body, "- wip two") {
+		t.Errorf("combined message missing squashed subjects:\n%s", body)
+	}
+}
+
+func TestGitPushSquashTooMany(t *testing.T) {
+	defer testPushedRepo(t)()
+
+	git, _ := NewGit()
+	git.SetPushOptions(PushOptions{Squash: 3})
+
+	os.WriteFile("notes.md", []byte("x"), 0644)
+	_, err := git.Push("docs: notes", "")
+	if err == nil || !strings.Contains(err.Error(), "only 0 unpushed") {
+		t.Errorf("expected unpushed count error, got %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package devflow
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// CommitOptions selects the changes Push stages instead of git add .:
+// the changed paths that match Include (all when empty) and no Exclude
+// pattern. Patterns are those of CommitGroup: path prefixes ("pkg/") or
+// globs matched against the full path or the file name ("*.md").
+type CommitOptions struct {
+	Include []string // Stage only the changes under these patterns, e.g. pkg/
+	Exclude []string // Leave the changes under these patterns unstaged, e.g. *.tmp
+}
+
+// Empty reports whether the options stage every change
+func (o CommitOptions) Empty() bool {
+	return len(o.Include) == 0 && len(o.Exclude) == 0
+}
+
+// Match reports whether the change of path is staged with the options
+func (o CommitOptions) Match(path string) bool {
+	if len(o.Include) > 0 && !(CommitGroup{Patterns: o.Include}).match(path) {
+		return false
+	}
+	return !(CommitGroup{Patterns: o.Exclude}).match(path)
+}
+
+// ParsePatterns splits comma-separated patterns, as -only and -exclude
+// take them
+func ParsePatterns(s string) []string {
+	var patterns []string
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns
+}
+
+// AddSelected stages the changes that match opts (new, modified and
+// deleted files) and unstages those already staged that do not, leaving
+// the working tree as it is. It returns the staged paths, relative to the
+// current directory.
+func (g *Git) AddSelected(opts CommitOptions) ([]string, error) {
+	changed, err := RunCommandSilent("git", "ls-files", "--modified", "--deleted", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changed files: %w", err)
+	}
+	staged, err := RunCommandSilent("git", "diff", "--cached", "--name-only", "--relative")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list staged files: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var add, unstage []string
+	for _, path := range nonEmptyLines(changed + "\n" + staged) {
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		if opts.Match(path) {
+			add = append(add, path)
+		} else {
+			unstage = append(unstage, path)
+		}
+	}
+
+	if len(add) > 0 {
+		if _, err := RunCommand("git", append([]string{"add", "-A", "--"}, add...)...); err != nil {
+			return nil, err
+		}
+	}
+	isStaged := make(map[string]bool)
+	for _, path := range nonEmptyLines(staged) {
+		isStaged[path] = true
+	}
+	var reset []string
+	for _, path := range unstage {
+		if isStaged[path] {
+			reset = append(reset, path)
+		}
+	}
+	if err := unstagePaths(reset); err != nil {
+		return nil, err
+	}
+	return add, nil
+}
+
+// unstagePaths removes paths from the index, keeping their working tree
+// changes
+func unstagePaths(paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	args := append([]string{"reset", "-q", "--"}, paths...)
+	if _, err := RunCommandSilent("git", "rev-parse", "HEAD"); err != nil {
+		// Fresh repo: nothing tracked yet, drop the paths from the index instead
+		args = append([]string{"rm", "--cached", "-r", "-q", "--"}, paths...)
+	}
+	_, err := RunCommand("git", args...)
+	return err
+}
+
+// stagePushFiles stages files of the root that Push wrote itself
+func (g *Git) stagePushFiles(files []string) error {
+	if len(files) == 0 {
+		return nil
+	}
+	args := []string{"add", "--"}
+	for _, f := range files {
+		args = append(args, filepath.Join(g.rootDir, f))
+	}
+	_, err := RunCommand("git", args...)
+	return err
+}
+
+// stageForPush stages the changes of a push: everything, or the changes
+// selected by PushOptions.Paths
+func (g *Git) stageForPush() (string, error) {
+	opts := g.pushOpts.Paths
+	if opts.Empty() {
+		return "", g.Add()
+	}
+	staged, err := g.AddSelected(opts)
+	if err != nil {
+		return "", err
+	}
+	if len(staged) == 0 && len(opts.Include) > 0 {
+		return "", fmt.Errorf("no changes match %s", strings.Join(opts.Include, ", "))
+	}
+	return fmt.Sprintf("✅ Staged %d selected files", len(staged)), nil
+}
@@ -0,0 +1,55 @@
+package devflow
+
+import (
+	"os"
+	"os/exec"
+	"reflect"
+	"testing"
+)
+
+func TestCommitOptionsMatch(t *testing.T) {
+	opts := CommitOptions{Include: []string{"pkg/", "*.go"}, Exclude: []string{"*_test.go"}}
+	for path, want := range map[string]bool{"pkg/a.txt": true, "main.go": true, "cmd/x/main.go": true, "pkg/a_test.go": false, "notes.txt": false} {
+		if got := opts.Match(path); got != want {
+			t.Errorf("Match(%q) = %v, want %v", path, got, want)
+		}
+	}
+	if !(CommitOptions{}).Match("anything") || !(CommitOptions{}).Empty() {
+		t.Error("expected empty options to stage everything")
+	}
+	if got := ParsePatterns(" pkg/, *.go ,,"); !reflect.DeepEqual(got, []string{"pkg/", "*.go"}) {
+		t.Errorf("unexpected patterns %q", got)
+	}
+}
+
+func TestGitPushOnly(t *testing.T) {
+	defer testPushedRepo(t)()
+	os.MkdirAll("pkg", 0755)
+	os.WriteFile("pkg/a.go", []byte("package pkg\n"), 0644)
+	os.WriteFile("scratch.txt", []byte("notes\n"), 0644)
+	os.WriteFile("staged.txt", []byte("x\n"), 0644)
+	exec.Command("git", "add", "staged.txt").Run()
+	os.Remove("README.md")
+
+	git, _ := NewGit()
+	git.SetPushOptions(PushOptions{Paths: CommitOptions{Include: []string{"pkg/", "README.md"}}})
+	summary, err := git.Push("feat: pkg", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary != "✅ Staged 2 selected files, ✅ Tag: v0.1.0, ✅ Pushed ok" {
+		t.Errorf("unexpected summary %q", summary)
+	}
+	files, _ := RunCommandSilent("git", "show", "--name-status", "--format=", "HEAD")
+	if files != "D\tREADME.md\nA\tpkg/a.go" {
+		t.Errorf("unexpected files in the commit %q", files)
+	}
+	if status, _ := RunCommandSilent("git", "status", "--porcelain"); status != "?? scratch.txt\n?? staged.txt" {
+		t.Errorf("expected the other changes left in the working tree, got %q", status)
+	}
+
+	git.SetPushOptions(PushOptions{Paths: CommitOptions{Include: []string{"docs/"}}})
+	if _, err := git.Push("docs: none", ""); err == nil {
+		t.Error("expected -only without matching changes to fail")
+	}
+}
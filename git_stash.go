@@ -0,0 +1,187 @@
+package devflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// pushStashMessage names the stash Push makes with StashUncommitted
+const pushStashMessage = "devflow push: uncommitted changes"
+
+// UncommittedChanges are the changes of the working tree that are not
+// staged, by path
+type UncommittedChanges struct {
+	Untracked []string // New files not ignored and not added
+	Unstaged  []string // Tracked files with changes not in the index
+	Partial   []string // Unstaged files that also have staged changes
+}
+
+// Empty reports whether everything in the working tree is staged
+func (c UncommittedChanges) Empty() bool {
+	return len(c.Untracked) == 0 && len(c.Unstaged) == 0
+}
+
+// String returns the counts, e.g. "2 untracked, 1 unstaged (1 partially staged)"
+func (c UncommittedChanges) String() string {
+	var parts []string
+	if n := len(c.Untracked); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d untracked", n))
+	}
+	if n := len(c.Unstaged); n > 0 {
+		part := fmt.Sprintf("%d unstaged", n)
+		if len(c.Partial) > 0 {
+			part += fmt.Sprintf(" (%d partially staged)", len(c.Partial))
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// UncommittedChanges returns the untracked and unstaged files of the
+// repository, relative to its top directory
+func (g *Git) UncommittedChanges() (UncommittedChanges, error) {
+	var c UncommittedChanges
+	untracked, err := RunCommandSilent("git", "ls-files", "--others", "--exclude-standard", "--full-name", ":/")
+	if err != nil {
+		return c, fmt.Errorf("failed to list untracked files: %w", err)
+	}
+	unstaged, err := RunCommandSilent("git", "diff", "--name-only")
+	if err != nil {
+		return c, fmt.Errorf("failed to list unstaged files: %w", err)
+	}
+	staged, err := RunCommandSilent("git", "diff", "--cached", "--name-only")
+	if err != nil {
+		return c, fmt.Errorf("failed to list staged files: %w", err)
+	}
+	c.Untracked = nonEmptyLines(untracked)
+	c.Unstaged = nonEmptyLines(unstaged)
+	isStaged := make(map[string]bool)
+	for _, path := range nonEmptyLines(staged) {
+		isStaged[path] = true
+	}
+	for _, path := range c.Unstaged {
+		if isStaged[path] {
+			c.Partial = append(c.Partial, path)
+		}
+	}
+	return c, nil
+}
+
+// PushStasher is implemented by git clients that put the changes Push
+// leaves out aside before it is called, so Go.Push tests what is committed
+type PushStasher interface {
+	StashForPush() (summary string, restore func() error, err error)
+}
+
+// StashForPush stashes the untracked files and unstaged changes when
+// PushOptions.StashUncommitted is set, until restore is called; Push does
+// not stash them again meanwhile. It returns the summary entry and restore,
+// nil when nothing was stashed. An interrupt before restore prints how to
+// get the files back from the stash.
+func (g *Git) StashForPush() (string, func() error, error) {
+	if !g.pushOpts.StashUncommitted || g.stashHeld {
+		return "", nil, nil
+	}
+	summary, restore, err := g.stashUncommitted()
+	if err != nil || restore == nil {
+		return "", nil, err
+	}
+	g.stashHeld = true
+	removeHint := OnInterrupt(func() {
+		fmt.Fprintf(os.Stderr, "⚠️ Uncommitted changes are in the stash %q. Restore them with git checkout stash@{0} -- :/ (and stash@{0}^3 for the untracked files), then git stash drop\n", pushStashMessage)
+	})
+	return summary, func() error {
+		removeHint()
+		g.stashHeld = false
+		return restore()
+	}, nil
+}
+
+// stashUncommitted puts the changes that are not staged aside, so Push
+// commits and tags only the index (plus its own changelog and copyright
+// updates). It returns a summary entry and the func that restores them,
+// nil when there was nothing to stash.
+func (g *Git) stashUncommitted() (string, func() error, error) {
+	changes, err := g.UncommittedChanges()
+	if err != nil || changes.Empty() {
+		return "", nil, err
+	}
+	if _, err := RunCommandSilent("git", "rev-parse", "--verify", "HEAD"); err != nil {
+		return "", nil, fmt.Errorf("cannot stash uncommitted changes before the first commit; stage everything or commit once")
+	}
+	top, err := RunCommandSilent("git", "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", nil, err
+	}
+	deletedOut, err := RunCommandSilent("git", "diff", "--name-only", "--diff-filter=D")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to list unstaged files: %w", err)
+	}
+	deleted := nonEmptyLines(deletedOut)
+	if _, err := RunCommand("git", "stash", "push", "--keep-index", "--include-untracked", "--quiet", "-m", pushStashMessage); err != nil {
+		return "", nil, fmt.Errorf("failed to stash uncommitted changes: %w", err)
+	}
+	g.logger.Debug("Stashed", changes.String(), "changes")
+
+	restore := func() error {
+		if err := restoreStash(top, changes, deleted); err != nil {
+			return fmt.Errorf("failed to restore the uncommitted changes, they are kept in the stash (%s): %w", pushStashMessage, err)
+		}
+		return nil
+	}
+	return "✅ Stashed " + changes.String(), restore, nil
+}
+
+// restoreStash puts the working tree files of the stash Push made back and
+// drops it. A stash pop would merge the unstaged part of partially staged
+// files with their committed part and conflict; the stashed files are the
+// wanted result, so they are checked out as they are and left unstaged.
+func restoreStash(top string, changes UncommittedChanges, deleted []string) error {
+	isDeleted := make(map[string]bool, len(deleted))
+	for _, path := range deleted {
+		isDeleted[path] = true
+	}
+	var modified []string
+	for _, path := range changes.Unstaged {
+		if !isDeleted[path] {
+			modified = append(modified, path)
+		}
+	}
+	if len(modified) > 0 {
+		if _, err := RunCommandInDir(top, "git", append([]string{"checkout", "stash@{0}", "--"}, modified...)...); err != nil {
+			return err
+		}
+	}
+	if len(changes.Untracked) > 0 {
+		if _, err := RunCommandInDir(top, "git", append([]string{"checkout", "stash@{0}^3", "--"}, changes.Untracked...)...); err != nil {
+			return err
+		}
+	}
+	// Deleted files stay in the index, as before the stash
+	for _, path := range deleted {
+		if err := os.Remove(filepath.Join(top, path)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	// checkout stages the files; only the pushed commit was staged
+	if paths := append(modified, changes.Untracked...); len(paths) > 0 {
+		if _, err := RunCommandInDir(top, "git", append([]string{"reset", "-q", "--"}, paths...)...); err != nil {
+			return err
+		}
+	}
+	_, err := RunCommandInDir(top, "git", "stash", "drop", "--quiet")
+	return err
+}
+
+// nonEmptyLines splits output into lines, leaving out empty ones
+func nonEmptyLines(out string) []string {
+	var lines []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
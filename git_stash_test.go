@@ -0,0 +1,102 @@
+package devflow
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestGitPushStashUncommitted(t *testing.T) {
+	defer testPushedRepo(t)()
+	os.WriteFile("a.go", []byte("package a\n"), 0644)
+	exec.Command("git", "add", "a.go").Run()
+	exec.Command("git", "commit", "-qm", "feat: a").Run()
+	exec.Command("git", "push", "-q").Run()
+
+	// a.go is partially staged, scratch.txt untracked
+	os.WriteFile("a.go", []byte("package a\n\nfunc A() {}\n"), 0644)
+	exec.Command("git", "add", "a.go").Run()
+	os.WriteFile("a.go", []byte("package a\n\nfunc A() {}\n\n// TODO\n"), 0644)
+	os.WriteFile("scratch.txt", []byte("notes\n"), 0644)
+	os.Remove("README.md")
+
+	git, _ := NewGit()
+	changes, err := git.UncommittedChanges()
+	if err != nil || changes.String() != "1 untracked, 2 unstaged (1 partially staged)" {
+		t.Fatalf("unexpected changes %q, %v", changes, err)
+	}
+	git.SetPushOptions(PushOptions{StashUncommitted: true})
+	summary, err := git.Push("fix: add A", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(summary, "✅ Stashed 1 untracked, 2 unstaged (1 partially staged)") {
+		t.Errorf("unexpected summary %q", summary)
+	}
+
+	files, _ := RunCommandSilent("git", "show", "--name-only", "--format=", "HEAD")
+	if files != "a.go" {
+		t.Errorf("expected only a.go in the commit, got %q", files)
+	}
+	if committed, _ := RunCommandSilent("git", "show", "HEAD:a.go"); strings.Contains(committed, "TODO") {
+		t.Error("expected the unstaged part left out of the commit")
+	}
+	if data, _ := os.ReadFile("a.go"); !strings.Contains(string(data), "TODO") {
+		t.Error("expected the unstaged part restored")
+	}
+	if _, err := os.Stat("scratch.txt"); err != nil {
+		t.Error("expected the untracked file restored")
+	}
+	if _, err := os.Stat("README.md"); !os.IsNotExist(err) {
+		t.Error("expected the unstaged deletion restored")
+	}
+	if status, _ := RunCommandSilent("git", "status", "--porcelain"); status != "D README.md\n M a.go\n?? scratch.txt" {
+		t.Errorf("expected the changes unstaged again, got %q", status)
+	}
+	if stashes, _ := RunCommandSilent("git", "stash", "list"); stashes != "" {
+		t.Errorf("expected the stash dropped, got %q", stashes)
+	}
+}
+
+func TestGoPushStashesBeforeTests(t *testing.T) {
+	defer testPushedRepo(t)()
+	os.WriteFile("go.mod", []byte("module example.com/stash\n\ngo 1.20\n"), 0644)
+	os.WriteFile(".devflow.yaml", []byte("hooks:\n  before_test:\n    check: \"@check\"\n"), 0644)
+	exec.Command("git", "add", ".").Run()
+	exec.Command("git", "commit", "-qm", "chore: setup").Run()
+	exec.Command("git", "push", "-q").Run()
+
+	os.WriteFile("a.go", []byte("package a\n"), 0644)
+	exec.Command("git", "add", "a.go").Run()
+	os.WriteFile("scratch.go", []byte("package a\n\nbroken(\n"), 0644)
+
+	git, _ := NewGit()
+	git.SetPushOptions(PushOptions{StashUncommitted: true})
+	g, _ := NewGo(git)
+	var sawScratch bool
+	g.RegisterHook("check", func(HookContext) error {
+		_, err := os.Stat("scratch.go")
+		sawScratch = err == nil
+		return nil
+	})
+	summary, err := g.Push("feat: a", "", true, true, true, true, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sawScratch {
+		t.Error("the untracked file should be stashed before the tests run")
+	}
+	if strings.Count(summary, "✅ Stashed") != 1 {
+		t.Errorf("expected one stash entry, got %q", summary)
+	}
+	if files, _ := RunCommandSilent("git", "show", "--name-only", "--format=", "HEAD"); files != "a.go" {
+		t.Errorf("expected only a.go in the commit, got %q", files)
+	}
+	if _, err := os.Stat("scratch.go"); err != nil {
+		t.Error("expected the untracked file restored")
+	}
+	if stashes, _ := RunCommandSilent("git", "stash", "list"); stashes != "" {
+		t.Errorf("expected the stash dropped, got %q", stashes)
+	}
+}
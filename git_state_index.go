@@ -0,0 +1,168 @@
+package devflow
+
+import (
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Git state modes of TestCache.SetGitStateMode (cache.git_state config)
+const (
+	GitStateAuto        = "auto"
+	GitStateFull        = "full"
+	GitStateIncremental = "incremental"
+)
+
+// largeIndexSize is the git index size from which the auto mode hashes
+// changed files instead of the full diff: about 10k tracked files
+const largeIndexSize = 1 << 20
+
+// racyWindow is how recent a file mtime may be for its hash to be kept
+// in the index: a write in the same timestamp tick would go unnoticed
+const racyWindow = 2 * time.Second
+
+// fileStamp is the index entry of a changed file: its content hash is
+// reused while mtime and size stay the same
+type fileStamp struct {
+	ModTime int64  `json:"mtime"`
+	Size    int64  `json:"size"`
+	Hash    string `json:"hash"`
+}
+
+// incremental reports whether the changed files are hashed instead of the
+// diff; index is the git index file, whose size tells a large repository
+func (tc *TestCache) incremental(index string) bool {
+	switch tc.gitState {
+	case GitStateIncremental:
+		return true
+	case GitStateFull:
+		return false
+	}
+	info, err := os.Stat(index)
+	return err == nil && info.Size() > largeIndexSize
+}
+
+// changedFilesHash hashes the files git status lists as changed or
+// untracked in the work tree at root. Only files whose mtime or size
+// differ from the persistent index are read, so the cost follows the
+// number of changed files, not the size of the repository. A clean tree
+// hashes like an empty diff, so both modes share the entries of committed
+// states.
+func (tc *TestCache) changedFilesHash(root string) (string, error) {
+	out, err := RunCommandSilent("git", "status", "--porcelain=v2", "-z", "--no-renames", "--untracked-files=all")
+	if err != nil {
+		return "", fmt.Errorf("git status failed: %s", firstLine(out))
+	}
+	paths := parseStatusPaths(out)
+	sort.Strings(paths)
+
+	indexPath, err := tc.getCachePath()
+	if err != nil {
+		return "", err
+	}
+	indexPath += ".files"
+	old := map[string]fileStamp{}
+	if data, err := os.ReadFile(indexPath); err == nil {
+		json.Unmarshal(data, &old)
+	}
+
+	index := make(map[string]fileStamp, len(paths))
+	h := md5.New()
+	for _, path := range paths {
+		stamp := hashWorkTreeFile(filepath.Join(root, filepath.FromSlash(path)), old[path])
+		fmt.Fprintf(h, "%s\x00%s\n", path, stamp.Hash)
+		if stamp.ModTime != 0 && time.Since(time.Unix(0, stamp.ModTime)) > racyWindow {
+			index[path] = stamp
+		}
+	}
+
+	if !sameStamps(old, index) {
+		// A lost index only costs re-hashing the changed files
+		if err := os.MkdirAll(tc.cacheDir, 0755); err == nil {
+			if data, err := json.Marshal(index); err == nil {
+				os.WriteFile(indexPath, data, 0644)
+			}
+		}
+	}
+	if len(paths) == 0 {
+		return fmt.Sprintf("%x", md5.Sum(nil)), nil
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// parseStatusPaths returns the paths of git status --porcelain=v2 -z
+// output: changed (1), unmerged (u) and untracked (?) entries
+func parseStatusPaths(out string) []string {
+	var paths []string
+	for _, entry := range strings.Split(out, "\x00") {
+		var fields []string
+		switch {
+		case strings.HasPrefix(entry, "1 "):
+			fields = strings.SplitN(entry, " ", 9)
+		case strings.HasPrefix(entry, "u "):
+			fields = strings.SplitN(entry, " ", 11)
+		case strings.HasPrefix(entry, "? "):
+			fields = strings.SplitN(entry, " ", 2)
+		default:
+			continue
+		}
+		paths = append(paths, fields[len(fields)-1])
+	}
+	return paths
+}
+
+// hashWorkTreeFile returns the stamp of the file at path, reusing the hash
+// of prev when its mtime and size did not change. Deleted files hash as
+// "deleted", symlinks by their target and submodules by their checked out
+// commit; their stamps have no mtime and are not kept.
+func hashWorkTreeFile(path string, prev fileStamp) fileStamp {
+	info, err := os.Lstat(path)
+	switch {
+	case err != nil:
+		return fileStamp{Hash: "deleted"}
+	case info.Mode()&os.ModeSymlink != 0:
+		target, _ := os.Readlink(path)
+		return fileStamp{Hash: "symlink:" + target}
+	case info.IsDir():
+		head, _ := RunCommandInDir(path, "git", "rev-parse", "HEAD")
+		return fileStamp{Hash: "submodule:" + head}
+	case !info.Mode().IsRegular():
+		return fileStamp{Hash: "special"}
+	}
+
+	stamp := fileStamp{ModTime: info.ModTime().UnixNano(), Size: info.Size()}
+	if prev.Hash != "" && prev.ModTime == stamp.ModTime && prev.Size == stamp.Size {
+		stamp.Hash = prev.Hash
+		return stamp
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return fileStamp{Hash: "unreadable"}
+	}
+	defer f.Close()
+	h := md5.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return fileStamp{Hash: "unreadable"}
+	}
+	stamp.Hash = fmt.Sprintf("%x", h.Sum(nil))
+	return stamp
+}
+
+// sameStamps reports whether two indexes are equal
+func sameStamps(a, b map[string]fileStamp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if b[k] != v {
+			return false
+		}
+	}
+	return true
+}
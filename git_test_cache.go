@@ -10,8 +10,17 @@ import (
 
 // TestCache provides git-based test caching to avoid re-running tests
 // when the code hasn't changed since the last successful test run.
+// Results are kept per toolchain, so switching Go versions or GOOS/GOARCH
+// runs the tests again.
 type TestCache struct {
-	cacheDir string
+	cacheDir  string
+	toolchain *Toolchain
+	remote    *RemoteCache
+	remoteHit bool
+	remoteErr error
+	gitState  string // How uncommitted changes are hashed: auto (default), full or incremental
+	module    *ModuleInfo
+	variant   string // Test settings the entries are keyed by, e.g. norace
 }
 
 // NewTestCache creates a new TestCache instance
@@ -21,14 +30,67 @@ func NewTestCache() *TestCache {
 	}
 }
 
-// getCacheKey returns a unique key for the current module based on its path
+// SetModule sets the module the entries are keyed by (default: that of
+// the current directory)
+func (tc *TestCache) SetModule(m *ModuleInfo) {
+	tc.module = m
+}
+
+// SetVariant keys the entries by the test settings of the run, so results
+// of e.g. a run without -race are not reused for one with it
+func (tc *TestCache) SetVariant(v string) {
+	tc.variant = v
+}
+
+// SetRemote shares results through a remote cache (nil disables it)
+func (tc *TestCache) SetRemote(rc *RemoteCache) {
+	tc.remote = rc
+}
+
+// SetGitStateMode sets how the uncommitted changes are hashed into the
+// cache key: full hashes the git diff, incremental hashes the changed files
+// listed by git status through a persistent mtime/size index, and auto
+// (the default) picks incremental for large repositories
+func (tc *TestCache) SetGitStateMode(mode string) error {
+	switch mode {
+	case "", GitStateAuto, GitStateFull, GitStateIncremental:
+		tc.gitState = mode
+		return nil
+	}
+	return fmt.Errorf("unknown git state mode %q (want %s, %s or %s)", mode, GitStateAuto, GitStateFull, GitStateIncremental)
+}
+
+// RemoteResult reports whether the last IsCacheValid was served by the
+// remote cache, and why the remote lookup failed (e.g. a bad signature)
+func (tc *TestCache) RemoteResult() (hit bool, err error) {
+	return tc.remoteHit, tc.remoteErr
+}
+
+// Toolchain returns the toolchain the cache entries are keyed by
+func (tc *TestCache) Toolchain() Toolchain {
+	if tc.toolchain == nil {
+		t := DetectToolchain()
+		tc.toolchain = &t
+	}
+	return *tc.toolchain
+}
+
+// getCacheKey returns a unique key for the current module, toolchain and
+// variant
 func (tc *TestCache) getCacheKey() (string, error) {
-	moduleName, err := getModuleName(".")
-	if err != nil {
-		return "", err
+	if tc.module == nil {
+		m, err := FindModule(".")
+		if err != nil {
+			return "", err
+		}
+		tc.module = m
 	}
 	// Hash the module name to create a safe filename
-	hash := fmt.Sprintf("%x", md5.Sum([]byte(moduleName)))
+	id := tc.module.Path + "\x00" + tc.Toolchain().String()
+	if tc.variant != "" {
+		id += "\x00" + tc.variant
+	}
+	hash := fmt.Sprintf("%x", md5.Sum([]byte(id)))
 	return hash[:16], nil
 }
 
@@ -44,24 +106,38 @@ func (tc *TestCache) getCachePath() (string, error) {
 // getGitState returns current git state: commit hash + diff hash
 // This uniquely identifies the exact state of the code
 func (tc *TestCache) getGitState() (string, error) {
-	// Get current commit hash
-	commitHash, err := RunCommandSilent("git", "rev-parse", "HEAD")
+	// Get current commit hash, the work tree and the index file
+	out, err := RunCommandSilent("git", "rev-parse", "HEAD", "--show-toplevel", "--git-path", "index")
 	if err != nil {
 		return "", fmt.Errorf("failed to get commit hash: %w", err)
 	}
-	commitHash = strings.TrimSpace(commitHash)
+	lines := strings.Split(out, "\n")
+	if len(lines) != 3 {
+		return "", fmt.Errorf("failed to get commit hash: unexpected git rev-parse output %q", out)
+	}
+	commitHash, root, index := strings.TrimSpace(lines[0]), strings.TrimSpace(lines[1]), strings.TrimSpace(lines[2])
 
-	// Get hash of uncommitted changes (if any)
-	diff, err := RunCommandSilent("git", "diff", "HEAD")
-	if err != nil {
-		// No diff or error, use empty
-		diff = ""
+	var diffHash string
+	if tc.incremental(index) {
+		if diffHash, err = tc.changedFilesHash(root); err != nil {
+			return "", err
+		}
+	} else {
+		// Get hash of uncommitted changes (if any)
+		diff, err := RunCommandSilent("git", "diff", "HEAD")
+		if err != nil {
+			// No diff or error, use empty
+			diff = ""
+		}
+		diffHash = fmt.Sprintf("%x", md5.Sum([]byte(diff)))
 	}
 
 	// Combine commit + diff hash for unique state
-	diffHash := fmt.Sprintf("%x", md5.Sum([]byte(diff)))
-
-	return commitHash + ":" + diffHash[:8], nil
+	state := commitHash + ":" + diffHash[:8]
+	if crashers := fuzzCrasherState(); crashers != "" {
+		state += ":" + crashers
+	}
+	return state, nil
 }
 
 // SaveCache saves the current git state and test message
@@ -83,11 +159,21 @@ func (tc *TestCache) SaveCache(message string) error {
 
 	// Store state and message separated by newline
 	content := state + "\n" + message
-	return os.WriteFile(cachePath, []byte(content), 0644)
+	if err := os.WriteFile(cachePath, []byte(content), 0644); err != nil {
+		return err
+	}
+	if tc.remote != nil {
+		if err := tc.remote.Put(filepath.Base(cachePath), state, []byte(content)); err != nil {
+			return fmt.Errorf("remote cache: %w", err)
+		}
+	}
+	return nil
 }
 
-// IsCacheValid checks if tests were already run successfully with the current code
+// IsCacheValid checks if tests were already run successfully with the current code.
+// On a local miss the remote cache is asked, and a verified entry is kept locally.
 func (tc *TestCache) IsCacheValid() bool {
+	tc.remoteHit, tc.remoteErr = false, nil
 	currentState, err := tc.getGitState()
 	if err != nil {
 		return false
@@ -98,18 +184,34 @@ func (tc *TestCache) IsCacheValid() bool {
 		return false
 	}
 
-	cachedData, err := os.ReadFile(cachePath)
-	if err != nil {
-		return false // No cache exists
+	if cachedData, err := os.ReadFile(cachePath); err == nil {
+		// First line is the state
+		lines := strings.SplitN(string(cachedData), "\n", 2)
+		if strings.TrimSpace(lines[0]) == currentState {
+			return true
+		}
 	}
 
-	// First line is the state
-	lines := strings.SplitN(string(cachedData), "\n", 2)
-	if len(lines) < 1 {
+	if tc.remote == nil {
 		return false
 	}
-
-	return strings.TrimSpace(lines[0]) == currentState
+	payload, found, err := tc.remote.Get(filepath.Base(cachePath), currentState)
+	if err != nil || !found {
+		tc.remoteErr = err
+		return false
+	}
+	lines := strings.SplitN(string(payload), "\n", 2)
+	if strings.TrimSpace(lines[0]) != currentState {
+		return false
+	}
+	if err := os.MkdirAll(tc.cacheDir, 0755); err != nil {
+		return false
+	}
+	if err := os.WriteFile(cachePath, payload, 0644); err != nil {
+		return false
+	}
+	tc.remoteHit = true
+	return true
 }
 
 // GetCachedMessage returns the cached test output message
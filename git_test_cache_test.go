@@ -3,7 +3,9 @@ package devflow
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestTestCache_SaveAndValidate(t *testing.T) {
@@ -133,3 +135,108 @@ func containsColon(s string) bool {
 	}
 	return false
 }
+
+func TestTestCache_KeyedByToolchain(t *testing.T) {
+	a := &TestCache{cacheDir: t.TempDir(), toolchain: &Toolchain{"go1.24.0", "linux", "amd64"}}
+	b := &TestCache{cacheDir: a.cacheDir, toolchain: &Toolchain{"go1.25.2", "linux", "amd64"}}
+	c := &TestCache{cacheDir: a.cacheDir, toolchain: &Toolchain{"go1.24.0", "darwin", "arm64"}}
+
+	ka, err := a.getCacheKey()
+	if err != nil {
+		t.Fatalf("Failed to get cache key: %v", err)
+	}
+	kb, _ := b.getCacheKey()
+	kc, _ := c.getCacheKey()
+	if ka == kb || ka == kc {
+		t.Errorf("cache keys should differ per toolchain: %s %s %s", ka, kb, kc)
+	}
+	if got := a.Toolchain().String(); got != "go1.24.0 linux/amd64" {
+		t.Errorf("Toolchain().String() = %q", got)
+	}
+}
+
+func TestTestCache_KeyedByVariant(t *testing.T) {
+	toolchain := &Toolchain{"go1.24.0", "linux", "amd64"}
+	a := &TestCache{cacheDir: t.TempDir(), toolchain: toolchain}
+	b := &TestCache{cacheDir: a.cacheDir, toolchain: toolchain}
+	b.SetVariant("norace")
+
+	ka, err := a.getCacheKey()
+	if err != nil {
+		t.Fatalf("Failed to get cache key: %v", err)
+	}
+	if kb, _ := b.getCacheKey(); ka == kb {
+		t.Errorf("a run without -race must not share the cache key of one with it: %s", ka)
+	}
+}
+
+func TestDetectToolchain(t *testing.T) {
+	tc := DetectToolchain()
+	if !strings.HasPrefix(tc.GoVersion, "go") || tc.GOOS == "" || tc.GOARCH == "" {
+		t.Errorf("unexpected toolchain: %+v", tc)
+	}
+}
+
+func TestTestCache_IncrementalGitState(t *testing.T) {
+	tmp := t.TempDir()
+	defer testChdir(t, tmp)()
+	os.WriteFile("go.mod", []byte("module example.com/big\n\ngo 1.25\n"), 0644)
+	os.WriteFile("a.go", []byte("package big\n"), 0644)
+	for _, args := range [][]string{{"init", "-q"}, {"add", "."}, {"-c", "user.name=t", "-c", "user.email=t@example.com", "commit", "-q", "-m", "init"}} {
+		if out, err := RunCommand("git", args...); err != nil {
+			t.Fatal(out, err)
+		}
+	}
+
+	full := &TestCache{cacheDir: t.TempDir(), toolchain: &Toolchain{"go1.25.2", "linux", "amd64"}}
+	full.SetGitStateMode(GitStateFull)
+	inc := &TestCache{cacheDir: full.cacheDir, toolchain: full.toolchain}
+	if err := inc.SetGitStateMode(GitStateIncremental); err != nil {
+		t.Fatal(err)
+	}
+	if err := inc.SetGitStateMode("fast"); err == nil {
+		t.Error("expected unknown mode error")
+	}
+	clean, err := inc.getGitState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fullClean, _ := full.getGitState(); fullClean != clean {
+		t.Errorf("clean tree states differ: full %s, incremental %s", fullClean, clean)
+	}
+	if (&TestCache{}).incremental(filepath.Join(".git", "index")) {
+		t.Error("auto should use the full diff for small repositories")
+	}
+
+	os.WriteFile("a.go", []byte("package big // edited\n"), 0644)
+	edited, _ := inc.getGitState()
+	if edited == clean {
+		t.Error("editing a file should change the state")
+	}
+	os.WriteFile("new.go", []byte("package big\n"), 0644)
+	untracked, _ := inc.getGitState()
+	if untracked == edited {
+		t.Error("an untracked file should change the state")
+	}
+
+	// Files older than the racy window are indexed and not read again
+	// while mtime and size stay the same
+	old := time.Now().Add(-time.Minute)
+	os.Chtimes("a.go", old, old)
+	if s, _ := inc.getGitState(); s != untracked {
+		t.Errorf("touching a file should not change the state: %s != %s", s, untracked)
+	}
+	path, _ := inc.getCachePath()
+	if data, err := os.ReadFile(path + ".files"); err != nil || !strings.Contains(string(data), `"a.go"`) {
+		t.Fatalf("expected a.go in the index, got %s, %v", data, err)
+	}
+	os.WriteFile("a.go", []byte("package big // EDITED\n"), 0644)
+	os.Chtimes("a.go", old, old)
+	if s, _ := inc.getGitState(); s != untracked {
+		t.Error("an unchanged mtime and size should reuse the indexed hash")
+	}
+	os.Remove("new.go")
+	if s, _ := inc.getGitState(); s == untracked {
+		t.Error("removing a file should change the state")
+	}
+}
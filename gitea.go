@@ -0,0 +1,129 @@
+package devflow
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Gitea creates repositories on a Gitea (or Forgejo) instance through its
+// REST API, authenticated with GITEA_TOKEN
+type Gitea struct {
+	host string
+	rest *restClient
+	log  func(...any)
+}
+
+// NewGitea creates the Gitea handler for host
+func NewGitea(host string, logFn func(...any)) (*Gitea, error) {
+	if logFn == nil {
+		logFn = func(...any) {}
+	}
+	if host == "" {
+		host = "gitea.com"
+	}
+	if err := providerHostCheck("Gitea", host); err != nil {
+		return nil, err
+	}
+	token := os.Getenv("GITEA_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("gitea: set GITEA_TOKEN to an access token for %s", host)
+	}
+	return &Gitea{
+		host: host,
+		rest: newRESTClient("https://"+host+"/api/v1", "Authorization", "token "+token),
+		log:  logFn,
+	}, nil
+}
+
+// SetLog sets the logger function
+func (gt *Gitea) SetLog(fn func(...any)) {
+	if fn != nil {
+		gt.log = fn
+	}
+}
+
+// Name returns the provider name
+func (gt *Gitea) Name() string {
+	return ProviderGitea
+}
+
+// Host returns the Gitea host
+func (gt *Gitea) Host() string {
+	return gt.host
+}
+
+// RepoURL returns the HTTPS clone URL of owner/name
+func (gt *Gitea) RepoURL(owner, name string) string {
+	return fmt.Sprintf("https://%s/%s/%s.git", gt.host, owner, name)
+}
+
+// GetCurrentUser gets the login of the authenticated user
+func (gt *Gitea) GetCurrentUser() (string, error) {
+	var user struct {
+		Login string `json:"login"`
+	}
+	if _, err := gt.rest.do("GET", "/user", nil, &user); err != nil {
+		return "", fmt.Errorf("failed to get current user: %w", err)
+	}
+	return user.Login, nil
+}
+
+// RepoExists checks if owner/name exists
+func (gt *Gitea) RepoExists(owner, name string) (bool, error) {
+	status, err := gt.rest.do("GET", "/repos/"+url.PathEscape(owner)+"/"+url.PathEscape(name), nil, nil)
+	if status == 404 {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// RepoIsEmpty reports whether owner/name has no commits
+func (gt *Gitea) RepoIsEmpty(owner, name string) (bool, error) {
+	var repo struct {
+		Empty bool `json:"empty"`
+	}
+	_, err := gt.rest.do("GET", "/repos/"+url.PathEscape(owner)+"/"+url.PathEscape(name), nil, &repo)
+	return repo.Empty, err
+}
+
+// CreateRepo creates an empty repository for the user or, when owner is
+// another account, for that organization
+func (gt *Gitea) CreateRepo(owner, name, description, visibility string) error {
+	body := map[string]any{"name": name, "description": SanitizeDescription(description), "private": visibility == "private"}
+	path := "/user/repos"
+	if owner != "" {
+		if user, err := gt.GetCurrentUser(); err == nil && user != owner {
+			path = "/orgs/" + url.PathEscape(owner) + "/repos"
+		}
+	}
+	_, err := gt.rest.do("POST", path, body, nil)
+	return err
+}
+
+// DeleteRepo deletes owner/name.
+// WARNING: This permanently deletes the repository and cannot be undone.
+func (gt *Gitea) DeleteRepo(owner, name string) error {
+	_, err := gt.rest.do("DELETE", "/repos/"+url.PathEscape(owner)+"/"+url.PathEscape(name), nil, nil)
+	return err
+}
+
+// IsNetworkError checks if an error is likely a network error
+func (gt *Gitea) IsNetworkError(err error) bool {
+	return isNetworkError(err)
+}
+
+// GetHelpfulErrorMessage returns a helpful message for common errors
+func (gt *Gitea) GetHelpfulErrorMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	if gt.IsNetworkError(err) {
+		return "Network error. Check your connection to " + gt.host + "."
+	}
+	if strings.Contains(err.Error(), "401") {
+		return "Authentication failed. Check GITEA_TOKEN."
+	}
+	return err.Error()
+}
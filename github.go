@@ -1,24 +1,27 @@
 package devflow
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
 )
 
 // GitHub handler for GitHub operations
 type GitHub struct {
-	log func(...any)
+	logger Logger
 }
 
 // NewGitHub creates handler and verifies gh CLI availability.
 // logFn is used to display authentication messages during Device Flow.
 // If not authenticated, it initiates OAuth Device Flow automatically.
 func NewGitHub(logFn func(...any), auth ...GitHubAuthenticator) (*GitHub, error) {
-	if logFn == nil {
-		logFn = func(...any) {}
-	}
 	gh := &GitHub{
-		log: logFn,
+		logger: FuncLogger(logFn),
+	}
+
+	if err := AirGapCheck(NetGitHub); err != nil {
+		return nil, fmt.Errorf("%w (set airgap.github_host for GitHub Enterprise)", err)
 	}
 
 	// Verify gh installation
@@ -34,7 +37,7 @@ func NewGitHub(logFn func(...any), auth ...GitHubAuthenticator) (*GitHub, error)
 	} else {
 		// Create default authenticator and set logger
 		authenticator = NewGitHubAuth()
-		authenticator.SetLog(gh.log)
+		authenticator.SetLog(gh.logger.Info)
 	}
 	if err := authenticator.EnsureGitHubAuth(); err != nil {
 		return nil, fmt.Errorf("github authentication failed: %w", err)
@@ -46,10 +49,45 @@ func NewGitHub(logFn func(...any), auth ...GitHubAuthenticator) (*GitHub, error)
 // SetLog sets the logger function
 func (gh *GitHub) SetLog(fn func(...any)) {
 	if fn != nil {
-		gh.log = fn
+		gh.SetLogger(FuncLogger(fn))
+	}
+}
+
+// SetLogger sets the leveled logger
+func (gh *GitHub) SetLogger(l Logger) {
+	if l != nil {
+		gh.logger = l
 	}
 }
 
+// Name returns the provider name
+func (gh *GitHub) Name() string {
+	return ProviderGitHub
+}
+
+// Host returns the GitHub host (GH_HOST for GitHub Enterprise)
+func (gh *GitHub) Host() string {
+	if host := os.Getenv("GH_HOST"); host != "" {
+		return host
+	}
+	return "github.com"
+}
+
+// RepoURL returns the HTTPS clone URL of owner/name
+func (gh *GitHub) RepoURL(owner, name string) string {
+	return fmt.Sprintf("https://%s/%s/%s.git", gh.Host(), owner, name)
+}
+
+// GitProtocol returns the protocol gh is set up to use on the host (gh
+// config get git_protocol): ssh, else https
+func (gh *GitHub) GitProtocol() string {
+	out, err := RunCommandSilent("gh", "config", "get", "git_protocol", "-h", gh.Host())
+	if err == nil && strings.TrimSpace(out) == ProtocolSSH {
+		return ProtocolSSH
+	}
+	return ProtocolHTTPS
+}
+
 // GetCurrentUser gets the current authenticated user
 func (gh *GitHub) GetCurrentUser() (string, error) {
 	output, err := RunCommandSilent("gh", "api", "user", "--jq", ".login")
@@ -59,34 +97,128 @@ func (gh *GitHub) GetCurrentUser() (string, error) {
 	return strings.TrimSpace(output), nil
 }
 
-// RepoExists checks if a repository exists
+// RepoExists checks if a repository exists. Only a not-found answer means
+// it does not; auth, network or rate-limit failures are errors.
 func (gh *GitHub) RepoExists(owner, name string) (bool, error) {
 	// gh repo view owner/name
-	_, err := RunCommandSilent("gh", "repo", "view", fmt.Sprintf("%s/%s", owner, name))
-	if err != nil {
+	out, err := RunCommandSilent("gh", "repo", "view", fmt.Sprintf("%s/%s", owner, name))
+	if err == nil {
+		return true, nil
+	}
+	if cliNotFound(out) {
 		return false, nil
 	}
-	return true, nil
+	return false, fmt.Errorf("gh repo view failed: %s", firstLine(out))
+}
+
+// cliNotFound reports whether the output of a failed gh or glab command
+// says the repository does not exist
+func cliNotFound(out string) bool {
+	out = strings.ToLower(out)
+	return strings.Contains(out, "could not resolve to a repository") || strings.Contains(out, "404 not found") || strings.Contains(out, "http 404")
+}
+
+// RepoIsEmpty reports whether owner/name has no commits
+func (gh *GitHub) RepoIsEmpty(owner, name string) (bool, error) {
+	out, err := RunCommandSilent("gh", "repo", "view", fmt.Sprintf("%s/%s", owner, name), "--json", "isEmpty", "-q", ".isEmpty")
+	if err != nil {
+		return false, fmt.Errorf("gh repo view failed: %s", firstLine(out))
+	}
+	return strings.TrimSpace(out) == "true", nil
+}
+
+// CheckOwner verifies that the authenticated user can create a repository
+// of visibility under owner: its own account, or an organization it is an
+// active member of. Members other than owners also need the organization
+// to allow members to create repositories of that visibility.
+func (gh *GitHub) CheckOwner(owner, visibility string) error {
+	user, err := gh.GetCurrentUser()
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(user, owner) {
+		return nil
+	}
+
+	out, err := RunCommandSilent("gh", "api", "user/memberships/orgs/"+owner)
+	if err != nil {
+		switch {
+		case strings.Contains(out, "HTTP 404"):
+			return fmt.Errorf("%s is not an organization %s is a member of", owner, user)
+		case strings.Contains(out, "HTTP 403"):
+			return fmt.Errorf("cannot read the membership of %s: %s (run 'gh auth refresh -s read:org')", owner, firstLine(out))
+		}
+		return fmt.Errorf("failed to check the membership of %s: %s", owner, firstLine(out))
+	}
+	var membership struct {
+		State string `json:"state"`
+		Role  string `json:"role"`
+	}
+	if err := json.Unmarshal([]byte(out), &membership); err != nil {
+		return fmt.Errorf("failed to parse the membership of %s: %w", owner, err)
+	}
+	if membership.State != "active" {
+		return fmt.Errorf("the invitation of %s to %s is %s; accept it first", user, owner, membership.State)
+	}
+	if membership.Role == "admin" {
+		return nil
+	}
+
+	field := "members_can_create_public_repositories"
+	if visibility == "private" {
+		field = "members_can_create_private_repositories"
+	}
+	out, err = RunCommandSilent("gh", "api", "orgs/"+owner, "--jq", "."+field)
+	if err != nil {
+		return fmt.Errorf("failed to read the settings of %s: %s", owner, firstLine(out))
+	}
+	if strings.TrimSpace(out) == "false" {
+		if visibility == "" {
+			visibility = "public"
+		}
+		return fmt.Errorf("members of %s cannot create %s repositories; ask an organization owner", owner, visibility)
+	}
+	return nil
 }
 
 // CreateRepo creates a new empty repository on GitHub
 // If owner is provided, creates repo under that organization
 func (gh *GitHub) CreateRepo(owner, name, description, visibility string) error {
+	return gh.CreateRemoteOnly(owner, name, description, visibility)
+}
+
+// CreateRemoteOnly creates an empty repository without touching any local
+// repository (no --source or --push; the caller adds origin and pushes)
+func (gh *GitHub) CreateRemoteOnly(owner, name, description, visibility string) error {
+	_, err := RunCommand("gh", repoCreateArgs(owner, name, description, visibility)...)
+	return err
+}
+
+// CreateFromLocal creates the repository from the local repository at
+// path: gh adds it as origin and pushes the current branch. Tags are not
+// pushed.
+func (gh *GitHub) CreateFromLocal(path, owner, name, description, visibility string) error {
+	args := append(repoCreateArgs(owner, name, description, visibility), "--source="+path, "--remote=origin", "--push")
+	out, err := RunCommand("gh", args...)
+	if err != nil {
+		return fmt.Errorf("gh repo create failed: %s", firstLine(out))
+	}
+	return nil
+}
+
+// repoCreateArgs returns the gh repo create arguments shared by both ways
+// of creating a repository
+func repoCreateArgs(owner, name, description, visibility string) []string {
 	repoName := name
 	if owner != "" {
 		repoName = fmt.Sprintf("%s/%s", owner, name)
 	}
-	// Create empty repo without --source or --push (will add remote and push manually)
-	args := []string{"repo", "create", repoName, "--description", description}
-
+	// --description=... keeps a description starting with "-" a value
+	args := []string{"repo", "create", repoName, "--description=" + SanitizeDescription(description)}
 	if visibility == "private" {
-		args = append(args, "--private")
-	} else {
-		args = append(args, "--public")
+		return append(args, "--private")
 	}
-
-	_, err := RunCommand("gh", args...)
-	return err
+	return append(args, "--public")
 }
 
 // DeleteRepo deletes a repository on GitHub.
@@ -99,16 +231,52 @@ func (gh *GitHub) DeleteRepo(owner, name string) error {
 	return err
 }
 
+// sshKeysEndpoint returns the API path of the account's authentication
+// or signing keys
+func sshKeysEndpoint(signing bool) string {
+	if signing {
+		return "user/ssh_signing_keys"
+	}
+	return "user/keys"
+}
+
+// sshKeyScopeHint explains the extra token scopes the key endpoints need
+func sshKeyScopeHint(out string) string {
+	if strings.Contains(out, "HTTP 403") || strings.Contains(out, "HTTP 404") || strings.Contains(out, "scope") {
+		return " (run 'gh auth refresh -s admin:public_key,admin:ssh_signing_key')"
+	}
+	return ""
+}
+
+// SSHKeys lists the public authentication keys (or signing keys) of the
+// current user
+func (gh *GitHub) SSHKeys(signing bool) ([]string, error) {
+	out, err := RunCommandSilent("gh", "api", "--paginate", sshKeysEndpoint(signing), "--jq", ".[].key")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list SSH keys: %s%s", firstLine(out), sshKeyScopeHint(out))
+	}
+	var keys []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			keys = append(keys, line)
+		}
+	}
+	return keys, nil
+}
+
+// AddSSHKey uploads a public key to the current user's authentication
+// keys, or to the signing keys that mark commits as verified
+func (gh *GitHub) AddSSHKey(title, publicKey string, signing bool) error {
+	out, err := RunCommand("gh", "api", "-X", "POST", sshKeysEndpoint(signing), "-f", "title="+title, "-f", "key="+publicKey)
+	if err != nil {
+		return fmt.Errorf("failed to add SSH key: %s%s", firstLine(out), sshKeyScopeHint(out))
+	}
+	return nil
+}
+
 // IsNetworkError checks if an error is likely a network error
 func (gh *GitHub) IsNetworkError(err error) bool {
-	if err == nil {
-		return false
-	}
-	msg := err.Error()
-	return strings.Contains(msg, "dial tcp") ||
-		strings.Contains(msg, "connection refused") ||
-		strings.Contains(msg, "no such host") ||
-		strings.Contains(msg, "timeout")
+	return isNetworkError(err)
 }
 
 // GetHelpfulErrorMessage returns a helpful message for common errors
@@ -1,7 +1,6 @@
 package devflow
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -71,6 +70,14 @@ type tokenResponse struct {
 
 // EnsureGitHubAuth checks if GitHub is authenticated via keyring, and if not, initiates Device Flow
 func (a *GitHubAuth) EnsureGitHubAuth() error {
+	// Air-gapped: Device Flow talks to github.com, rely on existing gh auth
+	if err := AirGapCheck(NetOAuth); err != nil {
+		if _, statusErr := RunCommandSilent("gh", "auth", "status"); statusErr != nil {
+			return fmt.Errorf("gh not authenticated and %w, run 'gh auth login --hostname <host>'", err)
+		}
+		return nil
+	}
+
 	// Initialize keyring (auto-installs if needed)
 	kr, err := NewKeyring()
 	if err != nil {
@@ -236,25 +243,25 @@ func (a *GitHubAuth) pollForToken(deviceCode string, interval, expiresIn int) (s
 
 // openBrowser opens a URL in the default browser (cross-platform)
 func (a *GitHubAuth) openBrowser(url string) error {
-	var cmd *exec.Cmd
+	cmd := &Command{Args: []string{url}, start: (*exec.Cmd).Start}
 
 	switch runtime.GOOS {
 	case "linux":
-		cmd = exec.Command("xdg-open", url)
+		cmd.Name = "xdg-open"
 	case "darwin":
-		cmd = exec.Command("open", url)
+		cmd.Name = "open"
 	case "windows":
-		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+		cmd.Name, cmd.Args = "rundll32", []string{"url.dll,FileProtocolHandler", url}
 	default:
 		return fmt.Errorf("unsupported platform: %s", runtime.GOOS)
 	}
 
-	return cmd.Start()
+	_, err := runCommand(cmd)
+	return err
 }
 
 // configureGhWithToken configures gh CLI to use the token
 func (a *GitHubAuth) configureGhWithToken(token string) error {
-	cmd := exec.Command("gh", "auth", "login", "--with-token")
-	cmd.Stdin = bytes.NewReader([]byte(token))
-	return cmd.Run()
+	_, err := runCommand(&Command{Name: "gh", Args: []string{"auth", "login", "--with-token"}, Stdin: strings.NewReader(token)})
+	return err
 }
@@ -0,0 +1,245 @@
+package devflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// GitLab creates repositories on gitlab.com or a self-hosted instance. It
+// uses the glab CLI when installed, otherwise the REST API with GITLAB_TOKEN.
+type GitLab struct {
+	host string
+	rest *restClient // nil when using glab
+	log  func(...any)
+}
+
+// NewGitLab creates the GitLab handler for host and verifies access
+func NewGitLab(host string, logFn func(...any)) (*GitLab, error) {
+	if logFn == nil {
+		logFn = func(...any) {}
+	}
+	if host == "" {
+		host = "gitlab.com"
+	}
+	if err := providerHostCheck("GitLab", host); err != nil {
+		return nil, err
+	}
+	gl := &GitLab{host: host, log: logFn}
+
+	if token := os.Getenv("GITLAB_TOKEN"); token != "" {
+		gl.rest = newRESTClient("https://"+host+"/api/v4", "PRIVATE-TOKEN", token)
+		return gl, nil
+	}
+	if _, err := RunCommandSilent("glab", "--version"); err != nil {
+		return nil, fmt.Errorf("gitlab: install glab or set GITLAB_TOKEN: %w", err)
+	}
+	if _, err := gl.glab("auth", "status", "--hostname", host); err != nil {
+		return nil, fmt.Errorf("gitlab authentication failed, run 'glab auth login --hostname %s': %w", host, err)
+	}
+	return gl, nil
+}
+
+// glab runs the glab CLI against the configured host
+func (gl *GitLab) glab(args ...string) (string, error) {
+	return RunCommandWithEnvInDir("", []string{"GITLAB_HOST=" + gl.host}, "glab", args...)
+}
+
+// SetLog sets the logger function
+func (gl *GitLab) SetLog(fn func(...any)) {
+	if fn != nil {
+		gl.log = fn
+	}
+}
+
+// Name returns the provider name
+func (gl *GitLab) Name() string {
+	return ProviderGitLab
+}
+
+// Host returns the GitLab host
+func (gl *GitLab) Host() string {
+	return gl.host
+}
+
+// RepoURL returns the HTTPS clone URL of owner/name
+func (gl *GitLab) RepoURL(owner, name string) string {
+	return fmt.Sprintf("https://%s/%s/%s.git", gl.host, owner, name)
+}
+
+// GetCurrentUser gets the username of the authenticated user
+func (gl *GitLab) GetCurrentUser() (string, error) {
+	var user struct {
+		Username string `json:"username"`
+	}
+	if gl.rest != nil {
+		if _, err := gl.rest.do("GET", "/user", nil, &user); err != nil {
+			return "", fmt.Errorf("failed to get current user: %w", err)
+		}
+	} else {
+		out, err := gl.glab("api", "user")
+		if err != nil {
+			return "", fmt.Errorf("failed to get current user: %w", err)
+		}
+		if err := json.Unmarshal([]byte(out), &user); err != nil {
+			return "", fmt.Errorf("failed to get current user: %w", err)
+		}
+	}
+	return user.Username, nil
+}
+
+// RepoExists checks if owner/name exists
+func (gl *GitLab) RepoExists(owner, name string) (bool, error) {
+	path := owner + "/" + name
+	if gl.rest == nil {
+		out, err := gl.glab("repo", "view", path)
+		if err == nil {
+			return true, nil
+		}
+		if cliNotFound(out) {
+			return false, nil
+		}
+		return false, fmt.Errorf("glab repo view failed: %s", firstLine(out))
+	}
+	status, err := gl.rest.do("GET", "/projects/"+url.PathEscape(path), nil, nil)
+	if status == 404 {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// RepoIsEmpty reports whether the project owner/name has no commits
+func (gl *GitLab) RepoIsEmpty(owner, name string) (bool, error) {
+	path := url.PathEscape(owner + "/" + name)
+	var project struct {
+		EmptyRepo bool `json:"empty_repo"`
+	}
+	if gl.rest == nil {
+		out, err := gl.glab("api", "projects/"+path)
+		if err != nil {
+			return false, fmt.Errorf("glab api failed: %s", firstLine(out))
+		}
+		if err := json.Unmarshal([]byte(out), &project); err != nil {
+			return false, err
+		}
+		return project.EmptyRepo, nil
+	}
+	_, err := gl.rest.do("GET", "/projects/"+path, nil, &project)
+	return project.EmptyRepo, err
+}
+
+// CreateRepo creates an empty project. owner may be the user or a group.
+func (gl *GitLab) CreateRepo(owner, name, description, visibility string) error {
+	if visibility != "private" {
+		visibility = "public"
+	}
+	if gl.rest == nil {
+		path := name
+		if owner != "" {
+			path = owner + "/" + name
+		}
+		_, err := gl.glab("repo", "create", path, "--description="+SanitizeDescription(description), "--"+visibility)
+		return err
+	}
+
+	body := map[string]any{"name": name, "path": name, "description": SanitizeDescription(description), "visibility": visibility}
+	if owner != "" {
+		if user, err := gl.GetCurrentUser(); err == nil && user != owner {
+			var ns struct {
+				ID int `json:"id"`
+			}
+			if _, err := gl.rest.do("GET", "/namespaces/"+url.PathEscape(owner), nil, &ns); err != nil {
+				return fmt.Errorf("gitlab namespace %s: %w", owner, err)
+			}
+			body["namespace_id"] = ns.ID
+		}
+	}
+	_, err := gl.rest.do("POST", "/projects", body, nil)
+	return err
+}
+
+// DeleteRepo deletes owner/name.
+// WARNING: This permanently deletes the project and cannot be undone.
+func (gl *GitLab) DeleteRepo(owner, name string) error {
+	path := owner + "/" + name
+	if gl.rest == nil {
+		_, err := gl.glab("repo", "delete", path, "--yes")
+		return err
+	}
+	_, err := gl.rest.do("DELETE", "/projects/"+url.PathEscape(path), nil, nil)
+	return err
+}
+
+// ProjectReleaser returns the Releaser of the GitLab project path
+func (gl *GitLab) ProjectReleaser(project string) Releaser {
+	return &gitLabReleaser{gl: gl, project: project}
+}
+
+// gitLabReleaser creates releases on one GitLab project
+type gitLabReleaser struct {
+	gl      *GitLab
+	project string
+}
+
+// CreateRelease creates the release of an existing tag with notes as its
+// description; assets are uploaded to the project and linked
+func (r *gitLabReleaser) CreateRelease(tag, notes string, assets []string) (string, error) {
+	if notes == "" {
+		notes = "Release " + tag
+	}
+	if r.gl.rest == nil {
+		args := append([]string{"release", "create", tag, "--repo", r.project, "--name", tag, "--notes", notes}, assets...)
+		out, err := r.gl.glab(args...)
+		if err != nil {
+			return "", fmt.Errorf("glab release create %s failed: %s", tag, firstLine(out))
+		}
+		return strings.TrimSpace(out), nil
+	}
+
+	id := "/projects/" + url.PathEscape(r.project)
+	var links []map[string]string
+	for _, asset := range assets {
+		var upload struct {
+			URL string `json:"url"`
+		}
+		if _, err := r.gl.rest.upload(id+"/uploads", asset, &upload); err != nil {
+			return "", err
+		}
+		links = append(links, map[string]string{"name": filepath.Base(asset), "url": "https://" + r.gl.host + "/" + r.project + upload.URL})
+	}
+	body := map[string]any{"tag_name": tag, "name": tag, "description": notes}
+	if len(links) > 0 {
+		body["assets"] = map[string]any{"links": links}
+	}
+	var release struct {
+		Links struct {
+			Self string `json:"self"`
+		} `json:"_links"`
+	}
+	if _, err := r.gl.rest.do("POST", id+"/releases", body, &release); err != nil {
+		return "", err
+	}
+	return release.Links.Self, nil
+}
+
+// IsNetworkError checks if an error is likely a network error
+func (gl *GitLab) IsNetworkError(err error) bool {
+	return isNetworkError(err)
+}
+
+// GetHelpfulErrorMessage returns a helpful message for common errors
+func (gl *GitLab) GetHelpfulErrorMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	if gl.IsNetworkError(err) {
+		return "Network error. Check your connection to " + gl.host + "."
+	}
+	if strings.Contains(err.Error(), "401") || strings.Contains(err.Error(), "authentication") {
+		return "Authentication failed. Run 'glab auth login --hostname " + gl.host + "' or set GITLAB_TOKEN."
+	}
+	return err.Error()
+}
@@ -2,39 +2,93 @@ package devflow
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strings"
+	"sync"
 	"time"
 )
 
 // Go handler for Go operations
 type Go struct {
-	rootDir       string
-	git           GitClient // Interface for better testing
-	log           func(...any)
-	backup        *DevBackup
-	retryDelay    time.Duration
-	retryAttempts int
+	rootDir        string
+	git            GitClient // Interface for better testing
+	logger         Logger
+	backup         *DevBackup
+	retryDelay     time.Duration
+	retryAttempts  int
+	depVerify      string // Dependency verification mode (DepVerifyWarn/DepVerifyFail)
+	config         *Config
+	module         *moduleLoad // Module of rootDir for the current run, see Module
+	moduleMu       sync.Mutex
+	netNotes       []string    // proxy/sumdb fallback messages for the summary
+	netNotesMu     sync.Mutex  // Dependents are updated concurrently
+	vulns          *VulnReport // Last govulncheck result, reused by Push
+	noRelease      bool        // Push creates no GitHub release
+	releaser       Releaser
+	mirrorReleaser Releaser
+	pullRequester  PullRequester // Opens the pull requests of PushPR (default: a GitHub client)
+	dryRun         bool
+	hookFuncs      map[string]HookFunc // Push hook steps registered with RegisterHook
+	warnings       Warnings            // Non-fatal problems of the last Test or Push
 }
 
-// GoVersion reads the Go version from the go.mod file in the current directory.
-// It returns the version string (e.g., "1.18") or an empty string if not found.
+// GoVersion returns the Go version of the module's go.mod (e.g., "1.18").
+// It returns an empty string if not found.
 func (g *Go) GoVersion() (string, error) {
-	data, err := os.ReadFile("go.mod")
+	m, err := g.Module()
 	if err != nil {
 		return "", err
 	}
+	major, minor, _ := strings.Cut(m.GoVersion, ".")
+	minor, _, _ = strings.Cut(minor, ".")
+	if minor == "" {
+		return major, nil
+	}
+	return major + "." + minor, nil
+}
 
-	re := regexp.MustCompile(`^go\s+(\d+\.\d+)`)
-	matches := re.FindStringSubmatch(string(data))
-	if len(matches) > 1 {
-		return matches[1], nil
+// moduleLoad reads the module of a run once, whichever step asks first
+type moduleLoad struct {
+	once sync.Once
+	info *ModuleInfo
+	err  error
+}
+
+// Module returns the module of rootDir (the nearest go.mod in it or a
+// parent), read on first use and reused by every step of Test and Push.
+// Each run, and SetRootDir, reads go.mod again.
+func (g *Go) Module() (*ModuleInfo, error) {
+	g.moduleMu.Lock()
+	if g.module == nil {
+		g.module = &moduleLoad{}
 	}
+	load, rootDir := g.module, g.rootDir
+	g.moduleMu.Unlock()
+	load.once.Do(func() { load.info, load.err = FindModule(rootDir) })
+	return load.info, load.err
+}
 
-	return "", nil
+// refreshModule makes the next Module call read go.mod again
+func (g *Go) refreshModule() {
+	g.moduleMu.Lock()
+	g.module = nil
+	g.moduleMu.Unlock()
+}
+
+// beginRun starts a Test, Push or PushPR run like Warnings.begin; the
+// outermost one also refreshes the module, so a long-lived Go sees go.mod
+// changes between runs
+func (g *Go) beginRun() func() {
+	g.warnings.mu.Lock()
+	outermost := g.warnings.depth == 0
+	g.warnings.mu.Unlock()
+	if outermost {
+		g.refreshModule()
+	}
+	return g.warnings.begin()
 }
 
 // NewGo creates a new Go handler and verifies Go installation
@@ -48,7 +102,7 @@ func NewGo(gitHandler GitClient) (*Go, error) {
 		rootDir:       ".",
 		git:           gitHandler,
 		backup:        NewDevBackup(),
-		log:           func(...any) {}, // default no-op
+		logger:        NopLogger(),
 		retryDelay:    5 * time.Second,
 		retryAttempts: 3,
 	}, nil
@@ -60,22 +114,78 @@ func (g *Go) SetRetryConfig(delay time.Duration, attempts int) {
 	g.retryAttempts = attempts
 }
 
+// SetConfig sets the project config (default: loaded from rootDir on first use)
+func (g *Go) SetConfig(c *Config) {
+	g.config = c
+}
+
+// Config returns the project config, loading it from rootDir if needed.
+// Load errors are logged and yield an empty config.
+func (g *Go) Config() *Config {
+	if g.config == nil {
+		c, err := LoadConfig(g.rootDir)
+		if err != nil {
+			g.logger.Warn("invalid config:", err)
+			c = NewConfig()
+		}
+		g.config = c
+	}
+	return g.config
+}
+
 // SetRootDir sets the root directory for Go operations
 func (g *Go) SetRootDir(path string) {
+	g.moduleMu.Lock()
 	g.rootDir = path
+	g.module = nil
+	g.moduleMu.Unlock()
+}
+
+// SetDryRun makes Push print the commands and file updates of the whole
+// workflow (tests, git, dependents, backup) instead of running them
+func (g *Go) SetDryRun(enabled bool) {
+	g.dryRun = enabled
 }
 
-// SetLog sets the logger function
+// SetLog sets the logger function of the handler, its git client and
+// backup. Use SetLogger for levels or JSON output.
 func (g *Go) SetLog(fn func(...any)) {
 	if fn != nil {
-		g.log = fn
-		if g.git != nil {
-			g.git.SetLog(fn)
-		}
-		if g.backup != nil {
-			g.backup.SetLog(fn)
-		}
+		g.SetLogger(FuncLogger(fn))
+	}
+}
+
+// SetLogger sets the leveled logger of the handler, its git client and
+// backup
+func (g *Go) SetLogger(l Logger) {
+	if l == nil {
+		return
+	}
+	g.logger = l
+	if g.git != nil {
+		setLogger(g.git, l)
+	}
+	if g.backup != nil {
+		g.backup.SetLogger(l)
+	}
+}
+
+// Warnings returns the non-fatal problems of the last TestWithOptions or
+// Push, e.g. a check that was skipped, badges or caches that were not
+// updated or dependents left behind. The commands print them after the
+// summary (see WriteWarnings).
+func (g *Go) Warnings() []Warning {
+	return g.warnings.List()
+}
+
+// warn records err as a warning of step; it is only logged at debug
+// level, the commands print the warnings at the end
+func (g *Go) warn(step string, err error) {
+	if err == nil {
+		return
 	}
+	g.logger.Debug("Warning:", step+":", err)
+	g.warnings.Add(step, err)
 }
 
 // Push executes the complete workflow for Go projects
@@ -88,71 +198,111 @@ func (g *Go) SetLog(fn func(...any)) {
 //	skipDependents: If true, skips updating dependent modules
 //	skipBackup: If true, skips backup
 //	searchPath: Path to search for dependent modules (default: "..")
-func (g *Go) Push(message, tag string, skipTests, skipRace, skipDependents, skipBackup bool, searchPath string) (string, error) {
+func (g *Go) Push(message, tag string, skipTests, skipRace, skipDependents, skipBackup bool, searchPath string) (_ string, err error) {
 	// Validate message
 	if err := ValidateCommitMessage(message); err != nil {
 		return "", err
 	}
 	message = FormatCommitMessage(message)
+	if g.dryRun {
+		defer startDryRun()()
+	}
+	defer trackMetric("gopush")(&err)
+	defer traceSpan("gopush")(&err)
+	defer g.beginRun()()
 
 	if searchPath == "" {
 		searchPath = ".."
 	}
 
+	// 0. With StashUncommitted, the tests check only what is committed
 	summary := []string{}
-
-	// 1. Verify go.mod
-	if err := g.verify(); err != nil {
-		return "", fmt.Errorf("go mod verify failed: %w", err)
-	}
-
-	// 2. Run tests (if not skipped)
-	if !skipTests {
-		testSummary, err := g.Test()
+	restoreStash := func() error { return nil }
+	if stasher, ok := g.git.(PushStasher); ok {
+		stashSummary, restore, err := stasher.StashForPush()
 		if err != nil {
-			return "", fmt.Errorf("tests failed: %w", err)
+			return "", err
+		}
+		if restore != nil {
+			summary = append(summary, stashSummary)
+			restoreStash = sync.OnceValue(restore)
+			defer func() {
+				err = errors.Join(err, restoreStash())
+			}()
 		}
-		summary = append(summary, testSummary)
-	} else {
-		summary = append(summary, "Tests skipped")
 	}
 
-	// 3. Execute git push workflow
+	// 1-2. Verify, test and refresh the notices
+	hookCtx := HookContext{Message: message}
+	checkSummary, err := g.checkBeforePush(hookCtx, skipTests, skipRace)
+	if err != nil {
+		return "", err
+	}
+	summary = append(summary, checkSummary...)
+
+	// 3. Execute git push workflow, with the before_commit and
+	// before_push hooks run by the git client
+	tracePhase("push")
+	previousTag, _ := g.git.GetLatestTag()
+	if h, ok := g.git.(PushHookSetter); ok {
+		h.SetPushHook(func(stage string) error { return g.runPushHooks(stage, hookCtx) })
+		defer h.SetPushHook(nil)
+	}
 	pushSummary, err := g.git.Push(message, tag)
 	if err != nil {
 		return "", fmt.Errorf("push workflow failed: %w", err)
 	}
 	summary = append(summary, pushSummary)
+	// The stashed files are back before the hooks and dependents; a restore
+	// failure is returned at the end
+	restoreStash()
 
 	// 4. Get created tag
 	latestTag, err := g.git.GetLatestTag()
-	if err != nil {
-		summary = append(summary, fmt.Sprintf("Warning: could not get latest tag: %v", err))
-		// Not fatal error
+	g.warn("could not get latest tag", err)
+	if tag := dryRunTag(); tag != "" && DryRunActive() {
+		latestTag = tag
+	}
+
+	// 4b. hooks.after_tag, then the GitHub release of the new tag
+	if latestTag != "" && latestTag != previousTag {
+		tracePhase("release")
+		hookCtx.Tag = latestTag
+		// The tag is out: a failing step must not keep the dependents behind
+		g.warn(latestTag+" is pushed but a hook failed", g.runPushHooks(HookAfterTag, hookCtx))
+		if release := g.createRelease(latestTag); release != "" {
+			summary = append(summary, release)
+		}
 	}
 
 	// 5. Get module name
 	modulePath, err := g.getModulePath()
 	if err != nil {
-		summary = append(summary, fmt.Sprintf("Warning: could not get module path: %v", err))
+		g.warn("dependents not updated: could not get module path", err)
 		return strings.Join(summary, ", "), nil
 	}
 
 	// 6. Update dependent modules
 	if !skipDependents {
-		updateResults, err := g.updateDependents(modulePath, latestTag, searchPath)
-		if err != nil {
-			summary = append(summary, fmt.Sprintf("Warning: failed to scan dependents: %v", err))
-		}
-		if len(updateResults) > 0 {
-			summary = append(summary, updateResults...)
+		tracePhase("dependents")
+		defer OnInterrupt(func() {
+			fmt.Fprintf(os.Stderr, "⚠️ %s %s is pushed but not every dependent was updated. In each one run: go get %s@%s && go mod tidy\n", modulePath, latestTag, modulePath, latestTag)
+		})()
+		report, err := g.updateDependents(modulePath, latestTag, searchPath)
+		g.warn("failed to scan dependents", err)
+		for _, u := range report.Failed() {
+			g.warn("dependent "+filepath.Base(u.Dir)+" not updated to "+latestTag, u.Err)
 		}
+		summary = append(summary, report.Lines()...)
 	}
 
-	// 7. Execute backup (asynchronous, non-blocking)
+	// Proxy/sumdb fallbacks used during the run
+	summary = append(summary, g.netNotes...)
+
+	// 7. Execute backup (asynchronous, except the git mode that commits and pushes in place)
 	if !skipBackup {
 		if backupMsg, err := g.backup.Run(); err != nil {
-			summary = append(summary, fmt.Sprintf("❌ backup failed to start: %v", err))
+			g.warn("backup failed", err)
 		} else if backupMsg != "" {
 			summary = append(summary, backupMsg)
 		}
@@ -161,110 +311,210 @@ func (g *Go) Push(message, tag string, skipTests, skipRace, skipDependents, skip
 	return strings.Join(summary, ", "), nil
 }
 
-// UpdateDependentModule updates a dependent module and optionally pushes it
-// This is called for each module that depends on the one we just published
-// UpdateDependentModule updates a specific dependent module
-// It modifies go.mod to require the new version and runs go mod tidy
-func (g *Go) UpdateDependentModule(depDir, modulePath, version string) (string, error) {
-	depName := filepath.Base(depDir)
-	fmt.Printf("📦 Processing dependent: %s\n", depName)
+// checkBeforePush runs the steps of a push before anything is committed:
+// go mod verify, the dependency signatures, the before_test hooks, the
+// tests, the vulnerability check and the third-party notices. It returns
+// their summary entries.
+func (g *Go) checkBeforePush(hookCtx HookContext, skipTests, skipRace bool) ([]string, error) {
+	summary := []string{}
 
-	// 1-2. Load and modify go.mod
-	// Since NewGoModFile reads from disk, we pass full path
-	// 1-2. Load and modify go.mod
-	// Since NewGoModFile reads from disk, we pass full path
-	modFile := filepath.Join(depDir, "go.mod")
-	gomod := NewGoModHandler()
-	gomod.SetRootDir(depDir)
-	// No error check needed for creation, but methods will fail if file missing
-
-	// Check/Load explicitly if we want to fail fast?
-	// But current flow relied on NewGoModHandler returning error.
-	// Since we moved loading to methods, we can't fail fast here unless we call something.
-	// But RemoveReplace returns bool.
-	// Save() returns error if write fails.
-	// Maybe we should just proceed.
-	// The original code returned error if file not found.
-	// Let's verify file existence manually?
-	if _, err := os.Stat(modFile); err != nil {
-		return "", fmt.Errorf("failed to load go.mod: %w", err)
+	// 1. Verify go.mod
+	tracePhase("verify")
+	if err := g.verify(); err != nil {
+		return nil, fmt.Errorf("go mod verify failed: %w", err)
 	}
 
-	gomod.RemoveReplace(modulePath)
+	// 1b. Supply chain: verify dependency tag signatures (optional)
+	if g.depVerify != DepVerifyOff {
+		depSummary, err := g.VerifyDependencies(g.depVerify)
+		if err != nil {
+			return nil, fmt.Errorf("dependency verification failed: %w", err)
+		}
+		if depSummary != "" {
+			summary = append(summary, depSummary)
+		}
+	}
 
-	// 3. Save changes (GoModFile saves to its absolute path)
-	if err := gomod.Save(); err != nil {
-		return "", fmt.Errorf("failed to save go.mod: %w", err)
+	// 1c. User-defined steps (hooks.before_test)
+	if err := g.runPushHooks(HookBeforeTest, hookCtx); err != nil {
+		return nil, err
 	}
 
-	// 4. Smart Update Logic
-	currentVer, err := g.GetCurrentVersion(depDir, modulePath)
-	if err == nil {
-		if CompareVersions(currentVer, version) >= 0 {
-			return fmt.Sprintf("already up-to-date (%s)", currentVer), nil
+	// 2. Run tests (if not skipped)
+	tracePhase("test")
+	if !skipTests && DryRunActive() {
+		if skipRace {
+			dryRunf(g.rootDir, "gotest -race=off")
+		} else {
+			dryRunf(g.rootDir, "gotest")
 		}
+		summary = append(summary, "Tests not run (dry run)")
+	} else if !skipTests {
+		opts := TestOptions{}
+		if skipRace {
+			opts.Race = RaceOff
+		}
+		testSummary, err := g.TestWithOptions(opts)
+		if err != nil {
+			return nil, fmt.Errorf("tests failed: %w", err)
+		}
+		summary = append(summary, testSummary)
+	} else {
+		summary = append(summary, "Tests skipped")
 	}
 
-	// 4.1 Run go get WITHOUT -u using explicit directory context
-	target := fmt.Sprintf("%s@%s", modulePath, version)
+	// 2a. Known vulnerabilities (vuln.block)
+	tracePhase("vuln")
+	if DryRunActive() {
+		if settings, err := LoadVulnSettings(g.Config()); err == nil && settings.Block != VulnBlockOff {
+			dryRunf(g.rootDir, "govulncheck ./...")
+		}
+	} else if err := g.checkPushVulns(); err != nil {
+		return nil, err
+	}
 
-	// Note: We use RunCommandWithRetryInDir here
-	if _, err := RunCommandWithRetryInDir(depDir, "go", []string{"get", target}, g.retryAttempts, g.retryDelay); err != nil {
-		return "", fmt.Errorf("go get failed after retries: %w", err)
+	// 2b. Refresh third-party notices so they ship with the release commit
+	noticesSummary, err := g.UpdateNotices()
+	if err != nil {
+		g.warn("notices not updated", err)
+	} else if noticesSummary != "" {
+		summary = append(summary, noticesSummary)
 	}
+	return summary, nil
+}
 
-	// 5. Run go mod tidy in the specific directory
-	// Note: GoModFile.RunTidy() uses a separate exec.Command with Dir set, but to be consistent
-	// and ensure we don't rely on side-effects, we can call it directly or ensure RunTidy works safely.
-	// Looking at GoModFile.RunTidy, it uses cmd.Dir = filepath.Dir(m.path), so it IS SAFE.
-	// However, let's explicitely use our safe helper if we prefer, OR trust RunTidy.
-	// The original code called gomod.RunTidy(). Let's stick to using RunCommandInDir for explicit control if gomod.RunTidy logic is hidden.
-	// But gomod.RunTidy IS safe (it takes absolute path from constructor).
-	// Let's use RunCommandInDir for consistency with 'go get' above to be 100% sure we control the execution.
-	if _, err := RunCommandInDir(depDir, "go", "mod", "tidy"); err != nil {
-		return "", fmt.Errorf("go mod tidy failed: %w", err)
+// UpdateDependentModule updates a dependent module and pushes it: its
+// go.mod requires the new version (go get, go mod tidy), then the change
+// is pushed with "deps: update <module> to <version>". The push changes
+// the working directory of the process until it returns, so calls must not
+// overlap with each other or with other work that depends on it.
+func (g *Go) UpdateDependentModule(depDir, modulePath, version string) (string, error) {
+	abs, err := filepath.Abs(depDir)
+	if err != nil {
+		return "", err
+	}
+	u, push := g.prepareDependent(abs, modulePath, version)
+	if push {
+		u = g.pushDependent(u, modulePath, version)
+	}
+	return u.Detail, u.Err
+}
+
+// prepareDependent makes the dependent in depDir (an absolute path, as
+// the push of another dependent changes the working directory) require
+// version and classifies the outcome. push reports whether the change is
+// left to pushDependent.
+func (g *Go) prepareDependent(depDir, modulePath, version string) (u DependentUpdate, push bool) {
+	u = DependentUpdate{Dir: depDir}
+	fmt.Fprintf(OutputWriter(os.Stdout), "📦 Processing dependent: %s\n", filepath.Base(depDir))
+
+	if DryRunActive() {
+		dryRunf(depDir, "update go.mod: drop replace %s", modulePath)
+		dryRunf(depDir, "go get %s@%s", modulePath, version)
+		dryRunf(depDir, "go mod tidy")
+		dryRunf(depDir, "push \"deps: update %s to %s\"", filepath.Base(modulePath), version)
+		u.Status, u.Detail = DependentUpdated, fmt.Sprintf("would update to %s", version)
+		return u, false
+	}
+
+	gomod, upToDate, err := g.requireVersion(depDir, modulePath, version)
+	if err != nil {
+		u.Status, u.Err = DependentFailed, err
+		return u, false
+	}
+	if upToDate != "" {
+		u.Status, u.Detail = DependentUpToDate, fmt.Sprintf("already up-to-date (%s)", upToDate)
+		return u, false
 	}
 
-	// 6. Check for other replaces
+	// Check for other replaces
 	if gomod.HasOtherReplaces(modulePath) {
-		return "updated (other replaces exist, manual push required)", nil
+		u.Status, u.Detail = DependentManual, "updated (other replaces exist, manual push required)"
+		return u, false
 	}
+	return u, true
+}
 
-	// 7. Push the dependent module
-	// Save current directory and change to depDir for the push
+// pushDependent pushes the go.mod update of a prepared dependent. The push
+// runs in the dependent's directory, so it changes the working directory
+// of the process until it returns: pushes run one at a time, after the
+// go.mod updates of updateDependents.
+func (g *Go) pushDependent(u DependentUpdate, modulePath, version string) DependentUpdate {
+	depDir := u.Dir
+	fail := func(err error) DependentUpdate {
+		u.Status, u.Err = DependentFailed, err
+		return u
+	}
 	originalDir, err := os.Getwd()
 	if err != nil {
-		return "", fmt.Errorf("failed to get current directory: %w", err)
+		return fail(fmt.Errorf("failed to get current directory: %w", err))
 	}
-
 	if err := os.Chdir(depDir); err != nil {
-		return "", fmt.Errorf("failed to change to dependent directory: %w", err)
+		return fail(fmt.Errorf("failed to change to dependent directory: %w", err))
 	}
-
-	// Ensure we restore the original directory
-	defer func() {
-		os.Chdir(originalDir)
-	}()
+	defer os.Chdir(originalDir)
 
 	// Create new handlers for the dependent directory
 	git, err := NewGit()
 	if err != nil {
-		return "", fmt.Errorf("git init failed: %w", err)
+		return fail(fmt.Errorf("git init failed: %w", err))
 	}
-
 	depHandler, err := NewGo(git)
 	if err != nil {
-		return "", fmt.Errorf("go handler init failed: %w", err)
+		return fail(fmt.Errorf("go handler init failed: %w", err))
 	}
 
 	// Push with skipDependents=true and skipBackup=true to avoid infinite recursion
 	commitMsg := fmt.Sprintf("deps: update %s to %s", filepath.Base(modulePath), version)
-	_, err = depHandler.Push(commitMsg, "", true, true, true, true, "")
-	if err != nil {
-		return "", fmt.Errorf("push failed: %w", err)
+	if _, err := depHandler.Push(commitMsg, "", true, true, true, true, ""); err != nil {
+		return fail(fmt.Errorf("push failed: %w", err))
+	}
+
+	u.Status, u.Detail = DependentUpdated, fmt.Sprintf("updated to %s", version)
+	return u
+}
+
+// requireVersion makes the module in depDir require modulePath at
+// version: drops its replace, then go get and go mod tidy. upToDate is the
+// current version when it is already version or newer.
+func (g *Go) requireVersion(depDir, modulePath, version string) (gomod *GoModHandler, upToDate string, err error) {
+	// 1-2. Load and modify go.mod (the handler reads it from depDir)
+	modFile := filepath.Join(depDir, "go.mod")
+	gomod = NewGoModHandler()
+	gomod.SetRootDir(depDir)
+	if _, err := os.Stat(modFile); err != nil {
+		return nil, "", fmt.Errorf("failed to load go.mod: %w", err)
+	}
+
+	gomod.RemoveReplace(modulePath)
+
+	// 3. Save changes (GoModFile saves to its absolute path)
+	if err := gomod.Save(); err != nil {
+		return nil, "", fmt.Errorf("failed to save go.mod: %w", err)
+	}
+
+	// 4. Smart Update Logic
+	currentVer, err := g.GetCurrentVersion(depDir, modulePath)
+	if err == nil {
+		if CompareVersions(currentVer, version) >= 0 {
+			return gomod, currentVer, nil
+		}
+	}
+
+	// 4.1 Run go get WITHOUT -u using explicit directory context
+	target := fmt.Sprintf("%s@%s", modulePath, version)
+
+	// Note: runGoNet retries and handles proxy/sumdb outages
+	if _, err := g.runGoNet(depDir, []string{"get", target}, g.retryAttempts); err != nil {
+		return nil, "", fmt.Errorf("go get failed after retries: %w", err)
+	}
+
+	// 5. Run go mod tidy in the specific directory
+	if _, err := RunCommandInDir(depDir, "go", "mod", "tidy"); err != nil {
+		return nil, "", fmt.Errorf("go mod tidy failed: %w", err)
 	}
 
-	return fmt.Sprintf("updated to %s", version), nil
+	return gomod, "", nil
 }
 
 // GetCurrentVersion returns the current version of a dependency in a module
@@ -1,12 +1,14 @@
 package devflow
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -221,10 +223,7 @@ func (m *GoModHandler) Save() error {
 
 // RunTidy executes 'go mod tidy' in the directory of the go.mod file
 func (m *GoModHandler) RunTidy() error {
-	dir := m.rootDir
-	cmd := exec.Command("go", "mod", "tidy")
-	cmd.Dir = dir
-	_, err := cmd.CombinedOutput()
+	_, err := runCommand(&Command{Name: "go", Args: []string{"mod", "tidy"}, Dir: m.rootDir})
 	return err
 }
 
@@ -328,21 +327,11 @@ func (g *GoModHandler) reconcilePaths(entries []ReplaceEntry) {
 
 // getModulePath gets full module path
 func (g *Go) getModulePath() (string, error) {
-	file, err := os.Open("go.mod")
+	m, err := g.Module()
 	if err != nil {
 		return "", err
 	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if strings.HasPrefix(line, "module ") {
-			return strings.TrimSpace(strings.TrimPrefix(line, "module")), nil
-		}
-	}
-
-	return "", fmt.Errorf("module directive not found in go.mod")
+	return m.Path, nil
 }
 
 // modExists checks if go.mod exists
@@ -402,58 +391,166 @@ func (g *Go) verify() error {
 
 // WaitForVersionAvailable waits for a module version to be available on Go proxy
 func (g *Go) WaitForVersionAvailable(modulePath, version string) error {
+	if err := AirGapCheck(NetProxy); err != nil {
+		return fmt.Errorf("version check for %s skipped: %w", version, err)
+	}
+
 	target := fmt.Sprintf("%s@%s", modulePath, version)
 	maxRetries := 3
 	delay := 5 * time.Second
 
 	for i := 0; i < maxRetries; i++ {
-		_, err := RunCommandSilent("go", "list", "-m", target)
+		_, err := g.runGoNet(".", []string{"list", "-m", target}, 1)
 		if err == nil {
 			return nil
 		}
+		if kind := ClassifyGoNetworkError(err.Error()); kind == GoNetAuth {
+			return err
+		}
 		if i < maxRetries-1 {
-			fmt.Printf("⏳ Waiting for %s (attempt %d/%d)...\n", version, i+1, maxRetries)
+			fmt.Fprintf(OutputWriter(os.Stdout), "⏳ Waiting for %s (attempt %d/%d)...\n", version, i+1, maxRetries)
 			time.Sleep(delay)
 		}
 	}
 	return fmt.Errorf("version %s not available after %d attempts", version, maxRetries)
 }
 
+// Status of a dependent module in an UpdateReport
+const (
+	DependentUpdated  = "updated"    // Requires the new version and was pushed
+	DependentUpToDate = "up-to-date" // Already required the version or a newer one
+	DependentManual   = "manual"     // Updated but not pushed: other replaces remain
+	DependentFailed   = "failed"
+)
+
+// DependentUpdate is the outcome of updating one dependent module
+type DependentUpdate struct {
+	Dir    string
+	Status string // DependentUpdated, DependentUpToDate, DependentManual or DependentFailed
+	Detail string // What was done, or why it failed
+	Err    error  // Set when Status is DependentFailed
+}
+
+// UpdateReport is the outcome of updating the dependents of Module to
+// Version, one result per dependent in directory order
+type UpdateReport struct {
+	Module  string
+	Version string
+	Results []DependentUpdate
+}
+
+// Failed returns the dependents that could not be updated
+func (r *UpdateReport) Failed() []DependentUpdate {
+	var failed []DependentUpdate
+	for _, u := range r.Results {
+		if u.Status == DependentFailed {
+			failed = append(failed, u)
+		}
+	}
+	return failed
+}
+
+// Err joins the failures, "dir: reason" each; nil when every dependent
+// was updated
+func (r *UpdateReport) Err() error {
+	var errs []error
+	for _, u := range r.Failed() {
+		errs = append(errs, fmt.Errorf("%s: %w", filepath.Base(u.Dir), u.Err))
+	}
+	return errors.Join(errs...)
+}
+
+// Lines returns a "✅ name: detail" or "❌ name: reason" line per
+// dependent, then the number of failures when there are any
+func (r *UpdateReport) Lines() []string {
+	var lines []string
+	for _, u := range r.Results {
+		if u.Status == DependentFailed {
+			lines = append(lines, fmt.Sprintf("❌ %s: %v", filepath.Base(u.Dir), u.Err))
+		} else {
+			lines = append(lines, fmt.Sprintf("✅ %s: %s", filepath.Base(u.Dir), u.Detail))
+		}
+	}
+	if failed := len(r.Failed()); failed > 0 {
+		lines = append(lines, fmt.Sprintf("⚠️ %d of %d dependents not updated to %s", failed, len(r.Results), r.Version))
+	}
+	return lines
+}
+
 // updateDependents updates modules that depend on the current one
-func (g *Go) updateDependents(modulePath, version, searchPath string) ([]string, error) {
+func (g *Go) updateDependents(modulePath, version, searchPath string) (*UpdateReport, error) {
+	return g.updateDependentsExcept(modulePath, version, searchPath, nil)
+}
+
+// updateDependentsExcept is updateDependents leaving out the module dirs
+// (absolute paths) in skip. The go.mod of up to dependents.parallel
+// modules are updated at once; once all are, the changes are pushed one
+// at a time, as each push runs in the dependent's directory.
+func (g *Go) updateDependentsExcept(modulePath, version, searchPath string, skip map[string]bool) (*UpdateReport, error) {
 	if searchPath == "" {
 		searchPath = ".."
 	}
+	report := &UpdateReport{Module: modulePath, Version: version}
 
 	// Find modules that depend on current
-	dependents, err := g.findDependentModules(modulePath, searchPath)
+	found, err := g.findDependentModules(modulePath, searchPath)
 	if err != nil {
-		return nil, err
+		return report, err
 	}
+	var dependents []string
+	for _, dir := range found {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return report, err
+		}
+		if !skip[abs] {
+			dependents = append(dependents, abs)
+		}
+	}
+	sort.Strings(dependents)
+	report.Results = make([]DependentUpdate, len(dependents))
 
 	if len(dependents) == 0 {
-		return nil, nil
+		return report, nil
 	}
 
 	// Wait for version to be available before updating any dependents
-	if err := g.WaitForVersionAvailable(modulePath, version); err != nil {
-		return []string{fmt.Sprintf("⏳ %s", err)}, nil
-	}
-
-	// Update each dependent sequentially to avoid os.Chdir race conditions
-	var results []string
-	for _, depDir := range dependents {
-		depName := filepath.Base(depDir)
-		result, err := g.UpdateDependentModule(depDir, modulePath, version)
-		if err != nil {
-			results = append(results, fmt.Sprintf("❌ %s: %v", depName, err))
-		} else {
-			results = append(results, fmt.Sprintf("✅ %s: %s", depName, result))
+	// (a dry run publishes nothing)
+	if DryRunActive() {
+		dryRunf("", "wait for %s@%s on the module proxy", modulePath, version)
+	} else if err := g.WaitForVersionAvailable(modulePath, version); err != nil {
+		for i, dir := range dependents {
+			report.Results[i] = DependentUpdate{Dir: dir, Status: DependentFailed, Err: err}
+		}
+		return report, nil
+	}
+
+	// A dry run prints the steps of each dependent in order
+	parallel := g.Config().Int("dependents.parallel", 4)
+	if parallel < 1 || DryRunActive() {
+		parallel = 1
+	}
+	sem := make(chan struct{}, parallel)
+	push := make([]bool, len(dependents))
+	var wg sync.WaitGroup
+	for i, depDir := range dependents {
+		wg.Add(1)
+		go func(i int, depDir string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			report.Results[i], push[i] = g.prepareDependent(depDir, modulePath, version)
+		}(i, depDir)
+	}
+	wg.Wait()
+	for i := range dependents {
+		if push[i] {
+			report.Results[i] = g.pushDependent(report.Results[i], modulePath, version)
 		}
 	}
 
 	fmt.Println()
-	return results, nil
+	return report, nil
 }
 
 // findDependentModules searches for modules that have modulePath as dependency
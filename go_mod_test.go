@@ -1,10 +1,12 @@
 package devflow
 
 import (
+	"archive/zip"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestModExistsInCurrentOrParent(t *testing.T) {
@@ -285,3 +287,121 @@ replace github.com/test/lib4 => ../lib4
 		}
 	})
 }
+
+// testModuleProxy serves example.com/core v0.0.1, v0.1.0 and v0.2.0
+// from a file GOPROXY
+func testModuleProxy(t *testing.T) {
+	t.Helper()
+	proxy := t.TempDir()
+	dir := filepath.Join(proxy, "example.com", "core", "@v")
+	os.MkdirAll(dir, 0755)
+	gomod := "module example.com/core\n\ngo 1.20\n"
+	versions := []string{"v0.0.1", "v0.1.0", "v0.2.0"}
+	os.WriteFile(filepath.Join(dir, "list"), []byte(strings.Join(versions, "\n")+"\n"), 0644)
+	for _, v := range versions {
+		os.WriteFile(filepath.Join(dir, v+".info"), []byte(`{"Version":"`+v+`","Time":"2024-01-01T00:00:00Z"}`), 0644)
+		os.WriteFile(filepath.Join(dir, v+".mod"), []byte(gomod), 0644)
+		f, _ := os.Create(filepath.Join(dir, v+".zip"))
+		z := zip.NewWriter(f)
+		for name, content := range map[string]string{"go.mod": gomod, "core.go": "package core\n"} {
+			w, _ := z.Create("example.com/core@" + v + "/" + name)
+			w.Write([]byte(content))
+		}
+		z.Close()
+		f.Close()
+	}
+	t.Setenv("GOPROXY", "file://"+proxy)
+	t.Setenv("GOSUMDB", "off")
+	t.Setenv("GOFLAGS", "-mod=mod")
+	t.Setenv("GOWORK", "off")
+}
+
+func TestUpdateDependentsReport(t *testing.T) {
+	testModuleProxy(t)
+	root := t.TempDir()
+	write := func(dir, gomod string) {
+		os.MkdirAll(filepath.Join(root, dir), 0755)
+		os.WriteFile(filepath.Join(root, dir, "go.mod"), []byte(gomod), 0644)
+	}
+	write("core", "module example.com/core\n\ngo 1.20\n")
+	write("current", "module example.com/current\n\ngo 1.20\n\nrequire example.com/core v0.2.0\n")
+	write("local", "module example.com/local\n\ngo 1.20\n\nrequire (\n\texample.com/core v0.0.1\n\texample.com/other v0.1.0\n)\n\nreplace example.com/core => ../core\n\nreplace example.com/other => ../other\n")
+	os.WriteFile(filepath.Join(root, "local", "local.go"), []byte("package local\n\nimport (\n\t_ \"example.com/core\"\n\t_ \"example.com/other\"\n)\n"), 0644)
+	write("other", "module example.com/other\n\ngo 1.20\n")
+	os.WriteFile(filepath.Join(root, "other", "other.go"), []byte("package other\n"), 0644)
+	write("broken", "module example.com/broken\n\nrequire example.com/core v0.0.1\nnot a directive\n")
+
+	g := &Go{rootDir: filepath.Join(root, "core"), logger: NopLogger(), retryDelay: time.Millisecond, retryAttempts: 1, config: NewConfig()}
+	report, err := g.updateDependents("example.com/core", "v0.1.0", root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	status := map[string]string{}
+	for _, u := range report.Results {
+		status[filepath.Base(u.Dir)] = u.Status
+	}
+	if len(report.Results) != 3 || status["broken"] != DependentFailed || status["current"] != DependentUpToDate || status["local"] != DependentManual {
+		t.Errorf("unexpected results %+v", report.Results)
+	}
+	if mod, _ := os.ReadFile(filepath.Join(root, "local", "go.mod")); !strings.Contains(string(mod), "example.com/core v0.1.0") {
+		t.Errorf("local should require v0.1.0:\n%s", mod)
+	}
+	if err := report.Err(); err == nil || !strings.HasPrefix(err.Error(), "broken: ") {
+		t.Errorf("expected the broken failure, got %v", err)
+	}
+	lines := report.Lines()
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "❌ broken: ") || lines[1] != "✅ current: already up-to-date (v0.2.0)" || lines[3] != "⚠️ 1 of 3 dependents not updated to v0.1.0" {
+		t.Errorf("unexpected lines %q", lines)
+	}
+}
+
+func TestUpdateDependentsParallelRelativeSearchPath(t *testing.T) {
+	testResumeEnv(t)
+	testModuleProxy(t)
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "core"), 0755)
+	os.WriteFile(filepath.Join(root, "core", "go.mod"), []byte("module example.com/core\n\ngo 1.20\n"), 0644)
+	os.WriteFile(filepath.Join(root, "core", ".devflow.yaml"), []byte("dependents:\n  parallel: 3\n"), 0644)
+	names := []string{"alpha", "beta", "gamma"}
+	for _, name := range names {
+		dir := filepath.Join(root, name)
+		os.MkdirAll(dir, 0755)
+		os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/"+name+"\n\ngo 1.20\n\nrequire example.com/core v0.0.1\n"), 0644)
+		os.WriteFile(filepath.Join(dir, name+".go"), []byte("package "+name+"\n\nimport _ \"example.com/core\"\n"), 0644)
+		remote := filepath.Join(root, "remotes", name+".git")
+		RunCommand("git", "init", "-q", "--bare", remote)
+		for _, args := range [][]string{{"init", "-q"}, {"add", "."}, {"commit", "-qm", "init"}, {"remote", "add", "origin", remote}, {"push", "-q", "-u", "origin", "HEAD"}} {
+			RunCommandInDir(dir, "git", args...)
+		}
+	}
+	defer testChdir(t, filepath.Join(root, "core"))()
+
+	g, _ := NewGo(&MockGitClient{})
+	g.SetRetryConfig(time.Millisecond, 1)
+	report, err := g.updateDependents("example.com/core", "v0.1.0", "..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Results) != len(names) {
+		t.Fatalf("expected %d results, got %+v", len(names), report.Results)
+	}
+	for i, u := range report.Results {
+		if !filepath.IsAbs(u.Dir) || filepath.Base(u.Dir) != names[i] || u.Status != DependentUpdated {
+			t.Errorf("unexpected result %+v", u)
+		}
+	}
+	for _, name := range names {
+		if mod, _ := os.ReadFile(filepath.Join(root, name, "go.mod")); !strings.Contains(string(mod), "example.com/core v0.1.0") {
+			t.Errorf("%s should require v0.1.0:\n%s", name, mod)
+		}
+		if subject, _ := RunCommandInDir(filepath.Join(root, "remotes", name+".git"), "git", "log", "-1", "--format=%s"); subject != "deps: update core to v0.1.0" {
+			t.Errorf("expected the update of %s pushed, got %q", name, subject)
+		}
+	}
+	if wd, _ := os.Getwd(); wd != filepath.Join(root, "core") {
+		t.Errorf("expected the working directory restored, got %s", wd)
+	}
+	if mod, _ := os.ReadFile("go.mod"); !strings.Contains(string(mod), "module example.com/core") {
+		t.Error("expected the go.mod of the project untouched")
+	}
+}
@@ -0,0 +1,119 @@
+package devflow
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kinds of module download failures
+const (
+	GoNetProxyOutage = "proxy outage" // proxy.golang.org unreachable or 5xx
+	GoNetSumDBOutage = "sumdb outage" // sum.golang.org unreachable or 5xx
+	GoNetAuth        = "auth"         // credentials rejected (private module, not an outage)
+)
+
+// configKeyProxyFallback allows switching to direct mode during outages
+const configKeyProxyFallback = "go.proxy_fallback"
+
+// outageMarkers indicate the remote service did not answer properly
+var outageMarkers = []string{
+	"502 Bad Gateway", "503 Service Unavailable", "504 Gateway Timeout",
+	"500 Internal Server Error", "connection refused", "connection reset",
+	"i/o timeout", "TLS handshake timeout", "no such host", "unexpected EOF",
+	"context deadline exceeded", "network is unreachable",
+}
+
+// authMarkers indicate missing or rejected credentials
+var authMarkers = []string{
+	"401 Unauthorized", "403 Forbidden", "terminal prompts disabled",
+	"could not read Username", "Authentication failed", "authentication required",
+	"Permission denied (publickey)",
+}
+
+// ClassifyGoNetworkError inspects go command output and reports whether it
+// failed because of a proxy/sumdb outage or an authentication problem.
+// Returns an empty string for any other failure.
+func ClassifyGoNetworkError(output string) string {
+	for _, m := range authMarkers {
+		if strings.Contains(output, m) {
+			return GoNetAuth
+		}
+	}
+
+	outage := false
+	for _, m := range outageMarkers {
+		if strings.Contains(output, m) {
+			outage = true
+			break
+		}
+	}
+	if !outage {
+		return ""
+	}
+
+	// Check sumdb first: sumdb errors also mention the module download
+	if strings.Contains(output, "sum.golang.org") {
+		return GoNetSumDBOutage
+	}
+	if strings.Contains(output, "proxy.golang.org") {
+		return GoNetProxyOutage
+	}
+	return ""
+}
+
+// directModeEnv returns the environment that bypasses the failing service
+func directModeEnv(kind string) []string {
+	if kind == GoNetSumDBOutage {
+		return []string{"GOSUMDB=off"}
+	}
+	return []string{"GOPROXY=direct", "GOSUMDB=off"}
+}
+
+// runGoNet runs a go command that contacts the module proxy or checksum
+// database. On outage it falls back to direct mode only when the project
+// config sets go.proxy_fallback: true; auth errors are never retried.
+func (g *Go) runGoNet(dir string, args []string, attempts int) (string, error) {
+	out, err := RunCommandWithRetryInDir(dir, "go", args, attempts, g.retryDelay)
+	if err == nil {
+		return out, nil
+	}
+
+	kind := ClassifyGoNetworkError(err.Error())
+	switch kind {
+	case "":
+		return out, err
+	case GoNetAuth:
+		return out, fmt.Errorf("authentication error (not a proxy outage), check GOPRIVATE and git credentials: %w", err)
+	}
+
+	service := "proxy.golang.org"
+	if kind == GoNetSumDBOutage {
+		service = "sum.golang.org"
+	}
+
+	if !g.Config().Bool(configKeyProxyFallback, false) {
+		return out, fmt.Errorf("%s unavailable (%s), set %s: true in .devflow.yaml to allow direct mode: %w", service, kind, configKeyProxyFallback, err)
+	}
+
+	env := directModeEnv(kind)
+	g.logger.Info(service, "unavailable, retrying with", strings.Join(env, " "))
+	out, err = RunCommandWithEnvInDir(dir, env, "go", args...)
+	if err != nil {
+		return out, fmt.Errorf("%s unavailable and direct mode failed: %w", service, err)
+	}
+
+	g.addNetNote(fmt.Sprintf("⚠️ %s unavailable, used %s", service, strings.Join(env, " ")))
+	return out, nil
+}
+
+// addNetNote records a fallback message once for the summary
+func (g *Go) addNetNote(note string) {
+	g.netNotesMu.Lock()
+	defer g.netNotesMu.Unlock()
+	for _, n := range g.netNotes {
+		if n == note {
+			return
+		}
+	}
+	g.netNotes = append(g.netNotes, note)
+}
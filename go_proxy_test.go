@@ -0,0 +1,73 @@
+package devflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestClassifyGoNetworkError(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{"go: github.com/a/b@v1.0.0: reading https://proxy.golang.org/github.com/a/b/@v/v1.0.0.zip: 503 Service Unavailable", GoNetProxyOutage},
+		{"verifying github.com/a/b@v1.0.0: github.com/a/b@v1.0.0: Get \"https://sum.golang.org/lookup/github.com/a/b@v1.0.0\": dial tcp: i/o timeout", GoNetSumDBOutage},
+		{"fatal: could not read Username for 'https://github.com': terminal prompts disabled", GoNetAuth},
+		{"reading https://proxy.golang.org/private/x/@v/list: 403 Forbidden", GoNetAuth},
+		{"go: module github.com/a/b: no matching versions for query \"v9\"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := ClassifyGoNetworkError(tt.output); got != tt.want {
+			t.Errorf("ClassifyGoNetworkError(%q) = %q; want %q", tt.output, got, tt.want)
+		}
+	}
+}
+
+// testFakeGo puts a fake go binary in PATH that fails with a proxy outage
+// unless GOPROXY=direct is set.
+func testFakeGo(t *testing.T) {
+	t.Helper()
+	bin := t.TempDir()
+	script := `#!/bin/sh
+if [ "$GOPROXY" = "direct" ]; then
+  echo "direct ok"
+  exit 0
+fi
+echo "reading https://proxy.golang.org/x/@v/list: 502 Bad Gateway" >&2
+exit 1
+`
+	os.WriteFile(filepath.Join(bin, "go"), []byte(script), 0755)
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestRunGoNetFallback(t *testing.T) {
+	g, _ := NewGo(nil)
+	testFakeGo(t)
+
+	dir := t.TempDir()
+
+	// Not allowed by config: outage reported with a hint
+	g.SetConfig(NewConfig())
+	_, err := g.runGoNet(dir, []string{"get", "x"}, 1)
+	if err == nil || !strings.Contains(err.Error(), "go.proxy_fallback") {
+		t.Fatalf("expected outage error with config hint, got %v", err)
+	}
+
+	// Allowed by config: retried in direct mode and noted for the summary
+	cfg := NewConfig()
+	cfg.Set(configKeyProxyFallback, "true")
+	g.SetConfig(cfg)
+	out, err := g.runGoNet(dir, []string{"get", "x"}, 1)
+	if err != nil {
+		t.Fatalf("expected fallback to succeed, got %v", err)
+	}
+	if out != "direct ok" {
+		t.Errorf("unexpected output: %q", out)
+	}
+	if len(g.netNotes) != 1 || !strings.Contains(g.netNotes[0], "proxy.golang.org unavailable") {
+		t.Errorf("expected fallback note, got %v", g.netNotes)
+	}
+}
@@ -0,0 +1,34 @@
+package devflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// WorkUse adds the module dirs to the go.work of workDir, creating it with
+// go work init when missing. Dirs are relative to workDir or absolute.
+func (g *Go) WorkUse(workDir string, dirs ...string) error {
+	if len(dirs) == 0 {
+		return fmt.Errorf("no modules to add to the workspace")
+	}
+	if _, err := os.Stat(filepath.Join(workDir, "go.work")); os.IsNotExist(err) {
+		if out, err := RunCommandInDir(workDir, "go", "work", "init"); err != nil {
+			return fmt.Errorf("go work init failed: %s", firstLine(out))
+		}
+	}
+	args := append([]string{"work", "use"}, dirs...)
+	if out, err := RunCommandInDir(workDir, "go", args...); err != nil {
+		return fmt.Errorf("go work use failed: %s", firstLine(out))
+	}
+	return nil
+}
+
+// WorkSync runs go work sync in workDir, so every module requires the
+// versions the workspace resolved
+func (g *Go) WorkSync(workDir string) error {
+	if out, err := RunCommandInDir(workDir, "go", "work", "sync"); err != nil {
+		return fmt.Errorf("go work sync failed: %s", firstLine(out))
+	}
+	return nil
+}
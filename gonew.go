@@ -1,9 +1,12 @@
 package devflow
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
@@ -12,18 +15,58 @@ type GoNew struct {
 	git    GitClient
 	github *Future
 	goH    *Go
-	log    func(...any)
+	logger Logger
+
+	// Template variable prompts (nil disables interactive prompts)
+	in  io.Reader
+	out io.Writer
+
+	audit []AuditEntry // Steps of the last Create
+
+	gists GistFetcher // Snippet source (default: the provider, when it hosts gists)
+
+	rollback string // What a failed create undoes (default: gonew.rollback config)
+
+	warnings Warnings // Non-fatal problems of the last Create
+
+	dryRun bool
 }
 
 // NewProjectOptions options for creating a new project
 type NewProjectOptions struct {
-	Name        string // Required, must be valid (alphanumeric, dash, underscore only)
-	Description string // Required, max 350 chars
-	Owner       string // GitHub owner/organization (default: detected from gh or git config)
-	Visibility  string // "public" or "private" (default: "public")
-	Directory   string // Supports ~/path, ./path, /abs/path (default: ./{Name})
-	LocalOnly   bool   // If true, skip remote creation
-	License     string // Default "MIT"
+	Name          string // Required, must be valid (alphanumeric, dash, underscore only)
+	Description   string // Required; the remote gets it sanitized to one line of at most 350 chars
+	Owner         string // Owner/organization on the provider (default: detected from the provider or git config)
+	Visibility    string // "public" or "private" (default: "public")
+	Directory     string // Supports ~/path, ./path, /abs/path (default: ./{Name})
+	LocalOnly     bool   // If true, skip remote creation
+	License       string // SPDX identifier: MIT (default), Apache-2.0, BSD-3-Clause, GPL-3.0, MPL-2.0 or Unlicense
+	Host          string // Module path host (default: the provider host, github.com)
+	ModulePrefix  string // Module path prefix, e.g. "go.example.com/libs" (default: <Host>/<owner>)
+	DefaultBranch string // Initial branch (default: main)
+	Type          string // Project type: library (default), cli, wasm or web
+
+	RemoteProtocol string // Protocol of the origin URL: https, ssh, or auto for the one the provider CLI uses (default: gonew.remote_protocol config, else auto)
+
+	ReadmeLanguages []string // Also write README.<lang>.md for these languages (see ReadmeLanguages)
+	CI              bool     // Also write the GitHub Actions workflow of GenerateCIWorkflow (default: gonew.ci config)
+
+	DependencyBot      string // "dependabot" or "renovate": also write its config (default: gonew.dependency_bot config, else off)
+	DependencySchedule string // Update interval of DependencyBot: daily, weekly (default) or monthly (default: gonew.dependency_schedule config)
+
+	Community    bool   // Also write the issue and PR templates, CODE_OF_CONDUCT.md and CONTRIBUTING.md (default: gonew.community config)
+	CommunityDir string // Files replacing or adding to the built-in community files (default: gonew.community_dir config)
+
+	Template      string            // Template directory with optional template.yml
+	TemplateVars  map[string]string // Preset template variable values
+	TrustTemplate bool              // Run the hooks of an installed template without asking
+	Seed          string            // Existing code directory copied into the project
+	Snippets      []string          // Gists (id, id@revision or URL) whose files are copied into the project
+
+	Repo RepoSettings // Applied to the remote once pushed (default: gonew.repo.* config)
+
+	SignOff   bool     // Sign off the initial commit with the git user.name and user.email (default: commit.signoff config)
+	CoAuthors []string // Co-authored-by identities of the initial commit, "Name <email>" (default: commit.co_authors config)
 }
 
 // NewGoNew creates orchestrator (all handlers must be initialized)
@@ -32,42 +75,92 @@ func NewGoNew(git GitClient, github *Future, goHandler *Go) *GoNew {
 		git:    git,
 		github: github,
 		goH:    goHandler,
-		log:    func(...any) {},
+		logger: NopLogger(),
 	}
 }
 
-// SetLog sets the logger function
+// SetLog sets the logger function of the orchestrator and its git and
+// Go handlers. Use SetLogger for levels or JSON output.
 func (gn *GoNew) SetLog(fn func(...any)) {
 	if fn != nil {
-		gn.log = fn
-		if gn.git != nil {
-			gn.git.SetLog(fn)
-		}
-		// Note: GitHub client uses its own logger set during initialization
-		// We don't update it here to avoid race conditions with the Future
-		if gn.goH != nil {
-			gn.goH.SetLog(fn)
-		}
+		gn.SetLogger(FuncLogger(fn))
+	}
+}
+
+// SetLogger sets the leveled logger of the orchestrator and its git and
+// Go handlers
+func (gn *GoNew) SetLogger(l Logger) {
+	if l == nil {
+		return
+	}
+	gn.logger = l
+	if gn.git != nil {
+		setLogger(gn.git, l)
 	}
+	// Note: GitHub client uses its own logger set during initialization
+	// We don't update it here to avoid race conditions with the Future
+	if gn.goH != nil {
+		gn.goH.SetLogger(l)
+	}
+}
+
+// SetDryRun makes Create and AddRemote print every command and write they
+// would perform instead of doing it. Read-only checks (git config, repo
+// exists) still run.
+func (gn *GoNew) SetDryRun(enabled bool) {
+	gn.dryRun = enabled
+}
+
+// SetGistFetcher sets where the snippets of NewProjectOptions.Snippets
+// are fetched from, e.g. a GitHub handler for a GitLab project
+func (gn *GoNew) SetGistFetcher(f GistFetcher) {
+	gn.gists = f
 }
 
-// Create executes full workflow with remote (or local-only fallback)
+// SetPrompt enables interactive prompts for template variables that are not
+// provided by flags, environment or global config
+func (gn *GoNew) SetPrompt(in io.Reader, out io.Writer) {
+	if in != nil {
+		// One buffered reader for every prompt, so none reads ahead of the next
+		in = bufio.NewReader(in)
+	}
+	gn.in = in
+	gn.out = out
+}
+
+// Create executes full workflow with remote (or local-only fallback).
+// Progress is saved as it goes (see CreateState), so a run that fails
+// can be finished with Resume.
 func (gn *GoNew) Create(opts NewProjectOptions) (string, error) {
+	return gn.create(opts, nil)
+}
+
+// create runs Create; with state it resumes, skipping the steps done
+func (gn *GoNew) create(opts NewProjectOptions, state *CreateState) (_ string, err error) {
+	gn.audit = nil
+	defer gn.warnings.begin()()
+	if gn.dryRun {
+		defer startDryRun()()
+	}
+	defer trackMetric("gonew")(&err)
+	defer traceSpan("gonew")(&err)
+
 	// 1. Validate inputs
+	tracePhase("validate")
 	if err := ValidateRepoName(opts.Name); err != nil {
 		return "", err
 	}
 	if err := ValidateDescription(opts.Description); err != nil {
 		return "", err
 	}
-
-	if opts.Visibility == "" {
-		opts.Visibility = "public"
+	if err := ValidateProjectType(opts.Type); err != nil {
+		return "", err
 	}
-
 	// Determine target directory
 	targetDir := opts.Directory
-	if targetDir == "" {
+	if state != nil {
+		targetDir = state.Dir
+	} else if targetDir == "" {
 		cwd, err := os.Getwd()
 		if err != nil {
 			return "", err
@@ -83,12 +176,100 @@ func (gn *GoNew) Create(opts NewProjectOptions) (string, error) {
 	}
 	targetDir, _ = filepath.Abs(targetDir)
 
+	// Defaults of the global and workspace config (gonew.*)
+	cfg, err := LoadConfig(filepath.Dir(targetDir))
+	if err != nil {
+		gn.warn("config ignored", err)
+		cfg = NewConfig()
+	}
+	if opts.License == "" {
+		opts.License = cfg.String("gonew.license", "")
+	}
+	license, err := NormalizeLicense(opts.License)
+	if err != nil {
+		return "", err
+	}
+	opts.License = license
+	if opts.Visibility == "" {
+		opts.Visibility = cfg.String("gonew.visibility", "public")
+	}
+	if opts.ModulePrefix == "" {
+		opts.ModulePrefix = strings.TrimSuffix(cfg.String("gonew.module_prefix", ""), "/")
+	}
+	if opts.RemoteProtocol == "" {
+		opts.RemoteProtocol = cfg.String("gonew.remote_protocol", ProtocolAuto)
+	}
+	if err := ValidateRemoteProtocol(opts.RemoteProtocol); err != nil {
+		return "", err
+	}
+	if !opts.SignOff {
+		opts.SignOff = cfg.Bool("commit.signoff", false)
+	}
+	if len(opts.CoAuthors) == 0 {
+		opts.CoAuthors = cfg.List("commit.co_authors")
+	}
+	if _, err := identityTrailers(gn.git, opts.CoAuthors, opts.SignOff, nil); err != nil {
+		return "", err
+	}
+	if opts.DefaultBranch == "" {
+		opts.DefaultBranch = cfg.String("gonew.default_branch", "")
+	}
+	if opts.DefaultBranch == "" {
+		opts.DefaultBranch = "main"
+		if bi, ok := gn.git.(BranchIniter); ok {
+			opts.DefaultBranch = bi.InitBranch()
+		}
+	}
+	if err := validateBranchName(opts.DefaultBranch); err != nil {
+		return "", err
+	}
+	if len(opts.ReadmeLanguages) == 0 {
+		opts.ReadmeLanguages = cfg.List("gonew.readme_languages")
+	}
+	if opts.ReadmeLanguages, err = NormalizeReadmeLanguages(opts.ReadmeLanguages); err != nil {
+		return "", err
+	}
+	if !opts.CI {
+		opts.CI = cfg.Bool("gonew.ci", false)
+	}
+	if opts.DependencyBot == "" {
+		opts.DependencyBot = cfg.String("gonew.dependency_bot", DependencyBotOff)
+	}
+	if opts.DependencySchedule == "" {
+		opts.DependencySchedule = cfg.String("gonew.dependency_schedule", "")
+	}
+	if !opts.Community {
+		opts.Community = cfg.Bool("gonew.community", false)
+	}
+	if opts.CommunityDir == "" {
+		opts.CommunityDir = cfg.String("gonew.community_dir", "")
+	}
+	if err := ValidateDependencyBot(opts.DependencyBot); err != nil {
+		return "", err
+	}
+	if err := ValidateDependencySchedule(opts.DependencySchedule); err != nil {
+		return "", err
+	}
+	opts.Repo = LoadRepoSettings(cfg, opts.Repo)
+	if err := opts.Repo.Validate(); err != nil {
+		return "", err
+	}
+
 	// 2. Check availability
 	// Check if directory exists
-	if _, err := os.Stat(targetDir); !os.IsNotExist(err) {
+	if _, err := os.Stat(targetDir); state == nil && !os.IsNotExist(err) {
+		if saved, _ := LoadCreateState(targetDir); saved != nil {
+			return "", fmt.Errorf("directory %s already exists from an unfinished create; run 'gonew resume %s'", targetDir, opts.Name)
+		}
 		return "", fmt.Errorf("directory %s already exists", targetDir)
 	}
 
+	if opts.Seed != "" {
+		if info, err := os.Stat(opts.Seed); err != nil || !info.IsDir() {
+			return "", fmt.Errorf("seed directory %s not found", opts.Seed)
+		}
+	}
+
 	// Prepare result summary
 	var resultSummary string
 	isRemote := false
@@ -104,12 +285,26 @@ func (gn *GoNew) Create(opts NewProjectOptions) (string, error) {
 		return "", fmt.Errorf("git user.email not configured. Run: git config --global user.email \"email@example.com\"")
 	}
 
+	// Probe the provider up front: offline, the project is created locally
+	// at once instead of after the gh timeouts
+	offline := ""
+	if !opts.LocalOnly && gn.github != nil && (state == nil || !state.Done(CreateStepRemote)) {
+		st := probeNetwork(opts.Host, 0)
+		gn.logger.Debug("Network probe of", st.Host+":", st, "in", st.Elapsed)
+		if !st.Online {
+			offline = st.Reason
+			gn.logger.Info("Offline:", st.Reason)
+		}
+	}
+
 	// 3. Determine owner
 	var ghUser string
-	if opts.Owner != "" {
+	if state != nil {
+		ghUser = state.Owner
+	} else if opts.Owner != "" {
 		// Use specified owner
 		ghUser = opts.Owner
-	} else if gn.github != nil {
+	} else if gn.github != nil && offline == "" {
 		// Auto-detect from gh CLI
 		res, err := gn.github.Get()
 		if err != nil {
@@ -122,82 +317,210 @@ func (gn *GoNew) Create(opts NewProjectOptions) (string, error) {
 			// Fallback to git config if gh fails
 			gitUser := strings.ReplaceAll(strings.ToLower(userName), " ", "")
 			ghUser = gitUser
-			gn.log("Warning: could not get GitHub user, using git user:", gitUser)
+			gn.warn("owner "+gitUser+" taken from git config", fmt.Errorf("could not get GitHub user: %w", err))
 		}
 	} else {
 		// Fallback to git config
 		ghUser = strings.ReplaceAll(strings.ToLower(userName), " ", "")
 	}
 
-	// 4. Create remote (if not local-only)
-	// We'll create the empty repo first, then add remote after local setup
-	if !opts.LocalOnly {
-		// Check if repo exists on GitHub
-		res, err := gn.github.Get()
+	// Go Mod Init path, also exposed to templates as {{module}}
+	host := opts.Host
+	if host == "" && !opts.LocalOnly && gn.github != nil && offline == "" {
+		if res, err := gn.github.Get(); err == nil {
+			if p, ok := res.(RepoProvider); ok {
+				host = p.Host()
+			}
+		}
+	}
+	if host == "" {
+		host = "github.com"
+	}
+	modulePath := fmt.Sprintf("%s/%s/%s", host, ghUser, opts.Name)
+	if opts.ModulePrefix != "" {
+		modulePath = opts.ModulePrefix + "/" + opts.Name
+	}
+	if state != nil {
+		modulePath = state.Module
+	}
+
+	// Published identity: author.* config, provider profile, git config
+	var profiler any
+	if !opts.LocalOnly && gn.github != nil && offline == "" {
+		profiler, _ = gn.github.Get()
+	}
+	author := ResolveAuthor(cfg, profiler, gn.git)
+
+	// Resolve template variables before creating anything
+	// The built-ins also fill the community files
+	var tmpl *Template
+	tmplValues := TemplateBuiltins(opts, ghUser, modulePath)
+	tmplValues["author"], tmplValues["author_email"], tmplValues["author_url"] = author.Name, author.Email, author.URL
+	if opts.Template != "" && (state == nil || !state.Done(CreateStepFiles)) {
+		if tmpl, err = LoadTemplate(opts.Template); err != nil {
+			return "", err
+		}
+		if err := gn.confirmHooks(tmpl, &opts); err != nil {
+			return "", err
+		}
+		global, err := LoadGlobalConfig()
 		if err != nil {
 			return "", err
 		}
-		gh := res.(GitHubClient)
+		vars, err := tmpl.Resolve(opts.TemplateVars, global, gn.in, gn.out)
+		if err != nil {
+			return "", err
+		}
+		for k, v := range vars {
+			tmplValues[k] = v
+		}
+		// Saved with the progress so a resume does not prompt again
+		opts.TemplateVars = vars
+	}
 
-		if ghUser == "" {
-			ghUser, err = gh.GetCurrentUser()
+	// Snippets are fetched up front too; their revisions are pinned in
+	// the saved options so a resume copies the same files
+	var snippets []*Gist
+	if len(opts.Snippets) > 0 && (state == nil || !state.Done(CreateStepFiles)) {
+		if snippets, err = gn.fetchSnippets(opts.Snippets, offline); err != nil {
+			return "", err
 		}
-		if err != nil {
-			// Fallback to local only
-			gn.log("GitHub unavailable:", err)
-			resultSummary = fmt.Sprintf("⚠️ Created: %s [local only] v0.0.1 - %s", opts.Name, gh.GetHelpfulErrorMessage(err))
-		} else {
-			exists, err := gh.RepoExists(ghUser, opts.Name)
-			if err == nil && exists {
-				return "", fmt.Errorf("repository %s/%s already exists on GitHub", ghUser, opts.Name)
-			} else if err != nil {
-				// Network error or other issue
-				gn.log("GitHub check failed:", err)
-				resultSummary = fmt.Sprintf("⚠️ Created: %s [local only] v0.0.1 - gh unavailable", opts.Name)
-			} else {
-				// Create empty remote repo
-				if err := gh.CreateRepo(ghUser, opts.Name, opts.Description, opts.Visibility); err != nil {
-					gn.log("Failed to create remote:", err)
-					resultSummary = fmt.Sprintf("⚠️ Created: %s [local only] v0.0.1 - failed to create remote", opts.Name)
-				} else {
-					isRemote = true
-					resultSummary = fmt.Sprintf("✅ Created: %s [local+remote] v0.0.1", opts.Name)
-				}
-			}
+		pinned := make([]string, len(snippets))
+		for i, gist := range snippets {
+			pinned[i] = gist.Ref()
+		}
+		opts.Snippets = pinned
+		if state != nil {
+			state.Options.Snippets = pinned
 		}
-	} else {
-		resultSummary = fmt.Sprintf("⚠️ Created: %s [local only] v0.0.1 - run 'gonew add-remote' when ready", opts.Name)
 	}
 
-	// 5. Initialize local directory
-	if err := os.MkdirAll(targetDir, 0755); err != nil {
-		return "", fmt.Errorf("failed to create directory: %w", err)
+	if state == nil {
+		state = &CreateState{Options: opts, Dir: targetDir, Owner: ghUser, Module: modulePath}
 	}
+	defer func() {
+		if saved, _ := LoadCreateState(targetDir); err != nil && saved != nil {
+			err = fmt.Errorf("%w\nResume with: gonew resume %s", err, opts.Name)
+		}
+	}()
 
-	// Always init local (don't clone, we'll add remote later)
-	if err := gn.git.InitRepo(targetDir); err != nil {
-		return "", fmt.Errorf("failed to init repo: %w", err)
+	// 4. Create remote (if not local-only). Whether it can be created is
+	// checked first, so a taken name or a refusing owner fails before any
+	// file is written; the creation itself runs concurrently with the
+	// local scaffold and is joined before the first git operation.
+	remote := make(chan remoteOutcome, 1)
+	switch {
+	case !opts.LocalOnly && state.Done(CreateStepRemote):
+		remote <- remoteOutcome{owner: ghUser, created: true, summary: fmt.Sprintf("✅ Created: %s [local+remote] v0.0.1", opts.Name)}
+	case !opts.LocalOnly && offline != "":
+		remote <- remoteOutcome{owner: ghUser, summary: fmt.Sprintf("⚠️ Created: %s [local only] v0.0.1 - offline (%s)", opts.Name, offline)}
+	case !opts.LocalOnly:
+		r := gn.checkRemote(opts, ghUser)
+		if r.err != nil {
+			gn.warn("progress not discarded", DiscardCreateState(targetDir))
+			return "", r.err
+		}
+		if !r.create {
+			remote <- r
+			break
+		}
+		if gn.dryRun {
+			// Printed, not run, while this run's dry-run mode is on
+			remote <- gn.createRemote(opts, r)
+			break
+		}
+		end := traceChild("create remote")
+		go func() {
+			r := gn.createRemote(opts, r)
+			end(&r.err)
+			remote <- r
+		}()
+	default:
+		remote <- remoteOutcome{owner: ghUser, summary: fmt.Sprintf("⚠️ Created: %s [local only] v0.0.1 - run 'gonew add-remote' when ready", opts.Name)}
+	}
+	joined := false
+	joinRemote := func() error {
+		if joined {
+			return nil
+		}
+		joined = true
+		r := <-remote
+		if r.err != nil {
+			return r.err
+		}
+		ghUser, state.Owner, isRemote, resultSummary = r.owner, r.owner, r.created, r.summary
+		if r.created && !state.Done(CreateStepRemote) {
+			state.RemoteCreated = !r.adopted
+			return state.complete(CreateStepRemote)
+		}
+		return nil
 	}
 
-	// 6. Generate files
-	if err := GenerateREADME(opts.Name, opts.Description, targetDir); err != nil {
-		return "", err
+	if gn.dryRun {
+		if err := joinRemote(); err != nil {
+			return "", err
+		}
+		gn.printCreatePlan(opts, targetDir, modulePath, tmpl, tmplValues, snippets, isRemote, ghUser)
+		return "[dry-run] " + resultSummary, nil
 	}
-	if err := GenerateLicense(userName, targetDir); err != nil {
-		return "", err
+
+	rollback := gn.rollback
+	if rollback == "" {
+		rollback = cfg.String("gonew.rollback", RollbackOff)
+		if err := ValidateRollbackMode(rollback); err != nil {
+			gn.warn("gonew.rollback ignored", err)
+			rollback = RollbackOff
+		}
 	}
-	if err := GenerateGitignore(targetDir); err != nil {
-		return "", err
+	if rollback != "" && rollback != RollbackOff {
+		defer func() {
+			if err != nil {
+				joinRemote()
+				err = gn.rollbackCreate(err, rollback, state)
+			}
+		}()
 	}
-	if err := GenerateHandlerFile(opts.Name, targetDir); err != nil {
+
+	if err := state.save(); err != nil {
+		joinRemote()
 		return "", err
 	}
+	defer OnInterrupt(func() {
+		fmt.Fprintf(os.Stderr, "⚠️ Create interrupted: resume with 'gonew resume %s'\n", opts.Name)
+	})()
 
-	// Go Mod Init
-	modulePath := fmt.Sprintf("github.com/%s/%s", ghUser, opts.Name)
+	tracePhase("generate")
+	if !state.Done(CreateStepFiles) {
+		// Files of an interrupted run are generated again from scratch, in
+		// a directory that run made only
+		if _, err := os.Stat(targetDir); err == nil {
+			if !state.DirCreated {
+				joinRemote()
+				return "", fmt.Errorf("directory %s was not made by this create; move it away or run 'gonew resume -discard %s'", targetDir, targetDir)
+			}
+			if err := os.RemoveAll(targetDir); err != nil {
+				joinRemote()
+				return "", err
+			}
+		}
+		state.DirCreated = true
+		if err := state.save(); err != nil {
+			joinRemote()
+			return "", err
+		}
+		if err := gn.generateFiles(opts, targetDir, modulePath, author, tmpl, tmplValues, snippets); err != nil {
+			// A created remote is kept in the progress for gonew resume
+			joinRemote()
+			return "", err
+		}
+		if err := state.complete(CreateStepFiles); err != nil {
+			joinRemote()
+			return "", err
+		}
+	}
 
-	if err := gn.goH.ModInit(modulePath, targetDir); err != nil {
-		return "", fmt.Errorf("go mod init failed: %w", err)
+	if err := joinRemote(); err != nil {
+		return "", err
 	}
 
 	// Change to target dir for git operations
@@ -211,37 +534,341 @@ func (gn *GoNew) Create(opts NewProjectOptions) (string, error) {
 	}
 
 	// 7. Initial commit
-	if err := gn.git.Add(); err != nil {
-		return "", err
-	}
-	if _, err := gn.git.Commit("Initial commit"); err != nil {
-		return "", err
+	tracePhase("commit")
+	if !state.Done(CreateStepCommitted) {
+		if err := gn.git.Add(); err != nil {
+			return "", err
+		}
+		trailers, err := identityTrailers(gn.git, opts.CoAuthors, opts.SignOff, nil)
+		if err != nil {
+			return "", err
+		}
+		if _, err := gn.git.Commit(AddTrailers("Initial commit", trailers)); err != nil {
+			return "", err
+		}
+		gn.record("initial commit", "", nil)
+		if err := state.complete(CreateStepCommitted); err != nil {
+			return "", err
+		}
 	}
 
 	// 8. Tag creation
-	if _, err := gn.git.CreateTag("v0.0.1"); err != nil {
-		return "", err
+	if !state.Done(CreateStepTagged) {
+		if _, err := gn.git.CreateTag("v0.0.1"); err != nil {
+			return "", err
+		}
+		gn.record("tag v0.0.1", "", nil)
+		if err := state.complete(CreateStepTagged); err != nil {
+			return "", err
+		}
 	}
 
 	// 9. Add remote and push (if remote was created)
-	if isRemote {
-		// Add remote origin
-		repoURL := fmt.Sprintf("https://github.com/%s/%s.git", ghUser, opts.Name)
-		if _, err := RunCommand("git", "remote", "add", "origin", repoURL); err != nil {
-			gn.log("Failed to add remote:", err)
+	if isRemote && !state.Done(CreateStepPushed) {
+		tracePhase("push")
+		// Add remote origin (already there when resuming a failed push)
+		res, _ := gn.github.Get()
+		repoURL := remoteRepoURL(res.(GitHubClient), ghUser, opts.Name, opts.RemoteProtocol)
+		var addErr error
+		if _, err := RunCommandSilent("git", "remote", "get-url", "origin"); err != nil {
+			_, addErr = RunCommand("git", "remote", "add", "origin", repoURL)
+		}
+		if addErr != nil {
+			gn.warn("remote not added", addErr)
+			gn.record("add remote "+repoURL, "", addErr)
 			resultSummary = fmt.Sprintf("⚠️ Created: %s [local only] v0.0.1 - failed to add remote", opts.Name)
 		} else if err := gn.git.PushWithTags("v0.0.1"); err != nil {
 			// If push fails, warn but don't fail the whole process
-			gn.log("Push failed:", err)
+			gn.warn("push failed", err)
+			gn.record("push", "", err)
 			resultSummary = fmt.Sprintf("⚠️ Created: %s [local only] v0.0.1 - push failed", opts.Name)
+		} else {
+			gn.record("push", "", nil)
+			if err := state.complete(CreateStepPushed); err != nil {
+				return "", err
+			}
+			if !opts.Repo.IsZero() {
+				tracePhase("configure")
+				resultSummary += gn.configureRepo(opts, ghUser)
+			}
 		}
 	}
 
+	// A missing remote or push is kept for gonew resume
+	if opts.LocalOnly || state.Done(CreateStepPushed) {
+		gn.warn("progress not discarded", DiscardCreateState(targetDir))
+	} else {
+		resultSummary += fmt.Sprintf(" - resume with 'gonew resume %s'", opts.Name)
+	}
+
 	return resultSummary, nil
 }
 
-// AddRemote adds GitHub remote to existing local project
+// configureRepo applies opts.Repo to the pushed remote and returns the
+// warning of the summary when it could not
+func (gn *GoNew) configureRepo(opts NewProjectOptions, owner string) string {
+	res, _ := gn.github.Get()
+	configurer, ok := res.(RepoConfigurer)
+	if !ok {
+		return fmt.Sprintf(" - repository settings not applied (not supported by %s)", providerLabel(res.(GitHubClient)))
+	}
+	settings := repoSettingsOf(opts)
+	err := configurer.ConfigureRepo(owner, opts.Name, settings)
+	gn.record("configure repository: "+settings.String(), "", err)
+	if err != nil {
+		gn.warn("repository settings", err)
+		return " - some repository settings failed (see the audit log)"
+	}
+	return ""
+}
+
+// repoSettingsOf returns opts.Repo for the branch of opts
+func repoSettingsOf(opts NewProjectOptions) RepoSettings {
+	s := opts.Repo
+	if s.DefaultBranch == "" {
+		s.DefaultBranch = opts.DefaultBranch
+	}
+	return s
+}
+
+// fetchSnippets fetches the gists of refs from the gist fetcher, else
+// the provider when it hosts gists
+func (gn *GoNew) fetchSnippets(refs []string, offline string) ([]*Gist, error) {
+	fetcher := gn.gists
+	if fetcher == nil && gn.github != nil && offline == "" {
+		if res, err := gn.github.Get(); err == nil {
+			fetcher, _ = res.(GistFetcher)
+		}
+	}
+	if fetcher == nil {
+		if offline != "" {
+			return nil, fmt.Errorf("snippets need GitHub gists, which are unreachable offline (%s)", offline)
+		}
+		return nil, fmt.Errorf("snippets need GitHub gists: the provider has none")
+	}
+	gists := make([]*Gist, 0, len(refs))
+	for _, ref := range refs {
+		id, revision, err := ParseGistRef(ref)
+		if err != nil {
+			return nil, err
+		}
+		gist, err := fetcher.FetchGist(id, revision)
+		if err != nil {
+			return nil, err
+		}
+		gn.logger.Info("Fetched gist", gist.Ref()+":", strings.Join(gist.FileNames(), ", "))
+		gists = append(gists, gist)
+	}
+	return gists, nil
+}
+
+// remoteOutcome is the result of creating the remote repository
+type remoteOutcome struct {
+	owner   string
+	created bool
+	adopted bool // created is an existing empty repository
+	create  bool // The repository is still to be created
+	summary string
+	err     error // Aborts Create: the provider is unusable or the repository exists
+}
+
+// checkRemote resolves the owner and checks, before anything is created,
+// that the repository may be created: the owner accepts it and it does not
+// exist yet (or is empty and adopted). Provider failures degrade to a
+// local-only summary; the remote is added on push.
+func (gn *GoNew) checkRemote(opts NewProjectOptions, owner string) remoteOutcome {
+	r := remoteOutcome{owner: owner}
+	res, err := gn.github.Get()
+	if err != nil {
+		r.err = err
+		return r
+	}
+	gh := res.(GitHubClient)
+
+	if r.owner == "" {
+		r.owner, err = gh.GetCurrentUser()
+	} else if checker, ok := gh.(OwnerChecker); ok {
+		// A given owner (an organization) must accept the repository
+		if err = checker.CheckOwner(r.owner, opts.Visibility); err != nil && !gh.IsNetworkError(err) {
+			r.err = err
+			return r
+		}
+	}
+	if err != nil {
+		// Fallback to local only
+		gn.warn(providerLabel(gh)+" unavailable", err)
+		r.summary = fmt.Sprintf("⚠️ Created: %s [local only] v0.0.1 - %s", opts.Name, gh.GetHelpfulErrorMessage(err))
+		return r
+	}
+	exists, err := gh.RepoExists(r.owner, opts.Name)
+	switch {
+	case err == nil && exists:
+		// An empty repository (e.g. of a failed run) is adopted as origin
+		if r.err = existingRepoError(gh, r.owner, opts.Name); r.err == nil {
+			gn.logger.Info("Adopting empty repository", r.owner+"/"+opts.Name)
+			r.created, r.adopted = true, true
+			r.summary = fmt.Sprintf("✅ Created: %s [local+remote] v0.0.1 - adopted the empty remote", opts.Name)
+		}
+	case err != nil:
+		// Network error or other issue
+		gn.warn(providerLabel(gh)+" check failed", err)
+		r.summary = fmt.Sprintf("⚠️ Created: %s [local only] v0.0.1 - gh unavailable", opts.Name)
+	default:
+		r.create = true
+	}
+	return r
+}
+
+// createRemote creates the empty repository checked by checkRemote. It
+// only calls the provider, so it may run concurrently with the scaffold.
+func (gn *GoNew) createRemote(opts NewProjectOptions, r remoteOutcome) remoteOutcome {
+	res, err := gn.github.Get()
+	if err != nil {
+		r.err = err
+		return r
+	}
+	gh := res.(GitHubClient)
+	create := gh.CreateRepo
+	if creator, ok := gh.(RepoCreator); ok {
+		create = creator.CreateRemoteOnly
+	}
+	if err := create(r.owner, opts.Name, opts.Description, opts.Visibility); err != nil {
+		gn.warn("remote not created", err)
+		r.summary = fmt.Sprintf("⚠️ Created: %s [local only] v0.0.1 - failed to create remote", opts.Name)
+	} else {
+		r.created = true
+		r.summary = fmt.Sprintf("✅ Created: %s [local+remote] v0.0.1", opts.Name)
+	}
+	return r
+}
+
+// existingRepoError returns nil when owner/name can be adopted because
+// the provider reports it empty, else the "already exists" error
+func existingRepoError(gh GitHubClient, owner, name string) error {
+	err := fmt.Errorf("repository %s/%s already exists on %s", owner, name, providerLabel(gh))
+	checker, ok := gh.(EmptyRepoChecker)
+	if !ok {
+		return err
+	}
+	empty, cerr := checker.RepoIsEmpty(owner, name)
+	if cerr != nil {
+		return fmt.Errorf("%w (checking whether it is empty failed: %v)", err, cerr)
+	}
+	if !empty {
+		return fmt.Errorf("%w and is not empty", err)
+	}
+	return nil
+}
+
+// generateFiles creates the project directory, initializes git and writes
+// the generated, template, snippet and seed files; failed hooks are
+// warnings unless the template makes them fatal
+func (gn *GoNew) generateFiles(opts NewProjectOptions, targetDir, modulePath string, author Author, tmpl *Template, tmplValues map[string]string, snippets []*Gist) error {
+	// 5. Initialize local directory
+	if err := os.MkdirAll(targetDir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	gn.record("create directory "+targetDir, "", nil)
+
+	// Always init local (don't clone, we'll add remote later)
+	if bi, ok := gn.git.(BranchIniter); ok {
+		if err := bi.InitRepoBranch(targetDir, opts.DefaultBranch); err != nil {
+			return fmt.Errorf("failed to init repo: %w", err)
+		}
+	} else if err := gn.git.InitRepo(targetDir); err != nil {
+		return fmt.Errorf("failed to init repo: %w", err)
+	}
+	gn.record("git init", "", nil)
+
+	// 6. Generate files
+	if err := GenerateREADMEWithAuthor(opts.Name, opts.Description, author, targetDir); err != nil {
+		return err
+	}
+	if err := GenerateLocalizedREADMEs(opts.Name, opts.Description, author, opts.ReadmeLanguages, targetDir); err != nil {
+		return err
+	}
+	if _, err := GenerateLicenseFile(opts.License, author.Name, targetDir); err != nil {
+		return err
+	}
+	if opts.Type == "" || opts.Type == ProjectLibrary {
+		if err := GenerateDocFile(opts.Name, opts.Description, author, targetDir); err != nil {
+			return err
+		}
+	}
+	if err := GenerateGitignore(targetDir); err != nil {
+		return err
+	}
+	skeleton, err := GenerateProjectFiles(opts.Type, opts.Name, targetDir)
+	if err != nil {
+		return err
+	}
+	gn.record("generate files", strings.Join(skeleton, ", "), nil)
+
+	if err := gn.goH.ModInit(modulePath, targetDir); err != nil {
+		return fmt.Errorf("go mod init failed: %w", err)
+	}
+	gn.record("go mod init "+modulePath, "", nil)
+
+	if opts.CI {
+		// After go mod init: the matrix starts at the go directive
+		err := GenerateCIWorkflow(targetDir, CIWorkflowOptions{Wasm: opts.Type == ProjectWasm})
+		gn.record("generate "+filepath.ToSlash(CIWorkflowPath), "", err)
+		if err != nil {
+			return err
+		}
+	}
+	if name, err := GenerateDependencyBotConfig(targetDir, opts.DependencyBot, opts.DependencySchedule); err != nil {
+		return err
+	} else if name != "" {
+		gn.record("generate "+filepath.ToSlash(name), "", nil)
+	}
+	if opts.Community {
+		err := GenerateCommunityFiles(targetDir, opts.CommunityDir, tmplValues)
+		gn.record("generate community files", strings.Join(CommunityFiles, ", "), err)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Template files override the generated defaults
+	if tmpl != nil {
+		if err := tmpl.Render(targetDir, tmplValues); err != nil {
+			gn.record("render template "+opts.Template, "", err)
+			return fmt.Errorf("template render failed: %w", err)
+		}
+		gn.record("render template "+opts.Template, "", nil)
+	}
+
+	for _, gist := range snippets {
+		err := WriteSnippets(gist, opts.Name, targetDir)
+		gn.record("snippet gist "+gist.Ref(), strings.Join(gist.FileNames(), ", "), err)
+		if err != nil {
+			return err
+		}
+	}
+
+	if opts.Seed != "" {
+		n, err := gn.ImportSeed(opts.Seed, targetDir, opts.Name, modulePath)
+		gn.record("import seed "+opts.Seed, fmt.Sprintf("%d files", n), err)
+		if err != nil {
+			return err
+		}
+	}
+
+	if tmpl != nil {
+		// Hooks run before the initial commit so generated files are included
+		if err := gn.runHooks(tmpl, targetDir, tmplValues); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddRemote creates the remote on the provider and adds it to an existing local project
 func (gn *GoNew) AddRemote(projectPath, visibility, owner string) (string, error) {
+	if gn.dryRun {
+		defer startDryRun()()
+	}
 	// ... Implement AddRemote logic ...
 	// For now, let's implement the basic structure based on spec.
 
@@ -275,12 +902,16 @@ func (gn *GoNew) AddRemote(projectPath, visibility, owner string) (string, error
 		// Look for first non-empty line after title?
 		for i, line := range lines {
 			if strings.HasPrefix(line, "#") {
-				// Title
-				if i+2 < len(lines) {
-					desc := strings.TrimSpace(lines[i+2])
-					if desc != "" {
-						description = desc
+				// Title; the description is the paragraph after it
+				var paragraph []string
+				for _, l := range lines[min(i+2, len(lines)):] {
+					if strings.TrimSpace(l) == "" {
+						break
 					}
+					paragraph = append(paragraph, strings.TrimSpace(l))
+				}
+				if len(paragraph) > 0 {
+					description = strings.Join(paragraph, " ")
 				}
 				break
 			}
@@ -319,7 +950,7 @@ func (gn *GoNew) AddRemote(projectPath, visibility, owner string) (string, error
 
 		ghUser, err = gh.GetCurrentUser()
 		if err != nil {
-			return "", fmt.Errorf("GitHub unavailable: %w", err)
+			return "", fmt.Errorf("%s unavailable: %w", providerLabel(gh), err)
 		}
 	}
 
@@ -329,33 +960,75 @@ func (gn *GoNew) AddRemote(projectPath, visibility, owner string) (string, error
 	}
 	gh := res.(GitHubClient)
 
-	exists, err := gh.RepoExists(ghUser, repoName)
-	if err == nil && exists {
-		return "", fmt.Errorf("repository %s/%s already exists on GitHub", ghUser, repoName)
+	// Visibility of the remote, which a given owner must allow
+	protocol := ProtocolAuto
+	if cfg, err := LoadConfig(targetDir); err == nil {
+		protocol = cfg.String("gonew.remote_protocol", protocol)
+		if visibility == "" {
+			visibility = cfg.String("gonew.visibility", "public")
+		}
 	}
-
-	// Create remote
 	if visibility == "" {
 		visibility = "public"
 	}
-	if err := gh.CreateRepo(ghUser, repoName, description, visibility); err != nil {
+	if err := ValidateRemoteProtocol(protocol); err != nil {
+		return "", err
+	}
+	if checker, ok := gh.(OwnerChecker); ok && owner != "" {
+		if err := checker.CheckOwner(ghUser, visibility); err != nil {
+			return "", err
+		}
+	}
+
+	exists, err := gh.RepoExists(ghUser, repoName)
+	if err != nil {
+		return "", fmt.Errorf("could not check %s/%s: %w", ghUser, repoName, err)
+	}
+	adopted := false
+	if exists {
+		if err := existingRepoError(gh, ghUser, repoName); err != nil {
+			return "", err
+		}
+		adopted = true
+	}
+
+	// Create remote, unless an empty one is adopted
+	if adopted {
+		gn.logger.Info("Adopting empty repository", ghUser+"/"+repoName)
+	} else if creator, ok := gh.(RepoCreator); ok {
+		// The local repository exists: the provider adds origin and pushes
+		if err := creator.CreateFromLocal(targetDir, ghUser, repoName, description, visibility); err != nil {
+			return "", fmt.Errorf("failed to create remote: %w", err)
+		}
+		if _, err := RunCommand("git", "push", "origin", "--tags"); err != nil {
+			return "", fmt.Errorf("failed to push tags: %w", err)
+		}
+		return fmt.Sprintf("✅ Remote added: %s/%s", ghUser, repoName), nil
+	} else if err := gh.CreateRepo(ghUser, repoName, description, visibility); err != nil {
 		return "", fmt.Errorf("failed to create remote: %w", err)
 	}
 
 	// Add remote
-	repoURL := fmt.Sprintf("https://github.com/%s/%s.git", ghUser, repoName)
+	repoURL := remoteRepoURL(gh, ghUser, repoName, protocol)
 	if _, err := RunCommand("git", "remote", "add", "origin", repoURL); err != nil {
 		return "", fmt.Errorf("failed to add remote: %w", err)
 	}
 
 	// Push
-	// We need to push current branch to main
+	// We need to push the current branch
 	// And push tags
 	if err := gn.git.PushWithTags("v0.0.1"); err != nil {
 		// If fails, maybe we need to push plain first?
 		// Or maybe v0.0.1 doesn't exist?
-		// Try pushing HEAD
-		if _, err := RunCommand("git", "push", "-u", "origin", "main"); err != nil {
+		// Try pushing HEAD, or the default branch when it is detached
+		branch, _ := RunCommandSilent("git", "symbolic-ref", "--short", "HEAD")
+		if d, ok := gn.git.(BranchDetector); ok && branch == "" {
+			branch, _ = d.DefaultBranch()
+		}
+		if branch == "" {
+			branch = "main"
+		}
+		if _, err := RunCommand("git", "push", "-u", "origin", branch); err != nil {
 			return "", fmt.Errorf("failed to push: %w", err)
 		}
 		// Try pushing tags if any
@@ -364,3 +1037,61 @@ func (gn *GoNew) AddRemote(projectPath, visibility, owner string) (string, error
 
 	return fmt.Sprintf("✅ Remote added: %s/%s", ghUser, repoName), nil
 }
+
+// printCreatePlan prints the local steps of Create in dry-run mode
+func (gn *GoNew) printCreatePlan(opts NewProjectOptions, targetDir, modulePath string, tmpl *Template, values map[string]string, snippets []*Gist, isRemote bool, owner string) {
+	dryRunf("", "mkdir -p %s", targetDir)
+	dryRunf("", "git init %s", targetDir)
+	dryRunf(targetDir, "git symbolic-ref HEAD refs/heads/%s", opts.DefaultBranch)
+	var skeleton []string
+	for name := range projectTypeFiles(opts.Type, opts.Name) {
+		skeleton = append(skeleton, name)
+	}
+	if opts.Type == "" || opts.Type == ProjectLibrary {
+		skeleton = append(skeleton, "doc.go")
+	}
+	for _, lang := range opts.ReadmeLanguages {
+		skeleton = append(skeleton, "README."+lang+".md")
+	}
+	sort.Strings(skeleton)
+	dryRunf(targetDir, "write README.md, LICENSE, .gitignore, %s", strings.Join(skeleton, ", "))
+	dryRunf(targetDir, "go mod init %s", modulePath)
+	if opts.CI {
+		dryRunf(targetDir, "write %s", filepath.ToSlash(CIWorkflowPath))
+	}
+	if name := dependencyBotFile(opts.DependencyBot); name != "" {
+		dryRunf(targetDir, "write %s", filepath.ToSlash(name))
+	}
+	if opts.Community {
+		dryRunf(targetDir, "write %s", strings.Join(CommunityFiles, ", "))
+		if opts.CommunityDir != "" {
+			dryRunf(targetDir, "render community files %s", opts.CommunityDir)
+		}
+	}
+	if tmpl != nil {
+		dryRunf(targetDir, "render template %s", opts.Template)
+	}
+	for _, gist := range snippets {
+		dryRunf(targetDir, "write %s (gist %s)", strings.Join(gist.FileNames(), ", "), gist.Ref())
+	}
+	if opts.Seed != "" {
+		dryRunf(targetDir, "import seed %s", opts.Seed)
+	}
+	if tmpl != nil {
+		for _, hook := range tmpl.Hooks {
+			dryRunf(targetDir, "%s", hookCommand(hook, values))
+		}
+	}
+	dryRunf(targetDir, "git add .")
+	dryRunf(targetDir, "git commit -m \"Initial commit\"")
+	dryRunf(targetDir, "git tag v0.0.1")
+	if isRemote {
+		res, _ := gn.github.Get()
+		dryRunf(targetDir, "git remote add origin %s", remoteRepoURL(res.(GitHubClient), owner, opts.Name, opts.RemoteProtocol))
+		dryRunf(targetDir, "git push --set-upstream origin %s", opts.DefaultBranch)
+		dryRunf(targetDir, "git push origin v0.0.1")
+		if !opts.Repo.IsZero() {
+			dryRunf("", "configure %s/%s: %s", owner, opts.Name, repoSettingsOf(opts))
+		}
+	}
+}
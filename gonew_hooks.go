@@ -0,0 +1,150 @@
+package devflow
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AuditEntry is one step recorded while creating a project
+type AuditEntry struct {
+	Time   time.Time
+	Step   string
+	Output string
+	Err    error
+}
+
+// String formats the entry as a log line
+func (e AuditEntry) String() string {
+	status := "ok"
+	if e.Err != nil {
+		status = "FAILED: " + firstLine(e.Err.Error())
+	}
+	return fmt.Sprintf("%s %s: %s", e.Time.Format(time.RFC3339), e.Step, status)
+}
+
+// record appends a step to the audit log
+func (gn *GoNew) record(step, output string, err error) {
+	gn.audit = append(gn.audit, AuditEntry{Time: time.Now(), Step: step, Output: output, Err: err})
+}
+
+// Warnings returns the non-fatal problems of the last Create, e.g. a
+// failed hook or push, or repository settings that were not applied
+func (gn *GoNew) Warnings() []Warning {
+	return gn.warnings.List()
+}
+
+// warn records err as a warning of step; it is only logged at debug
+// level, gonew prints the warnings after the summary
+func (gn *GoNew) warn(step string, err error) {
+	if err == nil {
+		return
+	}
+	gn.logger.Debug("Warning:", step+":", err)
+	gn.warnings.Add(step, err)
+}
+
+// AuditLog returns the steps recorded by the last Create
+func (gn *GoNew) AuditLog() []AuditEntry {
+	return gn.audit
+}
+
+// WriteAuditLog writes the audit log, including hook output, to w
+func (gn *GoNew) WriteAuditLog(w io.Writer) error {
+	for _, e := range gn.audit {
+		if _, err := fmt.Fprintln(w, e.String()); err != nil {
+			return err
+		}
+		if e.Output != "" {
+			for _, line := range strings.Split(e.Output, "\n") {
+				fmt.Fprintln(w, "    "+line)
+			}
+		}
+	}
+	return nil
+}
+
+// confirmHooks asks before the hooks of an installed (third-party)
+// template may run, unless opts.TrustTemplate is set. Without a prompt
+// the create fails, so nothing runs unreviewed.
+func (gn *GoNew) confirmHooks(tmpl *Template, opts *NewProjectOptions) error {
+	if len(tmpl.Hooks) == 0 || opts.TrustTemplate || !tmpl.Installed() {
+		return nil
+	}
+	if gn.in == nil {
+		return fmt.Errorf("template %s runs shell hooks: review them in %s and pass -trust", tmpl.Dir, tmpl.Dir)
+	}
+	out := gn.out
+	if out == nil {
+		out = io.Discard
+	}
+	fmt.Fprintf(out, "Template %s runs these commands in the new project:\n", tmpl.Dir)
+	for _, hook := range tmpl.Hooks {
+		fmt.Fprintln(out, "  "+hook)
+	}
+	if answer := strings.ToLower(promptReader(gn.in, out)("Run them? [y/N]: ")); answer != "y" && answer != "yes" {
+		return fmt.Errorf("hooks of template %s not trusted (pass -trust to run them)", tmpl.Dir)
+	}
+	// Saved with the progress so a resume does not ask again
+	opts.TrustTemplate = true
+	return nil
+}
+
+// hookCommand expands the {{name}} placeholders of hook with each value
+// shell-quoted, so a value is one word and never shell syntax
+func hookCommand(hook string, values map[string]string) string {
+	quoted := make(map[string]string, len(values))
+	for k, v := range values {
+		quoted[k] = shellQuote(v)
+	}
+	return ExpandTemplate(hook, quoted)
+}
+
+// shellQuote returns s as a single-quoted sh word
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// runHooks runs the template post-create hooks in targetDir. Template
+// variables are available to hooks as {{name}} placeholders (each one
+// shell word, see hookCommand) and as DEVFLOW_VAR_<NAME> environment
+// variables. A failing hook stops the
+// remaining ones only when the template sets hooks.fatal: true; else it
+// is a warning.
+func (gn *GoNew) runHooks(tmpl *Template, targetDir string, values map[string]string) error {
+	if len(tmpl.Hooks) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(values))
+	for k := range values {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	env := make([]string, 0, len(names))
+	for _, k := range names {
+		env = append(env, "DEVFLOW_VAR_"+strings.ToUpper(k)+"="+values[k])
+	}
+
+	for _, hook := range tmpl.Hooks {
+		command := hookCommand(hook, values)
+		gn.logger.Info("Running hook:", command)
+		out, err := RunShellCommandInDir(targetDir, command, env...)
+		gn.record("hook "+command, out, err)
+		if err == nil {
+			continue
+		}
+		if tmpl.HooksFatal {
+			return fmt.Errorf("post-create hook %q failed: %w", command, err)
+		}
+		if line := firstLine(out); line != "" {
+			// The output says more than "command failed"
+			err = errors.New(line)
+		}
+		gn.warn("hook "+command+" failed", err)
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+package devflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TemplateTopic is the repository topic that publishes a gonew template
+const TemplateTopic = "devflow-template"
+
+// InstalledTemplatesDir holds the templates installed with
+// "gonew templates install", relative to os.UserConfigDir
+const InstalledTemplatesDir = "gonew/templates"
+
+// templateRepoRe matches the owner/repo shorthand of a GitHub template
+var templateRepoRe = regexp.MustCompile(`^[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+$`)
+
+// isTemplateRepo reports whether s is an owner/repo shorthand. "." and
+// ".." are no names: they would leave InstalledTemplatesDir.
+func isTemplateRepo(s string) bool {
+	if !templateRepoRe.MatchString(s) {
+		return false
+	}
+	owner, repo, _ := strings.Cut(s, "/")
+	return !dotSegment(owner) && !dotSegment(repo)
+}
+
+// dotSegment reports whether a path element is "." or ".."
+func dotSegment(s string) bool {
+	return s == "." || s == ".."
+}
+
+// TemplateListing is a template repository found by SearchTemplates
+type TemplateListing struct {
+	Repo        string // owner/repo
+	Description string
+	URL         string
+	Stars       int
+}
+
+// InstalledTemplate is a template cloned into InstalledTemplatesDir,
+// checked out at the tag it is pinned to
+type InstalledTemplate struct {
+	Name string // owner/repo
+	Dir  string
+	Tag  string
+}
+
+// SearchTemplates lists the GitHub repositories with the TemplateTopic
+// topic matching query (all of them when empty), most starred first
+func (gh *GitHub) SearchTemplates(query string) ([]TemplateListing, error) {
+	args := []string{"search", "repos", "--topic", TemplateTopic, "--sort", "stars", "--limit", "30", "--json", "fullName,description,url,stargazersCount"}
+	if query = strings.TrimSpace(query); query != "" {
+		args = append(args, query)
+	}
+	out, err := RunCommandSilent("gh", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search templates: %s", firstLine(out))
+	}
+	var repos []struct {
+		FullName        string `json:"fullName"`
+		Description     string `json:"description"`
+		URL             string `json:"url"`
+		StargazersCount int    `json:"stargazersCount"`
+	}
+	if err := json.Unmarshal([]byte(out), &repos); err != nil {
+		return nil, fmt.Errorf("failed to parse the search results: %w", err)
+	}
+	listings := make([]TemplateListing, len(repos))
+	for i, r := range repos {
+		listings[i] = TemplateListing{Repo: r.FullName, Description: r.Description, URL: r.URL, Stars: r.StargazersCount}
+	}
+	return listings, nil
+}
+
+// installedTemplatesPath returns the directory of the installed templates
+func installedTemplatesPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, InstalledTemplatesDir), nil
+}
+
+// templateSource returns the installed name and clone URL of source: a
+// GitHub owner/repo, or any git URL, whose last two path elements name it
+func templateSource(source string) (name, url string, err error) {
+	if isTemplateRepo(source) {
+		if _, err := os.Stat(source); err != nil {
+			return source, "https://github.com/" + source + ".git", nil
+		}
+	}
+	parts := strings.FieldsFunc(strings.TrimSuffix(source, ".git"), func(r rune) bool { return r == '/' || r == ':' })
+	if len(parts) < 2 {
+		return "", "", fmt.Errorf("template source %q is neither owner/repo nor a git URL", source)
+	}
+	owner, repo := parts[len(parts)-2], parts[len(parts)-1]
+	if dotSegment(owner) || dotSegment(repo) {
+		return "", "", fmt.Errorf("template source %q does not name an owner/repo", source)
+	}
+	return owner + "/" + repo, source, nil
+}
+
+// latestRemoteTag returns the highest vX.Y.Z tag of the repository at url
+func latestRemoteTag(url string) (string, error) {
+	out, err := RunCommandWithEnvInDir("", remoteProbeEnv(), "git", "ls-remote", "--tags", url)
+	if err != nil {
+		return "", fmt.Errorf("git ls-remote %s failed: %s", url, firstLine(out))
+	}
+	latest := ""
+	for _, line := range strings.Split(out, "\n") {
+		_, ref, ok := strings.Cut(strings.TrimSpace(line), "\trefs/tags/")
+		if !ok || strings.HasSuffix(ref, "^{}") || !isReleaseTag(ref) {
+			continue
+		}
+		if latest == "" || CompareVersions(ref, latest) > 0 {
+			latest = ref
+		}
+	}
+	return latest, nil
+}
+
+// isReleaseTag reports whether tag is vX.Y.Z without a pre-release suffix
+func isReleaseTag(tag string) bool {
+	parts := strings.Split(strings.TrimPrefix(tag, "v"), ".")
+	if !strings.HasPrefix(tag, "v") || len(parts) != 3 {
+		return false
+	}
+	for _, p := range parts {
+		if _, err := strconv.Atoi(p); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// InstallTemplate clones the template repository source (owner/repo on
+// GitHub, or a git URL) into InstalledTemplatesDir, pinned to tag or, when
+// empty, to its latest release tag. Install it once; UpdateTemplates moves
+// it to newer tags.
+func InstallTemplate(source, tag string) (*InstalledTemplate, error) {
+	name, url, err := templateSource(source)
+	if err != nil {
+		return nil, err
+	}
+	if url != source {
+		// owner/repo follows gonew.remote_protocol like the project remotes
+		protocol := ProtocolAuto
+		if cfg, err := LoadGlobalConfig(); err == nil {
+			protocol = cfg.String("gonew.remote_protocol", protocol)
+		}
+		url = protocolURL(url, resolveRemoteProtocol(&GitHub{}, protocol))
+	}
+	root, err := installedTemplatesPath()
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(root, filepath.FromSlash(name))
+	if _, err := os.Stat(dir); err == nil {
+		return nil, fmt.Errorf("template %s is already installed in %s (see: gonew templates update)", name, dir)
+	}
+
+	if tag == "" {
+		if tag, err = latestRemoteTag(url); err != nil {
+			return nil, err
+		}
+		if tag == "" {
+			return nil, fmt.Errorf("template %s has no vX.Y.Z tag to pin, install a tag with -tag", name)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return nil, err
+	}
+	if out, err := RunCommand("git", "clone", "--quiet", "--depth", "1", "--branch", tag, "--", url, dir); err != nil {
+		return nil, fmt.Errorf("failed to clone %s at %s: %s", url, tag, firstLine(out))
+	}
+	if _, err := LoadTemplate(dir); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	return &InstalledTemplate{Name: name, Dir: dir, Tag: tag}, nil
+}
+
+// ListInstalledTemplates returns the installed templates sorted by name
+func ListInstalledTemplates() ([]InstalledTemplate, error) {
+	root, err := installedTemplatesPath()
+	if err != nil {
+		return nil, err
+	}
+	owners, err := os.ReadDir(root)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var templates []InstalledTemplate
+	for _, owner := range owners {
+		if !owner.IsDir() {
+			continue
+		}
+		repos, err := os.ReadDir(filepath.Join(root, owner.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, repo := range repos {
+			dir := filepath.Join(root, owner.Name(), repo.Name())
+			if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil || !repo.IsDir() {
+				continue
+			}
+			tag, _ := RunCommandInDir(dir, "git", "describe", "--tags", "--exact-match")
+			templates = append(templates, InstalledTemplate{Name: owner.Name() + "/" + repo.Name(), Dir: dir, Tag: tag})
+		}
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, nil
+}
+
+// UpdateTemplates moves the installed templates named (all when none) to
+// the latest release tag of their origin, returning a line per template
+func UpdateTemplates(names ...string) ([]string, error) {
+	installed, err := ListInstalledTemplates()
+	if err != nil {
+		return nil, err
+	}
+	wanted := make(map[string]bool, len(names))
+	for _, n := range names {
+		wanted[n] = true
+	}
+	var lines []string
+	for _, t := range installed {
+		if len(names) > 0 && !wanted[t.Name] {
+			continue
+		}
+		delete(wanted, t.Name)
+		url, err := RunCommandInDir(t.Dir, "git", "remote", "get-url", "origin")
+		if err != nil {
+			return lines, fmt.Errorf("%s: no origin to update from", t.Name)
+		}
+		latest, err := latestRemoteTag(url)
+		if err != nil {
+			return lines, fmt.Errorf("%s: %w", t.Name, err)
+		}
+		if latest == "" || (t.Tag != "" && CompareVersions(latest, t.Tag) <= 0) {
+			lines = append(lines, fmt.Sprintf("%s: %s is up to date", t.Name, t.Tag))
+			continue
+		}
+		if out, err := RunCommandInDir(t.Dir, "git", "fetch", "--quiet", "--depth", "1", "origin", "tag", latest); err != nil {
+			return lines, fmt.Errorf("%s: failed to fetch %s: %s", t.Name, latest, firstLine(out))
+		}
+		if out, err := RunCommandInDir(t.Dir, "git", "checkout", "--quiet", latest); err != nil {
+			return lines, fmt.Errorf("%s: failed to check out %s: %s", t.Name, latest, firstLine(out))
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s -> %s", t.Name, t.Tag, latest))
+	}
+	for _, n := range names {
+		if wanted[n] {
+			return lines, fmt.Errorf("template %s is not installed", n)
+		}
+	}
+	return lines, nil
+}
+
+// Installed reports whether the template was installed with "gonew
+// templates install", i.e. comes from a third party
+func (t *Template) Installed() bool {
+	root, err := installedTemplatesPath()
+	if err != nil {
+		return false
+	}
+	abs, err := filepath.Abs(t.Dir)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(root, abs)
+	return err == nil && rel != "." && !strings.HasPrefix(rel, "..")
+}
+
+// ResolveTemplatePath returns the directory of template: itself when it
+// is a directory, else the installed template of that owner/repo name
+func ResolveTemplatePath(template string) string {
+	if template == "" {
+		return ""
+	}
+	if info, err := os.Stat(template); err == nil && info.IsDir() {
+		return template
+	}
+	if isTemplateRepo(template) {
+		if root, err := installedTemplatesPath(); err == nil {
+			dir := filepath.Join(root, filepath.FromSlash(template))
+			if info, err := os.Stat(dir); err == nil && info.IsDir() {
+				return dir
+			}
+		}
+	}
+	return template
+}
@@ -0,0 +1,152 @@
+package devflow
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testTemplateRepo creates a bare template repository under root/acme
+// with the README.md of each tag
+func testTemplateRepo(t *testing.T, root string, tags ...string) string {
+	t.Helper()
+	work := filepath.Join(root, "work")
+	remote := filepath.Join(root, "acme", "svc-template.git")
+	run := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %s", args, out)
+		}
+	}
+	run("init", "--bare", remote)
+	run("init", work)
+	run("-C", work, "remote", "add", "origin", remote)
+	for _, tag := range tags {
+		os.WriteFile(filepath.Join(work, "README.md"), []byte("# {{name}} "+tag+"\n"), 0644)
+		run("-C", work, "add", ".")
+		run("-C", work, "commit", "-m", "release "+tag)
+		run("-C", work, "tag", tag)
+	}
+	run("-C", work, "push", "origin", "HEAD", "--tags")
+	return remote
+}
+
+func TestInstallTemplate(t *testing.T) {
+	testResumeEnv(t)
+	root := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(root, "config"))
+	remote := testTemplateRepo(t, root, "v0.1.0", "v0.2.0", "v0.3.0-rc1")
+
+	installed, err := InstallTemplate("file://"+remote, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if installed.Name != "acme/svc-template" || installed.Tag != "v0.2.0" {
+		t.Errorf("unexpected install %+v", installed)
+	}
+	if data, _ := os.ReadFile(filepath.Join(installed.Dir, "README.md")); string(data) != "# {{name}} v0.2.0\n" {
+		t.Errorf("expected the v0.2.0 files, got %q", data)
+	}
+	if _, err := InstallTemplate("file://"+remote, ""); err == nil || !strings.Contains(err.Error(), "already installed") {
+		t.Errorf("expected already installed error, got %v", err)
+	}
+	if got := ResolveTemplatePath("acme/svc-template"); got != installed.Dir {
+		t.Errorf("ResolveTemplatePath = %q", got)
+	}
+
+	// A pinned install moves to the latest release on update
+	os.RemoveAll(installed.Dir)
+	if _, err := InstallTemplate("file://"+remote, "v0.1.0"); err != nil {
+		t.Fatal(err)
+	}
+	list, err := ListInstalledTemplates()
+	if err != nil || len(list) != 1 || list[0].Tag != "v0.1.0" {
+		t.Fatalf("unexpected list %+v, %v", list, err)
+	}
+	lines, err := UpdateTemplates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 1 || lines[0] != "acme/svc-template: v0.1.0 -> v0.2.0" {
+		t.Errorf("unexpected update %q", lines)
+	}
+	if data, _ := os.ReadFile(filepath.Join(installed.Dir, "README.md")); string(data) != "# {{name}} v0.2.0\n" {
+		t.Errorf("expected the v0.2.0 files after update, got %q", data)
+	}
+	if lines, _ := UpdateTemplates("acme/svc-template"); len(lines) != 1 || !strings.HasSuffix(lines[0], "v0.2.0 is up to date") {
+		t.Errorf("unexpected second update %q", lines)
+	}
+	if _, err := UpdateTemplates("acme/other"); err == nil || !strings.Contains(err.Error(), "not installed") {
+		t.Errorf("expected not installed error, got %v", err)
+	}
+}
+
+func TestTemplateSourceRejectsDotSegments(t *testing.T) {
+	for _, source := range []string{"../x", "x/..", "./x", "https://example.com/acme/.."} {
+		if _, _, err := templateSource(source); err == nil {
+			t.Errorf("templateSource(%q): expected an error", source)
+		}
+	}
+	if isTemplateRepo("../x") || !isTemplateRepo("acme/.config") {
+		t.Error("unexpected owner/repo check")
+	}
+	if _, err := InstallTemplate("../x", "v1.0.0"); err == nil {
+		t.Error("expected InstallTemplate to reject ../x")
+	}
+}
+
+func TestGoNewInstalledTemplateHooks(t *testing.T) {
+	tmp := testResumeEnv(t)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmp, ".config"))
+	git, err := NewGit()
+	if err != nil {
+		t.Skip("git not installed")
+	}
+	goHandler, _ := NewGo(git)
+	gn := NewGoNew(git, nil, goHandler)
+
+	root, _ := installedTemplatesPath()
+	tmplDir := filepath.Join(root, "acme", "hooked")
+	os.MkdirAll(tmplDir, 0755)
+	os.WriteFile(filepath.Join(tmplDir, "template.yml"), []byte("hooks:\n  post_create:\n    - touch hooked\n"), 0644)
+	opts := func(name string) NewProjectOptions {
+		return NewProjectOptions{Name: name, Description: "Hooked", LocalOnly: true, Directory: filepath.Join(tmp, name), Template: tmplDir}
+	}
+
+	// Without a prompt, nothing is created
+	if _, err := gn.Create(opts("quiet-lib")); err == nil || !strings.Contains(err.Error(), "-trust") {
+		t.Fatalf("expected the hooks to need -trust, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "quiet-lib")); !os.IsNotExist(err) {
+		t.Error("nothing should be created for an untrusted template")
+	}
+
+	// Declined at the prompt
+	var out strings.Builder
+	gn.SetPrompt(strings.NewReader("n\n"), &out)
+	if _, err := gn.Create(opts("no-lib")); err == nil || !strings.Contains(err.Error(), "not trusted") {
+		t.Fatalf("expected declined hooks to fail, got %v", err)
+	}
+	if !strings.Contains(out.String(), "touch hooked") {
+		t.Errorf("expected the hooks listed, got %q", out.String())
+	}
+
+	// Accepted, or trusted up front
+	gn.SetPrompt(strings.NewReader("y\n"), &out)
+	if _, err := gn.Create(opts("yes-lib")); err != nil {
+		t.Fatal(err)
+	}
+	gn.SetPrompt(nil, nil)
+	trusted := opts("trusted-lib")
+	trusted.TrustTemplate = true
+	if _, err := gn.Create(trusted); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"yes-lib", "trusted-lib"} {
+		if _, err := os.Stat(filepath.Join(tmp, name, "hooked")); err != nil {
+			t.Errorf("expected the hook run in %s: %v", name, err)
+		}
+	}
+}
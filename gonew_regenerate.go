@@ -0,0 +1,91 @@
+package devflow
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Regenerate runs the generators of an existing project at dir: the
+// .gitignore and, as selected by opts, the CI workflow (opts.CI), the
+// dependency bot config (opts.DependencyBot) and the community files
+// (opts.Community). Files that exist with other content are handled by
+// policy (ConflictNew when empty) instead of being overwritten. The files
+// are left uncommitted. It returns the existing files that differed.
+func (gn *GoNew) Regenerate(dir string, opts NewProjectOptions, policy string) ([]FileConflict, error) {
+	if policy == "" {
+		policy = ConflictNew
+	}
+	if err := ValidateConflictPolicy(policy); err != nil {
+		return nil, err
+	}
+	if dir == "" {
+		dir = "."
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	module, err := ReadModule(dir)
+	if err != nil {
+		return nil, fmt.Errorf("not a Go project (go.mod missing)")
+	}
+	modulePath := module.Path
+
+	if gn.dryRun {
+		defer startDryRun()()
+		dryRunf(dir, "generate %s (conflicts: %s)", strings.Join(regenerateFiles(opts), ", "), policy)
+		return nil, nil
+	}
+
+	// The template values come from the module path and the LICENSE file
+	parts := strings.Split(modulePath, "/")
+	if n := len(parts); n > 1 && majorSuffixRe.MatchString(parts[n-1]) {
+		parts = parts[:n-1]
+	}
+	opts.Name = parts[len(parts)-1]
+	owner := ""
+	if len(parts) > 2 {
+		owner = parts[1]
+	}
+	if opts.License == "" {
+		opts.License = DetectLicense(dir)
+	}
+	values := TemplateBuiltins(opts, owner, modulePath)
+
+	return WithConflictPolicy(policy, func() error {
+		if err := GenerateGitignore(dir); err != nil {
+			return err
+		}
+		if opts.CI {
+			if err := GenerateCIWorkflow(dir, CIWorkflowOptions{Wasm: opts.Type == ProjectWasm}); err != nil {
+				return err
+			}
+		}
+		if _, err := GenerateDependencyBotConfig(dir, opts.DependencyBot, opts.DependencySchedule); err != nil {
+			return err
+		}
+		if opts.Community {
+			return GenerateCommunityFiles(dir, opts.CommunityDir, values)
+		}
+		return nil
+	})
+}
+
+// regenerateFiles lists the files Regenerate writes with opts
+func regenerateFiles(opts NewProjectOptions) []string {
+	files := []string{".gitignore"}
+	if opts.CI {
+		files = append(files, filepath.ToSlash(CIWorkflowPath))
+	}
+	switch opts.DependencyBot {
+	case DependencyBotDependabot:
+		files = append(files, ".github/dependabot.yml")
+	case DependencyBotRenovate:
+		files = append(files, "renovate.json")
+	}
+	if opts.Community {
+		files = append(files, CommunityFiles...)
+	}
+	return files
+}
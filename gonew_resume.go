@@ -0,0 +1,220 @@
+package devflow
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CreateStateDir holds the progress of unfinished Create runs, relative
+// to os.UserCacheDir
+const CreateStateDir = "devflow/create"
+
+// Create steps, in the order they run
+const (
+	CreateStepRemote    = "remote"    // Repository created on the provider
+	CreateStepFiles     = "files"     // Directory, git init, generated and template files, hooks
+	CreateStepCommitted = "committed" // Initial commit
+	CreateStepTagged    = "tagged"    // Tag v0.0.1
+	CreateStepPushed    = "pushed"    // Remote added, commit and tag pushed
+)
+
+// CreateState is the saved progress of a Create run, kept until the
+// project is complete so "gonew resume <name>" can finish it
+type CreateState struct {
+	Options NewProjectOptions `json:"options"` // Template variables already resolved
+	Dir     string            `json:"dir"`
+	Owner   string            `json:"owner"`
+	Module  string            `json:"module"`
+	Steps   []string          `json:"steps"` // Completed steps
+	Updated time.Time         `json:"updated"`
+
+	RemoteCreated bool `json:"remote_created,omitempty"` // The remote was made by gonew, not adopted (rollback may delete it)
+	DirCreated    bool `json:"dir_created,omitempty"`    // Dir was made by this create, so a resume or rollback may remove it
+}
+
+// Done reports whether step completed
+func (s *CreateState) Done(step string) bool {
+	for _, done := range s.Steps {
+		if done == step {
+			return true
+		}
+	}
+	return false
+}
+
+// complete records step as done and saves the state
+func (s *CreateState) complete(step string) error {
+	if !s.Done(step) {
+		s.Steps = append(s.Steps, step)
+	}
+	return s.save()
+}
+
+// save writes the state file (nothing in dry-run mode)
+func (s *CreateState) save() error {
+	if DryRunActive() {
+		return nil
+	}
+	path, err := createStatePath(s.Dir)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	s.Updated = time.Now().UTC()
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// createStatePath returns the state file of the create of directory dir:
+// named after the project, unique to its absolute path
+func createStatePath(dir string) (string, error) {
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(abs))
+	return filepath.Join(cache, CreateStateDir, filepath.Base(abs)+"-"+hex.EncodeToString(sum[:6])+".json"), nil
+}
+
+// readCreateState reads the state file path, nil when there is none
+func readCreateState(path string) (*CreateState, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s CreateState
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &s, nil
+}
+
+// LoadCreateState returns the saved progress of an unfinished Create of
+// the project directory dir, or nil when there is none
+func LoadCreateState(dir string) (*CreateState, error) {
+	path, err := createStatePath(dir)
+	if err != nil {
+		return nil, err
+	}
+	return readCreateState(path)
+}
+
+// FindCreateState returns the unfinished Create of ref, a project
+// directory or name, or nil when there is none. A name shared by the
+// unfinished creates of several directories fails: pass the directory.
+func FindCreateState(ref string) (*CreateState, error) {
+	if s, err := LoadCreateState(ref); err != nil || s != nil {
+		return s, err
+	}
+	states, err := ListCreateStates()
+	if err != nil {
+		return nil, err
+	}
+	var found []*CreateState
+	var dirs []string
+	for _, s := range states {
+		if s.Options.Name == ref {
+			found = append(found, s)
+			dirs = append(dirs, s.Dir)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, nil
+	case 1:
+		return found[0], nil
+	}
+	return nil, fmt.Errorf("several unfinished creates of %s (%s): pass the directory", ref, strings.Join(dirs, ", "))
+}
+
+// ListCreateStates returns the unfinished Create runs, most recent first
+func ListCreateStates() ([]*CreateState, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(filepath.Join(dir, CreateStateDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var states []*CreateState
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name(), ".json") {
+			if s, err := readCreateState(filepath.Join(dir, CreateStateDir, e.Name())); err == nil && s != nil {
+				states = append(states, s)
+			}
+		}
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Updated.After(states[j].Updated) })
+	return states, nil
+}
+
+// DiscardCreateState forgets the progress of an unfinished Create of the
+// project directory dir. The directory and remote repository are left as
+// they are.
+func DiscardCreateState(dir string) error {
+	path, err := createStatePath(dir)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// String returns e.g. "my-lib (~/Dev/my-lib): remote, files done; next: committed"
+func (s *CreateState) String() string {
+	done := "nothing done"
+	if len(s.Steps) > 0 {
+		done = strings.Join(s.Steps, ", ") + " done"
+	}
+	steps := []string{CreateStepRemote, CreateStepFiles, CreateStepCommitted, CreateStepTagged, CreateStepPushed}
+	if s.Options.LocalOnly {
+		steps = steps[1:4]
+	}
+	next := ""
+	for _, step := range steps {
+		if !s.Done(step) {
+			next = "; next: " + step
+			break
+		}
+	}
+	return fmt.Sprintf("%s (%s): %s%s", s.Options.Name, s.Dir, done, next)
+}
+
+// Resume finishes an unfinished Create of ref, a project name or
+// directory, from its saved progress: completed steps are skipped, and
+// partially generated files are created again from scratch
+func (gn *GoNew) Resume(ref string) (string, error) {
+	state, err := FindCreateState(ref)
+	if err != nil {
+		return "", err
+	}
+	if state == nil {
+		return "", fmt.Errorf("no unfinished create of %s", ref)
+	}
+	return gn.create(state.Options, state)
+}
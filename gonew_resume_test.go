@@ -0,0 +1,403 @@
+package devflow
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testProvider is a RepoProvider whose repositories are local bare repos
+type testProvider struct {
+	dir     string
+	created int
+	exists  bool // RepoExists reports every repository as taken
+}
+
+func (p *testProvider) SetLog(fn func(...any))                      {}
+func (p *testProvider) GetCurrentUser() (string, error)             { return "tester", nil }
+func (p *testProvider) RepoExists(owner, name string) (bool, error) { return p.exists, nil }
+func (p *testProvider) CreateRepo(owner, name, description, visibility string) error {
+	p.created++
+	return nil
+}
+func (p *testProvider) DeleteRepo(owner, name string) error     { return nil }
+func (p *testProvider) IsNetworkError(err error) bool           { return false }
+func (p *testProvider) GetHelpfulErrorMessage(err error) string { return err.Error() }
+func (p *testProvider) Name() string                            { return ProviderGitHub }
+func (p *testProvider) Host() string                            { return "github.com" }
+func (p *testProvider) RepoURL(owner, name string) string {
+	return "file://" + filepath.Join(p.dir, name+".git")
+}
+
+// testResumeEnv isolates HOME (git identity) and the cache dir of the states
+func testResumeEnv(t *testing.T) string {
+	t.Helper()
+	tmp := t.TempDir()
+	t.Setenv("HOME", tmp)
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(tmp, ".cache"))
+	os.WriteFile(filepath.Join(tmp, ".gitconfig"), []byte("[user]\n\tname = TestUser\n\temail = test@example.com\n"), 0644)
+	return tmp
+}
+
+func TestCreateStateRoundTrip(t *testing.T) {
+	testResumeEnv(t)
+
+	if s, err := LoadCreateState("/src/lib"); s != nil || err != nil {
+		t.Fatalf("expected no state, got %v, %v", s, err)
+	}
+	s := &CreateState{Options: NewProjectOptions{Name: "lib", TemplateVars: map[string]string{"team": "core"}}, Dir: "/src/lib"}
+	if err := s.complete(CreateStepRemote); err != nil {
+		t.Fatal(err)
+	}
+	s.complete(CreateStepFiles)
+
+	loaded, err := LoadCreateState("/src/lib")
+	if err != nil || loaded == nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if !loaded.Done(CreateStepFiles) || loaded.Done(CreateStepCommitted) || loaded.Options.TemplateVars["team"] != "core" {
+		t.Errorf("unexpected state: %+v", loaded)
+	}
+	if got := loaded.String(); got != "lib (/src/lib): remote, files done; next: committed" {
+		t.Errorf("String() = %q", got)
+	}
+	if states, _ := ListCreateStates(); len(states) != 1 {
+		t.Errorf("expected 1 unfinished create, got %d", len(states))
+	}
+
+	// Another directory of the same name has a state of its own
+	other := &CreateState{Options: NewProjectOptions{Name: "lib"}, Dir: "/work/lib"}
+	other.complete(CreateStepRemote)
+	if s, _ := LoadCreateState("/src/lib"); s == nil || !s.Done(CreateStepFiles) {
+		t.Errorf("expected the state of /src/lib kept, got %v", s)
+	}
+	if _, err := FindCreateState("lib"); err == nil || !strings.Contains(err.Error(), "pass the directory") {
+		t.Errorf("expected an ambiguous name to fail, got %v", err)
+	}
+	if s, err := FindCreateState("/work/lib"); err != nil || s == nil || s.Dir != "/work/lib" {
+		t.Errorf("expected the state of /work/lib, got %v, %v", s, err)
+	}
+	DiscardCreateState("/work/lib")
+
+	DiscardCreateState("/src/lib")
+	if s, _ := LoadCreateState("/src/lib"); s != nil {
+		t.Error("state should be discarded")
+	}
+}
+
+func TestGoNewCreateLeavesNoState(t *testing.T) {
+	tmp := testResumeEnv(t)
+	git, err := NewGit()
+	if err != nil {
+		t.Skip("git not installed")
+	}
+	goHandler, _ := NewGo(git)
+	gn := NewGoNew(git, nil, goHandler)
+
+	dir := filepath.Join(tmp, "done-lib")
+	if _, err := gn.Create(NewProjectOptions{Name: "done-lib", Description: "Done", LocalOnly: true, Directory: dir}); err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := LoadCreateState(dir); s != nil {
+		t.Errorf("completed create should leave no state, got %s", s)
+	}
+}
+
+func TestGoNewResumeFinishesTag(t *testing.T) {
+	tmp := testResumeEnv(t)
+	git, err := NewGit()
+	if err != nil {
+		t.Skip("git not installed")
+	}
+	goHandler, _ := NewGo(git)
+	gn := NewGoNew(git, nil, goHandler)
+
+	// A local-only create that stopped after the initial commit
+	dir := filepath.Join(tmp, "half-lib")
+	opts := NewProjectOptions{Name: "half-lib", Description: "Half", LocalOnly: true, Directory: dir}
+	if _, err := gn.Create(opts); err != nil {
+		t.Fatal(err)
+	}
+	exec.Command("git", "-C", dir, "tag", "-d", "v0.0.1").Run()
+	state := &CreateState{Options: opts, Dir: dir, Owner: "testuser", Module: "github.com/testuser/half-lib"}
+	state.complete(CreateStepFiles)
+	state.complete(CreateStepCommitted)
+
+	if _, err := gn.Create(opts); err == nil || !strings.Contains(err.Error(), "gonew resume half-lib") {
+		t.Errorf("expected resume hint for the existing directory, got %v", err)
+	}
+
+	if _, err := gn.Resume("half-lib"); err != nil {
+		t.Fatalf("resume failed: %v", err)
+	}
+	if out, err := exec.Command("git", "-C", dir, "tag").Output(); err != nil || strings.TrimSpace(string(out)) != "v0.0.1" {
+		t.Errorf("expected tag v0.0.1, got %q", out)
+	}
+	if out, _ := exec.Command("git", "-C", dir, "rev-list", "--count", "HEAD").Output(); strings.TrimSpace(string(out)) != "1" {
+		t.Errorf("commit should not be repeated, got %s commits", out)
+	}
+	if s, _ := LoadCreateState(dir); s != nil {
+		t.Error("state should be removed after resume")
+	}
+	if _, err := gn.Resume("half-lib"); err == nil {
+		t.Error("expected error resuming a finished create")
+	}
+}
+
+func TestGoNewResumeKeepsForeignDirectory(t *testing.T) {
+	tmp := testResumeEnv(t)
+	git, err := NewGit()
+	if err != nil {
+		t.Skip("git not installed")
+	}
+	goHandler, _ := NewGo(git)
+	gn := NewGoNew(git, nil, goHandler)
+
+	// A stale state names a directory that its run never made
+	dir := filepath.Join(tmp, "stale-lib")
+	os.MkdirAll(dir, 0755)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("mine"), 0644)
+	opts := NewProjectOptions{Name: "stale-lib", Description: "Stale", LocalOnly: true, Directory: dir}
+	state := &CreateState{Options: opts, Dir: dir, Owner: "testuser", Module: "github.com/testuser/stale-lib"}
+	state.save()
+
+	gn.SetRollback(RollbackLocal)
+	if _, err := gn.Resume("stale-lib"); err == nil || !strings.Contains(err.Error(), "was not made by this create") {
+		t.Fatalf("expected the foreign directory to stop the resume, got %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "notes.txt")); err != nil || string(data) != "mine" {
+		t.Errorf("expected the directory kept, got %q, %v", data, err)
+	}
+}
+
+func TestGoNewResumeRetriesPush(t *testing.T) {
+	tmp := testResumeEnv(t)
+	git, err := NewGit()
+	if err != nil {
+		t.Skip("git not installed")
+	}
+	goHandler, _ := NewGo(git)
+	remotes := filepath.Join(tmp, "remotes")
+	provider := &testProvider{dir: remotes}
+	future := NewResolvedFuture(provider)
+	gn := NewGoNew(git, future, goHandler)
+
+	// The remote URL has no repository yet, so the push fails
+	dir := filepath.Join(tmp, "push-lib")
+	summary, err := gn.Create(NewProjectOptions{Name: "push-lib", Description: "Push", Directory: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(summary, "push failed") || !strings.Contains(summary, "gonew resume push-lib") {
+		t.Errorf("expected push failure with resume hint, got %q", summary)
+	}
+	state, _ := LoadCreateState(dir)
+	if state == nil || !state.Done(CreateStepTagged) || state.Done(CreateStepPushed) {
+		t.Fatalf("unexpected state: %v", state)
+	}
+
+	if err := exec.Command("git", "init", "--bare", filepath.Join(remotes, "push-lib.git")).Run(); err != nil {
+		t.Fatal(err)
+	}
+	summary, err = gn.Resume("push-lib")
+	if err != nil {
+		t.Fatalf("resume failed: %v", err)
+	}
+	if !strings.Contains(summary, "[local+remote]") || provider.created != 1 {
+		t.Errorf("unexpected resume: %q, %d remotes created", summary, provider.created)
+	}
+	if out, _ := exec.Command("git", "-C", filepath.Join(remotes, "push-lib.git"), "tag").Output(); strings.TrimSpace(string(out)) != "v0.0.1" {
+		t.Errorf("expected v0.0.1 pushed, got %q", out)
+	}
+	if s, _ := LoadCreateState(dir); s != nil {
+		t.Error("state should be removed after the push")
+	}
+}
+
+func TestGoNewCreateRemoteExists(t *testing.T) {
+	tmp := testResumeEnv(t)
+	git, err := NewGit()
+	if err != nil {
+		t.Skip("git not installed")
+	}
+	goHandler, _ := NewGo(git)
+	provider := &testProvider{dir: filepath.Join(tmp, "remotes"), exists: true}
+	gn := NewGoNew(git, NewResolvedFuture(provider), goHandler)
+
+	// The remote is checked before anything is written: not even the
+	// parent directories of the project are made
+	dir := filepath.Join(tmp, "src", "taken-lib")
+	if _, err := gn.Create(NewProjectOptions{Name: "taken-lib", Description: "Taken", Directory: dir}); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("expected already exists error, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Dir(dir)); !os.IsNotExist(err) {
+		t.Error("nothing should be created for a taken repository")
+	}
+	if s, _ := LoadCreateState(dir); s != nil {
+		t.Errorf("failed create should leave no state, got %s", s)
+	}
+	if provider.created != 0 {
+		t.Error("no remote should be created")
+	}
+}
+
+// testOrgProvider lets the tester create repositories in its orgs only
+type testOrgProvider struct {
+	*testProvider
+	orgs map[string]bool
+}
+
+func (p *testOrgProvider) CheckOwner(owner, visibility string) error {
+	if owner != "tester" && !p.orgs[owner] {
+		return fmt.Errorf("%s is not an organization tester is a member of", owner)
+	}
+	return nil
+}
+
+func TestGoNewCreateOrgOwner(t *testing.T) {
+	tmp := testResumeEnv(t)
+	git, err := NewGit()
+	if err != nil {
+		t.Skip("git not installed")
+	}
+	goHandler, _ := NewGo(git)
+	provider := &testOrgProvider{testProvider: &testProvider{dir: filepath.Join(tmp, "remotes")}, orgs: map[string]bool{"acme": true}}
+	gn := NewGoNew(git, NewResolvedFuture(provider), goHandler)
+
+	dir := filepath.Join(tmp, "org-lib")
+	if _, err := gn.Create(NewProjectOptions{Name: "org-lib", Description: "Org", Owner: "acme", Directory: dir}); err != nil {
+		t.Fatal(err)
+	}
+	if mod, _ := os.ReadFile(filepath.Join(dir, "go.mod")); !strings.Contains(string(mod), "module github.com/acme/org-lib") {
+		t.Errorf("expected the module under the organization, got:\n%s", mod)
+	}
+	if provider.created != 1 {
+		t.Errorf("expected the remote created, got %d", provider.created)
+	}
+
+	// An organization the user cannot create in fails before any file
+	dir = filepath.Join(tmp, "foreign", "foreign-lib")
+	if _, err := gn.Create(NewProjectOptions{Name: "foreign-lib", Description: "Foreign", Owner: "other", Directory: dir}); err == nil || !strings.Contains(err.Error(), "not an organization tester is a member of") {
+		t.Fatalf("expected a membership error, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Dir(dir)); !os.IsNotExist(err) {
+		t.Error("nothing should be created for the foreign owner")
+	}
+	if provider.created != 1 {
+		t.Error("no remote should be created for the foreign owner")
+	}
+}
+
+// testEmptyProvider also tells whether its existing repositories are empty
+type testEmptyProvider struct {
+	*testProvider
+	empty bool
+}
+
+func (p *testEmptyProvider) RepoIsEmpty(owner, name string) (bool, error) { return p.empty, nil }
+
+func TestGoNewCreateAdoptsEmptyRemote(t *testing.T) {
+	tmp := testResumeEnv(t)
+	git, err := NewGit()
+	if err != nil {
+		t.Skip("git not installed")
+	}
+	goHandler, _ := NewGo(git)
+	remotes := filepath.Join(tmp, "remotes")
+	if err := exec.Command("git", "init", "--bare", filepath.Join(remotes, "empty-lib.git")).Run(); err != nil {
+		t.Fatal(err)
+	}
+	provider := &testEmptyProvider{testProvider: &testProvider{dir: remotes, exists: true}, empty: true}
+	gn := NewGoNew(git, NewResolvedFuture(provider), goHandler)
+
+	dir := filepath.Join(tmp, "empty-lib")
+	summary, err := gn.Create(NewProjectOptions{Name: "empty-lib", Description: "Empty", Directory: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(summary, "[local+remote]") || !strings.Contains(summary, "adopted") || provider.created != 0 {
+		t.Errorf("expected the empty remote adopted, got %q, %d created", summary, provider.created)
+	}
+	if out, _ := exec.Command("git", "-C", filepath.Join(remotes, "empty-lib.git"), "tag").Output(); strings.TrimSpace(string(out)) != "v0.0.1" {
+		t.Errorf("expected v0.0.1 pushed to the adopted remote, got %q", out)
+	}
+
+	provider.empty = false
+	dir = filepath.Join(tmp, "full-lib")
+	if _, err := gn.Create(NewProjectOptions{Name: "full-lib", Description: "Full", Directory: dir}); err == nil || !strings.Contains(err.Error(), "is not empty") {
+		t.Errorf("expected not empty error, got %v", err)
+	}
+}
+
+// testCreatorProvider creates repositories like gh repo create: empty, or
+// from a local repository that it adds as origin and pushes
+type testCreatorProvider struct {
+	*testProvider
+	remoteOnly int
+	fromLocal  []string
+}
+
+func (p *testCreatorProvider) CreateRemoteOnly(owner, name, description, visibility string) error {
+	p.remoteOnly++
+	return nil
+}
+
+func (p *testCreatorProvider) CreateFromLocal(path, owner, name, description, visibility string) error {
+	p.fromLocal = append(p.fromLocal, path)
+	if _, err := RunCommandInDir(path, "git", "remote", "add", "origin", p.RepoURL(owner, name)); err != nil {
+		return err
+	}
+	_, err := RunCommandInDir(path, "git", "push", "-u", "origin", "HEAD")
+	return err
+}
+
+func TestGoNewCreateStrategy(t *testing.T) {
+	tmp := testResumeEnv(t)
+	git, err := NewGit()
+	if err != nil {
+		t.Skip("git not installed")
+	}
+	goHandler, _ := NewGo(git)
+	remotes := filepath.Join(tmp, "remotes")
+	for _, name := range []string{"new-lib", "old-lib"} {
+		if err := exec.Command("git", "init", "--bare", filepath.Join(remotes, name+".git")).Run(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	provider := &testCreatorProvider{testProvider: &testProvider{dir: remotes}}
+	gn := NewGoNew(git, NewResolvedFuture(provider), goHandler)
+
+	// Create makes the local repository itself: the remote is created empty
+	if _, err := gn.Create(NewProjectOptions{Name: "new-lib", Description: "New", Directory: filepath.Join(tmp, "new-lib")}); err != nil {
+		t.Fatal(err)
+	}
+	if provider.remoteOnly != 1 || provider.created != 0 || len(provider.fromLocal) != 0 {
+		t.Errorf("Create should use CreateRemoteOnly, got %+v", provider)
+	}
+
+	// add-remote creates the remote from the existing local repository
+	dir := filepath.Join(tmp, "old-lib")
+	os.MkdirAll(dir, 0755)
+	os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/old-lib\n\ngo 1.20\n"), 0644)
+	for _, args := range [][]string{{"init"}, {"add", "."}, {"commit", "-m", "init"}, {"tag", "v0.0.1"}} {
+		if out, err := RunCommandInDir(dir, "git", args...); err != nil {
+			t.Fatal(out, err)
+		}
+	}
+	summary, err := gn.AddRemote(dir, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(provider.fromLocal) != 1 || provider.fromLocal[0] != dir || provider.remoteOnly != 1 || provider.created != 0 {
+		t.Errorf("AddRemote should use CreateFromLocal, got %+v", provider)
+	}
+	if !strings.Contains(summary, "Remote added") {
+		t.Errorf("unexpected summary %q", summary)
+	}
+	if out, _ := exec.Command("git", "-C", filepath.Join(remotes, "old-lib.git"), "tag").Output(); strings.TrimSpace(string(out)) != "v0.0.1" {
+		t.Errorf("expected v0.0.1 pushed, got %q", out)
+	}
+}
@@ -0,0 +1,75 @@
+package devflow
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Rollback modes of a failed Create (SetRollback, gonew.rollback config)
+const (
+	RollbackOff    = "off"    // Keep the files, remote and progress for gonew resume
+	RollbackLocal  = "local"  // Remove the project directory and the progress
+	RollbackRemote = "remote" // Also delete the repository the create made on the provider
+)
+
+// ValidateRollbackMode checks mode is RollbackOff, RollbackLocal or
+// RollbackRemote; empty means the gonew.rollback config
+func ValidateRollbackMode(mode string) error {
+	switch mode {
+	case "", RollbackOff, RollbackLocal, RollbackRemote:
+		return nil
+	}
+	return fmt.Errorf("invalid rollback mode %q (want %s, %s or %s)", mode, RollbackOff, RollbackLocal, RollbackRemote)
+}
+
+// SetRollback sets what a failed Create or Resume undoes instead of
+// keeping its progress for Resume (empty: the gonew.rollback config). An
+// adopted remote is never deleted.
+func (gn *GoNew) SetRollback(mode string) error {
+	if err := ValidateRollbackMode(mode); err != nil {
+		return err
+	}
+	gn.rollback = mode
+	return nil
+}
+
+// rollbackCreate undoes the failed create of state and returns cause with
+// what was undone. The progress is kept when the directory could not be
+// removed, so Resume can still finish it.
+func (gn *GoNew) rollbackCreate(cause error, mode string, state *CreateState) error {
+	var undone, notes []string
+	if state.DirCreated {
+		if err := os.RemoveAll(state.Dir); err != nil {
+			gn.record("rollback: remove "+state.Dir, "", err)
+			return fmt.Errorf("%w\nRollback failed: %v", cause, err)
+		}
+		gn.record("rollback: remove "+state.Dir, "", nil)
+		undone = append(undone, "removed "+state.Dir)
+	}
+
+	if state.RemoteCreated {
+		repo := state.Owner + "/" + state.Options.Name
+		if mode == RollbackRemote {
+			res, err := gn.github.Get()
+			if err == nil {
+				err = res.(GitHubClient).DeleteRepo(state.Owner, state.Options.Name)
+			}
+			gn.record("rollback: delete remote "+repo, "", err)
+			if err != nil {
+				notes = append(notes, fmt.Sprintf("remote %s not deleted: %v", repo, err))
+			} else {
+				undone = append(undone, "deleted remote "+repo)
+			}
+		} else {
+			notes = append(notes, fmt.Sprintf("remote %s kept (an empty remote is adopted by the next create)", repo))
+		}
+	}
+
+	gn.warn("progress not discarded", DiscardCreateState(state.Dir))
+	rolledBack := strings.Join(append(undone, notes...), "; ")
+	if rolledBack == "" {
+		rolledBack = "nothing to undo"
+	}
+	return fmt.Errorf("%w\nRolled back: %s", cause, rolledBack)
+}
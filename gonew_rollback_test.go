@@ -0,0 +1,75 @@
+package devflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testDeletingProvider records the repositories deleted by a rollback
+type testDeletingProvider struct {
+	*testProvider
+	deleted []string
+}
+
+func (p *testDeletingProvider) DeleteRepo(owner, name string) error {
+	p.deleted = append(p.deleted, owner+"/"+name)
+	return nil
+}
+
+func TestGoNewRollback(t *testing.T) {
+	tmp := testResumeEnv(t)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmp, ".config"))
+	git, err := NewGit()
+	if err != nil {
+		t.Skip("git not installed")
+	}
+	goHandler, _ := NewGo(git)
+	provider := &testDeletingProvider{testProvider: &testProvider{dir: filepath.Join(tmp, "remotes")}}
+	gn := NewGoNew(git, NewResolvedFuture(provider), goHandler)
+
+	// A fatal hook fails the create after the remote was made
+	tmplDir := t.TempDir()
+	os.WriteFile(filepath.Join(tmplDir, "template.yml"), []byte("hooks:\n  post_create:\n    - exit 3\n  fatal: true\n"), 0644)
+	opts := NewProjectOptions{Name: "undo-lib", Description: "Undo", Owner: "tester", Directory: filepath.Join(tmp, "undo-lib"), Template: tmplDir}
+
+	if err := gn.SetRollback("all"); err == nil {
+		t.Error("expected invalid rollback mode error")
+	}
+
+	// Default: kept for gonew resume
+	if _, err := gn.Create(opts); err == nil || !strings.Contains(err.Error(), "gonew resume undo-lib") {
+		t.Fatalf("expected resume hint, got %v", err)
+	}
+	state, _ := LoadCreateState(opts.Directory)
+	if state == nil || !state.RemoteCreated {
+		t.Fatalf("expected the created remote in the progress, got %v", state)
+	}
+
+	// remote: a resume failing again removes everything this create made
+	gn.SetRollback(RollbackRemote)
+	_, err = gn.Resume("undo-lib")
+	if err == nil || !strings.Contains(err.Error(), "Rolled back: removed "+opts.Directory+"; deleted remote tester/undo-lib") || strings.Contains(err.Error(), "gonew resume") {
+		t.Fatalf("expected rollback report without resume hint, got %v", err)
+	}
+	if _, err := os.Stat(opts.Directory); !os.IsNotExist(err) {
+		t.Error("project directory should be removed")
+	}
+	if s, _ := LoadCreateState(opts.Directory); s != nil {
+		t.Error("progress should be discarded")
+	}
+	if len(provider.deleted) != 1 || provider.deleted[0] != "tester/undo-lib" {
+		t.Errorf("expected the remote deleted, got %v", provider.deleted)
+	}
+
+	// local: the remote is kept and reported
+	provider.deleted = nil
+	gn.SetRollback(RollbackLocal)
+	if _, err := gn.Create(opts); err == nil || !strings.Contains(err.Error(), "remote tester/undo-lib kept") {
+		t.Errorf("expected kept remote in the report, got %v", err)
+	}
+	if len(provider.deleted) != 0 {
+		t.Errorf("local rollback should keep the remote, deleted %v", provider.deleted)
+	}
+}
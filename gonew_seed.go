@@ -0,0 +1,162 @@
+package devflow
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// packageClauseRe matches the package clause of a Go file
+var packageClauseRe = regexp.MustCompile(`(?m)^package\s+([A-Za-z_][A-Za-z0-9_]*)`)
+
+// ImportSeed copies an existing unversioned code directory into the new
+// project at targetDir:
+//   - .gitignore entries are merged into the generated .gitignore
+//   - package clauses of root-level non-main Go files are renamed to the
+//     project package
+//   - if the seed has a go.mod, imports of its module path are rewritten to
+//     modulePath and its direct requirements are added to the new go.mod
+//
+// Files from the seed overwrite the scaffolded ones. Returns the number of
+// files copied.
+func (gn *GoNew) ImportSeed(seedDir, targetDir, repoName, modulePath string) (int, error) {
+	info, err := os.Stat(seedDir)
+	if err != nil {
+		return 0, fmt.Errorf("seed: %w", err)
+	}
+	if !info.IsDir() {
+		return 0, fmt.Errorf("seed: %s is not a directory", seedDir)
+	}
+	if _, err := os.Stat(filepath.Join(seedDir, ".git")); err == nil {
+		return 0, fmt.Errorf("seed: %s is already a git repository", seedDir)
+	}
+
+	oldModule := ""
+	if module, err := ReadModule(seedDir); err == nil {
+		oldModule = module.Path
+	}
+	pkg := projectPackageName(repoName)
+
+	copied := 0
+	err = filepath.WalkDir(seedDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(seedDir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		dest := filepath.Join(targetDir, rel)
+		if d.IsDir() {
+			return os.MkdirAll(dest, 0755)
+		}
+
+		switch rel {
+		case "go.mod":
+			return nil // requirements merged below
+		case ".gitignore":
+			return mergeGitignore(path, dest)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if strings.HasSuffix(rel, ".go") {
+			src := string(data)
+			if filepath.Dir(rel) == "." {
+				src = renamePackage(src, pkg)
+			}
+			if oldModule != "" && oldModule != modulePath {
+				src = rewriteImports(src, oldModule, modulePath)
+			}
+			data = []byte(src)
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		copied++
+		return os.WriteFile(dest, data, info.Mode().Perm())
+	})
+	if err != nil {
+		return copied, err
+	}
+
+	if oldModule != "" {
+		deps, err := DirectDependencies(seedDir)
+		if err != nil {
+			return copied, err
+		}
+		for _, dep := range deps {
+			if _, err := RunCommandInDir(targetDir, "go", "mod", "edit", "-require="+dep.Path+"@"+dep.Version); err != nil {
+				return copied, fmt.Errorf("seed: add requirement %s: %w", dep.Path, err)
+			}
+		}
+	}
+
+	gn.logger.Info("Seed imported:", copied, "files from", seedDir)
+	return copied, nil
+}
+
+// renamePackage replaces the package clause unless it is main.
+// External test packages keep their _test suffix.
+func renamePackage(src, pkg string) string {
+	m := packageClauseRe.FindStringSubmatchIndex(src)
+	if m == nil {
+		return src
+	}
+	name := src[m[2]:m[3]]
+	if name == "main" {
+		return src
+	}
+	newName := pkg
+	if strings.HasSuffix(name, "_test") {
+		newName += "_test"
+	}
+	return src[:m[2]] + newName + src[m[3]:]
+}
+
+// rewriteImports replaces quoted import paths of oldModule (and its
+// subpackages) with newModule
+func rewriteImports(src, oldModule, newModule string) string {
+	src = strings.ReplaceAll(src, `"`+oldModule+`"`, `"`+newModule+`"`)
+	return strings.ReplaceAll(src, `"`+oldModule+`/`, `"`+newModule+`/`)
+}
+
+// mergeGitignore appends the seed entries missing from dest
+func mergeGitignore(seedPath, dest string) error {
+	seed, err := os.ReadFile(seedPath)
+	if err != nil {
+		return err
+	}
+	current, _ := os.ReadFile(dest)
+
+	existing := make(map[string]bool)
+	for _, line := range strings.Split(string(current), "\n") {
+		existing[strings.TrimSpace(line)] = true
+	}
+
+	var add []string
+	for _, line := range strings.Split(string(seed), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || existing[line] {
+			continue
+		}
+		existing[line] = true
+		add = append(add, line)
+	}
+	if len(add) == 0 {
+		return nil
+	}
+
+	content := string(current)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	content += "\n# From seed\n" + strings.Join(add, "\n") + "\n"
+	return os.WriteFile(dest, []byte(content), 0644)
+}
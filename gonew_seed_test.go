@@ -0,0 +1,93 @@
+package devflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenamePackage(t *testing.T) {
+	tests := []struct{ src, want string }{
+		{"// doc\npackage proto\n\nfunc A() {}\n", "// doc\npackage mytool\n\nfunc A() {}\n"},
+		{"package proto_test\n", "package mytool_test\n"},
+		{"package main\n", "package main\n"},
+		{"no clause", "no clause"},
+	}
+	for _, tt := range tests {
+		if got := renamePackage(tt.src, "mytool"); got != tt.want {
+			t.Errorf("renamePackage(%q) = %q; want %q", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestMergeGitignore(t *testing.T) {
+	dir := t.TempDir()
+	seed := filepath.Join(dir, "seed")
+	dest := filepath.Join(dir, "dest")
+	os.WriteFile(dest, []byte("*.test\n*.out\n"), 0644)
+	os.WriteFile(seed, []byte("# local\n*.out\nnode_modules/\n.env\n"), 0644)
+
+	if err := mergeGitignore(seed, dest); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := os.ReadFile(dest)
+	if string(got) != "*.test\n*.out\n\n# From seed\nnode_modules/\n.env\n" {
+		t.Errorf("unexpected merge result:\n%s", got)
+	}
+}
+
+func TestGoNewCreateWithSeed(t *testing.T) {
+	tmpDir := t.TempDir()
+	defer testChdir(t, tmpDir)()
+
+	git, err := NewGit()
+	if err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("HOME", tmpDir)
+	os.WriteFile(filepath.Join(tmpDir, ".gitconfig"), []byte("[user]\n\tname = TestUser\n\temail = test@example.com\n"), 0644)
+
+	seed := filepath.Join(tmpDir, "prototype")
+	os.MkdirAll(filepath.Join(seed, "internal", "util"), 0755)
+	os.WriteFile(filepath.Join(seed, "go.mod"), []byte("module example.com/proto\n\ngo 1.21\n\nrequire github.com/some/dep v1.2.3\n"), 0644)
+	os.WriteFile(filepath.Join(seed, "proto.go"), []byte("package proto\n\nimport _ \"example.com/proto/internal/util\"\n"), 0644)
+	os.WriteFile(filepath.Join(seed, "internal", "util", "util.go"), []byte("package util\n"), 0644)
+	os.WriteFile(filepath.Join(seed, ".gitignore"), []byte("data/\n"), 0644)
+
+	goHandler, _ := NewGo(git)
+	gn := NewGoNew(git, nil, goHandler)
+	opts := NewProjectOptions{
+		Name:        "my-tool",
+		Description: "Seeded project",
+		Owner:       "org",
+		LocalOnly:   true,
+		Directory:   filepath.Join(tmpDir, "my-tool"),
+		Seed:        seed,
+	}
+	if _, err := gn.Create(opts); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	target := opts.Directory
+	src, _ := os.ReadFile(filepath.Join(target, "proto.go"))
+	if !strings.HasPrefix(string(src), "package mytool") || !strings.Contains(string(src), `"github.com/org/my-tool/internal/util"`) {
+		t.Errorf("package or imports not rewritten:\n%s", src)
+	}
+	if util, _ := os.ReadFile(filepath.Join(target, "internal", "util", "util.go")); string(util) != "package util\n" {
+		t.Errorf("subpackage should keep its name, got %q", util)
+	}
+	mod, _ := os.ReadFile(filepath.Join(target, "go.mod"))
+	if !strings.Contains(string(mod), "module github.com/org/my-tool") || !strings.Contains(string(mod), "github.com/some/dep v1.2.3") {
+		t.Errorf("unexpected go.mod:\n%s", mod)
+	}
+	if ignore, _ := os.ReadFile(filepath.Join(target, ".gitignore")); !strings.Contains(string(ignore), "\ndata/\n") {
+		t.Errorf("seed .gitignore not merged:\n%s", ignore)
+	}
+
+	os.Chdir(target)
+	files, _ := RunCommandSilent("git", "ls-files")
+	if !strings.Contains(files, "internal/util/util.go") {
+		t.Errorf("seed files should be in the initial commit, got:\n%s", files)
+	}
+}
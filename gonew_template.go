@@ -0,0 +1,215 @@
+package devflow
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// TemplateManifestNames are the manifest files of a project template, in lookup order
+var TemplateManifestNames = []string{"template.yml", "template.yaml"}
+
+// templatePlaceholderRe matches {{name}} placeholders. Go template actions
+// such as {{.Name}} are left untouched.
+var templatePlaceholderRe = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// TemplateVar is a placeholder declared in template.yml
+type TemplateVar struct {
+	Name     string
+	Prompt   string // Question shown when asking interactively (default: Name)
+	Env      string // Environment variable (default: DEVFLOW_VAR_<NAME>)
+	Default  string
+	Required bool
+}
+
+// Template is a project scaffold directory. Files are copied into the new
+// project with {{name}} placeholders replaced in contents and paths.
+type Template struct {
+	Dir  string
+	Vars []TemplateVar
+
+	Hooks      []string // Post-create shell commands run in the new project
+	HooksFatal bool     // Abort Create when a hook fails (default: report only)
+}
+
+// LoadTemplate reads the template in dir. The manifest is optional; without
+// it only the built-in variables are available.
+func LoadTemplate(dir string) (*Template, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("template: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("template: %s is not a directory", dir)
+	}
+
+	t := &Template{Dir: dir}
+	for _, name := range TemplateManifestNames {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		c, err := LoadConfigFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range c.Keys("variables") {
+			key := "variables." + name
+			t.Vars = append(t.Vars, TemplateVar{
+				Name:     name,
+				Prompt:   c.String(key+".prompt", ""),
+				Env:      c.String(key+".env", ""),
+				Default:  c.String(key+".default", ""),
+				Required: c.Bool(key+".required", false),
+			})
+		}
+		t.Hooks = c.List("hooks.post_create")
+		t.HooksFatal = c.Bool("hooks.fatal", false)
+		break
+	}
+	return t, nil
+}
+
+// envName returns the environment variable that provides the value
+func (v TemplateVar) envName() string {
+	if v.Env != "" {
+		return v.Env
+	}
+	return "DEVFLOW_VAR_" + strings.ToUpper(v.Name)
+}
+
+// TemplateBuiltins returns the variables every template can use
+func TemplateBuiltins(opts NewProjectOptions, owner, modulePath string) map[string]string {
+	return map[string]string{
+		"name":        opts.Name,
+		"description": opts.Description,
+		"owner":       owner,
+		"module":      modulePath,
+		"license":     opts.License,
+		"year":        fmt.Sprint(time.Now().Year()),
+	}
+}
+
+// Resolve computes the value of every declared variable. Sources, in order:
+// preset values (e.g. -var flags), environment, global config
+// (template.vars.<name>), interactive prompt (when in is not nil) and the
+// declared default. Missing required variables are reported together.
+func (t *Template) Resolve(preset map[string]string, global *Config, in io.Reader, out io.Writer) (map[string]string, error) {
+	values := make(map[string]string)
+	var reader *bufio.Reader
+	if in != nil {
+		reader = bufio.NewReader(in)
+	}
+	if out == nil {
+		out = io.Discard
+	}
+	if global == nil {
+		global = NewConfig()
+	}
+
+	var missing []string
+	for _, v := range t.Vars {
+		value, ok := preset[v.Name]
+		if !ok {
+			value, ok = os.LookupEnv(v.envName())
+		}
+		if !ok && global.Has("template.vars."+v.Name) {
+			value, ok = global.String("template.vars."+v.Name, ""), true
+		}
+		if !ok && reader != nil {
+			prompt := v.Prompt
+			if prompt == "" {
+				prompt = v.Name
+			}
+			if v.Default != "" {
+				fmt.Fprintf(out, "%s [%s]: ", prompt, v.Default)
+			} else {
+				fmt.Fprintf(out, "%s: ", prompt)
+			}
+			line, _ := reader.ReadString('\n')
+			if line = strings.TrimSpace(line); line != "" {
+				value, ok = line, true
+			}
+		}
+		if !ok || value == "" {
+			value = v.Default
+		}
+		if value == "" && v.Required {
+			missing = append(missing, fmt.Sprintf("%s (set %s or -var %s=...)", v.Name, v.envName(), v.Name))
+			continue
+		}
+		values[v.Name] = value
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("template: missing required variables: %s", strings.Join(missing, ", "))
+	}
+	return values, nil
+}
+
+// Render copies the template into targetDir replacing placeholders.
+// Existing files (e.g. the generated README) are overwritten.
+func (t *Template) Render(targetDir string, values map[string]string) error {
+	return filepath.WalkDir(t.Dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(t.Dir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() && filepath.Dir(rel) == "." && isTemplateManifest(rel) {
+			return nil
+		}
+
+		dest := filepath.Join(targetDir, ExpandTemplate(rel, values))
+		if d.IsDir() {
+			return os.MkdirAll(dest, 0755)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		// Binary files are copied as-is
+		if !bytes.ContainsRune(data, 0) {
+			data = []byte(ExpandTemplate(string(data), values))
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(dest, data, info.Mode().Perm())
+	})
+}
+
+// ExpandTemplate replaces {{name}} placeholders with values. Unknown
+// placeholders are kept so files can contain other template syntaxes.
+func ExpandTemplate(s string, values map[string]string) string {
+	return templatePlaceholderRe.ReplaceAllStringFunc(s, func(m string) string {
+		name := templatePlaceholderRe.FindStringSubmatch(m)[1]
+		if v, ok := values[name]; ok {
+			return v
+		}
+		return m
+	})
+}
+
+func isTemplateManifest(name string) bool {
+	for _, n := range TemplateManifestNames {
+		if name == n {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,224 @@
+package devflow
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testTemplateDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	manifest := `variables:
+  team:
+    prompt: Owning team
+    env: TEAM_NAME
+    required: true
+  port:
+    default: "8080"
+  region:
+    required: true
+`
+	os.WriteFile(filepath.Join(dir, "template.yml"), []byte(manifest), 0644)
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("# {{name}}\n\nTeam {{ team }} on port {{port}}, docs {{.Name}} {{unknown}}\n"), 0644)
+	os.MkdirAll(filepath.Join(dir, "cmd", "{{name}}"), 0755)
+	os.WriteFile(filepath.Join(dir, "cmd", "{{name}}", "main.go"), []byte("package main // {{module}}\n"), 0644)
+	return dir
+}
+
+func TestLoadTemplate(t *testing.T) {
+	tmpl, err := LoadTemplate(testTemplateDir(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tmpl.Vars) != 3 {
+		t.Fatalf("expected 3 variables, got %+v", tmpl.Vars)
+	}
+	team := tmpl.Vars[0]
+	if team.Name != "team" || team.Prompt != "Owning team" || team.Env != "TEAM_NAME" || !team.Required {
+		t.Errorf("unexpected team variable: %+v", team)
+	}
+	if tmpl.Vars[1].Name != "port" || tmpl.Vars[1].Default != "8080" {
+		t.Errorf("unexpected port variable: %+v", tmpl.Vars[1])
+	}
+
+	if _, err := LoadTemplate(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("expected error for missing template dir")
+	}
+}
+
+func TestTemplateResolve(t *testing.T) {
+	tmpl, _ := LoadTemplate(testTemplateDir(t))
+	t.Setenv("TEAM_NAME", "")
+	os.Unsetenv("TEAM_NAME")
+	t.Setenv("DEVFLOW_VAR_REGION", "")
+	os.Unsetenv("DEVFLOW_VAR_REGION")
+
+	// Missing required variables are reported together
+	_, err := tmpl.Resolve(nil, nil, nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "team") || !strings.Contains(err.Error(), "region") {
+		t.Fatalf("expected missing team and region, got %v", err)
+	}
+
+	// Environment and global config
+	t.Setenv("TEAM_NAME", "core")
+	global, _ := ParseConfig("template:\n  vars:\n    region: eu\n")
+	values, err := tmpl.Resolve(nil, global, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values["team"] != "core" || values["region"] != "eu" || values["port"] != "8080" {
+		t.Errorf("unexpected values: %v", values)
+	}
+
+	// Preset wins over environment
+	values, _ = tmpl.Resolve(map[string]string{"team": "web"}, global, nil, nil)
+	if values["team"] != "web" {
+		t.Errorf("expected preset value, got %q", values["team"])
+	}
+
+	// Prompts for what is left; empty answer keeps the default
+	os.Unsetenv("TEAM_NAME")
+	var out bytes.Buffer
+	values, err = tmpl.Resolve(nil, global, strings.NewReader("payments\n\n"), &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values["team"] != "payments" || values["port"] != "8080" {
+		t.Errorf("unexpected prompted values: %v", values)
+	}
+	if !strings.Contains(out.String(), "Owning team: ") || !strings.Contains(out.String(), "port [8080]: ") {
+		t.Errorf("unexpected prompts: %q", out.String())
+	}
+}
+
+func TestTemplateRender(t *testing.T) {
+	tmpl, _ := LoadTemplate(testTemplateDir(t))
+	target := t.TempDir()
+	values := map[string]string{"name": "svc", "team": "core", "port": "9000", "module": "github.com/org/svc"}
+
+	if err := tmpl.Render(target, values); err != nil {
+		t.Fatal(err)
+	}
+
+	readme, _ := os.ReadFile(filepath.Join(target, "README.md"))
+	if string(readme) != "# svc\n\nTeam core on port 9000, docs {{.Name}} {{unknown}}\n" {
+		t.Errorf("unexpected README: %q", readme)
+	}
+	main, err := os.ReadFile(filepath.Join(target, "cmd", "svc", "main.go"))
+	if err != nil || !strings.Contains(string(main), "github.com/org/svc") {
+		t.Errorf("expected placeholder in path and content, got %q %v", main, err)
+	}
+	if _, err := os.Stat(filepath.Join(target, "template.yml")); err == nil {
+		t.Error("manifest should not be copied")
+	}
+}
+
+func TestGoNewCreateWithTemplateHooks(t *testing.T) {
+	tmpDir := t.TempDir()
+	defer testChdir(t, tmpDir)()
+
+	git, err := NewGit()
+	if err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("HOME", tmpDir)
+	os.WriteFile(filepath.Join(tmpDir, ".gitconfig"), []byte("[user]\n\tname = TestUser\n\temail = test@example.com\n"), 0644)
+
+	tmplDir := t.TempDir()
+	manifest := `variables:
+  team:
+    default: core
+hooks:
+  post_create:
+    - echo {{team}} $DEVFLOW_VAR_NAME > hook.txt
+    - exit 3
+`
+	os.WriteFile(filepath.Join(tmplDir, "template.yml"), []byte(manifest), 0644)
+
+	goHandler, _ := NewGo(git)
+	gn := NewGoNew(git, nil, goHandler)
+	opts := NewProjectOptions{
+		Name:        "hooked",
+		Description: "Template with hooks",
+		Owner:       "org",
+		LocalOnly:   true,
+		Directory:   filepath.Join(tmpDir, "hooked"),
+		Template:    tmplDir,
+	}
+
+	// Failing hooks are reported but not fatal by default
+	summary, err := gn.Create(opts)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if w := gn.Warnings(); len(w) != 1 || w[0].Step != "hook exit 3 failed" || strings.Contains(summary, "exit 3") {
+		t.Errorf("expected the hook failure as a warning, got %v (%q)", w, summary)
+	}
+
+	out, _ := os.ReadFile(filepath.Join(opts.Directory, "hook.txt"))
+	if strings.TrimSpace(string(out)) != "core hooked" {
+		t.Errorf("unexpected hook output: %q", out)
+	}
+	os.Chdir(opts.Directory)
+	if files, _ := RunCommandSilent("git", "ls-files"); !strings.Contains(files, "hook.txt") {
+		t.Errorf("hook output should be in the initial commit, got %q", files)
+	}
+	os.Chdir(tmpDir)
+
+	var failedSteps []string
+	for _, e := range gn.AuditLog() {
+		if e.Err != nil {
+			failedSteps = append(failedSteps, e.Step)
+		}
+	}
+	if len(failedSteps) != 1 || failedSteps[0] != "hook exit 3" {
+		t.Errorf("expected only the failing hook in the audit log, got %v", failedSteps)
+	}
+	var log bytes.Buffer
+	gn.WriteAuditLog(&log)
+	if !strings.Contains(log.String(), "initial commit: ok") {
+		t.Errorf("audit log missing commit step:\n%s", log.String())
+	}
+
+	// hooks.fatal aborts Create
+	os.WriteFile(filepath.Join(tmplDir, "template.yml"), []byte(manifest+"  fatal: true\n"), 0644)
+	opts.Name = "hooked-fatal"
+	opts.Directory = filepath.Join(tmpDir, "hooked-fatal")
+	if _, err := gn.Create(opts); err == nil || !strings.Contains(err.Error(), "exit 3") {
+		t.Errorf("expected fatal hook error, got %v", err)
+	}
+}
+
+func TestGoNewHookQuotesValues(t *testing.T) {
+	if got := hookCommand("say {{team}}", map[string]string{"team": "it's"}); got != `say 'it'\''s'` {
+		t.Errorf("hookCommand = %q", got)
+	}
+
+	tmp := testResumeEnv(t)
+	git, err := NewGit()
+	if err != nil {
+		t.Skip("git not installed")
+	}
+	tmplDir := t.TempDir()
+	os.WriteFile(filepath.Join(tmplDir, "template.yml"), []byte("variables:\n  team: {}\nhooks:\n  post_create:\n    - echo {{team}} > hook.txt\n  fatal: true\n"), 0644)
+	goHandler, _ := NewGo(git)
+	gn := NewGoNew(git, nil, goHandler)
+
+	team := `core; touch pwned $(touch pwned2) "q'`
+	dir := filepath.Join(tmp, "quoted")
+	opts := NewProjectOptions{Name: "quoted", Description: "Quoted", LocalOnly: true, Directory: dir, Template: tmplDir, TemplateVars: map[string]string{"team": team}}
+	if _, err := gn.Create(opts); err != nil {
+		t.Fatal(err)
+	}
+	if out, _ := os.ReadFile(filepath.Join(dir, "hook.txt")); strings.TrimSpace(string(out)) != team {
+		t.Errorf("expected the value as text, got %q", out)
+	}
+	for _, name := range []string{"pwned", "pwned2"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			t.Errorf("the value ran as shell: %s exists", name)
+		}
+	}
+}
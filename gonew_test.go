@@ -25,6 +25,9 @@ func TestProjectTemplates(t *testing.T) {
 	if err := ValidateDescription(""); err == nil {
 		t.Error("ValidateDescription should fail for empty desc")
 	}
+	if err := ValidateDescription(strings.Repeat("long ", 100)); err != nil {
+		t.Errorf("descriptions over the GitHub limit are sanitized, not rejected: %v", err)
+	}
 
 	// Test GenerateREADME
 	if err := GenerateREADME("my-repo", "desc", tmpDir); err != nil {
@@ -63,6 +66,20 @@ func New() *MyRepo {
 	}
 }
 
+func TestSanitizeDescription(t *testing.T) {
+	if got := SanitizeDescription("A tool\r\n\tfor  tabs\x00 and\u200b bidi\u202e"); got != "A tool for tabs and bidi" {
+		t.Errorf("unexpected sanitized description: %q", got)
+	}
+	long := strings.Repeat("word ", 100)
+	got := SanitizeDescription(long)
+	if n := len([]rune(got)); n > MaxRepoDescription || !strings.HasSuffix(got, "word…") {
+		t.Errorf("expected a cut at a word of at most %d chars, got %d: %q", MaxRepoDescription, n, got)
+	}
+	if got := SanitizeDescription(strings.Repeat("é", 400)); len([]rune(got)) != MaxRepoDescription {
+		t.Errorf("expected %d runes, got %d", MaxRepoDescription, len([]rune(got)))
+	}
+}
+
 func TestKebabToCamel(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -211,3 +228,106 @@ func TestGoNewWithCustomOwner(t *testing.T) {
 		t.Errorf("go.mod should contain '%s', got:\n%s", expectedModulePath, string(goModContent))
 	}
 }
+
+func TestGoNewConfigDefaults(t *testing.T) {
+	tmp := testResumeEnv(t)
+	git, err := NewGit()
+	if err != nil {
+		t.Skip("git not installed")
+	}
+	workspace := filepath.Join(tmp, "work")
+	os.MkdirAll(workspace, 0755)
+	os.WriteFile(filepath.Join(workspace, ".devflow.yaml"), []byte("gonew:\n  license: Apache-2.0\n  module_prefix: go.example.com/work/\n"), 0644)
+
+	goHandler, _ := NewGo(git)
+	gn := NewGoNew(git, nil, goHandler)
+	dir := filepath.Join(workspace, "conf-lib")
+	if _, err := gn.Create(NewProjectOptions{Name: "conf-lib", Description: "Configured", LocalOnly: true, Directory: dir}); err != nil {
+		t.Fatal(err)
+	}
+	if mod, _ := os.ReadFile(filepath.Join(dir, "go.mod")); !strings.HasPrefix(string(mod), "module go.example.com/work/conf-lib\n") {
+		t.Errorf("module prefix not applied: %q", mod)
+	}
+	if license, _ := os.ReadFile(filepath.Join(dir, "LICENSE")); !strings.Contains(string(license), "Apache License") {
+		t.Error("license default not applied")
+	}
+
+	// Options win over the config
+	dir = filepath.Join(workspace, "mit-lib")
+	if _, err := gn.Create(NewProjectOptions{Name: "mit-lib", Description: "MIT", License: "MIT", LocalOnly: true, Directory: dir}); err != nil {
+		t.Fatal(err)
+	}
+	if license, _ := os.ReadFile(filepath.Join(dir, "LICENSE")); !strings.Contains(string(license), "MIT License") {
+		t.Error("-license should override gonew.license")
+	}
+}
+
+func TestGoNewDefaultBranch(t *testing.T) {
+	tmp := testResumeEnv(t)
+	git, err := NewGit()
+	if err != nil {
+		t.Skip("git not installed")
+	}
+	goHandler, _ := NewGo(git)
+	gn := NewGoNew(git, nil, goHandler)
+
+	dir := filepath.Join(tmp, "trunk-lib")
+	if _, err := gn.Create(NewProjectOptions{Name: "trunk-lib", Description: "Trunk", LocalOnly: true, Directory: dir, DefaultBranch: "trunk"}); err != nil {
+		t.Fatal(err)
+	}
+	if out, _ := RunCommandInDir(dir, "git", "symbolic-ref", "--short", "HEAD"); out != "trunk" {
+		t.Errorf("expected branch trunk, got %q", out)
+	}
+	if _, err := gn.Create(NewProjectOptions{Name: "bad-lib", Description: "Bad", LocalOnly: true, Directory: filepath.Join(tmp, "bad-lib"), DefaultBranch: "a..b"}); err == nil {
+		t.Error("expected error for an invalid branch name")
+	}
+
+	// Without -branch: gonew.default_branch, else git's init.defaultBranch
+	branchOf := func(name string) string {
+		dir := filepath.Join(tmp, name)
+		if _, err := gn.Create(NewProjectOptions{Name: name, Description: "Lib", LocalOnly: true, Directory: dir}); err != nil {
+			t.Fatal(err)
+		}
+		out, _ := RunCommandInDir(dir, "git", "symbolic-ref", "--short", "HEAD")
+		return out
+	}
+	if b := branchOf("main-lib"); b != "main" {
+		t.Errorf("expected branch main, got %q", b)
+	}
+	RunCommand("git", "config", "--global", "init.defaultBranch", "master")
+	if b := branchOf("master-lib"); b != "master" {
+		t.Errorf("expected init.defaultBranch master, got %q", b)
+	}
+	os.WriteFile(filepath.Join(tmp, ".devflow.yaml"), []byte("gonew:\n  default_branch: develop\n"), 0644)
+	if b := branchOf("develop-lib"); b != "develop" {
+		t.Errorf("expected gonew.default_branch develop, got %q", b)
+	}
+}
+
+func TestGoNewInitialCommitTrailers(t *testing.T) {
+	tmp := testResumeEnv(t)
+	git, err := NewGit()
+	if err != nil {
+		t.Skip("git not installed")
+	}
+	goHandler, _ := NewGo(git)
+	gn := NewGoNew(git, nil, goHandler)
+
+	dir := filepath.Join(tmp, "paired-lib")
+	opts := NewProjectOptions{Name: "paired-lib", Description: "Paired", LocalOnly: true, Directory: dir, SignOff: true, CoAuthors: []string{"Jane Doe <jane@example.com>"}}
+	if _, err := gn.Create(opts); err != nil {
+		t.Fatal(err)
+	}
+	body, _ := RunCommandInDir(dir, "git", "log", "-1", "--format=%B")
+	if want := "Initial commit\n\nCo-authored-by: Jane Doe <jane@example.com>\nSigned-off-by: TestUser <test@example.com>"; body != want {
+		t.Errorf("unexpected message %q, want %q", body, want)
+	}
+
+	opts = NewProjectOptions{Name: "bad-lib", Description: "Bad", LocalOnly: true, Directory: filepath.Join(tmp, "bad-lib"), CoAuthors: []string{"jane"}}
+	if _, err := gn.Create(opts); err == nil {
+		t.Error("expected a co-author without email to fail")
+	}
+	if _, err := os.Stat(opts.Directory); err == nil {
+		t.Error("expected nothing created for an invalid co-author")
+	}
+}
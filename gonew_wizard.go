@@ -110,7 +110,23 @@ func (gn *GoNew) GetSteps() []*wizard.Step {
 			},
 		},
 
-		// Step 7: Create Execution
+		// Step 7: Project Type
+		{
+			LabelText: "Project Type (library/cli/wasm/web)",
+			DefaultFn: func(ctx *context.Context) string { return ProjectLibrary },
+			OnInputFn: func(in string, ctx *context.Context) (bool, error) {
+				if in == "" {
+					return false, nil
+				}
+				if err := ValidateProjectType(in); err != nil {
+					return false, err
+				}
+				err := ctx.Set("project_type", in)
+				return true, err
+			},
+		},
+
+		// Step 8: Create Execution
 		{
 			LabelText: "Create Project",
 			DefaultFn: func(ctx *context.Context) string { return "Press Enter to Create" },
@@ -121,6 +137,7 @@ func (gn *GoNew) GetSteps() []*wizard.Step {
 				desc := ctx.Value("project_desc")
 				vis := ctx.Value("project_vis")
 				lic := ctx.Value("project_lic")
+				typ := ctx.Value("project_type")
 
 				opts := NewProjectOptions{
 					Name:        name,
@@ -129,17 +146,18 @@ func (gn *GoNew) GetSteps() []*wizard.Step {
 					Description: desc,
 					Visibility:  vis,
 					License:     lic,
+					Type:        typ,
 					LocalOnly:   gn.github == nil, // Skip remote if no GitHub handler
 				}
 
-				gn.log("[...", "Creating project")
+				gn.logger.Info("[...", "Creating project")
 				summary, err := gn.Create(opts)
 				if err != nil {
-					gn.log("...]", "Error: "+err.Error())
+					gn.logger.Info("...]", "Error: "+err.Error())
 					return false, err
 				}
 
-				gn.log("...]", summary)
+				gn.logger.Info("...]", summary)
 				err = ctx.Set("creation_summary", summary)
 				return true, err
 			},
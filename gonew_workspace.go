@@ -0,0 +1,97 @@
+package devflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CreateWorkspace creates the directory dir with a go.work using the
+// existing modules (relative to the working directory or absolute) and a
+// README.md listing them. README.md is kept when dir already has one.
+func (gn *GoNew) CreateWorkspace(dir string, modules []string) (_ string, err error) {
+	if gn.dryRun {
+		defer startDryRun()()
+	}
+	defer trackMetric("gonew-workspace")(&err)
+
+	if len(modules) == 0 {
+		return "", fmt.Errorf("a workspace needs at least one module")
+	}
+	if strings.HasPrefix(dir, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			dir = filepath.Join(home, dir[2:])
+		}
+	}
+	workDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(filepath.Join(workDir, "go.work")); err == nil {
+		return "", fmt.Errorf("%s already has a go.work, add modules with go work use", workDir)
+	}
+
+	var uses, paths []string
+	seen := make(map[string]bool)
+	for _, m := range modules {
+		modDir, err := filepath.Abs(m)
+		if err != nil {
+			return "", err
+		}
+		module, err := ReadModule(modDir)
+		if err != nil {
+			return "", fmt.Errorf("module %s: %w", m, err)
+		}
+		path := module.Path
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		rel, err := filepath.Rel(workDir, modDir)
+		if err != nil {
+			return "", err
+		}
+		if rel = filepath.ToSlash(rel); !strings.HasPrefix(rel, "../") && rel != ".." {
+			rel = "./" + rel
+		}
+		uses = append(uses, rel)
+		paths = append(paths, path)
+	}
+
+	if DryRunActive() {
+		dryRunf(filepath.Dir(workDir), "mkdir %s", filepath.Base(workDir))
+	} else if err := os.MkdirAll(workDir, 0755); err != nil {
+		return "", err
+	}
+	if err := gn.goH.WorkUse(workDir, uses...); err != nil {
+		return "", err
+	}
+	gn.logger.Info("Added", len(uses), "modules to", filepath.Join(workDir, "go.work"))
+
+	readme := filepath.Join(workDir, "README.md")
+	if _, err := os.Stat(readme); os.IsNotExist(err) {
+		if err := writeUnlessDryRun(readme, []byte(workspaceREADME(filepath.Base(workDir), uses, paths))); err != nil {
+			return "", err
+		}
+	}
+
+	summary := fmt.Sprintf("✅ Created workspace: %s (%d modules)", filepath.Base(workDir), len(uses))
+	for i, use := range uses {
+		summary += fmt.Sprintf("\n   %s (%s)", use, paths[i])
+	}
+	return summary, nil
+}
+
+// workspaceREADME is the shared README.md of a workspace
+func workspaceREADME(name string, uses, paths []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\nGo workspace of:\n\n| Module | Directory |\n|--------|-----------|\n", name)
+	for i, use := range uses {
+		fmt.Fprintf(&b, "| `%s` | [%s](%s) |\n", paths[i], use, use)
+	}
+	b.WriteString("\n## Development\n\n`go.work` makes the modules build against each other's checkouts:\n\n")
+	b.WriteString("```bash\ngo work use ../other   # Add a module\ngo work sync           # Write the workspace versions back to each go.mod\n```\n\n")
+	b.WriteString("`gopush -workspace` pushes the modules in dependency order.\n")
+	return b.String()
+}
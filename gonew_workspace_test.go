@@ -0,0 +1,62 @@
+package devflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGoNewCreateWorkspace(t *testing.T) {
+	root := t.TempDir()
+	defer testChdir(t, root)()
+	for _, m := range []string{"api", "web"} {
+		os.MkdirAll(m, 0755)
+		os.WriteFile(filepath.Join(m, "go.mod"), []byte("module example.com/"+m+"\n\ngo 1.20\n"), 0644)
+	}
+	git := &MockGitClient{}
+	g, _ := NewGo(git)
+	gn := NewGoNew(git, nil, g)
+
+	// Dry run writes nothing
+	out := testDryRunOutput(t)
+	gn.SetDryRun(true)
+	if _, err := gn.CreateWorkspace("shop", []string{"api", "web"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat("shop"); !os.IsNotExist(err) {
+		t.Error("dry run should not create the workspace")
+	}
+	for _, want := range []string{"mkdir shop", "go work init", "go work use ../api ../web", "write README.md"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("dry run should print %q, got:\n%s", want, out)
+		}
+	}
+
+	gn.SetDryRun(false)
+	summary, err := gn.CreateWorkspace("shop", []string{"api", "./web", "api"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(summary, "shop (2 modules)") || !strings.Contains(summary, "../web (example.com/web)") {
+		t.Errorf("unexpected summary %q", summary)
+	}
+	dirs, err := FindWorkspaceModules("shop")
+	if err != nil || strings.Join(dirs, ",") != "../api,../web" {
+		t.Errorf("go.work uses = %v, %v", dirs, err)
+	}
+	readme, _ := os.ReadFile(filepath.Join("shop", "README.md"))
+	if !strings.HasPrefix(string(readme), "# shop\n") || !strings.Contains(string(readme), "| `example.com/api` | [../api](../api) |") {
+		t.Errorf("unexpected README:\n%s", readme)
+	}
+	if err := g.WorkSync("shop"); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := gn.CreateWorkspace("shop", []string{"api"}); err == nil || !strings.Contains(err.Error(), "already has a go.work") {
+		t.Errorf("expected existing go.work error, got %v", err)
+	}
+	if _, err := gn.CreateWorkspace("other", []string{"missing"}); err == nil || !strings.Contains(err.Error(), "module missing") {
+		t.Errorf("expected missing module error, got %v", err)
+	}
+}
@@ -3,26 +3,105 @@ package devflow
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
 	"regexp"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 )
 
+// TestOptions configures a test run
+type TestOptions struct {
+	CI    bool     // Write a Markdown report to $GITHUB_STEP_SUMMARY (GitHub Actions)
+	SARIF string   // Write vet, staticcheck and secret-scan findings to this SARIF file
+	Race  RaceMode // Race detector mode (default: test.race config, then auto)
+	// Budget bounds the wall time of all test runs (default: test.budget
+	// config; 0 means none). Packages still running are stopped and the
+	// slowest ones reported.
+	Budget time.Duration
+	Leaks  bool // Fail on goroutines left running after the tests (also leaks.enabled config)
+	// Format of the report written to Output: FormatText (default, none),
+	// FormatJSON or FormatJUnit. The structured formats run go test -json.
+	Format string
+	Output io.Writer // Default os.Stdout
+	// CoverageThreshold is the minimum coverage of every package in percent
+	// (default: coverage.threshold config; coverage.packages overrides it
+	// per package). 0 means none.
+	CoverageThreshold float64
+	Vuln              bool // Run govulncheck in parallel with the tests (also vuln.enabled config)
+	Lint              bool // Run staticcheck or golangci-lint alongside vet (also lint.enabled config)
+}
+
 // Test executes the test suite for the project
 func (g *Go) Test() (string, error) {
+	return g.TestWithOptions(TestOptions{})
+}
+
+// TestWithOptions executes the test suite with the given options
+func (g *Go) TestWithOptions(opts TestOptions) (_ string, err error) {
+	defer trackMetric("test")(&err)
+	defer traceSpan("test")(&err)
+	defer g.beginRun()()
+	format, err := ParseFormat(opts.Format)
+	if err != nil {
+		return "", err
+	}
+	structured := format != FormatText
+	if opts.Output == nil {
+		opts.Output = os.Stdout
+	}
+
 	// Detect Module Name
-	moduleName, err := getModuleName(".")
+	module, err := g.Module()
 	if err != nil {
 		return "", fmt.Errorf("error: %v", err)
 	}
+	moduleName := module.Path
 
 	// Check cache - if code hasn't changed since last successful test, return cached result
+	// A run without the race detector must not pass for one with it
+	useRace, raceReason := g.resolveRace(opts.Race)
 	cache := NewTestCache()
-	if cache.IsCacheValid() {
-		return cache.GetCachedMessage(), nil
+	cache.SetModule(module)
+	if !useRace {
+		cache.SetVariant("norace")
+	}
+	if err := cache.SetGitStateMode(g.Config().String("cache.git_state", GitStateAuto)); err != nil {
+		g.warn("cache.git_state", err)
+	}
+	if remote, err := LoadRemoteCache(g.Config()); err != nil {
+		g.warn("remote test cache", err)
+	} else {
+		cache.SetRemote(remote)
+	}
+	report := &TestReport{Module: moduleName, Toolchain: cache.Toolchain()}
+	valid := cache.IsCacheValid()
+	if hit, err := cache.RemoteResult(); err != nil {
+		g.logger.Warn("remote test cache:", err)
+	} else if hit {
+		g.logger.Info("Test results from remote cache")
+	}
+	if valid {
+		summary := cache.GetCachedMessage()
+		if opts.SARIF != "" {
+			// Code scanning uploads expect the file on every run
+			g.writeSARIFReport(opts.SARIF)
+		}
+		if opts.CI {
+			report.Cached = true
+			report.Passed = true
+			report.Messages = strings.Split(summary, ", ")
+			g.writeStepSummary(report)
+		}
+		if structured {
+			report.Cached = true
+			report.Passed = true
+			report.Messages = strings.Split(summary, ", ")
+			g.writeFormatReport(report, format, opts.Output)
+		}
+		return summary, nil
 	}
 
 	// Initialize Status
@@ -40,18 +119,37 @@ func (g *Go) Test() (string, error) {
 		msgs = append(msgs, fmt.Sprintf("%s %s", symbol, msg))
 	}
 
-	// Parallel Phase 1: Vet + WASM detection
+	// Parallel Phase 1: Vet + lint + WASM detection
+	vet := LoadVetSettings(g.Config())
+	lintSettings, err := LoadLintSettings(g.Config())
+	if err != nil {
+		return "", err
+	}
+	lintEnabled := opts.Lint || lintSettings.Enabled
 	var wg1 sync.WaitGroup
-	var vetOutput string
-	var vetErr error
+	var vetOutput, lintTool string
+	var vetErr, vetToolErr, lintErr error
+	var lintFindings []Finding
 	var enableWasmTests bool
 
 	wg1.Add(2)
+	if lintEnabled {
+		wg1.Add(1)
+		go func() {
+			defer wg1.Done()
+			defer report.timePhase("lint")()
+			lintTool, lintFindings, lintErr = g.Lint()
+		}()
+	}
 
 	// Go Vet (async)
 	go func() {
 		defer wg1.Done()
-		vetOutput, vetErr = RunCommand("go", "vet", "./...")
+		defer report.timePhase("vet")()
+		if vetToolErr = vet.Prepare(); vetToolErr != nil {
+			return
+		}
+		vetOutput, vetErr = RunCommand("go", vet.Args(nil)...)
 	}()
 
 	// Check for WASM test files by comparing native vs WASM test file lists (async)
@@ -59,23 +157,24 @@ func (g *Go) Test() (string, error) {
 		defer wg1.Done()
 
 		// 1. Get native test files
-		nativeCmd := exec.Command("go", "list", "-f", "{{.ImportPath}} {{.TestGoFiles}} {{.XTestGoFiles}}", "./...")
-		nativeOut, _ := nativeCmd.CombinedOutput()
+		listArgs := []string{"list", "-f", "{{.ImportPath}} {{.TestGoFiles}} {{.XTestGoFiles}}", "./..."}
+		nativeOut, _ := runCommand(&Command{Name: "go", Args: listArgs})
 
 		// 2. Get WASM test files
-		wasmCmd := exec.Command("go", "list", "-f", "{{.ImportPath}} {{.TestGoFiles}} {{.XTestGoFiles}}", "./...")
-		wasmCmd.Env = os.Environ()
-		wasmCmd.Env = append(wasmCmd.Env, "GOOS=js", "GOARCH=wasm")
-		wasmOut, _ := wasmCmd.CombinedOutput()
+		wasmOut, _ := runCommand(&Command{Name: "go", Args: listArgs, Env: []string{"GOOS=js", "GOARCH=wasm"}})
 
 		// 3. Decision logic
-		enableWasmTests = shouldEnableWasm(string(nativeOut), string(wasmOut))
+		enableWasmTests = shouldEnableWasm(nativeOut, wasmOut)
 	}()
 
 	wg1.Wait()
 
 	// Process vet results
-	if vetErr != nil {
+	var vetIssues []string
+	if vetToolErr != nil {
+		addMsg(false, "vet analyzers build failed")
+		g.logger.Error(vetToolErr)
+	} else if vetErr != nil {
 		// Check if it's just "no packages" error (WASM-only projects)
 		if strings.Contains(vetOutput, "matched no packages") ||
 			strings.Contains(vetOutput, "no packages to vet") ||
@@ -84,40 +183,168 @@ func (g *Go) Test() (string, error) {
 			addMsg(true, "vet ok")
 		} else {
 			vetStatus = "Issues"
-			// Filter unsafe.Pointer warnings
-			lines := strings.Split(vetOutput, "\n")
-			var filteredLines []string
-			for _, line := range lines {
-				if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") { // Ignore comments/empty
-					continue
-				}
-				if !strings.Contains(line, "possible misuse of unsafe.Pointer") {
-					filteredLines = append(filteredLines, line)
+			// Filter suppressed findings (vet.ignore)
+			filteredLines := vet.IssueLines(vetOutput)
+
+			// Findings recorded in .devflow-baseline.json do not fail the run
+			known := 0
+			if len(filteredLines) > 0 {
+				if baseline, err := LoadBaseline("."); err != nil {
+					g.warn("baseline not applied", err)
+				} else if baseline != nil {
+					fresh, old := baseline.Filter(ParseVetText(filteredLines))
+					known = len(old)
+					filteredLines = filteredLines[:0]
+					for _, f := range fresh {
+						filteredLines = append(filteredLines, f.String())
+					}
 				}
 			}
 
 			if len(filteredLines) > 0 {
-				addMsg(false, "vet issues found")
+				if known > 0 {
+					addMsg(false, fmt.Sprintf("vet issues found: %d new", len(filteredLines)))
+					for _, line := range filteredLines {
+						g.logger.Info("New vet issue:", line)
+					}
+				} else {
+					addMsg(false, "vet issues found")
+				}
 			} else {
 				vetStatus = "OK"
-				addMsg(true, "vet ok")
+				if known > 0 {
+					addMsg(true, fmt.Sprintf("vet ok (%d baselined)", known))
+				} else {
+					addMsg(true, "vet ok")
+				}
 			}
+			vetIssues = filteredLines
 		}
 	} else {
 		vetStatus = "OK"
 		addMsg(true, "vet ok")
 	}
 
+	// Lint: the linter findings merged with the new vet issues
+	lintStatus := ""
+	lintIssues := false
+	if lintEnabled {
+		if lintErr != nil {
+			msgs = append(msgs, "⚠️ lint: check skipped")
+			g.warn("lint skipped", lintErr)
+		} else {
+			result := g.lintResult(lintTool, lintFindings, vetIssues)
+			lintStatus = result.Status()
+			lintIssues = result.Vet+result.Lint > 0
+			msgs = append(msgs, result.Summary())
+		}
+	}
+
+	// SARIF report for GitHub code scanning
+	if opts.SARIF != "" {
+		if secrets := g.writeSARIFReport(opts.SARIF); secrets > 0 {
+			msgs = append(msgs, fmt.Sprintf("⚠️ secrets: %d possible", secrets))
+		}
+	}
+
+	// License headers (opt-in via license.check)
+	headerIssues := false
+	if g.Config().Bool("license.check", false) {
+		lh, err := NewLicenseHeader(g.rootDir, g.Config())
+		if err != nil {
+			headerIssues = true
+			addMsg(false, err.Error())
+		} else if report, err := lh.Check(); err != nil {
+			headerIssues = true
+			addMsg(false, "license headers: "+err.Error())
+		} else {
+			headerIssues = !report.OK()
+			addMsg(report.OK(), report.Summary())
+			for _, f := range append(report.Missing, report.Mismatched...) {
+				g.logger.Info("License header:", f)
+			}
+		}
+	}
+
+	// Package-level exclusions (//devflow:skip and test.skip.*)
+	nativePkgs, _ := listTestPackages()
+	skips := ResolvePackageSkips(".", nativePkgs, g.Config())
+	for _, phase := range skipPhases {
+		for _, pkg := range skips[phase] {
+			g.logger.Info("Skip", phase+":", pkg)
+		}
+	}
+
 	// Run tests with race detection AND coverage in a single command
 	// go test ./... automatically discovers all packages with tests
 	var testErr error
 	var testOutput string
 
-	testCmd := exec.Command("go", "test", "-race", "-cover", "-count=1", "./...")
+	if opts.Budget == 0 {
+		if opts.Budget, err = LoadTestBudget(g.Config()); err != nil {
+			return "", err
+		}
+	}
+	budget := newTestBudget(opts.Budget)
+
+	thresholds, err := LoadCoverageThresholds(g.Config())
+	if err != nil {
+		return "", err
+	}
+	if opts.CoverageThreshold > 0 {
+		thresholds.Global = opts.CoverageThreshold
+	}
+
+	// govulncheck runs alongside the test phases
+	vulnSettings, err := LoadVulnSettings(g.Config())
+	if err != nil {
+		return "", err
+	}
+	var vulnFuture *Future
+	if opts.Vuln || vulnSettings.Enabled {
+		vulnFuture = NewFuture(func() (any, error) {
+			defer report.timePhase("vuln")()
+			return g.VulnCheck()
+		})
+	}
+
+	// Goroutine leak check: a generated TestMain per package via -overlay
+	var leakCheck *LeakCheck
+	if opts.Leaks || g.Config().Bool("leaks.enabled", false) {
+		if leakCheck, err = PrepareLeakCheck(skips, g.Config().List("leaks.ignore")); err != nil {
+			g.warn("leak check skipped", err)
+		}
+		defer leakCheck.Cleanup()
+		defer OnInterrupt(leakCheck.Cleanup)()
+		if leakCheck != nil {
+			for _, pkg := range leakCheck.Custom {
+				g.logger.Info("Leak check skipped (own TestMain):", pkg)
+			}
+		}
+	}
+	var budgetOutput strings.Builder
+
+	// Packages excluded from race detection run in a second go test without -race
+	runs := [][]string{{"test", "-race", "-cover", "-count=1", "./..."}}
+	if !useRace {
+		runs = [][]string{{"test", "-cover", "-count=1", "./..."}}
+	} else if len(skips[SkipRace]) > 0 {
+		runs = nil
+		if racePkgs := packagesWithout(nativePkgs, skips, SkipRace); len(racePkgs) > 0 {
+			runs = append(runs, append([]string{"test", "-race", "-cover", "-count=1"}, racePkgs...))
+		}
+		runs = append(runs, append([]string{"test", "-cover", "-count=1"}, skips[SkipRace]...))
+	}
 
 	testBuffer := &bytes.Buffer{}
 
-	testFilter := NewConsoleFilter(nil)
+	// Keep stdout for a structured report written there
+	var console func(string)
+	if structured && opts.Output == io.Writer(os.Stdout) {
+		stderr := OutputWriter(os.Stderr)
+		console = func(s string) { fmt.Fprintln(stderr, s) }
+	}
+	testFilter := NewConsoleFilter(console)
 
 	testPipe := &paramWriter{
 		write: func(p []byte) (n int, err error) {
@@ -128,16 +355,49 @@ func (g *Go) Test() (string, error) {
 		},
 	}
 
-	testCmd.Stdout = testPipe
-	testCmd.Stderr = testPipe
-	testErr = testCmd.Run()
+	var testOut io.Writer = testPipe
+	testJSON := &testJSONWriter{text: testPipe}
+	if structured {
+		testOut = testJSON
+	}
+
+	stopTests := report.timePhase("tests stdlib")
+	for _, args := range runs {
+		if budget.expired() {
+			break
+		}
+		if structured {
+			args = testJSONArgs(args)
+		}
+		testCmd := &Command{Name: "go", Args: budget.args(leakCheck.Args(args)), Stdout: testOut, start: budget.run}
+		if _, err := runCommand(testCmd); err != nil && testErr == nil {
+			testErr = err
+		}
+	}
+	stopTests()
+	testJSON.Flush()
 	testFilter.Flush()
 
 	testOutput = testBuffer.String()
+	report.addTestOutput(testOutput, "")
+	report.addTestCases(TestCases(testJSON.events), "")
+	budgetOutput.WriteString(testOutput)
+	var leaks LeakResult
+	if leakCheck != nil && len(leakCheck.Packages) > 0 {
+		leaks = ParseLeaks(testOutput)
+	}
 
 	// Process test results
 	var stdTestsRan bool
 	testStatus, raceStatus, stdTestsRan, msgs = evaluateTestResults(testErr, testOutput, moduleName, msgs)
+	if !useRace {
+		raceStatus = "Skipped"
+		for i, m := range msgs {
+			if m == "✅ race detection ok" {
+				msgs[i] = "⏭️ race skipped (" + raceReason + ")"
+			}
+		}
+	}
 
 	// If no stdlib tests ran but we see exclusions, consider enabling WASM (if not already enabled)
 	if !stdTestsRan {
@@ -145,19 +405,42 @@ func (g *Go) Test() (string, error) {
 			strings.Contains(testOutput, "build constraints exclude all Go files")
 		if isExclusionError {
 			enableWasmTests = true
-			g.log("No stdlib tests matched/run (possibly WASM-only module), skipping stdlib tests...")
+			g.logger.Info("No stdlib tests matched/run (possibly WASM-only module), skipping stdlib tests...")
+		}
+	}
+
+	if leakCheck != nil && len(leakCheck.Packages) > 0 && stdTestsRan {
+		addMsg(leaks.Goroutines == 0, leaks.Summary())
+		for _, pkg := range leaks.Packages {
+			g.logger.Info("Goroutine leak:", pkg)
 		}
 	}
 
 	// Process coverage results (from the same test run)
 	if stdTestsRan {
-		coveragePercent = calculateAverageCoverage(testOutput)
+		coveragePercent = calculateAverageCoverage(excludeCoverage(testOutput, skips[SkipCoverage]))
 		if coveragePercent != "0" {
 			addMsg(true, "coverage: "+coveragePercent+"%")
 		}
 	}
 
 	// WASM Tests
+	var wasmPkgs []TestPackage
+	wasmSkips := PackageSkips{}
+	if enableWasmTests {
+		var err error
+		wasmPkgs, err = listTestPackages("GOOS=js", "GOARCH=wasm")
+		g.warn("WASM package skips not applied", err)
+		wasmSkips = ResolvePackageSkips(".", wasmPkgs, g.Config())
+		if len(wasmPkgs) > 0 && len(packagesWithout(wasmPkgs, wasmSkips, SkipWasm)) == 0 {
+			enableWasmTests = false
+			g.logger.Info("All packages skipped for WASM tests")
+		}
+	}
+	if enableWasmTests && budget.expired() {
+		enableWasmTests = false
+		addMsg(false, "WASM tests skipped (time budget exceeded)")
+	}
 	if enableWasmTests {
 
 		if err := g.installWasmBrowserTest(); err != nil {
@@ -168,16 +451,16 @@ func (g *Go) Test() (string, error) {
 			testArgs := []string{"test", "-exec", execArg, "-cover", "./..."}
 			execArg = "wasmbrowsertest"
 			testArgs = []string{"test", "-exec", execArg, "-v", "-cover", "./..."}
-
-			wasmCmd := exec.Command("go", testArgs...)
-			wasmCmd.Env = os.Environ()
-			wasmCmd.Env = append(wasmCmd.Env, "GOOS=js", "GOARCH=wasm")
+			if len(wasmSkips[SkipWasm]) > 0 {
+				testArgs = append(testArgs[:len(testArgs)-1], packagesWithout(wasmPkgs, wasmSkips, SkipWasm)...)
+			}
+			if structured {
+				testArgs = testJSONArgs(testArgs)
+			}
 
 			var wasmOut bytes.Buffer
 
-			var wasmFilterCallback func(string)
-
-			wasmFilter := NewConsoleFilter(wasmFilterCallback)
+			wasmFilter := NewConsoleFilter(console)
 			wasmPipe := &paramWriter{
 				write: func(p []byte) (n int, err error) {
 					s := string(p)
@@ -187,13 +470,23 @@ func (g *Go) Test() (string, error) {
 				},
 			}
 
-			wasmCmd.Stdout = wasmPipe
-			wasmCmd.Stderr = wasmPipe
+			var wasmTestOut io.Writer = wasmPipe
+			wasmJSON := &testJSONWriter{text: wasmPipe}
+			if structured {
+				wasmTestOut = wasmJSON
+			}
+			wasmCmd := &Command{Name: "go", Args: budget.args(testArgs), Env: []string{"GOOS=js", "GOARCH=wasm"}, Stdout: wasmTestOut, start: budget.run}
 
-			err := wasmCmd.Run()
+			stopWasm := report.timePhase("tests wasm")
+			_, err := runCommand(wasmCmd)
+			stopWasm()
+			wasmJSON.Flush()
 			wasmFilter.Flush()
 
 			wOutput := wasmOut.String()
+			report.addTestOutput(wOutput, "wasm")
+			report.addTestCases(TestCases(wasmJSON.events), "wasm")
+			budgetOutput.WriteString(wOutput)
 
 			if err != nil {
 				// WASM test failure - ConsoleFilter already filtered the output in quiet mode
@@ -204,7 +497,7 @@ func (g *Go) Test() (string, error) {
 				if testStatus != "Failed" {
 					testStatus = "Passing"
 				}
-				wCov := calculateAverageCoverage(wOutput)
+				wCov := calculateAverageCoverage(excludeCoverage(wOutput, wasmSkips[SkipCoverage]))
 				if wCov != "0" {
 					// Prefer WASM coverage if stdlib had 0% (common in WASM-only packages)
 					if coveragePercent == "0" {
@@ -216,31 +509,96 @@ func (g *Go) Test() (string, error) {
 		}
 	}
 
+	if budget.exceeded(budgetOutput.String()) {
+		testStatus = "Failed"
+		var pkgs []string
+		for _, p := range nativePkgs {
+			pkgs = append(pkgs, p.ImportPath)
+		}
+		addMsg(false, budget.report(budgetOutput.String(), pkgs))
+	}
+
+	// Coverage thresholds, with the change since the last successful run
+	coverage := packageCoverage(report.Packages, skips[SkipCoverage])
+	coverageFailed := false
+	if thresholds.Enabled() && len(coverage) > 0 {
+		dirs := make(map[string]string)
+		for _, p := range append(nativePkgs, wasmPkgs...) {
+			dirs[p.ImportPath] = p.Dir
+		}
+		result := CheckCoverage(coverage, dirs, thresholds, cache.LastCoverage())
+		coverageFailed = len(result.Below) > 0
+		addMsg(!coverageFailed, result.Summary())
+		for _, c := range result.Below {
+			g.logger.Info("Coverage below threshold:", c)
+		}
+		for _, c := range result.Regressed {
+			g.logger.Info("Coverage regressed:", c)
+		}
+	}
+
+	vulnStatus := ""
+	if vulnFuture != nil {
+		result, err := vulnFuture.Get()
+		if err != nil {
+			msgs = append(msgs, "⚠️ vulns: check skipped")
+			g.warn("vulnerability check skipped", err)
+		} else {
+			vulns := result.(VulnReport)
+			vulnStatus = vulns.Status()
+			msgs = append(msgs, vulns.Summary())
+			for _, f := range vulns.Findings {
+				g.logger.Info("Vulnerability:", f)
+			}
+		}
+	}
+
+	// Only report WASM exclusions when the WASM phase applies
+	skips[SkipWasm] = wasmSkips[SkipWasm]
+	if leakCheck == nil {
+		delete(skips, SkipLeaks)
+	}
+	if summary := skips.Summary(); summary != "" {
+		msgs = append(msgs, "⏭️ "+summary)
+	}
+
 	// Badges
 
-	licenseType := "MIT"
-	if checkFileExists("LICENSE") {
-		// naive check
+	licenseType := DetectLicense(".")
+	if licenseType == "" {
+		licenseType = "MIT"
 	}
 	goVer := getGoVersion()
 
+	report.BadgesBefore = ReadBadgeValues(DefaultBadgesFile)
 	bh := NewBadges()
-	bh.SetLog(g.log)
-	if err := bh.updateBadges("README.md", licenseType, goVer, testStatus, coveragePercent, raceStatus, vetStatus, true); err != nil {
+	bh.SetLog(g.logger.Info)
+	g.warn("badges not updated", bh.updateBadges("README.md", licenseType, goVer, testStatus, coveragePercent, raceStatus, vetStatus, vulnStatus, lintStatus, true))
+	report.BadgesAfter = ReadBadgeValues(DefaultBadgesFile)
 
+	// Return error if tests or vet failed
+	summary := strings.Join(append(msgs, report.Toolchain.String()), ", ")
+	failed := testStatus == "Failed" || vetStatus == "Issues" || lintIssues || headerIssues || coverageFailed
+	report.Warnings = g.warnings.List()
+
+	if opts.CI {
+		report.Messages = msgs
+		report.Passed = !failed
+		g.writeStepSummary(report)
+	}
+	if structured {
+		report.Messages = msgs
+		report.Passed = !failed
+		g.writeFormatReport(report, format, opts.Output)
 	}
 
-	// Return error if tests or vet failed
-	summary := strings.Join(msgs, ", ")
-	if testStatus == "Failed" || vetStatus == "Issues" {
+	if failed {
 		return summary, fmt.Errorf("%s", summary)
 	}
 
 	// Save test cache on success (for gopush optimization)
-	cache = NewTestCache()
-	if err := cache.SaveCache(summary); err != nil {
-		g.log("Warning: failed to save test cache:", err)
-	}
+	g.warn("test cache not saved", cache.SaveCache(summary))
+	g.warn("coverage not saved", cache.SaveCoverage(coverage))
 
 	return summary, nil
 }
@@ -284,6 +642,9 @@ func (g *Go) installWasmBrowserTest() error {
 	if _, err := RunCommandSilent("which", "wasmbrowsertest"); err == nil {
 		return nil
 	}
+	if err := AirGapCheck(NetToolInstall); err != nil {
+		return fmt.Errorf("wasmbrowsertest not installed: %w", err)
+	}
 
 	_, err := RunCommand("go", "install", "github.com/tinywasm/wasmbrowsertest@latest")
 	if err != nil {
@@ -0,0 +1,329 @@
+package devflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Report formats of TestOptions.Format
+const (
+	FormatText  = "text"  // Summary line only (default)
+	FormatJSON  = "json"  // Machine-readable summary with every test
+	FormatJUnit = "junit" // JUnit XML for Jenkins, GitLab CI and similar
+)
+
+// ParseFormat validates a -format value, empty meaning text
+func ParseFormat(s string) (string, error) {
+	switch s {
+	case "", FormatText:
+		return FormatText, nil
+	case FormatJSON, FormatJUnit:
+		return s, nil
+	}
+	return "", fmt.Errorf("unknown format %q (want text, json or junit)", s)
+}
+
+// TestEvent is one line of go test -json output (see go doc test2json)
+type TestEvent struct {
+	Time    time.Time
+	Action  string
+	Package string
+	Test    string
+	Elapsed float64 // Seconds
+	Output  string
+}
+
+// TestCase is the result of a single test. Package failures without a
+// failing test (build errors, TestMain) are reported as Name "(package)".
+type TestCase struct {
+	Package string
+	Name    string
+	Status  string // "pass", "fail" or "skip"
+	Elapsed time.Duration
+	Output  string
+}
+
+// testJSONArgs turns go test arguments into the -json form
+func testJSONArgs(args []string) []string {
+	if len(args) == 0 || args[0] != "test" {
+		return args
+	}
+	return append([]string{"test", "-json"}, args[1:]...)
+}
+
+// ParseTestEvents decodes go test -json output, ignoring non-JSON lines
+func ParseTestEvents(data []byte) []TestEvent {
+	var events []TestEvent
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		var ev TestEvent
+		if len(line) > 0 && line[0] == '{' && json.Unmarshal(line, &ev) == nil {
+			events = append(events, ev)
+		}
+	}
+	return events
+}
+
+// TestCases folds events into per-test results, in the order tests ended
+func TestCases(events []TestEvent) []TestCase {
+	type key struct{ pkg, test string }
+	output := map[key]*strings.Builder{}
+	failedTests := map[string]bool{}
+	var cases []TestCase
+
+	for _, ev := range events {
+		k := key{ev.Package, ev.Test}
+		if ev.Action == "output" {
+			if output[k] == nil {
+				output[k] = &strings.Builder{}
+			}
+			output[k].WriteString(ev.Output)
+			continue
+		}
+		if ev.Action != "pass" && ev.Action != "fail" && ev.Action != "skip" {
+			continue
+		}
+		out := ""
+		if output[k] != nil {
+			out = output[k].String()
+		}
+		elapsed := time.Duration(ev.Elapsed * float64(time.Second))
+		if ev.Test != "" {
+			if ev.Action == "fail" {
+				failedTests[ev.Package] = true
+			}
+			cases = append(cases, TestCase{Package: ev.Package, Name: ev.Test, Status: ev.Action, Elapsed: elapsed, Output: out})
+		} else if ev.Action == "fail" && !failedTests[ev.Package] {
+			cases = append(cases, TestCase{Package: ev.Package, Name: "(package)", Status: "fail", Elapsed: elapsed, Output: out})
+		}
+	}
+	return cases
+}
+
+// testJSONWriter decodes go test -json output as it is written, passing
+// the text of every event (and any non-JSON line) on to text, so the
+// console filter and result parsers see the usual go test -v output
+type testJSONWriter struct {
+	text    io.Writer
+	mu      sync.Mutex
+	partial []byte
+	events  []TestEvent
+}
+
+func (w *testJSONWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	data := append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			break
+		}
+		w.line(data[:i+1])
+		data = data[i+1:]
+	}
+	w.partial = append([]byte(nil), data...)
+	return len(p), nil
+}
+
+// Flush handles a last line without a newline
+func (w *testJSONWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.partial) > 0 {
+		w.line(w.partial)
+		w.partial = nil
+	}
+}
+
+func (w *testJSONWriter) line(line []byte) {
+	var ev TestEvent
+	if bytes.HasPrefix(line, []byte("{")) && json.Unmarshal(line, &ev) == nil {
+		w.events = append(w.events, ev)
+		if ev.Output != "" {
+			w.text.Write([]byte(ev.Output))
+		}
+		return
+	}
+	w.text.Write(line)
+}
+
+// JSON renders the report as a machine-readable summary
+func (r *TestReport) JSON() ([]byte, error) {
+	type pkgJSON struct {
+		Path     string   `json:"path"`
+		Status   string   `json:"status"`
+		Seconds  float64  `json:"elapsed_seconds"`
+		Coverage *float64 `json:"coverage,omitempty"`
+	}
+	type testJSON struct {
+		Package string  `json:"package"`
+		Name    string  `json:"name"`
+		Status  string  `json:"status"`
+		Seconds float64 `json:"elapsed_seconds"`
+		Output  string  `json:"output,omitempty"`
+	}
+	out := struct {
+		Module    string     `json:"module"`
+		Toolchain string     `json:"toolchain"`
+		Passed    bool       `json:"passed"`
+		Cached    bool       `json:"cached"`
+		Messages  []string   `json:"messages"`
+		Warnings  []Warning  `json:"warnings,omitempty"`
+		Packages  []pkgJSON  `json:"packages"`
+		Tests     []testJSON `json:"tests"`
+	}{Module: r.Module, Toolchain: r.Toolchain.String(), Passed: r.Passed, Cached: r.Cached, Messages: r.Messages, Warnings: r.Warnings,
+		Packages: []pkgJSON{}, Tests: []testJSON{}}
+
+	for _, p := range r.Packages {
+		pj := pkgJSON{Path: p.Path, Status: p.Status, Seconds: p.Elapsed.Seconds()}
+		if p.Coverage >= 0 {
+			cov := p.Coverage
+			pj.Coverage = &cov
+		}
+		out.Packages = append(out.Packages, pj)
+	}
+	for _, t := range r.Tests {
+		tj := testJSON{Package: t.Package, Name: t.Name, Status: t.Status, Seconds: t.Elapsed.Seconds()}
+		if t.Status == "fail" {
+			tj.Output = t.Output // Keep the summary small: output only for failures
+		}
+		out.Tests = append(out.Tests, tj)
+	}
+	return json.MarshalIndent(out, "", "  ")
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Time       string          `xml:"time,attr"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	Cases      []junitTestCase `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Classname string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",cdata"`
+}
+
+// JUnit renders the report as JUnit XML, one testsuite per package
+func (r *TestReport) JUnit() ([]byte, error) {
+	root := junitTestSuites{Name: r.Module}
+	index := map[string]int{}
+	var total time.Duration
+
+	for _, t := range r.Tests {
+		i, ok := index[t.Package]
+		if !ok {
+			i = len(root.Suites)
+			index[t.Package] = i
+			root.Suites = append(root.Suites, junitTestSuite{
+				Name: t.Package,
+				Properties: []junitProperty{
+					{Name: "go.version", Value: r.Toolchain.GoVersion},
+					{Name: "go.platform", Value: r.Toolchain.GOOS + "/" + r.Toolchain.GOARCH},
+				},
+			})
+		}
+		suite := &root.Suites[i]
+		tc := junitTestCase{Classname: t.Package, Name: t.Name, Time: junitSeconds(t.Elapsed)}
+		switch t.Status {
+		case "fail":
+			tc.Failure = &junitMessage{Message: "Failed", Text: t.Output}
+			suite.Failures++
+		case "skip":
+			tc.Skipped = &junitMessage{Message: strings.TrimSpace(lastLine(t.Output))}
+			suite.Skipped++
+		}
+		suite.Tests++
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	for i := range root.Suites {
+		s := &root.Suites[i]
+		var d time.Duration
+		for _, p := range r.Packages {
+			if p.Path == s.Name {
+				d = p.Elapsed
+			}
+		}
+		s.Time = junitSeconds(d)
+		total += d
+		root.Tests += s.Tests
+		root.Failures += s.Failures
+		root.Skipped += s.Skipped
+	}
+	root.Time = junitSeconds(total)
+
+	data, err := xml.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// lastLine returns the last non-empty line of s, e.g. a t.Skip reason
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if l := strings.TrimSpace(lines[i]); l != "" && !strings.HasPrefix(l, "--- SKIP") {
+			return l
+		}
+	}
+	return ""
+}
+
+// writeFormatReport writes the json or junit report to out
+func (g *Go) writeFormatReport(r *TestReport, format string, out io.Writer) {
+	var data []byte
+	var err error
+	switch format {
+	case FormatJSON:
+		data, err = r.JSON()
+		data = append(data, '\n')
+	case FormatJUnit:
+		data, err = r.JUnit()
+	default:
+		return
+	}
+	if err == nil {
+		_, err = out.Write(data)
+	}
+	if err != nil {
+		g.logger.Warn("could not write", format, "report:", err)
+	}
+}
@@ -0,0 +1,135 @@
+package devflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleTestJSON = `{"Action":"start","Package":"example.com/app"}
+{"Action":"run","Package":"example.com/app","Test":"TestOK"}
+{"Action":"output","Package":"example.com/app","Test":"TestOK","Output":"=== RUN   TestOK\n"}
+{"Action":"output","Package":"example.com/app","Test":"TestOK","Output":"--- PASS: TestOK (0.01s)\n"}
+{"Action":"pass","Package":"example.com/app","Test":"TestOK","Elapsed":0.01}
+{"Action":"run","Package":"example.com/app","Test":"TestSkip"}
+{"Action":"output","Package":"example.com/app","Test":"TestSkip","Output":"    app_test.go:9: needs network\n"}
+{"Action":"skip","Package":"example.com/app","Test":"TestSkip"}
+{"Action":"run","Package":"example.com/app","Test":"TestBad"}
+{"Action":"output","Package":"example.com/app","Test":"TestBad","Output":"    app_test.go:12: boom\n"}
+{"Action":"fail","Package":"example.com/app","Test":"TestBad","Elapsed":0.25}
+{"Action":"output","Package":"example.com/app","Output":"FAIL\texample.com/app\t0.301s\n"}
+{"Action":"fail","Package":"example.com/app","Elapsed":0.301}
+{"Action":"output","Package":"example.com/app/broken","Output":"FAIL\texample.com/app/broken [build failed]\n"}
+{"Action":"fail","Package":"example.com/app/broken","Elapsed":0}
+`
+
+func TestTestCases(t *testing.T) {
+	cases := TestCases(ParseTestEvents([]byte("go: downloading x\n" + sampleTestJSON)))
+	if len(cases) != 4 {
+		t.Fatalf("expected 4 cases, got %+v", cases)
+	}
+	if cases[0].Name != "TestOK" || cases[0].Status != "pass" || cases[0].Elapsed != 10*time.Millisecond {
+		t.Errorf("unexpected pass: %+v", cases[0])
+	}
+	if cases[1].Status != "skip" || cases[2].Status != "fail" || !strings.Contains(cases[2].Output, "boom") {
+		t.Errorf("unexpected skip/fail: %+v %+v", cases[1], cases[2])
+	}
+	// The package with a failing test gets no extra case, the build failure does
+	if cases[3].Package != "example.com/app/broken" || cases[3].Name != "(package)" || !strings.Contains(cases[3].Output, "build failed") {
+		t.Errorf("unexpected package failure: %+v", cases[3])
+	}
+}
+
+func TestTestJSONWriter(t *testing.T) {
+	var text bytes.Buffer
+	w := &testJSONWriter{text: &text}
+	// Split mid-line, as pipes do
+	w.Write([]byte(sampleTestJSON[:100]))
+	w.Write([]byte(sampleTestJSON[100:] + "# plain line"))
+	w.Flush()
+
+	if len(w.events) != 15 {
+		t.Errorf("expected 15 events, got %d", len(w.events))
+	}
+	got := text.String()
+	if !strings.HasPrefix(got, "=== RUN   TestOK\n--- PASS") || !strings.HasSuffix(got, "[build failed]\n# plain line") {
+		t.Errorf("unexpected text output:\n%s", got)
+	}
+	if len(parsePackageResults(got)) != 2 {
+		t.Errorf("text parsers should see the go test output: %+v", parsePackageResults(got))
+	}
+}
+
+func TestTestReportJUnit(t *testing.T) {
+	r := &TestReport{Module: "example.com/app", Toolchain: Toolchain{"go1.25.2", "linux", "amd64"}}
+	r.addTestOutput("FAIL\texample.com/app\t0.301s\n", "")
+	r.addTestCases(TestCases(ParseTestEvents([]byte(sampleTestJSON))), "")
+
+	data, err := r.JUnit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc junitTestSuites
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, data)
+	}
+	if doc.Tests != 4 || doc.Failures != 2 || doc.Skipped != 1 || len(doc.Suites) != 2 {
+		t.Errorf("unexpected totals: %+v", doc)
+	}
+	app := doc.Suites[0]
+	if app.Name != "example.com/app" || app.Time != "0.301" || app.Properties[0].Value != "go1.25.2" {
+		t.Errorf("unexpected suite: %+v", app)
+	}
+	if app.Cases[1].Skipped == nil || app.Cases[1].Skipped.Message != "app_test.go:9: needs network" {
+		t.Errorf("unexpected skipped case: %+v", app.Cases[1])
+	}
+	if app.Cases[2].Failure == nil || !strings.Contains(app.Cases[2].Failure.Text, "boom") {
+		t.Errorf("unexpected failed case: %+v", app.Cases[2])
+	}
+}
+
+func TestTestReportJSON(t *testing.T) {
+	r := &TestReport{Module: "example.com/app", Passed: false, Messages: []string{"❌ tests stdlib failed"}}
+	r.addTestOutput("ok  \texample.com/app\t0.5s\tcoverage: 80.0% of statements\n", "")
+	r.addTestCases(TestCases(ParseTestEvents([]byte(sampleTestJSON))), "wasm")
+
+	data, err := r.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Module   string
+		Packages []struct {
+			Coverage *float64
+		}
+		Tests []struct {
+			Package, Name, Status, Output string
+		}
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Module != "example.com/app" || len(got.Packages) != 1 || *got.Packages[0].Coverage != 80 {
+		t.Errorf("unexpected summary: %s", data)
+	}
+	if len(got.Tests) != 4 || got.Tests[0].Package != "example.com/app (wasm)" || got.Tests[0].Output != "" || got.Tests[2].Output == "" {
+		t.Errorf("unexpected tests: %+v", got.Tests)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for in, want := range map[string]string{"": FormatText, "text": FormatText, "json": FormatJSON, "junit": FormatJUnit} {
+		if got, err := ParseFormat(in); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v", in, got, err)
+		}
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+	if got := testJSONArgs([]string{"test", "-race", "./..."}); strings.Join(got, " ") != "test -json -race ./..." {
+		t.Errorf("unexpected args: %v", got)
+	}
+}
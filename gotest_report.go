@@ -0,0 +1,256 @@
+package devflow
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// stepSummaryEnv is the file GitHub Actions renders as the job summary
+const stepSummaryEnv = "GITHUB_STEP_SUMMARY"
+
+var (
+	// "ok  	pkg	0.12s	coverage: 71.2% of statements", "FAIL	pkg	0.50s", "?   	pkg	[no test files]"
+	packageLineRe = regexp.MustCompile(`^(ok|FAIL|\?)\s+(\S+)\s+(\S+)(?:\s+coverage:\s+(\d+(?:\.\d+)?)%)?`)
+	failedTestRe  = regexp.MustCompile(`^\s*--- FAIL: (\S+) \(([\d.]+)s\)`)
+	badgeValueRe  = regexp.MustCompile(`(?s)<!-- Badge: (.+?) -->.*?</text>.*?>([^<]*)</text>`)
+)
+
+// PackageResult is the outcome of one package in a go test run
+type PackageResult struct {
+	Path     string
+	Status   string        // "ok", "FAIL" or "no tests"
+	Elapsed  time.Duration // Zero when cached or without tests
+	Coverage float64       // -1 when not reported
+}
+
+// FailedTest is a test reported as --- FAIL
+type FailedTest struct {
+	Name    string
+	Elapsed time.Duration
+}
+
+// PhaseTiming is the wall time of a gotest phase
+type PhaseTiming struct {
+	Name     string
+	Duration time.Duration
+}
+
+// TestReport collects the details of a gotest run for CI reports
+type TestReport struct {
+	Module       string
+	Toolchain    Toolchain
+	Passed       bool
+	Cached       bool
+	Messages     []string
+	Packages     []PackageResult
+	FailedTests  []FailedTest
+	Tests        []TestCase // Every test, only for the json and junit formats
+	Phases       []PhaseTiming
+	Warnings     []Warning // Non-fatal problems of the run (see Go.Warnings)
+	BadgesBefore map[string]string
+	BadgesAfter  map[string]string
+
+	mu sync.Mutex
+}
+
+// timePhase starts timing a phase, also traced as a span of the test
+// run; call the returned func when it ends
+func (r *TestReport) timePhase(name string) func() {
+	start := time.Now()
+	end := traceChild(name)
+	return func() {
+		end(nil)
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.Phases = append(r.Phases, PhaseTiming{Name: name, Duration: time.Since(start)})
+	}
+}
+
+// addTestOutput parses go test output; tag marks the run (e.g. "wasm")
+func (r *TestReport) addTestOutput(output, tag string) {
+	for _, p := range parsePackageResults(output) {
+		if tag != "" {
+			p.Path += " (" + tag + ")"
+		}
+		r.Packages = append(r.Packages, p)
+	}
+	r.FailedTests = append(r.FailedTests, parseFailedTests(output)...)
+}
+
+// addTestCases records the results of a go test -json run
+func (r *TestReport) addTestCases(cases []TestCase, tag string) {
+	for _, c := range cases {
+		if tag != "" {
+			c.Package += " (" + tag + ")"
+		}
+		r.Tests = append(r.Tests, c)
+	}
+}
+
+// parsePackageResults extracts the per-package result lines of go test
+func parsePackageResults(output string) []PackageResult {
+	var results []PackageResult
+	for _, line := range strings.Split(output, "\n") {
+		m := packageLineRe.FindStringSubmatch(strings.TrimRight(line, "\r"))
+		if m == nil {
+			continue
+		}
+		p := PackageResult{Path: m[2], Status: m[1], Coverage: -1}
+		if p.Status == "?" {
+			p.Status = "no tests"
+		}
+		if d, err := time.ParseDuration(m[3]); err == nil {
+			p.Elapsed = d
+		}
+		if m[4] != "" {
+			p.Coverage, _ = strconv.ParseFloat(m[4], 64)
+		}
+		results = append(results, p)
+	}
+	return results
+}
+
+// parseFailedTests extracts the --- FAIL lines of go test
+func parseFailedTests(output string) []FailedTest {
+	var failed []FailedTest
+	for _, line := range strings.Split(output, "\n") {
+		if m := failedTestRe.FindStringSubmatch(line); m != nil {
+			secs, _ := strconv.ParseFloat(m[2], 64)
+			failed = append(failed, FailedTest{Name: m[1], Elapsed: time.Duration(secs * float64(time.Second))})
+		}
+	}
+	return failed
+}
+
+// ReadBadgeValues returns the label/value pairs of a generated badges SVG.
+// A missing file yields an empty map.
+func ReadBadgeValues(path string) map[string]string {
+	values := make(map[string]string)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return values
+	}
+	for _, m := range badgeValueRe.FindAllStringSubmatch(string(data), -1) {
+		values[m[1]] = m[2]
+	}
+	return values
+}
+
+// Markdown renders the report for the GitHub Actions job summary
+func (r *TestReport) Markdown() string {
+	var b strings.Builder
+
+	status := "✅ passed"
+	if !r.Passed {
+		status = "❌ failed"
+	}
+	fmt.Fprintf(&b, "## gotest: %s %s\n\n", r.Module, status)
+	if r.Toolchain.GoVersion != "" {
+		fmt.Fprintf(&b, "Toolchain: `%s`\n\n", r.Toolchain)
+	}
+	if r.Cached {
+		b.WriteString("Result from cache (no code changes since the last successful run).\n\n")
+	}
+	for _, m := range r.Messages {
+		fmt.Fprintf(&b, "- %s\n", m)
+	}
+
+	if len(r.Warnings) > 0 {
+		b.WriteString("\n### Warnings\n\n")
+		for _, w := range r.Warnings {
+			fmt.Fprintf(&b, "- ⚠️ %s\n", w)
+		}
+	}
+
+	if len(r.FailedTests) > 0 {
+		b.WriteString("\n### Failed tests\n\n| Test | Time |\n|------|------|\n")
+		for _, t := range r.FailedTests {
+			fmt.Fprintf(&b, "| `%s` | %s |\n", t.Name, t.Elapsed.Round(time.Millisecond))
+		}
+	}
+
+	if len(r.Packages) > 0 {
+		b.WriteString("\n### Packages\n\n| Package | Status | Coverage | Time |\n|---------|--------|----------|------|\n")
+		for _, p := range r.Packages {
+			cov := "-"
+			if p.Coverage >= 0 {
+				cov = fmt.Sprintf("%.1f%%", p.Coverage)
+			}
+			elapsed := "-"
+			if p.Elapsed > 0 {
+				elapsed = p.Elapsed.Round(time.Millisecond).String()
+			}
+			fmt.Fprintf(&b, "| `%s` | %s | %s | %s |\n", p.Path, packageStatusIcon(p.Status), cov, elapsed)
+		}
+	}
+
+	if len(r.Phases) > 0 {
+		b.WriteString("\n### Timings\n\n| Phase | Time |\n|-------|------|\n")
+		for _, p := range r.Phases {
+			fmt.Fprintf(&b, "| %s | %s |\n", p.Name, p.Duration.Round(time.Millisecond))
+		}
+	}
+
+	if diff := r.badgeChanges(); len(diff) > 0 {
+		b.WriteString("\n### Badges\n\n| Badge | Before | After |\n|-------|--------|-------|\n")
+		for _, row := range diff {
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", row[0], row[1], row[2])
+		}
+	}
+	return b.String()
+}
+
+// badgeChanges returns [label, before, after] for badges whose value changed
+func (r *TestReport) badgeChanges() [][3]string {
+	var labels []string
+	for label, after := range r.BadgesAfter {
+		if r.BadgesBefore[label] != after {
+			labels = append(labels, label)
+		}
+	}
+	sort.Strings(labels)
+
+	rows := make([][3]string, 0, len(labels))
+	for _, label := range labels {
+		before := r.BadgesBefore[label]
+		if before == "" {
+			before = "-"
+		}
+		rows = append(rows, [3]string{label, before, r.BadgesAfter[label]})
+	}
+	return rows
+}
+
+func packageStatusIcon(status string) string {
+	switch status {
+	case "ok":
+		return "✅ ok"
+	case "FAIL":
+		return "❌ fail"
+	}
+	return status
+}
+
+// writeStepSummary appends the report to $GITHUB_STEP_SUMMARY when set
+func (g *Go) writeStepSummary(r *TestReport) {
+	path := os.Getenv(stepSummaryEnv)
+	if path == "" {
+		g.logger.Info("CI report skipped:", stepSummaryEnv, "not set")
+		return
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		g.logger.Warn("could not write CI report:", err)
+		return
+	}
+	defer f.Close()
+	if _, err := f.WriteString(r.Markdown() + "\n"); err != nil {
+		g.logger.Warn("could not write CI report:", err)
+	}
+}
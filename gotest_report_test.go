@@ -0,0 +1,86 @@
+package devflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleTestOutput = `--- FAIL: TestParse (0.25s)
+    parse_test.go:10: boom
+FAIL
+FAIL	example.com/app/parse	0.301s
+ok  	example.com/app	0.120s	coverage: 71.2% of statements
+?   	example.com/app/cmd	[no test files]
+`
+
+func TestParsePackageResults(t *testing.T) {
+	got := parsePackageResults(sampleTestOutput)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 packages, got %+v", got)
+	}
+	if got[0].Path != "example.com/app/parse" || got[0].Status != "FAIL" || got[0].Elapsed != 301*time.Millisecond || got[0].Coverage != -1 {
+		t.Errorf("unexpected failed package: %+v", got[0])
+	}
+	if got[1].Status != "ok" || got[1].Coverage != 71.2 {
+		t.Errorf("unexpected ok package: %+v", got[1])
+	}
+	if got[2].Status != "no tests" {
+		t.Errorf("unexpected no-test package: %+v", got[2])
+	}
+
+	failed := parseFailedTests(sampleTestOutput)
+	if len(failed) != 1 || failed[0].Name != "TestParse" || failed[0].Elapsed != 250*time.Millisecond {
+		t.Errorf("unexpected failed tests: %+v", failed)
+	}
+}
+
+func TestReadBadgeValues(t *testing.T) {
+	values := ReadBadgeValues(filepath.Join("docs", "img", "badges.svg"))
+	if values["License"] != "MIT" || values["Tests"] == "" {
+		t.Errorf("unexpected badge values: %v", values)
+	}
+	if len(ReadBadgeValues("missing.svg")) != 0 {
+		t.Error("missing file should yield no values")
+	}
+}
+
+func TestTestReportMarkdown(t *testing.T) {
+	r := &TestReport{Module: "example.com/app", Messages: []string{"✅ vet ok", "❌ tests stdlib failed"}}
+	r.addTestOutput(sampleTestOutput, "")
+	r.Phases = []PhaseTiming{{"vet", 1500 * time.Millisecond}}
+	r.BadgesBefore = map[string]string{"Coverage": "80%", "Go": "1.25"}
+	r.BadgesAfter = map[string]string{"Coverage": "71%", "Go": "1.25"}
+
+	md := r.Markdown()
+	for _, want := range []string{
+		"## gotest: example.com/app ❌ failed",
+		"- ❌ tests stdlib failed",
+		"| `TestParse` | 250ms |",
+		"| `example.com/app` | ✅ ok | 71.2% | 120ms |",
+		"| vet | 1.5s |",
+		"| Coverage | 80% | 71% |",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+	if strings.Contains(md, "| Go |") {
+		t.Error("unchanged badges should not be listed")
+	}
+}
+
+func TestWriteStepSummary(t *testing.T) {
+	g, _ := NewGo(nil)
+	path := filepath.Join(t.TempDir(), "summary.md")
+	t.Setenv(stepSummaryEnv, path)
+	os.WriteFile(path, []byte("previous step\n"), 0644)
+
+	g.writeStepSummary(&TestReport{Module: "m", Passed: true, Cached: true})
+	got, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(got), "previous step\n## gotest: m ✅ passed") || !strings.Contains(string(got), "from cache") {
+		t.Errorf("expected report appended, got:\n%s", got)
+	}
+}
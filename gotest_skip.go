@@ -0,0 +1,217 @@
+package devflow
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Phases a package can be excluded from with //devflow:skip or test.skip.*
+const (
+	SkipCoverage = "coverage"
+	SkipRace     = "race"
+	SkipWasm     = "wasm"
+	SkipLeaks    = "leaks"
+)
+
+var skipPhases = []string{SkipCoverage, SkipRace, SkipWasm, SkipLeaks}
+
+// skipDirective in any .go file of a package excludes it from the listed
+// phases (all of them when none are listed):
+//
+//	//devflow:skip coverage race
+const skipDirective = "//devflow:skip"
+
+// TestPackage is a package of the module as reported by go list
+type TestPackage struct {
+	ImportPath string
+	Dir        string // Relative to the module root, slash separated ("." for the root)
+}
+
+// PackageSkips maps a phase to the import paths excluded from it
+type PackageSkips map[string][]string
+
+// Has reports whether pkg is excluded from phase
+func (s PackageSkips) Has(phase, pkg string) bool {
+	for _, p := range s[phase] {
+		if p == pkg {
+			return true
+		}
+	}
+	return false
+}
+
+// Summary describes the exclusions for the gotest summary, e.g.
+// "skipped: coverage 2 pkgs, race 1 pkg". Empty when nothing is skipped.
+func (s PackageSkips) Summary() string {
+	var parts []string
+	for _, phase := range skipPhases {
+		switch n := len(s[phase]); n {
+		case 0:
+		case 1:
+			parts = append(parts, phase+" 1 pkg")
+		default:
+			parts = append(parts, fmt.Sprintf("%s %d pkgs", phase, n))
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "skipped: " + strings.Join(parts, ", ")
+}
+
+// ResolvePackageSkips combines the //devflow:skip directives found in the
+// package directories under root with the test.skip.<phase> config lists.
+// Config entries are package directories relative to root; "dir/..."
+// matches dir and everything below it, and globs are allowed.
+func ResolvePackageSkips(root string, pkgs []TestPackage, c *Config) PackageSkips {
+	if c == nil {
+		c = NewConfig()
+	}
+	skips := make(PackageSkips)
+	for _, pkg := range pkgs {
+		phases := readSkipDirective(filepath.Join(root, filepath.FromSlash(pkg.Dir)))
+		for _, phase := range skipPhases {
+			if phases[phase] || matchPackageDir(c.List("test.skip."+phase), pkg.Dir) {
+				skips[phase] = append(skips[phase], pkg.ImportPath)
+			}
+		}
+	}
+	for _, list := range skips {
+		sort.Strings(list)
+	}
+	return skips
+}
+
+// readSkipDirective returns the phases named by //devflow:skip lines in the
+// .go files of dir
+func readSkipDirective(dir string) map[string]bool {
+	phases := make(map[string]bool)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return phases
+	}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".go") {
+			continue
+		}
+		f, err := os.Open(filepath.Join(dir, e.Name()))
+		if err != nil {
+			continue
+		}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			rest, ok := strings.CutPrefix(line, skipDirective)
+			if !ok || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+				continue
+			}
+			names := strings.FieldsFunc(rest, func(r rune) bool { return r == ' ' || r == '\t' || r == ',' })
+			if len(names) == 0 {
+				names = skipPhases
+			}
+			for _, n := range names {
+				phases[n] = true
+			}
+		}
+		f.Close()
+	}
+	return phases
+}
+
+// matchPackageDir reports whether a package dir matches one of the patterns
+func matchPackageDir(patterns []string, dir string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.TrimSuffix(strings.TrimPrefix(filepath.ToSlash(pattern), "./"), "/")
+		if pattern == "..." {
+			return true
+		}
+		if base, ok := strings.CutSuffix(pattern, "/..."); ok {
+			if dir == base || strings.HasPrefix(dir, base+"/") {
+				return true
+			}
+			continue
+		}
+		if ok, _ := filepath.Match(pattern, dir); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// listTestPackages lists the packages of the module in the current
+// directory; env adds variables such as GOOS=js
+func listTestPackages(env ...string) ([]TestPackage, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	out, err := RunCommandWithEnvInDir(cwd, env, "go", "list", "-e", "-f", "{{.ImportPath}}\t{{.Dir}}", "./...")
+	if err != nil {
+		return nil, err
+	}
+
+	var pkgs []TestPackage
+	for _, line := range strings.Split(out, "\n") {
+		path, dir, ok := strings.Cut(strings.TrimSpace(line), "\t")
+		if !ok || dir == "" {
+			continue
+		}
+		rel, err := filepath.Rel(cwd, dir)
+		if err != nil {
+			continue
+		}
+		pkgs = append(pkgs, TestPackage{ImportPath: path, Dir: filepath.ToSlash(rel)})
+	}
+	return pkgs, nil
+}
+
+// packagesWithout returns the import paths of pkgs not excluded from phase
+func packagesWithout(pkgs []TestPackage, skips PackageSkips, phase string) []string {
+	var paths []string
+	for _, p := range pkgs {
+		if !skips.Has(phase, p.ImportPath) {
+			paths = append(paths, p.ImportPath)
+		}
+	}
+	return paths
+}
+
+// excludeCoverage drops the coverage figures of the given packages from go
+// test output so calculateAverageCoverage ignores them. With -v the figure
+// is printed on its own line before the package result line.
+func excludeCoverage(output string, skipped []string) string {
+	if len(skipped) == 0 {
+		return output
+	}
+	isSkipped := make(map[string]bool, len(skipped))
+	for _, p := range skipped {
+		isSkipped[p] = true
+	}
+
+	var out, pending []string
+	for _, line := range strings.Split(output, "\n") {
+		if m := packageLineRe.FindStringSubmatch(strings.TrimRight(line, "\r")); m != nil {
+			if isSkipped[m[2]] {
+				pending = nil
+				if i := strings.Index(line, "coverage:"); i >= 0 {
+					line = strings.TrimRight(line[:i], " \t")
+				}
+			}
+			out = append(out, pending...)
+			out = append(out, line)
+			pending = nil
+			continue
+		}
+		if strings.HasPrefix(strings.TrimSpace(line), "coverage:") {
+			pending = append(pending, line)
+			continue
+		}
+		out = append(out, line)
+	}
+	out = append(out, pending...)
+	return strings.Join(out, "\n")
+}
@@ -0,0 +1,98 @@
+package devflow
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestResolvePackageSkips(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"app.go":             "package app\n",
+		"gen/doc.go":         "//devflow:skip\npackage gen\n",
+		"exp/a/a.go":         "package a\n",
+		"exp/b/b.go":         "package b\n",
+		"slow/slow.go":       "// Package slow does not work with -race\n//devflow:skip race, wasm\npackage slow\n",
+		"other/noskip.go":    "//devflow:skipper\npackage other\n",
+		"other/internal.txt": "//devflow:skip\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(content), 0644)
+	}
+	pkgs := []TestPackage{
+		{"example.com/app", "."},
+		{"example.com/app/gen", "gen"},
+		{"example.com/app/exp/a", "exp/a"},
+		{"example.com/app/exp/b", "exp/b"},
+		{"example.com/app/slow", "slow"},
+		{"example.com/app/other", "other"},
+	}
+	c, _ := ParseConfig("test:\n  skip:\n    coverage: [exp/...]\n")
+
+	skips := ResolvePackageSkips(root, pkgs, c)
+	want := PackageSkips{
+		SkipCoverage: {"example.com/app/exp/a", "example.com/app/exp/b", "example.com/app/gen"},
+		SkipRace:     {"example.com/app/gen", "example.com/app/slow"},
+		SkipWasm:     {"example.com/app/gen", "example.com/app/slow"},
+		SkipLeaks:    {"example.com/app/gen"},
+	}
+	if !reflect.DeepEqual(skips, want) {
+		t.Errorf("got %v, want %v", skips, want)
+	}
+	if got := skips.Summary(); got != "skipped: coverage 3 pkgs, race 2 pkgs, wasm 2 pkgs, leaks 1 pkg" {
+		t.Errorf("Summary() = %q", got)
+	}
+	if got := (PackageSkips{SkipRace: {"x"}}).Summary(); got != "skipped: race 1 pkg" {
+		t.Errorf("Summary() = %q", got)
+	}
+	if got := (PackageSkips{}).Summary(); got != "" {
+		t.Errorf("empty Summary() = %q", got)
+	}
+	if got := packagesWithout(pkgs[:2], skips, SkipRace); !reflect.DeepEqual(got, []string{"example.com/app"}) {
+		t.Errorf("packagesWithout = %v", got)
+	}
+}
+
+func TestMatchPackageDir(t *testing.T) {
+	tests := []struct {
+		pattern, dir string
+		want         bool
+	}{
+		{"gen", "gen", true},
+		{"./gen/", "gen", true},
+		{"gen", "gen/sub", false},
+		{"gen/...", "gen/sub", true},
+		{"gen/...", "generated", false},
+		{"internal/*_gen", "internal/api_gen", true},
+		{"./...", "anything", true},
+	}
+	for _, tt := range tests {
+		if got := matchPackageDir([]string{tt.pattern}, tt.dir); got != tt.want {
+			t.Errorf("matchPackageDir(%q, %q) = %v, want %v", tt.pattern, tt.dir, got, tt.want)
+		}
+	}
+}
+
+func TestExcludeCoverage(t *testing.T) {
+	output := "ok  \texample.com/app\t0.1s\tcoverage: 80.0% of statements\n" +
+		"ok  \texample.com/app/gen\t0.1s\tcoverage: 5.0% of statements\n"
+	if got := calculateAverageCoverage(excludeCoverage(output, []string{"example.com/app/gen"})); got != "80" {
+		t.Errorf("coverage = %s, want 80", got)
+	}
+
+	// -v output prints the figure before the package line
+	verbose := "=== RUN   TestA\n--- PASS: TestA (0.00s)\nPASS\ncoverage: 10.0% of statements\n" +
+		"ok  \texample.com/app/gen\t0.1s\tcoverage: 10.0% of statements\n" +
+		"PASS\ncoverage: 60.0% of statements\n" +
+		"ok  \texample.com/app\t0.1s\tcoverage: 60.0% of statements\n"
+	if got := calculateAverageCoverage(excludeCoverage(verbose, []string{"example.com/app/gen"})); got != "60" {
+		t.Errorf("verbose coverage = %s, want 60", got)
+	}
+	if got := excludeCoverage(output, nil); got != output {
+		t.Errorf("no skips should leave output unchanged")
+	}
+}
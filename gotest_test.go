@@ -17,7 +17,7 @@ func TestGo_SetLog(t *testing.T) {
 	})
 
 	// Call log to verify it works
-	g.log("test")
+	g.logger.Info("test")
 
 	if !called {
 		t.Error("Expected log function to be called")
@@ -0,0 +1,201 @@
+package devflow
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// WorkspaceOptions configures a workspace test run
+type WorkspaceOptions struct {
+	Parallel int      // Modules tested at once (default 1)
+	Command  string   // gotest command run in each module (default "gotest")
+	Args     []string // Flags passed to every module's gotest (e.g. -race=off)
+}
+
+// WorkspaceModule is the result of one module of a workspace test run
+type WorkspaceModule struct {
+	Dir      string // Relative to the workspace root
+	Path     string // Module path
+	Passed   bool
+	Coverage string // e.g. "83%", empty when not reported
+	Duration time.Duration
+	Summary  string // gotest summary line
+	Output   string // Full gotest output
+}
+
+var workspaceCoverageRe = regexp.MustCompile(`coverage: (\d+(?:\.\d+)?)%`)
+
+// FindWorkspaceModules returns the module dirs of root, relative to it:
+// the use directives of root/go.work, else every go.mod below root
+// (vendor, testdata and hidden dirs are skipped)
+func FindWorkspaceModules(root string) ([]string, error) {
+	if dirs, err := parseGoWorkUses(filepath.Join(root, "go.work")); err == nil {
+		return dirs, nil
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var dirs []string
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() && path != root {
+			name := d.Name()
+			if name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+				return filepath.SkipDir
+			}
+		}
+		if !d.IsDir() && d.Name() == "go.mod" {
+			rel, _ := filepath.Rel(root, filepath.Dir(path))
+			dirs = append(dirs, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	sort.Strings(dirs)
+	return dirs, err
+}
+
+// parseGoWorkUses reads the use directives of a go.work file, both
+// "use ./a" and "use ( ./a ./b )" forms
+func parseGoWorkUses(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var dirs []string
+	inBlock := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		switch {
+		case inBlock && line == ")":
+			inBlock = false
+		case inBlock && line != "":
+			dirs = append(dirs, cleanWorkDir(line))
+		case line == "use (":
+			inBlock = true
+		case strings.HasPrefix(line, "use "):
+			dirs = append(dirs, cleanWorkDir(strings.TrimPrefix(line, "use ")))
+		}
+	}
+	return dirs, scanner.Err()
+}
+
+func cleanWorkDir(s string) string {
+	return filepath.ToSlash(filepath.Clean(strings.Trim(strings.TrimSpace(s), `"`)))
+}
+
+// TestWorkspace runs the gotest pipeline in every module of root (see
+// FindWorkspaceModules), each in its own process so modules can run in
+// parallel. Returns an error when any module failed.
+func (g *Go) TestWorkspace(root string, opts WorkspaceOptions) ([]WorkspaceModule, error) {
+	dirs, err := FindWorkspaceModules(root)
+	if err != nil {
+		return nil, err
+	}
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("no Go modules found in %s", root)
+	}
+	if opts.Command == "" {
+		opts.Command = "gotest"
+	}
+	if opts.Parallel < 1 {
+		opts.Parallel = 1
+	}
+
+	results := make([]WorkspaceModule, len(dirs))
+	sem := make(chan struct{}, opts.Parallel)
+	var wg sync.WaitGroup
+	for i, dir := range dirs {
+		wg.Add(1)
+		go func(i int, dir string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i] = g.testWorkspaceModule(root, dir, opts)
+		}(i, dir)
+	}
+	wg.Wait()
+
+	var failed []string
+	for _, m := range results {
+		if !m.Passed {
+			failed = append(failed, m.Dir)
+		}
+	}
+	if len(failed) > 0 {
+		return results, fmt.Errorf("%d of %d module(s) failed: %s", len(failed), len(results), strings.Join(failed, ", "))
+	}
+	return results, nil
+}
+
+// testWorkspaceModule runs gotest in one module
+func (g *Go) testWorkspaceModule(root, dir string, opts WorkspaceOptions) WorkspaceModule {
+	m := WorkspaceModule{Dir: dir}
+	modDir := filepath.Join(root, filepath.FromSlash(dir))
+	if module, err := ReadModule(modDir); err == nil {
+		m.Path = module.Path
+	}
+	g.logger.Info("Testing", dir)
+
+	var out bytes.Buffer
+	start := time.Now()
+	_, err := runCommand(&Command{Name: opts.Command, Args: opts.Args, Dir: modDir, Stdout: &out})
+	m.Duration = time.Since(start)
+	m.Passed = err == nil
+	m.Output = out.String()
+
+	lines := strings.Split(strings.TrimSpace(m.Output), "\n")
+	m.Summary = strings.TrimPrefix(lines[len(lines)-1], "Tests failed: ")
+	if err != nil && m.Output == "" {
+		m.Summary = err.Error()
+	}
+	if match := workspaceCoverageRe.FindStringSubmatch(m.Summary); match != nil {
+		m.Coverage = match[1] + "%"
+	}
+	return m
+}
+
+// FormatWorkspaceTable renders the per-module status, coverage and duration
+func FormatWorkspaceTable(modules []WorkspaceModule) string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MODULE\tDIR\tSTATUS\tCOVERAGE\tTIME")
+	passed := 0
+	var total time.Duration
+	for _, m := range modules {
+		status := "❌ failed"
+		if m.Passed {
+			status = "✅ ok"
+			passed++
+		}
+		cov := m.Coverage
+		if cov == "" {
+			cov = "-"
+		}
+		path := m.Path
+		if path == "" {
+			path = "?"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", path, m.Dir, status, cov, m.Duration.Round(100*time.Millisecond))
+		total += m.Duration
+	}
+	w.Flush()
+	fmt.Fprintf(&b, "%d/%d modules passed (%s total)", passed, len(modules), total.Round(100*time.Millisecond))
+	return b.String()
+}
@@ -0,0 +1,61 @@
+package devflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testWorkspace(t *testing.T, mods ...string) string {
+	t.Helper()
+	root := t.TempDir()
+	for _, dir := range mods {
+		os.MkdirAll(filepath.Join(root, dir), 0755)
+		os.WriteFile(filepath.Join(root, dir, "go.mod"), []byte("module example.com/"+filepath.Base(dir)+"\n"), 0644)
+	}
+	return root
+}
+
+func TestFindWorkspaceModules(t *testing.T) {
+	root := testWorkspace(t, "a", "libs/b", "vendor/c", ".cache/d", "a/testdata/e")
+	dirs, err := FindWorkspaceModules(root)
+	if err != nil || strings.Join(dirs, " ") != "a libs/b" {
+		t.Errorf("tree modules = %v, %v", dirs, err)
+	}
+
+	os.WriteFile(filepath.Join(root, "go.work"), []byte("go 1.22\n\nuse (\n\t./libs/b // shared\n\t\"./a\"\n)\n\nuse ./tools\n"), 0644)
+	dirs, err = FindWorkspaceModules(root)
+	if err != nil || strings.Join(dirs, " ") != "libs/b a tools" {
+		t.Errorf("go.work modules = %v, %v", dirs, err)
+	}
+}
+
+func TestTestWorkspace(t *testing.T) {
+	root := testWorkspace(t, "a", "b", "c")
+	os.WriteFile(filepath.Join(root, "b", "fail"), nil, 0644)
+	g, _ := NewGo(nil)
+
+	script := `test -f fail && { echo 'Tests failed: ✅ vet ok, ❌ tests stdlib failed'; exit 1; }; echo "✅ vet ok, ✅ coverage: 75%, $1"`
+	modules, err := g.TestWorkspace(root, WorkspaceOptions{Parallel: 2, Command: "sh", Args: []string{"-c", script, "sh", "-race=off"}})
+	if err == nil || !strings.Contains(err.Error(), "1 of 3 module(s) failed: b") {
+		t.Errorf("err = %v", err)
+	}
+	if len(modules) != 3 || !modules[0].Passed || modules[1].Passed || modules[0].Coverage != "75%" || modules[0].Path != "example.com/a" {
+		t.Fatalf("unexpected modules: %+v", modules)
+	}
+	if !strings.HasSuffix(modules[2].Summary, "-race=off") || modules[1].Summary != "✅ vet ok, ❌ tests stdlib failed" {
+		t.Errorf("unexpected summaries: %q, %q", modules[2].Summary, modules[1].Summary)
+	}
+
+	table := FormatWorkspaceTable(modules)
+	for _, want := range []string{"MODULE", "example.com/b  b    ❌ failed", "2/3 modules passed"} {
+		if !strings.Contains(table, want) {
+			t.Errorf("table missing %q:\n%s", want, table)
+		}
+	}
+
+	if _, err := g.TestWorkspace(t.TempDir(), WorkspaceOptions{}); err == nil {
+		t.Error("expected error without modules")
+	}
+}
@@ -5,8 +5,26 @@ import (
 	"os/exec"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
+// Tests must not depend on the sandbox network: hosts are reported online
+// unless a test stubs probeNetwork itself
+func init() {
+	probeNetwork = func(host string, _ time.Duration) NetworkStatus {
+		return NetworkStatus{Host: host, Online: true, Resolved: true}
+	}
+}
+
+// testOffline makes probeNetwork report every host offline for the test
+func testOffline(t *testing.T, resolved bool, reason string) {
+	prev := probeNetwork
+	probeNetwork = func(host string, _ time.Duration) NetworkStatus {
+		return NetworkStatus{Host: host, Resolved: resolved, Reason: reason}
+	}
+	t.Cleanup(func() { probeNetwork = prev })
+}
+
 // testChdir changes to the specified directory and returns a cleanup function.
 // If chdir fails, it calls t.Fatal.
 func testChdir(t *testing.T, dir string) func() {
@@ -1,5 +1,7 @@
 package devflow
 
+import "time"
+
 // GitHubClient defines the interface for GitHub operations.
 // This allows mocking the GitHub dependency in tests.
 type GitHubClient interface {
@@ -12,6 +14,70 @@ type GitHubClient interface {
 	GetHelpfulErrorMessage(err error) string
 }
 
+// RepoProvider is a hosting service gonew can create remotes on. GitHub,
+// GitLab and Gitea implement it; the GitHubClient methods are shared so
+// existing clients and mocks keep working.
+type RepoProvider interface {
+	GitHubClient
+	Name() string                      // "github", "gitlab" or "gitea"
+	Host() string                      // e.g. "github.com", "gitlab.example.com"
+	RepoURL(owner, name string) string // HTTPS clone URL
+}
+
+// LoggerSetter is implemented by the handlers that take a leveled Logger
+// besides the SetLog function
+type LoggerSetter interface {
+	SetLogger(l Logger)
+}
+
+// RepoCreator is implemented by providers with two explicit ways of
+// creating a repository: empty, for a local repository that does not
+// exist yet (gonew creates it alongside the files), or from an existing
+// local repository, which becomes origin and is pushed in the same step
+type RepoCreator interface {
+	CreateRemoteOnly(owner, name, description, visibility string) error
+	CreateFromLocal(path, owner, name, description, visibility string) error
+}
+
+// BranchDetector is implemented by git clients that can tell the default
+// branch of the repository (main, master, trunk, ...)
+type BranchDetector interface {
+	DefaultBranch() (string, error)
+}
+
+// BranchIniter is implemented by git clients that can start a repository
+// on a chosen branch
+type BranchIniter interface {
+	InitBranch() string
+	InitRepoBranch(dir, branch string) error
+}
+
+// AuthorProfiler is implemented by providers that can read the profile
+// (display name, public email, website) of the authenticated user
+type AuthorProfiler interface {
+	AuthorProfile() (Author, error)
+}
+
+// EmptyRepoChecker is implemented by providers that can tell whether an
+// existing repository has no commits yet, so gonew can adopt it (e.g. when
+// a create is retried after the remote was made)
+type EmptyRepoChecker interface {
+	RepoIsEmpty(owner, name string) (bool, error)
+}
+
+// GitProtocolReader is implemented by providers whose CLI records the
+// protocol git remotes should use, for the auto remote protocol
+type GitProtocolReader interface {
+	GitProtocol() string // ssh or https
+}
+
+// OwnerChecker is implemented by providers that can tell whether the
+// authenticated user may create repositories under an owner, so gonew
+// fails before creating anything under an organization it cannot use
+type OwnerChecker interface {
+	CheckOwner(owner, visibility string) error
+}
+
 // GitHubAuthenticator defines the interface for GitHub authentication.
 // This allows mocking authentication in tests.
 type GitHubAuthenticator interface {
@@ -43,6 +109,26 @@ type GitClient interface {
 	PushWithTags(tag string) error
 }
 
+// Releaser publishes a release of an existing tag with its notes and
+// asset files, returning the release URL
+type Releaser interface {
+	CreateRelease(tag, notes string, assets []string) (string, error)
+}
+
+// ReleaseUploader is implemented by releasers that can attach assets to
+// a release created earlier
+type ReleaseUploader interface {
+	ReleaseExists(tag string) (bool, error)
+	UploadReleaseAssets(tag string, assets []string) error
+}
+
+// KeyUploader registers public SSH keys with an account, for
+// authentication or for verifying signed commits
+type KeyUploader interface {
+	SSHKeys(signing bool) ([]string, error)
+	AddSSHKey(title, publicKey string, signing bool) error
+}
+
 // FolderWatcher defines interface for adding/removing directories to watch
 type FolderWatcher interface {
 	AddDirectoriesToWatch(paths ...string) error
@@ -61,3 +147,46 @@ type GoModInterface interface {
 	SetRootDir(path string)
 	GetReplacePaths() ([]ReplaceEntry, error)
 }
+
+// TemplateSearcher is implemented by providers that can list the
+// repositories published as gonew templates (see TemplateTopic)
+type TemplateSearcher interface {
+	SearchTemplates(query string) ([]TemplateListing, error)
+}
+
+// GistFetcher is implemented by providers that host gists, the snippets
+// gonew copies into new projects
+type GistFetcher interface {
+	FetchGist(id, revision string) (*Gist, error)
+}
+
+// PullRequester opens pull requests on the hosting service and enables
+// their auto-merge, for the pull request workflow of Go.PushPR
+type PullRequester interface {
+	CreatePullRequest(pr PullRequest) (string, error) // Returns the pull request URL
+	EnableAutoMerge(url, method string) error
+}
+
+// PullRequestMerger is implemented by pull request clients that can wait
+// for the checks of a pull request and merge it, for Go.PushPR with
+// PullRequestOptions.WaitChecks
+type PullRequestMerger interface {
+	WaitForPRChecks(pr string, timeout time.Duration) ([]PRCheck, error)
+	MergePR(pr, method string, deleteBranch bool) error
+}
+
+// BranchPusher is implemented by git clients that can commit and push the
+// work to a topic branch without tagging it, for pull request workflows
+type BranchPusher interface {
+	BranchDetector
+	CurrentBranch() (string, error)
+	PushBranch(message, branch string) (string, error)
+	CommitsAhead(base string) ([]string, error)
+}
+
+// RepoConfigurer is implemented by providers that can change the settings
+// of a repository (topics, merges, branch protection), which gonew applies
+// once a new project is pushed
+type RepoConfigurer interface {
+	ConfigureRepo(owner, name string, s RepoSettings) error
+}
@@ -0,0 +1,191 @@
+package devflow
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// interruptGrace is how long child processes get to exit after an
+// interrupt before they are killed
+var interruptGrace = 3 * time.Second
+
+// interruptExit ends the process after an interrupt (replaced in tests)
+var interruptExit = os.Exit
+
+// interruptCleanup is a function registered with OnInterrupt
+type interruptCleanup struct {
+	fn func()
+}
+
+// interrupts holds the child processes started through the executor and
+// the cleanups to run when the command is interrupted
+var interrupts struct {
+	sync.Mutex
+	dir      string
+	procs    map[*exec.Cmd]bool // Value: started in its own process group
+	cleanups []*interruptCleanup
+}
+
+// HandleInterrupts makes Ctrl-C (SIGINT) and SIGTERM stop the running
+// child processes, run the OnInterrupt cleanups, restore the working
+// directory the command started in and exit with status 130. Commands
+// call it first thing in main; the returned function stops handling.
+func HandleInterrupts() (stop func()) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, os.Interrupt, syscall.SIGTERM)
+	wd, _ := os.Getwd()
+
+	interrupts.Lock()
+	interrupts.dir = wd
+	interrupts.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case sig := <-ch:
+			fmt.Fprintf(OutputWriter(os.Stderr), "\n⚠️ %s: stopping and cleaning up...\n", sig)
+			handleInterrupt(sig == os.Interrupt)
+			interruptExit(130)
+		case <-done:
+		}
+	}()
+	return func() {
+		signal.Stop(ch)
+		close(done)
+	}
+}
+
+// OnInterrupt registers fn to run if the command is interrupted before
+// the returned function is called. Cleanups run last registered first:
+// defer OnInterrupt(func() { os.RemoveAll(dir) })()
+func OnInterrupt(fn func()) (remove func()) {
+	c := &interruptCleanup{fn: fn}
+	interrupts.Lock()
+	interrupts.cleanups = append(interrupts.cleanups, c)
+	interrupts.Unlock()
+	return func() {
+		interrupts.Lock()
+		defer interrupts.Unlock()
+		for i, other := range interrupts.cleanups {
+			if other == c {
+				interrupts.cleanups = append(interrupts.cleanups[:i], interrupts.cleanups[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// handleInterrupt stops the tracked processes, runs the cleanups and
+// restores the original directory. fromTerminal is true for Ctrl-C, which
+// the terminal already delivered to the processes sharing our group.
+func handleInterrupt(fromTerminal bool) {
+	stopTrackedProcesses(fromTerminal)
+
+	interrupts.Lock()
+	cleanups := interrupts.cleanups
+	interrupts.cleanups = nil
+	dir := interrupts.dir
+	interrupts.Unlock()
+
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		runInterruptCleanup(cleanups[i].fn)
+	}
+	if dir != "" {
+		os.Chdir(dir)
+	}
+}
+
+// runInterruptCleanup runs fn, so a panicking cleanup does not skip the others
+func runInterruptCleanup(fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintln(os.Stderr, "⚠️ cleanup failed:", r)
+		}
+	}()
+	fn()
+}
+
+// stopTrackedProcesses interrupts the tracked processes, then kills the
+// ones still running after interruptGrace
+func stopTrackedProcesses(fromTerminal bool) {
+	interrupts.Lock()
+	for cmd, group := range interrupts.procs {
+		if group || !fromTerminal {
+			signalProcess(cmd, group, false)
+		}
+	}
+	interrupts.Unlock()
+
+	deadline := time.Now().Add(interruptGrace)
+	for time.Now().Before(deadline) {
+		interrupts.Lock()
+		n := len(interrupts.procs)
+		interrupts.Unlock()
+		if n == 0 {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	interrupts.Lock()
+	for cmd, group := range interrupts.procs {
+		signalProcess(cmd, group, true)
+	}
+	interrupts.Unlock()
+}
+
+// signalProcess interrupts (or with force, kills) cmd, or its whole
+// process group when it was started in one
+func signalProcess(cmd *exec.Cmd, group, force bool) {
+	switch {
+	case group:
+		stopProcessGroup(cmd, force)
+	case force:
+		_ = cmd.Process.Kill()
+	default:
+		_ = cmd.Process.Signal(os.Interrupt)
+	}
+}
+
+// trackProcess records a started cmd until the returned function is
+// called. group tells whether cmd runs in its own process group, which
+// Ctrl-C at the terminal does not reach.
+func trackProcess(cmd *exec.Cmd, group bool) (untrack func()) {
+	interrupts.Lock()
+	if interrupts.procs == nil {
+		interrupts.procs = make(map[*exec.Cmd]bool)
+	}
+	interrupts.procs[cmd] = group
+	interrupts.Unlock()
+	return func() {
+		interrupts.Lock()
+		delete(interrupts.procs, cmd)
+		interrupts.Unlock()
+	}
+}
+
+// runTracked runs cmd, tracked so an interrupt can stop it. Commands stay
+// in the terminal's process group so prompts (ssh passphrases, credential
+// helpers) keep working.
+func runTracked(cmd *exec.Cmd) error {
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	defer trackProcess(cmd, false)()
+	return cmd.Wait()
+}
+
+// combinedOutput is cmd.CombinedOutput through runTracked
+func combinedOutput(cmd *exec.Cmd) ([]byte, error) {
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := runTracked(cmd)
+	return out.Bytes(), err
+}
@@ -0,0 +1,119 @@
+package devflow
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandleInterruptStopsProcessesAndCleansUp(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sleep")
+	}
+	wd, _ := os.Getwd()
+	original := interrupts.dir
+	interrupts.dir = wd
+	defer func() { interrupts.dir = original }()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := RunCommand("sleep", "30")
+		done <- err
+	}()
+	waitTracked(t, 1)
+
+	var order []string
+	OnInterrupt(func() { order = append(order, "first") })
+	remove := OnInterrupt(func() { order = append(order, "removed") })
+	OnInterrupt(func() { order = append(order, "last") })
+	remove()
+
+	other := t.TempDir()
+	os.Chdir(other)
+	defer os.Chdir(wd)
+
+	start := time.Now()
+	handleInterrupt(false)
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("expected the interrupted command to fail")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("tracked process was not stopped")
+	}
+	if time.Since(start) > 2*time.Second {
+		t.Errorf("interrupt should not wait for the kill grace period, took %v", time.Since(start))
+	}
+	if strings.Join(order, ",") != "last,first" {
+		t.Errorf("expected cleanups last,first, got %v", order)
+	}
+	if now, _ := os.Getwd(); now != wd {
+		t.Errorf("expected working dir %s restored, got %s", wd, now)
+	}
+	if len(interrupts.cleanups) != 0 {
+		t.Errorf("cleanups should be consumed, got %d", len(interrupts.cleanups))
+	}
+}
+
+func TestHandleInterruptKillsProcessGroup(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh and process groups")
+	}
+	defer func(d time.Duration) { interruptGrace = d }(interruptGrace)
+	interruptGrace = 200 * time.Millisecond
+
+	// The shell ignores SIGINT and its child sleeps on, like a stuck test binary
+	marker := filepath.Join(t.TempDir(), "started")
+	cmd := exec.Command("sh", "-c", `trap "" INT; touch `+marker+`; sleep 30; sleep 30`)
+	var b testBudget
+	b.deadline = time.Now().Add(time.Hour)
+	done := make(chan error, 1)
+	go func() { done <- b.run(cmd) }()
+	waitTracked(t, 1)
+	for i := 0; i < 100; i++ {
+		if _, err := os.Stat(marker); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	handleInterrupt(true)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("process group was not killed")
+	}
+}
+
+func TestPushResumeHint(t *testing.T) {
+	if hint := pushResumeHint("v1.2.0", true); !strings.Contains(hint, "git push && git push origin v1.2.0") {
+		t.Errorf("tagged hint: %s", hint)
+	}
+	if hint := pushResumeHint("v1.2.0", false); !strings.Contains(hint, "git tag v1.2.0") {
+		t.Errorf("untagged hint: %s", hint)
+	}
+	if hint := pushResumeHint("", false); !strings.HasSuffix(hint, "git push") {
+		t.Errorf("no-tag hint: %s", hint)
+	}
+}
+
+// waitTracked waits until n child processes are tracked
+func waitTracked(t *testing.T, n int) {
+	t.Helper()
+	for i := 0; i < 200; i++ {
+		interrupts.Lock()
+		count := len(interrupts.procs)
+		interrupts.Unlock()
+		if count >= n {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("expected %d tracked processes", n)
+}
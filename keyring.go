@@ -1,7 +1,9 @@
 package devflow
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"runtime"
@@ -101,9 +103,8 @@ func (k *Keyring) tryInstallKeyring() bool {
 	for _, m := range managers {
 		if _, err := exec.LookPath(m.cmd); err == nil {
 			k.log(fmt.Sprintf("   Installing via %s...", m.cmd))
-			cmd := exec.Command(m.args[0], m.args[1:]...)
 			// We don't pipe to os.Stdout anymore to keep it quiet unless logged
-			if cmd.Run() == nil {
+			if _, err := runCommand(&Command{Name: m.args[0], Args: m.args[1:]}); err == nil {
 				return true
 			}
 		}
@@ -117,10 +118,10 @@ func (k *Keyring) startKeyringService() {
 		return
 	}
 
-	cmd := exec.Command("gnome-keyring-daemon", "--start", "--components=secrets")
-	output, err := cmd.Output()
+	var output bytes.Buffer
+	_, err := runCommand(&Command{Name: "gnome-keyring-daemon", Args: []string{"--start", "--components=secrets"}, Stdout: &output, Stderr: io.Discard})
 	if err == nil {
-		for _, line := range strings.Split(string(output), "\n") {
+		for _, line := range strings.Split(output.String(), "\n") {
 			if parts := strings.SplitN(line, "=", 2); len(parts) == 2 {
 				os.Setenv(parts[0], parts[1])
 			}
@@ -0,0 +1,244 @@
+package devflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// leakCheckFile is the test file added to each package through -overlay;
+// the source tree is never modified
+const leakCheckFile = "zz_devflow_leak_test.go"
+
+// leakMarkerRe matches the line printed by the generated TestMain when
+// goroutines are still running after the tests
+var leakMarkerRe = regexp.MustCompile(`(?m)^devflow:leaks (\S+) (\d+)$`)
+
+// defaultLeakIgnore are functions of goroutines owned by the runtime and
+// the testing package, never reported as leaks
+var defaultLeakIgnore = []string{
+	"testing.(*M).",
+	"testing.runFuzzing",
+	"os/signal.signal_recv",
+	"os/signal.loop",
+	"runtime.ensureSigM",
+	"runtime/trace.",
+	"runtime.ReadTrace",
+}
+
+// leakPackage is a package with tests, as far as the leak check cares
+type leakPackage struct {
+	ImportPath string
+	Dir        string // Absolute
+	Name       string
+	TestFiles  []string
+}
+
+// LeakCheck holds the overlay that injects the leak-checking TestMain
+type LeakCheck struct {
+	Overlay  string   // Path of the -overlay JSON file
+	Packages []string // Import paths checked
+	Custom   []string // Import paths skipped because they define TestMain
+	dir      string
+}
+
+// LeakResult is the outcome of a leak-checked test run
+type LeakResult struct {
+	Goroutines int
+	Packages   []string // Import paths with leaks
+}
+
+// Summary returns "leaks: none" or e.g. "leaks: 3 goroutines in 2 pkgs"
+func (r LeakResult) Summary() string {
+	if r.Goroutines == 0 {
+		return "leaks: none"
+	}
+	goroutines, pkgs := "1 goroutine", "1 pkg"
+	if r.Goroutines != 1 {
+		goroutines = fmt.Sprintf("%d goroutines", r.Goroutines)
+	}
+	if len(r.Packages) != 1 {
+		pkgs = fmt.Sprintf("%d pkgs", len(r.Packages))
+	}
+	return fmt.Sprintf("leaks: %s in %s", goroutines, pkgs)
+}
+
+// PrepareLeakCheck writes a TestMain for every package with tests that
+// reports goroutines still running once the tests are done. Packages that
+// already define TestMain, or are excluded with //devflow:skip leaks, are
+// left out. ignore lists extra function names (substrings of a goroutine
+// stack) that are not leaks.
+func PrepareLeakCheck(skips PackageSkips, ignore []string) (*LeakCheck, error) {
+	pkgs, err := listLeakPackages()
+	if err != nil {
+		return nil, err
+	}
+	dir, err := os.MkdirTemp("", "devflow-leaks-")
+	if err != nil {
+		return nil, err
+	}
+	lc := &LeakCheck{dir: dir}
+	overlay := map[string]map[string]string{"Replace": {}}
+
+	ignore = append(append([]string{}, defaultLeakIgnore...), ignore...)
+	for i, pkg := range pkgs {
+		if len(pkg.TestFiles) == 0 || skips.Has(SkipLeaks, pkg.ImportPath) {
+			continue
+		}
+		if hasTestMain(pkg) {
+			lc.Custom = append(lc.Custom, pkg.ImportPath)
+			continue
+		}
+		src := filepath.Join(dir, fmt.Sprintf("%d.go", i))
+		if err := os.WriteFile(src, []byte(leakCheckSource(pkg, ignore)), 0644); err != nil {
+			lc.Cleanup()
+			return nil, err
+		}
+		overlay["Replace"][filepath.Join(pkg.Dir, leakCheckFile)] = src
+		lc.Packages = append(lc.Packages, pkg.ImportPath)
+	}
+
+	data, err := json.Marshal(overlay)
+	if err != nil {
+		lc.Cleanup()
+		return nil, err
+	}
+	lc.Overlay = filepath.Join(dir, "overlay.json")
+	if err := os.WriteFile(lc.Overlay, data, 0644); err != nil {
+		lc.Cleanup()
+		return nil, err
+	}
+	return lc, nil
+}
+
+// Args adds -overlay to go test args
+func (lc *LeakCheck) Args(args []string) []string {
+	if lc == nil || len(lc.Packages) == 0 {
+		return args
+	}
+	return append([]string{args[0], "-overlay=" + lc.Overlay}, args[1:]...)
+}
+
+// Cleanup removes the generated files
+func (lc *LeakCheck) Cleanup() {
+	if lc != nil {
+		os.RemoveAll(lc.dir)
+	}
+}
+
+// ParseLeaks collects the leak reports in go test output
+func ParseLeaks(output string) LeakResult {
+	var r LeakResult
+	for _, m := range leakMarkerRe.FindAllStringSubmatch(output, -1) {
+		n, _ := strconv.Atoi(m[2])
+		r.Goroutines += n
+		r.Packages = append(r.Packages, m[1])
+	}
+	return r
+}
+
+func listLeakPackages() ([]leakPackage, error) {
+	out, err := RunCommandSilent("go", "list", "-e", "-f",
+		`{{.ImportPath}}{{"\t"}}{{.Dir}}{{"\t"}}{{.Name}}{{"\t"}}{{join .TestGoFiles ","}}{{"\t"}}{{join .XTestGoFiles ","}}`, "./...")
+	if err != nil {
+		return nil, err
+	}
+	var pkgs []leakPackage
+	for _, line := range strings.Split(out, "\n") {
+		f := strings.Split(line, "\t")
+		if len(f) != 5 || f[1] == "" {
+			continue
+		}
+		pkg := leakPackage{ImportPath: f[0], Dir: f[1], Name: f[2]}
+		for _, files := range f[3:] {
+			if files != "" {
+				pkg.TestFiles = append(pkg.TestFiles, strings.Split(files, ",")...)
+			}
+		}
+		pkgs = append(pkgs, pkg)
+	}
+	return pkgs, nil
+}
+
+// hasTestMain reports whether a test file of pkg declares TestMain
+func hasTestMain(pkg leakPackage) bool {
+	for _, name := range pkg.TestFiles {
+		data, err := os.ReadFile(filepath.Join(pkg.Dir, name))
+		if err == nil && strings.Contains(string(data), "func TestMain(") {
+			return true
+		}
+	}
+	return false
+}
+
+// leakCheckSource is the TestMain added to pkg, in its external test
+// package so it compiles next to any existing test files
+func leakCheckSource(pkg leakPackage, ignore []string) string {
+	return fmt.Sprintf(`package %s_test
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+var devflowLeakIgnore = %#v
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if code == 0 {
+		if leaked := devflowLeakedGoroutines(); len(leaked) > 0 {
+			fmt.Fprintf(os.Stderr, "leaked goroutines:\n\n%%s\n\n", strings.Join(leaked, "\n\n"))
+			fmt.Fprintf(os.Stderr, "devflow:leaks %%s %%d\n", %q, len(leaked))
+			code = 1
+		}
+	}
+	os.Exit(code)
+}
+
+// devflowLeakedGoroutines retries for a second so goroutines that are
+// already exiting are not reported
+func devflowLeakedGoroutines() []string {
+	var leaked []string
+	for i := 0; i < 20; i++ {
+		leaked = leaked[:0]
+		buf := make([]byte, 1<<16)
+		for {
+			n := runtime.Stack(buf, true)
+			if n < len(buf) {
+				buf = buf[:n]
+				break
+			}
+			buf = make([]byte, 2*len(buf))
+		}
+		// The first stack is this goroutine
+		for _, g := range strings.Split(string(buf), "\n\n")[1:] {
+			if !devflowLeakIgnored(g) {
+				leaked = append(leaked, g)
+			}
+		}
+		if len(leaked) == 0 {
+			return nil
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return leaked
+}
+
+func devflowLeakIgnored(stack string) bool {
+	for _, fn := range devflowLeakIgnore {
+		if strings.Contains(stack, fn) {
+			return true
+		}
+	}
+	return false
+}
+`, pkg.Name, ignore, pkg.ImportPath)
+}
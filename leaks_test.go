@@ -0,0 +1,79 @@
+package devflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLeakResultSummary(t *testing.T) {
+	out := "ok  \texample.com/m/a\t0.1s\ndevflow:leaks example.com/m/b 2\nFAIL\texample.com/m/b\t1.2s\ndevflow:leaks example.com/m/c 1\n"
+	r := ParseLeaks(out)
+	if r.Goroutines != 3 || strings.Join(r.Packages, ",") != "example.com/m/b,example.com/m/c" {
+		t.Fatalf("unexpected result: %+v", r)
+	}
+	if got := r.Summary(); got != "leaks: 3 goroutines in 2 pkgs" {
+		t.Errorf("Summary = %q", got)
+	}
+	if got := (LeakResult{Goroutines: 1, Packages: []string{"x"}}).Summary(); got != "leaks: 1 goroutine in 1 pkg" {
+		t.Errorf("Summary = %q", got)
+	}
+	if got := ParseLeaks("ok  \tx\t0.1s\n").Summary(); got != "leaks: none" {
+		t.Errorf("Summary = %q", got)
+	}
+}
+
+func TestLeakCheck(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":                "module example.com/leaky\n\ngo 1.21\n",
+		"leak/leak.go":          "package leak\n",
+		"leak/leak_test.go":     "package leak\n\nimport (\n\t\"testing\"\n\t\"time\"\n)\n\nfunc TestLeak(t *testing.T) { go time.Sleep(time.Hour) }\n",
+		"clean/clean_test.go":   "package clean\n\nimport \"testing\"\n\nfunc TestClean(t *testing.T) {}\n",
+		"custom/custom_test.go": "package custom\n\nimport (\n\t\"os\"\n\t\"testing\"\n)\n\nfunc TestMain(m *testing.M) { os.Exit(m.Run()) }\n",
+		"notests/notests.go":    "package notests\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(content), 0644)
+	}
+	defer testChdir(t, dir)()
+	if c := strings.TrimSpace(string(startGoCache)); c != "" {
+		t.Setenv("GOCACHE", c)
+	}
+
+	lc, err := PrepareLeakCheck(PackageSkips{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lc.Cleanup()
+	if strings.Join(lc.Packages, ",") != "example.com/leaky/clean,example.com/leaky/leak" {
+		t.Errorf("checked packages = %v", lc.Packages)
+	}
+	if strings.Join(lc.Custom, ",") != "example.com/leaky/custom" {
+		t.Errorf("custom TestMain packages = %v", lc.Custom)
+	}
+
+	out, err := RunCommandSilent("go", lc.Args([]string{"test", "-count=1", "./..."})...)
+	if err == nil {
+		t.Fatalf("expected the leaking package to fail:\n%s", out)
+	}
+	r := ParseLeaks(out)
+	if r.Goroutines != 1 || strings.Join(r.Packages, ",") != "example.com/leaky/leak" {
+		t.Errorf("unexpected leaks %+v in:\n%s", r, out)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "leak", leakCheckFile)); !os.IsNotExist(err) {
+		t.Error("leak check file written into the source tree")
+	}
+
+	skipped, err := PrepareLeakCheck(PackageSkips{SkipLeaks: {"example.com/leaky/leak"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer skipped.Cleanup()
+	if strings.Join(skipped.Packages, ",") != "example.com/leaky/clean" {
+		t.Errorf("skipped packages still checked: %v", skipped.Packages)
+	}
+}
@@ -0,0 +1,293 @@
+package devflow
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultLicenseHeader is the header template used unless
+// license.header_template points to a project file
+const DefaultLicenseHeader = "// SPDX-License-Identifier: {{license}}\n// Copyright {{year}} {{holder}}"
+
+const spdxTag = "SPDX-License-Identifier:"
+
+var generatedFileRe = regexp.MustCompile(`(?m)^// Code generated .* DO NOT EDIT\.$`)
+
+// LicenseHeader inserts and verifies SPDX license headers in .go files.
+// Settings come from the license.* keys of the project config:
+//
+//	license:
+//	  spdx: Apache-2.0                # default: detected from LICENSE
+//	  holder: ACME Inc.               # default: git user.name
+//	  header_template: .header.txt    # default: DefaultLicenseHeader
+//	  check: true                     # gotest verifies headers
+//	  exclude: [internal/gen/*]
+type LicenseHeader struct {
+	rootDir  string
+	SPDX     string
+	Holder   string
+	Template string
+	Exclude  []string
+	log      func(...any)
+}
+
+// LicenseHeaderReport lists the files that fail the header check
+type LicenseHeaderReport struct {
+	Checked    int
+	Missing    []string // No SPDX identifier
+	Mismatched []string // SPDX identifier differs from the project license
+}
+
+// OK reports whether every checked file has the expected header
+func (r LicenseHeaderReport) OK() bool {
+	return len(r.Missing) == 0 && len(r.Mismatched) == 0
+}
+
+// Summary returns a one-line description for the gotest summary
+func (r LicenseHeaderReport) Summary() string {
+	if r.OK() {
+		return "license headers ok"
+	}
+	return fmt.Sprintf("license headers: %d missing, %d mismatched", len(r.Missing), len(r.Mismatched))
+}
+
+// NewLicenseHeader loads the header settings for the project at rootDir
+func NewLicenseHeader(rootDir string, c *Config) (*LicenseHeader, error) {
+	if c == nil {
+		c = NewConfig()
+	}
+	h := &LicenseHeader{
+		rootDir:  rootDir,
+		SPDX:     c.String("license.spdx", ""),
+		Holder:   c.String("license.holder", ""),
+		Template: DefaultLicenseHeader,
+		Exclude:  c.List("license.exclude"),
+		log:      func(...any) {},
+	}
+
+	if h.SPDX == "" {
+		h.SPDX = DetectLicense(rootDir)
+	}
+	if h.SPDX == "" {
+		return nil, fmt.Errorf("license: set license.spdx, no known license found in LICENSE")
+	}
+	if h.Holder == "" {
+		h.Holder, _ = RunCommandInDir(rootDir, "git", "config", "user.name")
+	}
+
+	if path := c.String("license.header_template", ""); path != "" {
+		data, err := os.ReadFile(filepath.Join(rootDir, path))
+		if err != nil {
+			return nil, fmt.Errorf("license.header_template: %w", err)
+		}
+		h.Template = strings.TrimRight(string(data), "\n")
+	}
+	return h, nil
+}
+
+// SetLog sets the logger function
+func (h *LicenseHeader) SetLog(fn func(...any)) {
+	if fn != nil {
+		h.log = fn
+	}
+}
+
+// Header renders the header as Go line comments
+func (h *LicenseHeader) Header() string {
+	text := ExpandTemplate(h.Template, map[string]string{
+		"license": h.SPDX,
+		"holder":  h.Holder,
+		"year":    fmt.Sprint(time.Now().Year()),
+	})
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "//"):
+		case line == "":
+			lines[i] = "//"
+		default:
+			lines[i] = "// " + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Files returns the .go files covered by the header policy (relative to
+// rootDir). Generated files, vendor, testdata, hidden dirs and
+// license.exclude patterns are skipped.
+func (h *LicenseHeader) Files() ([]string, error) {
+	return goSourceFiles(h.rootDir, h.excluded)
+}
+
+// goSourceFiles walks root for .go files, skipping vendor, testdata, hidden
+// dirs and paths rejected by skip (relative, slash separated)
+func goSourceFiles(root string, skip func(rel string) bool) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(root, path)
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			name := d.Name()
+			if rel != "." && (strings.HasPrefix(name, ".") || name == "vendor" || name == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(rel, ".go") || (skip != nil && skip(rel)) {
+			return nil
+		}
+		files = append(files, rel)
+		return nil
+	})
+	return files, err
+}
+
+func (h *LicenseHeader) excluded(rel string) bool {
+	for _, pattern := range h.Exclude {
+		if ok, _ := filepath.Match(pattern, rel); ok {
+			return true
+		}
+		if strings.HasPrefix(rel, strings.TrimSuffix(pattern, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// Check verifies the header of every covered file
+func (h *LicenseHeader) Check() (LicenseHeaderReport, error) {
+	var report LicenseHeaderReport
+	files, err := h.Files()
+	if err != nil {
+		return report, err
+	}
+
+	for _, rel := range files {
+		data, err := os.ReadFile(filepath.Join(h.rootDir, rel))
+		if err != nil {
+			return report, err
+		}
+		if generatedFileRe.Match(data) {
+			continue
+		}
+		report.Checked++
+		switch id, _ := headerSPDX(string(data)); {
+		case id == "":
+			report.Missing = append(report.Missing, rel)
+		case id != h.SPDX:
+			report.Mismatched = append(report.Mismatched, rel)
+		}
+	}
+	return report, nil
+}
+
+// Insert adds the header to files without one and fixes mismatched SPDX
+// identifiers. Returns the number of files changed.
+func (h *LicenseHeader) Insert() (int, error) {
+	report, err := h.Check()
+	if err != nil {
+		return 0, err
+	}
+
+	header := h.Header()
+	for _, rel := range report.Missing {
+		path := filepath.Join(h.rootDir, rel)
+		info, err := os.Stat(path)
+		if err != nil {
+			return 0, err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return 0, err
+		}
+		if err := os.WriteFile(path, []byte(header+"\n\n"+string(data)), info.Mode().Perm()); err != nil {
+			return 0, err
+		}
+		h.log("Added license header:", rel)
+	}
+	for _, rel := range report.Mismatched {
+		path := filepath.Join(h.rootDir, rel)
+		info, err := os.Stat(path)
+		if err != nil {
+			return 0, err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return 0, err
+		}
+		id, line := headerSPDX(string(data))
+		lines := strings.Split(string(data), "\n")
+		lines[line] = strings.Replace(lines[line], spdxTag+" "+id, spdxTag+" "+h.SPDX, 1)
+		if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), info.Mode().Perm()); err != nil {
+			return 0, err
+		}
+		h.log("Fixed license header:", rel)
+	}
+	return len(report.Missing) + len(report.Mismatched), nil
+}
+
+// headerSPDX returns the SPDX identifier in the leading comment block of a
+// Go file and the line it is on
+func headerSPDX(src string) (string, int) {
+	scanner := bufio.NewScanner(strings.NewReader(src))
+	for i := 0; scanner.Scan(); i++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "//") {
+			break
+		}
+		if _, id, ok := strings.Cut(line, spdxTag); ok {
+			return strings.TrimSpace(id), i
+		}
+	}
+	return "", -1
+}
+
+// licenseMarkers maps LICENSE file phrases to SPDX identifiers, most
+// specific first
+var licenseMarkers = []struct{ phrase, spdx string }{
+	{"Apache License", "Apache-2.0"},
+	{"Mozilla Public License Version 2.0", "MPL-2.0"},
+	{"GNU GENERAL PUBLIC LICENSE\n                       Version 3", "GPL-3.0"},
+	{"GNU LESSER GENERAL PUBLIC LICENSE", "LGPL-3.0"},
+	{"Neither the name of", "BSD-3-Clause"},
+	{"Redistribution and use in source and binary forms", "BSD-2-Clause"},
+	{"This is free and unencumbered software released into the public domain", "Unlicense"},
+	{"MIT License", "MIT"},
+	{"Permission is hereby granted, free of charge", "MIT"},
+}
+
+// DetectLicense returns the SPDX identifier of the LICENSE file in dir, or
+// an empty string if missing or not recognized
+func DetectLicense(dir string) string {
+	for _, name := range []string{"LICENSE", "LICENSE.md", "LICENSE.txt"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		return detectLicenseText(string(data))
+	}
+	return ""
+}
+
+// detectLicenseText returns the SPDX identifier of a license text
+func detectLicenseText(text string) string {
+	for _, m := range licenseMarkers {
+		if strings.Contains(text, m.phrase) {
+			return m.spdx
+		}
+	}
+	return ""
+}
@@ -0,0 +1,99 @@
+package devflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDetectLicense(t *testing.T) {
+	dir := t.TempDir()
+	if got := DetectLicense(dir); got != "" {
+		t.Errorf("expected empty without LICENSE, got %q", got)
+	}
+	GenerateLicense("Owner", dir)
+	if got := DetectLicense(dir); got != "MIT" {
+		t.Errorf("expected MIT, got %q", got)
+	}
+	os.WriteFile(filepath.Join(dir, "LICENSE"), []byte("                                 Apache License\n                           Version 2.0, January 2004\n"), 0644)
+	if got := DetectLicense(dir); got != "Apache-2.0" {
+		t.Errorf("expected Apache-2.0, got %q", got)
+	}
+}
+
+func TestLicenseHeaderCheckAndInsert(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "gen"), 0755)
+	os.MkdirAll(filepath.Join(dir, "testdata"), 0755)
+	files := map[string]string{
+		"ok.go":          "// SPDX-License-Identifier: Apache-2.0\n// Copyright 2020 ACME\n\npackage lib\n",
+		"missing.go":     "//go:build linux\n\npackage lib\n",
+		"wrong.go":       "// SPDX-License-Identifier: MIT\n\npackage lib\n",
+		"generated.go":   "// Code generated by stringer. DO NOT EDIT.\n\npackage lib\n",
+		"gen/skip.go":    "package gen\n",
+		"testdata/x.go":  "package x\n",
+		"late_header.go": "package lib\n\n// SPDX-License-Identifier: Apache-2.0\n",
+	}
+	for name, content := range files {
+		os.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+	}
+
+	c, _ := ParseConfig("license:\n  spdx: Apache-2.0\n  holder: ACME\n  exclude: [gen]\n")
+	lh, err := NewLicenseHeader(dir, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := lh.Check()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Checked != 4 || strings.Join(report.Missing, ",") != "late_header.go,missing.go" || strings.Join(report.Mismatched, ",") != "wrong.go" {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if report.Summary() != "license headers: 2 missing, 1 mismatched" {
+		t.Errorf("unexpected summary: %q", report.Summary())
+	}
+
+	os.Chmod(filepath.Join(dir, "wrong.go"), 0600)
+	n, err := lh.Insert()
+	if err != nil || n != 3 {
+		t.Fatalf("Insert() = %d, %v", n, err)
+	}
+	got, _ := os.ReadFile(filepath.Join(dir, "missing.go"))
+	want := fmt.Sprintf("// SPDX-License-Identifier: Apache-2.0\n// Copyright %d ACME\n\n//go:build linux\n\npackage lib\n", time.Now().Year())
+	if string(got) != want {
+		t.Errorf("unexpected inserted header:\n%s", got)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dir, "wrong.go")); !strings.HasPrefix(string(got), "// SPDX-License-Identifier: Apache-2.0\n") {
+		t.Errorf("identifier not fixed:\n%s", got)
+	}
+	if info, _ := os.Stat(filepath.Join(dir, "wrong.go")); info.Mode().Perm() != 0600 {
+		t.Errorf("file mode not kept: %v", info.Mode())
+	}
+
+	if report, _ := lh.Check(); !report.OK() {
+		t.Errorf("expected clean report after insert, got %+v", report)
+	}
+}
+
+func TestLicenseHeaderTemplate(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "header.txt"), []byte("SPDX-License-Identifier: {{license}}\n\nCopyright {{holder}}\n"), 0644)
+	c, _ := ParseConfig("license:\n  spdx: MIT\n  holder: Me\n  header_template: header.txt\n")
+
+	lh, err := NewLicenseHeader(dir, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := lh.Header(); got != "// SPDX-License-Identifier: MIT\n//\n// Copyright Me" {
+		t.Errorf("unexpected header: %q", got)
+	}
+
+	if _, err := NewLicenseHeader(t.TempDir(), NewConfig()); err == nil {
+		t.Error("expected error without license.spdx or LICENSE")
+	}
+}
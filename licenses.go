@@ -0,0 +1,69 @@
+package devflow
+
+import (
+	"embed"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// licenseTexts holds the LICENSE templates, one <SPDX>.txt per license.
+// {{year}} and {{holder}} are replaced when the file is generated.
+//
+//go:embed licenses/*.txt
+var licenseTexts embed.FS
+
+// Licenses lists the SPDX identifiers GenerateLicenseFile supports
+var Licenses = []string{"MIT", "Apache-2.0", "BSD-3-Clause", "GPL-3.0", "MPL-2.0", "Unlicense"}
+
+// licenseAliases maps common spellings (lowercase) to SPDX identifiers
+var licenseAliases = map[string]string{
+	"apache":           "Apache-2.0",
+	"apache2":          "Apache-2.0",
+	"bsd":              "BSD-3-Clause",
+	"bsd-3":            "BSD-3-Clause",
+	"gpl":              "GPL-3.0",
+	"gpl3":             "GPL-3.0",
+	"gpl-3.0-only":     "GPL-3.0",
+	"gpl-3.0-or-later": "GPL-3.0",
+	"mpl":              "MPL-2.0",
+	"mpl2":             "MPL-2.0",
+}
+
+// NormalizeLicense returns the SPDX identifier for name (case-insensitive,
+// empty meaning MIT), or an error for unsupported licenses
+func NormalizeLicense(name string) (string, error) {
+	if name == "" {
+		return "MIT", nil
+	}
+	lower := strings.ToLower(strings.TrimSpace(name))
+	for _, id := range Licenses {
+		if strings.ToLower(id) == lower {
+			return id, nil
+		}
+	}
+	if id, ok := licenseAliases[lower]; ok {
+		return id, nil
+	}
+	return "", fmt.Errorf("unsupported license %q (want %s)", name, strings.Join(Licenses, ", "))
+}
+
+// GenerateLicenseFile writes LICENSE for the given license (see
+// NormalizeLicense) and returns its SPDX identifier
+func GenerateLicenseFile(license, holder, targetDir string) (string, error) {
+	id, err := NormalizeLicense(license)
+	if err != nil {
+		return "", err
+	}
+	text, err := licenseTexts.ReadFile("licenses/" + id + ".txt")
+	if err != nil {
+		return "", err
+	}
+	content := strings.NewReplacer(
+		"{{year}}", strconv.Itoa(time.Now().Year()),
+		"{{holder}}", holder,
+	).Replace(string(text))
+	return id, writeGenerated(filepath.Join(targetDir, "LICENSE"), []byte(content))
+}
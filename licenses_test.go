@@ -0,0 +1,48 @@
+package devflow
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerateLicenseFile(t *testing.T) {
+	for _, id := range Licenses {
+		dir := t.TempDir()
+		got, err := GenerateLicenseFile(strings.ToLower(id), "Jane Doe", dir)
+		if err != nil {
+			t.Fatalf("%s: %v", id, err)
+		}
+		if got != id {
+			t.Errorf("GenerateLicenseFile(%s) = %s", id, got)
+		}
+		// Badges and license headers read the same identifier back
+		if detected := DetectLicense(dir); detected != id {
+			t.Errorf("%s: DetectLicense = %q", id, detected)
+		}
+		data, _ := os.ReadFile(filepath.Join(dir, "LICENSE"))
+		if strings.Contains(string(data), "{{") {
+			t.Errorf("%s: unexpanded placeholder in LICENSE", id)
+		}
+		if id == "MIT" || id == "BSD-3-Clause" {
+			if !strings.Contains(string(data), strconv.Itoa(time.Now().Year())) || !strings.Contains(string(data), "Jane Doe") {
+				t.Errorf("%s: missing year or holder:\n%s", id, data)
+			}
+		}
+	}
+}
+
+func TestNormalizeLicense(t *testing.T) {
+	cases := map[string]string{"": "MIT", "apache": "Apache-2.0", "GPL-3.0-or-later": "GPL-3.0", "unlicense": "Unlicense"}
+	for in, want := range cases {
+		if got, err := NormalizeLicense(in); err != nil || got != want {
+			t.Errorf("NormalizeLicense(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := NormalizeLicense("WTFPL"); err == nil {
+		t.Error("expected error for unsupported license")
+	}
+}
@@ -0,0 +1,210 @@
+package devflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Linters of lint.tool
+const (
+	LintAuto        = "auto" // golangci-lint when installed, else staticcheck
+	LintStaticcheck = "staticcheck"
+	LintGolangci    = "golangci-lint"
+)
+
+// lintPackages are the go install targets of the linters
+var lintPackages = map[string]string{
+	LintStaticcheck: "honnef.co/go/tools/cmd/staticcheck@latest",
+	LintGolangci:    "github.com/golangci/golangci-lint/v2/cmd/golangci-lint@latest",
+}
+
+// LintSettings are the lint.* settings of .devflow.yaml
+type LintSettings struct {
+	Enabled bool   // Run the linter as a gotest phase
+	Tool    string // LintAuto, LintStaticcheck or LintGolangci
+	Install bool   // go install the linter when it is missing
+}
+
+// LoadLintSettings reads lint.enabled, lint.tool and lint.install
+func LoadLintSettings(c *Config) (LintSettings, error) {
+	s := LintSettings{Tool: LintAuto, Install: true}
+	if c == nil {
+		return s, nil
+	}
+	s.Enabled = c.Bool("lint.enabled", false)
+	s.Install = c.Bool("lint.install", true)
+	switch tool := c.String("lint.tool", LintAuto); tool {
+	case LintAuto, LintStaticcheck, LintGolangci:
+		s.Tool = tool
+	default:
+		return s, fmt.Errorf("lint.tool: invalid value %q (want auto, staticcheck or golangci-lint)", tool)
+	}
+	return s, nil
+}
+
+// resolveLinter returns the linter to run, installing it if allowed.
+// auto prefers an installed golangci-lint, which reads the project's
+// .golangci.yml, and otherwise uses staticcheck.
+func (g *Go) resolveLinter(s LintSettings) (string, error) {
+	tool := s.Tool
+	if tool == LintAuto {
+		if _, err := RunCommandSilent("which", LintGolangci); err == nil {
+			return LintGolangci, nil
+		}
+		tool = LintStaticcheck
+	}
+	if _, err := RunCommandSilent("which", tool); err == nil {
+		return tool, nil
+	}
+	if !s.Install {
+		return "", fmt.Errorf("%s not installed (lint.install: false)", tool)
+	}
+	if err := AirGapCheck(NetToolInstall); err != nil {
+		return "", fmt.Errorf("%s not installed: %w", tool, err)
+	}
+	if _, err := RunCommand("go", "install", lintPackages[tool]); err != nil {
+		return "", fmt.Errorf("go install failed: %w", err)
+	}
+	return tool, nil
+}
+
+// Lint runs staticcheck or golangci-lint (lint.tool) on the module in the
+// current directory and returns the linter used and its findings, with
+// paths relative to the module
+func (g *Go) Lint() (tool string, findings []Finding, err error) {
+	settings, err := LoadLintSettings(g.Config())
+	if err != nil {
+		return "", nil, err
+	}
+	if tool, err = g.resolveLinter(settings); err != nil {
+		return "", nil, err
+	}
+
+	args := []string{"-f", "json", "./..."}
+	if tool == LintGolangci {
+		// The JSON output flag changed in golangci-lint v2
+		args = []string{"run", "--issues-exit-code=0", "--output.json.path=stdout", "./..."}
+		if version, _ := RunCommandSilent(tool, "--version"); strings.Contains(version, "version 1.") {
+			args = []string{"run", "--issues-exit-code=0", "--out-format=json", "./..."}
+		}
+	}
+	var stdout, stderr bytes.Buffer
+	_, runErr := runCommand(&Command{Name: tool, Args: args, Stdout: &stdout, Stderr: &stderr})
+
+	cwd, _ := os.Getwd()
+	if tool == LintGolangci {
+		if runErr != nil {
+			return tool, nil, fmt.Errorf("golangci-lint failed: %w: %s", runErr, strings.TrimSpace(stderr.String()))
+		}
+		findings, err = ParseGolangciJSON(stdout.String(), cwd)
+		return tool, findings, err
+	}
+	// staticcheck exits 1 when it reports findings
+	findings = ParseStaticcheckJSON(stdout.String(), cwd)
+	if runErr != nil && len(findings) == 0 {
+		return tool, nil, fmt.Errorf("staticcheck failed: %w: %s", runErr, strings.TrimSpace(stderr.String()))
+	}
+	return tool, findings, nil
+}
+
+// ParseGolangciJSON parses the JSON report of golangci-lint run
+func ParseGolangciJSON(output, root string) ([]Finding, error) {
+	start := strings.Index(output, "{")
+	if start < 0 {
+		return nil, fmt.Errorf("golangci-lint output: no JSON report")
+	}
+	var report struct {
+		Issues []struct {
+			FromLinter string
+			Text       string
+			Severity   string
+			Pos        struct {
+				Filename string
+				Line     int
+				Column   int
+			}
+		}
+	}
+	if err := json.NewDecoder(strings.NewReader(output[start:])).Decode(&report); err != nil {
+		return nil, fmt.Errorf("golangci-lint output: %w", err)
+	}
+	var findings []Finding
+	for _, issue := range report.Issues {
+		level := "warning"
+		if issue.Severity == "error" {
+			level = "error"
+		}
+		findings = append(findings, Finding{
+			Tool: LintGolangci, Rule: issue.FromLinter, File: relativeTo(root, issue.Pos.Filename),
+			Line: issue.Pos.Line, Column: issue.Pos.Column, Message: issue.Text, Level: level,
+		})
+	}
+	sortFindings(findings)
+	return findings, nil
+}
+
+// mergeLintFindings drops the linter findings that repeat a vet issue
+// (golangci-lint runs govet too, prefixing the analyzer name)
+func mergeLintFindings(vet, lint []Finding) []Finding {
+	var merged []Finding
+	for _, l := range lint {
+		duplicate := false
+		for _, v := range vet {
+			if v.File == l.File && v.Line == l.Line && (strings.HasSuffix(l.Message, v.Message) || strings.HasSuffix(v.Message, l.Message)) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			merged = append(merged, l)
+		}
+	}
+	return merged
+}
+
+// lintResult merges the linter findings with the new vet issues and
+// leaves out those recorded in the baseline
+func (g *Go) lintResult(tool string, findings []Finding, vetIssues []string) LintResult {
+	vet := ParseVetText(vetIssues)
+	fresh, known := findings, []Finding(nil)
+	if baseline, err := LoadBaseline("."); err != nil {
+		g.logger.Warn(err)
+	} else {
+		fresh, known = baseline.Filter(findings)
+	}
+	fresh = mergeLintFindings(vet, fresh)
+	for _, f := range fresh {
+		g.logger.Info("Lint issue:", f)
+	}
+	return LintResult{Tool: tool, Vet: len(vet), Lint: len(fresh), Known: len(known)}
+}
+
+// LintResult is the combined outcome of go vet and the linter
+type LintResult struct {
+	Tool  string
+	Vet   int // New vet issues
+	Lint  int // New linter findings not reported by vet
+	Known int // Linter findings recorded in the baseline
+}
+
+// Status returns the badge value: "OK" or "N issues"
+func (r LintResult) Status() string {
+	if n := r.Vet + r.Lint; n > 0 {
+		return fmt.Sprintf("%d issues", n)
+	}
+	return "OK"
+}
+
+// Summary returns the gotest message for the result
+func (r LintResult) Summary() string {
+	if r.Vet+r.Lint == 0 {
+		if r.Known > 0 {
+			return fmt.Sprintf("✅ lint ok (%s, %d baselined)", r.Tool, r.Known)
+		}
+		return fmt.Sprintf("✅ lint ok (%s)", r.Tool)
+	}
+	return fmt.Sprintf("❌ lint: %d issues (vet %d, %s %d)", r.Vet+r.Lint, r.Vet, r.Tool, r.Lint)
+}
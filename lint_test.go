@@ -0,0 +1,96 @@
+package devflow
+
+import (
+	"strings"
+	"testing"
+)
+
+const testGolangciJSON = `{"Issues":[` +
+	`{"FromLinter":"errcheck","Text":"Error return value of ` + "`f.Close`" + ` is not checked","Severity":"","Pos":{"Filename":"store/file.go","Line":42,"Column":10}},` +
+	`{"FromLinter":"govet","Text":"printf: fmt.Sprintf format %d has arg s of wrong type string","Severity":"error","Pos":{"Filename":"main.go","Line":7,"Column":2}}` +
+	`],"Report":{"Linters":[{"Name":"errcheck","Enabled":true}]}}
+0 issues.`
+
+func TestParseGolangciJSON(t *testing.T) {
+	findings, err := ParseGolangciJSON(testGolangciJSON, "/src/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got %+v", findings)
+	}
+	if f := findings[0]; f.Tool != LintGolangci || f.Rule != "govet" || f.File != "main.go" || f.Line != 7 || f.Level != "error" {
+		t.Errorf("unexpected first finding: %+v", f)
+	}
+	if f := findings[1]; f.Rule != "errcheck" || f.File != "store/file.go" || f.Column != 10 || f.Level != "warning" {
+		t.Errorf("unexpected second finding: %+v", f)
+	}
+
+	if _, err := ParseGolangciJSON("level=error msg=\"context loading failed\"", ""); err == nil {
+		t.Error("expected error for output without a report")
+	}
+}
+
+func TestMergeLintFindings(t *testing.T) {
+	lint, _ := ParseGolangciJSON(testGolangciJSON, "")
+	vet := ParseVetText([]string{"./main.go:7:2: fmt.Sprintf format %d has arg s of wrong type string"})
+
+	merged := mergeLintFindings(vet, lint)
+	if len(merged) != 1 || merged[0].Rule != "errcheck" {
+		t.Errorf("expected the govet duplicate dropped, got %+v", merged)
+	}
+}
+
+func TestLintResult(t *testing.T) {
+	dir, cleanup := testCreateGoModule("lintresult")
+	defer cleanup()
+	defer testChdir(t, dir)()
+
+	g, _ := NewGo(nil)
+	lint, _ := ParseGolangciJSON(testGolangciJSON, "")
+	vetIssues := []string{"./main.go:7:2: fmt.Sprintf format %d has arg s of wrong type string"}
+
+	result := g.lintResult(LintGolangci, lint, vetIssues)
+	if result.Vet != 1 || result.Lint != 1 || result.Status() != "2 issues" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if got := result.Summary(); got != "❌ lint: 2 issues (vet 1, golangci-lint 1)" {
+		t.Errorf("Summary() = %q", got)
+	}
+
+	// Baselined findings do not count
+	if err := NewBaseline(lint).Save("."); err != nil {
+		t.Fatal(err)
+	}
+	result = g.lintResult(LintGolangci, lint, nil)
+	if result.Status() != "OK" || result.Summary() != "✅ lint ok (golangci-lint, 2 baselined)" {
+		t.Errorf("unexpected baselined result: %+v %q", result, result.Summary())
+	}
+}
+
+func TestLoadLintSettings(t *testing.T) {
+	s, err := LoadLintSettings(nil)
+	if err != nil || s.Enabled || s.Tool != LintAuto || !s.Install {
+		t.Errorf("unexpected defaults: %+v, %v", s, err)
+	}
+
+	cfg, _ := ParseConfig("lint:\n  enabled: true\n  tool: golangci-lint\n  install: false\n")
+	s, err = LoadLintSettings(cfg)
+	if err != nil || !s.Enabled || s.Tool != LintGolangci || s.Install {
+		t.Errorf("unexpected settings: %+v, %v", s, err)
+	}
+
+	cfg, _ = ParseConfig("lint:\n  tool: revive\n")
+	if _, err := LoadLintSettings(cfg); err == nil || !strings.Contains(err.Error(), "lint.tool") {
+		t.Errorf("expected invalid lint.tool error, got %v", err)
+	}
+}
+
+func TestResolveLinterWithoutInstall(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	g, _ := NewGo(nil)
+	_, err := g.resolveLinter(LintSettings{Tool: LintStaticcheck})
+	if err == nil || !strings.Contains(err.Error(), "lint.install: false") {
+		t.Errorf("expected not installed error, got %v", err)
+	}
+}
@@ -0,0 +1,177 @@
+package devflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Logger receives the progress and diagnostic messages of the handlers.
+// Arguments are joined like fmt.Println.
+type Logger interface {
+	Debug(args ...any)
+	Info(args ...any)
+	Warn(args ...any)
+	Error(args ...any)
+}
+
+// LogLevel is the minimum level a Logger writes
+type LogLevel int
+
+const (
+	LevelDebug LogLevel = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// String returns "debug", "info", "warn" or "error"
+func (l LogLevel) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	}
+	return "info"
+}
+
+// ParseLogLevel parses "debug", "info", "warn" (or "warning") and "error"
+func ParseLogLevel(s string) (LogLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return LevelDebug, nil
+	case "", "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	}
+	return LevelInfo, fmt.Errorf("invalid log level %q (want debug, info, warn or error)", s)
+}
+
+// Log formats of NewLogger
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+// streamLogger writes the messages of level and above to w, as text
+// lines or as JSON objects {"time", "level", "msg"}
+type streamLogger struct {
+	mu    sync.Mutex
+	w     io.Writer
+	level LogLevel
+	json  bool
+}
+
+// NewLogger returns a Logger that writes the messages of level and above
+// to w. Text lines are those of the old SetLog functions: warnings and
+// errors start with "Warning:" and "Error:". LogFormatJSON writes one
+// JSON object per message instead.
+func NewLogger(w io.Writer, level LogLevel, format string) Logger {
+	return &streamLogger{w: w, level: level, json: format == LogFormatJSON}
+}
+
+// NewLoggerFromConfig returns NewLogger(w, ...) with log.level and
+// log.format of the global config, overridden by DEVFLOW_LOG_LEVEL and
+// DEVFLOW_LOG_FORMAT. Text lines follow the accessible output mode.
+func NewLoggerFromConfig(w io.Writer) Logger {
+	levelName, format := "", ""
+	if global, err := LoadGlobalConfig(); err == nil {
+		levelName, format = global.String("log.level", ""), global.String("log.format", "")
+	}
+	if v := os.Getenv("DEVFLOW_LOG_LEVEL"); v != "" {
+		levelName = v
+	}
+	if v := os.Getenv("DEVFLOW_LOG_FORMAT"); v != "" {
+		format = v
+	}
+	level, _ := ParseLogLevel(levelName)
+	if format != LogFormatJSON {
+		w = OutputWriter(w)
+	}
+	return NewLogger(w, level, format)
+}
+
+func (l *streamLogger) Debug(args ...any) { l.write(LevelDebug, args) }
+func (l *streamLogger) Info(args ...any)  { l.write(LevelInfo, args) }
+func (l *streamLogger) Warn(args ...any)  { l.write(LevelWarn, args) }
+func (l *streamLogger) Error(args ...any) { l.write(LevelError, args) }
+
+func (l *streamLogger) write(level LogLevel, args []any) {
+	if level < l.level {
+		return
+	}
+	msg := strings.TrimSuffix(fmt.Sprintln(args...), "\n")
+	var line []byte
+	if l.json {
+		line, _ = json.Marshal(struct {
+			Time  string `json:"time"`
+			Level string `json:"level"`
+			Msg   string `json:"msg"`
+		}{time.Now().UTC().Format(time.RFC3339), level.String(), msg})
+	} else {
+		line = []byte(levelPrefix(level) + msg)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.w.Write(append(line, '\n'))
+}
+
+// levelPrefix is the text prefix of warnings and errors
+func levelPrefix(level LogLevel) string {
+	switch level {
+	case LevelWarn:
+		return "Warning: "
+	case LevelError:
+		return "Error: "
+	}
+	return ""
+}
+
+// funcLogger adapts an old SetLog function
+type funcLogger func(...any)
+
+// FuncLogger adapts fn to a Logger, for the SetLog setters: warnings and
+// errors are passed to fn with their "Warning:"/"Error:" prefix and debug
+// messages are dropped, so the output stays that of the unleveled logger
+func FuncLogger(fn func(...any)) Logger {
+	if fn == nil {
+		return NopLogger()
+	}
+	return funcLogger(fn)
+}
+
+func (f funcLogger) Debug(args ...any) {}
+func (f funcLogger) Info(args ...any)  { f(args...) }
+func (f funcLogger) Warn(args ...any)  { f(append([]any{"Warning:"}, args...)...) }
+func (f funcLogger) Error(args ...any) { f(append([]any{"Error:"}, args...)...) }
+
+// setLogger passes l to c, leveled when c has SetLogger and as the old
+// unleveled function otherwise (e.g. mocks of GitClient)
+func setLogger(c interface{ SetLog(func(...any)) }, l Logger) {
+	if s, ok := c.(LoggerSetter); ok {
+		s.SetLogger(l)
+		return
+	}
+	c.SetLog(l.Info)
+}
+
+type nopLogger struct{}
+
+// NopLogger returns a Logger that discards every message, the default of
+// the handlers
+func NopLogger() Logger { return nopLogger{} }
+
+func (nopLogger) Debug(...any) {}
+func (nopLogger) Info(...any)  {}
+func (nopLogger) Warn(...any)  {}
+func (nopLogger) Error(...any) {}
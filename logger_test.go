@@ -0,0 +1,88 @@
+package devflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParseLogLevel(t *testing.T) {
+	for in, want := range map[string]LogLevel{"": LevelInfo, "debug": LevelDebug, "WARNING": LevelWarn, "error": LevelError} {
+		if got, err := ParseLogLevel(in); err != nil || got != want {
+			t.Errorf("ParseLogLevel(%q) = %v, %v", in, got, err)
+		}
+	}
+	if _, err := ParseLogLevel("loud"); err == nil {
+		t.Error("expected error for an unknown level")
+	}
+}
+
+func TestLoggerText(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(&buf, LevelInfo, LogFormatText)
+	l.Debug("hidden")
+	l.Info("Building", "linux/amd64")
+	l.Warn("could not add", "dist")
+	l.Error("boom")
+	want := "Building linux/amd64\nWarning: could not add dist\nError: boom\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestLoggerJSON(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(&buf, LevelDebug, LogFormatJSON)
+	l.Debug("probe", 2)
+	l.Warn("offline")
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", buf.String())
+	}
+	var entry struct{ Time, Level, Msg string }
+	if err := json.Unmarshal([]byte(lines[1]), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.Level != "warn" || entry.Msg != "offline" || entry.Time == "" {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+}
+
+func TestLoggerFromConfigEnv(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("DEVFLOW_LOG_LEVEL", "error")
+	t.Setenv("DEVFLOW_LOG_FORMAT", "json")
+	var buf bytes.Buffer
+	l := NewLoggerFromConfig(&buf)
+	l.Warn("hidden")
+	l.Error("shown")
+	if !strings.HasPrefix(buf.String(), "{") || !strings.Contains(buf.String(), `"msg":"shown"`) || strings.Contains(buf.String(), "hidden") {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+}
+
+func TestSetLogCompat(t *testing.T) {
+	git, err := NewGit()
+	if err != nil {
+		t.Skip("git not installed")
+	}
+	g, _ := NewGo(git)
+	var got []string
+	g.SetLog(func(args ...any) { got = append(got, strings.TrimSpace(fmt.Sprintln(args...))) })
+	g.logger.Debug("dropped")
+	g.logger.Warn("invalid config:", "x")
+	git.logger.Info("Tag v0.0.2 already exists")
+	if strings.Join(got, "|") != "Warning: invalid config: x|Tag v0.0.2 already exists" {
+		t.Errorf("unexpected messages %q", got)
+	}
+
+	// SetLogger reaches the git client of the handler
+	var buf bytes.Buffer
+	g.SetLogger(NewLogger(&buf, LevelDebug, LogFormatText))
+	git.logger.Debug("from git")
+	if buf.String() != "from git\n" {
+		t.Errorf("git handler not given the logger: %q", buf.String())
+	}
+}
@@ -0,0 +1,276 @@
+package devflow
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// MetricsFile is the local metrics log, relative to os.UserCacheDir
+const MetricsFile = "devflow/metrics.jsonl"
+
+// MetricEvent is one recorded command run. Only the command, project dir
+// name, duration and a failure category are kept; error messages,
+// arguments and paths are not.
+type MetricEvent struct {
+	Time     time.Time     `json:"time"`
+	Command  string        `json:"command"`
+	Project  string        `json:"project,omitempty"`
+	Duration time.Duration `json:"duration"`
+	Failure  string        `json:"failure,omitempty"` // Failure category, empty on success
+}
+
+// metrics tracks nested tracked calls (gopush -> push -> test) so only
+// the outermost one is recorded. The depth is kept per goroutine: calls
+// running side by side (e.g. from the dashboard) are recorded each.
+var metrics struct {
+	sync.Mutex
+	depth map[uint64]int
+}
+
+// goroutineID returns the id of the calling goroutine, from the
+// "goroutine 18 [running]:" header of its stack
+func goroutineID() uint64 {
+	var buf [64]byte
+	fields := strings.Fields(string(buf[:runtime.Stack(buf[:], false)]))
+	if len(fields) < 2 {
+		return 0
+	}
+	id, _ := strconv.ParseUint(fields[1], 10, 64)
+	return id
+}
+
+// MetricsEnabled reports whether local metrics are on: metrics.enabled in
+// the global config or DEVFLOW_METRICS=1. Metrics are never uploaded.
+func MetricsEnabled() bool {
+	if v := os.Getenv("DEVFLOW_METRICS"); v != "" {
+		return v == "1" || v == "true"
+	}
+	global, err := LoadGlobalConfig()
+	return err == nil && global.Bool("metrics.enabled", false)
+}
+
+// trackMetric records the run of command when the returned function is
+// called with its error: defer trackMetric("push")(&err)
+func trackMetric(command string) func(*error) {
+	id := goroutineID()
+	metrics.Lock()
+	if metrics.depth == nil {
+		metrics.depth = map[uint64]int{}
+	}
+	metrics.depth[id]++
+	outer := metrics.depth[id] == 1
+	metrics.Unlock()
+	start := time.Now()
+	return func(err *error) {
+		metrics.Lock()
+		if metrics.depth[id]--; metrics.depth[id] == 0 {
+			delete(metrics.depth, id)
+		}
+		metrics.Unlock()
+		if !outer || DryRunActive() || !MetricsEnabled() {
+			return
+		}
+		event := MetricEvent{Time: start.UTC(), Command: command, Duration: time.Since(start)}
+		if wd, e := os.Getwd(); e == nil {
+			event.Project = filepath.Base(wd)
+		}
+		if err != nil && *err != nil {
+			event.Failure = FailureCategory(*err)
+		}
+		RecordMetric(event)
+	}
+}
+
+// FailureCategory classifies an error for the metrics: test, vet, race,
+// coverage, network, auth, git, usage or other. Words match whole, with
+// their inflections ("tests", "committed"), so "latest tag" is not a test.
+func FailureCategory(err error) string {
+	msg := strings.ToLower(err.Error())
+	categories := []struct {
+		name  string
+		words []string
+	}{
+		{"network", []string{"dial tcp", "timeout", "timed out", "no such host", "connection refused", "connection reset", "network"}},
+		{"auth", []string{"authentication", "permission denied", "unauthorized", "forbidden", "401", "403", "token"}},
+		{"race", []string{"race"}},
+		{"vet", []string{"vet", "staticcheck"}},
+		{"coverage", []string{"coverage"}},
+		{"test", []string{"test", "fuzz", "bench"}},
+		{"git", []string{"git", "merge", "conflict", "rejected", "commit", "tag"}},
+		{"usage", []string{"invalid", "unknown", "required", "must", "empty"}},
+	}
+	for _, c := range categories {
+		for _, w := range c.words {
+			if failureWordRe(w).MatchString(msg) {
+				return c.name
+			}
+		}
+	}
+	return "other"
+}
+
+// failureWordRe matches word as a whole word of a lowercase message
+func failureWordRe(word string) *regexp.Regexp {
+	return regexp.MustCompile(`(^|[^a-z0-9])` + regexp.QuoteMeta(word) + `(s|es|ed|ing|ted)?([^a-z0-9]|$)`)
+}
+
+// metricsPath returns the location of the metrics log
+func metricsPath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, MetricsFile), nil
+}
+
+// RecordMetric appends an event to the metrics log
+func RecordMetric(e MetricEvent) error {
+	path, err := metricsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// LoadMetrics returns the recorded events since the given time (zero for
+// all). Lines that do not parse are skipped.
+func LoadMetrics(since time.Time) ([]MetricEvent, error) {
+	path, err := metricsPath()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var events []MetricEvent
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e MetricEvent
+		if json.Unmarshal(scanner.Bytes(), &e) != nil || e.Time.Before(since) {
+			continue
+		}
+		events = append(events, e)
+	}
+	return events, scanner.Err()
+}
+
+// ClearMetrics deletes the metrics log
+func ClearMetrics() error {
+	path, err := metricsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// FormatMetrics renders the runs, failures and time of each command, the
+// failure categories and the projects that took the most time
+func FormatMetrics(events []MetricEvent) string {
+	if len(events) == 0 {
+		return "No metrics recorded"
+	}
+	type stats struct {
+		runs, failed int
+		total        time.Duration
+	}
+	byCommand := make(map[string]*stats)
+	byProject := make(map[string]time.Duration)
+	failures := make(map[string]int)
+	var total time.Duration
+	for _, e := range events {
+		s := byCommand[e.Command]
+		if s == nil {
+			s = &stats{}
+			byCommand[e.Command] = s
+		}
+		s.runs++
+		s.total += e.Duration
+		if e.Failure != "" {
+			s.failed++
+			failures[e.Failure]++
+		}
+		if e.Project != "" {
+			byProject[e.Project] += e.Duration
+		}
+		total += e.Duration
+	}
+
+	commands := make([]string, 0, len(byCommand))
+	for c := range byCommand {
+		commands = append(commands, c)
+	}
+	sort.Slice(commands, func(i, j int) bool { return byCommand[commands[i]].total > byCommand[commands[j]].total })
+
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "COMMAND\tRUNS\tFAILED\tTOTAL\tAVG")
+	for _, c := range commands {
+		s := byCommand[c]
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\n", c, s.runs, s.failed, s.total.Round(time.Second), (s.total / time.Duration(s.runs)).Round(100*time.Millisecond))
+	}
+	w.Flush()
+
+	if len(failures) > 0 {
+		fmt.Fprintf(&b, "\nFailures: %s\n", rankCounts(failures, func(n int) string { return fmt.Sprint(n) }, 0))
+	}
+	if len(byProject) > 0 {
+		fmt.Fprintf(&b, "\nTop projects: %s\n", rankCounts(byProject, func(d time.Duration) string { return d.Round(time.Second).String() }, 5))
+	}
+	fmt.Fprintf(&b, "\n%d runs, %s total since %s", len(events), total.Round(time.Second), events[0].Time.Local().Format("2006-01-02"))
+	return b.String()
+}
+
+// rankCounts returns "a 3, b 1" ordered by count, at most limit entries (0: all)
+func rankCounts[N int | time.Duration](counts map[string]N, format func(N) string, limit int) string {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	if limit > 0 && len(names) > limit {
+		names = names[:limit]
+	}
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = name + " " + format(counts[name])
+	}
+	return strings.Join(parts, ", ")
+}
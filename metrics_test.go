@@ -0,0 +1,95 @@
+package devflow
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTrackMetric(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("DEVFLOW_METRICS", "1")
+
+	run := func(command string, fail error, nested func()) (err error) {
+		defer trackMetric(command)(&err)
+		if nested != nil {
+			nested()
+		}
+		return fail
+	}
+	run("gopush", errors.New("tests failed: ❌ tests"), func() { run("push", nil, nil) })
+	run("push", nil, nil)
+
+	events, err := LoadMetrics(time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || events[0].Command != "gopush" || events[0].Failure != "test" || events[1].Command != "push" || events[1].Failure != "" {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+	if recent, _ := LoadMetrics(time.Now().Add(time.Hour)); len(recent) != 0 {
+		t.Errorf("expected no events after now, got %d", len(recent))
+	}
+
+	// A call running in another goroutine meanwhile is not nested in this one
+	run("gopush", nil, func() {
+		done := make(chan struct{})
+		go func() { run("test", nil, nil); close(done) }()
+		<-done
+	})
+	if events, _ := LoadMetrics(time.Time{}); len(events) != 4 || events[2].Command != "test" || events[3].Command != "gopush" {
+		t.Errorf("expected the side by side runs recorded each, got %+v", events)
+	}
+
+	t.Setenv("DEVFLOW_METRICS", "0")
+	run("push", nil, nil)
+	if events, _ := LoadMetrics(time.Time{}); len(events) != 4 {
+		t.Errorf("disabled metrics should not be recorded, got %d events", len(events))
+	}
+
+	if err := ClearMetrics(); err != nil {
+		t.Fatal(err)
+	}
+	if events, _ := LoadMetrics(time.Time{}); len(events) != 0 {
+		t.Errorf("expected no events after ClearMetrics, got %d", len(events))
+	}
+}
+
+func TestFailureCategory(t *testing.T) {
+	for msg, want := range map[string]string{
+		"dial tcp 140.82.112.3:443: i/o timeout":    "network",
+		"git push: Permission denied (publickey)":   "auth",
+		"Tests failed: ❌ race detected":             "race",
+		"coverage below threshold: app 40% < 70%":   "coverage",
+		"fuzzing failed":                            "test",
+		"merge conflict in go.mod":                  "git",
+		"invalid commit type \"wip\"":               "git",
+		"something else":                            "other",
+		"description must not be longer than 350":   "usage",
+		"could not get latest tag: exit status 128": "git",
+		"trace upload failed":                       "other",
+	} {
+		if got := FailureCategory(errors.New(msg)); got != want {
+			t.Errorf("FailureCategory(%q) = %s, want %s", msg, got, want)
+		}
+	}
+}
+
+func TestFormatMetrics(t *testing.T) {
+	start := time.Date(2026, 10, 7, 12, 0, 0, 0, time.UTC)
+	events := []MetricEvent{
+		{Time: start, Command: "test", Project: "api", Duration: 10 * time.Second, Failure: "test"},
+		{Time: start, Command: "gopush", Project: "api", Duration: time.Minute},
+		{Time: start, Command: "test", Project: "web", Duration: 20 * time.Second},
+	}
+	out := FormatMetrics(events)
+	for _, want := range []string{"COMMAND  RUNS  FAILED  TOTAL  AVG", "gopush   1     0       1m0s   1m0s", "test     2     1       30s    15s", "Failures: test 1", "Top projects: api 1m10s, web 20s", "3 runs, 1m30s total"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+	if FormatMetrics(nil) != "No metrics recorded" {
+		t.Error("unexpected output without events")
+	}
+}
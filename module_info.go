@@ -0,0 +1,105 @@
+package devflow
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ModuleInfo is the Go module of a run, read from its go.mod once and
+// passed to the steps that need it
+type ModuleInfo struct {
+	Path      string // Module path, e.g. github.com/owner/repo/v2
+	Name      string // Last path element without the major version, e.g. repo
+	GoVersion string // go directive, e.g. 1.22 (empty when missing)
+	Dir       string // Absolute directory of go.mod
+}
+
+// ReadModule reads the go.mod in dir
+func ReadModule(dir string) (*ModuleInfo, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(filepath.Join(abs, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	m := &ModuleInfo{Dir: abs}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "//")
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		switch fields[0] {
+		case "module":
+			m.Path = fields[1]
+			if unquoted, err := strconv.Unquote(m.Path); err == nil {
+				m.Path = unquoted
+			}
+		case "go":
+			m.GoVersion = fields[1]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if m.Path == "" {
+		return nil, fmt.Errorf("module directive not found in %s", filepath.Join(abs, "go.mod"))
+	}
+	m.Name = path.Base(m.Path)
+	if parent := path.Dir(m.Path); parent != "." && majorSuffixRe.MatchString(m.Name) {
+		m.Name = path.Base(parent)
+	}
+	return m, nil
+}
+
+// FindModule returns the module dir belongs to: that of the nearest go.mod
+// in dir or one of its parents, so a run from a subdirectory finds it too
+func FindModule(dir string) (*ModuleInfo, error) {
+	root, err := FindRoot(dir)
+	if err != nil {
+		return nil, err
+	}
+	return ReadModule(root)
+}
+
+// FindRoot returns the root of the project dir belongs to: the nearest of
+// dir and its parents that holds a go.mod, without leaving the git
+// repository; else the root of the repository
+func FindRoot(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for d := abs; ; d = filepath.Dir(d) {
+		if _, err := os.Stat(filepath.Join(d, "go.mod")); err == nil {
+			return d, nil
+		}
+		if _, err := os.Stat(filepath.Join(d, ".git")); err == nil {
+			return d, nil
+		}
+		if filepath.Dir(d) == d {
+			return "", fmt.Errorf("go.mod not found in %s or its parents", abs)
+		}
+	}
+}
+
+// ChdirRoot changes the working directory to the root of its project (see
+// FindRoot), so gotest and gopush run from a subdirectory as from the root
+// and print paths relative to it. Outside any project it stays put.
+func ChdirRoot() error {
+	root, err := FindRoot(".")
+	if err != nil {
+		return nil
+	}
+	return os.Chdir(root)
+}
@@ -0,0 +1,112 @@
+package devflow
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestReadModule(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "go.mod"), []byte("// Deprecated: use v3\nmodule \"github.com/acme/api/v2\" // moved\n\ngo 1.22.3\n\nrequire example.com/x v1.0.0\n"), 0644)
+	m, err := ReadModule(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (&ModuleInfo{Path: "github.com/acme/api/v2", Name: "api", GoVersion: "1.22.3", Dir: dir}); !reflect.DeepEqual(m, want) {
+		t.Errorf("unexpected module %+v", m)
+	}
+
+	for path, name := range map[string]string{"example.com/tool": "tool", "tool": "tool", "example.com/v2": "example.com"} {
+		os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module "+path+"\n"), 0644)
+		if m, err := ReadModule(dir); err != nil || m.Name != name || m.GoVersion != "" {
+			t.Errorf("ReadModule(%s) = %+v, %v, want name %s", path, m, err, name)
+		}
+	}
+
+	os.WriteFile(filepath.Join(dir, "go.mod"), []byte("go 1.22\n"), 0644)
+	if _, err := ReadModule(dir); err == nil {
+		t.Error("expected a go.mod without module directive to fail")
+	}
+}
+
+func TestFindModuleFromSubdirectory(t *testing.T) {
+	root := t.TempDir()
+	os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/app\n\ngo 1.21\n"), 0644)
+	sub := filepath.Join(root, "internal", "store")
+	os.MkdirAll(sub, 0755)
+
+	m, err := FindModule(sub)
+	if err != nil || m.Path != "example.com/app" || m.Dir != root {
+		t.Fatalf("expected the module of the root, got %+v, %v", m, err)
+	}
+	if _, err := FindModule(t.TempDir()); err == nil {
+		t.Error("expected a directory outside any module to fail")
+	}
+
+	// The Go handler reads it once, until the root changes
+	defer testChdir(t, sub)()
+	g := &Go{rootDir: "."}
+	if path, err := g.getModulePath(); err != nil || path != "example.com/app" {
+		t.Fatalf("expected the module path from a subdirectory, got %q, %v", path, err)
+	}
+	if v, _ := g.GoVersion(); v != "1.21" {
+		t.Errorf("unexpected go version %q", v)
+	}
+	os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/other\n"), 0644)
+	if m, _ := g.Module(); m.Path != "example.com/app" {
+		t.Errorf("expected the module reused, got %s", m.Path)
+	}
+	g.SetRootDir(root)
+	if m, _ := g.Module(); m.Path != "example.com/other" {
+		t.Errorf("expected the module read again for a new root, got %s", m.Path)
+	}
+
+	// A run reads it once for all its steps, and the next run again
+	os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/third\n"), 0644)
+	end := g.beginRun()
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() { defer wg.Done(); g.Module() }()
+	}
+	wg.Wait()
+	os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/fourth\n"), 0644)
+	if m, _ := g.Module(); m.Path != "example.com/third" {
+		t.Errorf("expected the module of the run start, got %s", m.Path)
+	}
+	end()
+	defer g.beginRun()()
+	if m, _ := g.Module(); m.Path != "example.com/fourth" {
+		t.Errorf("expected the module read again by the next run, got %s", m.Path)
+	}
+}
+
+func TestFindRootStopsAtRepository(t *testing.T) {
+	repo := t.TempDir()
+	os.Mkdir(filepath.Join(repo, ".git"), 0755)
+	tools := filepath.Join(repo, "tools")
+	os.MkdirAll(filepath.Join(tools, "gen"), 0755)
+	os.WriteFile(filepath.Join(tools, "go.mod"), []byte("module example.com/tools\n"), 0644)
+	docs := filepath.Join(repo, "docs", "img")
+	os.MkdirAll(docs, 0755)
+
+	for dir, want := range map[string]string{filepath.Join(tools, "gen"): tools, docs: repo, repo: repo} {
+		if root, err := FindRoot(dir); err != nil || root != want {
+			t.Errorf("FindRoot(%s) = %s, %v, want %s", dir, root, err, want)
+		}
+	}
+	if _, err := FindModule(docs); err == nil {
+		t.Error("expected no module above the repository root")
+	}
+
+	defer testChdir(t, filepath.Join(tools, "gen"))()
+	if err := ChdirRoot(); err != nil {
+		t.Fatal(err)
+	}
+	if wd, _ := os.Getwd(); wd != tools {
+		t.Errorf("expected the module root as working directory, got %s", wd)
+	}
+}
@@ -0,0 +1,120 @@
+package devflow
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultProbeTimeout bounds each step of ProbeNetwork unless
+// network.probe_timeout is set
+const DefaultProbeTimeout = 2 * time.Second
+
+// probeCacheTTL is how long a probe result is reused for the same host
+const probeCacheTTL = 30 * time.Second
+
+// NetworkStatus is the result of ProbeNetwork
+type NetworkStatus struct {
+	Host     string
+	Online   bool
+	Resolved bool   // The DNS lookup succeeded
+	Reason   string // Why the host is unreachable, e.g. "DNS lookup failed"
+	Elapsed  time.Duration
+}
+
+// String returns "online" or "offline (<reason>)"
+func (s NetworkStatus) String() string {
+	if s.Online {
+		return "online"
+	}
+	return "offline (" + s.Reason + ")"
+}
+
+var probeCache struct {
+	sync.Mutex
+	results map[string]probeResult
+}
+
+type probeResult struct {
+	status NetworkStatus
+	at     time.Time
+}
+
+// probeNetwork is ProbeNetwork, replaced in tests
+var probeNetwork = ProbeNetwork
+
+// ProbeNetwork checks within a few seconds whether host (default: the
+// GitHub host) is reachable: a DNS lookup, then an HTTPS HEAD request,
+// each bounded by timeout (default: network.probe_timeout, else 2s).
+// With network.probe: false the host is always reported online.
+// Workflows call it before their network steps so an offline machine
+// degrades to the local-only path at once instead of after the long
+// gh and git timeouts. Results are cached for 30 seconds per host.
+func ProbeNetwork(host string, timeout time.Duration) NetworkStatus {
+	if host == "" {
+		host = (&GitHub{}).Host()
+	}
+	status := NetworkStatus{Host: host}
+	// Air-gap mode does not probe public hosts; its own checks apply
+	enabled, configured := probeSettings()
+	if !enabled || (host == "github.com" && AirGapCheck(NetGitHub) != nil) {
+		status.Online = true
+		return status
+	}
+	if timeout <= 0 {
+		timeout = configured
+	}
+
+	probeCache.Lock()
+	if r, ok := probeCache.results[host]; ok && time.Since(r.at) < probeCacheTTL {
+		probeCache.Unlock()
+		return r.status
+	}
+	probeCache.Unlock()
+
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if _, err := net.DefaultResolver.LookupHost(ctx, host); err != nil {
+		status.Reason = "DNS lookup of " + host + " failed"
+	} else {
+		status.Resolved = true
+		client := &http.Client{
+			Timeout:       timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		}
+		req, _ := http.NewRequest(http.MethodHead, "https://"+host+"/", nil)
+		if resp, err := client.Do(req); err != nil {
+			status.Reason = fmt.Sprintf("https://%s unreachable", host)
+		} else {
+			resp.Body.Close()
+			status.Online = true // Any HTTP answer means the host is up
+		}
+	}
+	status.Elapsed = time.Since(start)
+
+	probeCache.Lock()
+	if probeCache.results == nil {
+		probeCache.results = map[string]probeResult{}
+	}
+	probeCache.results[host] = probeResult{status, time.Now()}
+	probeCache.Unlock()
+	return status
+}
+
+// probeSettings reads network.probe and network.probe_timeout from the
+// config of the current directory
+func probeSettings() (bool, time.Duration) {
+	cfg, err := LoadConfig(".")
+	if err != nil {
+		return true, DefaultProbeTimeout
+	}
+	timeout := DefaultProbeTimeout
+	if d, err := time.ParseDuration(cfg.String("network.probe_timeout", "")); err == nil && d > 0 {
+		timeout = d
+	}
+	return cfg.Bool("network.probe", true), timeout
+}
@@ -0,0 +1,75 @@
+package devflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProbeNetwork(t *testing.T) {
+	defer testChdir(t, t.TempDir())()
+
+	start := time.Now()
+	st := ProbeNetwork("devflow-probe.invalid", 500*time.Millisecond)
+	if st.Online || st.Resolved || !strings.Contains(st.Reason, "DNS lookup") {
+		t.Errorf("unexpected status: %+v", st)
+	}
+	if time.Since(start) > 2*time.Second {
+		t.Errorf("probe took %s", time.Since(start))
+	}
+	if st.String() != "offline (DNS lookup of devflow-probe.invalid failed)" {
+		t.Errorf("String() = %q", st.String())
+	}
+
+	os.WriteFile(".devflow.yaml", []byte("network:\n  probe: false\n"), 0644)
+	if st := ProbeNetwork("devflow-probe2.invalid", 0); !st.Online {
+		t.Errorf("disabled probe should report online, got %+v", st)
+	}
+}
+
+func TestGoNewCreateOffline(t *testing.T) {
+	tmp := testResumeEnv(t)
+	testOffline(t, false, "DNS lookup of github.com failed")
+	git, err := NewGit()
+	if err != nil {
+		t.Skip("git not installed")
+	}
+	goHandler, _ := NewGo(git)
+	provider := &testProvider{dir: filepath.Join(tmp, "remotes")}
+	gn := NewGoNew(git, NewResolvedFuture(provider), goHandler)
+
+	dir := filepath.Join(tmp, "offline-lib")
+	summary, err := gn.Create(NewProjectOptions{Name: "offline-lib", Description: "Offline", Directory: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(summary, "[local only] v0.0.1 - offline (DNS lookup of github.com failed)") || !strings.Contains(summary, "gonew resume offline-lib") {
+		t.Errorf("unexpected summary: %q", summary)
+	}
+	if provider.created != 0 {
+		t.Error("no remote should be created offline")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "go.mod")); err != nil {
+		t.Error("project not created locally")
+	}
+}
+
+func TestCheckRemoteAccessOffline(t *testing.T) {
+	dir, cleanup := testCreateGitRepo()
+	defer cleanup()
+	defer testChdir(t, dir)()
+	RunCommand("git", "remote", "add", "origin", "https://github.com/o/r.git")
+	git, _ := NewGit()
+
+	testOffline(t, true, "https://github.com unreachable")
+	err := git.CheckRemoteAccess()
+	if e, ok := err.(*RemoteAccessError); !ok || e.Kind != RemoteNetwork || e.Detail != "https://github.com unreachable" {
+		t.Errorf("expected network error, got %v", err)
+	}
+	testOffline(t, false, "DNS lookup of github.com failed")
+	if e, ok := git.CheckRemoteAccess().(*RemoteAccessError); !ok || e.Kind != RemoteDNS || e.Remedy != "nslookup github.com" {
+		t.Errorf("expected DNS error, got %v", e)
+	}
+}
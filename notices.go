@@ -0,0 +1,150 @@
+package devflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// NoticesFile aggregates the license texts of third-party dependencies
+const NoticesFile = "THIRD_PARTY_NOTICES.md"
+
+// noticeLicenseNames are the license file names looked up in each module
+var noticeLicenseNames = []string{
+	"LICENSE", "LICENSE.md", "LICENSE.txt", "LICENCE", "LICENCE.md",
+	"COPYING", "COPYING.md", "COPYING.txt", "LICENSE-MIT", "LICENSE-APACHE",
+}
+
+// ModuleNotice is the license of one dependency
+type ModuleNotice struct {
+	Path    string
+	Version string
+	SPDX    string // Detected identifier, empty if unknown
+	Text    string // License text, empty if not found in the module cache
+}
+
+// BuildDependencies lists the non-main modules providing packages to the
+// build of dir (go list -deps), with their module cache directories
+func BuildDependencies(dir string) (mods []ModuleVersion, dirs map[string]string, err error) {
+	out, err := RunCommandInDir(dir, "go", "list", "-deps",
+		"-f", "{{with .Module}}{{if not .Main}}{{.Path}}\t{{.Version}}\t{{.Dir}}{{end}}{{end}}", "./...")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dirs = make(map[string]string)
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 {
+			continue
+		}
+		if _, seen := dirs[fields[0]]; seen {
+			continue
+		}
+		dirs[fields[0]] = fields[2]
+		mods = append(mods, ModuleVersion{Path: fields[0], Version: fields[1]})
+	}
+	sort.Slice(mods, func(i, j int) bool { return mods[i].Path < mods[j].Path })
+	return mods, dirs, nil
+}
+
+// CollectNotices reads the license of every build dependency of dir from
+// the module cache
+func CollectNotices(dir string) ([]ModuleNotice, error) {
+	mods, dirs, err := BuildDependencies(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	notices := make([]ModuleNotice, 0, len(mods))
+	for _, m := range mods {
+		n := ModuleNotice{Path: m.Path, Version: m.Version}
+		if modDir := dirs[m.Path]; modDir != "" {
+			var texts []string
+			for _, name := range noticeLicenseNames {
+				if data, err := os.ReadFile(filepath.Join(modDir, name)); err == nil {
+					texts = append(texts, strings.TrimSpace(string(data)))
+				}
+			}
+			n.Text = strings.Join(texts, "\n\n")
+			n.SPDX = detectLicenseText(n.Text)
+		}
+		notices = append(notices, n)
+	}
+	return notices, nil
+}
+
+// RenderNotices formats the notices file. The output is deterministic so
+// regenerating without dependency changes leaves the file untouched.
+func RenderNotices(modulePath string, notices []ModuleNotice) string {
+	var b strings.Builder
+	b.WriteString("# Third-Party Notices\n\n")
+	fmt.Fprintf(&b, "%s includes the following third-party modules. Generated by devflow, do not edit.\n", modulePath)
+
+	if len(notices) == 0 {
+		b.WriteString("\nNo third-party modules.\n")
+		return b.String()
+	}
+
+	b.WriteString("\n| Module | Version | License |\n|--------|---------|---------|\n")
+	for _, n := range notices {
+		spdx := n.SPDX
+		if spdx == "" {
+			spdx = "unknown"
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s |\n", n.Path, n.Version, spdx)
+	}
+
+	for _, n := range notices {
+		fmt.Fprintf(&b, "\n## %s %s\n\n", n.Path, n.Version)
+		if n.Text == "" {
+			b.WriteString("License text not found in the module cache.\n")
+			continue
+		}
+		fmt.Fprintf(&b, "```\n%s\n```\n", n.Text)
+	}
+	return b.String()
+}
+
+// UpdateNotices regenerates THIRD_PARTY_NOTICES.md in rootDir when the file
+// already exists or notices.enabled is true. Returns a summary message only
+// when the file changed.
+func (g *Go) UpdateNotices() (string, error) {
+	path := filepath.Join(g.rootDir, NoticesFile)
+	current, readErr := os.ReadFile(path)
+	if readErr != nil && !g.Config().Bool("notices.enabled", false) {
+		return "", nil
+	}
+
+	module, err := g.Module()
+	if err != nil {
+		return "", err
+	}
+	modulePath := module.Path
+	notices, err := CollectNotices(g.rootDir)
+	if err != nil {
+		return "", err
+	}
+
+	content := RenderNotices(modulePath, notices)
+	if readErr == nil && string(current) == content {
+		return "", nil
+	}
+	if err := writeUnlessDryRun(path, []byte(content)); err != nil {
+		return "", err
+	}
+
+	missing := 0
+	for _, n := range notices {
+		if n.Text == "" {
+			missing++
+		}
+	}
+	msg := fmt.Sprintf("✅ Notices: %d modules", len(notices))
+	if missing > 0 {
+		msg = fmt.Sprintf("⚠️ Notices: %d modules, %d without license text", len(notices), missing)
+	}
+	return msg, nil
+}
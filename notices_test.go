@@ -0,0 +1,77 @@
+package devflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testNoticesModule(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	dep := filepath.Join(root, "dep")
+	app := filepath.Join(root, "app")
+	os.MkdirAll(dep, 0755)
+	os.MkdirAll(app, 0755)
+
+	os.WriteFile(filepath.Join(dep, "go.mod"), []byte("module example.com/dep\n\ngo 1.21\n"), 0644)
+	os.WriteFile(filepath.Join(dep, "dep.go"), []byte("package dep\n\nfunc X() {}\n"), 0644)
+	GenerateLicense("Dep Author", dep)
+
+	os.WriteFile(filepath.Join(app, "go.mod"), []byte("module example.com/app\n\ngo 1.21\n\nrequire example.com/dep v0.0.0\n\nreplace example.com/dep => ../dep\n"), 0644)
+	os.WriteFile(filepath.Join(app, "app.go"), []byte("package app\n\nimport \"example.com/dep\"\n\nfunc Y() { dep.X() }\n"), 0644)
+
+	t.Setenv("GOFLAGS", "-mod=mod")
+	t.Setenv("GOPROXY", "off")
+	t.Setenv("GOWORK", "off")
+	return app
+}
+
+func TestCollectNotices(t *testing.T) {
+	app := testNoticesModule(t)
+
+	notices, err := CollectNotices(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notices) != 1 || notices[0].Path != "example.com/dep" || notices[0].SPDX != "MIT" {
+		t.Fatalf("unexpected notices: %+v", notices)
+	}
+	if !strings.Contains(notices[0].Text, "Dep Author") {
+		t.Errorf("license text not read: %q", notices[0].Text)
+	}
+
+	out := RenderNotices("example.com/app", notices)
+	for _, want := range []string{"# Third-Party Notices", "| example.com/dep | v0.0.0 | MIT |", "## example.com/dep v0.0.0", "Dep Author"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("rendered notices missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestUpdateNotices(t *testing.T) {
+	app := testNoticesModule(t)
+	g, _ := NewGo(nil)
+	g.SetRootDir(app)
+
+	// Opt-in: nothing happens without the file or notices.enabled
+	g.SetConfig(NewConfig())
+	if msg, err := g.UpdateNotices(); err != nil || msg != "" {
+		t.Fatalf("expected no-op, got %q %v", msg, err)
+	}
+
+	cfg := NewConfig()
+	cfg.Set("notices.enabled", "true")
+	g.SetConfig(cfg)
+	msg, err := g.UpdateNotices()
+	if err != nil || msg != "✅ Notices: 1 modules" {
+		t.Fatalf("unexpected result: %q %v", msg, err)
+	}
+
+	// Unchanged dependencies: file left as is
+	g.SetConfig(NewConfig())
+	if msg, err := g.UpdateNotices(); err != nil || msg != "" {
+		t.Errorf("expected no change, got %q %v", msg, err)
+	}
+}
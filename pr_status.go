@@ -0,0 +1,180 @@
+package devflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PRInfo is a pull request listed by GitHub.ListPRs
+type PRInfo struct {
+	Number         int
+	Title          string
+	URL            string
+	Head           string // Branch with the changes
+	Base           string // Branch the changes are merged into
+	State          string // OPEN, CLOSED or MERGED
+	Author         string
+	Draft          bool
+	ReviewDecision string // APPROVED, CHANGES_REQUESTED, REVIEW_REQUIRED or empty
+}
+
+// PRCheck is a CI check of a pull request, as gh pr checks reports it
+type PRCheck struct {
+	Name   string
+	State  string // As reported by the check: SUCCESS, FAILURE, IN_PROGRESS, ...
+	Bucket string // pass, fail, pending, skipping or cancel
+	Link   string
+}
+
+// Check buckets of PRCheck
+const (
+	CheckPass     = "pass"
+	CheckFail     = "fail"
+	CheckPending  = "pending"
+	CheckSkipping = "skipping"
+	CheckCancel   = "cancel"
+)
+
+// prChecksInterval is how often WaitForPRChecks polls the checks
+var prChecksInterval = 15 * time.Second
+
+// prChecksGrace is how long a pull request without checks is waited for:
+// the workflows of a new push take a moment to register theirs
+var prChecksGrace = time.Minute
+
+// ListPRs returns the pull requests of the repository of the current
+// directory in state (open, closed, merged or all; default open), most
+// recent first
+func (gh *GitHub) ListPRs(state string) ([]PRInfo, error) {
+	if state == "" {
+		state = "open"
+	}
+	out, err := RunCommandSilent("gh", "pr", "list", "--state", state, "--limit", "100",
+		"--json", "number,title,url,headRefName,baseRefName,state,author,isDraft,reviewDecision")
+	if err != nil {
+		return nil, fmt.Errorf("gh pr list failed: %s", firstLine(out))
+	}
+	var resp []struct {
+		Number         int    `json:"number"`
+		Title          string `json:"title"`
+		URL            string `json:"url"`
+		HeadRefName    string `json:"headRefName"`
+		BaseRefName    string `json:"baseRefName"`
+		State          string `json:"state"`
+		IsDraft        bool   `json:"isDraft"`
+		ReviewDecision string `json:"reviewDecision"`
+		Author         struct {
+			Login string `json:"login"`
+		} `json:"author"`
+	}
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse gh pr list: %w", err)
+	}
+	prs := make([]PRInfo, 0, len(resp))
+	for _, p := range resp {
+		prs = append(prs, PRInfo{
+			Number: p.Number, Title: p.Title, URL: p.URL, Head: p.HeadRefName, Base: p.BaseRefName,
+			State: p.State, Author: p.Author.Login, Draft: p.IsDraft, ReviewDecision: p.ReviewDecision,
+		})
+	}
+	return prs, nil
+}
+
+// GetPRChecks returns the checks of the pull request pr (number, URL or
+// branch), none while the workflows have not reported yet
+func (gh *GitHub) GetPRChecks(pr string) ([]PRCheck, error) {
+	// gh pr checks also exits non-zero while checks are pending or failing,
+	// with the JSON still printed
+	out, err := RunCommandSilent("gh", "pr", "checks", pr, "--json", "name,state,bucket,link")
+	if strings.Contains(out, "no checks reported") {
+		return nil, nil
+	}
+	var checks []PRCheck
+	if start, end := strings.Index(out, "["), strings.LastIndex(out, "]"); start >= 0 && end > start {
+		if jerr := json.Unmarshal([]byte(out[start:end+1]), &checks); jerr == nil {
+			return checks, nil
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("gh pr checks failed: %s", firstLine(out))
+	}
+	return nil, fmt.Errorf("failed to parse gh pr checks: %s", firstLine(out))
+}
+
+// WaitForPRChecks polls the checks of pr until none is pending and returns
+// them; it fails when a check failed or was cancelled, or when some are
+// still pending after timeout. A pull request that reports no checks
+// within a minute has none to wait for.
+func (gh *GitHub) WaitForPRChecks(pr string, timeout time.Duration) ([]PRCheck, error) {
+	return waitForPRChecks(gh.GetPRChecks, gh.logger.Info, pr, timeout)
+}
+
+// waitForPRChecks is WaitForPRChecks over any checks client
+func waitForPRChecks(get func(pr string) ([]PRCheck, error), log func(...any), pr string, timeout time.Duration) ([]PRCheck, error) {
+	start := time.Now()
+	for {
+		checks, err := get(pr)
+		if err != nil {
+			return nil, err
+		}
+		var pending, failed []string
+		for _, c := range checks {
+			switch c.Bucket {
+			case CheckPending:
+				pending = append(pending, c.Name)
+			case CheckFail, CheckCancel:
+				failed = append(failed, c.Name)
+			}
+		}
+		waited := time.Since(start)
+		switch {
+		case len(failed) > 0:
+			return checks, fmt.Errorf("checks failed: %s", strings.Join(failed, ", "))
+		case len(checks) == 0 && waited >= prChecksGrace:
+			return checks, nil
+		case len(checks) > 0 && len(pending) == 0:
+			return checks, nil
+		case waited >= timeout:
+			if len(pending) == 0 {
+				return checks, fmt.Errorf("no checks reported after %s", timeout)
+			}
+			return checks, fmt.Errorf("checks still pending after %s: %s", timeout, strings.Join(pending, ", "))
+		}
+		if len(pending) > 0 {
+			log("Waiting for checks:", strings.Join(pending, ", "))
+		}
+		time.Sleep(min(prChecksInterval, timeout-waited))
+	}
+}
+
+// MergePR merges the pull request pr (number, URL or branch) with method
+// (squash, merge or rebase), deleting its branch when deleteBranch is set
+func (gh *GitHub) MergePR(pr, method string, deleteBranch bool) error {
+	if err := validateAutoMerge(method); err != nil || method == "off" {
+		return fmt.Errorf("unknown merge method %q (want %s, %s or %s)", method, MergeSquash, MergeCommit, MergeRebase)
+	}
+	args := []string{"pr", "merge", pr, "--" + method}
+	if deleteBranch {
+		args = append(args, "--delete-branch")
+	}
+	if out, err := RunCommand("gh", args...); err != nil {
+		return fmt.Errorf("gh pr merge failed: %s", firstLine(out))
+	}
+	return nil
+}
+
+// ApprovePR approves the pull request pr (number, URL or branch), with body
+// as the review comment when set. GitHub does not let authors approve
+// their own pull requests.
+func (gh *GitHub) ApprovePR(pr, body string) error {
+	args := []string{"pr", "review", pr, "--approve"}
+	if body != "" {
+		args = append(args, "--body", body)
+	}
+	if out, err := RunCommand("gh", args...); err != nil {
+		return fmt.Errorf("gh pr review failed: %s", firstLine(out))
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package devflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWaitForPRChecks(t *testing.T) {
+	defer func(interval, grace time.Duration) { prChecksInterval, prChecksGrace = interval, grace }(prChecksInterval, prChecksGrace)
+	prChecksInterval, prChecksGrace = time.Millisecond, 5*time.Millisecond
+	noLog := func(...any) {}
+
+	// Polls until the pending checks finish
+	polls := 0
+	get := func(string) ([]PRCheck, error) {
+		polls++
+		if polls < 3 {
+			return []PRCheck{{Name: "test", Bucket: CheckPending}, {Name: "lint", Bucket: CheckPass}}, nil
+		}
+		return []PRCheck{{Name: "test", Bucket: CheckPass}, {Name: "lint", Bucket: CheckPass}, {Name: "docs", Bucket: CheckSkipping}}, nil
+	}
+	checks, err := waitForPRChecks(get, noLog, "1", time.Minute)
+	if err != nil || polls != 3 || len(checks) != 3 {
+		t.Fatalf("expected the checks after 3 polls, got %v, %d, %v", checks, polls, err)
+	}
+
+	failing := func(string) ([]PRCheck, error) {
+		return []PRCheck{{Name: "test", Bucket: CheckFail}, {Name: "e2e", Bucket: CheckPending}}, nil
+	}
+	if _, err := waitForPRChecks(failing, noLog, "1", time.Minute); err == nil || err.Error() != "checks failed: test" {
+		t.Errorf("expected a failed check error, got %v", err)
+	}
+
+	pending := func(string) ([]PRCheck, error) {
+		return []PRCheck{{Name: "e2e", Bucket: CheckPending}}, nil
+	}
+	if _, err := waitForPRChecks(pending, noLog, "1", 20*time.Millisecond); err == nil || !strings.Contains(err.Error(), "still pending after 20ms: e2e") {
+		t.Errorf("expected a timeout error, got %v", err)
+	}
+
+	// A pull request without checks is merged after the grace period
+	none := func(string) ([]PRCheck, error) { return nil, nil }
+	if _, err := waitForPRChecks(none, noLog, "1", time.Minute); err != nil {
+		t.Errorf("expected no checks to pass, got %v", err)
+	}
+}
+
+func TestGetPRChecksUnbalancedOutput(t *testing.T) {
+	bin := t.TempDir()
+	os.WriteFile(filepath.Join(bin, "gh"), []byte("#!/bin/sh\necho 'warning: ] unexpected [ token'\nexit 1\n"), 0755)
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	if _, err := (&GitHub{}).GetPRChecks("1"); err == nil || !strings.Contains(err.Error(), "gh pr checks failed") {
+		t.Errorf("expected the gh error, got %v", err)
+	}
+}
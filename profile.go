@@ -0,0 +1,135 @@
+package devflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PackageProfile holds the profiles captured for one package
+type PackageProfile struct {
+	Package string
+	Binary  string // Test binary, needed by pprof for symbols
+	CPU     string
+	Mem     string
+	Err     error // Test failure; profiles may still exist
+}
+
+// ProfileDir returns the directory where gotest -profile stores the
+// profiles of the current module; each run replaces the previous one
+func (tc *TestCache) ProfileDir() (string, error) {
+	path, err := tc.getCachePath()
+	if err != nil {
+		return "", err
+	}
+	return path + "-profiles", nil
+}
+
+// Profile runs the tests of each package with -cpuprofile and -memprofile
+// (go test accepts them for a single package only) and returns the
+// go tool pprof commands to inspect them. With top > 0 the top entries of
+// each CPU profile are included.
+func (g *Go) Profile(top int) (string, error) {
+	dir, err := NewTestCache().ProfileDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	pkgs, err := packagesWithTests()
+	if err != nil {
+		return "", err
+	}
+	if len(pkgs) == 0 {
+		return "No packages with tests to profile", nil
+	}
+
+	var profiles []PackageProfile
+	for _, pkg := range pkgs {
+		g.logger.Info("Profiling", pkg+"...")
+		profiles = append(profiles, profilePackage(dir, pkg))
+	}
+
+	var b strings.Builder
+	failed := 0
+	fmt.Fprintf(&b, "Profiles in %s\n", dir)
+	for _, p := range profiles {
+		fmt.Fprintf(&b, "\n%s\n", p.Package)
+		if p.Err != nil {
+			failed++
+			fmt.Fprintf(&b, "  ❌ tests failed: %v\n", firstLine(p.Err.Error()))
+		}
+		if p.CPU != "" {
+			fmt.Fprintf(&b, "  cpu: go tool pprof -top %s %s\n", p.Binary, p.CPU)
+		}
+		if p.Mem != "" {
+			fmt.Fprintf(&b, "  mem: go tool pprof -top -sample_index=alloc_space %s %s\n", p.Binary, p.Mem)
+		}
+		if top > 0 && p.CPU != "" {
+			out, err := RunCommandSilent("go", "tool", "pprof", "-top", fmt.Sprintf("-nodecount=%d", top), p.Binary, p.CPU)
+			if err == nil {
+				b.WriteString(indent(out, "    ") + "\n")
+			}
+		}
+	}
+	b.WriteString("\nInteractive: go tool pprof -http=: <binary> <profile>")
+
+	if failed > 0 {
+		return b.String(), fmt.Errorf("tests failed in %d package(s) while profiling", failed)
+	}
+	return b.String(), nil
+}
+
+// profilePackage runs go test for one package with profiling enabled
+func profilePackage(dir, pkg string) PackageProfile {
+	name := profileFileName(pkg)
+	p := PackageProfile{
+		Package: pkg,
+		Binary:  filepath.Join(dir, name+".test"),
+		CPU:     filepath.Join(dir, name+".cpu.prof"),
+		Mem:     filepath.Join(dir, name+".mem.prof"),
+	}
+	_, p.Err = RunCommandSilent("go", "test", "-count=1", "-o", p.Binary,
+		"-cpuprofile", p.CPU, "-memprofile", p.Mem, pkg)
+
+	for _, f := range []*string{&p.CPU, &p.Mem} {
+		if _, err := os.Stat(*f); err != nil {
+			*f = ""
+		}
+	}
+	return p
+}
+
+// packagesWithTests lists the packages of the current module with test files
+func packagesWithTests() ([]string, error) {
+	out, err := RunCommandSilent("go", "list", "-f", "{{if or .TestGoFiles .XTestGoFiles}}{{.ImportPath}}{{end}}", "./...")
+	if err != nil {
+		return nil, err
+	}
+	var pkgs []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			pkgs = append(pkgs, line)
+		}
+	}
+	return pkgs, nil
+}
+
+// profileFileName turns an import path into a file name
+func profileFileName(pkg string) string {
+	return strings.NewReplacer("/", "_", ".", "_", "@", "_").Replace(pkg)
+}
+
+func indent(s, prefix string) string {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		lines[i] = prefix + l
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,54 @@
+package devflow
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// startGoCache is read before any test changes HOME, so builds keep using
+// the warm cache
+var startGoCache, _ = exec.Command("go", "env", "GOCACHE").Output()
+
+func TestProfileFileName(t *testing.T) {
+	if got := profileFileName("github.com/acme/app/v2"); got != "github_com_acme_app_v2" {
+		t.Errorf("profileFileName = %q", got)
+	}
+}
+
+func TestProfile(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/profiled\n\ngo 1.21\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "a.go"), []byte("package profiled\n\nfunc Sum(n int) int {\n\ts := 0\n\tfor i := 0; i < n; i++ {\n\t\ts += i\n\t}\n\treturn s\n}\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "a_test.go"), []byte("package profiled\n\nimport \"testing\"\n\nfunc TestSum(t *testing.T) {\n\tif Sum(3) != 3 {\n\t\tt.Fatal()\n\t}\n}\n"), 0644)
+	os.MkdirAll(filepath.Join(dir, "notests"), 0755)
+	os.WriteFile(filepath.Join(dir, "notests", "b.go"), []byte("package notests\n"), 0644)
+	defer testChdir(t, dir)()
+	if c := strings.TrimSpace(string(startGoCache)); c != "" {
+		t.Setenv("GOCACHE", c)
+	}
+
+	g := &Go{logger: NopLogger()}
+	summary, err := g.Profile(0)
+	if err != nil {
+		t.Fatalf("Profile: %v\n%s", err, summary)
+	}
+	if strings.Contains(summary, "notests") {
+		t.Errorf("package without tests should not be profiled:\n%s", summary)
+	}
+
+	profileDir, _ := NewTestCache().ProfileDir()
+	for _, name := range []string{"example_com_profiled.cpu.prof", "example_com_profiled.mem.prof", "example_com_profiled.test"} {
+		if _, err := os.Stat(filepath.Join(profileDir, name)); err != nil {
+			t.Errorf("missing %s: %v", name, err)
+		}
+		if !strings.Contains(summary, name) {
+			t.Errorf("summary does not mention %s:\n%s", name, summary)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "profiled.test")); err == nil {
+		t.Error("test binary should not be left in the module directory")
+	}
+}
@@ -0,0 +1,73 @@
+package devflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ProjectAliases returns the project aliases of the global config and the
+// directory of each, "~/" expanded:
+//
+//	projects:
+//	  api: ~/work/api
+//	  web: ~/work/web
+func ProjectAliases(c *Config) map[string]string {
+	aliases := make(map[string]string)
+	if c == nil {
+		return aliases
+	}
+	home, _ := os.UserHomeDir()
+	for _, alias := range c.Keys("projects") {
+		dir := c.String("projects."+alias, "")
+		if dir == "" {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(dir, "~/"); ok && home != "" {
+			dir = filepath.Join(home, rest)
+		}
+		aliases[alias] = filepath.Clean(dir)
+	}
+	return aliases
+}
+
+// ResolveProject returns the directory of a project alias from the global
+// config. A path to an existing directory is accepted as is.
+func ResolveProject(alias string) (string, error) {
+	global, err := LoadGlobalConfig()
+	if err != nil {
+		return "", err
+	}
+	aliases := ProjectAliases(global)
+	dir, ok := aliases[alias]
+	if !ok {
+		if info, err := os.Stat(alias); err == nil && info.IsDir() {
+			return alias, nil
+		}
+		known := make([]string, 0, len(aliases))
+		for name := range aliases {
+			known = append(known, name)
+		}
+		sort.Strings(known)
+		if len(known) == 0 {
+			return "", fmt.Errorf("unknown project %q (no projects in %s)", alias, GlobalConfigFile)
+		}
+		return "", fmt.Errorf("unknown project %q (known: %s)", alias, strings.Join(known, ", "))
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("project %s: %s is not a directory", alias, dir)
+	}
+	return dir, nil
+}
+
+// ChdirProject changes the working directory to the project alias, for
+// the -p flag of the commands
+func ChdirProject(alias string) error {
+	dir, err := ResolveProject(alias)
+	if err != nil {
+		return err
+	}
+	return os.Chdir(dir)
+}
@@ -0,0 +1,46 @@
+package devflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveProject(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+
+	if _, err := ResolveProject("api"); err == nil || !strings.Contains(err.Error(), "no projects") {
+		t.Errorf("err = %v", err)
+	}
+
+	os.MkdirAll(filepath.Join(home, "work", "api"), 0755)
+	os.MkdirAll(filepath.Join(home, ".config", "devflow"), 0755)
+	os.WriteFile(filepath.Join(home, ".config", GlobalConfigFile), []byte("projects:\n  api: ~/work/api\n  gone: ~/work/gone\n"), 0644)
+
+	dir, err := ResolveProject("api")
+	if err != nil || dir != filepath.Join(home, "work", "api") {
+		t.Errorf("api = %q, %v", dir, err)
+	}
+	if _, err := ResolveProject("gone"); err == nil || !strings.Contains(err.Error(), "not a directory") {
+		t.Errorf("missing dir: %v", err)
+	}
+	if _, err := ResolveProject("web"); err == nil || !strings.Contains(err.Error(), "known: api, gone") {
+		t.Errorf("unknown alias: %v", err)
+	}
+	// Plain directories work too
+	if dir, err := ResolveProject(home); err != nil || dir != home {
+		t.Errorf("dir = %q, %v", dir, err)
+	}
+
+	cwd, _ := os.Getwd()
+	defer os.Chdir(cwd)
+	if err := ChdirProject("api"); err != nil {
+		t.Fatal(err)
+	}
+	if wd, _ := os.Getwd(); wd != filepath.Join(home, "work", "api") {
+		t.Errorf("cwd = %s", wd)
+	}
+}
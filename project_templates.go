@@ -2,11 +2,11 @@ package devflow
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
-	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 // ValidateRepoName validates the repository name
@@ -25,49 +25,67 @@ func ValidateRepoName(name string) error {
 	return nil
 }
 
-// ValidateDescription validates the repository description
+// MaxRepoDescription is GitHub's limit for repository descriptions, in
+// characters
+const MaxRepoDescription = 350
+
+// maxDescription bounds the full description written to README.md
+const maxDescription = 2000
+
+// ValidateDescription validates the repository description. Longer than
+// MaxRepoDescription is fine: the remote gets SanitizeDescription of it
+// and README.md the full text.
 func ValidateDescription(desc string) error {
-	if desc == "" {
+	if strings.TrimSpace(desc) == "" {
 		return fmt.Errorf("description is required")
 	}
-	if len(desc) > 350 {
-		return fmt.Errorf("description too long (max 350 chars)")
+	if utf8.RuneCountInString(desc) > maxDescription {
+		return fmt.Errorf("description too long (max %d chars)", maxDescription)
 	}
 	return nil
 }
 
+// SanitizeDescription makes desc a valid provider description: one line
+// without control characters, collapsed whitespace, and at most
+// MaxRepoDescription characters, cut at a word with "…"
+func SanitizeDescription(desc string) string {
+	clean := strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || unicode.IsSpace(r) {
+			return ' '
+		}
+		if r == utf8.RuneError || unicode.Is(unicode.Cf, r) {
+			return -1 // Invalid UTF-8, zero-width and bidi marks
+		}
+		return r
+	}, desc)
+	clean = strings.Join(strings.Fields(clean), " ")
+	runes := []rune(clean)
+	if len(runes) <= MaxRepoDescription {
+		return clean
+	}
+	cut := string(runes[:MaxRepoDescription-1])
+	if i := strings.LastIndex(cut, " "); i > len(cut)/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " .,;:") + "…"
+}
+
 // GenerateREADME generates README.md
 func GenerateREADME(repoName, description, targetDir string) error {
-	content := fmt.Sprintf("# %s\n\n%s\n", repoName, description)
-	return os.WriteFile(filepath.Join(targetDir, "README.md"), []byte(content), 0644)
+	return GenerateREADMEWithAuthor(repoName, description, Author{}, targetDir)
+}
+
+// GenerateREADMEWithAuthor generates README.md with an Author section
+// (none when author has no name)
+func GenerateREADMEWithAuthor(repoName, description string, author Author, targetDir string) error {
+	content := fmt.Sprintf("# %s\n\n%s\n", repoName, description) + authorSection(author)
+	return writeGenerated(filepath.Join(targetDir, "README.md"), []byte(content))
 }
 
 // GenerateLicense generates LICENSE (MIT)
 func GenerateLicense(ownerName, targetDir string) error {
-	year := time.Now().Year()
-	content := fmt.Sprintf(`MIT License
-
-Copyright (c) %d %s
-
-Permission is hereby granted, free of charge, to any person obtaining a copy
-of this software and associated documentation files (the "Software"), to deal
-in the Software without restriction, including without limitation the rights
-to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
-copies of the Software, and to permit persons to whom the Software is
-furnished to do so, subject to the following conditions:
-
-The above copyright notice and this permission notice shall be included in all
-copies or substantial portions of the Software.
-
-THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
-IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
-FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
-AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
-LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
-OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
-SOFTWARE.
-`, year, ownerName)
-	return os.WriteFile(filepath.Join(targetDir, "LICENSE"), []byte(content), 0644)
+	_, err := GenerateLicenseFile("MIT", ownerName, targetDir)
+	return err
 }
 
 // GenerateGitignore generates .gitignore for Go
@@ -91,34 +109,20 @@ coverage.html
 *.code-workspace
 go.work
 `
-	return os.WriteFile(filepath.Join(targetDir, ".gitignore"), []byte(content), 0644)
+	return writeGenerated(filepath.Join(targetDir, ".gitignore"), []byte(content))
 }
 
-// GenerateHandlerFile generates the main handler file
+// GenerateHandlerFile generates the main handler file (library projects)
 func GenerateHandlerFile(repoName, targetDir string) error {
-	// Convert repo-name to RepoName
-	structName := kebabToCamel(repoName)
-	// Convert RepoName to r (first letter lowercase)
-	// Actually spec says: First letter lowercase of struct name
-	// But it says: Variable: First letter lowercase of struct name (e.g., m := &MyRepo{})
-	// This is for usage, but what about the file content?
-	// "Basic struct + New() constructor (bash script pattern)"
-
-	packageName := strings.ReplaceAll(repoName, "-", "")
-	packageName = strings.ReplaceAll(packageName, "_", "")
-	packageName = strings.ToLower(packageName)
-
-	content := fmt.Sprintf(`package %s
-
-type %s struct {}
-
-func New() *%s {
-    return &%s{}
+	filename := fmt.Sprintf("%s.go", repoName)
+	return writeGenerated(filepath.Join(targetDir, filename), []byte(handlerFile(repoName)))
 }
-`, packageName, structName, structName, structName)
 
-	filename := fmt.Sprintf("%s.go", repoName)
-	return os.WriteFile(filepath.Join(targetDir, filename), []byte(content), 0644)
+// projectPackageName derives the Go package name from the repository name
+func projectPackageName(repoName string) string {
+	name := strings.ReplaceAll(repoName, "-", "")
+	name = strings.ReplaceAll(name, "_", "")
+	return strings.ToLower(name)
 }
 
 // kebabToCamel converts kebab-case or snake_case to CamelCase
@@ -0,0 +1,210 @@
+package devflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Project types scaffolded by gonew
+const (
+	ProjectLibrary = "library" // Exported handler with a New constructor (default)
+	ProjectCLI     = "cli"     // cmd/<name>/main.go with flag parsing
+	ProjectWasm    = "wasm"    // js/wasm main and index.html
+	ProjectWeb     = "web"     // net/http server
+)
+
+// ProjectTypes lists the supported project types
+var ProjectTypes = []string{ProjectLibrary, ProjectCLI, ProjectWasm, ProjectWeb}
+
+// ValidateProjectType checks typ, empty meaning library
+func ValidateProjectType(typ string) error {
+	if typ == "" {
+		return nil
+	}
+	for _, t := range ProjectTypes {
+		if t == typ {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown project type %q (want %s)", typ, strings.Join(ProjectTypes, ", "))
+}
+
+// GenerateProjectFiles writes the Go skeleton of a project type and
+// returns the paths written, relative to targetDir
+func GenerateProjectFiles(typ, repoName, targetDir string) ([]string, error) {
+	files := projectTypeFiles(typ, repoName)
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		path := filepath.Join(targetDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, err
+		}
+		if err := writeGenerated(path, []byte(files[name])); err != nil {
+			return nil, err
+		}
+	}
+	return names, nil
+}
+
+// projectTypeFiles returns the skeleton files of a project type by path
+func projectTypeFiles(typ, repoName string) map[string]string {
+	switch typ {
+	case ProjectCLI:
+		return map[string]string{filepath.Join("cmd", repoName, "main.go"): cliMainFile(repoName)}
+	case ProjectWasm:
+		files := map[string]string{
+			"main_wasm.go": wasmMainFile,
+			"main.go":      wasmStubFile,
+			"index.html":   wasmIndexFile(repoName),
+		}
+		// wasm_exec.js must match the Go version that builds main.wasm
+		if js := wasmExecJS(); js != "" {
+			files["wasm_exec.js"] = js
+		}
+		return files
+	case ProjectWeb:
+		return map[string]string{"main.go": webMainFile(repoName)}
+	}
+	return map[string]string{repoName + ".go": handlerFile(repoName)}
+}
+
+// handlerFile is the library skeleton written by GenerateHandlerFile
+func handlerFile(repoName string) string {
+	structName := kebabToCamel(repoName)
+	return fmt.Sprintf(`package %s
+
+type %s struct {}
+
+func New() *%s {
+    return &%s{}
+}
+`, projectPackageName(repoName), structName, structName, structName)
+}
+
+func cliMainFile(repoName string) string {
+	return fmt.Sprintf(`package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	verbose := flag.Bool("v", false, "Verbose output")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [flags] [args]\n\nFlags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if err := run(flag.Args(), *verbose); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, verbose bool) error {
+	if verbose {
+		fmt.Println("args:", args)
+	}
+	return nil
+}
+`, repoName)
+}
+
+const wasmMainFile = `//go:build js && wasm
+
+package main
+
+import "syscall/js"
+
+func main() {
+	doc := js.Global().Get("document")
+	p := doc.Call("createElement", "p")
+	p.Set("textContent", "Hello from Go WebAssembly")
+	doc.Get("body").Call("appendChild", p)
+
+	// Keep the module alive for callbacks
+	select {}
+}
+`
+
+const wasmStubFile = `//go:build !(js && wasm)
+
+package main
+
+import "fmt"
+
+func main() {
+	fmt.Println("build with: GOOS=js GOARCH=wasm go build -o main.wasm")
+}
+`
+
+func wasmIndexFile(repoName string) string {
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head>
+	<meta charset="utf-8">
+	<title>%s</title>
+	<script src="wasm_exec.js"></script>
+	<script>
+		const go = new Go();
+		WebAssembly.instantiateStreaming(fetch("main.wasm"), go.importObject)
+			.then((result) => go.run(result.instance));
+	</script>
+</head>
+<body></body>
+</html>
+`, repoName)
+}
+
+// wasmExecJS returns the wasm_exec.js of the installed Go toolchain, or ""
+func wasmExecJS() string {
+	goroot, err := RunCommandSilent("go", "env", "GOROOT")
+	if err != nil {
+		return ""
+	}
+	for _, dir := range []string{"lib/wasm", "misc/wasm"} { // lib/wasm since Go 1.24
+		if data, err := os.ReadFile(filepath.Join(goroot, dir, "wasm_exec.js")); err == nil {
+			return string(data)
+		}
+	}
+	return ""
+}
+
+func webMainFile(repoName string) string {
+	return fmt.Sprintf(`package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "Listen address")
+	flag.Parse()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "%s")
+	})
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	log.Printf("listening on %%s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, mux))
+}
+`, repoName)
+}
@@ -0,0 +1,56 @@
+package devflow
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestGenerateProjectFiles checks that every project type builds
+func TestGenerateProjectFiles(t *testing.T) {
+	if c := strings.TrimSpace(string(startGoCache)); c != "" {
+		t.Setenv("GOCACHE", c)
+	}
+
+	cases := map[string][]string{
+		ProjectLibrary: {"my-app.go"},
+		ProjectCLI:     {filepath.Join("cmd", "my-app", "main.go")},
+		ProjectWasm:    {"index.html", "main.go", "main_wasm.go"},
+		ProjectWeb:     {"main.go"},
+	}
+	for typ, want := range cases {
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/my-app\n\ngo 1.22\n"), 0644)
+
+		names, err := GenerateProjectFiles(typ, "my-app", dir)
+		if err != nil {
+			t.Fatalf("%s: %v", typ, err)
+		}
+		got := strings.Join(names, ",")
+		for _, name := range want {
+			if !strings.Contains(got, name) {
+				t.Errorf("%s: files %v, missing %s", typ, names, name)
+			}
+		}
+
+		cmd := exec.Command("go", "vet", "./...")
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Errorf("%s: go vet failed: %v\n%s", typ, err, out)
+		}
+		if typ == ProjectWasm {
+			cmd := exec.Command("go", "vet", "./...")
+			cmd.Dir = dir
+			cmd.Env = append(os.Environ(), "GOOS=js", "GOARCH=wasm")
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Errorf("wasm: go vet (js/wasm) failed: %v\n%s", err, out)
+			}
+		}
+	}
+
+	if err := ValidateProjectType("desktop"); err == nil {
+		t.Error("expected error for unknown project type")
+	}
+}
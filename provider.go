@@ -0,0 +1,249 @@
+package devflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Supported repository providers
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+	ProviderGitea  = "gitea"
+)
+
+// providerPublicHosts are the SaaS hosts disabled in air-gapped mode
+var providerPublicHosts = map[string]bool{"gitlab.com": true, "gitea.com": true, "codeberg.org": true}
+
+// ProviderSettings selects the provider used by gonew. Settings come from
+// the provider.* keys of the global config (~/.config/devflow/config.yaml),
+// overridden by the -provider and -host flags:
+//
+//	provider:
+//	  name: gitlab               # github (default), gitlab or gitea
+//	  host: gitlab.example.com   # default: github.com, gitlab.com
+type ProviderSettings struct {
+	Name string
+	Host string
+}
+
+// LoadProviderSettings reads the provider settings from c
+func LoadProviderSettings(c *Config) ProviderSettings {
+	if c == nil {
+		c = NewConfig()
+	}
+	return ProviderSettings{
+		Name: strings.ToLower(c.String("provider.name", ProviderGitHub)),
+		Host: c.String("provider.host", ""),
+	}
+}
+
+// ModuleHost returns the host used in Go module paths of new projects
+func (s ProviderSettings) ModuleHost() string {
+	if s.Host != "" {
+		return s.Host
+	}
+	switch s.Name {
+	case ProviderGitLab:
+		return "gitlab.com"
+	case ProviderGitea:
+		return "gitea.com"
+	}
+	if host := os.Getenv("GH_HOST"); host != "" {
+		return host
+	}
+	return "github.com"
+}
+
+// NewRepoProvider creates the provider selected by s
+func NewRepoProvider(s ProviderSettings, logFn func(...any)) (RepoProvider, error) {
+	switch s.Name {
+	case "", ProviderGitHub:
+		return NewGitHub(logFn)
+	case ProviderGitLab:
+		return NewGitLab(s.ModuleHost(), logFn)
+	case ProviderGitea:
+		return NewGitea(s.ModuleHost(), logFn)
+	}
+	return nil, fmt.Errorf("unknown provider %q (want github, gitlab or gitea)", s.Name)
+}
+
+// providerHostCheck rejects public SaaS hosts in air-gapped mode
+func providerHostCheck(name, host string) error {
+	if AirGapEnabled() && providerPublicHosts[host] {
+		return fmt.Errorf("%s at %s disabled in air-gapped mode (set provider.host to a self-hosted instance)", name, host)
+	}
+	return nil
+}
+
+// Remote protocols of NewProjectOptions.RemoteProtocol (gonew.remote_protocol)
+const (
+	ProtocolAuto  = "auto"  // The protocol the provider CLI is set up with (gh config get git_protocol), else https
+	ProtocolHTTPS = "https" // https://<host>/<owner>/<name>.git
+	ProtocolSSH   = "ssh"   // git@<host>:<owner>/<name>.git
+)
+
+// ValidateRemoteProtocol checks protocol, empty meaning auto
+func ValidateRemoteProtocol(protocol string) error {
+	switch protocol {
+	case "", ProtocolAuto, ProtocolHTTPS, ProtocolSSH:
+		return nil
+	}
+	return fmt.Errorf("unknown remote protocol %q (want %s, %s or %s)", protocol, ProtocolAuto, ProtocolHTTPS, ProtocolSSH)
+}
+
+// resolveRemoteProtocol returns https or ssh for protocol, asking client
+// for auto when it is a GitProtocolReader
+func resolveRemoteProtocol(client any, protocol string) string {
+	if protocol == ProtocolHTTPS || protocol == ProtocolSSH {
+		return protocol
+	}
+	if r, ok := client.(GitProtocolReader); ok && r.GitProtocol() == ProtocolSSH {
+		return ProtocolSSH
+	}
+	return ProtocolHTTPS
+}
+
+// remoteRepoURL returns the clone URL for GitHub or any RepoProvider in
+// protocol: the HTTPS URL, or its ssh form
+func remoteRepoURL(client GitHubClient, owner, name, protocol string) string {
+	url := fmt.Sprintf("https://github.com/%s/%s.git", owner, name)
+	if p, ok := client.(RepoProvider); ok {
+		url = p.RepoURL(owner, name)
+	}
+	return protocolURL(url, resolveRemoteProtocol(client, protocol))
+}
+
+// protocolURL returns the git@host:owner/name.git form of an HTTPS remote
+// URL for ssh, else url unchanged
+func protocolURL(url, protocol string) string {
+	if protocol != ProtocolSSH || !strings.HasPrefix(url, "https://") {
+		return url
+	}
+	host, owner, name, ok := ParseRemoteURL(url)
+	if !ok {
+		return url
+	}
+	return fmt.Sprintf("git@%s:%s/%s.git", host, owner, name)
+}
+
+// providerLabel returns the display name of the provider for messages
+func providerLabel(client GitHubClient) string {
+	if p, ok := client.(RepoProvider); ok {
+		switch p.Name() {
+		case ProviderGitLab:
+			return "GitLab"
+		case ProviderGitea:
+			return "Gitea"
+		}
+	}
+	return "GitHub"
+}
+
+// isNetworkError checks if an error is likely a network error
+func isNetworkError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "dial tcp") ||
+		strings.Contains(msg, "connection refused") ||
+		strings.Contains(msg, "no such host") ||
+		strings.Contains(msg, "timeout")
+}
+
+// restClient calls the JSON API of a self-hosted provider with a token
+type restClient struct {
+	baseURL string // e.g. https://gitlab.example.com/api/v4
+	header  string // Auth header name
+	token   string // Auth header value
+	http    *http.Client
+}
+
+func newRESTClient(baseURL, header, token string) *restClient {
+	return &restClient{baseURL: strings.TrimRight(baseURL, "/"), header: header, token: token, http: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// do sends a request and decodes the JSON response into out (if not nil).
+// Returns the status code; statuses >= 400 are errors. In dry-run mode only
+// GET requests are sent.
+func (c *restClient) do(method, path string, body, out any) (int, error) {
+	if method != "GET" && DryRunActive() {
+		dryRunf("", "%s %s%s", method, c.baseURL, path)
+		return 0, nil
+	}
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return 0, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, c.baseURL+path, reader)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return c.send(req, out)
+}
+
+// upload posts file as the multipart "file" field and decodes the JSON
+// response into out
+func (c *restClient) upload(path, file string, out any) (int, error) {
+	if DryRunActive() {
+		dryRunf("", "POST %s%s (upload %s)", c.baseURL, path, file)
+		return 0, nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return 0, err
+	}
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	part, err := mw.CreateFormFile("file", filepath.Base(file))
+	if err != nil {
+		return 0, err
+	}
+	part.Write(data)
+	mw.Close()
+	req, err := http.NewRequest("POST", c.baseURL+path, &buf)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return c.send(req, out)
+}
+
+// send authenticates and sends req; statuses >= 400 are errors
+func (c *restClient) send(req *http.Request, out any) (int, error) {
+	method, path := req.Method, strings.TrimPrefix(req.URL.String(), c.baseURL)
+	req.Header.Set(c.header, c.token)
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 400 {
+		return resp.StatusCode, fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, strings.TrimSpace(string(data)))
+	}
+	if out != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, out); err != nil {
+			return resp.StatusCode, fmt.Errorf("%s %s: %w", method, path, err)
+		}
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,191 @@
+package devflow
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testProviderServer serves a minimal GitLab/Gitea API: alice is the user,
+// alice/exists exists, alice/empty has no commits and every POST is recorded
+func testProviderServer(t *testing.T, posts map[string]map[string]any) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/user":
+			json.NewEncoder(w).Encode(map[string]string{"username": "alice", "login": "alice"})
+		case r.Method == "GET" && r.URL.EscapedPath() == "/projects/alice%2Fexists",
+			r.Method == "GET" && r.URL.Path == "/repos/alice/exists":
+			w.Write([]byte(`{"id":1}`))
+		case r.Method == "GET" && r.URL.EscapedPath() == "/projects/alice%2Fempty",
+			r.Method == "GET" && r.URL.Path == "/repos/alice/empty":
+			w.Write([]byte(`{"id":2,"empty_repo":true,"empty":true}`))
+		case r.Method == "GET" && r.URL.Path == "/namespaces/team":
+			w.Write([]byte(`{"id":42}`))
+		case r.Method == "POST":
+			var body map[string]any
+			json.NewDecoder(r.Body).Decode(&body)
+			posts[r.URL.Path] = body
+			w.WriteHeader(http.StatusCreated)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestGitLabREST(t *testing.T) {
+	posts := map[string]map[string]any{}
+	srv := testProviderServer(t, posts)
+	gl := &GitLab{host: "gitlab.example.com", rest: newRESTClient(srv.URL, "PRIVATE-TOKEN", "t"), log: func(...any) {}}
+
+	if user, err := gl.GetCurrentUser(); err != nil || user != "alice" {
+		t.Fatalf("GetCurrentUser = %q, %v", user, err)
+	}
+	if ok, err := gl.RepoExists("alice", "exists"); err != nil || !ok {
+		t.Errorf("RepoExists(exists) = %v, %v", ok, err)
+	}
+	if ok, err := gl.RepoExists("alice", "missing"); err != nil || ok {
+		t.Errorf("RepoExists(missing) = %v, %v", ok, err)
+	}
+	if empty, err := gl.RepoIsEmpty("alice", "empty"); err != nil || !empty {
+		t.Errorf("RepoIsEmpty(empty) = %v, %v", empty, err)
+	}
+	if empty, err := gl.RepoIsEmpty("alice", "exists"); err != nil || empty {
+		t.Errorf("RepoIsEmpty(exists) = %v, %v", empty, err)
+	}
+
+	if err := gl.CreateRepo("team", "svc", "Service", "private"); err != nil {
+		t.Fatal(err)
+	}
+	body := posts["/projects"]
+	if body["namespace_id"] != float64(42) || body["visibility"] != "private" || body["path"] != "svc" {
+		t.Errorf("unexpected create body: %v", body)
+	}
+	if got := gl.RepoURL("team", "svc"); got != "https://gitlab.example.com/team/svc.git" {
+		t.Errorf("RepoURL = %s", got)
+	}
+}
+
+func TestGiteaREST(t *testing.T) {
+	posts := map[string]map[string]any{}
+	srv := testProviderServer(t, posts)
+	gt := &Gitea{host: "git.example.com", rest: newRESTClient(srv.URL, "Authorization", "token t"), log: func(...any) {}}
+
+	if ok, err := gt.RepoExists("alice", "exists"); err != nil || !ok {
+		t.Errorf("RepoExists(exists) = %v, %v", ok, err)
+	}
+	if ok, err := gt.RepoExists("alice", "missing"); err != nil || ok {
+		t.Errorf("RepoExists(missing) = %v, %v", ok, err)
+	}
+	if empty, err := gt.RepoIsEmpty("alice", "empty"); err != nil || !empty {
+		t.Errorf("RepoIsEmpty(empty) = %v, %v", empty, err)
+	}
+
+	if err := gt.CreateRepo("alice", "mine", "Mine", "public"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gt.CreateRepo("team", "shared", "Shared", "private"); err != nil {
+		t.Fatal(err)
+	}
+	if posts["/user/repos"]["name"] != "mine" || posts["/user/repos"]["private"] != false {
+		t.Errorf("unexpected user repo body: %v", posts["/user/repos"])
+	}
+	if posts["/orgs/team/repos"]["name"] != "shared" || posts["/orgs/team/repos"]["private"] != true {
+		t.Errorf("unexpected org repo body: %v", posts["/orgs/team/repos"])
+	}
+}
+
+func TestNewGiteaRequiresToken(t *testing.T) {
+	t.Setenv("GITEA_TOKEN", "")
+	if _, err := NewGitea("git.example.com", nil); err == nil {
+		t.Error("expected error without GITEA_TOKEN")
+	}
+}
+
+func TestProviderSettings(t *testing.T) {
+	t.Setenv("GH_HOST", "")
+	c := NewConfig()
+	s := LoadProviderSettings(c)
+	if s.Name != ProviderGitHub || s.ModuleHost() != "github.com" {
+		t.Errorf("default settings = %+v, host %s", s, s.ModuleHost())
+	}
+
+	c, err := ParseConfig("provider:\n  name: GitLab\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s = LoadProviderSettings(c)
+	if s.Name != ProviderGitLab || s.ModuleHost() != "gitlab.com" {
+		t.Errorf("gitlab settings = %+v, host %s", s, s.ModuleHost())
+	}
+	s.Host = "gitlab.example.com"
+	if s.ModuleHost() != "gitlab.example.com" {
+		t.Errorf("ModuleHost = %s", s.ModuleHost())
+	}
+
+	if _, err := NewRepoProvider(ProviderSettings{Name: "bitbucket"}, nil); err == nil {
+		t.Error("expected error for unknown provider")
+	}
+}
+
+func TestProviderAirGap(t *testing.T) {
+	t.Setenv("DEVFLOW_AIRGAP", "1")
+	t.Setenv("GITLAB_TOKEN", "t")
+	if _, err := NewGitLab("gitlab.com", nil); err == nil {
+		t.Error("expected gitlab.com to be rejected in air-gapped mode")
+	}
+	if _, err := NewGitLab("gitlab.corp.local", nil); err != nil {
+		t.Errorf("self-hosted GitLab rejected: %v", err)
+	}
+}
+
+func TestRemoteRepoURL(t *testing.T) {
+	t.Setenv("GH_HOST", "")
+	if got := remoteRepoURL(&GitHub{}, "o", "r", ProtocolHTTPS); got != "https://github.com/o/r.git" {
+		t.Errorf("remoteRepoURL(github) = %s", got)
+	}
+	gt := &Gitea{host: "git.example.com"}
+	if got := remoteRepoURL(gt, "o", "r", ProtocolAuto); got != "https://git.example.com/o/r.git" {
+		t.Errorf("remoteRepoURL(gitea) = %s", got)
+	}
+	if got := remoteRepoURL(&GitHub{}, "o", "r", ProtocolSSH); got != "git@github.com:o/r.git" {
+		t.Errorf("remoteRepoURL(github, ssh) = %s", got)
+	}
+	if got := remoteRepoURL(&GitLab{host: "gitlab.example.com"}, "group/sub", "r", ProtocolSSH); got != "git@gitlab.example.com:group/sub/r.git" {
+		t.Errorf("remoteRepoURL(gitlab, ssh) = %s", got)
+	}
+	if got := protocolURL("file:///tmp/r.git", ProtocolSSH); got != "file:///tmp/r.git" {
+		t.Errorf("expected local URLs unchanged, got %s", got)
+	}
+	if ValidateRemoteProtocol("git") == nil || ValidateRemoteProtocol("") != nil {
+		t.Error("unexpected remote protocol validation")
+	}
+	if providerLabel(gt) != "Gitea" || providerLabel(&GitHub{}) != "GitHub" {
+		t.Error("unexpected provider labels")
+	}
+}
+
+func TestGitHubRepoExistsErrors(t *testing.T) {
+	bin := t.TempDir()
+	os.WriteFile(filepath.Join(bin, "gh"), []byte(`#!/bin/sh
+case "$3" in
+o/missing) echo "GraphQL: Could not resolve to a Repository with the name 'o/missing'. (repository)" ;;
+*) echo "HTTP 401: Bad credentials (https://api.github.com/graphql)" ;;
+esac
+exit 1
+`), 0755)
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	if ok, err := (&GitHub{}).RepoExists("o", "missing"); ok || err != nil {
+		t.Errorf("RepoExists(missing) = %v, %v", ok, err)
+	}
+	if ok, err := (&GitHub{}).RepoExists("o", "r"); ok || err == nil || !strings.Contains(err.Error(), "Bad credentials") {
+		t.Errorf("an auth failure should be an error, got %v, %v", ok, err)
+	}
+}
@@ -0,0 +1,282 @@
+package devflow
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// PullRequest is a pull request to open with a PullRequester
+type PullRequest struct {
+	Base  string // Branch the changes are merged into
+	Head  string // Branch with the changes
+	Title string
+	Body  string
+	Draft bool
+}
+
+// PullRequestOptions configures Go.PushPR
+type PullRequestOptions struct {
+	Branch    string // Head branch (default: the current branch, or one named after the message when on Base)
+	Base      string // Branch the pull request targets (default: the default branch)
+	Title     string // Default: the subject of the only commit, else the message
+	Body      string // Default: the body of the only commit, else the list of commits
+	Draft     bool
+	AutoMerge string // Enable auto-merge with squash, merge or rebase (default: pr.auto_merge config, else off)
+	// WaitChecks, when set, waits up to this long for the checks of the pull
+	// request and merges it with AutoMerge (squash when off) instead of
+	// enabling auto-merge, for repositories that do not allow it
+	WaitChecks time.Duration
+	SkipTests  bool
+	SkipRace   bool
+}
+
+// branchSlugRe matches the runs of characters a branch name slug drops
+var branchSlugRe = regexp.MustCompile(`[^a-z0-9]+`)
+
+// maxBranchSlug bounds the part of a generated branch name after the type
+const maxBranchSlug = 40
+
+// SetPullRequester sets the client PushPR opens pull requests with
+// (default: a GitHub client created on the first pull request)
+func (g *Go) SetPullRequester(p PullRequester) {
+	g.pullRequester = p
+}
+
+// PushPR is Push for protected default branches: after the same checks
+// and tests, the work is committed on a topic branch and pushed without a
+// tag, and a pull request to the default branch is opened with a title
+// and body taken from the commits. With opts.AutoMerge the pull request
+// merges by itself once the required checks pass; with opts.WaitChecks
+// PushPR waits for the checks and merges it. The tag, the release
+// and the dependents are left to the push that follows the merge.
+func (g *Go) PushPR(message string, opts PullRequestOptions) (_ string, err error) {
+	if err := ValidateCommitMessage(message); err != nil {
+		return "", err
+	}
+	message = FormatCommitMessage(message)
+	if g.dryRun {
+		defer startDryRun()()
+	}
+	defer trackMetric("gopush-pr")(&err)
+	defer traceSpan("gopush-pr")(&err)
+	defer g.beginRun()()
+
+	bp, ok := g.git.(BranchPusher)
+	if !ok {
+		return "", fmt.Errorf("pull requests need a git client that pushes branches")
+	}
+	if opts.AutoMerge == "" {
+		opts.AutoMerge = g.Config().String("pr.auto_merge", "off")
+	}
+	if err := validateAutoMerge(opts.AutoMerge); err != nil {
+		return "", err
+	}
+	base := opts.Base
+	if base == "" {
+		if base, err = bp.DefaultBranch(); err != nil {
+			return "", err
+		}
+	}
+	branch := opts.Branch
+	if branch == "" {
+		if current, err := bp.CurrentBranch(); err == nil && current != base {
+			branch = current
+		} else {
+			branch = PullRequestBranch(message)
+		}
+	}
+	if branch == base {
+		return "", fmt.Errorf("the pull request branch cannot be its base %s", base)
+	}
+
+	hookCtx := HookContext{Message: message}
+	summary, err := g.checkBeforePush(hookCtx, opts.SkipTests, opts.SkipRace)
+	if err != nil {
+		return "", err
+	}
+
+	tracePhase("push")
+	if h, ok := g.git.(PushHookSetter); ok {
+		h.SetPushHook(func(stage string) error { return g.runPushHooks(stage, hookCtx) })
+		defer h.SetPushHook(nil)
+	}
+	pushSummary, err := bp.PushBranch(message, branch)
+	if err != nil {
+		return "", fmt.Errorf("push workflow failed: %w", err)
+	}
+	summary = append(summary, pushSummary)
+
+	tracePhase("pull request")
+	pr := PullRequest{Base: base, Head: branch, Title: opts.Title, Body: opts.Body, Draft: opts.Draft}
+	if pr.Title == "" || pr.Body == "" {
+		commits, err := bp.CommitsAhead(base)
+		if err != nil {
+			return "", err
+		}
+		title, body := pullRequestText(message, commits)
+		if pr.Title == "" {
+			pr.Title = title
+		}
+		if pr.Body == "" {
+			pr.Body = body
+		}
+	}
+	if DryRunActive() {
+		dryRunf(g.rootDir, "gh pr create --base %s --head %s --title %q", base, branch, pr.Title)
+		switch {
+		case opts.WaitChecks > 0:
+			dryRunf(g.rootDir, "gh pr checks (up to %s), then gh pr merge --%s --delete-branch", opts.WaitChecks, mergeMethod(opts.AutoMerge))
+		case opts.AutoMerge != "off":
+			dryRunf(g.rootDir, "gh pr merge --auto --%s", opts.AutoMerge)
+		}
+		return strings.Join(summary, ", "), nil
+	}
+	if g.pullRequester == nil {
+		gh, err := NewGitHub(g.logger.Info)
+		if err != nil {
+			return "", err
+		}
+		g.pullRequester = gh
+	}
+	url, err := g.pullRequester.CreatePullRequest(pr)
+	if err != nil {
+		return "", err
+	}
+	summary = append(summary, "✅ Pull request: "+url)
+
+	if opts.WaitChecks > 0 {
+		merger, ok := g.pullRequester.(PullRequestMerger)
+		if !ok {
+			return "", fmt.Errorf("pull request %s opened, but its client cannot wait for checks and merge", url)
+		}
+		tracePhase("checks")
+		if _, err := merger.WaitForPRChecks(url, opts.WaitChecks); err != nil {
+			return "", fmt.Errorf("pull request %s not merged: %w", url, err)
+		}
+		method := mergeMethod(opts.AutoMerge)
+		if err := merger.MergePR(url, method, true); err != nil {
+			return "", fmt.Errorf("pull request %s not merged: %w", url, err)
+		}
+		summary = append(summary, "✅ Checks passed, merged ("+method+")")
+	} else if opts.AutoMerge != "off" {
+		if err := g.pullRequester.EnableAutoMerge(url, opts.AutoMerge); err != nil {
+			// The pull request is open; it can still be merged by hand
+			g.warn("auto-merge not enabled", err)
+		} else {
+			summary = append(summary, "✅ Auto-merge ("+opts.AutoMerge+")")
+		}
+	}
+	return strings.Join(summary, ", "), nil
+}
+
+// validateAutoMerge checks an auto-merge method, "off" disabling it
+func validateAutoMerge(method string) error {
+	switch method {
+	case "off", MergeSquash, MergeCommit, MergeRebase:
+		return nil
+	}
+	return fmt.Errorf("unknown auto-merge method %q (want %s, %s, %s or off)", method, MergeSquash, MergeCommit, MergeRebase)
+}
+
+// mergeMethod returns the merge method of an auto-merge setting, squash
+// when off
+func mergeMethod(autoMerge string) string {
+	if autoMerge == "off" {
+		return MergeSquash
+	}
+	return autoMerge
+}
+
+// PullRequestBranch returns the branch PushPR names after a commit
+// message: "feat(api): Add login" gets feat/add-login, a message without
+// a conventional type update/<slug>
+func PullRequestBranch(message string) string {
+	subject := strings.SplitN(strings.TrimSpace(message), "\n", 2)[0]
+	kind, text := "update", subject
+	if m := conventionalRe.FindStringSubmatch(subject); m != nil {
+		kind, text = strings.ToLower(m[1]), m[4]
+	}
+	slug := strings.Trim(branchSlugRe.ReplaceAllString(strings.ToLower(text), "-"), "-")
+	if len(slug) > maxBranchSlug {
+		slug = slug[:maxBranchSlug]
+		if i := strings.LastIndex(slug, "-"); i > maxBranchSlug/2 {
+			slug = slug[:i]
+		}
+		slug = strings.TrimRight(slug, "-")
+	}
+	if slug == "" {
+		slug = "changes"
+	}
+	return kind + "/" + slug
+}
+
+// pullRequestText returns the title and body of a pull request with the
+// commits of its branch (oldest first): the subject and body of a single
+// commit, else message and the list of commit subjects
+func pullRequestText(message string, commits []string) (title, body string) {
+	if len(commits) == 1 {
+		subject, rest, _ := strings.Cut(commits[0], "\n")
+		return subject, strings.TrimSpace(rest)
+	}
+	title = strings.SplitN(message, "\n", 2)[0]
+	var lines []string
+	for _, c := range commits {
+		lines = append(lines, "- "+strings.SplitN(c, "\n", 2)[0])
+	}
+	return title, strings.Join(lines, "\n")
+}
+
+// PushBranch commits the changes on branch, creating it from HEAD when it
+// does not exist, and pushes it without a tag. The other push options
+// (exclude, split, squash) apply as in Push.
+func (g *Git) PushBranch(message, branch string) (string, error) {
+	prev := g.pushOpts
+	defer func() { g.pushOpts = prev }()
+	g.pushOpts.Branch, g.pushOpts.NoTag = branch, true
+	return g.Push(message, "")
+}
+
+// CommitsAhead returns the messages of the commits of HEAD that are not on
+// base (its origin branch when fetched), oldest first
+func (g *Git) CommitsAhead(base string) ([]string, error) {
+	ref := base
+	if _, err := RunCommandSilent("git", "rev-parse", "--verify", "--quiet", "refs/remotes/origin/"+base); err == nil {
+		ref = "origin/" + base
+	}
+	commits, err := g.Log(LogOptions{Range: ref + "..HEAD", NoMerges: true, Reverse: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the commits ahead of %s: %w", base, err)
+	}
+	var messages []string
+	for _, c := range commits {
+		messages = append(messages, c.Message())
+	}
+	return messages, nil
+}
+
+// CreatePullRequest opens pr in the repository of the current directory
+// and returns its URL
+func (gh *GitHub) CreatePullRequest(pr PullRequest) (string, error) {
+	args := []string{"pr", "create", "--base", pr.Base, "--head", pr.Head, "--title", pr.Title, "--body", pr.Body}
+	if pr.Draft {
+		args = append(args, "--draft")
+	}
+	out, err := RunCommand("gh", args...)
+	if err != nil {
+		return "", fmt.Errorf("gh pr create failed: %s", firstLine(out))
+	}
+	lines := strings.Split(out, "\n")
+	return strings.TrimSpace(lines[len(lines)-1]), nil
+}
+
+// EnableAutoMerge merges the pull request at url with method (squash,
+// merge or rebase) once its required checks pass. The repository must
+// allow auto-merge.
+func (gh *GitHub) EnableAutoMerge(url, method string) error {
+	if out, err := RunCommand("gh", "pr", "merge", url, "--auto", "--"+method); err != nil {
+		return fmt.Errorf("gh pr merge --auto failed: %s", firstLine(out))
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package devflow
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testPullRequester records the pull requests it is asked to open
+type testPullRequester struct {
+	prs       []PullRequest
+	autoMerge string
+	mergeErr  error
+	checksErr error
+	merged    string
+}
+
+func (r *testPullRequester) CreatePullRequest(pr PullRequest) (string, error) {
+	r.prs = append(r.prs, pr)
+	return "https://github.com/o/r/pull/1", nil
+}
+
+func (r *testPullRequester) EnableAutoMerge(url, method string) error {
+	r.autoMerge = method
+	return r.mergeErr
+}
+
+func (r *testPullRequester) WaitForPRChecks(pr string, timeout time.Duration) ([]PRCheck, error) {
+	return nil, r.checksErr
+}
+
+func (r *testPullRequester) MergePR(pr, method string, deleteBranch bool) error {
+	r.merged = method
+	return nil
+}
+
+func TestPullRequestBranch(t *testing.T) {
+	for message, want := range map[string]string{
+		"feat(api): Add login":     "feat/add-login",
+		"fix!: Crash on nil input": "fix/crash-on-nil-input",
+		"Update the docs":          "update/update-the-docs",
+		"docs: ¡¿?!":               "docs/changes",
+		"feat: a very long description of the change that goes on": "feat/a-very-long-description-of-the-change",
+	} {
+		if got := PullRequestBranch(message); got != want {
+			t.Errorf("PullRequestBranch(%q) = %q, want %q", message, got, want)
+		}
+	}
+}
+
+func TestPullRequestText(t *testing.T) {
+	title, body := pullRequestText("fix: x", []string{"feat: Add login\n\nWith tokens."})
+	if title != "feat: Add login" || body != "With tokens." {
+		t.Errorf("single commit: %q, %q", title, body)
+	}
+	title, body = pullRequestText("fix: Typo", []string{"feat: Add login\n\nWith tokens.", "fix: Typo"})
+	if title != "fix: Typo" || body != "- feat: Add login\n- fix: Typo" {
+		t.Errorf("several commits: %q, %q", title, body)
+	}
+}
+
+func TestGoPushPR(t *testing.T) {
+	testResumeEnv(t)
+	tmp := t.TempDir()
+	remote := filepath.Join(tmp, "remote.git")
+	if err := exec.Command("git", "init", "--bare", "--initial-branch=main", remote).Run(); err != nil {
+		t.Skip("git does not support --initial-branch")
+	}
+	dir, cleanup := testCreateGoModule("github.com/o/r")
+	defer cleanup()
+	defer testChdir(t, dir)()
+	for _, args := range [][]string{{"init", "-q", "--initial-branch=main"}, {"add", "."}, {"commit", "-q", "-m", "init"}, {"remote", "add", "origin", remote}, {"push", "-q", "origin", "main"}} {
+		if out, err := RunCommand("git", args...); err != nil {
+			t.Fatal(out, err)
+		}
+	}
+
+	git, _ := NewGit()
+	g, _ := NewGo(git)
+	r := &testPullRequester{mergeErr: errors.New("auto-merge is not allowed")}
+	g.SetPullRequester(r)
+	os.WriteFile("login.go", []byte("package main\n"), 0644)
+	summary, err := g.PushPR("feat: Add login", PullRequestOptions{SkipTests: true, AutoMerge: MergeSquash})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(summary, "✅ Branch: feat/add-login (new)") || !strings.Contains(summary, "✅ Pull request: https://github.com/o/r/pull/1") {
+		t.Errorf("unexpected summary %q", summary)
+	}
+	if len(r.prs) != 1 || r.prs[0] != (PullRequest{Base: "main", Head: "feat/add-login", Title: "feat: Add login"}) {
+		t.Errorf("unexpected pull requests %+v", r.prs)
+	}
+	if out, err := RunCommand("git", "--git-dir", remote, "rev-parse", "--verify", "refs/heads/feat/add-login"); err != nil {
+		t.Errorf("branch not pushed: %s", out)
+	}
+	if tags, _ := RunCommand("git", "tag"); tags != "" {
+		t.Errorf("expected no tag, got %q", tags)
+	}
+	if r.autoMerge != MergeSquash || len(g.Warnings()) != 1 || g.Warnings()[0].Step != "auto-merge not enabled" {
+		t.Errorf("expected the auto-merge failure as a warning, got %q, %v", r.autoMerge, g.Warnings())
+	}
+
+	// On the topic branch a second push extends the same pull request branch
+	os.WriteFile("logout.go", []byte("package main\n"), 0644)
+	if _, err := g.PushPR("feat: Add logout", PullRequestOptions{SkipTests: true, AutoMerge: "off"}); err != nil {
+		t.Fatal(err)
+	}
+	if pr := r.prs[1]; pr.Head != "feat/add-login" || pr.Title != "feat: Add logout" || pr.Body != "- feat: Add login\n- feat: Add logout" {
+		t.Errorf("unexpected second pull request %+v", pr)
+	}
+
+	// WaitChecks merges once the checks pass, and not when they fail
+	os.WriteFile("session.go", []byte("package main\n"), 0644)
+	summary, err = g.PushPR("feat: Add sessions", PullRequestOptions{SkipTests: true, AutoMerge: "off", WaitChecks: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.merged != MergeSquash || !strings.Contains(summary, "✅ Checks passed, merged (squash)") {
+		t.Errorf("expected a squash merge after the checks, got %q, %q", r.merged, summary)
+	}
+	r.merged, r.checksErr = "", errors.New("checks failed: test")
+	os.WriteFile("token.go", []byte("package main\n"), 0644)
+	if _, err := g.PushPR("feat: Add tokens", PullRequestOptions{SkipTests: true, AutoMerge: MergeRebase, WaitChecks: time.Minute}); err == nil || !strings.Contains(err.Error(), "checks failed: test") || r.merged != "" {
+		t.Errorf("expected failed checks to block the merge, got %v, %q", err, r.merged)
+	}
+
+	if _, err := g.PushPR("fix: x", PullRequestOptions{SkipTests: true, Branch: "main"}); err == nil {
+		t.Error("expected an error for a pull request from its base")
+	}
+	if _, err := g.PushPR("fix: x", PullRequestOptions{SkipTests: true, AutoMerge: "fast-forward"}); err == nil {
+		t.Error("expected an unknown auto-merge method error")
+	}
+}
@@ -0,0 +1,103 @@
+package devflow
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Stages of the push hooks, configured under hooks.<stage> in .devflow.yaml
+const (
+	HookBeforeTest   = "before_test"   // Before the tests of gopush
+	HookBeforeCommit = "before_commit" // Before the changes are staged and committed
+	HookBeforePush   = "before_push"   // After the commit and tag, before they are pushed
+	HookAfterTag     = "after_tag"     // After the new tag is pushed
+)
+
+// HookStages are the push hook stages in the order they run
+var HookStages = []string{HookBeforeTest, HookBeforeCommit, HookBeforePush, HookAfterTag}
+
+// PushHook is one named step of a stage. Run is a shell command, or
+// "@name" for a function registered with RegisterHook.
+type PushHook struct {
+	Name string
+	Run  string
+}
+
+// HookContext is what a registered hook function gets
+type HookContext struct {
+	Stage   string
+	Step    string
+	Dir     string // Module root
+	Message string // Commit message
+	Tag     string // New tag, set for after_tag
+}
+
+// HookFunc is a push hook step written in Go
+type HookFunc func(HookContext) error
+
+// PushHookSetter is implemented by git clients that run the before_commit
+// and before_push stages of Go.Push inside their push workflow
+type PushHookSetter interface {
+	SetPushHook(fn func(stage string) error)
+}
+
+// LoadPushHooks returns the steps of stage in file order, e.g.
+//
+//	hooks:
+//	  before_test:
+//	    generate: go generate ./...
+//	    schema: "@schema"
+func LoadPushHooks(cfg *Config, stage string) []PushHook {
+	var hooks []PushHook
+	for _, name := range cfg.Keys("hooks." + stage) {
+		hooks = append(hooks, PushHook{Name: name, Run: cfg.String("hooks."+stage+"."+name, "")})
+	}
+	return hooks
+}
+
+// RegisterHook makes fn the step of every hook configured as "@name"
+func (g *Go) RegisterHook(name string, fn HookFunc) {
+	if g.hookFuncs == nil {
+		g.hookFuncs = make(map[string]HookFunc)
+	}
+	g.hookFuncs[name] = fn
+}
+
+// runPushHooks runs the steps of stage in the module root. The first
+// failing step aborts the stage with its name. Shell steps get
+// DEVFLOW_HOOK_STAGE, DEVFLOW_COMMIT_MESSAGE and DEVFLOW_TAG. In dry-run
+// mode the steps are printed instead.
+func (g *Go) runPushHooks(stage string, ctx HookContext) error {
+	hooks := LoadPushHooks(g.Config(), stage)
+	ctx.Stage = stage
+	ctx.Dir = g.rootDir
+	env := []string{"DEVFLOW_HOOK_STAGE=" + stage, "DEVFLOW_COMMIT_MESSAGE=" + ctx.Message, "DEVFLOW_TAG=" + ctx.Tag}
+	for _, hook := range hooks {
+		if strings.TrimSpace(hook.Run) == "" {
+			return fmt.Errorf("%s hook %q has no command", stage, hook.Name)
+		}
+		g.logger.Info("Running", stage, "hook:", hook.Name)
+		if name, ok := strings.CutPrefix(hook.Run, "@"); ok {
+			fn := g.hookFuncs[name]
+			if fn == nil {
+				return fmt.Errorf("%s hook %q: no function registered as %q", stage, hook.Name, name)
+			}
+			if DryRunActive() {
+				dryRunf(g.rootDir, "hook %s (%s)", hook.Name, hook.Run)
+				continue
+			}
+			ctx.Step = hook.Name
+			if err := fn(ctx); err != nil {
+				return fmt.Errorf("%s hook %q failed: %w", stage, hook.Name, err)
+			}
+			continue
+		}
+		if out, err := RunShellCommandInDir(g.rootDir, hook.Run, env...); err != nil {
+			if line := firstLine(out); line != "" {
+				return fmt.Errorf("%s hook %q failed: %s", stage, hook.Name, line)
+			}
+			return fmt.Errorf("%s hook %q failed: %w", stage, hook.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package devflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGoPushHooks(t *testing.T) {
+	defer testPushedRepo(t)()
+	os.WriteFile("go.mod", []byte("module example.com/hooks\n\ngo 1.20\n"), 0644)
+	stages := filepath.Join(t.TempDir(), "stages.log")
+	os.WriteFile(".devflow.yaml", []byte(`hooks:
+  before_test:
+    generate: echo generated > gen.txt
+  before_commit:
+    stamp: "@stamp"
+  before_push:
+    log: echo "$DEVFLOW_HOOK_STAGE $DEVFLOW_COMMIT_MESSAGE" >> `+stages+`
+  after_tag:
+    log: echo "$DEVFLOW_HOOK_STAGE $DEVFLOW_TAG" >> `+stages+`
+`), 0644)
+
+	git, _ := NewGit()
+	g, _ := NewGo(git)
+	var stamped HookContext
+	g.RegisterHook("stamp", func(ctx HookContext) error {
+		stamped = ctx
+		return os.WriteFile("stamp.txt", []byte(ctx.Stage), 0644)
+	})
+	if _, err := g.Push("feat: hooks", "v0.1.0", true, true, true, true, ""); err != nil {
+		t.Fatal(err)
+	}
+	if stamped.Stage != HookBeforeCommit || stamped.Step != "stamp" || stamped.Message != "feat: hooks" {
+		t.Errorf("unexpected hook context %+v", stamped)
+	}
+	if files, _ := RunCommand("git", "ls-files"); !strings.Contains(files, "gen.txt") || !strings.Contains(files, "stamp.txt") {
+		t.Errorf("hook files should be committed, got %q", files)
+	}
+	if log, _ := os.ReadFile(stages); string(log) != "before_push feat: hooks\nafter_tag v0.1.0\n" {
+		t.Errorf("unexpected stages log %q", log)
+	}
+
+	// The first failing step stops the push with its name
+	os.WriteFile(".devflow.yaml", []byte("hooks:\n  before_push:\n    smoke: echo smoke failed && exit 3\n    never: touch never.txt\n"), 0644)
+	g, _ = NewGo(git)
+	_, err := g.Push("fix: smoke", "", true, true, true, true, "")
+	if err == nil || !strings.Contains(err.Error(), `before_push hook "smoke" failed: smoke failed`) {
+		t.Errorf("expected the smoke step error, got %v", err)
+	}
+	if _, err := os.Stat("never.txt"); !os.IsNotExist(err) {
+		t.Error("steps after the failing one should not run")
+	}
+
+	// After the tag is pushed a failing step is only a warning
+	os.WriteFile(".devflow.yaml", []byte("hooks:\n  after_tag:\n    announce: echo offline && exit 1\n"), 0644)
+	g, _ = NewGo(git)
+	if _, err := g.Push("fix: announce", "v0.2.0", true, true, true, true, ""); err != nil {
+		t.Errorf("a failing after_tag hook should not fail the push, got %v", err)
+	}
+	if w := g.Warnings(); len(w) != 1 || !strings.Contains(w[0].String(), `after_tag hook "announce" failed: offline`) {
+		t.Errorf("expected the after_tag warning, got %v", w)
+	}
+
+	os.WriteFile(".devflow.yaml", []byte("hooks:\n  before_test:\n    missing: \"@missing\"\n"), 0644)
+	g, _ = NewGo(git)
+	if _, err := g.Push("fix: missing", "", true, true, true, true, ""); err == nil || !strings.Contains(err.Error(), "no function registered") {
+		t.Errorf("expected unregistered function error, got %v", err)
+	}
+}
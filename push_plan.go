@@ -0,0 +1,197 @@
+package devflow
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// PushPlan is what a push would do, with every computed value resolved.
+// Building it changes nothing in the repository.
+type PushPlan struct {
+	Branch     string
+	Files      []ChangedFile // Changes that would be committed
+	Excluded   []string      // Changes left out by PushOptions.Exclude
+	Message    string        // Formatted commit message
+	CommitMode string        // "", "amend HEAD", "squash N commits" or "split by group"
+	LatestTag  string
+	Tag        string   // Tag that would be created
+	TagNotes   string   // Annotated tag message body (see ReleaseNotes), empty for a lightweight tag
+	Tests      string   // Test command, empty when skipped
+	Module     string   // Module path (Go projects)
+	Dependents []string // Module dirs that would be updated to Module@Tag
+}
+
+// PushPlanner is implemented by git clients that can preview Push
+type PushPlanner interface {
+	Plan(message, tag string) (PushPlan, error)
+}
+
+// Plan resolves what Push(message, tag) would commit, tag and push
+func (g *Git) Plan(message, tag string) (PushPlan, error) {
+	if err := ValidateCommitMessage(message); err != nil {
+		return PushPlan{}, err
+	}
+	plan := PushPlan{Message: FormatCommitMessage(message)}
+	plan.Branch, _ = g.CurrentBranch()
+	if g.pushOpts.Branch != "" {
+		plan.Branch = g.pushOpts.Branch
+	}
+
+	files, err := g.ChangedFiles()
+	if err != nil {
+		return PushPlan{}, err
+	}
+	for _, f := range files {
+		if pathExcluded(f.Path, g.pushOpts.Exclude) {
+			plan.Excluded = append(plan.Excluded, f.Path)
+		} else {
+			plan.Files = append(plan.Files, f)
+		}
+	}
+
+	switch {
+	case g.pushOpts.Split:
+		plan.CommitMode = "split by group"
+	case g.pushOpts.Squash > 0:
+		plan.CommitMode = fmt.Sprintf("squash %d commits", g.pushOpts.Squash)
+	case g.pushOpts.Amend:
+		if ok, _ := g.canAmend(); ok {
+			plan.CommitMode = "amend HEAD"
+		}
+	}
+
+	// Same tag resolution as Push, without creating anything
+	plan.LatestTag, _ = g.GetLatestTag()
+	plan.Tag = tag
+	if plan.Tag == "" {
+		if plan.Tag, err = g.nextTag(plan.Message); err != nil {
+			return PushPlan{}, fmt.Errorf("failed to generate tag: %w", err)
+		}
+	}
+	if plan.LatestTag != "" && CompareVersions(plan.Tag, plan.LatestTag) <= 0 {
+		return PushPlan{}, fmt.Errorf("tag %s is not greater than latest tag %s", plan.Tag, plan.LatestTag)
+	}
+	if plan.Tag, err = g.nextFreeTag(plan.Tag); err != nil {
+		return PushPlan{}, err
+	}
+	if plan.TagNotes, err = g.ReleaseNotes(plan.Tag); err != nil {
+		return PushPlan{}, err
+	}
+	return plan, nil
+}
+
+// PushPlan resolves what Push would do with the same arguments: tests,
+// commit, next tag, release and the dependents that would be updated
+func (g *Go) PushPlan(message, tag string, skipTests, skipRace, skipDependents bool, searchPath string) (PushPlan, error) {
+	var plan PushPlan
+	if planner, ok := g.git.(PushPlanner); ok {
+		p, err := planner.Plan(message, tag)
+		if err != nil {
+			return PushPlan{}, err
+		}
+		plan = p
+	} else {
+		if err := ValidateCommitMessage(message); err != nil {
+			return PushPlan{}, err
+		}
+		plan.Message = FormatCommitMessage(message)
+		plan.LatestTag, _ = g.git.GetLatestTag()
+		plan.Tag = tag
+	}
+
+	if !skipTests {
+		plan.Tests = "gotest"
+		if skipRace {
+			plan.Tests += " -race=off"
+		}
+	}
+
+	modulePath, err := g.getModulePath()
+	if err != nil {
+		return plan, nil
+	}
+	plan.Module = modulePath
+
+	if !skipDependents && plan.Tag != "" {
+		if searchPath == "" {
+			searchPath = ".."
+		}
+		if plan.Dependents, err = g.findDependentModules(modulePath, searchPath); err != nil {
+			return PushPlan{}, fmt.Errorf("failed to scan dependents: %w", err)
+		}
+	}
+	return plan, nil
+}
+
+// String renders the plan as ordered steps
+func (p PushPlan) String() string {
+	var b strings.Builder
+	branch := p.Branch
+	if branch == "" {
+		branch = "(detached)"
+	}
+	fmt.Fprintf(&b, "Push plan for %s:\n", branch)
+
+	n := 0
+	step := func(format string, args ...any) {
+		n++
+		fmt.Fprintf(&b, "  %d. %s\n", n, fmt.Sprintf(format, args...))
+	}
+
+	if p.Tests != "" {
+		step("Run %s", p.Tests)
+	}
+
+	mode := ""
+	if p.CommitMode != "" {
+		mode = " (" + p.CommitMode + ")"
+	}
+	if len(p.Files) == 0 {
+		step("Nothing to commit%s", mode)
+	} else {
+		step("Commit %d file(s)%s: %q", len(p.Files), mode, p.Message)
+		for _, f := range p.Files {
+			fmt.Fprintf(&b, "       %s %s\n", f.Status, f.Path)
+		}
+	}
+	if len(p.Excluded) > 0 {
+		fmt.Fprintf(&b, "     Excluded: %s\n", strings.Join(p.Excluded, ", "))
+	}
+
+	if p.Tag != "" {
+		latest := "no previous tag"
+		if p.LatestTag != "" {
+			latest = "latest " + p.LatestTag
+		}
+		step("Tag %s (%s)", p.Tag, latest)
+		if p.TagNotes != "" {
+			fmt.Fprintf(&b, "       annotated with %d line(s) of release notes\n", strings.Count(p.TagNotes, "\n")+1)
+		}
+		step("Push %s and %s to origin", branch, p.Tag)
+	} else {
+		step("Tag and push (next tag decided by the git client)")
+	}
+
+	if p.Module != "" && p.Tag != "" {
+		step("Release %s@%s", p.Module, p.Tag)
+		if len(p.Dependents) > 0 {
+			step("Update %d dependent(s) to %s:", len(p.Dependents), p.Tag)
+			for _, dir := range p.Dependents {
+				fmt.Fprintf(&b, "       %s\n", filepath.Clean(dir))
+			}
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// pathExcluded reports whether path is one of excluded or inside one of them
+func pathExcluded(path string, excluded []string) bool {
+	for _, e := range excluded {
+		e = strings.TrimSuffix(filepath.ToSlash(e), "/")
+		if path == e || strings.HasPrefix(path, e+"/") {
+			return true
+		}
+	}
+	return false
+}
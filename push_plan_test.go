@@ -0,0 +1,63 @@
+package devflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGoPushPlan(t *testing.T) {
+	defer testPushedRepo(t)()
+	os.WriteFile("go.mod", []byte("module example.com/lib\n\ngo 1.20\n"), 0644)
+	os.WriteFile("lib.go", []byte("package lib\n"), 0644)
+	os.WriteFile("notes.txt", []byte("local"), 0644)
+
+	deps := t.TempDir()
+	os.MkdirAll(filepath.Join(deps, "app"), 0755)
+	os.WriteFile(filepath.Join(deps, "app", "go.mod"), []byte("module example.com/app\n\nrequire example.com/lib v0.0.1\n"), 0644)
+
+	git, _ := NewGit()
+	git.SetPushOptions(PushOptions{Exclude: []string{"notes.txt"}})
+	g, _ := NewGo(git)
+
+	head, _ := RunCommandSilent("git", "rev-parse", "HEAD")
+	plan, err := g.PushPlan("feat: add lib", "", false, true, false, deps)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if plan.Tag != "v0.1.0" || plan.LatestTag != "v0.0.1" {
+		t.Errorf("tag = %s (latest %s), want v0.1.0", plan.Tag, plan.LatestTag)
+	}
+	if len(plan.Files) != 2 || len(plan.Excluded) != 1 || plan.Excluded[0] != "notes.txt" {
+		t.Errorf("files = %+v, excluded = %v", plan.Files, plan.Excluded)
+	}
+	if plan.Module != "example.com/lib" || len(plan.Dependents) != 1 || filepath.Base(plan.Dependents[0]) != "app" {
+		t.Errorf("module = %s, dependents = %v", plan.Module, plan.Dependents)
+	}
+
+	out := plan.String()
+	for _, want := range []string{"1. Run gotest -race=off", `Commit 2 file(s): "feat: add lib"`, "Excluded: notes.txt",
+		"Tag v0.1.0 (latest v0.0.1)", "Release example.com/lib@v0.1.0", "Update 1 dependent(s) to v0.1.0"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("plan missing %q:\n%s", want, out)
+		}
+	}
+
+	// Nothing changed
+	if after, _ := RunCommandSilent("git", "rev-parse", "HEAD"); after != head {
+		t.Error("PushPlan created a commit")
+	}
+	if exists, _ := git.TagExists("v0.1.0"); exists {
+		t.Error("PushPlan created a tag")
+	}
+}
+
+func TestGitPlanRejectsOldTag(t *testing.T) {
+	defer testPushedRepo(t)()
+	git, _ := NewGit()
+	if _, err := git.Plan("fix: x", "v0.0.1"); err == nil {
+		t.Error("expected error for a tag not greater than the latest")
+	}
+}
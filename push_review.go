@@ -0,0 +1,156 @@
+package devflow
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ChangedFile is a working tree change as reported by git status
+type ChangedFile struct {
+	Path    string // Path relative to the repository root
+	Status  string // Two-letter porcelain status (e.g., " M", "??")
+	Added   int    // Lines added (from numstat, 0 for new/binary files)
+	Removed int    // Lines removed
+}
+
+// ReviewResult holds the decisions taken during an interactive review
+type ReviewResult struct {
+	Message string   // Final commit message
+	Tag     string   // Tag computed from the chosen bump level
+	Exclude []string // Files deselected by the user
+}
+
+// ChangedFiles lists working tree changes (including untracked files) with line stats
+func (g *Git) ChangedFiles() ([]ChangedFile, error) {
+	// -z keeps paths unquoted; porcelain v2 starts every entry with its type,
+	// so trimming the output does not eat the status of the first one
+	out, err := RunCommandSilent("git", "status", "--porcelain=v2", "-z", "-uall")
+	if err != nil {
+		return nil, fmt.Errorf("git status failed: %w", err)
+	}
+
+	stats := map[string][2]int{}
+	if numstat, err := RunCommandSilent("git", "diff", "HEAD", "--numstat", "-z"); err == nil {
+		fields := strings.Split(numstat, "\x00")
+		for i := 0; i < len(fields); i++ {
+			parts := strings.SplitN(fields[i], "\t", 3)
+			if len(parts) < 3 {
+				continue
+			}
+			path := parts[2]
+			if path == "" && i+2 < len(fields) {
+				// Rename: the old and the new path follow
+				path = fields[i+2]
+				i += 2
+			}
+			added, _ := strconv.Atoi(parts[0])
+			removed, _ := strconv.Atoi(parts[1])
+			stats[path] = [2]int{added, removed}
+		}
+	}
+
+	var files []ChangedFile
+	entries := strings.Split(out, "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		var status, path string
+		switch {
+		case strings.HasPrefix(entry, "? "):
+			status, path = "??", entry[2:]
+		case strings.HasPrefix(entry, "1 "):
+			if f := strings.SplitN(entry, " ", 9); len(f) == 9 {
+				status, path = f[1], f[8]
+			}
+		case strings.HasPrefix(entry, "2 "):
+			if f := strings.SplitN(entry, " ", 10); len(f) == 10 {
+				status, path = f[1], f[9]
+			}
+			i++ // The original path of the rename follows
+		case strings.HasPrefix(entry, "u "):
+			if f := strings.SplitN(entry, " ", 11); len(f) == 11 {
+				status, path = f[1], f[10]
+			}
+		}
+		if path == "" {
+			continue
+		}
+		st := stats[path]
+		files = append(files, ChangedFile{Path: path, Status: strings.ReplaceAll(status, ".", " "), Added: st[0], Removed: st[1]})
+	}
+	return files, nil
+}
+
+// ReviewChanges runs the interactive pre-commit review: shows the diffstat,
+// lets the user deselect files, edit the message (or build a conventional
+// one when message is empty) and choose the bump level.
+// Nothing is committed or pushed; the caller applies the returned result.
+func (g *Git) ReviewChanges(in io.Reader, out io.Writer, message string) (ReviewResult, error) {
+	ask := promptReader(in, out)
+
+	files, err := g.ChangedFiles()
+	if err != nil {
+		return ReviewResult{}, err
+	}
+	if len(files) == 0 {
+		return ReviewResult{}, fmt.Errorf("no changes to review")
+	}
+
+	fmt.Fprintln(out, "Changes:")
+	for i, f := range files {
+		fmt.Fprintf(out, "  %2d) %s %s", i+1, f.Status, f.Path)
+		if f.Added > 0 || f.Removed > 0 {
+			fmt.Fprintf(out, " (+%d -%d)", f.Added, f.Removed)
+		}
+		fmt.Fprintln(out)
+	}
+
+	result := ReviewResult{}
+
+	// 1. Deselect files
+	if answer := ask("Exclude files (numbers separated by comma, Enter for none): "); answer != "" {
+		for _, field := range strings.FieldsFunc(answer, func(r rune) bool { return r == ',' || r == ' ' }) {
+			n, err := strconv.Atoi(field)
+			if err != nil || n < 1 || n > len(files) {
+				return ReviewResult{}, fmt.Errorf("invalid file number: %s", field)
+			}
+			result.Exclude = append(result.Exclude, files[n-1].Path)
+		}
+		if len(result.Exclude) == len(files) {
+			return ReviewResult{}, fmt.Errorf("all files excluded, nothing to commit")
+		}
+	}
+
+	// 2. Edit message, or build one when none was given
+	if message == "" {
+		if message, err = buildCommitMessage(ask, out); err != nil {
+			return ReviewResult{}, err
+		}
+	} else if answer := ask(fmt.Sprintf("Commit message [%s]: ", message)); answer != "" {
+		message = answer
+	}
+	if err := ValidateCommitMessage(message); err != nil {
+		return ReviewResult{}, err
+	}
+	result.Message = FormatCommitMessage(message)
+
+	// 3. Bump level
+	latest, _ := g.GetLatestTag()
+	level := g.BumpLevel(latest, result.Message)
+	if answer := ask(fmt.Sprintf("Bump level (patch/minor/major) [%s]: ", level)); answer != "" {
+		level = answer
+	}
+	result.Tag, err = BumpVersion(latest, level)
+	if err != nil {
+		return ReviewResult{}, err
+	}
+
+	// 4. Confirm
+	fmt.Fprintf(out, "Commit %d file(s) as %q and tag %s\n", len(files)-len(result.Exclude), result.Message, result.Tag)
+	if answer := strings.ToLower(ask("Proceed? [Y/n]: ")); answer != "" && answer != "y" && answer != "yes" {
+		return ReviewResult{}, fmt.Errorf("push cancelled")
+	}
+
+	return result, nil
+}
@@ -0,0 +1,88 @@
+package devflow
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestReviewChanges(t *testing.T) {
+	defer testPushedRepo(t)()
+
+	os.WriteFile("a.go", []byte("package a\n"), 0644)
+	os.WriteFile("scratch.txt", []byte("tmp\n"), 0644)
+	os.WriteFile("README.md", []byte("# test\nmore\n"), 0644)
+
+	git, _ := NewGit()
+
+	files, err := git.ChangedFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("expected 3 changed files, got %d: %+v", len(files), files)
+	}
+
+	// Files are sorted by git: README.md, a.go, scratch.txt
+	in := strings.NewReader("3\nfeat: reviewed\nminor\ny\n")
+	var out bytes.Buffer
+	res, err := git.ReviewChanges(in, &out, "wip")
+	if err != nil {
+		t.Fatalf("ReviewChanges failed: %v\n%s", err, out.String())
+	}
+
+	if res.Message != "feat: reviewed" {
+		t.Errorf("unexpected message: %s", res.Message)
+	}
+	if res.Tag != "v0.1.0" {
+		t.Errorf("expected minor bump to v0.1.0, got %s", res.Tag)
+	}
+	if len(res.Exclude) != 1 || res.Exclude[0] != "scratch.txt" {
+		t.Errorf("unexpected exclude list: %v", res.Exclude)
+	}
+	if !strings.Contains(out.String(), "README.md (+2 -1)") {
+		t.Errorf("diffstat not shown:\n%s", out.String())
+	}
+
+	git.SetPushOptions(PushOptions{Exclude: res.Exclude})
+	if _, err := git.Push(res.Message, res.Tag); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+
+	status, _ := RunCommandSilent("git", "status", "--porcelain")
+	if status != "?? scratch.txt" {
+		t.Errorf("excluded file should stay uncommitted, status: %q", status)
+	}
+}
+
+func TestChangedFilesQuotedAndRenamed(t *testing.T) {
+	defer testPushedRepo(t)()
+
+	os.WriteFile("héllo wörld.txt", []byte("hi\n"), 0644)
+	RunCommandSilent("git", "mv", "README.md", "GUIDE.md")
+
+	git, _ := NewGit()
+	files, err := git.ChangedFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{}
+	for _, f := range files {
+		got[f.Path] = f.Status
+	}
+	if len(files) != 2 || got["GUIDE.md"] != "R " || got["héllo wörld.txt"] != "??" {
+		t.Errorf("unexpected changed files: %+v", files)
+	}
+}
+
+func TestReviewChangesCancel(t *testing.T) {
+	defer testPushedRepo(t)()
+	os.WriteFile("a.go", []byte("package a\n"), 0644)
+
+	git, _ := NewGit()
+	_, err := git.ReviewChanges(strings.NewReader("\n\n\nn\n"), &bytes.Buffer{}, "feat: x")
+	if err == nil || !strings.Contains(err.Error(), "cancelled") {
+		t.Errorf("expected cancel error, got %v", err)
+	}
+}
@@ -0,0 +1,294 @@
+package devflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WorkspacePushOptions configures PushWorkspace
+type WorkspacePushOptions struct {
+	SkipTests      bool
+	SkipRace       bool
+	SkipDependents bool // Leave the dependent modules outside the workspace alone
+	SkipBackup     bool
+	SearchPath     string // Where dependents outside the workspace are searched (default: the parent of root)
+}
+
+// workspacePush is the outcome of one module of PushWorkspace
+type workspacePush struct {
+	dir     string // Relative to the workspace root
+	path    string // Module path
+	tag     string // New tag, empty when none was created
+	summary string // Summary of its push
+}
+
+// workspaceModule is a module of the workspace with the workspace
+// modules it requires
+type workspaceModule struct {
+	dir      string
+	path     string
+	requires []string // Module paths
+}
+
+// PushWorkspace pushes every module of root (see FindWorkspaceModules)
+// with message, dependencies first: each module requires the new tags of
+// the workspace modules pushed before it, then is tested, tagged and
+// pushed like Push. Modules outside the workspace that depend on a pushed
+// module are updated at the end. Each module must have its own git
+// repository. The first failing module stops the run; the summary has a
+// line per module.
+func (g *Go) PushWorkspace(root, message string, opts WorkspacePushOptions) (_ string, err error) {
+	if err := ValidateCommitMessage(message); err != nil {
+		return "", err
+	}
+	if g.dryRun {
+		defer startDryRun()()
+	}
+	defer trackMetric("gopush-workspace")(&err)
+
+	root, err = filepath.Abs(root)
+	if err != nil {
+		return "", err
+	}
+	dirs, err := FindWorkspaceModules(root)
+	if err != nil {
+		return "", err
+	}
+	if len(dirs) == 0 {
+		return "", fmt.Errorf("no Go modules found in %s", root)
+	}
+	modules, err := g.orderWorkspace(root, dirs)
+	if err != nil {
+		return "", err
+	}
+	if err := checkWorkspaceRepos(root, modules); err != nil {
+		return "", err
+	}
+
+	// Each module is released against the published versions of the
+	// others, as its users get it, not against the go.work checkouts
+	if old, ok := os.LookupEnv("GOWORK"); ok {
+		defer os.Setenv("GOWORK", old)
+	} else {
+		defer os.Unsetenv("GOWORK")
+	}
+	os.Setenv("GOWORK", "off")
+
+	var pushed []workspacePush
+	tags := make(map[string]string) // Module path -> new tag
+	for i, m := range modules {
+		modDir := filepath.Join(root, filepath.FromSlash(m.dir))
+		g.logger.Info("Pushing", m.dir)
+		if err := g.requireWorkspaceTags(modDir, m, tags); err != nil {
+			return formatWorkspacePush(pushed), workspacePushError(m, modules[i+1:], err)
+		}
+		result, err := g.pushWorkspaceModule(modDir, message, opts)
+		if err != nil {
+			return formatWorkspacePush(pushed), workspacePushError(m, modules[i+1:], err)
+		}
+		result.dir, result.path = m.dir, m.path
+		if result.tag != "" {
+			tags[m.path] = result.tag
+		}
+		pushed = append(pushed, result)
+	}
+	summary := formatWorkspacePush(pushed)
+
+	// Dependents outside the workspace, once every module is pushed
+	if !opts.SkipDependents {
+		searchPath := opts.SearchPath
+		if searchPath == "" {
+			searchPath = filepath.Dir(root)
+		}
+		inWorkspace := make(map[string]bool, len(modules))
+		for _, m := range modules {
+			inWorkspace[filepath.Join(root, filepath.FromSlash(m.dir))] = true
+		}
+		var updates []string
+		for _, p := range pushed {
+			if p.tag == "" {
+				continue
+			}
+			report, err := g.updateDependentsExcept(p.path, p.tag, searchPath, inWorkspace)
+			if err != nil {
+				updates = append(updates, fmt.Sprintf("Warning: failed to scan dependents of %s: %v", p.path, err))
+			}
+			updates = append(updates, report.Lines()...)
+		}
+		if len(updates) > 0 {
+			summary += "\nDependents: " + strings.Join(updates, ", ")
+		}
+	}
+
+	if !opts.SkipBackup {
+		if backupMsg, err := g.backup.Run(); err != nil {
+			summary += fmt.Sprintf("\n❌ backup failed to start: %v", err)
+		} else if backupMsg != "" {
+			summary += "\n" + backupMsg
+		}
+	}
+	return summary, nil
+}
+
+// orderWorkspace reads the module path of each dir and sorts the modules
+// so each comes after the workspace modules it requires, keeping the dir
+// order otherwise. A dependency cycle is an error.
+func (g *Go) orderWorkspace(root string, dirs []string) ([]workspaceModule, error) {
+	modules := make([]workspaceModule, len(dirs))
+	for i, dir := range dirs {
+		m, err := ReadModule(filepath.Join(root, filepath.FromSlash(dir)))
+		if err != nil {
+			return nil, fmt.Errorf("module %s: %w", dir, err)
+		}
+		modules[i] = workspaceModule{dir: dir, path: m.Path}
+	}
+	for i := range modules {
+		gomod := filepath.Join(root, filepath.FromSlash(modules[i].dir), "go.mod")
+		for _, other := range modules {
+			if other.path != modules[i].path && g.hasDependency(gomod, other.path) {
+				modules[i].requires = append(modules[i].requires, other.path)
+			}
+		}
+	}
+
+	var ordered []workspaceModule
+	done := make(map[string]bool, len(modules))
+	for len(ordered) < len(modules) {
+		progress := false
+		for _, m := range modules {
+			if done[m.path] || !allDone(m.requires, done) {
+				continue
+			}
+			ordered = append(ordered, m)
+			done[m.path] = true
+			progress = true
+			break // Restart so the earliest ready dir comes next
+		}
+		if !progress {
+			var cycle []string
+			for _, m := range modules {
+				if !done[m.path] {
+					cycle = append(cycle, m.dir)
+				}
+			}
+			return nil, fmt.Errorf("dependency cycle between modules: %s", strings.Join(cycle, ", "))
+		}
+	}
+	return ordered, nil
+}
+
+func allDone(paths []string, done map[string]bool) bool {
+	for _, p := range paths {
+		if !done[p] {
+			return false
+		}
+	}
+	return true
+}
+
+// checkWorkspaceRepos rejects modules sharing a git repository: their
+// tags would need a module prefix (sub/v1.2.3), which Push does not create
+func checkWorkspaceRepos(root string, modules []workspaceModule) error {
+	repos := make(map[string]string)
+	for _, m := range modules {
+		top, err := RunCommandInDir(filepath.Join(root, filepath.FromSlash(m.dir)), "git", "rev-parse", "--show-toplevel")
+		if err != nil {
+			return fmt.Errorf("module %s is not in a git repository", m.dir)
+		}
+		if other, ok := repos[top]; ok {
+			return fmt.Errorf("modules %s and %s share the git repository %s; PushWorkspace needs one repository per module", other, m.dir, top)
+		}
+		repos[top] = m.dir
+	}
+	return nil
+}
+
+// requireWorkspaceTags makes the module in modDir require the new tags of
+// the workspace modules it depends on
+func (g *Go) requireWorkspaceTags(modDir string, m workspaceModule, tags map[string]string) error {
+	for _, dep := range m.requires {
+		tag := tags[dep]
+		if tag == "" {
+			continue
+		}
+		if DryRunActive() {
+			dryRunf(modDir, "go get %s@%s", dep, tag)
+			dryRunf(modDir, "go mod tidy")
+			continue
+		}
+		if err := g.WaitForVersionAvailable(dep, tag); err != nil {
+			return err
+		}
+		if _, _, err := g.requireVersion(modDir, dep, tag); err != nil {
+			return fmt.Errorf("update %s to %s: %w", dep, tag, err)
+		}
+	}
+	return nil
+}
+
+// pushWorkspaceModule runs Push in modDir with handlers configured like g
+func (g *Go) pushWorkspaceModule(modDir, message string, opts WorkspacePushOptions) (workspacePush, error) {
+	originalDir, err := os.Getwd()
+	if err != nil {
+		return workspacePush{}, err
+	}
+	if err := os.Chdir(modDir); err != nil {
+		return workspacePush{}, err
+	}
+	defer os.Chdir(originalDir)
+
+	git, err := NewGit()
+	if err != nil {
+		return workspacePush{}, err
+	}
+	git.SetLogger(g.logger)
+	if parent, ok := g.git.(*Git); ok {
+		git.SetPushOptions(parent.pushOpts)
+	}
+	mod, err := NewGo(git)
+	if err != nil {
+		return workspacePush{}, err
+	}
+	mod.SetLogger(g.logger)
+	mod.SetDryRun(g.dryRun)
+	mod.SetRetryConfig(g.retryDelay, g.retryAttempts)
+	mod.SetRelease(!g.noRelease)
+	mod.hookFuncs = g.hookFuncs
+
+	previous, _ := git.GetLatestTag()
+	resetDryRunTag()
+	summary, err := mod.Push(message, "", opts.SkipTests, opts.SkipRace, true, true, "")
+	if err != nil {
+		return workspacePush{}, err
+	}
+	result := workspacePush{summary: summary}
+	if DryRunActive() {
+		result.tag = dryRunTag()
+	} else if latest, _ := git.GetLatestTag(); latest != previous {
+		result.tag = latest
+	}
+	return result, nil
+}
+
+// workspacePushError names the failed module and those left unpushed
+func workspacePushError(failed workspaceModule, rest []workspaceModule, err error) error {
+	if len(rest) == 0 {
+		return fmt.Errorf("%s: %w", failed.dir, err)
+	}
+	names := make([]string, len(rest))
+	for i, m := range rest {
+		names[i] = m.dir
+	}
+	return fmt.Errorf("%s: %w\nNot pushed: %s", failed.dir, err, strings.Join(names, ", "))
+}
+
+// formatWorkspacePush returns a "✅ dir (path): summary" line per module
+func formatWorkspacePush(pushed []workspacePush) string {
+	lines := make([]string, len(pushed))
+	for i, p := range pushed {
+		lines[i] = fmt.Sprintf("✅ %s (%s): %s", p.dir, p.path, p.summary)
+	}
+	return strings.Join(lines, "\n")
+}
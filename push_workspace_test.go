@@ -0,0 +1,115 @@
+package devflow
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testWorkspaceModule creates root/dir as a pushed module repository
+// with its own bare remote, tagged v0.0.1
+func testWorkspaceModule(t *testing.T, root, dir, gomod string) string {
+	t.Helper()
+	modDir := filepath.Join(root, dir)
+	remote := filepath.Join(root, ".remotes", dir+".git")
+	os.MkdirAll(modDir, 0755)
+	os.WriteFile(filepath.Join(modDir, "go.mod"), []byte(gomod), 0644)
+	for _, args := range [][]string{
+		{"init", "--bare", remote},
+		{"-C", modDir, "init"},
+		{"-C", modDir, "add", "."},
+		{"-C", modDir, "commit", "-m", "initial"},
+		{"-C", modDir, "tag", "v0.0.1"},
+		{"-C", modDir, "remote", "add", "origin", "file://" + remote},
+		{"-C", modDir, "push", "-u", "origin", "HEAD", "--tags"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %s", args, out)
+		}
+	}
+	return remote
+}
+
+func TestOrderWorkspace(t *testing.T) {
+	root := t.TempDir()
+	write := func(dir, gomod string) {
+		os.MkdirAll(filepath.Join(root, dir), 0755)
+		os.WriteFile(filepath.Join(root, dir, "go.mod"), []byte(gomod), 0644)
+	}
+	write("api", "module example.com/api\n\nrequire example.com/core v0.1.0\n")
+	write("core", "module example.com/core\n")
+	write("tool", "module example.com/tool\n\nrequire (\n\texample.com/api v0.2.0\n\texample.com/core v0.1.0\n)\n")
+	write("web", "module example.com/web\n")
+
+	g := &Go{}
+	modules, err := g.orderWorkspace(root, []string{"api", "core", "tool", "web"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var order []string
+	for _, m := range modules {
+		order = append(order, m.dir)
+	}
+	if strings.Join(order, ",") != "core,api,tool,web" {
+		t.Errorf("order = %v", order)
+	}
+
+	write("core", "module example.com/core\n\nrequire example.com/tool v0.3.0\n")
+	if _, err := g.orderWorkspace(root, []string{"api", "core", "tool", "web"}); err == nil || !strings.Contains(err.Error(), "cycle between modules: api, core, tool") {
+		t.Errorf("expected cycle error, got %v", err)
+	}
+}
+
+func TestPushWorkspace(t *testing.T) {
+	testResumeEnv(t)
+	root := t.TempDir()
+	defer testChdir(t, root)()
+	coreRemote := testWorkspaceModule(t, root, "core", "module example.com/core\n\ngo 1.20\n")
+	webRemote := testWorkspaceModule(t, root, "web", "module example.com/web\n\ngo 1.20\n")
+	testWorkspaceModule(t, root, "api", "module example.com/api\n\ngo 1.20\n\nrequire example.com/core v0.0.1\n\nreplace example.com/core => ../core\n")
+	os.WriteFile(filepath.Join(root, "go.work"), []byte("go 1.20\n\nuse (\n\t./web\n\t./api\n\t./core\n)\n"), 0644)
+
+	// Dry run: api requires the core tag pushed before it
+	out := testDryRunOutput(t)
+	git, _ := NewGit()
+	g, _ := NewGo(git)
+	g.SetDryRun(true)
+	opts := WorkspacePushOptions{SkipTests: true, SkipDependents: true, SkipBackup: true}
+	summary, err := g.PushWorkspace(root, "feat: shared", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(summary, "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "✅ web (example.com/web)") || !strings.HasPrefix(lines[1], "✅ core (example.com/core)") || !strings.HasPrefix(lines[2], "✅ api (example.com/api)") {
+		t.Errorf("unexpected summary %q", summary)
+	}
+	if !strings.Contains(out.String(), "go get example.com/core@v0.1.0") {
+		t.Errorf("expected api to require the new core tag, got:\n%s", out)
+	}
+	if os.Getenv("GOWORK") != "" {
+		t.Error("GOWORK should be restored")
+	}
+
+	// Modules without requirements are pushed for real
+	os.WriteFile(filepath.Join(root, "go.work"), []byte("go 1.20\n\nuse ./core\nuse ./web\n"), 0644)
+	os.WriteFile(filepath.Join(root, "core", "core.go"), []byte("package core\n"), 0644)
+	g.SetDryRun(false)
+	if _, err := g.PushWorkspace(root, "fix: release", opts); err != nil {
+		t.Fatal(err)
+	}
+	for _, remote := range []string{coreRemote, webRemote} {
+		if tags, _ := RunCommand("git", "-C", remote, "tag"); !strings.Contains(tags, "v0.0.2") {
+			t.Errorf("expected v0.0.2 pushed to %s, got %q", remote, tags)
+		}
+	}
+
+	// One repository per module
+	os.MkdirAll(filepath.Join(root, "core", "sub"), 0755)
+	os.WriteFile(filepath.Join(root, "core", "sub", "go.mod"), []byte("module example.com/core/sub\n"), 0644)
+	os.WriteFile(filepath.Join(root, "go.work"), []byte("go 1.20\n\nuse ./core\nuse ./core/sub\n"), 0644)
+	if _, err := g.PushWorkspace(root, "fix: shared repo", opts); err == nil || !strings.Contains(err.Error(), "share the git repository") {
+		t.Errorf("expected shared repository error, got %v", err)
+	}
+}
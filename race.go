@@ -0,0 +1,81 @@
+package devflow
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RaceMode controls whether gotest runs the race detector
+type RaceMode string
+
+const (
+	RaceAuto RaceMode = "auto" // -race when CGO and the platform support it (default)
+	RaceOn   RaceMode = "on"   // Always -race; go test fails where it is unavailable
+	RaceOff  RaceMode = "off"  // Never -race
+)
+
+// racePlatforms are the GOOS/GOARCH pairs supported by the race detector
+var racePlatforms = map[string]bool{
+	"linux/amd64": true, "linux/arm64": true, "linux/ppc64le": true, "linux/s390x": true,
+	"linux/loong64": true, "linux/riscv64": true,
+	"darwin/amd64": true, "darwin/arm64": true,
+	"freebsd/amd64": true, "netbsd/amd64": true,
+	"windows/amd64": true, "windows/arm64": true,
+}
+
+// ParseRaceMode parses "auto", "on" or "off" (empty means auto). Boolean
+// spellings are accepted for on/off.
+func ParseRaceMode(s string) (RaceMode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "auto":
+		return RaceAuto, nil
+	case "on", "true", "yes", "1":
+		return RaceOn, nil
+	case "off", "false", "no", "0":
+		return RaceOff, nil
+	}
+	return RaceAuto, fmt.Errorf("invalid race mode %q (want auto, on or off)", s)
+}
+
+// raceSupport reports whether -race works with the current go env. reason
+// explains why not, for the summary.
+func raceSupport() (ok bool, reason string) {
+	out, err := RunCommandSilent("go", "env", "CGO_ENABLED", "GOOS", "GOARCH")
+	if err != nil {
+		return false, "go env failed"
+	}
+	return raceSupportFor(strings.Fields(out))
+}
+
+// raceSupportFor decides from the CGO_ENABLED, GOOS and GOARCH values
+func raceSupportFor(env []string) (bool, string) {
+	if len(env) < 3 {
+		return false, "go env failed"
+	}
+	if platform := env[1] + "/" + env[2]; !racePlatforms[platform] {
+		return false, "unsupported platform: " + platform
+	}
+	if env[0] != "1" {
+		return false, "unsupported platform: CGO_ENABLED=0"
+	}
+	return true, ""
+}
+
+// resolveRace decides if this run uses -race. Without an explicit mode the
+// test.race config key applies.
+func (g *Go) resolveRace(mode RaceMode) (bool, string) {
+	if mode == "" {
+		parsed, err := ParseRaceMode(g.Config().String("test.race", ""))
+		if err != nil {
+			g.logger.Warn("test.race:", err)
+		}
+		mode = parsed
+	}
+	switch mode {
+	case RaceOn:
+		return true, ""
+	case RaceOff:
+		return false, "disabled"
+	}
+	return raceSupport()
+}
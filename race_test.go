@@ -0,0 +1,48 @@
+package devflow
+
+import "testing"
+
+func TestParseRaceMode(t *testing.T) {
+	tests := map[string]RaceMode{"": RaceAuto, "auto": RaceAuto, "ON": RaceOn, "true": RaceOn, "off": RaceOff, "0": RaceOff}
+	for in, want := range tests {
+		if got, err := ParseRaceMode(in); err != nil || got != want {
+			t.Errorf("ParseRaceMode(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseRaceMode("sometimes"); err == nil {
+		t.Error("expected error for invalid mode")
+	}
+}
+
+func TestRaceSupportFor(t *testing.T) {
+	tests := []struct {
+		env    []string
+		ok     bool
+		reason string
+	}{
+		{[]string{"1", "linux", "amd64"}, true, ""},
+		{[]string{"0", "linux", "amd64"}, false, "unsupported platform: CGO_ENABLED=0"},
+		{[]string{"1", "linux", "386"}, false, "unsupported platform: linux/386"},
+		{[]string{"1", "js", "wasm"}, false, "unsupported platform: js/wasm"},
+		{nil, false, "go env failed"},
+	}
+	for _, tt := range tests {
+		ok, reason := raceSupportFor(tt.env)
+		if ok != tt.ok || reason != tt.reason {
+			t.Errorf("raceSupportFor(%v) = %v, %q; want %v, %q", tt.env, ok, reason, tt.ok, tt.reason)
+		}
+	}
+}
+
+func TestResolveRace(t *testing.T) {
+	g := &Go{logger: NopLogger()}
+	c, _ := ParseConfig("test:\n  race: off\n")
+	g.SetConfig(c)
+
+	if ok, reason := g.resolveRace(""); ok || reason != "disabled" {
+		t.Errorf("test.race: off should disable race, got %v %q", ok, reason)
+	}
+	if ok, _ := g.resolveRace(RaceOn); !ok {
+		t.Error("explicit mode should override config")
+	}
+}
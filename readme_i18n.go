@@ -0,0 +1,140 @@
+package devflow
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// readmeCatalogs holds the localized README strings, one <lang>.yaml per
+// language with its name and the section headings
+//
+//go:embed readmes/*.yaml
+var readmeCatalogs embed.FS
+
+// localizedReadmeRe matches README.<lang>.md, e.g. README.es.md or README.pt-BR.md
+var localizedReadmeRe = regexp.MustCompile(`^README\.([a-z]{2}(?:-[A-Z]{2})?)\.md$`)
+
+// ReadmeLanguages returns the language codes GenerateLocalizedREADMEs supports
+func ReadmeLanguages() []string {
+	entries, _ := readmeCatalogs.ReadDir("readmes")
+	var langs []string
+	for _, e := range entries {
+		if lang, ok := strings.CutSuffix(e.Name(), ".yaml"); ok {
+			langs = append(langs, lang)
+		}
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// loadReadmeCatalog returns the catalog of lang, that of its language
+// when there is none for the region (pt-BR uses pt)
+func loadReadmeCatalog(lang string) (*Config, error) {
+	data, err := readmeCatalogs.ReadFile("readmes/" + lang + ".yaml")
+	if base, _, ok := strings.Cut(lang, "-"); err != nil && ok {
+		data, err = readmeCatalogs.ReadFile("readmes/" + base + ".yaml")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unsupported README language %q (want %s)", lang, strings.Join(ReadmeLanguages(), ", "))
+	}
+	return ParseConfig(string(data))
+}
+
+// NormalizeReadmeLanguages writes langs as README.<lang>.md names them
+// (lowercase language, uppercase region: pt-BR), deduplicates and checks
+// them; "en" is README.md itself and is dropped
+func NormalizeReadmeLanguages(langs []string) ([]string, error) {
+	var out []string
+	seen := map[string]bool{"en": true}
+	for _, lang := range langs {
+		lang, region, _ := strings.Cut(strings.ToLower(strings.TrimSpace(lang)), "-")
+		if region != "" {
+			lang += "-" + strings.ToUpper(region)
+		}
+		if lang == "" || seen[lang] {
+			continue
+		}
+		if _, err := loadReadmeCatalog(lang); err != nil {
+			return nil, err
+		}
+		seen[lang] = true
+		out = append(out, lang)
+	}
+	return out, nil
+}
+
+// GenerateLocalizedREADMEs writes README.<lang>.md for each of langs, with
+// the headings of the language and the description as given (to be
+// translated by hand), and links every variant from README.md
+func GenerateLocalizedREADMEs(repoName, description string, author Author, langs []string, targetDir string) error {
+	if len(langs) == 0 {
+		return nil
+	}
+	names := map[string]string{"en": "English"}
+	catalogs := make(map[string]*Config, len(langs))
+	for _, lang := range langs {
+		c, err := loadReadmeCatalog(lang)
+		if err != nil {
+			return err
+		}
+		catalogs[lang] = c
+		names[lang] = c.String("language", lang)
+	}
+	all := append([]string{"en"}, langs...)
+
+	for _, lang := range langs {
+		c := catalogs[lang]
+		content := fmt.Sprintf("# %s\n\n%s\n\n%s\n", repoName, readmeLanguageLinks(all, names, lang), description) +
+			titledAuthorSection(author, c.String("headings.author", "Author"))
+		if err := writeGenerated(filepath.Join(targetDir, "README."+lang+".md"), []byte(content)); err != nil {
+			return err
+		}
+	}
+
+	readme := filepath.Join(targetDir, "README.md")
+	data, err := os.ReadFile(readme)
+	if err != nil {
+		return err
+	}
+	title, rest, _ := strings.Cut(string(data), "\n\n")
+	content := title + "\n\n" + readmeLanguageLinks(all, names, "en") + "\n\n" + rest
+	return writeGenerated(readme, []byte(content))
+}
+
+// readmeLanguageLinks is the line linking the README variants, current in bold
+func readmeLanguageLinks(langs []string, names map[string]string, current string) string {
+	links := make([]string, len(langs))
+	for i, lang := range langs {
+		file := "README." + lang + ".md"
+		if lang == "en" {
+			file = "README.md"
+		}
+		if lang == current {
+			links[i] = "**" + names[lang] + "**"
+		} else {
+			links[i] = "[" + names[lang] + "](" + file + ")"
+		}
+	}
+	return "🌐 " + strings.Join(links, " · ")
+}
+
+// LocalizedREADMEs returns the README.<lang>.md files next to readme, sorted
+func LocalizedREADMEs(readme string) []string {
+	dir := filepath.Dir(readme)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() && localizedReadmeRe.MatchString(e.Name()) {
+			files = append(files, filepath.Join(dir, e.Name()))
+		}
+	}
+	return files
+}
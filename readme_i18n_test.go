@@ -0,0 +1,73 @@
+package devflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateLocalizedREADMEs(t *testing.T) {
+	dir := t.TempDir()
+	author := Author{Name: "Jane Doe", Email: "jane@example.com"}
+	if err := GenerateREADMEWithAuthor("my-lib", "A library", author, dir); err != nil {
+		t.Fatal(err)
+	}
+	langs, err := NormalizeReadmeLanguages([]string{"ES", "en", "pt", "es"})
+	if err != nil || strings.Join(langs, ",") != "es,pt" {
+		t.Fatalf("NormalizeReadmeLanguages = %v, %v", langs, err)
+	}
+	if err := GenerateLocalizedREADMEs("my-lib", "A library", author, langs, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	es, _ := os.ReadFile(filepath.Join(dir, "README.es.md"))
+	for _, want := range []string{"# my-lib\n", "🌐 [English](README.md) · **Español** · [Português](README.pt.md)\n", "## Autor\n", "Jane Doe"} {
+		if !strings.Contains(string(es), want) {
+			t.Errorf("README.es.md should contain %q, got:\n%s", want, es)
+		}
+	}
+	readme, _ := os.ReadFile(filepath.Join(dir, "README.md"))
+	if !strings.HasPrefix(string(readme), "# my-lib\n\n🌐 **English** · [Español](README.es.md) · [Português](README.pt.md)\n\n") {
+		t.Errorf("README.md should link the variants after the title, got:\n%s", readme)
+	}
+
+	got := LocalizedREADMEs(filepath.Join(dir, "README.md"))
+	if len(got) != 2 || filepath.Base(got[0]) != "README.es.md" || filepath.Base(got[1]) != "README.pt.md" {
+		t.Errorf("LocalizedREADMEs = %v", got)
+	}
+
+	// The region keeps the case of README.pt-BR.md and uses the pt catalog
+	if langs, err := NormalizeReadmeLanguages([]string{"pt-br", "PT-BR"}); err != nil || strings.Join(langs, ",") != "pt-BR" {
+		t.Errorf("NormalizeReadmeLanguages = %v, %v", langs, err)
+	}
+	if err := GenerateLocalizedREADMEs("my-lib", "A library", author, []string{"pt-BR"}, dir); err != nil {
+		t.Fatal(err)
+	}
+	if got := LocalizedREADMEs(filepath.Join(dir, "README.md")); len(got) != 3 || filepath.Base(got[1]) != "README.pt-BR.md" {
+		t.Errorf("LocalizedREADMEs = %v", got)
+	}
+
+	if _, err := NormalizeReadmeLanguages([]string{"xx"}); err == nil || !strings.Contains(err.Error(), "unsupported README language") {
+		t.Errorf("expected unsupported language error, got %v", err)
+	}
+}
+
+func TestUpdateBadgesLocalizedREADMEs(t *testing.T) {
+	dir := t.TempDir()
+	defer testChdir(t, dir)()
+	os.WriteFile("README.md", []byte("# my-lib\n\nA library\n"), 0644)
+	os.WriteFile("README.es.md", []byte("# my-lib\n\nUna biblioteca\n"), 0644)
+
+	h := NewBadges()
+	h.SetLog(func(...any) {})
+	if err := h.updateBadges("README.md", "MIT", "1.22", "Passing", "80", "Clean", "OK", "", "", true); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"README.md", "README.es.md"} {
+		data, _ := os.ReadFile(name)
+		if !strings.Contains(string(data), "START_SECTION:BADGES_SECTION") || !strings.Contains(string(data), "badges.svg") {
+			t.Errorf("%s should have the badge section, got:\n%s", name, data)
+		}
+	}
+}
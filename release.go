@@ -0,0 +1,245 @@
+package devflow
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// TagNotesReader is implemented by git clients that can read back the
+// notes of an annotated tag
+type TagNotesReader interface {
+	TagNotes(tag string) (string, error)
+}
+
+// TagNotes returns the body of the annotated tag message, the notes
+// CreateTag wrote, or "" for a lightweight tag
+func (g *Git) TagNotes(tag string) (string, error) {
+	out, err := RunCommandSilent("git", "tag", "--list", "--format=%(contents)", tag)
+	if err != nil {
+		return "", err
+	}
+	if kind, _ := RunCommandSilent("git", "cat-file", "-t", tag); kind != "tag" {
+		return "", nil // Lightweight: %(contents) is the commit message
+	}
+	_, body, _ := strings.Cut(out, "\n")
+	return strings.TrimSpace(body), nil
+}
+
+// CreateRelease publishes a GitHub release of an existing tag in the
+// repository of the current directory, with notes as its body (GitHub
+// generates notes when empty) and assets attached. It returns the
+// release URL.
+func (gh *GitHub) CreateRelease(tag, notes string, assets []string) (string, error) {
+	args := []string{"release", "create", tag, "--title", tag, "--verify-tag"}
+	if notes != "" {
+		args = append(args, "--notes", notes)
+	} else {
+		args = append(args, "--generate-notes")
+	}
+	args = append(args, assets...)
+	out, err := RunCommand("gh", args...)
+	if err != nil {
+		return "", fmt.Errorf("gh release create %s failed: %s", tag, firstLine(out))
+	}
+	lines := strings.Split(out, "\n")
+	return strings.TrimSpace(lines[len(lines)-1]), nil
+}
+
+// ReleaseExists reports whether tag has a release in the repository of
+// the current directory
+func (gh *GitHub) ReleaseExists(tag string) (bool, error) {
+	out, err := RunCommandSilent("gh", "release", "view", tag, "--json", "tagName")
+	if err != nil {
+		if strings.Contains(out, "not found") {
+			return false, nil
+		}
+		return false, fmt.Errorf("gh release view %s failed: %s", tag, firstLine(out))
+	}
+	return true, nil
+}
+
+// UploadReleaseAssets attaches assets to the release of tag, replacing
+// assets of the same name
+func (gh *GitHub) UploadReleaseAssets(tag string, assets []string) error {
+	args := append([]string{"release", "upload", tag, "--clobber"}, assets...)
+	if out, err := RunCommand("gh", args...); err != nil {
+		return fmt.Errorf("gh release upload %s failed: %s", tag, firstLine(out))
+	}
+	return nil
+}
+
+// SetRelease sets whether Push creates a GitHub release for the new tag
+// (on by default, release.create: false in the config also disables it)
+func (g *Go) SetRelease(enabled bool) {
+	g.noRelease = !enabled
+}
+
+// SetReleaser sets the client releases are created with (default: a
+// GitHub client created on the first release)
+func (g *Go) SetReleaser(r Releaser) {
+	g.releaser = r
+}
+
+// releaseGitHubRepo returns owner/name when origin is a GitHub repository
+func releaseGitHubRepo() string {
+	url, err := RunCommandSilent("git", "remote", "get-url", "origin")
+	if err != nil {
+		return ""
+	}
+	host, owner, name, ok := ParseRemoteURL(url)
+	if !ok || host != (&GitHub{}).Host() {
+		return ""
+	}
+	return owner + "/" + name
+}
+
+// releaseAssets expands the release.assets globs, relative to the module
+func (g *Go) releaseAssets() ([]string, error) {
+	var assets []string
+	for _, pattern := range g.Config().List("release.assets") {
+		matches, err := filepath.Glob(filepath.Join(g.rootDir, pattern))
+		if err != nil {
+			return nil, fmt.Errorf("release.assets: %w", err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("release.assets: no file matches %s", pattern)
+		}
+		assets = append(assets, matches...)
+	}
+	sort.Strings(assets)
+	return assets, nil
+}
+
+// ReleaseMirror is a GitLab project that mirrors the repository: Push
+// sends it the branch and tag and creates the release there too.
+//
+//	release:
+//	  mirror:
+//	    gitlab: group/project
+//	    host: gitlab.example.com   # default: gitlab.com
+//	    remote: gitlab             # git remote or URL (default: https://<host>/<project>.git)
+type ReleaseMirror struct {
+	Project string
+	Host    string
+	Remote  string
+}
+
+// LoadReleaseMirror reads the release.mirror.* settings; Project is empty
+// when no mirror is configured
+func LoadReleaseMirror(c *Config) ReleaseMirror {
+	m := ReleaseMirror{
+		Project: strings.Trim(c.String("release.mirror.gitlab", ""), "/"),
+		Host:    c.String("release.mirror.host", "gitlab.com"),
+		Remote:  c.String("release.mirror.remote", ""),
+	}
+	if m.Remote == "" {
+		m.Remote = fmt.Sprintf("https://%s/%s.git", m.Host, m.Project)
+	}
+	return m
+}
+
+// SetMirrorReleaser sets the client mirror releases are created with
+// (default: a GitLab client for release.mirror.host)
+func (g *Go) SetMirrorReleaser(r Releaser) {
+	g.mirrorReleaser = r
+}
+
+// createRelease publishes the release of the tag Push just created: on
+// GitHub when origin is there, and on the release.mirror GitLab project
+// with a status per provider. The push already succeeded, so failures
+// are returned as a summary warning.
+func (g *Go) createRelease(tag string) string {
+	if g.noRelease || !g.Config().Bool("release.create", true) {
+		return ""
+	}
+	mirror := LoadReleaseMirror(g.Config())
+	repo := releaseGitHubRepo()
+	if repo == "" && mirror.Project == "" {
+		return ""
+	}
+	notes := ""
+	if reader, ok := g.git.(TagNotesReader); ok {
+		notes, _ = reader.TagNotes(tag)
+	}
+	assets, err := g.releaseAssets()
+	if err != nil {
+		return fmt.Sprintf("⚠️ release not created: %v", err)
+	}
+
+	if mirror.Project == "" {
+		if err := g.githubRelease(tag, repo, notes, assets); err != nil {
+			return fmt.Sprintf("⚠️ release not created: %v", err)
+		}
+		if DryRunActive() {
+			return ""
+		}
+		return fmt.Sprintf("✅ Release %s", tag)
+	}
+
+	var status []string
+	failed := false
+	report := func(provider string, err error) {
+		if err != nil {
+			failed = true
+			status = append(status, fmt.Sprintf("%s failed (%v)", provider, err))
+			return
+		}
+		status = append(status, provider+" ok")
+	}
+	if repo != "" {
+		report("GitHub", g.githubRelease(tag, repo, notes, assets))
+	}
+	report("GitLab", g.mirrorRelease(mirror, tag, notes, assets))
+	if DryRunActive() {
+		return ""
+	}
+	icon := "✅"
+	if failed {
+		icon = "⚠️"
+	}
+	return fmt.Sprintf("%s Release %s: %s", icon, tag, strings.Join(status, ", "))
+}
+
+// githubRelease creates the release of tag on the GitHub repository repo
+func (g *Go) githubRelease(tag, repo, notes string, assets []string) error {
+	if DryRunActive() {
+		dryRunf(g.rootDir, "gh release create %s (%s, %d line(s) of notes, %d asset(s))", tag, repo, strings.Count(notes, "\n")+1, len(assets))
+		return nil
+	}
+	if g.releaser == nil {
+		gh, err := NewGitHub(g.logger.Info)
+		if err != nil {
+			return err
+		}
+		g.releaser = gh
+	}
+	_, err := g.releaser.CreateRelease(tag, notes, assets)
+	return err
+}
+
+// mirrorRelease pushes the current branch and tag to the mirror and
+// creates the release of tag on its GitLab project
+func (g *Go) mirrorRelease(m ReleaseMirror, tag, notes string, assets []string) error {
+	if err := providerHostCheck("GitLab", m.Host); err != nil {
+		return err
+	}
+	if out, err := RunCommand("git", "push", m.Remote, "HEAD", tag); err != nil {
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		return fmt.Errorf("push to %s: %s", m.Remote, lines[len(lines)-1])
+	}
+	if DryRunActive() {
+		dryRunf(g.rootDir, "create GitLab release %s (%s/%s, %d asset(s))", tag, m.Host, m.Project, len(assets))
+		return nil
+	}
+	if g.mirrorReleaser == nil {
+		gl, err := NewGitLab(m.Host, g.logger.Info)
+		if err != nil {
+			return err
+		}
+		g.mirrorReleaser = gl.ProjectReleaser(m.Project)
+	}
+	_, err := g.mirrorReleaser.CreateRelease(tag, notes, assets)
+	return err
+}
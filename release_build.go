@@ -0,0 +1,350 @@
+package devflow
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Target is a GOOS/GOARCH pair of a release build
+type Target struct {
+	GOOS   string
+	GOARCH string
+}
+
+// String returns "linux/amd64"
+func (t Target) String() string {
+	return t.GOOS + "/" + t.GOARCH
+}
+
+// DefaultTargets are built when neither -targets nor build.targets is set
+var DefaultTargets = []Target{
+	{"linux", "amd64"}, {"linux", "arm64"},
+	{"darwin", "amd64"}, {"darwin", "arm64"},
+	{"windows", "amd64"},
+}
+
+// ReleaseChecksumsFile lists the SHA-256 of every archive of a build
+const ReleaseChecksumsFile = "checksums.txt"
+
+// ParseTargets parses "linux/amd64,darwin/arm64" (or a list of such items)
+func ParseTargets(items ...string) ([]Target, error) {
+	var targets []Target
+	for _, item := range items {
+		for _, s := range strings.Split(item, ",") {
+			if s = strings.TrimSpace(s); s == "" {
+				continue
+			}
+			goos, goarch, ok := strings.Cut(s, "/")
+			if !ok || goos == "" || goarch == "" || strings.Contains(goarch, "/") {
+				return nil, fmt.Errorf("invalid target %q (want GOOS/GOARCH, e.g. linux/amd64)", s)
+			}
+			targets = append(targets, Target{goos, goarch})
+		}
+	}
+	return targets, nil
+}
+
+// BuildResult describes the files written by BuildRelease
+type BuildResult struct {
+	Version   string
+	Dir       string   // Output directory (build.dir)
+	Commands  []string // Binaries built for each target
+	Archives  []string // One per target, paths relative to Dir
+	Checksums string   // Path of checksums.txt
+}
+
+// Summary returns e.g. "✅ Built v1.2.0: 2 command(s) x 5 target(s) in dist"
+func (r BuildResult) Summary() string {
+	return fmt.Sprintf("✅ Built %s: %d command(s) x %d target(s) in %s", r.Version, len(r.Commands), len(r.Archives), r.Dir)
+}
+
+// releaseCommands returns the main packages to build: each cmd/<name>
+// directory, or the module itself when it is a main package
+func (g *Go) releaseCommands(name string) (map[string]string, error) {
+	cmds := map[string]string{}
+	dirs, _ := filepath.Glob(filepath.Join(g.rootDir, "cmd", "*"))
+	for _, dir := range dirs {
+		if isMainPackage(dir) {
+			cmds[filepath.Base(dir)] = "./" + filepath.ToSlash(filepath.Join("cmd", filepath.Base(dir)))
+		}
+	}
+	if len(cmds) == 0 && isMainPackage(g.rootDir) {
+		cmds[name] = "."
+	}
+	if len(cmds) == 0 {
+		return nil, fmt.Errorf("no commands to build: no main package in cmd/* or the module root")
+	}
+	return cmds, nil
+}
+
+// isMainPackage reports whether dir holds a non-test Go file of package main
+func isMainPackage(dir string) bool {
+	files, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+	for _, f := range files {
+		if strings.HasSuffix(f, "_test.go") {
+			continue
+		}
+		if data, err := os.ReadFile(f); err == nil && goPackageName(string(data)) == "main" {
+			return true
+		}
+	}
+	return false
+}
+
+// goPackageName returns the name of the package clause of src
+func goPackageName(src string) string {
+	for _, line := range strings.Split(src, "\n") {
+		if fields := strings.Fields(line); len(fields) >= 2 && fields[0] == "package" {
+			return fields[1]
+		}
+	}
+	return ""
+}
+
+// releaseVersion returns the tag at HEAD, else git describe (e.g.
+// "v1.2.0-3-gabc1234-dirty") for a snapshot build
+func releaseVersion(dir string) string {
+	if tag, err := RunCommandInDir(dir, "git", "describe", "--tags", "--exact-match"); err == nil && tag != "" {
+		return tag
+	}
+	if v, err := RunCommandInDir(dir, "git", "describe", "--tags", "--always", "--dirty"); err == nil && v != "" {
+		return v
+	}
+	return "dev"
+}
+
+// BuildRelease cross-compiles the commands of the module (cmd/* or the
+// module root) for each target (default: build.targets, else
+// DefaultTargets) with CGO disabled and the version embedded with
+//
+//	-ldflags "-s -w -X <build.version_var>=<tag>"
+//
+// and packs each target into <name>_<version>_<os>_<arch>.tar.gz (.zip
+// for windows) with the LICENSE and README, plus checksums.txt, in
+// build.dir (default dist, added to .gitignore).
+func (g *Go) BuildRelease(targets []Target) (_ BuildResult, err error) {
+	if g.dryRun {
+		defer startDryRun()()
+	}
+	defer trackMetric("gorelease")(&err)
+
+	cfg := g.Config()
+	if len(targets) == 0 {
+		if targets, err = ParseTargets(cfg.List("build.targets")...); err != nil {
+			return BuildResult{}, fmt.Errorf("build.targets: %w", err)
+		}
+	}
+	if len(targets) == 0 {
+		targets = DefaultTargets
+	}
+
+	module, err := g.Module()
+	if err != nil {
+		return BuildResult{}, err
+	}
+	cmds, err := g.releaseCommands(module.Name)
+	if err != nil {
+		return BuildResult{}, err
+	}
+	names := make([]string, 0, len(cmds))
+	for name := range cmds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := BuildResult{
+		Version:  releaseVersion(g.rootDir),
+		Dir:      cfg.String("build.dir", "dist"),
+		Commands: names,
+	}
+	outDir := filepath.Join(g.rootDir, result.Dir)
+	if g.git != nil && !DryRunActive() {
+		if err := g.git.GitIgnoreAdd(filepath.ToSlash(result.Dir) + "/"); err != nil {
+			g.logger.Warn("could not add", result.Dir, "to .gitignore:", err)
+		}
+	}
+	if !DryRunActive() {
+		if err := os.MkdirAll(outDir, 0755); err != nil {
+			return result, err
+		}
+	}
+
+	ldflags := fmt.Sprintf("-s -w -X %s=%s", cfg.String("build.version_var", "main.version"), result.Version)
+	if extra := cfg.String("build.ldflags", ""); extra != "" {
+		ldflags += " " + extra
+	}
+	project := module.Name
+	var checksums []string
+	for _, t := range targets {
+		base := fmt.Sprintf("%s_%s_%s_%s", project, result.Version, t.GOOS, t.GOARCH)
+		stage := filepath.Join(outDir, base)
+		remove := OnInterrupt(func() { os.RemoveAll(stage) })
+		g.logger.Info("Building", t)
+		for _, name := range names {
+			bin := name
+			if t.GOOS == "windows" {
+				bin += ".exe"
+			}
+			env := []string{"GOOS=" + t.GOOS, "GOARCH=" + t.GOARCH, "CGO_ENABLED=0"}
+			if _, err := RunCommandWithEnvInDir(g.rootDir, env, "go", "build", "-trimpath", "-ldflags", ldflags, "-o", filepath.Join(stage, bin), cmds[name]); err != nil {
+				remove()
+				os.RemoveAll(stage)
+				return result, fmt.Errorf("build %s for %s failed: %w", name, t, err)
+			}
+		}
+
+		archive := base + ".tar.gz"
+		if t.GOOS == "windows" {
+			archive = base + ".zip"
+		}
+		result.Archives = append(result.Archives, archive)
+		if DryRunActive() {
+			dryRunf(g.rootDir, "archive %s", filepath.Join(result.Dir, archive))
+			remove()
+			continue
+		}
+		for _, doc := range []string{"LICENSE", "README.md"} {
+			if data, err := os.ReadFile(filepath.Join(g.rootDir, doc)); err == nil {
+				os.WriteFile(filepath.Join(stage, doc), data, 0644)
+			}
+		}
+		if t.GOOS == "windows" {
+			err = writeZip(filepath.Join(outDir, archive), stage)
+		} else {
+			err = writeTarGz(filepath.Join(outDir, archive), stage)
+		}
+		os.RemoveAll(stage)
+		remove()
+		if err != nil {
+			return result, fmt.Errorf("archive %s: %w", archive, err)
+		}
+		sum, err := fileSHA256(filepath.Join(outDir, archive))
+		if err != nil {
+			return result, err
+		}
+		checksums = append(checksums, sum+"  "+archive)
+	}
+
+	result.Checksums = filepath.Join(result.Dir, ReleaseChecksumsFile)
+	if DryRunActive() {
+		dryRunf(g.rootDir, "write %s", result.Checksums)
+		return result, nil
+	}
+	return result, os.WriteFile(filepath.Join(g.rootDir, result.Checksums), []byte(strings.Join(checksums, "\n")+"\n"), 0644)
+}
+
+// ReleaseFiles returns the archives and checksums of r, relative to the
+// module, for attaching to a release
+func (r BuildResult) ReleaseFiles() []string {
+	files := make([]string, 0, len(r.Archives)+1)
+	for _, a := range r.Archives {
+		files = append(files, filepath.Join(r.Dir, a))
+	}
+	return append(files, r.Checksums)
+}
+
+// UploadRelease attaches the archives and checksums of r to the GitHub
+// release of r.Version, creating the release (with the tag notes) when
+// gopush has not. Snapshot builds of untagged commits cannot be uploaded.
+func (g *Go) UploadRelease(r BuildResult) (string, error) {
+	if g.dryRun {
+		defer startDryRun()()
+	}
+	if _, err := RunCommandInDir(g.rootDir, "git", "rev-parse", "--quiet", "--verify", "refs/tags/"+r.Version); err != nil {
+		return "", fmt.Errorf("%s is not a tag: tag the release (gopush) before uploading", r.Version)
+	}
+	files := r.ReleaseFiles()
+	if DryRunActive() {
+		dryRunf(g.rootDir, "gh release upload %s (%d file(s))", r.Version, len(files))
+		return "", nil
+	}
+	if g.releaser == nil {
+		gh, err := NewGitHub(g.logger.Info)
+		if err != nil {
+			return "", err
+		}
+		g.releaser = gh
+	}
+	if uploader, ok := g.releaser.(ReleaseUploader); ok {
+		exists, err := uploader.ReleaseExists(r.Version)
+		if err != nil {
+			return "", err
+		}
+		if exists {
+			if err := uploader.UploadReleaseAssets(r.Version, files); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("✅ Uploaded %d file(s) to release %s", len(files), r.Version), nil
+		}
+	}
+	notes := ""
+	if reader, ok := g.git.(TagNotesReader); ok {
+		notes, _ = reader.TagNotes(r.Version)
+	}
+	if _, err := g.releaser.CreateRelease(r.Version, notes, files); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("✅ Release %s with %d file(s)", r.Version, len(files)), nil
+}
+
+// fileSHA256 returns the hex SHA-256 of the file at path
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// writeZip archives the files under dir into the zip archive
+func writeZip(archive, dir string) error {
+	f, err := os.Create(archive)
+	if err != nil {
+		return err
+	}
+	zw := zip.NewWriter(f)
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		hdr, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		hdr.Method = zip.Deflate
+		w, err := zw.CreateHeader(hdr)
+		if err != nil {
+			return err
+		}
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.Copy(w, src)
+		return err
+	})
+	for _, c := range []io.Closer{zw, f} {
+		if cerr := c.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		os.Remove(archive)
+	}
+	return err
+}
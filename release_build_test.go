@@ -0,0 +1,149 @@
+package devflow
+
+import (
+	"archive/zip"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// testUploader is a testReleaser that also uploads to existing releases
+type testUploader struct {
+	testReleaser
+	existing string
+	uploaded []string
+}
+
+func (u *testUploader) ReleaseExists(tag string) (bool, error) {
+	return tag == u.existing, nil
+}
+
+func (u *testUploader) UploadReleaseAssets(tag string, assets []string) error {
+	u.uploaded = assets
+	return nil
+}
+
+func TestParseTargets(t *testing.T) {
+	targets, err := ParseTargets("linux/amd64, darwin/arm64", "windows/amd64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(targets) != 3 || targets[1].String() != "darwin/arm64" || targets[2].GOOS != "windows" {
+		t.Errorf("unexpected targets: %v", targets)
+	}
+	for _, bad := range []string{"linux", "linux/", "/amd64", "linux/arm/v7"} {
+		if _, err := ParseTargets(bad); err == nil {
+			t.Errorf("ParseTargets(%q) should fail", bad)
+		}
+	}
+	if targets, err := ParseTargets(""); err != nil || len(targets) != 0 {
+		t.Errorf("empty list should give no targets, got %v %v", targets, err)
+	}
+}
+
+func TestGoBuildRelease(t *testing.T) {
+	dir, cleanup := testCreateGitRepo()
+	defer cleanup()
+	defer testChdir(t, dir)()
+	os.WriteFile("go.mod", []byte("module example.com/tool\n\ngo 1.20\n"), 0644)
+	os.MkdirAll(filepath.Join("cmd", "hello"), 0755)
+	os.WriteFile(filepath.Join("cmd", "hello", "main.go"), []byte("package main\n\nvar version = \"dev\"\n\nfunc main() { println(version) }\n"), 0644)
+	os.WriteFile("LICENSE", []byte("MIT"), 0644)
+	exec.Command("git", "add", ".").Run()
+	exec.Command("git", "commit", "-m", "initial").Run()
+	exec.Command("git", "tag", "v1.2.0").Run()
+
+	git, _ := NewGit()
+	git.SetShouldWrite(func() bool { return true })
+	g, _ := NewGo(git)
+	host := Target{runtime.GOOS, runtime.GOARCH}
+	result, err := g.BuildRelease([]Target{host, {"windows", "amd64"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Version != "v1.2.0" || len(result.Commands) != 1 || result.Commands[0] != "hello" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if got := result.Summary(); got != "✅ Built v1.2.0: 1 command(s) x 2 target(s) in dist" {
+		t.Errorf("Summary() = %q", got)
+	}
+
+	base := "tool_v1.2.0_" + runtime.GOOS + "_" + runtime.GOARCH
+	if result.Archives[0] != base+".tar.gz" || result.Archives[1] != "tool_v1.2.0_windows_amd64.zip" {
+		t.Fatalf("unexpected archives: %v", result.Archives)
+	}
+
+	// The version is embedded in the binary
+	extracted := t.TempDir()
+	if err := extractTarGz(filepath.Join("dist", result.Archives[0]), extracted); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(extracted, "LICENSE")); err != nil {
+		t.Error("LICENSE not packed")
+	}
+	os.Chmod(filepath.Join(extracted, "hello"), 0755)
+	out, _ := exec.Command(filepath.Join(extracted, "hello")).CombinedOutput()
+	if strings.TrimSpace(string(out)) != "v1.2.0" {
+		t.Errorf("expected embedded version v1.2.0, got %q", out)
+	}
+
+	zr, err := zip.OpenReader(filepath.Join("dist", result.Archives[1]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	if len(zr.File) != 2 || zr.File[0].Name != "LICENSE" || zr.File[1].Name != "hello.exe" {
+		t.Errorf("unexpected zip contents: %v", zr.File)
+	}
+
+	sums, _ := os.ReadFile(filepath.Join("dist", ReleaseChecksumsFile))
+	lines := strings.Split(strings.TrimSpace(string(sums)), "\n")
+	sum, _ := fileSHA256(filepath.Join("dist", result.Archives[1]))
+	if len(lines) != 2 || lines[1] != sum+"  "+result.Archives[1] {
+		t.Errorf("unexpected checksums:\n%s", sums)
+	}
+	if data, _ := os.ReadFile(".gitignore"); !strings.Contains(string(data), "dist/") {
+		t.Error("dist/ not added to .gitignore")
+	}
+	if entries, _ := os.ReadDir("dist"); len(entries) != 3 {
+		t.Errorf("staging directories left in dist: %v", entries)
+	}
+
+	// Upload to the release gopush created, or create it
+	u := &testUploader{existing: "v1.2.0"}
+	g.SetReleaser(u)
+	if got, err := g.UploadRelease(result); err != nil || got != "✅ Uploaded 3 file(s) to release v1.2.0" || len(u.uploaded) != 3 {
+		t.Errorf("UploadRelease() = %q, %v (uploaded %v)", got, err, u.uploaded)
+	}
+	u.existing = ""
+	if got, _ := g.UploadRelease(result); got != "✅ Release v1.2.0 with 3 file(s)" || u.tag != "v1.2.0" {
+		t.Errorf("expected the release to be created, got %q", got)
+	}
+
+	result.Version = "v1.2.0-1-gabc1234"
+	if _, err := g.UploadRelease(result); err == nil || !strings.Contains(err.Error(), "not a tag") {
+		t.Errorf("expected snapshot upload error, got %v", err)
+	}
+}
+
+func TestGoBuildReleaseDryRun(t *testing.T) {
+	dir, cleanup := testCreateGoModule("example.com/single")
+	defer cleanup()
+	defer testChdir(t, dir)()
+
+	g, _ := NewGo(nil)
+	g.SetDryRun(true)
+	result, err := g.BuildRelease([]Target{{"linux", "amd64"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Commands) != 1 || result.Commands[0] != "single" || result.Version != "dev" {
+		t.Errorf("module root should build as one command, got %+v", result)
+	}
+	if _, err := os.Stat("dist"); !os.IsNotExist(err) {
+		t.Error("dry run should not write dist")
+	}
+}
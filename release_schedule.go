@@ -0,0 +1,142 @@
+package devflow
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Release schedules for scheduled releases (release.schedule config)
+const (
+	ScheduleDaily   = "daily"
+	ScheduleWeekly  = "weekly"
+	ScheduleMonthly = "monthly"
+)
+
+// ReleaseCheck is the outcome of checking a release schedule
+type ReleaseCheck struct {
+	Due       bool
+	Reason    string // Why a release is or is not due
+	LatestTag string
+	Tag       string   // Next tag, set when Due
+	Commits   []string // Subjects committed since LatestTag
+}
+
+// ReleaseScheduler is implemented by git clients that can check a schedule
+type ReleaseScheduler interface {
+	CheckReleaseSchedule(schedule string, now time.Time) (ReleaseCheck, error)
+}
+
+// releasePeriod returns the calendar period of t for schedule, in UTC, so
+// a cron job that runs late or twice still releases once per period
+func releasePeriod(schedule string, t time.Time) (string, error) {
+	t = t.UTC()
+	switch schedule {
+	case ScheduleDaily:
+		return t.Format("2006-01-02"), nil
+	case ScheduleWeekly:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week), nil
+	case ScheduleMonthly:
+		return t.Format("2006-01"), nil
+	}
+	return "", fmt.Errorf("invalid release schedule: %q (use daily, weekly or monthly)", schedule)
+}
+
+// UnreleasedCommits returns the subjects committed since the latest tag
+func (g *Git) UnreleasedCommits() ([]string, error) {
+	args := []string{"log", "--no-merges", "--format=%s"}
+	if latest, _ := g.GetLatestTag(); latest != "" {
+		args = append(args, latest+"..HEAD")
+	} else if _, err := RunCommandSilent("git", "rev-parse", "--verify", "-q", "HEAD"); err != nil {
+		return nil, nil // No commits yet
+	}
+	out, err := RunCommandSilent("git", args...)
+	if err != nil || out == "" {
+		return nil, err
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// CheckReleaseSchedule decides whether a scheduled release is due at now:
+// there must be unreleased commits, a clean working tree and no release
+// yet in the current period. The next tag follows the release.bump
+// config (patch by default).
+func (g *Git) CheckReleaseSchedule(schedule string, now time.Time) (ReleaseCheck, error) {
+	current, err := releasePeriod(schedule, now)
+	if err != nil {
+		return ReleaseCheck{}, err
+	}
+
+	var check ReleaseCheck
+	if check.LatestTag, err = g.GetLatestTag(); err != nil {
+		return ReleaseCheck{}, err
+	}
+	if check.Commits, err = g.UnreleasedCommits(); err != nil {
+		return ReleaseCheck{}, err
+	}
+	if len(check.Commits) == 0 {
+		check.Reason = "nothing to release since " + check.LatestTag
+		if check.LatestTag == "" {
+			check.Reason = "nothing to release yet: no commits"
+		}
+		return check, nil
+	}
+
+	if files, err := g.ChangedFiles(); err != nil {
+		return ReleaseCheck{}, err
+	} else if len(files) > 0 {
+		return ReleaseCheck{}, fmt.Errorf("working tree has %d uncommitted change(s); scheduled releases only tag committed work", len(files))
+	}
+
+	if check.LatestTag != "" {
+		date, err := RunCommandSilent("git", "log", "-1", "--format=%cI", check.LatestTag)
+		if err != nil {
+			return ReleaseCheck{}, err
+		}
+		released, err := time.Parse(time.RFC3339, date)
+		if err != nil {
+			return ReleaseCheck{}, fmt.Errorf("tag %s: %w", check.LatestTag, err)
+		}
+		if last, _ := releasePeriod(schedule, released); last == current {
+			check.Reason = fmt.Sprintf("%s already released %s (%s)", check.LatestTag, current, schedule)
+			return check, nil
+		}
+	}
+
+	level := BumpPatch
+	if cfg, err := LoadConfig(g.rootDir); err == nil {
+		level = cfg.String("release.bump", BumpPatch)
+	}
+	if check.Tag, err = BumpVersion(check.LatestTag, level); err != nil {
+		return ReleaseCheck{}, fmt.Errorf("release.bump: %w", err)
+	}
+	check.Due = true
+	check.Reason = fmt.Sprintf("%d unreleased commit(s)", len(check.Commits))
+	return check, nil
+}
+
+// ScheduledRelease runs the full Push pipeline (tests, tag, push,
+// dependents, backup) only when CheckReleaseSchedule says a release is
+// due. An empty schedule reads release.schedule from the config. Meant
+// for cron jobs and scheduled CI workflows.
+func (g *Go) ScheduledRelease(schedule string, skipDependents bool, searchPath string) (_ string, err error) {
+	defer trackMetric("release")(&err)
+	if schedule == "" {
+		schedule = g.Config().String("release.schedule", ScheduleWeekly)
+	}
+	scheduler, ok := g.git.(ReleaseScheduler)
+	if !ok {
+		return "", fmt.Errorf("git client does not support scheduled releases")
+	}
+	check, err := scheduler.CheckReleaseSchedule(schedule, time.Now())
+	if err != nil {
+		return "", err
+	}
+	if !check.Due {
+		return "⏭️ Release skipped: " + check.Reason, nil
+	}
+	g.logger.Info("Releasing", check.Tag+":", check.Reason)
+
+	return g.Push("chore: release "+check.Tag, check.Tag, false, false, skipDependents, false, searchPath)
+}
@@ -0,0 +1,89 @@
+package devflow
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReleasePeriod(t *testing.T) {
+	sun := time.Date(2024, 12, 29, 23, 0, 0, 0, time.UTC)
+	mon := time.Date(2024, 12, 30, 1, 0, 0, 0, time.UTC)
+	if a, _ := releasePeriod(ScheduleWeekly, sun); a != "2024-W52" {
+		t.Errorf("week of %v = %s", sun, a)
+	}
+	if b, _ := releasePeriod(ScheduleWeekly, mon); b != "2025-W01" {
+		t.Errorf("week of %v = %s", mon, b)
+	}
+	if a, _ := releasePeriod(ScheduleMonthly, sun); a != "2024-12" {
+		t.Errorf("month = %s", a)
+	}
+	if _, err := releasePeriod("hourly", sun); err == nil {
+		t.Error("expected error for unknown schedule")
+	}
+}
+
+func TestCheckReleaseScheduleNoCommits(t *testing.T) {
+	repo, cleanup := testCreateGitRepo()
+	defer cleanup()
+	defer testChdir(t, repo)()
+	git, _ := NewGit()
+
+	check, err := git.CheckReleaseSchedule(ScheduleWeekly, time.Now())
+	if err != nil || check.Due || check.Reason != "nothing to release yet: no commits" {
+		t.Fatalf("new repository: %+v, %v", check, err)
+	}
+}
+
+func TestCheckReleaseSchedule(t *testing.T) {
+	defer testPushedRepo(t)()
+	git, _ := NewGit()
+	nextWeek := time.Now().AddDate(0, 0, 8)
+
+	check, err := git.CheckReleaseSchedule(ScheduleWeekly, nextWeek)
+	if err != nil || check.Due || !strings.Contains(check.Reason, "nothing to release") {
+		t.Fatalf("no commits: %+v, %v", check, err)
+	}
+
+	os.WriteFile("a.txt", []byte("a"), 0644)
+	exec.Command("git", "add", ".").Run()
+	exec.Command("git", "commit", "-m", "feat: a").Run()
+	os.WriteFile("b.txt", []byte("b"), 0644)
+	if _, err := git.CheckReleaseSchedule(ScheduleWeekly, nextWeek); err == nil {
+		t.Error("expected error for uncommitted changes")
+	}
+	os.Remove("b.txt")
+
+	// v0.0.1 was tagged this week
+	if check, err = git.CheckReleaseSchedule(ScheduleWeekly, time.Now()); err != nil || check.Due {
+		t.Errorf("same week: %+v, %v", check, err)
+	}
+
+	check, err = git.CheckReleaseSchedule(ScheduleWeekly, nextWeek)
+	if err != nil || !check.Due || check.Tag != "v0.0.2" || len(check.Commits) != 1 || check.Commits[0] != "feat: a" {
+		t.Errorf("next week: %+v, %v", check, err)
+	}
+
+	os.WriteFile(".devflow.yaml", []byte("release:\n  bump: minor\n"), 0644)
+	exec.Command("git", "add", ".").Run()
+	exec.Command("git", "commit", "-m", "chore: config").Run()
+	if check, _ = git.CheckReleaseSchedule(ScheduleWeekly, nextWeek); check.Tag != "v0.1.0" {
+		t.Errorf("release.bump minor: tag = %s", check.Tag)
+	}
+}
+
+func TestScheduledReleaseSkips(t *testing.T) {
+	defer testPushedRepo(t)()
+	git, _ := NewGit()
+	g, _ := NewGo(git)
+
+	summary, err := g.ScheduledRelease(ScheduleDaily, true, "")
+	if err != nil || !strings.HasPrefix(summary, "⏭️ Release skipped: nothing to release since v0.0.1") {
+		t.Errorf("summary = %q, %v", summary, err)
+	}
+	if _, err := g.ScheduledRelease("hourly", true, ""); err == nil {
+		t.Error("expected error for unknown schedule")
+	}
+}
@@ -0,0 +1,149 @@
+package devflow
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testReleaser records the releases it is asked to create
+type testReleaser struct {
+	tag, notes string
+	assets     []string
+}
+
+func (r *testReleaser) CreateRelease(tag, notes string, assets []string) (string, error) {
+	r.tag, r.notes, r.assets = tag, notes, assets
+	return "https://github.com/o/r/releases/tag/" + tag, nil
+}
+
+func TestGoCreateRelease(t *testing.T) {
+	t.Setenv("GH_HOST", "")
+	dir, cleanup := testCreateGitRepo()
+	defer cleanup()
+	defer testChdir(t, dir)()
+	exec.Command("git", "remote", "add", "origin", "https://github.com/o/r.git").Run()
+	os.WriteFile("README.md", []byte("# r"), 0644)
+	os.MkdirAll("dist", 0755)
+	os.WriteFile(filepath.Join("dist", "r.tar.gz"), []byte("x"), 0644)
+	os.WriteFile(".devflow.yaml", []byte("release:\n  assets: [dist/*.tar.gz]\n"), 0644)
+	exec.Command("git", "add", ".").Run()
+	exec.Command("git", "commit", "-m", "initial").Run()
+
+	git, _ := NewGit()
+	if _, err := RunCommand("git", "tag", "-a", "--cleanup=whitespace", "v1.0.0", "-m", tagMessage("v1.0.0", "### Added\n- release notes")); err != nil {
+		t.Fatal(err)
+	}
+	if notes, _ := git.TagNotes("v1.0.0"); notes != "### Added\n- release notes" {
+		t.Errorf("TagNotes() = %q", notes)
+	}
+	exec.Command("git", "tag", "v1.0.1").Run()
+	if notes, _ := git.TagNotes("v1.0.1"); notes != "" {
+		t.Errorf("lightweight tag should have no notes, got %q", notes)
+	}
+
+	g, _ := NewGo(git)
+	r := &testReleaser{}
+	g.SetReleaser(r)
+	if got := g.createRelease("v1.0.0"); got != "✅ Release v1.0.0" {
+		t.Errorf("createRelease() = %q", got)
+	}
+	if r.notes != "### Added\n- release notes" || len(r.assets) != 1 || filepath.Base(r.assets[0]) != "r.tar.gz" {
+		t.Errorf("unexpected release: %+v", r)
+	}
+
+	r.tag = ""
+	g.SetRelease(false)
+	if got := g.createRelease("v1.0.1"); got != "" || r.tag != "" {
+		t.Errorf("disabled release should be skipped, got %q", got)
+	}
+
+	// Other providers get no release
+	g.SetRelease(true)
+	exec.Command("git", "remote", "set-url", "origin", "https://gitlab.com/o/r.git").Run()
+	if got := g.createRelease("v1.0.1"); got != "" || r.tag != "" {
+		t.Errorf("non-GitHub remote should be skipped, got %q", got)
+	}
+}
+
+func TestGoReleaseAssetsMissing(t *testing.T) {
+	dir, cleanup := testCreateGoModule("assets")
+	defer cleanup()
+	defer testChdir(t, dir)()
+	os.WriteFile(".devflow.yaml", []byte("release:\n  assets: [dist/*.zip]\n"), 0644)
+
+	g, _ := NewGo(nil)
+	if _, err := g.releaseAssets(); err == nil || !strings.Contains(err.Error(), "dist/*.zip") {
+		t.Errorf("expected no match error, got %v", err)
+	}
+}
+
+func TestGoMirrorRelease(t *testing.T) {
+	defer testPushedRepo(t)()
+	mirror := t.TempDir()
+	exec.Command("git", "init", "--bare", mirror).Run()
+	os.WriteFile(".devflow.yaml", []byte("release:\n  mirror:\n    gitlab: platform/r\n    host: gitlab.example.com\n    remote: file://"+mirror+"\n"), 0644)
+
+	git, _ := NewGit()
+	g, _ := NewGo(git)
+	r := &testReleaser{}
+	g.SetMirrorReleaser(r)
+
+	// origin is not GitHub: only the mirror gets the release
+	if got := g.createRelease("v0.0.1"); got != "✅ Release v0.0.1: GitLab ok" {
+		t.Errorf("createRelease() = %q", got)
+	}
+	if r.tag != "v0.0.1" {
+		t.Errorf("mirror release not created: %+v", r)
+	}
+	if out, _ := RunCommandInDir(mirror, "git", "tag"); out != "v0.0.1" {
+		t.Errorf("tag not pushed to the mirror: %q", out)
+	}
+
+	os.WriteFile(".devflow.yaml", []byte("release:\n  mirror:\n    gitlab: platform/r\n    host: gitlab.example.com\n    remote: file:///nonexistent/mirror.git\n"), 0644)
+	g, _ = NewGo(git)
+	g.SetMirrorReleaser(r)
+	if got := g.createRelease("v0.0.1"); !strings.HasPrefix(got, "⚠️ Release v0.0.1: GitLab failed (push to file:///nonexistent/mirror.git") {
+		t.Errorf("expected mirror push failure, got %q", got)
+	}
+}
+
+func TestGitLabCreateReleaseREST(t *testing.T) {
+	var release map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/projects/platform%2Fr/uploads":
+			if _, _, err := r.FormFile("file"); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte(`{"url":"/uploads/abc/r.tar.gz"}`))
+		case "/projects/platform%2Fr/releases":
+			json.NewDecoder(r.Body).Decode(&release)
+			w.Write([]byte(`{"_links":{"self":"https://gitlab.example.com/platform/r/-/releases/v1.0.0"}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	asset := filepath.Join(t.TempDir(), "r.tar.gz")
+	os.WriteFile(asset, []byte("x"), 0644)
+	gl := &GitLab{host: "gitlab.example.com", rest: newRESTClient(srv.URL, "PRIVATE-TOKEN", "t"), log: func(...any) {}}
+	url, err := gl.ProjectReleaser("platform/r").CreateRelease("v1.0.0", "notes", []string{asset})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if url != "https://gitlab.example.com/platform/r/-/releases/v1.0.0" || release["description"] != "notes" {
+		t.Errorf("unexpected release %s: %v", url, release)
+	}
+	links := release["assets"].(map[string]any)["links"].([]any)
+	if link := links[0].(map[string]any); link["url"] != "https://gitlab.example.com/platform/r/uploads/abc/r.tar.gz" {
+		t.Errorf("unexpected asset link: %v", link)
+	}
+}
@@ -0,0 +1,196 @@
+package devflow
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RemoteAccessKind classifies why the remote cannot be reached
+type RemoteAccessKind string
+
+const (
+	RemoteMissing  RemoteAccessKind = "missing"  // No origin remote
+	RemoteDNS      RemoteAccessKind = "dns"      // Host name does not resolve
+	RemoteNetwork  RemoteAccessKind = "network"  // Host resolves but does not answer
+	RemoteAuth     RemoteAccessKind = "auth"     // Credentials rejected
+	RemoteNotFound RemoteAccessKind = "notfound" // No repository at the URL
+	RemoteProtocol RemoteAccessKind = "protocol" // Credentials only work over the other protocol
+	RemoteUnknown  RemoteAccessKind = "unknown"
+)
+
+// RemoteAccessError is returned by CheckRemoteAccess: what failed, for
+// which URL, and the command that fixes it
+type RemoteAccessError struct {
+	Kind   RemoteAccessKind
+	Remote string // e.g. "origin"
+	URL    string
+	Detail string // First line of the git error
+	Remedy string // Command to run, "" when there is none
+}
+
+func (e *RemoteAccessError) Error() string {
+	var msg string
+	switch e.Kind {
+	case RemoteMissing:
+		msg = fmt.Sprintf("Remote '%s' is not configured", e.Remote)
+	case RemoteDNS:
+		msg = fmt.Sprintf("Network error: cannot resolve the host of %s", e.URL)
+	case RemoteNetwork:
+		msg = fmt.Sprintf("Network error: %s is unreachable", e.URL)
+	case RemoteAuth:
+		msg = fmt.Sprintf("Authentication failed for %s", e.URL)
+	case RemoteNotFound:
+		msg = fmt.Sprintf("Repository %s not found (or no access to it)", e.URL)
+	case RemoteProtocol:
+		msg = fmt.Sprintf("Authentication failed for %s, but your credentials work over %s", e.URL, otherProtocol(e.URL))
+	default:
+		msg = fmt.Sprintf("checking remote access to %s failed: %s", e.URL, e.Detail)
+	}
+	if e.Kind != RemoteUnknown && e.Detail != "" {
+		msg += " (" + e.Detail + ")"
+	}
+	if e.Remedy != "" {
+		msg += "\n   Run: " + e.Remedy
+	}
+	return "❌ " + msg
+}
+
+// CheckRemoteAccess verifies connectivity to the remote repository. On
+// failure it returns a *RemoteAccessError telling DNS failures, rejected
+// credentials, missing repositories and ssh/https mismatches apart.
+func (g *Git) CheckRemoteAccess() error {
+	url, err := RunCommandSilent("git", "remote", "get-url", "origin")
+	if err != nil || url == "" {
+		return &RemoteAccessError{Kind: RemoteMissing, Remote: "origin", Remedy: "git remote add origin <url>"}
+	}
+	// Fail fast when the host is unreachable, instead of after git's timeouts
+	if host, _, _, ok := ParseRemoteURL(url); ok && (!isSSHRemote(url) || host == (&GitHub{}).Host()) {
+		if st := probeNetwork(host, 0); !st.Online {
+			kind := RemoteNetwork
+			if !st.Resolved {
+				kind = RemoteDNS
+			}
+			e := &RemoteAccessError{Kind: kind, Remote: "origin", URL: url, Detail: st.Reason}
+			if kind == RemoteDNS {
+				e.Remedy = "nslookup " + host
+			}
+			return e
+		}
+	}
+	// git ls-remote origin checks access without needing upstream configured
+	out, err := RunCommandWithEnvInDir("", remoteProbeEnv(), "git", "ls-remote", "origin")
+	if err == nil {
+		return nil
+	}
+	return diagnoseRemoteAccess("origin", url, out, probeRemote)
+}
+
+// remoteProbeEnv makes git fail instead of prompting for credentials,
+// passphrases or unknown host keys
+func remoteProbeEnv() []string {
+	ssh, _ := RunCommandSilent("git", "config", "--get", "core.sshCommand")
+	if ssh == "" {
+		ssh = "ssh"
+	}
+	return []string{"GIT_TERMINAL_PROMPT=0", "GIT_SSH_COMMAND=" + ssh + " -o BatchMode=yes -o ConnectTimeout=10"}
+}
+
+// probeRemote reports whether git ls-remote of url succeeds
+func probeRemote(url string) bool {
+	_, err := RunCommandWithEnvInDir("", remoteProbeEnv(), "git", "ls-remote", "--heads", url)
+	return err == nil
+}
+
+// diagnoseRemoteAccess classifies the output of a failed git ls-remote.
+// Rejected credentials are retried over the other protocol with probe.
+func diagnoseRemoteAccess(remote, url, out string, probe func(string) bool) *RemoteAccessError {
+	e := &RemoteAccessError{Kind: RemoteUnknown, Remote: remote, URL: url, Detail: remoteErrorLine(out)}
+	lower := strings.ToLower(out)
+	host, owner, name, parsed := ParseRemoteURL(url)
+	switch {
+	case strings.Contains(lower, "could not resolve host") || strings.Contains(lower, "could not resolve hostname") ||
+		strings.Contains(lower, "name or service not known") || strings.Contains(lower, "temporary failure in name resolution") ||
+		strings.Contains(lower, "nodename nor servname"):
+		e.Kind = RemoteDNS
+		if parsed {
+			e.Remedy = "nslookup " + host
+		}
+	case strings.Contains(lower, "connection timed out") || strings.Contains(lower, "operation timed out") ||
+		strings.Contains(lower, "connection refused") || strings.Contains(lower, "network is unreachable"):
+		e.Kind = RemoteNetwork
+	case strings.Contains(lower, "repository not found") || strings.Contains(lower, "does not appear to be a git repository") ||
+		strings.Contains(lower, "project you were looking for could not be found") || strings.Contains(lower, "returned error: 404"):
+		e.Kind = RemoteNotFound
+		e.Remedy = fmt.Sprintf("git remote set-url %s <correct-url>", remote)
+		if parsed && host == (&GitHub{}).Host() {
+			e.Remedy = fmt.Sprintf("gh repo create %s/%s --private", owner, name)
+		}
+	case strings.Contains(lower, "permission denied") || strings.Contains(lower, "authentication failed") ||
+		strings.Contains(lower, "could not read username") || strings.Contains(lower, "terminal prompts disabled") ||
+		strings.Contains(lower, "invalid username or password") || strings.Contains(lower, "host key verification failed") ||
+		strings.Contains(lower, "returned error: 403"):
+		e.Kind = RemoteAuth
+		e.Remedy = authRemedy(url, host)
+		if !parsed {
+			break
+		}
+		other := fmt.Sprintf("git@%s:%s/%s.git", host, owner, name)
+		if isSSHRemote(url) {
+			other = fmt.Sprintf("https://%s/%s/%s.git", host, owner, name)
+		}
+		if probe != nil && probe(other) {
+			e.Kind = RemoteProtocol
+			e.Remedy = fmt.Sprintf("git remote set-url %s %s", remote, other)
+		}
+	}
+	return e
+}
+
+// authRemedy returns the command that sets up credentials for url
+func authRemedy(url, host string) string {
+	if isSSHRemote(url) {
+		if strings.Contains(host, "github") {
+			return "devflow keys setup"
+		}
+		return "ssh-keygen -t ed25519 && ssh-add, then add ~/.ssh/id_ed25519.pub to " + host
+	}
+	switch {
+	case host == (&GitHub{}).Host():
+		return "gh auth login && gh auth setup-git"
+	case strings.Contains(host, "gitlab"):
+		return "glab auth login --hostname " + host
+	}
+	return "git config --global credential.helper cache"
+}
+
+// isSSHRemote reports whether url uses ssh (git@host:path or ssh://)
+func isSSHRemote(url string) bool {
+	return strings.HasPrefix(url, "ssh://") || (!strings.Contains(url, "://") && strings.Contains(url, "@"))
+}
+
+// otherProtocol names the protocol url does not use
+func otherProtocol(url string) string {
+	if isSSHRemote(url) {
+		return "https"
+	}
+	return "ssh"
+}
+
+// remoteErrorLine picks the most telling line of git's error output
+func remoteErrorLine(out string) string {
+	var first string
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "Output:") || strings.HasPrefix(line, "exit status") {
+			continue
+		}
+		if strings.HasPrefix(line, "fatal:") || strings.HasPrefix(line, "ERROR:") || strings.HasPrefix(line, "remote:") ||
+			strings.Contains(line, "denied") || strings.Contains(line, "resolve") {
+			return line
+		}
+		if first == "" {
+			first = line
+		}
+	}
+	return first
+}
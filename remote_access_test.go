@@ -0,0 +1,69 @@
+package devflow
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestDiagnoseRemoteAccess(t *testing.T) {
+	tests := []struct {
+		url, out string
+		probe    bool // Other protocol works
+		kind     RemoteAccessKind
+		remedy   string
+	}{
+		{"https://github.com/o/r.git", "fatal: unable to access 'https://github.com/o/r.git/': Could not resolve host: github.com", false, RemoteDNS, "nslookup github.com"},
+		{"git@github.com:o/r.git", "ssh: Could not resolve hostname github.com: Temporary failure in name resolution\nfatal: Could not read from remote repository.", false, RemoteDNS, "nslookup github.com"},
+		{"git@github.com:o/r.git", "ssh: connect to host github.com port 22: Connection timed out", false, RemoteNetwork, ""},
+		{"git@github.com:o/r.git", "git@github.com: Permission denied (publickey).\nfatal: Could not read from remote repository.", false, RemoteAuth, "devflow keys setup"},
+		{"git@github.com:o/r.git", "git@github.com: Permission denied (publickey).", true, RemoteProtocol, "git remote set-url origin https://github.com/o/r.git"},
+		{"https://github.com/o/r.git", "fatal: could not read Username for 'https://github.com': terminal prompts disabled", false, RemoteAuth, "gh auth login && gh auth setup-git"},
+		{"https://gitlab.example.com/g/r.git", "remote: HTTP Basic: Access denied\nfatal: Authentication failed for 'https://gitlab.example.com/g/r.git/'", true, RemoteProtocol, "git remote set-url origin git@gitlab.example.com:g/r.git"},
+		{"https://github.com/o/r.git", "remote: Repository not found.\nfatal: repository 'https://github.com/o/r.git/' not found", false, RemoteNotFound, "gh repo create o/r --private"},
+		{"file:///srv/r.git", "fatal: '/srv/r.git' does not appear to be a git repository", false, RemoteNotFound, "git remote set-url origin <correct-url>"},
+		{"https://github.com/o/r.git", "fatal: something else", false, RemoteUnknown, ""},
+	}
+	for _, tt := range tests {
+		var probed string
+		e := diagnoseRemoteAccess("origin", tt.url, tt.out, func(url string) bool { probed = url; return tt.probe })
+		if e.Kind != tt.kind || e.Remedy != tt.remedy {
+			t.Errorf("%s %q: got %s %q, want %s %q", tt.url, tt.out, e.Kind, e.Remedy, tt.kind, tt.remedy)
+		}
+		if tt.kind != RemoteAuth && tt.kind != RemoteProtocol && probed != "" {
+			t.Errorf("%s: only rejected credentials should probe the other protocol, probed %s", tt.url, probed)
+		}
+	}
+
+	e := diagnoseRemoteAccess("origin", "git@github.com:o/r.git", "git@github.com: Permission denied (publickey).", func(string) bool { return true })
+	if msg := e.Error(); !strings.Contains(msg, "work over https") || !strings.HasSuffix(msg, "Run: git remote set-url origin https://github.com/o/r.git") {
+		t.Errorf("unexpected message: %s", msg)
+	}
+}
+
+func TestCheckRemoteAccess(t *testing.T) {
+	dir, cleanup := testCreateGitRepo()
+	defer cleanup()
+	defer testChdir(t, dir)()
+	git, _ := NewGit()
+
+	var rae *RemoteAccessError
+	if err := git.CheckRemoteAccess(); !errors.As(err, &rae) || rae.Kind != RemoteMissing {
+		t.Errorf("expected missing remote, got %v", err)
+	}
+
+	exec.Command("git", "remote", "add", "origin", "file:///nonexistent/repo.git").Run()
+	if err := git.CheckRemoteAccess(); !errors.As(err, &rae) || rae.Kind != RemoteNotFound {
+		t.Errorf("expected missing repository, got %v", err)
+	}
+
+	remote, _ := os.MkdirTemp("", "gitgo-remote-access-")
+	defer os.RemoveAll(remote)
+	exec.Command("git", "init", "--bare", remote).Run()
+	exec.Command("git", "remote", "set-url", "origin", "file://"+remote).Run()
+	if err := git.CheckRemoteAccess(); err != nil {
+		t.Errorf("expected access, got %v", err)
+	}
+}
@@ -0,0 +1,247 @@
+package devflow
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Environment variables read by the remote test cache. Credentials are
+// never read from .devflow.yaml, which is usually committed.
+const (
+	remoteCacheURLEnv    = "DEVFLOW_CACHE_URL"    // Overrides cache.remote.url
+	remoteCacheTokenEnv  = "DEVFLOW_CACHE_TOKEN"  // Bearer token for http(s) backends
+	remoteCacheSecretEnv = "DEVFLOW_CACHE_SECRET" // Key signing and verifying entries
+)
+
+// remoteCacheHeader is the first line of every remote cache entry
+const remoteCacheHeader = "devflow-cache v1"
+
+// RemoteCache shares successful test results for identical git states
+// between machines. Entries are signed with HMAC-SHA256 using a secret
+// shared by CI and the team; entries that fail verification are ignored,
+// so write access to the store alone is not enough to skip tests.
+//
+//	cache:
+//	  remote:
+//	    url: https://cache.example.com/gotest   # or s3://bucket/prefix
+//	    region: eu-west-1                       # s3 only, default AWS_REGION
+//	    endpoint: https://minio.example.com     # s3-compatible stores
+//
+// http(s) backends receive GET and PUT requests with the bearer token from
+// DEVFLOW_CACHE_TOKEN; s3 requests are signed with the AWS_* credentials.
+type RemoteCache struct {
+	baseURL string // http(s) base URL, or the bucket URL for s3
+	prefix  string // s3 key prefix
+	token   string
+	secret  []byte
+	s3      *s3Credentials
+	http    *http.Client
+}
+
+// s3Credentials sign S3 requests (AWS Signature Version 4)
+type s3Credentials struct {
+	accessKey    string
+	secretKey    string
+	sessionToken string
+	region       string
+}
+
+// LoadRemoteCache reads cache.remote.* from c. It returns nil when no
+// remote cache is configured, and an error when one is configured without
+// a signing secret.
+func LoadRemoteCache(c *Config) (*RemoteCache, error) {
+	if c == nil {
+		c = NewConfig()
+	}
+	rawURL := os.Getenv(remoteCacheURLEnv)
+	if rawURL == "" {
+		rawURL = c.String("cache.remote.url", "")
+	}
+	if rawURL == "" {
+		return nil, nil
+	}
+	secret := os.Getenv(remoteCacheSecretEnv)
+	if secret == "" {
+		return nil, fmt.Errorf("remote test cache: %s is required to sign and verify entries", remoteCacheSecretEnv)
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("cache.remote.url: %w", err)
+	}
+	rc := &RemoteCache{secret: []byte(secret), http: &http.Client{Timeout: 30 * time.Second}}
+
+	switch u.Scheme {
+	case "http", "https":
+		rc.baseURL = strings.TrimRight(rawURL, "/")
+		rc.token = os.Getenv(remoteCacheTokenEnv)
+	case "s3":
+		if u.Host == "" {
+			return nil, fmt.Errorf("cache.remote.url: missing bucket in %q", rawURL)
+		}
+		region := c.String("cache.remote.region", os.Getenv("AWS_REGION"))
+		if region == "" {
+			region = "us-east-1"
+		}
+		endpoint := strings.TrimRight(c.String("cache.remote.endpoint", ""), "/")
+		if endpoint == "" {
+			endpoint = "https://s3." + region + ".amazonaws.com"
+		}
+		rc.baseURL = endpoint + "/" + u.Host
+		rc.prefix = strings.Trim(u.Path, "/")
+		rc.s3 = &s3Credentials{
+			accessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+			secretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			sessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+			region:       region,
+		}
+		if rc.s3.accessKey == "" || rc.s3.secretKey == "" {
+			return nil, fmt.Errorf("remote test cache: AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are required for %s", rawURL)
+		}
+	default:
+		return nil, fmt.Errorf("cache.remote.url: unsupported scheme %q (want http, https or s3)", u.Scheme)
+	}
+	return rc, nil
+}
+
+// entryKey is the object name of the entry for a cache key and git state
+func (rc *RemoteCache) entryKey(key, state string) string {
+	sum := sha256.Sum256([]byte(state))
+	name := key + "/" + hex.EncodeToString(sum[:16])
+	if rc.prefix != "" {
+		name = rc.prefix + "/" + name
+	}
+	return name
+}
+
+// sign returns the signature of an entry's payload, bound to its name so
+// an entry cannot be replayed under another module or toolchain
+func (rc *RemoteCache) sign(name string, payload []byte) string {
+	mac := hmac.New(sha256.New, rc.secret)
+	mac.Write([]byte(name + "\n"))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Get returns the verified payload stored for key and state. found is
+// false when there is no entry; entries with a bad signature are errors.
+func (rc *RemoteCache) Get(key, state string) (payload []byte, found bool, err error) {
+	name := rc.entryKey(key, state)
+	data, status, err := rc.request("GET", name, nil)
+	if err != nil {
+		if status == http.StatusNotFound || status == http.StatusForbidden && rc.s3 != nil {
+			return nil, false, nil // S3 answers 403 for missing keys without list permission
+		}
+		return nil, false, err
+	}
+
+	header, rest, _ := strings.Cut(string(data), "\n")
+	sig, body, ok := strings.Cut(rest, "\n")
+	if header != remoteCacheHeader || !ok {
+		return nil, false, fmt.Errorf("remote cache entry %s: unknown format", name)
+	}
+	if !hmac.Equal([]byte(sig), []byte(rc.sign(name, []byte(body)))) {
+		return nil, false, fmt.Errorf("remote cache entry %s: signature mismatch", name)
+	}
+	return []byte(body), true, nil
+}
+
+// Put stores a signed payload for key and state
+func (rc *RemoteCache) Put(key, state string, payload []byte) error {
+	name := rc.entryKey(key, state)
+	entry := remoteCacheHeader + "\n" + rc.sign(name, payload) + "\n" + string(payload)
+	_, _, err := rc.request("PUT", name, []byte(entry))
+	return err
+}
+
+func (rc *RemoteCache) request(method, name string, body []byte) ([]byte, int, error) {
+	req, err := http.NewRequest(method, rc.baseURL+"/"+name, bytes.NewReader(body))
+	if err != nil {
+		return nil, 0, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	}
+	if rc.s3 != nil {
+		rc.s3.sign(req, body, time.Now())
+	} else if rc.token != "" {
+		req.Header.Set("Authorization", "Bearer "+rc.token)
+	}
+
+	resp, err := rc.http.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode >= 300 {
+		return nil, resp.StatusCode, fmt.Errorf("%s %s: %s", method, name, resp.Status)
+	}
+	return data, resp.StatusCode, nil
+}
+
+// sign adds AWS Signature Version 4 headers to req. The host and every
+// header already set on req are signed.
+func (s *s3Credentials) sign(req *http.Request, body []byte, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256.Sum256(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payloadHash[:]))
+	if s.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.sessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		headers[strings.ToLower(k)] = strings.TrimSpace(strings.Join(v, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := []byte("AWS4" + s.secretKey)
+	for _, part := range []string{date, s.region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,173 @@
+package devflow
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Merge strategies of RepoSettings.MergeStrategies
+const (
+	MergeSquash = "squash"
+	MergeCommit = "merge"
+	MergeRebase = "rebase"
+)
+
+// RepoSettings are the repository settings applied after a new project
+// is pushed (see RepoConfigurer). Zero fields keep the provider defaults.
+type RepoSettings struct {
+	Topics          []string
+	Homepage        string
+	DefaultBranch   string   // Also the branch ProtectBranch protects (gonew: the pushed branch)
+	MergeStrategies []string // Allowed pull request merges, e.g. [squash] for squash-only
+	ProtectBranch   bool     // Protect the default branch: no force pushes or deletion, changes through pull requests (admins may still push)
+	Issues          *bool
+	Wiki            *bool
+}
+
+// IsZero reports whether s changes nothing
+func (s RepoSettings) IsZero() bool {
+	return len(s.Topics) == 0 && s.Homepage == "" && s.DefaultBranch == "" && len(s.MergeStrategies) == 0 &&
+		!s.ProtectBranch && s.Issues == nil && s.Wiki == nil
+}
+
+// String returns the settings that change, e.g. "topics go, cli; merge squash; protect main"
+func (s RepoSettings) String() string {
+	var parts []string
+	if len(s.Topics) > 0 {
+		parts = append(parts, "topics "+strings.Join(s.Topics, ", "))
+	}
+	if s.Homepage != "" {
+		parts = append(parts, "homepage "+s.Homepage)
+	}
+	if s.DefaultBranch != "" {
+		parts = append(parts, "default branch "+s.DefaultBranch)
+	}
+	if len(s.MergeStrategies) > 0 {
+		parts = append(parts, "merge "+strings.Join(s.MergeStrategies, ", "))
+	}
+	if s.ProtectBranch {
+		parts = append(parts, "protect "+s.DefaultBranch)
+	}
+	if s.Issues != nil {
+		parts = append(parts, fmt.Sprintf("issues %s", onOff(*s.Issues)))
+	}
+	if s.Wiki != nil {
+		parts = append(parts, fmt.Sprintf("wiki %s", onOff(*s.Wiki)))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// onOff returns "on" or "off"
+func onOff(b bool) string {
+	if b {
+		return "on"
+	}
+	return "off"
+}
+
+// Validate checks the merge strategies and topics
+func (s RepoSettings) Validate() error {
+	for _, m := range s.MergeStrategies {
+		if m != MergeSquash && m != MergeCommit && m != MergeRebase {
+			return fmt.Errorf("invalid merge strategy %q (want %s, %s or %s)", m, MergeSquash, MergeCommit, MergeRebase)
+		}
+	}
+	for _, topic := range s.Topics {
+		if !validTopic(topic) {
+			return fmt.Errorf("invalid topic %q: lowercase letters, digits and hyphens, at most 50 characters", topic)
+		}
+	}
+	return nil
+}
+
+// validTopic reports whether topic is a valid GitHub topic
+func validTopic(topic string) bool {
+	if topic == "" || len(topic) > 50 || topic[0] == '-' {
+		return false
+	}
+	for _, r := range topic {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// LoadRepoSettings returns the gonew.repo.* defaults of c for settings
+// that opts leave unset
+func LoadRepoSettings(c *Config, s RepoSettings) RepoSettings {
+	if len(s.MergeStrategies) == 0 {
+		s.MergeStrategies = c.List("gonew.repo.merge")
+	}
+	if !s.ProtectBranch {
+		s.ProtectBranch = c.Bool("gonew.repo.protect_branch", false)
+	}
+	if s.Issues == nil && c.Has("gonew.repo.issues") {
+		v := c.Bool("gonew.repo.issues", true)
+		s.Issues = &v
+	}
+	if s.Wiki == nil && c.Has("gonew.repo.wiki") {
+		v := c.Bool("gonew.repo.wiki", true)
+		s.Wiki = &v
+	}
+	return s
+}
+
+// ConfigureRepo applies s to owner/name with the GitHub API. Every
+// setting is tried; the errors of the failed ones are joined.
+func (gh *GitHub) ConfigureRepo(owner, name string, s RepoSettings) error {
+	repo := "repos/" + owner + "/" + name
+	var errs []error
+	api := func(what string, args ...string) {
+		if out, err := RunCommand("gh", append([]string{"api", "--silent"}, args...)...); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s", what, firstLine(out)))
+		}
+	}
+
+	var fields []string
+	if s.Homepage != "" {
+		fields = append(fields, "-f", "homepage="+s.Homepage)
+	}
+	if s.DefaultBranch != "" {
+		fields = append(fields, "-f", "default_branch="+s.DefaultBranch)
+	}
+	if len(s.MergeStrategies) > 0 {
+		allowed := map[string]bool{}
+		for _, m := range s.MergeStrategies {
+			allowed[m] = true
+		}
+		fields = append(fields,
+			"-F", fmt.Sprintf("allow_squash_merge=%t", allowed[MergeSquash]),
+			"-F", fmt.Sprintf("allow_merge_commit=%t", allowed[MergeCommit]),
+			"-F", fmt.Sprintf("allow_rebase_merge=%t", allowed[MergeRebase]))
+	}
+	if s.Issues != nil {
+		fields = append(fields, "-F", fmt.Sprintf("has_issues=%t", *s.Issues))
+	}
+	if s.Wiki != nil {
+		fields = append(fields, "-F", fmt.Sprintf("has_wiki=%t", *s.Wiki))
+	}
+	if len(fields) > 0 {
+		api("repository settings", append([]string{"-X", "PATCH", repo}, fields...)...)
+	}
+
+	if len(s.Topics) > 0 {
+		args := []string{"-X", "PUT", repo + "/topics"}
+		for _, topic := range s.Topics {
+			args = append(args, "-f", "names[]="+topic)
+		}
+		api("topics", args...)
+	}
+
+	if s.ProtectBranch && s.DefaultBranch != "" {
+		api("branch protection", "-X", "PUT", repo+"/branches/"+s.DefaultBranch+"/protection",
+			"-F", "required_status_checks=null",
+			"-F", "enforce_admins=false",
+			"-F", "required_pull_request_reviews[required_approving_review_count]=0",
+			"-F", "restrictions=null",
+			"-F", "allow_force_pushes=false",
+			"-F", "allow_deletions=false")
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,99 @@
+package devflow
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigureRepo(t *testing.T) {
+	var calls []string
+	defer UseMiddleware(func(next CommandRunner) CommandRunner {
+		return func(cmd *Command) (string, error) {
+			calls = append(calls, cmd.String())
+			if strings.Contains(cmd.String(), "/protection") {
+				return "gh: Upgrade to GitHub Pro (HTTP 403)", errors.New("exit status 1")
+			}
+			return "", nil
+		}
+	})()
+
+	off := false
+	s := RepoSettings{Topics: []string{"go", "cli"}, MergeStrategies: []string{MergeSquash}, ProtectBranch: true, DefaultBranch: "main", Wiki: &off}
+	err := (&GitHub{}).ConfigureRepo("me", "lib", s)
+	if err == nil || !strings.Contains(err.Error(), "branch protection: gh: Upgrade to GitHub Pro (HTTP 403)") {
+		t.Errorf("expected the protection error, got %v", err)
+	}
+	want := []string{
+		"gh api --silent -X PATCH repos/me/lib -f default_branch=main -F allow_squash_merge=true -F allow_merge_commit=false -F allow_rebase_merge=false -F has_wiki=false",
+		"gh api --silent -X PUT repos/me/lib/topics -f names[]=go -f names[]=cli",
+	}
+	if len(calls) != 3 || calls[0] != want[0] || calls[1] != want[1] || !strings.HasPrefix(calls[2], "gh api --silent -X PUT repos/me/lib/branches/main/protection ") {
+		t.Errorf("unexpected gh calls:\n%s", strings.Join(calls, "\n"))
+	}
+	if got := s.String(); got != "topics go, cli; default branch main; merge squash; protect main; wiki off" {
+		t.Errorf("String() = %q", got)
+	}
+
+	if err := (RepoSettings{MergeStrategies: []string{"fast-forward"}}).Validate(); err == nil {
+		t.Error("expected invalid merge strategy error")
+	}
+	if err := (RepoSettings{Topics: []string{"Go"}}).Validate(); err == nil {
+		t.Error("expected invalid topic error")
+	}
+
+	cfg, _ := ParseConfig("gonew:\n  repo:\n    merge: [squash]\n    issues: false\n")
+	loaded := LoadRepoSettings(cfg, RepoSettings{})
+	if strings.Join(loaded.MergeStrategies, ",") != MergeSquash || loaded.Issues == nil || *loaded.Issues || loaded.Wiki != nil {
+		t.Errorf("unexpected config settings %+v", loaded)
+	}
+}
+
+// testConfigurerProvider records the settings gonew applies
+type testConfigurerProvider struct {
+	*testProvider
+	applied []string
+}
+
+func (p *testConfigurerProvider) ConfigureRepo(owner, name string, s RepoSettings) error {
+	p.applied = append(p.applied, owner+"/"+name+": "+s.String())
+	return nil
+}
+
+func TestGoNewRepoSettings(t *testing.T) {
+	tmp := testResumeEnv(t)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmp, ".config"))
+	git, err := NewGit()
+	if err != nil {
+		t.Skip("git not installed")
+	}
+	goHandler, _ := NewGo(git)
+	remotes := filepath.Join(tmp, "remotes")
+	if err := exec.Command("git", "init", "--bare", filepath.Join(remotes, "set-lib.git")).Run(); err != nil {
+		t.Fatal(err)
+	}
+	provider := &testConfigurerProvider{testProvider: &testProvider{dir: remotes}}
+	gn := NewGoNew(git, NewResolvedFuture(provider), goHandler)
+
+	opts := NewProjectOptions{Name: "set-lib", Description: "Settings", Owner: "tester", Directory: filepath.Join(tmp, "set-lib"), DefaultBranch: "main", Community: true,
+		Repo: RepoSettings{Topics: []string{"go"}, ProtectBranch: true}}
+	summary, err := gn.Create(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(provider.applied) != 1 || provider.applied[0] != "tester/set-lib: topics go; default branch main; protect main" || strings.Contains(summary, "settings") {
+		t.Errorf("expected the settings applied after the push, got %v (%q)", provider.applied, summary)
+	}
+	if _, err := os.Stat(filepath.Join(opts.Directory, "CONTRIBUTING.md")); err != nil {
+		t.Errorf("expected CONTRIBUTING.md: %v", err)
+	}
+
+	opts.Name, opts.Directory = "bad-lib", filepath.Join(tmp, "bad-lib")
+	opts.Repo.Topics = []string{"Bad Topic"}
+	if _, err := gn.Create(opts); err == nil || !strings.Contains(err.Error(), "invalid topic") {
+		t.Errorf("expected invalid topic error, got %v", err)
+	}
+}
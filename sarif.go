@@ -0,0 +1,339 @@
+package devflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Finding is a single issue reported by vet, staticcheck or the secret scan
+type Finding struct {
+	Tool    string // "go vet", "staticcheck", "secret-scan"
+	Rule    string // Analyzer or check id (e.g. "printf", "SA4006")
+	File    string // Path relative to the repository root
+	Line    int
+	Column  int
+	Message string
+	Level   string // SARIF level: "error", "warning" or "note"
+
+	BaselineState string // "new" or "unchanged" when a baseline exists
+}
+
+// toolURIs are the documentation links of each tool in the SARIF driver
+var toolURIs = map[string]string{
+	"go vet":      "https://pkg.go.dev/cmd/vet",
+	"staticcheck": "https://staticcheck.dev",
+	"secret-scan": "https://github.com/tinywasm/devflow",
+}
+
+// ParseVetJSON parses the output of go vet -json. Positions are made
+// relative to root.
+func ParseVetJSON(output, root string) ([]Finding, error) {
+	// go vet -json prints "# pkg" comment lines between JSON objects
+	var body strings.Builder
+	for _, line := range strings.Split(output, "\n") {
+		if !strings.HasPrefix(line, "#") {
+			body.WriteString(line + "\n")
+		}
+	}
+
+	var findings []Finding
+	dec := json.NewDecoder(strings.NewReader(body.String()))
+	for {
+		var pkgs map[string]map[string]json.RawMessage
+		if err := dec.Decode(&pkgs); err == io.EOF {
+			break
+		} else if err != nil {
+			return findings, fmt.Errorf("parse go vet -json: %w", err)
+		}
+		for _, analyzers := range pkgs {
+			for analyzer, raw := range analyzers {
+				var diags []struct {
+					Posn    string `json:"posn"`
+					Message string `json:"message"`
+				}
+				if json.Unmarshal(raw, &diags) != nil {
+					continue // {"error": ...} entries for packages that failed to load
+				}
+				for _, d := range diags {
+					file, line, col := splitPosition(d.Posn)
+					findings = append(findings, Finding{
+						Tool: "go vet", Rule: analyzer, File: relativeTo(root, file),
+						Line: line, Column: col, Message: d.Message, Level: "warning",
+					})
+				}
+			}
+		}
+	}
+	sortFindings(findings)
+	return findings, nil
+}
+
+// ParseStaticcheckJSON parses the output of staticcheck -f json (one object per line)
+func ParseStaticcheckJSON(output, root string) []Finding {
+	var findings []Finding
+	for _, line := range strings.Split(output, "\n") {
+		var d struct {
+			Code     string `json:"code"`
+			Severity string `json:"severity"`
+			Location struct {
+				File   string `json:"file"`
+				Line   int    `json:"line"`
+				Column int    `json:"column"`
+			} `json:"location"`
+			Message string `json:"message"`
+		}
+		if json.Unmarshal([]byte(line), &d) != nil || d.Code == "" {
+			continue
+		}
+		level := "warning"
+		if d.Severity == "error" {
+			level = "error"
+		}
+		findings = append(findings, Finding{
+			Tool: "staticcheck", Rule: d.Code, File: relativeTo(root, d.Location.File),
+			Line: d.Location.Line, Column: d.Location.Column, Message: d.Message, Level: level,
+		})
+	}
+	sortFindings(findings)
+	return findings
+}
+
+// splitPosition splits "file.go:12:3" into its parts
+func splitPosition(posn string) (file string, line, col int) {
+	parts := strings.Split(posn, ":")
+	// Windows paths contain a drive colon, so parse from the end
+	if len(parts) >= 3 {
+		if c, err := strconv.Atoi(parts[len(parts)-1]); err == nil {
+			if l, err := strconv.Atoi(parts[len(parts)-2]); err == nil {
+				return strings.Join(parts[:len(parts)-2], ":"), l, c
+			}
+		}
+	}
+	if len(parts) >= 2 {
+		if l, err := strconv.Atoi(parts[len(parts)-1]); err == nil {
+			return strings.Join(parts[:len(parts)-1], ":"), l, 0
+		}
+	}
+	return posn, 0, 0
+}
+
+// relativeTo returns path relative to root with forward slashes
+func relativeTo(root, path string) string {
+	if root != "" && filepath.IsAbs(path) {
+		if rel, err := filepath.Rel(root, path); err == nil {
+			path = rel
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
+func sortFindings(f []Finding) {
+	sort.SliceStable(f, func(i, j int) bool {
+		if f[i].File != f[j].File {
+			return f[i].File < f[j].File
+		}
+		return f[i].Line < f[j].Line
+	})
+}
+
+// SARIF 2.1.0 document, limited to the fields GitHub code scanning uses
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID        string          `json:"ruleId"`
+	Level         string          `json:"level"`
+	Message       sarifMessage    `json:"message"`
+	Locations     []sarifLocation `json:"locations"`
+	BaselineState string          `json:"baselineState,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysical `json:"physicalLocation"`
+}
+
+type sarifPhysical struct {
+	ArtifactLocation sarifArtifact `json:"artifactLocation"`
+	Region           sarifRegion   `json:"region"`
+}
+
+type sarifArtifact struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// BuildSARIF converts findings into a SARIF document with one run per tool.
+// tools lists the tools that ran, so a clean tool still reports an empty run.
+func BuildSARIF(tools []string, findings []Finding) ([]byte, error) {
+	doc := sarifLog{Version: "2.1.0", Schema: "https://json.schemastore.org/sarif-2.1.0.json"}
+
+	for _, tool := range tools {
+		run := sarifRun{
+			Tool:    sarifTool{Driver: sarifDriver{Name: tool, InformationURI: toolURIs[tool], Rules: []sarifRule{}}},
+			Results: []sarifResult{},
+		}
+		rules := make(map[string]bool)
+		for _, f := range findings {
+			if f.Tool != tool {
+				continue
+			}
+			if !rules[f.Rule] {
+				rules[f.Rule] = true
+				run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: f.Rule})
+			}
+			line := f.Line
+			if line < 1 {
+				line = 1 // SARIF regions are 1-based
+			}
+			run.Results = append(run.Results, sarifResult{
+				RuleID:  f.Rule,
+				Level:   f.Level,
+				Message: sarifMessage{Text: f.Message},
+				Locations: []sarifLocation{{PhysicalLocation: sarifPhysical{
+					ArtifactLocation: sarifArtifact{URI: f.File},
+					Region:           sarifRegion{StartLine: line, StartColumn: f.Column},
+				}}},
+				BaselineState: f.BaselineState,
+			})
+		}
+		doc.Runs = append(doc.Runs, run)
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// WriteSARIF writes the SARIF document to path
+func WriteSARIF(path string, tools []string, findings []Finding) error {
+	data, err := BuildSARIF(tools, findings)
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// writeSARIFReport collects the findings and writes them to path. Errors
+// are logged, not fatal. Returns the number of possible secrets found.
+func (g *Go) writeSARIFReport(path string) int {
+	tools, findings, err := g.collectFindings()
+	if err == nil {
+		err = WriteSARIF(path, tools, findings)
+	}
+	if err != nil {
+		g.logger.Warn("SARIF report failed:", err)
+		return 0
+	}
+	g.logger.Info(fmt.Sprintf("SARIF: %d findings written to %s", len(findings), path))
+
+	secrets := 0
+	for _, f := range findings {
+		if f.Tool == "secret-scan" {
+			secrets++
+		}
+	}
+	return secrets
+}
+
+// collectFindings runs the SARIF analyses for the module in the current
+// directory: go vet -json, staticcheck (when installed) and the secret scan
+func (g *Go) collectFindings() (tools []string, findings []Finding, err error) {
+	root, rootErr := RunCommandSilent("git", "rev-parse", "--show-toplevel")
+	if rootErr != nil {
+		root, _ = os.Getwd()
+	}
+
+	// go vet -json exits 0 when it only reports diagnostics
+	vet := LoadVetSettings(g.Config())
+	if err := vet.Prepare(); err != nil {
+		return nil, nil, err
+	}
+	vetOut, vetErr := RunCommandSilent("go", vet.Args([]string{"-json"})...)
+	vetFindings, parseErr := ParseVetJSON(vetOut, root)
+	if parseErr != nil && vetErr != nil {
+		return nil, nil, vetErr
+	}
+	tools = append(tools, "go vet")
+	for _, f := range vetFindings {
+		if !vet.Ignored(f.Message) {
+			findings = append(findings, f)
+		}
+	}
+
+	if _, err := RunCommandSilent("staticcheck", "-version"); err == nil {
+		// staticcheck exits 1 when it reports findings
+		out, _ := RunCommandSilent("staticcheck", "-f", "json", "./...")
+		tools = append(tools, "staticcheck")
+		findings = append(findings, ParseStaticcheckJSON(out, root)...)
+	} else {
+		g.logger.Info("staticcheck not installed, skipped in SARIF report")
+	}
+
+	cwd, _ := os.Getwd()
+	secrets, err := ScanSecrets(cwd)
+	if err != nil {
+		return tools, findings, err
+	}
+	tools = append(tools, "secret-scan")
+	for _, f := range secrets {
+		f.File = relativeTo(root, filepath.Join(cwd, f.File))
+		findings = append(findings, f)
+	}
+
+	// The baseline keys use paths relative to the module directory
+	if baseline, err := LoadBaseline("."); err == nil && baseline != nil {
+		prefix, _ := RunCommandSilent("git", "rev-parse", "--show-prefix")
+		for i, f := range baseline.mark(relativeFindings(findings, prefix)) {
+			findings[i].BaselineState = f.BaselineState
+		}
+	}
+	return tools, findings, nil
+}
+
+// relativeFindings strips the module directory prefix from repository paths
+func relativeFindings(findings []Finding, prefix string) []Finding {
+	out := make([]Finding, len(findings))
+	for i, f := range findings {
+		f.File = strings.TrimPrefix(f.File, prefix)
+		out[i] = f
+	}
+	return out
+}
@@ -0,0 +1,122 @@
+package devflow
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseVetJSON(t *testing.T) {
+	output := `# example.com/app
+{
+	"example.com/app": {
+		"printf": [
+			{
+				"posn": "/repo/app/main.go:12:3",
+				"message": "fmt.Sprintf format %d has arg s of wrong type string"
+			}
+		]
+	}
+}
+# example.com/app/broken
+{
+	"example.com/app/broken": {
+		"error": "could not import"
+	}
+}
+`
+	findings, err := ParseVetJSON(output, "/repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 1 {
+		t.Fatalf("expected 1 finding, got %+v", findings)
+	}
+	f := findings[0]
+	if f.Tool != "go vet" || f.Rule != "printf" || f.File != "app/main.go" || f.Line != 12 || f.Column != 3 {
+		t.Errorf("unexpected finding: %+v", f)
+	}
+}
+
+func TestParseStaticcheckJSON(t *testing.T) {
+	output := `{"code":"SA4006","severity":"error","location":{"file":"/repo/x.go","line":4,"column":2},"message":"value never used"}
+not json
+{"code":"ST1005","severity":"warning","location":{"file":"/repo/a.go","line":9,"column":1},"message":"error strings should not be capitalized"}`
+	findings := ParseStaticcheckJSON(output, "/repo")
+	if len(findings) != 2 || findings[0].File != "a.go" || findings[1].Rule != "SA4006" || findings[1].Level != "error" {
+		t.Errorf("unexpected findings: %+v", findings)
+	}
+}
+
+func TestSplitPosition(t *testing.T) {
+	tests := []struct {
+		posn      string
+		file      string
+		line, col int
+	}{
+		{"a/b.go:12:3", "a/b.go", 12, 3},
+		{`C:\src\b.go:7:1`, `C:\src\b.go`, 7, 1},
+		{"b.go:5", "b.go", 5, 0},
+		{"b.go", "b.go", 0, 0},
+	}
+	for _, tt := range tests {
+		file, line, col := splitPosition(tt.posn)
+		if file != tt.file || line != tt.line || col != tt.col {
+			t.Errorf("splitPosition(%q) = %q, %d, %d", tt.posn, file, line, col)
+		}
+	}
+}
+
+func TestBuildSARIF(t *testing.T) {
+	findings := []Finding{
+		{Tool: "go vet", Rule: "printf", File: "main.go", Line: 3, Column: 2, Message: "bad format", Level: "warning"},
+		{Tool: "go vet", Rule: "printf", File: "x.go", Line: 0, Message: "other", Level: "warning"},
+	}
+	data, err := BuildSARIF([]string{"go vet", "secret-scan"}, findings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc sarifLog
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Version != "2.1.0" || len(doc.Runs) != 2 {
+		t.Fatalf("unexpected document: %s", data)
+	}
+	vet := doc.Runs[0]
+	if vet.Tool.Driver.Name != "go vet" || len(vet.Tool.Driver.Rules) != 1 || len(vet.Results) != 2 {
+		t.Errorf("unexpected vet run: %+v", vet)
+	}
+	if r := vet.Results[1].Locations[0].PhysicalLocation.Region; r.StartLine != 1 {
+		t.Errorf("line 0 should be clamped to 1, got %d", r.StartLine)
+	}
+	if !strings.Contains(string(data), `"results": []`) {
+		t.Error("tools without findings should report an empty results array")
+	}
+}
+
+func TestScanSecrets(t *testing.T) {
+	dir := t.TempDir()
+	// Built at runtime so this file does not trip the scanner itself
+	awsKey := "AKIA" + strings.Repeat("Q", 16)
+	ghToken := "ghp_" + strings.Repeat("a1", 18)
+	os.WriteFile(filepath.Join(dir, "config.go"), []byte("package x\n\nconst key = \""+awsKey+"\"\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "fixture.txt"), []byte(ghToken+" // "+secretAllowMarker+"\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "id_rsa"), []byte("-----BEGIN RSA PRIVATE"+" KEY-----\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "blob.bin"), []byte("\x00"+awsKey), 0644)
+
+	findings, err := ScanSecrets(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got %+v", findings)
+	}
+	rules := findings[0].Rule + "," + findings[1].Rule
+	if rules != "aws-access-key,private-key" || findings[0].Line != 3 || findings[0].Column != 14 {
+		t.Errorf("unexpected findings: %+v", findings)
+	}
+}
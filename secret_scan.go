@@ -0,0 +1,100 @@
+package devflow
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// secretMaxFileSize skips large files (assets, fixtures) in the secret scan
+const secretMaxFileSize = 1 << 20
+
+// secretRule is a pattern for a credential that must not be committed
+type secretRule struct {
+	id      string
+	message string
+	re      *regexp.Regexp
+}
+
+var secretRules = []secretRule{
+	{"aws-access-key", "AWS access key id", regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"github-token", "GitHub token", regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{"gitlab-token", "GitLab personal access token", regexp.MustCompile(`\bglpat-[A-Za-z0-9_-]{20,}\b`)},
+	{"slack-token", "Slack token", regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}\b`)},
+	{"google-api-key", "Google API key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{"stripe-key", "Stripe secret key", regexp.MustCompile(`\b(sk|rk)_live_[0-9A-Za-z]{24,}\b`)},
+	{"private-key", "Private key", regexp.MustCompile(`-----BEGIN ((RSA|DSA|EC|OPENSSH|PGP) )?PRIVATE KEY( BLOCK)?-----`)},
+}
+
+// secretAllowMarker on a line suppresses the secret scan for it (test fixtures)
+const secretAllowMarker = "devflow:allow-secret"
+
+// ScanSecrets looks for committed credentials in the git-tracked files of
+// dir (all files when dir is not a repository). Paths are relative to dir.
+func ScanSecrets(dir string) ([]Finding, error) {
+	files, err := trackedFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []Finding
+	for _, rel := range files {
+		path := filepath.Join(dir, rel)
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() || info.Size() > secretMaxFileSize {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil || bytes.IndexByte(data, 0) >= 0 {
+			continue // unreadable or binary
+		}
+
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(make([]byte, 0, 64*1024), secretMaxFileSize)
+		for n := 1; scanner.Scan(); n++ {
+			line := scanner.Text()
+			if strings.Contains(line, secretAllowMarker) {
+				continue
+			}
+			for _, r := range secretRules {
+				if loc := r.re.FindStringIndex(line); loc != nil {
+					findings = append(findings, Finding{
+						Tool: "secret-scan", Rule: r.id, File: filepath.ToSlash(rel),
+						Line: n, Column: loc[0] + 1, Message: r.message + " committed to the repository", Level: "error",
+					})
+				}
+			}
+		}
+	}
+	return findings, nil
+}
+
+// trackedFiles lists the files git tracks in dir, falling back to a walk
+func trackedFiles(dir string) ([]string, error) {
+	if out, err := RunCommandInDir(dir, "git", "ls-files", "-co", "--exclude-standard"); err == nil {
+		if out == "" {
+			return nil, nil
+		}
+		return strings.Split(out, "\n"), nil
+	}
+
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, path)
+		files = append(files, rel)
+		return nil
+	})
+	return files, err
+}
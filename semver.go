@@ -1,6 +1,7 @@
 package devflow
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 )
@@ -52,3 +53,81 @@ func CompareVersions(v1, v2 string) int {
 func isNotDigit(r rune) bool {
 	return r < '0' || r > '9'
 }
+
+// Semver bump levels
+const (
+	BumpPatch = "patch"
+	BumpMinor = "minor"
+	BumpMajor = "major"
+)
+
+// BumpVersion returns the tag that follows tag for the given bump level
+// (e.g., v1.2.3 + minor -> v1.3.0). An empty tag starts from v0.0.0.
+func BumpVersion(tag, level string) (string, error) {
+	if tag == "" {
+		tag = "v0.0.0"
+	}
+
+	parts := strings.Split(strings.TrimPrefix(tag, "v"), ".")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("invalid tag format: %s", tag)
+	}
+
+	nums := make([]int, 3)
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return "", fmt.Errorf("invalid tag number: %s", p)
+		}
+		nums[i] = n
+	}
+
+	switch level {
+	case BumpMajor:
+		nums = []int{nums[0] + 1, 0, 0}
+	case BumpMinor:
+		nums = []int{nums[0], nums[1] + 1, 0}
+	case BumpPatch, "":
+		nums[2]++
+	default:
+		return "", fmt.Errorf("invalid bump level: %s (use patch, minor or major)", level)
+	}
+
+	return fmt.Sprintf("v%d.%d.%d", nums[0], nums[1], nums[2]), nil
+}
+
+// CommitBump returns the bump level a conventional commit message asks for:
+// major for "feat!:" or a "BREAKING CHANGE:" footer, minor for "feat:" and
+// patch for anything else
+func CommitBump(message string) string {
+	subject, body, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(line, "BREAKING CHANGE:") || strings.HasPrefix(line, "BREAKING-CHANGE:") {
+			return BumpMajor
+		}
+	}
+	m := conventionalRe.FindStringSubmatch(strings.TrimSpace(subject))
+	switch {
+	case m == nil:
+		return BumpPatch
+	case m[3] == "!":
+		return BumpMajor
+	case strings.EqualFold(m[1], "feat"):
+		return BumpMinor
+	}
+	return BumpPatch
+}
+
+// ConventionalBump returns the highest bump level of messages
+func ConventionalBump(messages []string) string {
+	level := BumpPatch
+	for _, m := range messages {
+		switch CommitBump(m) {
+		case BumpMajor:
+			return BumpMajor
+		case BumpMinor:
+			level = BumpMinor
+		}
+	}
+	return level
+}
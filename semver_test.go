@@ -25,3 +25,59 @@ func TestCompareVersions(t *testing.T) {
 		}
 	}
 }
+
+func TestBumpVersion(t *testing.T) {
+	tests := []struct {
+		tag, level, want string
+	}{
+		{"v1.2.3", BumpPatch, "v1.2.4"},
+		{"v1.2.3", BumpMinor, "v1.3.0"},
+		{"v1.2.3", BumpMajor, "v2.0.0"},
+		{"", BumpPatch, "v0.0.1"},
+		{"v0.4.9", "", "v0.4.10"},
+	}
+
+	for _, tt := range tests {
+		got, err := BumpVersion(tt.tag, tt.level)
+		if err != nil {
+			t.Errorf("BumpVersion(%q, %q) error: %v", tt.tag, tt.level, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("BumpVersion(%q, %q) = %q; want %q", tt.tag, tt.level, got, tt.want)
+		}
+	}
+
+	if _, err := BumpVersion("v1.0", BumpPatch); err == nil {
+		t.Error("expected error for invalid tag")
+	}
+	if _, err := BumpVersion("v1.0.0", "huge"); err == nil {
+		t.Error("expected error for invalid level")
+	}
+}
+
+func TestCommitBump(t *testing.T) {
+	tests := map[string]string{
+		"feat: add retries":                          BumpMinor,
+		"feat(api): add retries":                     BumpMinor,
+		"fix: handle empty input":                    BumpPatch,
+		"docs: typo":                                 BumpPatch,
+		"update readme":                              BumpPatch,
+		"feat!: drop v1 config":                      BumpMajor,
+		"fix(api)!: rename field":                    BumpMajor,
+		"fix: rename\n\nBREAKING CHANGE: Name is ID": BumpMajor,
+		"refactor: x\n\nBREAKING-CHANGE: removed Y":  BumpMajor,
+	}
+	for message, want := range tests {
+		if got := CommitBump(message); got != want {
+			t.Errorf("CommitBump(%q) = %s; want %s", message, got, want)
+		}
+	}
+
+	if got := ConventionalBump([]string{"fix: a", "feat: b", "docs: c"}); got != BumpMinor {
+		t.Errorf("ConventionalBump = %s; want minor", got)
+	}
+	if got := ConventionalBump(nil); got != BumpPatch {
+		t.Errorf("ConventionalBump(nil) = %s; want patch", got)
+	}
+}
@@ -0,0 +1,317 @@
+package devflow
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// KeySetupOptions configures KeySetup.Setup
+type KeySetupOptions struct {
+	Path      string // Private key of the authentication key (default ~/.ssh/id_ed25519)
+	Signing   bool   // Also set up a commit signing key (<Path>_signing)
+	Title     string // Title of the uploaded keys (default: devflow@<hostname>)
+	VerifyURL string // Repository checked with git ls-remote (default: origin when it is on the host)
+}
+
+// KeySetupResult describes what Setup did
+type KeySetupResult struct {
+	Host       string
+	Key        string   // Public authentication key file
+	SigningKey string   // Public signing key file, "" without Signing
+	Generated  []string // Keys created by ssh-keygen
+	Uploaded   []string // "authentication" and/or "signing"
+	Verified   string   // How access was verified
+}
+
+// Summary returns e.g. "✅ SSH keys: generated 2, uploaded authentication, signing, git@github.com ok"
+func (r KeySetupResult) Summary() string {
+	parts := []string{fmt.Sprintf("generated %d", len(r.Generated))}
+	if len(r.Uploaded) > 0 {
+		parts = append(parts, "uploaded "+strings.Join(r.Uploaded, ", "))
+	} else {
+		parts = append(parts, "already uploaded")
+	}
+	if r.Verified != "" {
+		parts = append(parts, r.Verified+" ok")
+	}
+	return "✅ SSH keys: " + strings.Join(parts, ", ")
+}
+
+// KeySetup bootstraps SSH access to GitHub: it creates the keys that are
+// missing, registers their public halves with the account, points git at
+// them and checks that the host accepts the authentication key.
+type KeySetup struct {
+	log      func(...any)
+	uploader KeyUploader
+	host     string
+	dryRun   bool
+}
+
+// NewKeySetup creates a KeySetup for GitHub (GH_HOST for GitHub Enterprise)
+func NewKeySetup() *KeySetup {
+	return &KeySetup{log: func(...any) {}, host: (&GitHub{}).Host()}
+}
+
+// SetLog sets the logger function
+func (k *KeySetup) SetLog(fn func(...any)) {
+	if fn != nil {
+		k.log = fn
+	}
+}
+
+// SetUploader sets the client the public keys are uploaded with
+// (default: a GitHub client created on first use)
+func (k *KeySetup) SetUploader(u KeyUploader) {
+	k.uploader = u
+}
+
+// SetDryRun makes Setup print the commands and file updates it would
+// perform instead of running them
+func (k *KeySetup) SetDryRun(enabled bool) {
+	k.dryRun = enabled
+}
+
+// Setup runs every step of the bootstrap. Existing keys are reused and
+// keys the account already has are not uploaded again, so it can be
+// re-run after a failure.
+func (k *KeySetup) Setup(opts KeySetupOptions) (_ KeySetupResult, err error) {
+	if k.dryRun {
+		defer startDryRun()()
+	}
+	defer trackMetric("keys setup")(&err)
+
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		return KeySetupResult{}, fmt.Errorf("OpenSSH is not installed or not in PATH: %w", err)
+	}
+	if opts.Path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return KeySetupResult{}, err
+		}
+		opts.Path = filepath.Join(home, ".ssh", "id_ed25519")
+	}
+	if opts.Title == "" {
+		hostname, _ := os.Hostname()
+		opts.Title = "devflow@" + hostname
+	}
+	email, _ := RunCommandSilent("git", "config", "--get", "user.email")
+
+	result := KeySetupResult{Host: k.host, Key: opts.Path + ".pub"}
+	keys := map[string]string{"authentication": opts.Path}
+	if opts.Signing {
+		keys["signing"] = opts.Path + "_signing"
+		result.SigningKey = keys["signing"] + ".pub"
+	}
+
+	// 1. Generate the missing keys
+	public := map[string]string{}
+	for _, kind := range []string{"authentication", "signing"} {
+		path, ok := keys[kind]
+		if !ok {
+			continue
+		}
+		generated, err := generateSSHKey(path, email)
+		if err != nil {
+			return result, err
+		}
+		if generated {
+			result.Generated = append(result.Generated, path)
+			k.log("Generated", kind, "key", path, "without a passphrase; add one with: ssh-keygen -p -f", shellQuote(path))
+		}
+		if public[kind], err = readPublicKey(path + ".pub"); err != nil {
+			return result, err
+		}
+	}
+
+	// 2. Upload the public keys the account does not have yet
+	if err := AirGapCheck(NetGitHub); err != nil {
+		return result, err
+	}
+	if k.uploader == nil {
+		gh, err := NewGitHub(k.log)
+		if err != nil {
+			return result, err
+		}
+		k.uploader = gh
+	}
+	for _, kind := range []string{"authentication", "signing"} {
+		key, ok := public[kind]
+		if !ok {
+			continue
+		}
+		uploaded, err := k.uploadKey(opts.Title, key, kind == "signing")
+		if err != nil {
+			return result, fmt.Errorf("upload %s key: %w", kind, err)
+		}
+		if uploaded {
+			result.Uploaded = append(result.Uploaded, kind)
+		}
+	}
+
+	// 3. Configure git
+	if err := k.configureGit(opts, public["signing"], email); err != nil {
+		return result, err
+	}
+
+	// 4. Verify access with the authentication key
+	if DryRunActive() {
+		return result, nil
+	}
+	if result.Verified, err = k.verify(opts); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// generateSSHKey creates an ed25519 key pair at path unless it exists
+func generateSSHKey(path, comment string) (bool, error) {
+	if _, err := os.Stat(path); err == nil {
+		if _, err := os.Stat(path + ".pub"); err != nil {
+			if DryRunActive() {
+				dryRunf(filepath.Dir(path), "ssh-keygen -y -f %s > %s.pub", filepath.Base(path), filepath.Base(path))
+				return false, nil
+			}
+			out, err := RunCommandSilent("ssh-keygen", "-y", "-f", path)
+			if err != nil {
+				return false, fmt.Errorf("read public key of %s: %s", path, firstLine(out))
+			}
+			return false, os.WriteFile(path+".pub", []byte(out+"\n"), 0644)
+		}
+		return false, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil && !DryRunActive() {
+		return false, err
+	}
+	if out, err := RunCommand("ssh-keygen", "-t", "ed25519", "-C", comment, "-f", path, "-N", ""); err != nil {
+		return false, fmt.Errorf("ssh-keygen failed: %s", firstLine(out))
+	}
+	return true, nil
+}
+
+// readPublicKey returns the "<type> <base64>" part of a public key file.
+// In dry-run mode a key that was not generated reads as a placeholder.
+func readPublicKey(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if DryRunActive() && os.IsNotExist(err) {
+			return "ssh-ed25519 <new key>", nil
+		}
+		return "", err
+	}
+	return sshKeyID(string(data)), nil
+}
+
+// sshKeyID drops the comment of an authorized_keys line, which is how
+// providers list keys
+func sshKeyID(line string) string {
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return strings.TrimSpace(line)
+	}
+	return fields[0] + " " + fields[1]
+}
+
+// uploadKey adds key to the account unless it is already there
+func (k *KeySetup) uploadKey(title, key string, signing bool) (bool, error) {
+	existing, err := k.uploader.SSHKeys(signing)
+	if err != nil {
+		return false, err
+	}
+	for _, e := range existing {
+		if sshKeyID(e) == key {
+			return false, nil
+		}
+	}
+	if DryRunActive() {
+		kind := "authentication"
+		if signing {
+			kind = "signing"
+		}
+		dryRunf("", "upload %s key %q to %s", kind, title, k.host)
+		return true, nil
+	}
+	return true, k.uploader.AddSSHKey(title, key, signing)
+}
+
+// configureGit makes git use a non-default authentication key and sign
+// commits with the signing key. core.sshCommand is run by the shell, so
+// the key path is quoted.
+func (k *KeySetup) configureGit(opts KeySetupOptions, signingKey, email string) error {
+	home, _ := os.UserHomeDir()
+	var settings [][2]string
+	if opts.Path != filepath.Join(home, ".ssh", "id_ed25519") {
+		settings = append(settings, [2]string{"core.sshCommand", fmt.Sprintf("ssh -i %s -o IdentitiesOnly=yes", shellQuote(opts.Path))})
+	}
+	if signingKey != "" {
+		signers := filepath.Join(filepath.Dir(opts.Path), "allowed_signers")
+		settings = append(settings,
+			[2]string{"gpg.format", "ssh"},
+			[2]string{"user.signingkey", opts.Path + "_signing.pub"},
+			[2]string{"commit.gpgsign", "true"},
+			[2]string{"gpg.ssh.allowedSignersFile", signers},
+		)
+		if email != "" {
+			if err := addAllowedSigner(signers, email, signingKey); err != nil {
+				return err
+			}
+		}
+	}
+	for _, s := range settings {
+		if out, err := RunCommand("git", "config", "--global", s[0], s[1]); err != nil {
+			return fmt.Errorf("git config %s failed: %s", s[0], firstLine(out))
+		}
+	}
+	return nil
+}
+
+// addAllowedSigner appends email and key to the allowed signers file so
+// git log --show-signature can verify local commits
+func addAllowedSigner(path, email, key string) error {
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), key) {
+		return nil
+	}
+	line := fmt.Sprintf("%s namespaces=\"git\" %s\n", email, key)
+	if DryRunActive() {
+		dryRunf(filepath.Dir(path), "append %s to %s", email, filepath.Base(path))
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.WriteString(line)
+	return err
+}
+
+// verify checks that the host accepts the authentication key: git
+// ls-remote of VerifyURL or origin, else "ssh -T git@<host>"
+func (k *KeySetup) verify(opts KeySetupOptions) (string, error) {
+	sshArgs := []string{"-i", opts.Path, "-o", "IdentitiesOnly=yes", "-o", "BatchMode=yes", "-o", "StrictHostKeyChecking=accept-new"}
+	url := opts.VerifyURL
+	if url == "" {
+		if origin, err := RunCommandSilent("git", "remote", "get-url", "origin"); err == nil {
+			if host, owner, name, ok := ParseRemoteURL(origin); ok && host == k.host {
+				url = fmt.Sprintf("git@%s:%s/%s.git", host, owner, name)
+			}
+		}
+	}
+	if url == "" {
+		// GitHub closes the session with exit status 1 after the greeting
+		target := "git@" + k.host
+		out, _ := RunCommandSilent("ssh", append(sshArgs, "-T", target)...)
+		if !strings.Contains(out, "successfully authenticated") {
+			return "", fmt.Errorf("%s rejected the key: %s", target, firstLine(out))
+		}
+		return target, nil
+	}
+	env := []string{"GIT_SSH_COMMAND=ssh " + strings.Join(sshArgs, " ")}
+	if out, err := RunCommandWithEnvInDir("", env, "git", "ls-remote", "--heads", url); err != nil {
+		return "", fmt.Errorf("git ls-remote %s failed: %s", url, firstLine(out))
+	}
+	return url, nil
+}
@@ -0,0 +1,124 @@
+package devflow
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testKeyUploader keeps the uploaded keys in memory
+type testKeyUploader struct {
+	keys, signing []string
+}
+
+func (u *testKeyUploader) SSHKeys(signing bool) ([]string, error) {
+	if signing {
+		return u.signing, nil
+	}
+	return u.keys, nil
+}
+
+func (u *testKeyUploader) AddSSHKey(title, key string, signing bool) error {
+	if signing {
+		u.signing = append(u.signing, key)
+	} else {
+		u.keys = append(u.keys, key+" "+title)
+	}
+	return nil
+}
+
+func TestKeySetup(t *testing.T) {
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen not installed")
+	}
+	home := t.TempDir()
+	defer testChdir(t, home)()
+	t.Setenv("HOME", home)
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(home, ".gitconfig"))
+	exec.Command("git", "config", "--global", "user.email", "dev@example.com").Run()
+	remote := t.TempDir()
+	exec.Command("git", "init", "--bare", remote).Run()
+
+	k := NewKeySetup()
+	var logged []string
+	k.SetLog(func(args ...any) { logged = append(logged, fmt.Sprintln(args...)) })
+	u := &testKeyUploader{}
+	k.SetUploader(u)
+	key := filepath.Join(home, ".ssh", "work key")
+	opts := KeySetupOptions{Path: key, Signing: true, Title: "laptop", VerifyURL: "file://" + remote}
+	result, err := k.Setup(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := result.Summary(); got != "✅ SSH keys: generated 2, uploaded authentication, signing, file://"+remote+" ok" {
+		t.Errorf("Summary() = %q", got)
+	}
+	if len(u.keys) != 1 || !strings.HasPrefix(u.keys[0], "ssh-ed25519 ") || !strings.HasSuffix(u.keys[0], " laptop") || len(u.signing) != 1 {
+		t.Errorf("unexpected uploads: %+v", u)
+	}
+
+	config := func(key string) string {
+		out, _ := RunCommandSilent("git", "config", "--global", "--get", key)
+		return out
+	}
+	if len(logged) != 2 || !strings.Contains(logged[0], "without a passphrase") {
+		t.Errorf("expected the missing passphrase reported, got %q", logged)
+	}
+	if got := config("core.sshCommand"); got != "ssh -i '"+key+"' -o IdentitiesOnly=yes" {
+		t.Errorf("core.sshCommand = %q", got)
+	}
+	if config("gpg.format") != "ssh" || config("user.signingkey") != key+"_signing.pub" || config("commit.gpgsign") != "true" {
+		t.Error("signing not configured")
+	}
+	signers, _ := os.ReadFile(filepath.Join(home, ".ssh", "allowed_signers"))
+	if !strings.HasPrefix(string(signers), `dev@example.com namespaces="git" `+u.signing[0]) {
+		t.Errorf("unexpected allowed_signers: %q", signers)
+	}
+
+	// A second run reuses the keys and uploads nothing
+	result, err = k.Setup(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Generated) != 0 || len(result.Uploaded) != 0 || len(u.keys) != 1 {
+		t.Errorf("second run should change nothing, got %+v", result)
+	}
+	if signers2, _ := os.ReadFile(filepath.Join(home, ".ssh", "allowed_signers")); string(signers2) != string(signers) {
+		t.Error("allowed signer added twice")
+	}
+
+	opts.VerifyURL = "file:///nonexistent/repo.git"
+	if _, err := k.Setup(opts); err == nil || !strings.Contains(err.Error(), "ls-remote") {
+		t.Errorf("expected verify error, got %v", err)
+	}
+}
+
+func TestKeySetupDryRun(t *testing.T) {
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen not installed")
+	}
+	home := t.TempDir()
+	defer testChdir(t, home)()
+	t.Setenv("HOME", home)
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(home, ".gitconfig"))
+
+	k := NewKeySetup()
+	u := &testKeyUploader{}
+	k.SetUploader(u)
+	k.SetDryRun(true)
+	if _, err := k.Setup(KeySetupOptions{Signing: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(home, ".ssh", "id_ed25519")); !os.IsNotExist(err) {
+		t.Error("dry run should not generate keys")
+	}
+	if len(u.keys) != 0 || len(u.signing) != 0 {
+		t.Error("dry run should not upload keys")
+	}
+	if _, err := os.Stat(filepath.Join(home, ".gitconfig")); !os.IsNotExist(err) {
+		t.Error("dry run should not configure git")
+	}
+}
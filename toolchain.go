@@ -0,0 +1,33 @@
+package devflow
+
+import (
+	"runtime"
+	"strings"
+)
+
+// Toolchain identifies the Go version and target platform of a test run
+type Toolchain struct {
+	GoVersion string // e.g. "go1.25.2"
+	GOOS      string
+	GOARCH    string
+}
+
+// String returns e.g. "go1.25.2 linux/amd64"
+func (t Toolchain) String() string {
+	return t.GoVersion + " " + t.GOOS + "/" + t.GOARCH
+}
+
+// DetectToolchain asks the go command for the toolchain it will use, so
+// GOTOOLCHAIN, GOOS and GOARCH overrides are honored. Falls back to the
+// values devflow was built with.
+func DetectToolchain() Toolchain {
+	t := Toolchain{GoVersion: runtime.Version(), GOOS: runtime.GOOS, GOARCH: runtime.GOARCH}
+	out, err := RunCommandSilent("go", "env", "GOVERSION", "GOOS", "GOARCH")
+	if err != nil {
+		return t
+	}
+	if f := strings.Fields(out); len(f) == 3 {
+		t.GoVersion, t.GOOS, t.GOARCH = f[0], f[1], f[2]
+	}
+	return t
+}
@@ -0,0 +1,339 @@
+package devflow
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Span kinds and status codes of the OTLP trace format
+const (
+	otlpKindInternal = 1
+	otlpKindClient   = 3
+	otlpStatusOK     = 1
+	otlpStatusError  = 2
+)
+
+// span is one traced workflow, phase or external command
+type span struct {
+	name       string
+	id, parent string
+	kind       int
+	start, end time.Time
+	attrs      [][2]string
+	err        error
+
+	phase *span // Current phase of a workflow span (tracePhase)
+}
+
+// tracer holds the trace of the outermost traced workflow of the process.
+// It is exported once that workflow ends.
+var tracer struct {
+	sync.Mutex
+	traceID  string
+	parentID string // Span of the calling process (TRACEPARENT)
+	stack    []*span
+	done     []*span
+	remove   func()
+}
+
+// TracingEndpoint returns the OTLP/HTTP traces URL workflows are exported
+// to, empty when tracing is off. It is OTEL_EXPORTER_OTLP_TRACES_ENDPOINT,
+// else OTEL_EXPORTER_OTLP_ENDPOINT or tracing.endpoint in the global config
+// with /v1/traces appended. OTEL_SDK_DISABLED=true or
+// OTEL_TRACES_EXPORTER=none turn tracing off.
+func TracingEndpoint() string {
+	if os.Getenv("OTEL_SDK_DISABLED") == "true" || os.Getenv("OTEL_TRACES_EXPORTER") == "none" {
+		return ""
+	}
+	if v := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"); v != "" {
+		return v
+	}
+	base := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	if base == "" {
+		if global, err := LoadGlobalConfig(); err == nil {
+			base = global.String("tracing.endpoint", "")
+		}
+	}
+	if base == "" {
+		return ""
+	}
+	return strings.TrimSuffix(base, "/") + "/v1/traces"
+}
+
+// traceSpan starts the span of a workflow (or of a sequential part of one)
+// under the innermost open span; commands run until it ends are its
+// children: defer traceSpan("push")(&err). The outermost span starts the
+// trace, which is exported when it ends.
+func traceSpan(name string) func(*error) {
+	tracer.Lock()
+	root := len(tracer.stack) == 0
+	tracer.Unlock()
+	var endpoint string
+	if root {
+		if endpoint = TracingEndpoint(); endpoint == "" || DryRunActive() {
+			return func(*error) {}
+		}
+	}
+
+	tracer.Lock()
+	if root {
+		tracer.traceID, tracer.parentID = parseTraceparent(os.Getenv("TRACEPARENT"))
+		tracer.done = nil
+	}
+	s := newSpan(name, currentSpanID(), otlpKindInternal)
+	tracer.stack = append(tracer.stack, s)
+	tracer.Unlock()
+	if root {
+		tracer.remove = UseMiddleware(traceMiddleware)
+	}
+
+	return func(err *error) {
+		tracer.Lock()
+		if s.phase != nil {
+			finishSpan(s.phase, err)
+		}
+		finishSpan(s, err)
+		for i, other := range tracer.stack {
+			if other == s {
+				tracer.stack = append(tracer.stack[:i], tracer.stack[i+1:]...)
+				break
+			}
+		}
+		if !root {
+			tracer.Unlock()
+			return
+		}
+		spans, traceID := tracer.done, tracer.traceID
+		tracer.done, tracer.stack = nil, nil
+		tracer.Unlock()
+		tracer.remove()
+		if xerr := exportTrace(endpoint, traceID, spans); xerr != nil {
+			fmt.Fprintf(OutputWriter(os.Stderr), "⚠️ Trace export failed: %v\n", xerr)
+		}
+	}
+}
+
+// tracePhase ends the current phase of the innermost workflow span and
+// starts the next one, so a workflow with many returns is split into its
+// steps: the phase open when it fails gets the error
+func tracePhase(name string) {
+	tracer.Lock()
+	defer tracer.Unlock()
+	if len(tracer.stack) == 0 {
+		return
+	}
+	top := tracer.stack[len(tracer.stack)-1]
+	if top.phase != nil {
+		finishSpan(top.phase, nil)
+	}
+	top.phase = newSpan(name, top.id, otlpKindInternal)
+}
+
+// traceChild starts a span under the current one that runs alongside the
+// others (e.g. the parallel gotest phases): commands do not nest under it
+func traceChild(name string) func(*error) {
+	tracer.Lock()
+	defer tracer.Unlock()
+	if len(tracer.stack) == 0 {
+		return func(*error) {}
+	}
+	s := newSpan(name, currentSpanID(), otlpKindInternal)
+	return func(err *error) {
+		tracer.Lock()
+		defer tracer.Unlock()
+		finishSpan(s, err)
+	}
+}
+
+// traceMiddleware records a client span per executor command and passes
+// the trace on to it in TRACEPARENT, so nested devflow runs join it
+func traceMiddleware(next CommandRunner) CommandRunner {
+	return func(cmd *Command) (string, error) {
+		name := cmd.Name
+		if len(cmd.Args) > 0 && !strings.HasPrefix(cmd.Args[0], "-") {
+			name += " " + cmd.Args[0]
+		}
+		tracer.Lock()
+		s := newSpan(name, currentSpanID(), otlpKindClient)
+		traceID := tracer.traceID
+		tracer.Unlock()
+		s.attrs = append(s.attrs, [2]string{"process.executable.name", cmd.Name}, [2]string{"process.command_line", cmd.String()})
+		if cmd.Dir != "" {
+			s.attrs = append(s.attrs, [2]string{"process.working_directory", cmd.Dir})
+		}
+		cmd.Env = append(cmd.Env, "TRACEPARENT=00-"+traceID+"-"+s.id+"-01")
+
+		out, err := next(cmd)
+		tracer.Lock()
+		finishSpan(s, &err)
+		if line := firstLine(out); err != nil && line != "" {
+			// The first output line says more than "exit status 1"
+			s.err = errors.New(line)
+		}
+		tracer.Unlock()
+		return out, err
+	}
+}
+
+// newSpan returns a started span; the tracer must be locked
+func newSpan(name, parent string, kind int) *span {
+	return &span{name: name, id: randomHex(8), parent: parent, kind: kind, start: time.Now()}
+}
+
+// finishSpan ends s with err and queues it for export; the tracer must be
+// locked
+func finishSpan(s *span, err *error) {
+	s.end = time.Now()
+	if err != nil && *err != nil {
+		s.err = *err
+	}
+	tracer.done = append(tracer.done, s)
+}
+
+// currentSpanID returns the parent of a new span: the current phase of the
+// innermost workflow, the workflow itself or the calling process span;
+// the tracer must be locked
+func currentSpanID() string {
+	if len(tracer.stack) == 0 {
+		return tracer.parentID
+	}
+	top := tracer.stack[len(tracer.stack)-1]
+	if top.phase != nil {
+		return top.phase.id
+	}
+	return top.id
+}
+
+// parseTraceparent returns the trace and parent span of a W3C traceparent
+// header, or a new trace when it is empty or invalid
+func parseTraceparent(v string) (traceID, parentID string) {
+	parts := strings.Split(v, "-")
+	if len(parts) == 4 && len(parts[1]) == 32 && len(parts[2]) == 16 {
+		if _, err := hex.DecodeString(parts[1] + parts[2]); err == nil {
+			return parts[1], parts[2]
+		}
+	}
+	return randomHex(16), ""
+}
+
+// randomHex returns n random bytes in hex
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// tracingHeaders returns the headers of the export request:
+// tracing.headers.* in the global config, then OTEL_EXPORTER_OTLP_HEADERS
+// and OTEL_EXPORTER_OTLP_TRACES_HEADERS (name=value,name=value)
+func tracingHeaders() map[string]string {
+	headers := map[string]string{}
+	if global, err := LoadGlobalConfig(); err == nil {
+		for _, name := range global.Keys("tracing.headers") {
+			headers[name] = global.String("tracing.headers."+name, "")
+		}
+	}
+	for _, env := range []string{"OTEL_EXPORTER_OTLP_HEADERS", "OTEL_EXPORTER_OTLP_TRACES_HEADERS"} {
+		for _, pair := range strings.Split(os.Getenv(env), ",") {
+			k, v, ok := strings.Cut(pair, "=")
+			if !ok || strings.TrimSpace(k) == "" {
+				continue
+			}
+			if unescaped, err := url.QueryUnescape(strings.TrimSpace(v)); err == nil {
+				v = unescaped
+			}
+			headers[strings.TrimSpace(k)] = v
+		}
+	}
+	return headers
+}
+
+// exportTrace posts spans to endpoint in the OTLP/HTTP JSON encoding
+func exportTrace(endpoint, traceID string, spans []*span) error {
+	type attr struct {
+		Key   string            `json:"key"`
+		Value map[string]string `json:"value"`
+	}
+	attrsOf := func(pairs [][2]string) []attr {
+		out := make([]attr, len(pairs))
+		for i, p := range pairs {
+			out[i] = attr{p[0], map[string]string{"stringValue": p[1]}}
+		}
+		return out
+	}
+	type status struct {
+		Code    int    `json:"code"`
+		Message string `json:"message,omitempty"`
+	}
+	type otlpSpan struct {
+		TraceID      string `json:"traceId"`
+		SpanID       string `json:"spanId"`
+		ParentSpanID string `json:"parentSpanId,omitempty"`
+		Name         string `json:"name"`
+		Kind         int    `json:"kind"`
+		Start        string `json:"startTimeUnixNano"`
+		End          string `json:"endTimeUnixNano"`
+		Attributes   []attr `json:"attributes,omitempty"`
+		Status       status `json:"status"`
+	}
+
+	out := make([]otlpSpan, len(spans))
+	for i, s := range spans {
+		st := status{Code: otlpStatusOK}
+		if s.err != nil {
+			st = status{Code: otlpStatusError, Message: firstLine(s.err.Error())}
+		}
+		out[i] = otlpSpan{TraceID: traceID, SpanID: s.id, ParentSpanID: s.parent, Name: s.name, Kind: s.kind,
+			Start: strconv.FormatInt(s.start.UnixNano(), 10), End: strconv.FormatInt(s.end.UnixNano(), 10),
+			Attributes: attrsOf(s.attrs), Status: st}
+	}
+
+	service := os.Getenv("OTEL_SERVICE_NAME")
+	if service == "" {
+		service = "devflow"
+	}
+	resource := [][2]string{{"service.name", service}}
+	if wd, err := os.Getwd(); err == nil {
+		resource = append(resource, [2]string{"devflow.project", filepath.Base(wd)})
+	}
+	body, err := json.Marshal(map[string]any{"resourceSpans": []any{map[string]any{
+		"resource": map[string]any{"attributes": attrsOf(resource)},
+		"scopeSpans": []any{map[string]any{
+			"scope": map[string]string{"name": "github.com/tinywasm/devflow"},
+			"spans": out,
+		}},
+	}}})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range tracingHeaders() {
+		req.Header.Set(k, v)
+	}
+	resp, err := (&http.Client{Timeout: 5 * time.Second}).Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("%s: %s", endpoint, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package devflow
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTracing(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	var bodies []map[string]any
+	var headers []http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if r.URL.Path != "/v1/traces" || json.NewDecoder(r.Body).Decode(&body) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		bodies, headers = append(bodies, body), append(headers, r.Header)
+	}))
+	defer srv.Close()
+
+	if TracingEndpoint() != "" {
+		t.Fatal("tracing should be off without an endpoint")
+	}
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", srv.URL+"/")
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "x-api-key=se%20cret")
+	t.Setenv("TRACEPARENT", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	if got := TracingEndpoint(); got != srv.URL+"/v1/traces" {
+		t.Errorf("TracingEndpoint() = %q", got)
+	}
+
+	var childEnv string
+	removeEnv := UseMiddleware(func(next CommandRunner) CommandRunner {
+		return func(cmd *Command) (string, error) {
+			out, err := next(cmd)
+			childEnv = strings.Join(cmd.Env, " ")
+			return out, err
+		}
+	})
+	err := func() (err error) {
+		defer traceSpan("gopush")(&err)
+		tracePhase("verify")
+		RunCommand("echo", "ok")
+		tracePhase("release")
+		func() (err error) {
+			defer traceSpan("push")(&err)
+			_, err = RunCommandInDir(t.TempDir(), "sh", "-c", "echo rejected; exit 1")
+			return err
+		}()
+		return errors.New("push workflow failed")
+	}()
+	removeEnv()
+	if err == nil || len(bodies) != 1 {
+		t.Fatalf("expected one export, got %d", len(bodies))
+	}
+	if headers[0].Get("X-Api-Key") != "se cret" {
+		t.Errorf("expected the OTLP header, got %v", headers[0])
+	}
+
+	rs := bodies[0]["resourceSpans"].([]any)[0].(map[string]any)
+	spans := rs["scopeSpans"].([]any)[0].(map[string]any)["spans"].([]any)
+	byName := map[string]map[string]any{}
+	for _, s := range spans {
+		m := s.(map[string]any)
+		if m["traceId"] != "0af7651916cd43dd8448eb211c80319c" {
+			t.Errorf("span %v should join the TRACEPARENT trace", m["name"])
+		}
+		byName[m["name"].(string)] = m
+	}
+	if len(spans) != 6 {
+		t.Fatalf("expected 6 spans, got %d: %v", len(spans), byName)
+	}
+	parent := func(child, want string) {
+		t.Helper()
+		if byName[child]["parentSpanId"] != byName[want]["spanId"] {
+			t.Errorf("%s should be a child of %s", child, want)
+		}
+	}
+	if byName["gopush"]["parentSpanId"] != "b7ad6b7169203331" {
+		t.Error("the workflow should be a child of the TRACEPARENT span")
+	}
+	parent("verify", "gopush")
+	parent("echo ok", "verify")
+	parent("release", "gopush")
+	parent("push", "release")
+	parent("sh", "push")
+	status := func(name string) map[string]any { return byName[name]["status"].(map[string]any) }
+	if status("sh")["message"] != "rejected" || status("push")["code"] != float64(otlpStatusError) || status("echo ok")["code"] != float64(otlpStatusOK) {
+		t.Errorf("unexpected statuses: sh %v, push %v, echo %v", status("sh"), status("push"), status("echo ok"))
+	}
+	if !strings.Contains(childEnv, "TRACEPARENT=00-0af7651916cd43dd8448eb211c80319c-"+byName["sh"]["spanId"].(string)+"-01") {
+		t.Errorf("the command should get its span in TRACEPARENT, got %q", childEnv)
+	}
+
+	// Commands outside a traced workflow are not recorded
+	RunCommand("echo", "untraced")
+	t.Setenv("OTEL_SDK_DISABLED", "true")
+	func() {
+		defer traceSpan("test")(nil)
+	}()
+	if len(bodies) != 1 {
+		t.Errorf("expected no more exports, got %d", len(bodies))
+	}
+}
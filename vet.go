@@ -0,0 +1,103 @@
+package devflow
+
+import "strings"
+
+// defaultVetIgnore is used when vet.ignore is not set. WASM bindings
+// commonly convert uintptr to unsafe.Pointer on purpose.
+var defaultVetIgnore = []string{"possible misuse of unsafe.Pointer"}
+
+// VetSettings controls how gotest runs go vet. Settings come from the vet.*
+// keys of the project config:
+//
+//	vet:
+//	  enable: [printf, shadow]           # run only these analyzers
+//	  disable: [unsafeptr, composites]   # analyzers passed as -name=false
+//	  ignore:                            # findings whose message contains one of these
+//	    - possible misuse of unsafe.Pointer
+//	  analyzers:                         # extra go/analysis analyzers, built into a -vettool
+//	    - golang.org/x/tools/go/analysis/passes/shadow
+//	    - example.com/lint/nilerr.Analyzer@v1.2.0
+type VetSettings struct {
+	Enable    []string
+	Disable   []string
+	Ignore    []string
+	Analyzers []string
+	Tool      string // -vettool binary, set by Prepare when Analyzers is not empty
+}
+
+// LoadVetSettings reads the vet settings from c. Without vet.ignore the
+// unsafe.Pointer warning is suppressed as before; set "ignore: []" to
+// report it.
+func LoadVetSettings(c *Config) VetSettings {
+	if c == nil {
+		c = NewConfig()
+	}
+	s := VetSettings{
+		Enable:    c.List("vet.enable"),
+		Disable:   c.List("vet.disable"),
+		Ignore:    c.List("vet.ignore"),
+		Analyzers: c.List("vet.analyzers"),
+	}
+	if !c.Has("vet.ignore") {
+		s.Ignore = defaultVetIgnore
+	}
+	return s
+}
+
+// Prepare builds the -vettool for the custom analyzers, if any
+func (s *VetSettings) Prepare() error {
+	if len(s.Analyzers) == 0 || s.Tool != "" {
+		return nil
+	}
+	tool, err := BuildVetTool(s.Analyzers)
+	if err != nil {
+		return err
+	}
+	s.Tool = tool
+	return nil
+}
+
+// Args returns the go vet arguments for pkgs, adding extra flags (e.g. -json)
+// before the analyzer flags. go vet runs only the analyzers set to true when
+// any is, which is how vet.enable selects them.
+func (s VetSettings) Args(extra []string, pkgs ...string) []string {
+	args := append([]string{"vet"}, extra...)
+	if s.Tool != "" {
+		args = append(args, "-vettool="+s.Tool)
+	}
+	for _, name := range s.Enable {
+		args = append(args, "-"+strings.TrimPrefix(name, "-"))
+	}
+	for _, name := range s.Disable {
+		args = append(args, "-"+strings.TrimPrefix(name, "-")+"=false")
+	}
+	if len(pkgs) == 0 {
+		pkgs = []string{"./..."}
+	}
+	return append(args, pkgs...)
+}
+
+// Ignored reports whether a vet message matches the ignore list
+func (s VetSettings) Ignored(msg string) bool {
+	for _, pattern := range s.Ignore {
+		if pattern != "" && strings.Contains(msg, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// IssueLines returns the go vet output lines that report issues not
+// suppressed by the ignore list
+func (s VetSettings) IssueLines(vetOutput string) []string {
+	var lines []string
+	for _, line := range strings.Split(vetOutput, "\n") {
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") { // Ignore comments/empty
+			continue
+		}
+		if !s.Ignored(line) {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,36 @@
+package devflow
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLoadVetSettings(t *testing.T) {
+	s := LoadVetSettings(nil)
+	if !s.Ignored("./a.go:3:2: possible misuse of unsafe.Pointer") {
+		t.Error("unsafe.Pointer warning should be ignored by default")
+	}
+	if got := s.Args(nil); !reflect.DeepEqual(got, []string{"vet", "./..."}) {
+		t.Errorf("default Args = %v", got)
+	}
+
+	c, _ := ParseConfig("vet:\n  disable: [unsafeptr, -composites]\n  ignore: []\n")
+	s = LoadVetSettings(c)
+	if s.Ignored("possible misuse of unsafe.Pointer") {
+		t.Error("empty vet.ignore should report every finding")
+	}
+	want := []string{"vet", "-json", "-unsafeptr=false", "-composites=false", "./..."}
+	if got := s.Args([]string{"-json"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Args = %v, want %v", got, want)
+	}
+}
+
+func TestVetIssueLines(t *testing.T) {
+	c, _ := ParseConfig("vet:\n  ignore:\n    - generated stub\n")
+	output := "# example.com/app\n./a.go:3:2: unreachable code\n\n./gen.go:1:1: generated stub has no body\n./b.go:4:2: possible misuse of unsafe.Pointer\n"
+	got := LoadVetSettings(c).IssueLines(output)
+	want := []string{"./a.go:3:2: unreachable code", "./b.go:4:2: possible misuse of unsafe.Pointer"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("IssueLines = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,138 @@
+package devflow
+
+import (
+	"crypto/md5"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// xToolsModule provides unitchecker and the standard vet passes
+const xToolsModule = "golang.org/x/tools"
+
+// standardVetPasses are the analyzers go vet runs by default. A -vettool
+// replaces them, so the generated tool includes them next to the custom ones.
+var standardVetPasses = []string{
+	"appends", "asmdecl", "assign", "atomic", "bools", "buildtag", "cgocall",
+	"composite", "copylock", "defers", "directive", "errorsas", "framepointer",
+	"httpresponse", "ifaceassert", "loopclosure", "lostcancel", "nilfunc",
+	"printf", "shift", "sigchanyzer", "slog", "stdmethods", "stringintconv",
+	"structtag", "testinggoroutine", "tests", "timeformat", "unmarshal",
+	"unreachable", "unsafeptr", "unusedresult",
+}
+
+// VetAnalyzer is a go/analysis analyzer listed in vet.analyzers as
+// "import/path[.Var][@version]". Var defaults to Analyzer, version to latest.
+type VetAnalyzer struct {
+	Package string
+	Var     string
+	Version string
+}
+
+// ParseVetAnalyzer parses a vet.analyzers entry
+func ParseVetAnalyzer(entry string) (VetAnalyzer, error) {
+	a := VetAnalyzer{Var: "Analyzer", Version: "latest"}
+	entry = strings.TrimSpace(entry)
+	if path, version, ok := strings.Cut(entry, "@"); ok {
+		entry, a.Version = path, version
+	}
+	// A variable name follows the last path element: "example.com/lint/nilerr.Strict"
+	if i := strings.LastIndex(entry, "."); i > strings.LastIndex(entry, "/") && strings.Contains(entry, "/") {
+		entry, a.Var = entry[:i], entry[i+1:]
+	}
+	if entry == "" || a.Var == "" || a.Version == "" || !strings.Contains(entry, "/") {
+		return a, fmt.Errorf("vet.analyzers: invalid entry %q (want import/path[.Var][@version])", entry)
+	}
+	a.Package = entry
+	return a, nil
+}
+
+// vetToolSource generates the main.go of a unitchecker binary running the
+// standard passes plus the custom analyzers
+func vetToolSource(custom []VetAnalyzer) string {
+	var b strings.Builder
+	b.WriteString("// Code generated by devflow. DO NOT EDIT.\n\npackage main\n\nimport (\n")
+	for _, p := range standardVetPasses {
+		fmt.Fprintf(&b, "\t%q\n", xToolsModule+"/go/analysis/passes/"+p)
+	}
+	for i, a := range custom {
+		fmt.Fprintf(&b, "\tcustom%d %q\n", i, a.Package)
+	}
+	b.WriteString("\n\t\"golang.org/x/tools/go/analysis/unitchecker\"\n)\n\nfunc main() {\n\tunitchecker.Main(\n")
+	for _, p := range standardVetPasses {
+		fmt.Fprintf(&b, "\t\t%s.Analyzer,\n", p)
+	}
+	for i, a := range custom {
+		fmt.Fprintf(&b, "\t\tcustom%d.%s,\n", i, a.Var)
+	}
+	b.WriteString("\t)\n}\n")
+	return b.String()
+}
+
+// BuildVetTool builds a -vettool binary with the standard vet passes and
+// the given custom analyzers. Binaries are cached in the temp dir by
+// analyzer list, so later runs reuse them.
+func BuildVetTool(entries []string) (string, error) {
+	var custom []VetAnalyzer
+	for _, e := range entries {
+		a, err := ParseVetAnalyzer(e)
+		if err != nil {
+			return "", err
+		}
+		custom = append(custom, a)
+	}
+
+	key := append([]string{runtime.Version()}, entries...)
+	sort.Strings(key[1:])
+	hash := fmt.Sprintf("%x", md5.Sum([]byte(strings.Join(key, "\n"))))[:16]
+	dir := filepath.Join(os.TempDir(), "devflow-vettool", hash)
+	bin := filepath.Join(dir, "vettool")
+	if runtime.GOOS == "windows" {
+		bin += ".exe"
+	}
+	if _, err := os.Stat(bin); err == nil {
+		return bin, nil
+	}
+
+	if err := AirGapCheck(NetToolInstall); err != nil {
+		return "", fmt.Errorf("vet.analyzers: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	// Build in a directory of this process and rename the binary into place,
+	// so concurrent runs never see (or write) a half-built tool
+	build, err := os.MkdirTemp(dir, "build-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(build)
+	if err := os.WriteFile(filepath.Join(build, "go.mod"), []byte("module devflow-vettool\n"), 0644); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(build, "main.go"), []byte(vetToolSource(custom)), 0644); err != nil {
+		return "", err
+	}
+
+	gets := []string{"get", xToolsModule + "@latest"}
+	for _, a := range custom {
+		gets = append(gets, a.Package+"@"+a.Version)
+	}
+	if _, err := RunCommandInDir(build, "go", gets...); err != nil {
+		return "", fmt.Errorf("vet.analyzers: %w", err)
+	}
+	if _, err := RunCommandInDir(build, "go", "mod", "tidy"); err != nil {
+		return "", fmt.Errorf("vet.analyzers: %w", err)
+	}
+	tmp := filepath.Join(build, filepath.Base(bin))
+	if _, err := RunCommandInDir(build, "go", "build", "-o", tmp, "."); err != nil {
+		return "", fmt.Errorf("vet.analyzers: %w", err)
+	}
+	if err := os.Rename(tmp, bin); err != nil {
+		return "", err
+	}
+	return bin, nil
+}
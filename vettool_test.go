@@ -0,0 +1,56 @@
+package devflow
+
+import (
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseVetAnalyzer(t *testing.T) {
+	tests := []struct {
+		entry string
+		want  VetAnalyzer
+	}{
+		{"golang.org/x/tools/go/analysis/passes/shadow", VetAnalyzer{"golang.org/x/tools/go/analysis/passes/shadow", "Analyzer", "latest"}},
+		{"example.com/lint/nilerr.Strict@v1.2.0", VetAnalyzer{"example.com/lint/nilerr", "Strict", "v1.2.0"}},
+		{"example.com/lint.v2/check", VetAnalyzer{"example.com/lint.v2/check", "Analyzer", "latest"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseVetAnalyzer(tt.entry)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseVetAnalyzer(%q) = %+v, %v; want %+v", tt.entry, got, err, tt.want)
+		}
+	}
+	for _, bad := range []string{"", "shadow", "example.com/x@", "example.com/x."} {
+		if _, err := ParseVetAnalyzer(bad); err == nil {
+			t.Errorf("ParseVetAnalyzer(%q) should fail", bad)
+		}
+	}
+}
+
+func TestVetToolSource(t *testing.T) {
+	src := vetToolSource([]VetAnalyzer{{Package: "example.com/lint/nilerr", Var: "Strict"}})
+	if _, err := parser.ParseFile(token.NewFileSet(), "main.go", src, 0); err != nil {
+		t.Fatalf("generated source does not parse: %v\n%s", err, src)
+	}
+	for _, want := range []string{`custom0 "example.com/lint/nilerr"`, "custom0.Strict,", "printf.Analyzer,", "unitchecker.Main("} {
+		if !strings.Contains(src, want) {
+			t.Errorf("generated source missing %q", want)
+		}
+	}
+}
+
+func TestVetSettingsEnable(t *testing.T) {
+	c, _ := ParseConfig("vet:\n  enable: [printf, shadow]\n")
+	s := LoadVetSettings(c)
+	s.Tool = "/tmp/vettool"
+	want := []string{"vet", "-vettool=/tmp/vettool", "-printf", "-shadow", "./..."}
+	if got := s.Args(nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("Args = %v, want %v", got, want)
+	}
+	if err := s.Prepare(); err != nil {
+		t.Errorf("Prepare without analyzers: %v", err)
+	}
+}
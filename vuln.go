@@ -0,0 +1,286 @@
+package devflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Policies of vuln.block: which findings stop gopush
+const (
+	VulnBlockOff    = "off"    // Never block (default)
+	VulnBlockCalled = "called" // Block when vulnerable code is called
+	VulnBlockAny    = "any"    // Block on any vulnerable dependency
+)
+
+// VulnSettings are the vuln.* settings of .devflow.yaml
+type VulnSettings struct {
+	Enabled bool   // Run govulncheck as a gotest phase
+	Block   string // VulnBlockOff, VulnBlockCalled or VulnBlockAny
+	DB      string // Vulnerability database URL (govulncheck -db), e.g. an internal mirror
+}
+
+// LoadVulnSettings reads vuln.enabled, vuln.block and vuln.db
+func LoadVulnSettings(c *Config) (VulnSettings, error) {
+	s := VulnSettings{Block: VulnBlockOff}
+	if c == nil {
+		return s, nil
+	}
+	s.Enabled = c.Bool("vuln.enabled", false)
+	s.DB = c.String("vuln.db", "")
+	switch block := c.String("vuln.block", VulnBlockOff); block {
+	case VulnBlockOff, VulnBlockCalled, VulnBlockAny:
+		s.Block = block
+	default:
+		return s, fmt.Errorf("vuln.block: invalid value %q (want off, called or any)", block)
+	}
+	return s, nil
+}
+
+// VulnFinding is a known vulnerability affecting the module
+type VulnFinding struct {
+	ID      string   // Go vulnerability ID, e.g. GO-2024-2687
+	Aliases []string // CVE and GHSA IDs
+	Summary string
+	Module  string
+	Version string // Version in use
+	Fixed   string // First fixed version, empty when there is none
+	Package string // Affected package, empty when only the module is required
+	Symbol  string // Vulnerable function called by the module, empty when not called
+}
+
+// Called reports whether the module calls the vulnerable code
+func (f VulnFinding) Called() bool {
+	return f.Symbol != ""
+}
+
+// String returns e.g. "GO-2024-2687 golang.org/x/net@v0.17.0 (fixed in v0.23.0)"
+func (f VulnFinding) String() string {
+	s := fmt.Sprintf("%s %s@%s", f.ID, f.Module, f.Version)
+	if f.Symbol != "" {
+		s += " called via " + f.Symbol
+	}
+	if f.Fixed != "" {
+		s += " (fixed in " + f.Fixed + ")"
+	} else {
+		s += " (no fix)"
+	}
+	return s
+}
+
+// VulnReport is the result of a govulncheck run
+type VulnReport struct {
+	Findings []VulnFinding // One per vulnerability, called ones first
+}
+
+// Called returns the findings whose vulnerable code is called
+func (r VulnReport) Called() []VulnFinding {
+	var called []VulnFinding
+	for _, f := range r.Findings {
+		if f.Called() {
+			called = append(called, f)
+		}
+	}
+	return called
+}
+
+// Status returns the badge value: "none", "N called" or "N imported"
+func (r VulnReport) Status() string {
+	if n := len(r.Called()); n > 0 {
+		return fmt.Sprintf("%d called", n)
+	}
+	if len(r.Findings) > 0 {
+		return fmt.Sprintf("%d imported", len(r.Findings))
+	}
+	return "none"
+}
+
+// Summary returns the gotest message for the report
+func (r VulnReport) Summary() string {
+	called := r.Called()
+	switch {
+	case len(called) > 0:
+		ids := make([]string, len(called))
+		for i, f := range called {
+			ids[i] = f.ID
+		}
+		s := fmt.Sprintf("⚠️ vulns: %d called (%s)", len(called), strings.Join(ids, ", "))
+		if other := len(r.Findings) - len(called); other > 0 {
+			s += fmt.Sprintf(", %d imported", other)
+		}
+		return s
+	case len(r.Findings) > 0:
+		return fmt.Sprintf("✅ vulns: none called (%d in dependencies)", len(r.Findings))
+	}
+	return "✅ vulns: none"
+}
+
+// Blocking returns the findings that stop a push under policy
+func (r VulnReport) Blocking(policy string) []VulnFinding {
+	switch policy {
+	case VulnBlockCalled:
+		return r.Called()
+	case VulnBlockAny:
+		return r.Findings
+	}
+	return nil
+}
+
+// ParseGovulncheckJSON reads the message stream of govulncheck -format json.
+// A vulnerability reported at several levels (module, package, symbol)
+// is kept once, at the deepest one.
+func ParseGovulncheckJSON(r io.Reader) (VulnReport, error) {
+	type frame struct {
+		Module   string `json:"module"`
+		Version  string `json:"version"`
+		Package  string `json:"package"`
+		Function string `json:"function"`
+		Receiver string `json:"receiver"`
+	}
+	type message struct {
+		OSV *struct {
+			ID      string   `json:"id"`
+			Aliases []string `json:"aliases"`
+			Summary string   `json:"summary"`
+		} `json:"osv"`
+		Finding *struct {
+			OSV          string  `json:"osv"`
+			FixedVersion string  `json:"fixed_version"`
+			Trace        []frame `json:"trace"`
+		} `json:"finding"`
+	}
+
+	entries := make(map[string]VulnFinding)
+	osvs := make(map[string]VulnFinding)
+	dec := json.NewDecoder(r)
+	for {
+		var m message
+		if err := dec.Decode(&m); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return VulnReport{}, fmt.Errorf("govulncheck output: %w", err)
+		}
+		if m.OSV != nil {
+			osvs[m.OSV.ID] = VulnFinding{ID: m.OSV.ID, Aliases: m.OSV.Aliases, Summary: m.OSV.Summary}
+		}
+		if m.Finding == nil || len(m.Finding.Trace) == 0 {
+			continue
+		}
+		top := m.Finding.Trace[0]
+		f := VulnFinding{ID: m.Finding.OSV, Module: top.Module, Version: top.Version, Fixed: m.Finding.FixedVersion, Package: top.Package}
+		if top.Function != "" {
+			f.Symbol = top.Package + "." + top.Function
+			if top.Receiver != "" {
+				f.Symbol = top.Package + "." + strings.TrimPrefix(top.Receiver, "*") + "." + top.Function
+			}
+		}
+		if prev, ok := entries[f.ID]; !ok || vulnLevel(f) > vulnLevel(prev) {
+			entries[f.ID] = f
+		}
+	}
+
+	var report VulnReport
+	for id, f := range entries {
+		f.Aliases, f.Summary = osvs[id].Aliases, osvs[id].Summary
+		report.Findings = append(report.Findings, f)
+	}
+	sort.Slice(report.Findings, func(i, j int) bool {
+		a, b := report.Findings[i], report.Findings[j]
+		if a.Called() != b.Called() {
+			return a.Called()
+		}
+		return a.ID < b.ID
+	})
+	return report, nil
+}
+
+// vulnLevel ranks how precisely a finding was located
+func vulnLevel(f VulnFinding) int {
+	switch {
+	case f.Symbol != "":
+		return 2
+	case f.Package != "":
+		return 1
+	}
+	return 0
+}
+
+// installGovulncheck installs govulncheck when it is not on PATH
+func (g *Go) installGovulncheck() error {
+	if _, err := RunCommandSilent("which", "govulncheck"); err == nil {
+		return nil
+	}
+	if err := AirGapCheck(NetToolInstall); err != nil {
+		return fmt.Errorf("govulncheck not installed: %w", err)
+	}
+	if _, err := RunCommand("go", "install", "golang.org/x/vuln/cmd/govulncheck@latest"); err != nil {
+		return fmt.Errorf("go install failed: %w", err)
+	}
+	return nil
+}
+
+// VulnCheck runs govulncheck on the module in the current directory,
+// installing it first if needed. Without vuln.db the public database is
+// queried, which air-gapped mode disables.
+func (g *Go) VulnCheck() (VulnReport, error) {
+	settings, err := LoadVulnSettings(g.Config())
+	if err != nil {
+		return VulnReport{}, err
+	}
+	if settings.DB == "" {
+		if err := AirGapCheck(NetVulnDB); err != nil {
+			return VulnReport{}, err
+		}
+	}
+	if err := g.installGovulncheck(); err != nil {
+		return VulnReport{}, err
+	}
+
+	args := []string{"-format", "json"}
+	if settings.DB != "" {
+		args = append(args, "-db", settings.DB)
+	}
+	args = append(args, "./...")
+	var stdout, stderr bytes.Buffer
+	_, runErr := runCommand(&Command{Name: "govulncheck", Args: args, Stdout: &stdout, Stderr: &stderr})
+
+	report, err := ParseGovulncheckJSON(&stdout)
+	if runErr != nil && (err != nil || stdout.Len() == 0) {
+		return VulnReport{}, fmt.Errorf("govulncheck failed: %w: %s", runErr, strings.TrimSpace(stderr.String()))
+	}
+	if err != nil {
+		return VulnReport{}, err
+	}
+	g.vulns = &report
+	return report, nil
+}
+
+// checkPushVulns returns an error when vuln.block is set and the last
+// check (from the test run, else a new one) has blocking findings
+func (g *Go) checkPushVulns() error {
+	settings, err := LoadVulnSettings(g.Config())
+	if err != nil || settings.Block == VulnBlockOff {
+		return err
+	}
+	report := g.vulns
+	if report == nil {
+		r, err := g.VulnCheck()
+		if err != nil {
+			return fmt.Errorf("vulnerability check failed (vuln.block: %s): %w", settings.Block, err)
+		}
+		report = &r
+	}
+	blocking := report.Blocking(settings.Block)
+	if len(blocking) == 0 {
+		return nil
+	}
+	lines := make([]string, len(blocking))
+	for i, f := range blocking {
+		lines[i] = f.String()
+	}
+	return fmt.Errorf("%d vulnerabilities block the push (vuln.block: %s): %s", len(blocking), settings.Block, strings.Join(lines, "; "))
+}
@@ -0,0 +1,74 @@
+package devflow
+
+import (
+	"strings"
+	"testing"
+)
+
+const testGovulncheckJSON = `{"config":{"scanner_name":"govulncheck","scan_level":"symbol"}}
+{"progress":{"message":"Scanning your code and 12 packages across 3 dependent modules for known vulnerabilities..."}}
+{"osv":{"id":"GO-2024-2687","aliases":["CVE-2023-45288","GHSA-4v7x-pqxf-cx7m"],"summary":"HTTP/2 CONTINUATION flood in net/http"}}
+{"osv":{"id":"GO-2023-1840","aliases":["CVE-2023-29403"],"summary":"Unsafe behavior in setuid/setgid binaries in runtime"}}
+{"finding":{"osv":"GO-2024-2687","fixed_version":"v0.23.0","trace":[{"module":"golang.org/x/net","version":"v0.17.0"}]}}
+{"finding":{"osv":"GO-2024-2687","fixed_version":"v0.23.0","trace":[{"module":"golang.org/x/net","version":"v0.17.0","package":"golang.org/x/net/http2"}]}}
+{"finding":{"osv":"GO-2024-2687","fixed_version":"v0.23.0","trace":[{"module":"golang.org/x/net","version":"v0.17.0","package":"golang.org/x/net/http2","function":"ReadFrame","receiver":"*Framer"},{"module":"example.com/app","package":"example.com/app","function":"serve"}]}}
+{"finding":{"osv":"GO-2023-1840","trace":[{"module":"stdlib","version":"v1.20.1"}]}}
+`
+
+func TestParseGovulncheckJSON(t *testing.T) {
+	report, err := ParseGovulncheckJSON(strings.NewReader(testGovulncheckJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Findings) != 2 {
+		t.Fatalf("expected 2 findings, got %+v", report.Findings)
+	}
+	called := report.Findings[0]
+	if !called.Called() || called.Symbol != "golang.org/x/net/http2.Framer.ReadFrame" || called.Aliases[0] != "CVE-2023-45288" {
+		t.Errorf("unexpected called finding: %+v", called)
+	}
+	if got := called.String(); got != "GO-2024-2687 golang.org/x/net@v0.17.0 called via golang.org/x/net/http2.Framer.ReadFrame (fixed in v0.23.0)" {
+		t.Errorf("String() = %q", got)
+	}
+	if report.Findings[1].Called() || !strings.HasSuffix(report.Findings[1].String(), "(no fix)") {
+		t.Errorf("unexpected imported finding: %+v", report.Findings[1])
+	}
+
+	if report.Status() != "1 called" || report.Summary() != "⚠️ vulns: 1 called (GO-2024-2687), 1 imported" {
+		t.Errorf("Status() = %q, Summary() = %q", report.Status(), report.Summary())
+	}
+	if len(report.Blocking(VulnBlockOff)) != 0 || len(report.Blocking(VulnBlockCalled)) != 1 || len(report.Blocking(VulnBlockAny)) != 2 {
+		t.Error("unexpected blocking findings")
+	}
+
+	empty, err := ParseGovulncheckJSON(strings.NewReader(`{"config":{}}`))
+	if err != nil || empty.Status() != "none" || empty.Summary() != "✅ vulns: none" {
+		t.Errorf("empty report: %+v, %v", empty, err)
+	}
+	if _, err := ParseGovulncheckJSON(strings.NewReader("govulncheck: no go.mod")); err == nil {
+		t.Error("expected error for text output")
+	}
+}
+
+func TestCheckPushVulns(t *testing.T) {
+	report, _ := ParseGovulncheckJSON(strings.NewReader(testGovulncheckJSON))
+	g, _ := NewGo(nil)
+	g.vulns = &report
+
+	for block, blocked := range map[string]bool{"off": false, "called": true, "any": true} {
+		cfg, _ := ParseConfig("vuln:\n  block: " + block + "\n")
+		g.SetConfig(cfg)
+		err := g.checkPushVulns()
+		if (err != nil) != blocked {
+			t.Errorf("vuln.block %s: err = %v", block, err)
+		}
+		if blocked && !strings.Contains(err.Error(), "GO-2024-2687") {
+			t.Errorf("error should name the vulnerability: %v", err)
+		}
+	}
+
+	cfg, _ := ParseConfig("vuln:\n  block: critical\n")
+	if _, err := LoadVulnSettings(cfg); err == nil {
+		t.Error("expected error for invalid vuln.block")
+	}
+}
@@ -0,0 +1,78 @@
+package devflow
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Warning is a non-fatal problem of a run: the step it names was skipped
+// or left incomplete and the run went on without it
+type Warning struct {
+	Step    string `json:"step"`
+	Message string `json:"message"`
+}
+
+// String returns "step: message"
+func (w Warning) String() string {
+	return w.Step + ": " + w.Message
+}
+
+// Warnings collects the warnings of a run. It is safe for concurrent use,
+// as dependents are updated in parallel.
+type Warnings struct {
+	mu    sync.Mutex
+	depth int
+	list  []Warning
+}
+
+// Add records err as a warning of step; a nil err is ignored
+func (w *Warnings) Add(step string, err error) {
+	if err == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.list = append(w.list, Warning{Step: step, Message: firstLine(err.Error())})
+}
+
+// List returns the warnings in the order they were added
+func (w *Warnings) List() []Warning {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]Warning(nil), w.list...)
+}
+
+// begin starts a run; the outermost one (gopush, not the tests inside it)
+// clears the warnings of the previous run. Call the returned func when the
+// run ends: defer g.warnings.begin()()
+func (w *Warnings) begin() func() {
+	w.mu.Lock()
+	if w.depth == 0 {
+		w.list = nil
+	}
+	w.depth++
+	w.mu.Unlock()
+	return func() {
+		w.mu.Lock()
+		w.depth--
+		w.mu.Unlock()
+	}
+}
+
+// WriteWarnings writes the "Warnings:" section of the commands, one
+// "⚠️ step: message" line per warning; nothing when there are none
+func WriteWarnings(w io.Writer, warnings []Warning) error {
+	if len(warnings) == 0 {
+		return nil
+	}
+	if _, err := fmt.Fprintf(w, "Warnings (%d):\n", len(warnings)); err != nil {
+		return err
+	}
+	for _, warning := range warnings {
+		if _, err := fmt.Fprintf(w, "  ⚠️ %s\n", warning); err != nil {
+			return err
+		}
+	}
+	return nil
+}
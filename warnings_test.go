@@ -0,0 +1,46 @@
+package devflow
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWarnings(t *testing.T) {
+	var w Warnings
+	end := w.begin()
+	w.Add("badges not updated", errors.New("README.md: permission denied\nmore detail"))
+	w.Add("ignored", nil)
+
+	// A nested run (the tests inside gopush) keeps the warnings of the outer one
+	endTests := w.begin()
+	w.Add("test cache not saved", errors.New("disk full"))
+	endTests()
+	end()
+	if got := w.List(); len(got) != 2 || got[0].String() != "badges not updated: README.md: permission denied" || got[1].Step != "test cache not saved" {
+		t.Fatalf("unexpected warnings %v", got)
+	}
+
+	var b strings.Builder
+	WriteWarnings(&b, w.List())
+	want := "Warnings (2):\n  ⚠️ badges not updated: README.md: permission denied\n  ⚠️ test cache not saved: disk full\n"
+	if b.String() != want {
+		t.Errorf("WriteWarnings() = %q, want %q", b.String(), want)
+	}
+
+	// The next run starts empty
+	w.begin()()
+	if len(w.List()) != 0 {
+		t.Errorf("expected the previous warnings cleared, got %v", w.List())
+	}
+	b.Reset()
+	WriteWarnings(&b, nil)
+	if b.Len() != 0 {
+		t.Errorf("no warnings should print nothing, got %q", b.String())
+	}
+
+	r := &TestReport{Module: "example.com/app", Passed: true, Warnings: []Warning{{Step: "badges not updated", Message: "read-only"}}}
+	if md := r.Markdown(); !strings.Contains(md, "### Warnings\n\n- ⚠️ badges not updated: read-only\n") {
+		t.Errorf("expected the warnings in the CI report:\n%s", md)
+	}
+}